@@ -6,7 +6,7 @@ document.body.addEventListener('htmx:configRequest', (evt) => {
     }
 });
 
-function checkinManager(initialData, sessionId, isStarted) {
+function checkinManager(initialData, sessionId, isStarted, waitlist) {
     return {
         attendance: initialData,
         original: JSON.parse(JSON.stringify(initialData)),
@@ -14,6 +14,111 @@ function checkinManager(initialData, sessionId, isStarted) {
         isStarted: isStarted,
         showAddModal: false,
         submitting: false,
+        waitlist: waitlist || [],
+        originalWaitlist: [...(waitlist || [])],
+        savingWaitlist: false,
+        leaveComments: {},
+        reviewed: {},
+        reviewing: false,
+
+        // 核准時釋出名額並通知家長，駁回時維持預約
+        async reviewLeave(id, approve) {
+            if (this.reviewing) return;
+            this.reviewing = true;
+            try {
+                const response = await fetch('/v2/admin/checkin/leave/' + (approve ? 'approve' : 'reject'), {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: JSON.stringify({
+                        bookingId: id,
+                        comment: (this.leaveComments[id] || '').trim()
+                    })
+                });
+                if (response.ok) {
+                    this.reviewed[id] = true;
+                    if (approve) {
+                        this.attendance[id] = 'Leave';
+                        this.original[id] = 'Leave';
+                    }
+                    showToast({
+                        title: approve ? "已核准請假" : "已駁回請假",
+                        description: "審核結果將通知家長",
+                        variant: "default"
+                    });
+                } else {
+                    const data = await response.json();
+                    showToast({
+                        title: "審核失敗",
+                        description: data.message || '請重新整理後再試',
+                        variant: "destructive"
+                    });
+                }
+            } catch (e) {
+                showToast({
+                    title: "系統錯誤",
+                    description: "審核過程發生問題",
+                    variant: "destructive"
+                });
+            } finally {
+                this.reviewing = false;
+            }
+        },
+
+        get waitlistChanged() {
+            return JSON.stringify(this.waitlist) !== JSON.stringify(this.originalWaitlist);
+        },
+
+        moveWaitlist(id, offset) {
+            const i = this.waitlist.indexOf(id), j = i + offset;
+            if (i < 0 || j < 0 || j >= this.waitlist.length) return;
+            const list = [...this.waitlist];
+            [list[i], list[j]] = [list[j], list[i]];
+            this.waitlist = list;
+        },
+
+        async submitWaitlistOrder() {
+            if (this.savingWaitlist) return;
+            this.savingWaitlist = true;
+            try {
+                const response = await fetch('/v2/admin/checkin/waitlist/reorder', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: JSON.stringify({
+                        sessionId: this.sessionId,
+                        entryIds: this.waitlist
+                    })
+                });
+                if (response.ok) {
+                    this.originalWaitlist = [...this.waitlist];
+                    showToast({
+                        title: "儲存成功",
+                        description: "候補順序已更新",
+                        variant: "default"
+                    });
+                } else {
+                    const data = await response.json();
+                    showToast({
+                        title: "儲存失敗",
+                        description: data.message || '候補名單已變動，請重新整理',
+                        variant: "destructive"
+                    });
+                }
+            } catch (e) {
+                showToast({
+                    title: "系統錯誤",
+                    description: "儲存過程發生問題",
+                    variant: "destructive"
+                });
+            } finally {
+                this.savingWaitlist = false;
+            }
+        },
         
         get hasChanges() {
             return JSON.stringify(this.attendance) !== JSON.stringify(this.original);
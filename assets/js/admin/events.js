@@ -0,0 +1,105 @@
+function eventAdmin() {
+    return {
+        loading: false,
+        submitting: false,
+        loaded: false,
+        events: [],
+        details: {},
+        targets: {},
+        query: {},
+        next: '',
+        truncated: false,
+
+        // 只列出程式仍在訂閱的訂閱者，已停用的無法重送
+        subscribersFor(topic) {
+            return Array.from(document.querySelectorAll('[data-subscriber][data-registered]'))
+                .filter(el => el.dataset.topic === topic)
+                .map(el => el.dataset.subscriber);
+        },
+
+        async search(form) {
+            const data = new FormData(form);
+            this.query = {};
+            for (const key of ['topic', 'user', 'booking', 'from', 'to']) {
+                const value = (data.get(key) || '').trim();
+                if (value) this.query[key] = value;
+            }
+            this.events = [];
+            this.details = {};
+            await this.fetchEvents('');
+        },
+
+        async more() {
+            await this.fetchEvents(this.next);
+        },
+
+        async fetchEvents(before) {
+            if (this.loading) return;
+            this.loading = true;
+            const params = new URLSearchParams(this.query);
+            if (before) params.set('before', before);
+            try {
+                const response = await fetch(`/v2/admin/events/logs?${params}`);
+                const data = await response.json();
+                if (!response.ok) {
+                    showToast({ title: "查詢失敗", description: data.message || '無法取得事件紀錄', variant: "destructive" });
+                    return;
+                }
+                this.events = this.events.concat(data.items);
+                this.next = data.next;
+                this.truncated = data.truncated;
+                this.loaded = true;
+            } catch (e) {
+                showToast({ title: "系統錯誤", description: "操作過程發生問題", variant: "destructive" });
+            } finally {
+                this.loading = false;
+            }
+        },
+
+        async toggle(id) {
+            if (this.details[id]) {
+                delete this.details[id];
+                return;
+            }
+            try {
+                const response = await fetch(`/v2/admin/events/logs/${encodeURIComponent(id)}`);
+                const data = await response.json();
+                if (!response.ok) {
+                    showToast({ title: "查詢失敗", description: data.message || '無法取得事件內容', variant: "destructive" });
+                    return;
+                }
+                this.targets[id] = this.subscribersFor(data.item.topic)[0] || '';
+                this.details[id] = data;
+            } catch (e) {
+                showToast({ title: "系統錯誤", description: "操作過程發生問題", variant: "destructive" });
+            }
+        },
+
+        async redispatch(id) {
+            const subscriberId = this.targets[id];
+            if (this.submitting || !subscriberId) return;
+            if (!confirm(`確定要將事件重新送給 ${subscriberId} 嗎？`)) return;
+            this.submitting = true;
+            try {
+                const response = await fetch(`/v2/admin/events/logs/${encodeURIComponent(id)}/redispatch`, {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: JSON.stringify({ subscriberId })
+                });
+                if (response.ok) {
+                    showToast({ title: "操作成功", description: "已重新送出", variant: "default" });
+                } else {
+                    const data = await response.json();
+                    showToast({ title: "操作失敗", description: data.message || '重新送出失敗', variant: "destructive" });
+                }
+            } catch (e) {
+                showToast({ title: "系統錯誤", description: "操作過程發生問題", variant: "destructive" });
+            } finally {
+                this.submitting = false;
+            }
+        }
+    };
+}
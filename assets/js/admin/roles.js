@@ -0,0 +1,52 @@
+function roleAdmin() {
+    return {
+        submitting: false,
+
+        async save(form) {
+            const data = new FormData(form);
+            const userId = (data.get('userId') || '').trim();
+            if (!userId) return;
+            const body = {
+                userName: (data.get('userName') || '').trim(),
+                roles: data.getAll('roles')
+            };
+            await this.send(`/v2/admin/roles/${encodeURIComponent(userId)}`, 'PUT', body, '角色已更新');
+        },
+
+        async remove(el) {
+            if (!confirm('移除後此使用者將依 LINE 管理員身分判斷權限，確定要移除嗎？')) return;
+            const userId = el.closest('form').querySelector('input[name="userId"]').value;
+            await this.send(`/v2/admin/roles/${encodeURIComponent(userId)}`, 'DELETE', null, '角色紀錄已移除');
+        },
+
+        async send(url, method, body, successMsg) {
+            if (this.submitting) return;
+            this.submitting = true;
+            try {
+                const response = await fetch(url, {
+                    method: method,
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: body ? JSON.stringify(body) : undefined
+                });
+                if (response.ok) {
+                    showToast({ title: "操作成功", description: successMsg, variant: "default" });
+                    setTimeout(() => window.location.reload(), 600);
+                } else {
+                    const data = await response.json();
+                    showToast({
+                        title: "操作失敗",
+                        description: data.message || '請確認輸入內容',
+                        variant: "destructive"
+                    });
+                }
+            } catch (e) {
+                showToast({ title: "系統錯誤", description: "操作過程發生問題", variant: "destructive" });
+            } finally {
+                this.submitting = false;
+            }
+        }
+    };
+}
@@ -0,0 +1,78 @@
+function teamAdmin() {
+    return {
+        submitting: false,
+
+        // 助理教練每行一位：LINE User ID:名稱
+        formBody(form) {
+            const data = new FormData(form);
+            const assistants = (data.get('assistants') || '')
+                .split('\n')
+                .map(line => line.trim())
+                .filter(line => line)
+                .map(line => {
+                    const [userId, ...rest] = line.split(':');
+                    return { userId: userId.trim(), userName: rest.join(':').trim() };
+                });
+            return {
+                name: (data.get('name') || '').trim(),
+                headCoachId: (data.get('headCoachId') || '').trim(),
+                headCoachName: (data.get('headCoachName') || '').trim(),
+                assistants: assistants,
+                leaveApproval: data.get('leaveApproval') === 'on'
+            };
+        },
+
+        teamId(el) {
+            return el.closest('[data-team-id]').dataset.teamId;
+        },
+
+        async create(form) {
+            await this.send('/v2/admin/teams', 'POST', this.formBody(form), '團隊已建立');
+        },
+
+        async update(form) {
+            await this.send(`/v2/admin/teams/${encodeURIComponent(this.teamId(form))}`, 'PUT', this.formBody(form), '團隊已更新');
+        },
+
+        async remove(el) {
+            if (!confirm('確定要刪除此團隊嗎？')) return;
+            await this.send(`/v2/admin/teams/${encodeURIComponent(this.teamId(el))}`, 'DELETE', null, '團隊已刪除');
+        },
+
+        async removeMember(el) {
+            const body = { removeStudentIds: [el.dataset.studentId] };
+            await this.send(`/v2/admin/teams/${encodeURIComponent(this.teamId(el))}/members`, 'POST', body, '成員已移除');
+        },
+
+        async send(url, method, body, successMsg) {
+            if (this.submitting) return;
+            this.submitting = true;
+            try {
+                const response = await fetch(url, {
+                    method: method,
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: body ? JSON.stringify(body) : undefined
+                });
+                if (response.ok) {
+                    showToast({ title: "操作成功", description: successMsg, variant: "default" });
+                    setTimeout(() => window.location.reload(), 600);
+                } else {
+                    const data = await response.json();
+                    showToast({
+                        title: "操作失敗",
+                        description: data.message || '請確認輸入內容',
+                        variant: "destructive"
+                    });
+                }
+            } catch (e) {
+                showToast({ title: "系統錯誤", description: "操作過程發生問題", variant: "destructive" });
+            } finally {
+                this.submitting = false;
+            }
+        }
+    };
+}
+
@@ -0,0 +1,131 @@
+function creditTopUp() {
+    return {
+        open: false,
+        submitting: false,
+        quantity: 10,
+        expiresAt: '',
+        childName: '',
+        note: '',
+
+        async submit() {
+            if (this.submitting) return;
+            this.submitting = true;
+            const { userId, userName } = this.$root.dataset;
+            try {
+                const response = await fetch(`/v2/admin/users/${encodeURIComponent(userId)}/credits`, {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: JSON.stringify({
+                        userName: userName,
+                        childName: this.childName.trim(),
+                        quantity: this.quantity,
+                        expiresAt: this.expiresAt,
+                        note: this.note.trim()
+                    })
+                });
+                if (response.ok) {
+                    showToast({
+                        title: "儲值成功",
+                        description: `已新增 ${this.quantity} 堂`,
+                        variant: "default"
+                    });
+                    setTimeout(() => window.location.reload(), 600);
+                } else {
+                    const data = await response.json();
+                    showToast({
+                        title: "儲值失敗",
+                        description: data.message || '請確認堂數與到期日',
+                        variant: "destructive"
+                    });
+                }
+            } catch (e) {
+                showToast({
+                    title: "系統錯誤",
+                    description: "儲值過程發生問題",
+                    variant: "destructive"
+                });
+            } finally {
+                this.submitting = false;
+            }
+        }
+    };
+}
+
+function studentMerge() {
+    return {
+        open: false,
+        submitting: false,
+        target: '',
+        sources: [],
+
+        async submit() {
+            if (this.submitting) return;
+            const sources = this.sources.filter(id => id !== this.target);
+            if (!this.target || sources.length === 0) return;
+            this.submitting = true;
+            const { userId } = this.$root.dataset;
+            try {
+                const response = await fetch(`/v2/admin/users/${encodeURIComponent(userId)}/students/merge`, {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: JSON.stringify({ targetId: this.target, sourceIds: sources })
+                });
+                if (response.ok) {
+                    showToast({
+                        title: "合併成功",
+                        description: `已合併 ${sources.length} 位學員`,
+                        variant: "default"
+                    });
+                    setTimeout(() => window.location.reload(), 600);
+                } else {
+                    const data = await response.json();
+                    showToast({
+                        title: "合併失敗",
+                        description: data.message || '請確認選擇的學員',
+                        variant: "destructive"
+                    });
+                }
+            } catch (e) {
+                showToast({
+                    title: "系統錯誤",
+                    description: "合併過程發生問題",
+                    variant: "destructive"
+                });
+            } finally {
+                this.submitting = false;
+            }
+        }
+    };
+}
+
+// addToTeam 學員明細頁將學員加入團隊
+async function addToTeam(select) {
+    const teamId = select.value;
+    if (!teamId) return;
+    try {
+        const response = await fetch(`/v2/admin/teams/${encodeURIComponent(teamId)}/members`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+                'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+            },
+            body: JSON.stringify({ addStudentIds: [select.dataset.studentId] })
+        });
+        if (response.ok) {
+            showToast({ title: "加入成功", description: select.options[select.selectedIndex].text, variant: "default" });
+        } else {
+            const data = await response.json();
+            showToast({ title: "加入失敗", description: data.message || '請稍後再試', variant: "destructive" });
+        }
+    } catch (e) {
+        showToast({ title: "系統錯誤", description: "加入團隊時發生問題", variant: "destructive" });
+    } finally {
+        select.value = '';
+    }
+}
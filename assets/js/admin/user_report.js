@@ -0,0 +1,66 @@
+function recordPayment() {
+    return {
+        open: false,
+        submitting: false,
+        method: 'CASH',
+        amount: 0,
+        reference: '',
+        paidAt: '',
+        note: '',
+
+        toggle() {
+            this.open = !this.open;
+            if (this.open && !this.amount) {
+                this.amount = parseInt(this.$root.dataset.outstanding, 10) || 0;
+            }
+        },
+
+        async submit() {
+            if (this.submitting) return;
+            this.submitting = true;
+            const { userId, userName, year, month } = this.$root.dataset;
+            try {
+                const response = await fetch(`/v2/admin/users/${encodeURIComponent(userId)}/payments`, {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: JSON.stringify({
+                        userName: userName,
+                        year: parseInt(year, 10),
+                        month: parseInt(month, 10),
+                        amount: this.amount,
+                        method: this.method,
+                        reference: this.reference.trim(),
+                        paidAt: this.paidAt,
+                        note: this.note.trim()
+                    })
+                });
+                if (response.ok) {
+                    showToast({
+                        title: "登記成功",
+                        description: `已登記收款 $${this.amount}`,
+                        variant: "default"
+                    });
+                    setTimeout(() => window.location.reload(), 600);
+                } else {
+                    const data = await response.json();
+                    showToast({
+                        title: "登記失敗",
+                        description: data.message || '請確認金額與付款資訊',
+                        variant: "destructive"
+                    });
+                }
+            } catch (e) {
+                showToast({
+                    title: "系統錯誤",
+                    description: "登記收款過程發生問題",
+                    variant: "destructive"
+                });
+            } finally {
+                this.submitting = false;
+            }
+        }
+    };
+}
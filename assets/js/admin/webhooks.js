@@ -0,0 +1,128 @@
+function webhookAdmin() {
+    return {
+        submitting: false,
+        deliveries: {},
+
+        fields(form) {
+            const data = new FormData(form);
+            return {
+                name: (data.get('name') || '').trim(),
+                url: (data.get('url') || '').trim(),
+                topics: data.getAll('topics'),
+                enabled: data.get('enabled') === 'on'
+            };
+        },
+
+        async create(form) {
+            const body = this.fields(form);
+            if (body.topics.length === 0) {
+                showToast({ title: "操作失敗", description: "請至少選擇一個主題", variant: "destructive" });
+                return;
+            }
+            await this.send('/v2/admin/webhooks', 'POST', body, '端點已建立');
+        },
+
+        async update(form) {
+            await this.send(`/v2/admin/webhooks/${form.dataset.id}`, 'PUT', this.fields(form), '端點已更新');
+        },
+
+        async rotate(el) {
+            if (!confirm('更換後舊金鑰立即失效，確定要更換嗎？')) return;
+            const form = el.closest('form');
+            const body = { ...this.fields(form), rotateSecret: true };
+            await this.send(`/v2/admin/webhooks/${form.dataset.id}`, 'PUT', body, '金鑰已更換');
+        },
+
+        async remove(el) {
+            if (!confirm('確定要刪除此端點嗎？')) return;
+            const id = el.closest('form').dataset.id;
+            await this.send(`/v2/admin/webhooks/${id}`, 'DELETE', null, '端點已刪除');
+        },
+
+        async ping(el) {
+            if (this.submitting) return;
+            this.submitting = true;
+            const id = el.closest('form').dataset.id;
+            try {
+                const response = await fetch(`/v2/admin/webhooks/${id}/ping`, {
+                    method: 'POST',
+                    headers: { 'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value }
+                });
+                const data = await response.json();
+                if (!response.ok) {
+                    showToast({ title: "操作失敗", description: data.message || '測試送出失敗', variant: "destructive" });
+                    return;
+                }
+                const d = data.delivery;
+                showToast({
+                    title: d.succeeded ? "送達成功" : "送達失敗",
+                    description: this.deliveryStatus(d),
+                    variant: d.succeeded ? "default" : "destructive"
+                });
+                if (this.deliveries[id]) await this.fetchDeliveries(id);
+            } catch (e) {
+                showToast({ title: "系統錯誤", description: "操作過程發生問題", variant: "destructive" });
+            } finally {
+                this.submitting = false;
+            }
+        },
+
+        async loadDeliveries(el) {
+            const id = el.closest('form').dataset.id;
+            if (this.deliveries[id]) {
+                delete this.deliveries[id];
+                return;
+            }
+            await this.fetchDeliveries(id);
+        },
+
+        async fetchDeliveries(id) {
+            try {
+                const response = await fetch(`/v2/admin/webhooks/${id}/deliveries?limit=20`);
+                const data = await response.json();
+                if (!response.ok) {
+                    showToast({ title: "查詢失敗", description: data.message || '無法取得送出紀錄', variant: "destructive" });
+                    return;
+                }
+                this.deliveries[id] = data.items;
+            } catch (e) {
+                showToast({ title: "系統錯誤", description: "操作過程發生問題", variant: "destructive" });
+            }
+        },
+
+        deliveryStatus(d) {
+            const status = d.statusCode ? `HTTP ${d.statusCode}` : (d.error || '無回應');
+            return `第 ${d.attempt} 次 · ${status} · ${d.durationMs}ms`;
+        },
+
+        async send(url, method, body, successMsg) {
+            if (this.submitting) return;
+            this.submitting = true;
+            try {
+                const response = await fetch(url, {
+                    method: method,
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-Token': document.querySelector('input[name="_csrf"]').value
+                    },
+                    body: body ? JSON.stringify(body) : undefined
+                });
+                if (response.ok) {
+                    showToast({ title: "操作成功", description: successMsg, variant: "default" });
+                    setTimeout(() => window.location.reload(), 600);
+                } else {
+                    const data = await response.json();
+                    showToast({
+                        title: "操作失敗",
+                        description: data.message || '請確認輸入內容',
+                        variant: "destructive"
+                    });
+                }
+            } catch (e) {
+                showToast({ title: "系統錯誤", description: "操作過程發生問題", variant: "destructive" });
+            } finally {
+                this.submitting = false;
+            }
+        }
+    };
+}
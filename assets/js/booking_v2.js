@@ -24,8 +24,11 @@ const getStatusClasses = (s) => {
     const m = {
         'Booked': 'bg-[#60A5FA] text-white border-transparent',
         'Leave': 'bg-[#F59E0B]/10 text-[#F59E0B] border border-[#F59E0B]/30',
+        'LeavePending': 'bg-[#F59E0B]/10 text-[#F59E0B] border border-dashed border-[#F59E0B]/50',
         'CheckedIn': 'bg-[#10B981]/10 text-[#10B981] border border-[#10B981]/30',
-        'Absent': 'bg-[#EF4444]/10 text-[#EF4444] border border-[#EF4444]/30'
+        'Absent': 'bg-[#EF4444]/10 text-[#EF4444] border border-[#EF4444]/30',
+        'Waitlist': 'bg-[#A78BFA]/10 text-[#A78BFA] border border-[#A78BFA]/30',
+        'Rescheduled': 'bg-[#60A5FA]/10 text-[#60A5FA] border border-[#60A5FA]/30'
     };
     return m[s] || 'bg-[#3F3F46]/10 text-[#A1A1AA] border border-[#3F3F46]/30';
 };
@@ -33,7 +36,7 @@ const getStatusClasses = (s) => {
 const jsRenderTag = (p, mini) => {
     const s = p.status || p.Status, n = p.name || p.Name, classes = "rounded transition-colors " + getStatusClasses(s);
     if (mini) return ("<span class=\"text-[9px] px-1.5 py-0.5 rounded-[4px] overflow-hidden whitespace-nowrap block " + classes + "\">" + n + "</span>");
-    const suffix = s === 'Leave' ? ' (請假)' : (s === 'CheckedIn' ? ' (已簽到)' : (s === 'Absent' ? ' (缺席)' : ''));
+    const suffix = s === 'Leave' ? ' (請假)' : s === 'LeavePending' ? ' (請假審核中)' : (s === 'CheckedIn' ? ' (已簽到)' : (s === 'Absent' ? ' (缺席)' : (s === 'Waitlist' ? ' (候補)' : (s === 'CoachCancelled' ? ' (停課)' : (s === 'Rescheduled' ? ' (改期)' : '')))));
     const slotId = p.slot_id || p.SlotID;
     const bookingTime = p.booking_time || p.BookingTime;
     const bookingId = p.booking_id || p.BookingID;
@@ -71,6 +74,7 @@ async function refreshSlot(slotId) {
         if (popupSlotId && popupSlotId.value === slotId) {
             const countEl = document.getElementById('popup-booked-count');
             if (countEl) countEl.innerText = s.booked_count;
+            setPopupWaitlistMode(s.is_full);
         }
     } catch (e) { console.error("Refresh slot failed:", e); }
 }
@@ -110,13 +114,6 @@ async function fetchMonthlyStats(y, m) {
         const ts = document.getElementById('total-sessions'); if (ts) ts.innerText = stats.total_sessions + " 堂";
         const tl = document.getElementById('total-leave'); if (tl) tl.innerText = stats.total_leave;
 
-        const frequentList = document.getElementById('frequent-names-list');
-        if (frequentList && stats.children) {
-            frequentList.innerHTML = stats.children.map(c => {
-                const escapedName = c.name.replace(/'/g, "\\'");
-                return `<button onclick="addDraftTag('${escapedName}')" class="px-3 py-1.5 rounded-full bg-[#27272A] text-white text-sm border border-[#3A3A3C] hover:bg-[#3A3A3C]">${c.name}</button>`;
-            }).join('');
-        }
     } catch (e) { console.error(e); }
 }
 
@@ -226,9 +223,41 @@ function openBookingPopup(id, time, endTime, title, bookedCount, capacity, atten
     if (attendees && attendees.length) { document.getElementById('booked-list-wrapper').classList.remove('hidden'); attendees.forEach(p => addBookedTag(p.name || p.Name, p.status || p.Status, p.booking_time || p.BookingTime, p.booking_id || p.BookingID)); }
     else document.getElementById('booked-list-wrapper').classList.add('hidden');
     
+    const isFull = bookedCount >= capacity;
+    setPopupWaitlistMode(isFull);
+    if (isFull) syncPopupWaitlist(id);
     document.getElementById('booking-popup').classList.remove('hidden');
 }
 
+// 額滿時改為加入候補
+function setPopupWaitlistMode(isFull) {
+    const btn = document.getElementById('booking-submit-btn'), hint = document.getElementById('popup-waitlist-hint');
+    if (!btn) return;
+    // 補課只能預約尚有名額的場次
+    const makeUp = !isFull && window.pendingMakeUp;
+    btn.dataset.full = isFull ? "true" : "";
+    btn.dataset.waitlist = isFull ? "true" : "";
+    btn.dataset.makeup = makeUp ? "true" : "";
+    btn.innerText = isFull ? "加入候補" : (makeUp ? "確認補課（" + window.pendingMakeUp.name + "）" : "確認預約");
+    if (hint) hint.classList.toggle('hidden', !isFull);
+    const makeUpHint = document.getElementById('popup-makeup-hint');
+    if (makeUpHint) {
+        makeUpHint.classList.toggle('hidden', !makeUp);
+        if (makeUp) document.getElementById('popup-makeup-name').textContent = window.pendingMakeUp.name;
+    }
+}
+
+// 行事曆資料不含候補，開啟額滿課程時另外載入使用者的候補學員
+async function syncPopupWaitlist(slotId) {
+    try {
+        const s = await fetchApi("/api/v2/calendar/slots/" + slotId);
+        if (document.getElementById('popup-slot-id').value !== slotId) return;
+        (s.attendees || []).filter(p => p.status === 'Waitlist').forEach(p => {
+            if (!document.querySelector('#booked-participants-list [data-id="' + p.booking_id + '"]')) addBookedTag(p.name, p.status, p.booking_time, p.booking_id);
+        });
+    } catch (e) { console.error("Load waitlist failed:", e); }
+}
+
 function closeBookingPopup() { 
     window.currentIdempotencyKey = null;
     document.getElementById('booking-popup').classList.add('hidden'); 
@@ -254,6 +283,7 @@ const addBookedTag = (n, s, t, id) => {
     const tag = document.createElement('div'); tag.className = "flex items-center gap-1 px-3 py-1 rounded-full cursor-pointer transition-all " + getStatusClasses(s);
     tag.innerHTML = ("<span>" + n + "</span>"); 
     tag.dataset.status = s;
+    tag.dataset.id = id;
     tag.onclick = () => handleTagAction(tag, "booking", n, tag.dataset.status, t, id, (ns) => { 
         if (ns === "Remove") tag.remove(); 
         else {
@@ -272,10 +302,48 @@ async function switchMyBookingsTab(type) {
     l.innerHTML = '<div class="text-center text-[#8E8E93] py-8">載入中...</div>';
     try {
         const data = await fetchApi("/api/v2/my-bookings?type=" + type);
+        renderCreditBalance(data.credit);
+        renderMakeUpCredits(data.make_up_credits);
         l.innerHTML = data.items.length ? data.items.map(item => ("<div class=\"bg-[#000000] p-3 rounded-lg border border-[#27272A]\"><div class=\"mb-2\"><div class=\"text-white font-bold\">" + item.date_display + "</div><div class=\"text-xs text-[#8E8E93]\">" + item.title + "</div></div><div class=\"flex flex-wrap gap-2\">" + item.attendees.map(p => jsRenderTag(p, false)).join('') + "</div></div>")).join('') : '<div class="text-center text-[#8E8E93] py-8">無預約紀錄</div>';
     } catch(e) { l.innerHTML = '<div class="text-center text-[#F87171] py-8">載入失敗</div>'; }
 }
 
+function renderCreditBalance(credit) {
+    const box = document.getElementById('my-bookings-credit');
+    if (!box) return;
+    if (!credit) { box.classList.add('hidden'); return; }
+    document.getElementById('my-bookings-credit-available').textContent = credit.available;
+    const detail = [];
+    if (credit.reserved > 0) detail.push("已預約 " + credit.reserved + " 堂");
+    if (credit.next_expiry) detail.push("最近到期 " + credit.next_expiry);
+    document.getElementById('my-bookings-credit-detail').innerHTML = detail.map(d => "<div>" + d + "</div>").join('');
+    box.classList.remove('hidden');
+}
+
+// 請假核准後發放的補課資格，選擇後回到行事曆挑選補課場次
+function renderMakeUpCredits(credits) {
+    const box = document.getElementById('my-bookings-makeup');
+    if (!box) return;
+    if (!credits || !credits.length) { box.classList.add('hidden'); return; }
+    document.getElementById('my-bookings-makeup-list').innerHTML = credits.map(c => {
+        const escapedName = c.child_name.replace(/'/g, "\\'");
+        return "<div class=\"flex items-center justify-between\"><div><div class=\"text-white text-sm font-bold\">" + c.child_name + "</div><div class=\"text-[10px] text-zinc-500\">" + c.expires_at + " 前開課的場次</div></div><button class=\"text-xs px-3 py-1 rounded bg-[#10B981]/10 text-[#10B981] border border-[#10B981]/30\" onclick=\"startMakeUp('" + c.id + "', '" + escapedName + "')\">預約補課</button></div>";
+    }).join('');
+    box.classList.remove('hidden');
+}
+
+function startMakeUp(id, name) {
+    window.pendingMakeUp = { id, name };
+    closeMyBookings();
+    showToast({ title: "預約補課", description: "請選擇 " + name + " 要補課的場次", variant: "default" });
+}
+
+function cancelMakeUp() {
+    window.pendingMakeUp = null;
+    const btn = document.getElementById('booking-submit-btn');
+    setPopupWaitlistMode(btn && btn.dataset.full === "true");
+}
+
 async function handleMyBookingTagClick(btn, n, s, t, id, slotId) { 
     handleTagAction(btn, "my-booking", n, btn.dataset.status || s, t, id, (ns) => { 
         if (ns === "Remove") btn.remove(); 
@@ -332,11 +400,20 @@ async function handleTagAction(btn, prefix, n, s, t, id, callback, slotId) {
             if (prefix === 'my-booking') closeMyBookings();
             openLeaveRequest(id, n, currentSlotId);
         }
-    } else if (s === "Leave") {
-        if (await showInlineConfirm(prefix, "恢復預約", "取消 " + n + " 的請假？")) {
+    } else if (s === "Leave" || s === "LeavePending") {
+        if (await showInlineConfirm(prefix, "恢復預約", (s === "LeavePending" ? "撤回 " : "取消 ") + n + " 的請假？")) {
             window.currentIdempotencyKey = self.crypto.randomUUID();
             await executeAction("/api/v2/bookings/" + id + "/leave", 'DELETE', "Booked");
         }
+    } else if (s === "Rescheduled") {
+        if (await showInlineConfirm(prefix, "免費取消", "課程時間或地點已異動，確定取消 " + n + " 的預約？")) {
+            window.currentIdempotencyKey = self.crypto.randomUUID();
+            await executeAction("/api/v2/bookings/" + id, 'DELETE', "Remove");
+        }
+    } else if (s === "Waitlist") {
+        if (await showInlineConfirm(prefix, "退出候補", "確定讓 " + n + " 退出候補？")) {
+            await executeAction("/api/v2/waitlist/" + currentSlotId + "/entries/" + id, 'DELETE', "Remove");
+        }
     }
 }
 
@@ -351,12 +428,13 @@ function showInlineConfirm(prefix, title, msg) {
     });
 }
 
-function addDraftTag(name) {
+function addDraftTag(name, studentId) {
     const input = document.getElementById('smart-input'), container = document.getElementById('smart-input-container');
-    if ([...container.querySelectorAll('[data-name]')].some(t => t.dataset.name === name)) return;
+    if ([...container.querySelectorAll('[data-name]')].some(t => t.dataset.name === name || (studentId && t.dataset.studentId === studentId))) return;
     const tag = document.createElement('div'); tag.className = "flex items-center gap-1 px-3 py-1 rounded-full bg-[#27272A] text-white text-sm cursor-pointer border border-[#3A3A3C]";
     tag.innerHTML = ("<span>" + name + "</span><span class=\"text-[#8E8E93] text-xs ml-1\">×</span>");
     tag.dataset.name = name; tag.dataset.draft = "true"; tag.onclick = () => tag.remove();
+    if (studentId) tag.dataset.studentId = studentId;
     container.insertBefore(tag, input);
 }
 
@@ -374,10 +452,17 @@ const submitBooking = async () => {
         names.push(pendingName);
     }
 
+    const submitBtn = document.getElementById('booking-submit-btn');
+    if (submitBtn.dataset.makeup === "true") { submitMakeUp(id, submitBtn); return; }
+
     if (!names.length) { closeBookingPopup(); return; }
+    // 已建立的學員以 ID 送出，新輸入的姓名由後端建立學員
+    const studentIds = [...tags].filter(t => t.dataset.studentId).map(t => t.dataset.studentId);
+    const newNames = [...tags].filter(t => !t.dataset.studentId).map(t => t.dataset.name);
+    if (pendingName && !newNames.includes(pendingName)) newNames.push(pendingName);
 
-    const submitBtn = document.querySelector('#booking-main-view button[onclick="submitBooking()"]');
     const originalText = submitBtn.innerText;
+    const isWaitlist = submitBtn.dataset.waitlist === "true";
     
     isSubmitting = true;
     submitBtn.disabled = true;
@@ -387,23 +472,24 @@ const submitBooking = async () => {
     try {
         const opts = { 
             method: 'POST', 
-            body: JSON.stringify({ slot_id: id, student_names: names }),
+            body: JSON.stringify(isWaitlist ? { slot_id: id, student_names: names } : { slot_id: id, student_ids: studentIds, student_names: newNames }),
             headers: {} 
         };
         if (window.currentIdempotencyKey) {
             opts.headers['Idempotency-Key'] = window.currentIdempotencyKey;
         }
 
-        const res = await fetchApi('/api/v2/bookings', opts);
-        res.new_bookings.forEach(b => addBookedTag(b.name, b.status, b.booking_time, b.booking_id));
+        const res = await fetchApi(isWaitlist ? '/api/v2/waitlist' : '/api/v2/bookings', opts);
+        (isWaitlist ? res.new_entries : res.new_bookings).forEach(b => addBookedTag(b.name, b.status, b.booking_time, b.booking_id));
         tags.forEach(t => t.remove()); 
         input.value = ''; 
-        showToast({ title: "成功", description: "預約成功！", variant: "default" });
+        showToast({ title: "成功", description: isWaitlist ? "已加入候補，有名額時將通知您！" : "預約成功！", variant: "default" });
         closeBookingPopup(); 
         refreshSlot(id); 
         refreshStats();
+        if (!isWaitlist && newNames.length) loadStudents();
     } catch(e) { 
-        showToast({ title: "預約失敗", description: e.message, variant: "destructive" });
+        showToast({ title: isWaitlist ? "候補失敗" : "預約失敗", description: e.message, variant: "destructive" });
     } finally {
         isSubmitting = false;
         submitBtn.disabled = false;
@@ -412,6 +498,39 @@ const submitBooking = async () => {
     }
 };
 
+// submitMakeUp 以補課資格預約目前場次，不需輸入學員
+async function submitMakeUp(id, submitBtn) {
+    const originalText = submitBtn.innerText;
+    isSubmitting = true;
+    submitBtn.disabled = true;
+    submitBtn.innerText = "處理中...";
+    submitBtn.classList.add('opacity-50', 'cursor-not-allowed');
+    try {
+        const opts = {
+            method: 'POST',
+            body: JSON.stringify({ slot_id: id, credit_id: window.pendingMakeUp.id }),
+            headers: {}
+        };
+        if (window.currentIdempotencyKey) {
+            opts.headers['Idempotency-Key'] = window.currentIdempotencyKey;
+        }
+        const res = await fetchApi('/api/v2/make-ups', opts);
+        res.new_bookings.forEach(b => addBookedTag(b.name, b.status, b.booking_time, b.booking_id));
+        window.pendingMakeUp = null;
+        showToast({ title: "成功", description: "補課預約成功！", variant: "default" });
+        closeBookingPopup();
+        refreshSlot(id);
+        refreshStats();
+    } catch(e) {
+        showToast({ title: "補課失敗", description: e.message, variant: "destructive" });
+    } finally {
+        isSubmitting = false;
+        submitBtn.disabled = false;
+        submitBtn.innerText = originalText;
+        submitBtn.classList.remove('opacity-50', 'cursor-not-allowed');
+    }
+}
+
 function openLeaveRequest(id, name, slotId) { 
     window.currentIdempotencyKey = self.crypto.randomUUID();
     document.getElementById('leave-booking-id').value = id; 
@@ -450,8 +569,8 @@ async function submitLeaveRequest(e) {
             opts.headers['Idempotency-Key'] = window.currentIdempotencyKey;
         }
 
-        await fetchApi("/api/v2/bookings/" + document.getElementById('leave-booking-id').value + "/leave", opts); 
-        showToast({ title: "成功", description: "請假申請已送出", variant: "default" });
+        const res = await fetchApi("/api/v2/bookings/" + document.getElementById('leave-booking-id').value + "/leave", opts); 
+        showToast({ title: "成功", description: res.message || "請假申請已送出", variant: "default" });
         cancelLeaveRequest(); 
         closeBookingPopup(); 
         await refreshSlot(slotId); 
@@ -490,3 +609,92 @@ async function shareBookingStatus() {
         btn.innerHTML = originalContent;
     }
 }
+
+// --- Students ---
+
+let studentsCache = [];
+
+const escapeHtml = (v) => String(v ?? '').replace(/[&<>"']/g, ch => ({ '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' }[ch]));
+
+async function openStudents() {
+    document.getElementById('students-modal').classList.remove('hidden');
+    resetStudentForm();
+    await loadStudents();
+}
+
+function closeStudents() { document.getElementById('students-modal').classList.add('hidden'); }
+
+async function loadStudents() {
+    try {
+        const res = await fetchApi('/api/v2/students');
+        studentsCache = res.students || [];
+        renderStudents();
+    } catch (e) {
+        showToast({ title: "錯誤", description: e.message, variant: "destructive" });
+    }
+}
+
+function renderStudents() {
+    const list = document.getElementById('students-list');
+    if (list) {
+        list.innerHTML = studentsCache.length ? studentsCache.map(st => (
+            "<div class=\"bg-black/40 p-3 rounded-xl border border-white/5 flex justify-between items-center\">" +
+            "<div><div class=\"text-white font-bold\">" + escapeHtml(st.name) + (st.nickname ? " <span class=\"text-xs text-zinc-500\">(" + escapeHtml(st.nickname) + ")</span>" : "") + "</div>" +
+            "<div class=\"text-[10px] text-zinc-500\">" + escapeHtml(st.birthdate || '') + (st.notes ? " · " + escapeHtml(st.notes) : "") + "</div></div>" +
+            "<div class=\"flex gap-2\"><button onclick=\"editStudent('" + st.id + "')\" class=\"text-xs text-[#FFD700] font-bold\">編輯</button>" +
+            "<button onclick=\"deleteStudent('" + st.id + "')\" class=\"text-xs text-[#F87171] font-bold\">刪除</button></div></div>"
+        )).join('') : "<p class=\"text-zinc-500 text-sm text-center\">尚未建立學員，預約時輸入的姓名也會自動建立</p>";
+    }
+    const frequentList = document.getElementById('frequent-names-list');
+    if (frequentList) {
+        frequentList.innerHTML = studentsCache.map(st => (
+            "<button data-student-id=\"" + st.id + "\" data-student-name=\"" + escapeHtml(st.name) + "\" onclick=\"addDraftTag(this.dataset.studentName, this.dataset.studentId)\" class=\"px-3 py-1.5 rounded-full bg-[#27272A] text-zinc-300 text-sm border border-[#3A3A3C] hover:bg-[#3A3A3C] transition-colors\">" + escapeHtml(st.display_name) + "</button>"
+        )).join('');
+    }
+}
+
+function resetStudentForm() {
+    ['student-id', 'student-name', 'student-nickname', 'student-birthdate', 'student-notes'].forEach(id => { document.getElementById(id).value = ''; });
+    document.getElementById('student-form-title').innerText = '新增學員';
+}
+
+function editStudent(id) {
+    const st = studentsCache.find(s => s.id === id);
+    if (!st) return;
+    document.getElementById('student-id').value = st.id;
+    document.getElementById('student-name').value = st.name;
+    document.getElementById('student-nickname').value = st.nickname || '';
+    document.getElementById('student-birthdate').value = st.birthdate || '';
+    document.getElementById('student-notes').value = st.notes || '';
+    document.getElementById('student-form-title').innerText = '編輯 ' + st.name;
+}
+
+async function submitStudent(e) {
+    e.preventDefault();
+    const id = document.getElementById('student-id').value;
+    const body = {
+        name: document.getElementById('student-name').value.trim(),
+        nickname: document.getElementById('student-nickname').value.trim(),
+        birthdate: document.getElementById('student-birthdate').value,
+        notes: document.getElementById('student-notes').value.trim()
+    };
+    try {
+        await fetchApi(id ? '/api/v2/students/' + id : '/api/v2/students', { method: id ? 'PUT' : 'POST', body: JSON.stringify(body) });
+        showToast({ title: "成功", description: "學員資料已儲存", variant: "default" });
+        resetStudentForm();
+        await loadStudents();
+    } catch (err) {
+        showToast({ title: "儲存失敗", description: err.message, variant: "destructive" });
+    }
+}
+
+async function deleteStudent(id) {
+    const st = studentsCache.find(s => s.id === id);
+    if (!st || !(await showInlineConfirm('student', "刪除學員", "確定刪除 " + st.name + "？已有預約紀錄的學員無法刪除。"))) return;
+    try {
+        await fetchApi('/api/v2/students/' + id, { method: 'DELETE' });
+        await loadStudents();
+    } catch (err) {
+        showToast({ title: "刪除失敗", description: err.message, variant: "destructive" });
+    }
+}
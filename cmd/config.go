@@ -1,11 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/transport/web"
+	"seanAIgent/internal/event"
+	"seanAIgent/internal/util/timeutil"
 
+	"github.com/94peter/vulpes/log"
+	"github.com/94peter/vulpes/storage"
 	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+const defaultBillingTimezone = "Asia/Taipei"
+
 func ProvideWebConfig() web.Config {
 	opts := []web.Option{
 		web.WithPort(viper.GetUint16("http.port")),
@@ -34,3 +45,66 @@ func ProvideWebConfig() web.Config {
 	}
 	return cfg
 }
+
+// ProvideBillingPolicy 月結計費設定，未設定 billing.due_day 時為次月 10 號
+func ProvideBillingPolicy() entity.BillingPolicy {
+	tz := viper.GetString("billing.timezone")
+	if tz == "" {
+		tz = defaultBillingTimezone
+	}
+	loc, err := timeutil.GetLocation(tz)
+	if err != nil {
+		loc, _ = timeutil.GetLocation(defaultBillingTimezone)
+	}
+	return entity.NewBillingPolicy(
+		viper.GetInt("billing.price_per_class"),
+		viper.GetInt("billing.due_day"),
+		loc,
+	)
+}
+
+// ProvideBookingPolicy 新建場次預設的預約規則，未設定的項目沿用系統預設值
+func ProvideBookingPolicy() entity.BookingPolicy {
+	def := entity.DefaultBookingPolicy()
+	durationOr := func(key string, fallback time.Duration) time.Duration {
+		if !viper.IsSet(key) {
+			return fallback
+		}
+		return viper.GetDuration(key)
+	}
+	policy, err := entity.NewBookingPolicy(
+		durationOr("booking.cancel_window", def.CancelWindow()),
+		durationOr("booking.leave_cutoff", def.LeaveCutoff()),
+		durationOr("booking.check_in_open", def.CheckInOpen()),
+		durationOr("booking.attendance_amend", def.AttendanceAmend()),
+	)
+	if err != nil {
+		log.Warnf("invalid booking policy config, use default: %v", err)
+		return def
+	}
+	return policy
+}
+
+// ProvideArchiver 事件封存，排程與 events archive 指令共用
+func ProvideArchiver(eventLog event.EventLog, db *mongo.Database) (*event.Archiver, error) {
+	index, err := event.NewMongoArchiveIndex(db)
+	if err != nil {
+		return nil, err
+	}
+	r2storage, err := newR2Storage(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return event.NewArchiver(eventLog, r2storage, index,
+		event.WithArchivePrefix(viper.GetString("events.archive.prefix"))), nil
+}
+
+// newR2Storage 報表與事件封存檔共用的 R2 儲存空間 (storage.r2.*)
+func newR2Storage(ctx context.Context) (storage.Storage, error) {
+	return storage.New(ctx,
+		storage.WithAccessKey(viper.GetString("storage.r2.access_key_id")),
+		storage.WithSecretKey(viper.GetString("storage.r2.secret_access_key")),
+		storage.WithEndpoint(viper.GetString("storage.r2.endpoint")),
+		storage.WithBucket(viper.GetString("storage.r2.bucket")),
+	)
+}
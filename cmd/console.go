@@ -19,7 +19,6 @@ import (
 	"github.com/94peter/vulpes/db/mgo"
 	_ "github.com/94peter/vulpes/ezapi/session"
 	"github.com/94peter/vulpes/log"
-	"github.com/94peter/vulpes/storage"
 	"github.com/invopop/ctxi18n"
 	"github.com/line/line-bot-sdk-go/v7/linebot"
 	"github.com/spf13/cobra"
@@ -120,12 +119,7 @@ to quickly create a Cobra application.`,
 		var cancelSlice []context.CancelFunc
 		storageCtx, storageCancel := context.WithCancel(mainCtx)
 		cancelSlice = append(cancelSlice, storageCancel)
-		r2storage, err := storage.New(storageCtx,
-			storage.WithAccessKey(viper.GetString("storage.r2.access_key_id")),
-			storage.WithSecretKey(viper.GetString("storage.r2.secret_access_key")),
-			storage.WithEndpoint(viper.GetString("storage.r2.endpoint")),
-			storage.WithBucket(viper.GetString("storage.r2.bucket")),
-		)
+		r2storage, err := newR2Storage(storageCtx)
 		if err != nil {
 			log.Fatal(err.Error())
 		}
@@ -134,6 +128,10 @@ to quickly create a Cobra application.`,
 		var appointmentState textreply.LineKeywordReply
 		var catchUpCheckIn textreply.LineKeywordReply
 		var userApptStatsNotify notification.UserApptStatsNotifier
+		var waitlistPromotedNotify notification.WaitlistPromotedNotifier
+		var trainDateCancelledNotify notification.TrainDateCancelledNotifier
+		var trainDateRescheduledNotify notification.TrainDateRescheduledNotifier
+		var leaveReviewNotify notification.LeaveReviewNotifier
 		var webService web.WebService
 
 		// v2 initialization
@@ -147,6 +145,10 @@ to quickly create a Cobra application.`,
 		}
 		dbRepo := db.NewDbRepoAndIdGenerate()
 		userApptStatsNotify = notification.NewUserApptStatsNotifier(dbRepo)
+		waitlistPromotedNotify = notification.NewWaitlistPromotedNotifier(dbRepo)
+		trainDateCancelledNotify = notification.NewTrainDateCancelledNotifier(dbRepo, viper.GetString("linebot.group_id"))
+		trainDateRescheduledNotify = notification.NewTrainDateRescheduledNotifier(dbRepo)
+		leaveReviewNotify = notification.NewLeaveReviewNotifier(dbRepo)
 
 		checkinReplyer = linemsg.NewStartCheckinReply(registry.FindNearestTrainByTime)
 		appointmentState = linemsg.NewAppointmentStateReply(registry.QueryAllUserApptStats, r2storage)
@@ -159,6 +161,18 @@ to quickly create a Cobra application.`,
 		notifyService.RegisterNotification(
 			"user-appt-stats", userApptStatsNotify,
 		)
+		notifyService.RegisterNotification(
+			"waitlist-promoted", waitlistPromotedNotify,
+		)
+		notifyService.RegisterNotification(
+			"train-date-cancelled", trainDateCancelledNotify,
+		)
+		notifyService.RegisterNotification(
+			"train-date-rescheduled", trainDateRescheduledNotify,
+		)
+		notifyService.RegisterNotification(
+			"leave-review", leaveReviewNotify,
+		)
 		err = botreplyer.InitBotReplyer(
 			botctx,
 			botreplyer.WithLineConfig(
@@ -169,6 +183,7 @@ to quickly create a Cobra application.`,
 					linemsg.NewStartBookingReply(),
 					checkinReplyer,
 					catchUpCheckIn,
+					linemsg.NewLeaveReviewReply(registry.ResolveActor, registry.ApproveLeave, registry.RejectLeave),
 				),
 				line.WithAdminUserId(viper.GetString("linebot.admin_user_id")),
 				line.WithNotificationService(notifyService),
@@ -214,6 +229,7 @@ to quickly create a Cobra application.`,
 		}
 		log.Info("Server is shutting down...")
 		time.Sleep(time.Second)
+		shutdownRegistry(cmd, registry)
 	},
 }
 
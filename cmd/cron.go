@@ -16,6 +16,9 @@ import (
 	"github.com/spf13/viper"
 )
 
+// archiveEventsTask 事件紀錄 30 天後由 TTL 刪除，未在設定檔排程時仍每天封存
+var archiveEventsTask = CronTask{Name: "archive-events", Spec: "30 3 * * *", Path: "/cron/archive-events"}
+
 type CronTask struct {
 	Name string `mapstructure:"name"`
 	Spec string `mapstructure:"spec"`
@@ -41,6 +44,7 @@ var cronCmd = &cobra.Command{
 			fmt.Printf("讀取 Cron 任務設定失敗: %v\n", err)
 			return
 		}
+		tasks = withDefaultTasks(tasks)
 
 		if len(tasks) == 0 {
 			fmt.Println("未偵測到任何排程任務，請檢查設定檔中的 cron.tasks 區塊。")
@@ -108,6 +112,16 @@ func init() {
 	rootCmd.AddCommand(cronCmd)
 }
 
+// withDefaultTasks 設定檔已有相同 path 的任務時以設定檔為準
+func withDefaultTasks(tasks []CronTask) []CronTask {
+	for _, t := range tasks {
+		if t.Path == archiveEventsTask.Path {
+			return tasks
+		}
+	}
+	return append(tasks, archiveEventsTask)
+}
+
 // 統一發送 POST 請求
 func triggerAPI(url string) {
 	client := &http.Client{
@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"github.com/94peter/vulpes/log"
+	"github.com/spf13/cobra"
+
+	"seanAIgent/internal/booking/usecase"
+	"seanAIgent/internal/booking/usecase/core"
+	readDeadLetter "seanAIgent/internal/booking/usecase/deadletter/read"
+	writeDeadLetter "seanAIgent/internal/booking/usecase/deadletter/write"
+	writeEventLog "seanAIgent/internal/booking/usecase/eventlog/write"
+	"seanAIgent/internal/event"
+)
+
+const defaultRestoreCollection = "event_logs_restored"
+
+// eventsCmd represents the events command
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "領域事件維運工具",
+	Long:  `領域事件維運工具，需與服務使用相同的設定檔 (database.*)。`,
+}
+
+// deadLettersCmd 訂閱者重試用盡仍失敗的事件
+var deadLettersCmd = &cobra.Command{
+	Use:   "dead-letters",
+	Short: "查看、重送或捨棄處理失敗的事件",
+}
+
+var deadLettersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出處理失敗的事件，依最後失敗時間由新到舊",
+	Run: func(cmd *cobra.Command, args []string) {
+		subscriber, _ := cmd.Flags().GetString("subscriber")
+		topic, _ := cmd.Flags().GetString("topic")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		ctx, registry, closeDB := initEventsRegistry()
+		defer closeDB()
+		letters, ucErr := registry.QueryDeadLetters.Execute(ctx, readDeadLetter.ReqQueryDeadLetters{
+			SubscriberID: subscriber,
+			Topic:        topic,
+			Limit:        limit,
+		})
+		if ucErr != nil {
+			log.Fatalf("query dead letters fail: %v", ucErr)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTOPIC\tATTEMPTS\tLAST FAILED\tERROR")
+		for _, dl := range letters {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+				dl.ID, dl.Topic, dl.Attempts, dl.LastFailedAt.Local().Format(time.DateTime), truncate(dl.Error, 80))
+		}
+		_ = w.Flush()
+		fmt.Printf("%d dead letters\n", len(letters))
+	},
+}
+
+var deadLettersShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "顯示事件內容與錯誤",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, registry, closeDB := initEventsRegistry()
+		defer closeDB()
+		dl, ucErr := registry.GetDeadLetter.Execute(ctx, readDeadLetter.ReqGetDeadLetter{ID: args[0]})
+		if ucErr != nil {
+			log.Fatalf("get dead letter fail: %v", ucErr)
+		}
+		fmt.Printf("ID:           %s\n", dl.ID)
+		fmt.Printf("Subscriber:   %s\n", dl.SubscriberID)
+		fmt.Printf("Event:        %s\n", dl.EventID)
+		fmt.Printf("Topic:        %s\n", dl.Topic)
+		fmt.Printf("Version:      %d\n", dl.Event().Version())
+		fmt.Printf("Occurred at:  %s\n", dl.OccurredAt.Local().Format(time.DateTime))
+		fmt.Printf("Attempts:     %d\n", dl.Attempts)
+		fmt.Printf("First failed: %s\n", dl.FirstFailedAt.Local().Format(time.DateTime))
+		fmt.Printf("Last failed:  %s\n", dl.LastFailedAt.Local().Format(time.DateTime))
+		fmt.Printf("Error:        %s\n", dl.Error)
+		fmt.Printf("Data:         %s\n", dl.Data)
+	},
+}
+
+var deadLettersReplayCmd = &cobra.Command{
+	Use:   "replay <id>...",
+	Short: "交給原本的訂閱者重新處理，成功後移除",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, registry, closeDB := initEventsRegistry()
+		defer closeDB()
+		failed := 0
+		for _, id := range args {
+			if _, ucErr := registry.ReplayDeadLetter.Execute(ctx, writeDeadLetter.ReqReplayDeadLetter{ID: id}); ucErr != nil {
+				failed++
+				log.Errorf("replay %s fail: %v", id, ucErr)
+				continue
+			}
+			log.Infof("replayed %s", id)
+		}
+		if failed > 0 {
+			log.Fatalf("%d of %d dead letters failed to replay", failed, len(args))
+		}
+	},
+}
+
+var deadLettersDiscardCmd = &cobra.Command{
+	Use:   "discard <id>...",
+	Short: "捨棄不需重送的事件",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, registry, closeDB := initEventsRegistry()
+		defer closeDB()
+		for _, id := range args {
+			if _, ucErr := registry.DiscardDeadLetter.Execute(ctx, writeDeadLetter.ReqDiscardDeadLetter{ID: id}); ucErr != nil {
+				log.Fatalf("discard %s fail: %v", id, ucErr)
+			}
+			log.Infof("discarded %s", id)
+		}
+	},
+}
+
+var eventsReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "將事件紀錄重新交給訂閱者處理，用於修正後重建統計或快取",
+	Long: `依事件 ID 由舊到新，將 event_logs 中符合條件的事件直接交給指定訂閱者處理。
+處理失敗的事件寫入 dead letter，可再以 events dead-letters replay 重送。
+加上 --advance-progress 時，完成後將訂閱者進度推進到最後一筆重播的事件，不會往回調整；
+需重播到最新的事件 (不可指定 --to)，且 --from 之前沒有訂閱者尚未處理的事件；中斷時不調整進度。
+事件紀錄保留 30 天，更早的事件可先以 events archive restore 由封存檔還原，
+再加上 --collection 由還原的集合重播。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		subscriber, _ := cmd.Flags().GetString("subscriber")
+		topic, _ := cmd.Flags().GetString("topic")
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		rate, _ := cmd.Flags().GetInt("rate")
+		advanceProgress, _ := cmd.Flags().GetBool("advance-progress")
+		collection, _ := cmd.Flags().GetString("collection")
+		if collection != "" && advanceProgress {
+			log.Fatal("--advance-progress cannot be used with --collection")
+		}
+
+		req := writeEventLog.ReqReplayEvents{
+			SubscriberID:    subscriber,
+			Topic:           topic,
+			DryRun:          dryRun,
+			Rate:            rate,
+			AdvanceProgress: advanceProgress,
+		}
+		if from != "" {
+			t, err := time.ParseInLocation(time.DateOnly, from, time.Local)
+			if err != nil {
+				log.Fatalf("invalid --from: %v", err)
+			}
+			req.From = t
+		}
+		if to != "" {
+			t, err := time.ParseInLocation(time.DateOnly, to, time.Local)
+			if err != nil {
+				log.Fatalf("invalid --to: %v", err)
+			}
+			req.To = t.AddDate(0, 0, 1)
+		}
+
+		ctx, registry, closeDB := initEventsRegistry()
+		defer closeDB()
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		replayUC := registry.ReplayEvents
+		if collection != "" {
+			replayUC = restoredReplayUC(collection, registry.Subscribers)
+		}
+
+		var total int64
+		req.OnProgress = func(p writeEventLog.RespReplayEvents) {
+			total = p.Matched
+			done := p.Replayed + p.Failed
+			if done%100 == 0 || int64(done) == p.Matched {
+				fmt.Printf("\rreplayed %d/%d, failed %d", done, p.Matched, p.Failed)
+			}
+		}
+		resp, ucErr := replayUC.Execute(ctx, req)
+		if total > 0 {
+			fmt.Println()
+		}
+		if ucErr != nil {
+			if ucErr.Type() == core.ErrNotFound {
+				ids := make([]string, 0, len(registry.Subscribers))
+				for _, s := range registry.Subscribers {
+					ids = append(ids, s.ID())
+				}
+				log.Fatalf("unknown subscriber %q, available: %s", subscriber, strings.Join(ids, ", "))
+			}
+			log.Fatalf("replay events fail: %v", ucErr)
+		}
+		if dryRun {
+			fmt.Printf("%d events would be replayed into %s\n", resp.Matched, subscriber)
+			return
+		}
+		fmt.Printf("%d replayed, %d failed (moved to dead letters)\n", resp.Replayed, resp.Failed)
+		if advanceProgress && resp.LastEventID != "" {
+			fmt.Printf("progress of %s advanced to %s\n", subscriber, resp.LastEventID)
+		}
+	},
+}
+
+// restoredReplayUC 由封存檔還原的集合重播，該集合沒有 TTL
+func restoredReplayUC(collection string, subscribers []event.Subscriber) writeEventLog.ReplayEventsUseCase {
+	db := mgo.GetDatabase()
+	store, err := event.NewMongoEventStore(db, event.WithEventCollection(collection), event.WithEventTTL(0))
+	if err != nil {
+		log.Fatalf("init restored event store fail: %v", err)
+	}
+	deadLetters, err := event.ProvideDeadLetterStore(db)
+	if err != nil {
+		log.Fatalf("init dead letter store fail: %v", err)
+	}
+	return usecase.ProvideReplayEventsUC(store.(event.EventLog), deadLetters, subscribers)
+}
+
+// eventsArchiveCmd 事件紀錄 30 天後由 TTL 刪除，刪除前封存到 R2 (storage.r2.*)
+var eventsArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "封存即將過期的事件紀錄，或由封存檔還原",
+	Long: `封存檔為每天 (UTC)、每個主題一個 gzip 壓縮的 JSONL，key 為
+<events.archive.prefix>/YYYY/MM/DD/<topic>.jsonl.gz，清單記錄於 event_archives。`,
+}
+
+var eventsArchiveRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "封存發生超過指定天數的事件，cron 每天會自動執行",
+	Long: `只封存完整的一天，已封存的檔案略過；中斷後重新執行即可接續。
+--older-than 需小於事件紀錄保留的 30 天，預留排程失敗時重試的時間。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, _ := cmd.Flags().GetInt("older-than")
+		if olderThan <= 0 || olderThan >= 30 {
+			log.Fatal("--older-than must be between 1 and 29")
+		}
+		ctx, registry, closeDB := initEventsRegistry()
+		defer closeDB()
+		resp, err := registry.ArchiveEvents.Execute(ctx, writeEventLog.ReqArchiveEvents{OlderThanDays: olderThan})
+		if err != nil {
+			log.Fatalf("archive events fail: %v", err)
+		}
+		for _, e := range resp.Entries {
+			log.Infof("archived %s (%d events)", e.Key, e.Count)
+		}
+		fmt.Printf("%d files archived\n", len(resp.Entries))
+	},
+}
+
+var eventsArchiveListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出封存檔",
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := archiveFilterFlags(cmd)
+		ctx, archiver, closeDB := initArchiver()
+		defer closeDB()
+		entries, err := archiver.Entries(ctx, filter)
+		if err != nil {
+			log.Fatalf("list archives fail: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "DAY\tTOPIC\tEVENTS\tSIZE\tKEY")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", e.Day.Format(time.DateOnly), e.Topic, e.Count, e.Size, e.Key)
+		}
+		_ = w.Flush()
+		fmt.Printf("%d files\n", len(entries))
+	},
+}
+
+var eventsArchiveRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "將封存檔匯入另一個集合，供調查或重播",
+	Long: `匯入的集合沒有 TTL，同一事件重複匯入時覆蓋。用完請自行刪除集合。
+不可匯入 event_logs，否則會立即被 TTL 刪除。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := archiveFilterFlags(cmd)
+		collection, _ := cmd.Flags().GetString("collection")
+		if collection == "event_logs" {
+			log.Fatal("cannot restore into event_logs")
+		}
+		ctx, archiver, closeDB := initArchiver()
+		defer closeDB()
+		target, err := event.NewMongoEventStore(mgo.GetDatabase(), event.WithEventCollection(collection), event.WithEventTTL(0))
+		if err != nil {
+			log.Fatalf("init restore collection fail: %v", err)
+		}
+		n, err := archiver.Restore(ctx, target, filter)
+		if err != nil {
+			log.Fatalf("restore fail after %d events: %v", n, err)
+		}
+		fmt.Printf("%d events restored into %s\n", n, collection)
+	},
+}
+
+// archiveFilterFlags 封存檔以 UTC 日期分檔，--from、--to 亦為 UTC 日期
+func archiveFilterFlags(cmd *cobra.Command) event.ArchiveFilter {
+	topic, _ := cmd.Flags().GetString("topic")
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	filter := event.ArchiveFilter{Topic: topic}
+	if from != "" {
+		t, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			log.Fatalf("invalid --from: %v", err)
+		}
+		filter.From = t
+	}
+	if to != "" {
+		t, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			log.Fatalf("invalid --to: %v", err)
+		}
+		filter.To = t.AddDate(0, 0, 1)
+	}
+	return filter
+}
+
+func initArchiver() (context.Context, *event.Archiver, func()) {
+	ctx, closeDB := initMigrateDB()
+	db := mgo.GetDatabase()
+	eventStore, err := event.NewMongoEventStore(db)
+	if err != nil {
+		closeDB()
+		log.Fatalf("init event store fail: %v", err)
+	}
+	archiver, err := ProvideArchiver(eventStore.(event.EventLog), db)
+	if err != nil {
+		closeDB()
+		log.Fatalf("init archiver fail: %v", err)
+	}
+	return ctx, archiver, closeDB
+}
+
+func initEventsRegistry() (ctx context.Context, registry *usecase.Registry, closeDB func()) {
+	ctx, closeDB = initMigrateDB()
+	registry, err := GetUseCaseRegistry()
+	if err != nil {
+		closeDB()
+		log.Fatalf("GetUseCaseRegistry fail: %v", err)
+	}
+	return ctx, registry, closeDB
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+	eventsCmd.AddCommand(deadLettersCmd)
+	deadLettersCmd.AddCommand(deadLettersListCmd, deadLettersShowCmd, deadLettersReplayCmd, deadLettersDiscardCmd)
+	eventsCmd.AddCommand(eventsReplayCmd)
+	eventsCmd.AddCommand(eventsArchiveCmd)
+	eventsArchiveCmd.AddCommand(eventsArchiveRunCmd, eventsArchiveListCmd, eventsArchiveRestoreCmd)
+
+	deadLettersListCmd.Flags().String("subscriber", "", "只列出指定訂閱者")
+	deadLettersListCmd.Flags().String("topic", "", "只列出指定主題")
+	deadLettersListCmd.Flags().Int("limit", 100, "最多列出的筆數")
+
+	eventsReplayCmd.Flags().String("subscriber", "", "重播給哪個訂閱者 (必填)")
+	eventsReplayCmd.Flags().String("topic", "", "主題，預設為訂閱者的主題")
+	eventsReplayCmd.Flags().String("from", "", "事件發生的起始日期 (YYYY-MM-DD)")
+	eventsReplayCmd.Flags().String("to", "", "事件發生的結束日期 (YYYY-MM-DD)，含當天")
+	eventsReplayCmd.Flags().Bool("dry-run", false, "只計算符合條件的事件數")
+	eventsReplayCmd.Flags().Int("rate", 50, "每秒最多重播的事件數，0 代表不限制")
+	eventsReplayCmd.Flags().Bool("advance-progress", false, "完成後將訂閱者進度推進到最後一筆重播的事件 (不可搭配 --to)")
+	eventsReplayCmd.Flags().String("collection", "", "改由 events archive restore 還原的集合重播")
+	_ = eventsReplayCmd.MarkFlagRequired("subscriber")
+
+	eventsArchiveRunCmd.Flags().Int("older-than", 25, "封存發生超過幾天的事件")
+	for _, c := range []*cobra.Command{eventsArchiveListCmd, eventsArchiveRestoreCmd} {
+		c.Flags().String("topic", "", "只處理指定主題")
+		c.Flags().String("from", "", "封存日期的起始 (YYYY-MM-DD，UTC)")
+		c.Flags().String("to", "", "封存日期的結束 (YYYY-MM-DD，UTC)，含當天")
+	}
+	eventsArchiveRestoreCmd.Flags().String("collection", defaultRestoreCollection, "匯入的集合")
+}
@@ -4,31 +4,140 @@ Copyright © 2026 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+	"time"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"github.com/94peter/vulpes/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+
+	writeStats "seanAIgent/internal/booking/usecase/stats/write"
+	writeStudent "seanAIgent/internal/booking/usecase/student/write"
 )
 
 // migrateCmd represents the migrate command
 var migrateCmd = &cobra.Command{
 	Use:   "migrate",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "資料遷移工具",
+	Long:  `資料遷移工具，需與服務使用相同的設定檔 (database.*)。`,
+}
+
+// migrateStudentsCmd 將舊預約的 childName 對應到學員，找不到同名學員時自動建立
+var migrateStudentsCmd = &cobra.Command{
+	Use:   "students",
+	Short: "將舊預約的孩子姓名對應到學員",
+	Long: `將尚未對應學員的預約依家長與姓名 (忽略多餘空白) 對應到學員，找不到時自動建立。
+同一孩子的不同寫法 (例如「小明」與「王小明」) 會建立成不同學員，請再以 merge-students 合併。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		syncMonths, _ := cmd.Flags().GetInt("sync-months")
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+		ctx, closeDB := initMigrateDB()
+		defer closeDB()
+		registry, err := GetUseCaseRegistry()
+		if err != nil {
+			log.Fatalf("GetUseCaseRegistry fail: %v", err)
+		}
+
+		resp, ucErr := registry.MigrateChildNames.Execute(ctx, writeStudent.ReqMigrateChildNames{DryRun: dryRun})
+		if ucErr != nil {
+			log.Fatalf("migrate child names fail: %v", ucErr)
+		}
+		for _, s := range resp.CreatedStudents {
+			log.Infof("student %s: user=%s(%s) name=%s", s.ID(), s.Parent().UserID(), s.Parent().UserName(), s.Name())
+		}
+		for _, id := range resp.FailedApptIDs {
+			log.Warnf("appointment %s: cannot link to student, please fix manually", id)
+		}
+		log.Infof("linked=%d created_students=%d failed=%d dry_run=%v",
+			resp.Linked, len(resp.CreatedStudents), len(resp.FailedApptIDs), dryRun)
+		if dryRun {
+			return
+		}
+		syncRecentMonthlyStats(ctx, registry.BatchSyncMonthlyStats, syncMonths)
+	},
 }
 
-func init() {
-	rootCmd.AddCommand(migrateCmd)
+// migrateMergeStudentsCmd 將重複建立的學員合併
+var migrateMergeStudentsCmd = &cobra.Command{
+	Use:   "merge-students",
+	Short: "合併同一家長底下重複的學員",
+	Run: func(cmd *cobra.Command, args []string) {
+		target, _ := cmd.Flags().GetString("target")
+		sources, _ := cmd.Flags().GetStringSlice("sources")
+		syncMonths, _ := cmd.Flags().GetInt("sync-months")
 
-	// Here you will define your flags and configuration settings.
+		ctx, closeDB := initMigrateDB()
+		defer closeDB()
+		registry, err := GetUseCaseRegistry()
+		if err != nil {
+			log.Fatalf("GetUseCaseRegistry fail: %v", err)
+		}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// migrateCmd.PersistentFlags().String("foo", "", "A help for foo")
+		student, ucErr := registry.MergeStudents.Execute(ctx, writeStudent.ReqMergeStudents{
+			TargetID:  target,
+			SourceIDs: sources,
+		})
+		if ucErr != nil {
+			log.Fatalf("merge students fail: %v", ucErr)
+		}
+		log.Infof("merged %v into %s (%s)", sources, student.ID(), student.Name())
+		syncRecentMonthlyStats(ctx, registry.BatchSyncMonthlyStats, syncMonths)
+	},
+}
+
+// syncRecentMonthlyStats 月統計以孩子姓名分組，遷移後重新計算近幾個月
+func syncRecentMonthlyStats(ctx context.Context, uc writeStats.BatchSyncMonthlyStatsUseCase, months int) {
+	now := time.Now()
+	for i := 0; i < months; i++ {
+		t := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -i, 0)
+		if _, err := uc.Execute(ctx, writeStats.ReqBatchSyncMonthlyStats{
+			Year:  t.Year(),
+			Month: int(t.Month()),
+		}); err != nil {
+			log.Errorf("sync monthly stats %d-%02d fail: %v", t.Year(), int(t.Month()), err)
+		}
+	}
+}
+
+func initMigrateDB() (context.Context, func()) {
+	ctx := context.Background()
+	dbCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	err := mgo.InitConnection(
+		dbCtx,
+		viper.GetString("database.db"),
+		otel.Tracer("Mongodb"),
+		mgo.WithURI(viper.GetString("database.uri")),
+		mgo.WithMinPoolSize(viper.GetUint64("database.min_pool_size")),
+		mgo.WithMaxPoolSize(viper.GetUint64("database.max_pool_size")),
+	)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if err = mgo.SyncIndexes(dbCtx); err != nil {
+		log.Fatal(err.Error())
+	}
+	return ctx, func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := mgo.Close(closeCtx); err != nil {
+			log.Error(err.Error())
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateStudentsCmd)
+	migrateCmd.AddCommand(migrateMergeStudentsCmd)
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// migrateCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	migrateCmd.PersistentFlags().Int("sync-months", 3, "遷移後重新計算最近幾個月的月統計")
+	migrateStudentsCmd.Flags().Bool("dry-run", false, "只統計不寫入")
+	migrateMergeStudentsCmd.Flags().String("target", "", "保留的學員 ID")
+	migrateMergeStudentsCmd.Flags().StringSlice("sources", nil, "要併入的學員 ID，以逗號分隔")
+	_ = migrateMergeStudentsCmd.MarkFlagRequired("target")
+	_ = migrateMergeStudentsCmd.MarkFlagRequired("sources")
 }
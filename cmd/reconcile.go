@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/log"
+	"github.com/spf13/cobra"
+
+	writeTrain "seanAIgent/internal/booking/usecase/traindate/write"
+)
+
+// reconcileCmd represents the reconcile command
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "資料對帳工具",
+	Long:  `資料對帳工具，需與服務使用相同的設定檔 (database.*)。`,
+}
+
+// reconcileCapacityCmd 依實際預約重新計算場次剩餘名額
+var reconcileCapacityCmd = &cobra.Command{
+	Use:   "capacity",
+	Short: "依實際預約檢查場次剩餘名額",
+	Long: `以已預約、已出席、缺席 (含待審核請假) 的預約數重新計算剩餘名額，列出與記錄不一致的場次。
+加上 --fix 才會修正；修正時比對原本的剩餘名額，對帳期間有人預約的場次會略過，請再執行一次。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		days, _ := cmd.Flags().GetInt("days")
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		now := time.Now()
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		if from != "" {
+			t, err := time.ParseInLocation(time.DateOnly, from, time.Local)
+			if err != nil {
+				log.Fatalf("invalid --from: %v", err)
+			}
+			start = t
+		}
+		end := start.AddDate(0, 0, days)
+
+		ctx, closeDB := initMigrateDB()
+		defer closeDB()
+		registry, err := GetUseCaseRegistry()
+		if err != nil {
+			log.Fatalf("GetUseCaseRegistry fail: %v", err)
+		}
+
+		// 單次查詢有筆數上限，逐週對帳
+		checked, drifts, fixed := 0, 0, 0
+		for s := start; s.Before(end); s = s.AddDate(0, 0, 7) {
+			e := s.AddDate(0, 0, 7)
+			if e.After(end) {
+				e = end
+			}
+			resp, ucErr := registry.ReconcileCapacity.Execute(ctx, writeTrain.ReqReconcileCapacity{
+				Start: s,
+				End:   e,
+				Fix:   fix,
+			})
+			if ucErr != nil {
+				log.Fatalf("reconcile capacity %s fail: %v", s.Format(time.DateOnly), ucErr)
+			}
+			checked += resp.Checked
+			for _, d := range resp.Drifts {
+				drifts++
+				if d.Fixed {
+					fixed++
+				}
+				log.Warnf("train_date %s %s %s: capacity=%d recorded=%d expected=%d drift=%+d fixed=%v %s",
+					d.TrainDateID, d.StartDate.Format("2006-01-02 15:04"), d.Location,
+					d.Capacity, d.Recorded, d.Expected, d.Drift, d.Fixed, d.FixError)
+			}
+		}
+		log.Infof("checked=%d drift=%d fixed=%d fix=%v", checked, drifts, fixed, fix)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.AddCommand(reconcileCapacityCmd)
+
+	reconcileCapacityCmd.Flags().String("from", "", "起始日期 (YYYY-MM-DD)，預設今天")
+	reconcileCapacityCmd.Flags().Int("days", 60, "檢查的天數")
+	reconcileCapacityCmd.Flags().Bool("fix", false, "修正不一致的剩餘名額")
+}
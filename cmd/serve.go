@@ -5,9 +5,14 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/94peter/vulpes/log"
 	"github.com/spf13/cobra"
+
+	"seanAIgent/internal/booking/usecase"
 )
 
 // serveCmd represents the serve command
@@ -25,8 +30,24 @@ to quickly create a Cobra application.`,
 	},
 }
 
+// shutdownRegistry 在 web 與 relay 停止後呼叫，等待處理中的事件最多 shutdown-timeout，
+// 未完成的事件不更新進度，下次啟動時追趕
+func shutdownRegistry(cmd *cobra.Command, registry *usecase.Registry) {
+	timeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Info("Draining in-flight events...")
+	if err := registry.Close(ctx); err != nil {
+		log.Warnf("drain events fail, unfinished events will be caught up on next start: %v", err)
+		return
+	}
+	log.Info("In-flight events drained")
+}
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
+	serveCmd.PersistentFlags().Duration("shutdown-timeout", 30*time.Second, "關閉時等待處理中事件的時間")
 
 	// Here you will define your flags and configuration settings.
 
@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/booking/infra"
 	"seanAIgent/internal/booking/infra/db"
 	"seanAIgent/internal/booking/transport/web"
 	"seanAIgent/internal/booking/transport/web/handler"
@@ -31,9 +32,13 @@ func InitializeWeb() (web.WebService, error) {
 
 		// 2. 提供 Domain Service
 		service.NewTrainDateService,
+		infra.NewWebhookSender,
 
 		// 3. 提供包裝過的 UseCase 與 Registry
 		usecase.UseCaseSet,
+		ProvideBillingPolicy,
+		ProvideBookingPolicy,
+		ProvideArchiver,
 
 		// 4. 提供 API 需要的 UseCaseSet
 		handler.NewBookingUseCaseSet,
@@ -50,9 +55,13 @@ func InitializeWeb() (web.WebService, error) {
 func toolSet() []server.ServerTool {
 	return []server.ServerTool{
 		tool.ProvideCreateTrainingCoursesTool(),
+		tool.ProvideCreateTrainingSeriesTool(),
+		tool.ProvideUpdateTrainingSeriesTool(),
+		tool.ProvideDeleteTrainingSeriesTool(),
 		tool.ProvideDeleteLeaveByIdTool(),
 		tool.ProvideQueryLeaveByDateTool(),
 		tool.ProvideQueryTrainingByRangeTool(),
+		tool.ProvideQueryTrainingSeriesTool(),
 	}
 }
 
@@ -64,9 +73,13 @@ func InitializeMCP() (mcp.Server, error) {
 
 		// 2. 提供 Domain Service
 		service.NewTrainDateService,
+		infra.NewWebhookSender,
 
 		// 3. 提供包裝過的 UseCase 與 Registry
 		usecase.UseCaseSet,
+		ProvideBillingPolicy,
+		ProvideBookingPolicy,
+		ProvideArchiver,
 		toolSet,
 		// 4. 提供 MCP 需要的 UseCaseSet
 		mcp.InitMcpServer,
@@ -79,7 +92,11 @@ func GetUseCaseRegistry() (*usecase.Registry, error) {
 		ProvideDatabase,
 		db.InfraSet,
 		service.NewTrainDateService,
+		infra.NewWebhookSender,
 		usecase.UseCaseSet,
+		ProvideBillingPolicy,
+		ProvideBookingPolicy,
+		ProvideArchiver,
 	)
 	return nil, nil
 }
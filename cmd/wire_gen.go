@@ -11,6 +11,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/booking/infra"
 	"seanAIgent/internal/booking/infra/db"
 	"seanAIgent/internal/booking/transport/mcp"
 	"seanAIgent/internal/booking/transport/mcp/tool"
@@ -29,12 +30,16 @@ import (
 func InitializeWeb() (web.WebService, error) {
 	dbRepository := db.NewDbRepoAndIdGenerate()
 	trainDateService := service.NewTrainDateService(dbRepository)
+	webhookSender := infra.NewWebhookSender()
 	serviceAggregator := usecase.ServiceAggregator{
 		TrainDateService: trainDateService,
+		WebhookSender:    webhookSender,
 	}
-	writeUseCase := usecase.ProvideCreateTrainDateUC(dbRepository, serviceAggregator)
-	coreWriteUseCase := usecase.ProvideBatchCreateTrainDateUC(dbRepository, serviceAggregator)
+	bookingPolicy := ProvideBookingPolicy()
+	writeUseCase := usecase.ProvideCreateTrainDateUC(dbRepository, serviceAggregator, bookingPolicy)
+	coreWriteUseCase := usecase.ProvideBatchCreateTrainDateUC(dbRepository, serviceAggregator, bookingPolicy)
 	writeUseCase2 := usecase.ProvideDeleteTrainDateUC(dbRepository)
+	updateTrainDateBookingPolicyUseCase := usecase.ProvideUpdateTrainDateBookingPolicyUC(dbRepository)
 	readUseCase := usecase.ProvideQueryFutureTrainUC(dbRepository)
 	coreReadUseCase := usecase.ProvideFindNearestTrainByTimeUC(dbRepository)
 	readUseCase2 := usecase.ProvideFindTrainHasApptsByIdUC(dbRepository)
@@ -47,65 +52,193 @@ func InitializeWeb() (web.WebService, error) {
 	if err != nil {
 		return nil, err
 	}
-	bus := event.ProvideEventBus(eventStore)
-	createApptUseCase := usecase.ProvideCreateApptUC(dbRepository, bus)
-	adminCheckInUseCase := usecase.ProvideAdminCheckInUC(dbRepository, bus)
-	checkInUseCase := usecase.ProvideCheckInUC(adminCheckInUseCase)
-	cancelApptUseCase := usecase.ProvideCancelApptUC(dbRepository, bus)
-	createLeaveUseCase := usecase.ProvideCreateLeaveUC(dbRepository, bus)
-	cancelLeaveUseCase := usecase.ProvideCancelLeaveUC(dbRepository, bus)
-	adminToggleCheckInUseCase := usecase.ProvideAdminToggleCheckInUC(dbRepository, bus)
-	adminCreateLeaveUseCase := usecase.ProvideAdminCreateLeaveUC(dbRepository, bus)
-	adminRestoreFromLeaveUseCase := usecase.ProvideAdminRestoreFromLeaveUC(dbRepository, bus)
-	adminCreateWalkInUseCase := usecase.ProvideAdminCreateWalkInUC(dbRepository, bus)
+	deadLetterStore, err := event.ProvideDeadLetterStore(database)
+	if err != nil {
+		return nil, err
+	}
+	bus := event.ProvideEventBus(eventStore, deadLetterStore)
+	outbox, err := event.ProvideOutbox(database)
+	if err != nil {
+		return nil, err
+	}
+	outboxRelay := event.ProvideOutboxRelay(outbox, bus)
+	createApptUseCase := usecase.ProvideCreateApptUC(dbRepository)
+	adminCheckInUseCase := usecase.ProvideAdminCheckInUC(dbRepository)
+	checkInUseCase := usecase.ProvideCheckInUC(adminCheckInUseCase, dbRepository)
+	cancelApptUseCase := usecase.ProvideCancelApptUC(dbRepository)
+	createLeaveUseCase := usecase.ProvideCreateLeaveUC(dbRepository)
+	cancelLeaveUseCase := usecase.ProvideCancelLeaveUC(dbRepository)
+	updateLeaveReasonUseCase := usecase.ProvideUpdateLeaveReasonUC(dbRepository)
+	adminToggleCheckInUseCase := usecase.ProvideAdminToggleCheckInUC(dbRepository)
+	adminCreateLeaveUseCase := usecase.ProvideAdminCreateLeaveUC(dbRepository)
+	adminRestoreFromLeaveUseCase := usecase.ProvideAdminRestoreFromLeaveUC(dbRepository)
+	approveLeaveUseCase := usecase.ProvideApproveLeaveUC(dbRepository)
+	rejectLeaveUseCase := usecase.ProvideRejectLeaveUC(dbRepository)
+	adminCreateWalkInUseCase := usecase.ProvideAdminCreateWalkInUC(dbRepository)
 	adminQueryStudentsUseCase := usecase.ProvideAdminQueryStudentsUC(dbRepository)
-	autoMarkAbsentUseCase := usecase.ProvideAutoMarkAbsentUC(dbRepository, bus)
-	adminBatchUpdateAttendanceUseCase := usecase.ProvideAdminBatchUpdateAttendanceUC(dbRepository, bus)
+	autoMarkAbsentUseCase := usecase.ProvideAutoMarkAbsentUC(dbRepository)
+	adminBatchUpdateAttendanceUseCase := usecase.ProvideAdminBatchUpdateAttendanceUC(dbRepository)
 	readUseCase7 := usecase.ProvideQueryUserBookingsUC(dbRepository)
 	getUserMonthlyStatsUseCase := usecase.ProvideGetUserMonthlyStatsUC(dbRepository)
 	queryTwoWeeksScheduleUseCase := usecase.ProvideQueryTwoWeeksScheduleUC(dbRepository)
 	readUseCase8 := usecase.ProvideQueryAllUserApptStatsUC(dbRepository)
 	batchSyncMonthlyStatsUseCase := usecase.ProvideBatchSyncMonthlyStatsUC(dbRepository)
-	queryMonthlyUserReportsUseCase := usecase.ProvideQueryMonthlyUserReportsUC(dbRepository)
+	billingPolicy := ProvideBillingPolicy()
+	queryMonthlyUserReportsUseCase := usecase.ProvideQueryMonthlyUserReportsUC(dbRepository, billingPolicy)
 	getBusinessAnalyticsUseCase := usecase.ProvideGetBusinessAnalyticsUC(dbRepository)
 	getUserDetailUseCase := usecase.ProvideGetUserDetailUC(dbRepository)
-	v := usecase.ProvideSubscribers(dbRepository)
+	joinWaitlistUseCase := usecase.ProvideJoinWaitlistUC(dbRepository)
+	leaveWaitlistUseCase := usecase.ProvideLeaveWaitlistUC(dbRepository)
+	adminReorderWaitlistUseCase := usecase.ProvideAdminReorderWaitlistUC(dbRepository)
+	promoteWaitlistUseCase := usecase.ProvidePromoteWaitlistUC(dbRepository)
+	queryWaitlistUseCase := usecase.ProvideQueryWaitlistUC(dbRepository)
+	createTrainingSeriesUseCase := usecase.ProvideCreateTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	materializeTrainingSeriesUseCase := usecase.ProvideMaterializeTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	updateTrainingSeriesUseCase := usecase.ProvideUpdateTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	deleteTrainingSeriesUseCase := usecase.ProvideDeleteTrainingSeriesUC(dbRepository)
+	queryTrainingSeriesUseCase := usecase.ProvideQueryTrainingSeriesUC(dbRepository)
+	adminTopUpCreditsUseCase := usecase.ProvideAdminTopUpCreditsUC(dbRepository)
+	queryCreditLedgerUseCase := usecase.ProvideQueryCreditLedgerUC(dbRepository)
+	bookMakeUpUseCase := usecase.ProvideBookMakeUpUC(dbRepository)
+	queryMakeUpCreditsUseCase := usecase.ProvideQueryMakeUpCreditsUC(dbRepository)
+	recordPaymentUseCase := usecase.ProvideRecordPaymentUC(dbRepository)
+	createStudentUseCase := usecase.ProvideCreateStudentUC(dbRepository)
+	updateStudentUseCase := usecase.ProvideUpdateStudentUC(dbRepository)
+	deleteStudentUseCase := usecase.ProvideDeleteStudentUC(dbRepository)
+	mergeStudentsUseCase := usecase.ProvideMergeStudentsUC(dbRepository)
+	migrateChildNamesUseCase := usecase.ProvideMigrateChildNamesUC(dbRepository)
+	queryStudentsUseCase := usecase.ProvideQueryStudentsUC(dbRepository)
+	assignTrainDateTeamUseCase := usecase.ProvideAssignTrainDateTeamUC(dbRepository)
+	cancelTrainDateUseCase := usecase.ProvideCancelTrainDateUC(dbRepository)
+	rescheduleTrainDateUseCase := usecase.ProvideRescheduleTrainDateUC(dbRepository, serviceAggregator)
+	reconcileCapacityUseCase := usecase.ProvideReconcileCapacityUC(dbRepository)
+	createTeamUseCase := usecase.ProvideCreateTeamUC(dbRepository)
+	updateTeamUseCase := usecase.ProvideUpdateTeamUC(dbRepository)
+	updateTeamMembersUseCase := usecase.ProvideUpdateTeamMembersUC(dbRepository)
+	deleteTeamUseCase := usecase.ProvideDeleteTeamUC(dbRepository)
+	queryTeamsUseCase := usecase.ProvideQueryTeamsUC(dbRepository)
+	queryTeamMembersUseCase := usecase.ProvideQueryTeamMembersUC(dbRepository)
+	assignUserRolesUseCase := usecase.ProvideAssignUserRolesUC(dbRepository)
+	removeUserRolesUseCase := usecase.ProvideRemoveUserRolesUC(dbRepository)
+	queryUserRolesUseCase := usecase.ProvideQueryUserRolesUC(dbRepository)
+	resolveActorUseCase := usecase.ProvideResolveActorUC(dbRepository)
+	applyApptCreditUseCase := usecase.ProvideApplyApptCreditUC(dbRepository)
+	settleCreditLedgerUseCase := usecase.ProvideSettleCreditLedgerUC(dbRepository)
+	syncMakeUpCreditUseCase := usecase.ProvideSyncMakeUpCreditUC(dbRepository)
+	v := usecase.ProvideSubscribers(dbRepository, serviceAggregator, promoteWaitlistUseCase, applyApptCreditUseCase, settleCreditLedgerUseCase, syncMakeUpCreditUseCase)
+	queryDeadLettersUseCase := usecase.ProvideQueryDeadLettersUC(deadLetterStore)
+	getDeadLetterUseCase := usecase.ProvideGetDeadLetterUC(deadLetterStore)
+	replayDeadLetterUseCase := usecase.ProvideReplayDeadLetterUC(deadLetterStore, v)
+	discardDeadLetterUseCase := usecase.ProvideDiscardDeadLetterUC(deadLetterStore)
+	eventLog := event.ProvideEventLog(eventStore)
+	replayEventsUseCase := usecase.ProvideReplayEventsUC(eventLog, deadLetterStore, v)
+	archiver, err := ProvideArchiver(eventLog, database)
+	if err != nil {
+		return nil, err
+	}
+	archiveEventsUseCase := usecase.ProvideArchiveEventsUC(archiver)
+	redispatchEventUseCase := usecase.ProvideRedispatchEventUC(eventLog, deadLetterStore, v)
+	queryEventsUseCase := usecase.ProvideQueryEventsUC(eventLog)
+	getEventUseCase := usecase.ProvideGetEventUC(eventLog)
+	querySubscriberProgressUseCase := usecase.ProvideQuerySubscriberProgressUC(eventLog, v)
+	createWebhookEndpointUseCase := usecase.ProvideCreateWebhookEndpointUC(dbRepository)
+	updateWebhookEndpointUseCase := usecase.ProvideUpdateWebhookEndpointUC(dbRepository)
+	deleteWebhookEndpointUseCase := usecase.ProvideDeleteWebhookEndpointUC(dbRepository)
+	pingWebhookEndpointUseCase := usecase.ProvidePingWebhookEndpointUC(dbRepository, serviceAggregator)
+	queryWebhookEndpointsUseCase := usecase.ProvideQueryWebhookEndpointsUC(dbRepository)
+	queryWebhookDeliveriesUseCase := usecase.ProvideQueryWebhookDeliveriesUC(dbRepository)
 	idempotencyManager := usecase.ProvideIdempotencyManager()
 	registry := &usecase.Registry{
-		CreateTrainDate:            writeUseCase,
-		BatchCreateTrainDate:       coreWriteUseCase,
-		DeleteTrainDate:            writeUseCase2,
-		QueryFutureTrain:           readUseCase,
-		FindNearestTrainByTime:     coreReadUseCase,
-		FindTrainHasApptsById:      readUseCase2,
-		UserQueryFutureTrain:       readUseCase3,
-		UserQueryTrainByID:         readUseCase4,
-		AdminQueryTrainRange:       readUseCase5,
-		AdminQueryRecentTrain:      readUseCase6,
-		CreateAppt:                 createApptUseCase,
-		CheckIn:                    checkInUseCase,
-		CancelAppt:                 cancelApptUseCase,
-		CreateLeave:                createLeaveUseCase,
-		CancelLeave:                cancelLeaveUseCase,
-		AdminCheckIn:               adminCheckInUseCase,
-		AdminToggleCheckIn:         adminToggleCheckInUseCase,
-		AdminCreateLeave:           adminCreateLeaveUseCase,
-		AdminRestoreFromLeave:      adminRestoreFromLeaveUseCase,
-		AdminCreateWalkIn:          adminCreateWalkInUseCase,
-		AdminQueryStudents:         adminQueryStudentsUseCase,
-		AutoMarkAbsent:             autoMarkAbsentUseCase,
-		AdminBatchUpdateAttendance: adminBatchUpdateAttendanceUseCase,
-		QueryUserBookings:          readUseCase7,
-		GetUserMonthlyStats:        getUserMonthlyStatsUseCase,
-		QueryTwoWeeksSchedule:      queryTwoWeeksScheduleUseCase,
-		QueryAllUserApptStats:      readUseCase8,
-		BatchSyncMonthlyStats:      batchSyncMonthlyStatsUseCase,
-		QueryMonthlyUserReports:    queryMonthlyUserReportsUseCase,
-		GetBusinessAnalytics:       getBusinessAnalyticsUseCase,
-		GetUserDetail:              getUserDetailUseCase,
-		Bus:                        bus,
-		Subscribers:                v,
-		IdempotencyManager:         idempotencyManager,
+		CreateTrainDate:              writeUseCase,
+		BatchCreateTrainDate:         coreWriteUseCase,
+		DeleteTrainDate:              writeUseCase2,
+		UpdateTrainDateBookingPolicy: updateTrainDateBookingPolicyUseCase,
+		AssignTrainDateTeam:          assignTrainDateTeamUseCase,
+		CancelTrainDate:              cancelTrainDateUseCase,
+		RescheduleTrainDate:          rescheduleTrainDateUseCase,
+		ReconcileCapacity:            reconcileCapacityUseCase,
+		QueryFutureTrain:             readUseCase,
+		FindNearestTrainByTime:       coreReadUseCase,
+		FindTrainHasApptsById:        readUseCase2,
+		UserQueryFutureTrain:         readUseCase3,
+		UserQueryTrainByID:           readUseCase4,
+		AdminQueryTrainRange:         readUseCase5,
+		AdminQueryRecentTrain:        readUseCase6,
+		CreateAppt:                   createApptUseCase,
+		CheckIn:                      checkInUseCase,
+		CancelAppt:                   cancelApptUseCase,
+		CreateLeave:                  createLeaveUseCase,
+		CancelLeave:                  cancelLeaveUseCase,
+		UpdateLeaveReason:            updateLeaveReasonUseCase,
+		AdminCheckIn:                 adminCheckInUseCase,
+		AdminToggleCheckIn:           adminToggleCheckInUseCase,
+		AdminCreateLeave:             adminCreateLeaveUseCase,
+		AdminRestoreFromLeave:        adminRestoreFromLeaveUseCase,
+		ApproveLeave:                 approveLeaveUseCase,
+		RejectLeave:                  rejectLeaveUseCase,
+		AdminCreateWalkIn:            adminCreateWalkInUseCase,
+		AdminQueryStudents:           adminQueryStudentsUseCase,
+		AutoMarkAbsent:               autoMarkAbsentUseCase,
+		AdminBatchUpdateAttendance:   adminBatchUpdateAttendanceUseCase,
+		QueryUserBookings:            readUseCase7,
+		GetUserMonthlyStats:          getUserMonthlyStatsUseCase,
+		QueryTwoWeeksSchedule:        queryTwoWeeksScheduleUseCase,
+		QueryAllUserApptStats:        readUseCase8,
+		BatchSyncMonthlyStats:        batchSyncMonthlyStatsUseCase,
+		QueryMonthlyUserReports:      queryMonthlyUserReportsUseCase,
+		GetBusinessAnalytics:         getBusinessAnalyticsUseCase,
+		GetUserDetail:                getUserDetailUseCase,
+		JoinWaitlist:                 joinWaitlistUseCase,
+		LeaveWaitlist:                leaveWaitlistUseCase,
+		AdminReorderWaitlist:         adminReorderWaitlistUseCase,
+		PromoteWaitlist:              promoteWaitlistUseCase,
+		QueryWaitlist:                queryWaitlistUseCase,
+		CreateTrainingSeries:         createTrainingSeriesUseCase,
+		MaterializeTrainingSeries:    materializeTrainingSeriesUseCase,
+		UpdateTrainingSeries:         updateTrainingSeriesUseCase,
+		DeleteTrainingSeries:         deleteTrainingSeriesUseCase,
+		QueryTrainingSeries:          queryTrainingSeriesUseCase,
+		AdminTopUpCredits:            adminTopUpCreditsUseCase,
+		QueryCreditLedger:            queryCreditLedgerUseCase,
+		BookMakeUp:                   bookMakeUpUseCase,
+		QueryMakeUpCredits:           queryMakeUpCreditsUseCase,
+		RecordPayment:                recordPaymentUseCase,
+		CreateStudent:                createStudentUseCase,
+		UpdateStudent:                updateStudentUseCase,
+		DeleteStudent:                deleteStudentUseCase,
+		MergeStudents:                mergeStudentsUseCase,
+		MigrateChildNames:            migrateChildNamesUseCase,
+		QueryStudents:                queryStudentsUseCase,
+		CreateTeam:                   createTeamUseCase,
+		UpdateTeam:                   updateTeamUseCase,
+		UpdateTeamMembers:            updateTeamMembersUseCase,
+		DeleteTeam:                   deleteTeamUseCase,
+		QueryTeams:                   queryTeamsUseCase,
+		QueryTeamMembers:             queryTeamMembersUseCase,
+		AssignUserRoles:              assignUserRolesUseCase,
+		RemoveUserRoles:              removeUserRolesUseCase,
+		QueryUserRoles:               queryUserRolesUseCase,
+		ResolveActor:                 resolveActorUseCase,
+		QueryDeadLetters:             queryDeadLettersUseCase,
+		GetDeadLetter:                getDeadLetterUseCase,
+		ReplayDeadLetter:             replayDeadLetterUseCase,
+		DiscardDeadLetter:            discardDeadLetterUseCase,
+		ReplayEvents:                 replayEventsUseCase,
+		ArchiveEvents:                archiveEventsUseCase,
+		QueryEvents:                  queryEventsUseCase,
+		GetEvent:                     getEventUseCase,
+		QuerySubscriberProgress:      querySubscriberProgressUseCase,
+		RedispatchEvent:              redispatchEventUseCase,
+		CreateWebhookEndpoint:        createWebhookEndpointUseCase,
+		UpdateWebhookEndpoint:        updateWebhookEndpointUseCase,
+		DeleteWebhookEndpoint:        deleteWebhookEndpointUseCase,
+		PingWebhookEndpoint:          pingWebhookEndpointUseCase,
+		QueryWebhookEndpoints:        queryWebhookEndpointsUseCase,
+		QueryWebhookDeliveries:       queryWebhookDeliveriesUseCase,
+		Bus:                          bus,
+		Subscribers:                  v,
+		Relay:                        outboxRelay,
+		IdempotencyManager:           idempotencyManager,
 	}
 	bookingUseCaseSet := handler.NewBookingUseCaseSet(registry)
 	trainingUseCaseSet := handler.NewTrainingUseCaseSet(registry)
@@ -118,12 +251,16 @@ func InitializeWeb() (web.WebService, error) {
 func InitializeMCP() (mcp.Server, error) {
 	dbRepository := db.NewDbRepoAndIdGenerate()
 	trainDateService := service.NewTrainDateService(dbRepository)
+	webhookSender := infra.NewWebhookSender()
 	serviceAggregator := usecase.ServiceAggregator{
 		TrainDateService: trainDateService,
+		WebhookSender:    webhookSender,
 	}
-	writeUseCase := usecase.ProvideCreateTrainDateUC(dbRepository, serviceAggregator)
-	coreWriteUseCase := usecase.ProvideBatchCreateTrainDateUC(dbRepository, serviceAggregator)
+	bookingPolicy := ProvideBookingPolicy()
+	writeUseCase := usecase.ProvideCreateTrainDateUC(dbRepository, serviceAggregator, bookingPolicy)
+	coreWriteUseCase := usecase.ProvideBatchCreateTrainDateUC(dbRepository, serviceAggregator, bookingPolicy)
 	writeUseCase2 := usecase.ProvideDeleteTrainDateUC(dbRepository)
+	updateTrainDateBookingPolicyUseCase := usecase.ProvideUpdateTrainDateBookingPolicyUC(dbRepository)
 	readUseCase := usecase.ProvideQueryFutureTrainUC(dbRepository)
 	coreReadUseCase := usecase.ProvideFindNearestTrainByTimeUC(dbRepository)
 	readUseCase2 := usecase.ProvideFindTrainHasApptsByIdUC(dbRepository)
@@ -136,65 +273,193 @@ func InitializeMCP() (mcp.Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	bus := event.ProvideEventBus(eventStore)
-	createApptUseCase := usecase.ProvideCreateApptUC(dbRepository, bus)
-	adminCheckInUseCase := usecase.ProvideAdminCheckInUC(dbRepository, bus)
-	checkInUseCase := usecase.ProvideCheckInUC(adminCheckInUseCase)
-	cancelApptUseCase := usecase.ProvideCancelApptUC(dbRepository, bus)
-	createLeaveUseCase := usecase.ProvideCreateLeaveUC(dbRepository, bus)
-	cancelLeaveUseCase := usecase.ProvideCancelLeaveUC(dbRepository, bus)
-	adminToggleCheckInUseCase := usecase.ProvideAdminToggleCheckInUC(dbRepository, bus)
-	adminCreateLeaveUseCase := usecase.ProvideAdminCreateLeaveUC(dbRepository, bus)
-	adminRestoreFromLeaveUseCase := usecase.ProvideAdminRestoreFromLeaveUC(dbRepository, bus)
-	adminCreateWalkInUseCase := usecase.ProvideAdminCreateWalkInUC(dbRepository, bus)
+	deadLetterStore, err := event.ProvideDeadLetterStore(database)
+	if err != nil {
+		return nil, err
+	}
+	bus := event.ProvideEventBus(eventStore, deadLetterStore)
+	outbox, err := event.ProvideOutbox(database)
+	if err != nil {
+		return nil, err
+	}
+	outboxRelay := event.ProvideOutboxRelay(outbox, bus)
+	createApptUseCase := usecase.ProvideCreateApptUC(dbRepository)
+	adminCheckInUseCase := usecase.ProvideAdminCheckInUC(dbRepository)
+	checkInUseCase := usecase.ProvideCheckInUC(adminCheckInUseCase, dbRepository)
+	cancelApptUseCase := usecase.ProvideCancelApptUC(dbRepository)
+	createLeaveUseCase := usecase.ProvideCreateLeaveUC(dbRepository)
+	cancelLeaveUseCase := usecase.ProvideCancelLeaveUC(dbRepository)
+	updateLeaveReasonUseCase := usecase.ProvideUpdateLeaveReasonUC(dbRepository)
+	adminToggleCheckInUseCase := usecase.ProvideAdminToggleCheckInUC(dbRepository)
+	adminCreateLeaveUseCase := usecase.ProvideAdminCreateLeaveUC(dbRepository)
+	adminRestoreFromLeaveUseCase := usecase.ProvideAdminRestoreFromLeaveUC(dbRepository)
+	approveLeaveUseCase := usecase.ProvideApproveLeaveUC(dbRepository)
+	rejectLeaveUseCase := usecase.ProvideRejectLeaveUC(dbRepository)
+	adminCreateWalkInUseCase := usecase.ProvideAdminCreateWalkInUC(dbRepository)
 	adminQueryStudentsUseCase := usecase.ProvideAdminQueryStudentsUC(dbRepository)
-	autoMarkAbsentUseCase := usecase.ProvideAutoMarkAbsentUC(dbRepository, bus)
-	adminBatchUpdateAttendanceUseCase := usecase.ProvideAdminBatchUpdateAttendanceUC(dbRepository, bus)
+	autoMarkAbsentUseCase := usecase.ProvideAutoMarkAbsentUC(dbRepository)
+	adminBatchUpdateAttendanceUseCase := usecase.ProvideAdminBatchUpdateAttendanceUC(dbRepository)
 	readUseCase7 := usecase.ProvideQueryUserBookingsUC(dbRepository)
 	getUserMonthlyStatsUseCase := usecase.ProvideGetUserMonthlyStatsUC(dbRepository)
 	queryTwoWeeksScheduleUseCase := usecase.ProvideQueryTwoWeeksScheduleUC(dbRepository)
 	readUseCase8 := usecase.ProvideQueryAllUserApptStatsUC(dbRepository)
 	batchSyncMonthlyStatsUseCase := usecase.ProvideBatchSyncMonthlyStatsUC(dbRepository)
-	queryMonthlyUserReportsUseCase := usecase.ProvideQueryMonthlyUserReportsUC(dbRepository)
+	billingPolicy := ProvideBillingPolicy()
+	queryMonthlyUserReportsUseCase := usecase.ProvideQueryMonthlyUserReportsUC(dbRepository, billingPolicy)
 	getBusinessAnalyticsUseCase := usecase.ProvideGetBusinessAnalyticsUC(dbRepository)
 	getUserDetailUseCase := usecase.ProvideGetUserDetailUC(dbRepository)
-	v := usecase.ProvideSubscribers(dbRepository)
+	joinWaitlistUseCase := usecase.ProvideJoinWaitlistUC(dbRepository)
+	leaveWaitlistUseCase := usecase.ProvideLeaveWaitlistUC(dbRepository)
+	adminReorderWaitlistUseCase := usecase.ProvideAdminReorderWaitlistUC(dbRepository)
+	promoteWaitlistUseCase := usecase.ProvidePromoteWaitlistUC(dbRepository)
+	queryWaitlistUseCase := usecase.ProvideQueryWaitlistUC(dbRepository)
+	createTrainingSeriesUseCase := usecase.ProvideCreateTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	materializeTrainingSeriesUseCase := usecase.ProvideMaterializeTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	updateTrainingSeriesUseCase := usecase.ProvideUpdateTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	deleteTrainingSeriesUseCase := usecase.ProvideDeleteTrainingSeriesUC(dbRepository)
+	queryTrainingSeriesUseCase := usecase.ProvideQueryTrainingSeriesUC(dbRepository)
+	adminTopUpCreditsUseCase := usecase.ProvideAdminTopUpCreditsUC(dbRepository)
+	queryCreditLedgerUseCase := usecase.ProvideQueryCreditLedgerUC(dbRepository)
+	bookMakeUpUseCase := usecase.ProvideBookMakeUpUC(dbRepository)
+	queryMakeUpCreditsUseCase := usecase.ProvideQueryMakeUpCreditsUC(dbRepository)
+	recordPaymentUseCase := usecase.ProvideRecordPaymentUC(dbRepository)
+	createStudentUseCase := usecase.ProvideCreateStudentUC(dbRepository)
+	updateStudentUseCase := usecase.ProvideUpdateStudentUC(dbRepository)
+	deleteStudentUseCase := usecase.ProvideDeleteStudentUC(dbRepository)
+	mergeStudentsUseCase := usecase.ProvideMergeStudentsUC(dbRepository)
+	migrateChildNamesUseCase := usecase.ProvideMigrateChildNamesUC(dbRepository)
+	queryStudentsUseCase := usecase.ProvideQueryStudentsUC(dbRepository)
+	assignTrainDateTeamUseCase := usecase.ProvideAssignTrainDateTeamUC(dbRepository)
+	cancelTrainDateUseCase := usecase.ProvideCancelTrainDateUC(dbRepository)
+	rescheduleTrainDateUseCase := usecase.ProvideRescheduleTrainDateUC(dbRepository, serviceAggregator)
+	reconcileCapacityUseCase := usecase.ProvideReconcileCapacityUC(dbRepository)
+	createTeamUseCase := usecase.ProvideCreateTeamUC(dbRepository)
+	updateTeamUseCase := usecase.ProvideUpdateTeamUC(dbRepository)
+	updateTeamMembersUseCase := usecase.ProvideUpdateTeamMembersUC(dbRepository)
+	deleteTeamUseCase := usecase.ProvideDeleteTeamUC(dbRepository)
+	queryTeamsUseCase := usecase.ProvideQueryTeamsUC(dbRepository)
+	queryTeamMembersUseCase := usecase.ProvideQueryTeamMembersUC(dbRepository)
+	assignUserRolesUseCase := usecase.ProvideAssignUserRolesUC(dbRepository)
+	removeUserRolesUseCase := usecase.ProvideRemoveUserRolesUC(dbRepository)
+	queryUserRolesUseCase := usecase.ProvideQueryUserRolesUC(dbRepository)
+	resolveActorUseCase := usecase.ProvideResolveActorUC(dbRepository)
+	applyApptCreditUseCase := usecase.ProvideApplyApptCreditUC(dbRepository)
+	settleCreditLedgerUseCase := usecase.ProvideSettleCreditLedgerUC(dbRepository)
+	syncMakeUpCreditUseCase := usecase.ProvideSyncMakeUpCreditUC(dbRepository)
+	v := usecase.ProvideSubscribers(dbRepository, serviceAggregator, promoteWaitlistUseCase, applyApptCreditUseCase, settleCreditLedgerUseCase, syncMakeUpCreditUseCase)
+	queryDeadLettersUseCase := usecase.ProvideQueryDeadLettersUC(deadLetterStore)
+	getDeadLetterUseCase := usecase.ProvideGetDeadLetterUC(deadLetterStore)
+	replayDeadLetterUseCase := usecase.ProvideReplayDeadLetterUC(deadLetterStore, v)
+	discardDeadLetterUseCase := usecase.ProvideDiscardDeadLetterUC(deadLetterStore)
+	eventLog := event.ProvideEventLog(eventStore)
+	replayEventsUseCase := usecase.ProvideReplayEventsUC(eventLog, deadLetterStore, v)
+	archiver, err := ProvideArchiver(eventLog, database)
+	if err != nil {
+		return nil, err
+	}
+	archiveEventsUseCase := usecase.ProvideArchiveEventsUC(archiver)
+	redispatchEventUseCase := usecase.ProvideRedispatchEventUC(eventLog, deadLetterStore, v)
+	queryEventsUseCase := usecase.ProvideQueryEventsUC(eventLog)
+	getEventUseCase := usecase.ProvideGetEventUC(eventLog)
+	querySubscriberProgressUseCase := usecase.ProvideQuerySubscriberProgressUC(eventLog, v)
+	createWebhookEndpointUseCase := usecase.ProvideCreateWebhookEndpointUC(dbRepository)
+	updateWebhookEndpointUseCase := usecase.ProvideUpdateWebhookEndpointUC(dbRepository)
+	deleteWebhookEndpointUseCase := usecase.ProvideDeleteWebhookEndpointUC(dbRepository)
+	pingWebhookEndpointUseCase := usecase.ProvidePingWebhookEndpointUC(dbRepository, serviceAggregator)
+	queryWebhookEndpointsUseCase := usecase.ProvideQueryWebhookEndpointsUC(dbRepository)
+	queryWebhookDeliveriesUseCase := usecase.ProvideQueryWebhookDeliveriesUC(dbRepository)
 	idempotencyManager := usecase.ProvideIdempotencyManager()
 	registry := &usecase.Registry{
-		CreateTrainDate:            writeUseCase,
-		BatchCreateTrainDate:       coreWriteUseCase,
-		DeleteTrainDate:            writeUseCase2,
-		QueryFutureTrain:           readUseCase,
-		FindNearestTrainByTime:     coreReadUseCase,
-		FindTrainHasApptsById:      readUseCase2,
-		UserQueryFutureTrain:       readUseCase3,
-		UserQueryTrainByID:         readUseCase4,
-		AdminQueryTrainRange:       readUseCase5,
-		AdminQueryRecentTrain:      readUseCase6,
-		CreateAppt:                 createApptUseCase,
-		CheckIn:                    checkInUseCase,
-		CancelAppt:                 cancelApptUseCase,
-		CreateLeave:                createLeaveUseCase,
-		CancelLeave:                cancelLeaveUseCase,
-		AdminCheckIn:               adminCheckInUseCase,
-		AdminToggleCheckIn:         adminToggleCheckInUseCase,
-		AdminCreateLeave:           adminCreateLeaveUseCase,
-		AdminRestoreFromLeave:      adminRestoreFromLeaveUseCase,
-		AdminCreateWalkIn:          adminCreateWalkInUseCase,
-		AdminQueryStudents:         adminQueryStudentsUseCase,
-		AutoMarkAbsent:             autoMarkAbsentUseCase,
-		AdminBatchUpdateAttendance: adminBatchUpdateAttendanceUseCase,
-		QueryUserBookings:          readUseCase7,
-		GetUserMonthlyStats:        getUserMonthlyStatsUseCase,
-		QueryTwoWeeksSchedule:      queryTwoWeeksScheduleUseCase,
-		QueryAllUserApptStats:      readUseCase8,
-		BatchSyncMonthlyStats:      batchSyncMonthlyStatsUseCase,
-		QueryMonthlyUserReports:    queryMonthlyUserReportsUseCase,
-		GetBusinessAnalytics:       getBusinessAnalyticsUseCase,
-		GetUserDetail:              getUserDetailUseCase,
-		Bus:                        bus,
-		Subscribers:                v,
-		IdempotencyManager:         idempotencyManager,
+		CreateTrainDate:              writeUseCase,
+		BatchCreateTrainDate:         coreWriteUseCase,
+		DeleteTrainDate:              writeUseCase2,
+		UpdateTrainDateBookingPolicy: updateTrainDateBookingPolicyUseCase,
+		AssignTrainDateTeam:          assignTrainDateTeamUseCase,
+		CancelTrainDate:              cancelTrainDateUseCase,
+		RescheduleTrainDate:          rescheduleTrainDateUseCase,
+		ReconcileCapacity:            reconcileCapacityUseCase,
+		QueryFutureTrain:             readUseCase,
+		FindNearestTrainByTime:       coreReadUseCase,
+		FindTrainHasApptsById:        readUseCase2,
+		UserQueryFutureTrain:         readUseCase3,
+		UserQueryTrainByID:           readUseCase4,
+		AdminQueryTrainRange:         readUseCase5,
+		AdminQueryRecentTrain:        readUseCase6,
+		CreateAppt:                   createApptUseCase,
+		CheckIn:                      checkInUseCase,
+		CancelAppt:                   cancelApptUseCase,
+		CreateLeave:                  createLeaveUseCase,
+		CancelLeave:                  cancelLeaveUseCase,
+		UpdateLeaveReason:            updateLeaveReasonUseCase,
+		AdminCheckIn:                 adminCheckInUseCase,
+		AdminToggleCheckIn:           adminToggleCheckInUseCase,
+		AdminCreateLeave:             adminCreateLeaveUseCase,
+		AdminRestoreFromLeave:        adminRestoreFromLeaveUseCase,
+		ApproveLeave:                 approveLeaveUseCase,
+		RejectLeave:                  rejectLeaveUseCase,
+		AdminCreateWalkIn:            adminCreateWalkInUseCase,
+		AdminQueryStudents:           adminQueryStudentsUseCase,
+		AutoMarkAbsent:               autoMarkAbsentUseCase,
+		AdminBatchUpdateAttendance:   adminBatchUpdateAttendanceUseCase,
+		QueryUserBookings:            readUseCase7,
+		GetUserMonthlyStats:          getUserMonthlyStatsUseCase,
+		QueryTwoWeeksSchedule:        queryTwoWeeksScheduleUseCase,
+		QueryAllUserApptStats:        readUseCase8,
+		BatchSyncMonthlyStats:        batchSyncMonthlyStatsUseCase,
+		QueryMonthlyUserReports:      queryMonthlyUserReportsUseCase,
+		GetBusinessAnalytics:         getBusinessAnalyticsUseCase,
+		GetUserDetail:                getUserDetailUseCase,
+		JoinWaitlist:                 joinWaitlistUseCase,
+		LeaveWaitlist:                leaveWaitlistUseCase,
+		AdminReorderWaitlist:         adminReorderWaitlistUseCase,
+		PromoteWaitlist:              promoteWaitlistUseCase,
+		QueryWaitlist:                queryWaitlistUseCase,
+		CreateTrainingSeries:         createTrainingSeriesUseCase,
+		MaterializeTrainingSeries:    materializeTrainingSeriesUseCase,
+		UpdateTrainingSeries:         updateTrainingSeriesUseCase,
+		DeleteTrainingSeries:         deleteTrainingSeriesUseCase,
+		QueryTrainingSeries:          queryTrainingSeriesUseCase,
+		AdminTopUpCredits:            adminTopUpCreditsUseCase,
+		QueryCreditLedger:            queryCreditLedgerUseCase,
+		BookMakeUp:                   bookMakeUpUseCase,
+		QueryMakeUpCredits:           queryMakeUpCreditsUseCase,
+		RecordPayment:                recordPaymentUseCase,
+		CreateStudent:                createStudentUseCase,
+		UpdateStudent:                updateStudentUseCase,
+		DeleteStudent:                deleteStudentUseCase,
+		MergeStudents:                mergeStudentsUseCase,
+		MigrateChildNames:            migrateChildNamesUseCase,
+		QueryStudents:                queryStudentsUseCase,
+		CreateTeam:                   createTeamUseCase,
+		UpdateTeam:                   updateTeamUseCase,
+		UpdateTeamMembers:            updateTeamMembersUseCase,
+		DeleteTeam:                   deleteTeamUseCase,
+		QueryTeams:                   queryTeamsUseCase,
+		QueryTeamMembers:             queryTeamMembersUseCase,
+		AssignUserRoles:              assignUserRolesUseCase,
+		RemoveUserRoles:              removeUserRolesUseCase,
+		QueryUserRoles:               queryUserRolesUseCase,
+		ResolveActor:                 resolveActorUseCase,
+		QueryDeadLetters:             queryDeadLettersUseCase,
+		GetDeadLetter:                getDeadLetterUseCase,
+		ReplayDeadLetter:             replayDeadLetterUseCase,
+		DiscardDeadLetter:            discardDeadLetterUseCase,
+		ReplayEvents:                 replayEventsUseCase,
+		ArchiveEvents:                archiveEventsUseCase,
+		QueryEvents:                  queryEventsUseCase,
+		GetEvent:                     getEventUseCase,
+		QuerySubscriberProgress:      querySubscriberProgressUseCase,
+		RedispatchEvent:              redispatchEventUseCase,
+		CreateWebhookEndpoint:        createWebhookEndpointUseCase,
+		UpdateWebhookEndpoint:        updateWebhookEndpointUseCase,
+		DeleteWebhookEndpoint:        deleteWebhookEndpointUseCase,
+		PingWebhookEndpoint:          pingWebhookEndpointUseCase,
+		QueryWebhookEndpoints:        queryWebhookEndpointsUseCase,
+		QueryWebhookDeliveries:       queryWebhookDeliveriesUseCase,
+		Bus:                          bus,
+		Subscribers:                  v,
+		Relay:                        outboxRelay,
+		IdempotencyManager:           idempotencyManager,
 	}
 	v2 := toolSet()
 	server := mcp.InitMcpServer(registry, v2)
@@ -204,12 +469,16 @@ func InitializeMCP() (mcp.Server, error) {
 func GetUseCaseRegistry() (*usecase.Registry, error) {
 	dbRepository := db.NewDbRepoAndIdGenerate()
 	trainDateService := service.NewTrainDateService(dbRepository)
+	webhookSender := infra.NewWebhookSender()
 	serviceAggregator := usecase.ServiceAggregator{
 		TrainDateService: trainDateService,
+		WebhookSender:    webhookSender,
 	}
-	writeUseCase := usecase.ProvideCreateTrainDateUC(dbRepository, serviceAggregator)
-	coreWriteUseCase := usecase.ProvideBatchCreateTrainDateUC(dbRepository, serviceAggregator)
+	bookingPolicy := ProvideBookingPolicy()
+	writeUseCase := usecase.ProvideCreateTrainDateUC(dbRepository, serviceAggregator, bookingPolicy)
+	coreWriteUseCase := usecase.ProvideBatchCreateTrainDateUC(dbRepository, serviceAggregator, bookingPolicy)
 	writeUseCase2 := usecase.ProvideDeleteTrainDateUC(dbRepository)
+	updateTrainDateBookingPolicyUseCase := usecase.ProvideUpdateTrainDateBookingPolicyUC(dbRepository)
 	readUseCase := usecase.ProvideQueryFutureTrainUC(dbRepository)
 	coreReadUseCase := usecase.ProvideFindNearestTrainByTimeUC(dbRepository)
 	readUseCase2 := usecase.ProvideFindTrainHasApptsByIdUC(dbRepository)
@@ -222,65 +491,193 @@ func GetUseCaseRegistry() (*usecase.Registry, error) {
 	if err != nil {
 		return nil, err
 	}
-	bus := event.ProvideEventBus(eventStore)
-	createApptUseCase := usecase.ProvideCreateApptUC(dbRepository, bus)
-	adminCheckInUseCase := usecase.ProvideAdminCheckInUC(dbRepository, bus)
-	checkInUseCase := usecase.ProvideCheckInUC(adminCheckInUseCase)
-	cancelApptUseCase := usecase.ProvideCancelApptUC(dbRepository, bus)
-	createLeaveUseCase := usecase.ProvideCreateLeaveUC(dbRepository, bus)
-	cancelLeaveUseCase := usecase.ProvideCancelLeaveUC(dbRepository, bus)
-	adminToggleCheckInUseCase := usecase.ProvideAdminToggleCheckInUC(dbRepository, bus)
-	adminCreateLeaveUseCase := usecase.ProvideAdminCreateLeaveUC(dbRepository, bus)
-	adminRestoreFromLeaveUseCase := usecase.ProvideAdminRestoreFromLeaveUC(dbRepository, bus)
-	adminCreateWalkInUseCase := usecase.ProvideAdminCreateWalkInUC(dbRepository, bus)
+	deadLetterStore, err := event.ProvideDeadLetterStore(database)
+	if err != nil {
+		return nil, err
+	}
+	bus := event.ProvideEventBus(eventStore, deadLetterStore)
+	outbox, err := event.ProvideOutbox(database)
+	if err != nil {
+		return nil, err
+	}
+	outboxRelay := event.ProvideOutboxRelay(outbox, bus)
+	createApptUseCase := usecase.ProvideCreateApptUC(dbRepository)
+	adminCheckInUseCase := usecase.ProvideAdminCheckInUC(dbRepository)
+	checkInUseCase := usecase.ProvideCheckInUC(adminCheckInUseCase, dbRepository)
+	cancelApptUseCase := usecase.ProvideCancelApptUC(dbRepository)
+	createLeaveUseCase := usecase.ProvideCreateLeaveUC(dbRepository)
+	cancelLeaveUseCase := usecase.ProvideCancelLeaveUC(dbRepository)
+	updateLeaveReasonUseCase := usecase.ProvideUpdateLeaveReasonUC(dbRepository)
+	adminToggleCheckInUseCase := usecase.ProvideAdminToggleCheckInUC(dbRepository)
+	adminCreateLeaveUseCase := usecase.ProvideAdminCreateLeaveUC(dbRepository)
+	adminRestoreFromLeaveUseCase := usecase.ProvideAdminRestoreFromLeaveUC(dbRepository)
+	approveLeaveUseCase := usecase.ProvideApproveLeaveUC(dbRepository)
+	rejectLeaveUseCase := usecase.ProvideRejectLeaveUC(dbRepository)
+	adminCreateWalkInUseCase := usecase.ProvideAdminCreateWalkInUC(dbRepository)
 	adminQueryStudentsUseCase := usecase.ProvideAdminQueryStudentsUC(dbRepository)
-	autoMarkAbsentUseCase := usecase.ProvideAutoMarkAbsentUC(dbRepository, bus)
-	adminBatchUpdateAttendanceUseCase := usecase.ProvideAdminBatchUpdateAttendanceUC(dbRepository, bus)
+	autoMarkAbsentUseCase := usecase.ProvideAutoMarkAbsentUC(dbRepository)
+	adminBatchUpdateAttendanceUseCase := usecase.ProvideAdminBatchUpdateAttendanceUC(dbRepository)
 	readUseCase7 := usecase.ProvideQueryUserBookingsUC(dbRepository)
 	getUserMonthlyStatsUseCase := usecase.ProvideGetUserMonthlyStatsUC(dbRepository)
 	queryTwoWeeksScheduleUseCase := usecase.ProvideQueryTwoWeeksScheduleUC(dbRepository)
 	readUseCase8 := usecase.ProvideQueryAllUserApptStatsUC(dbRepository)
 	batchSyncMonthlyStatsUseCase := usecase.ProvideBatchSyncMonthlyStatsUC(dbRepository)
-	queryMonthlyUserReportsUseCase := usecase.ProvideQueryMonthlyUserReportsUC(dbRepository)
+	billingPolicy := ProvideBillingPolicy()
+	queryMonthlyUserReportsUseCase := usecase.ProvideQueryMonthlyUserReportsUC(dbRepository, billingPolicy)
 	getBusinessAnalyticsUseCase := usecase.ProvideGetBusinessAnalyticsUC(dbRepository)
 	getUserDetailUseCase := usecase.ProvideGetUserDetailUC(dbRepository)
-	v := usecase.ProvideSubscribers(dbRepository)
+	joinWaitlistUseCase := usecase.ProvideJoinWaitlistUC(dbRepository)
+	leaveWaitlistUseCase := usecase.ProvideLeaveWaitlistUC(dbRepository)
+	adminReorderWaitlistUseCase := usecase.ProvideAdminReorderWaitlistUC(dbRepository)
+	promoteWaitlistUseCase := usecase.ProvidePromoteWaitlistUC(dbRepository)
+	queryWaitlistUseCase := usecase.ProvideQueryWaitlistUC(dbRepository)
+	createTrainingSeriesUseCase := usecase.ProvideCreateTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	materializeTrainingSeriesUseCase := usecase.ProvideMaterializeTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	updateTrainingSeriesUseCase := usecase.ProvideUpdateTrainingSeriesUC(dbRepository, serviceAggregator, bookingPolicy)
+	deleteTrainingSeriesUseCase := usecase.ProvideDeleteTrainingSeriesUC(dbRepository)
+	queryTrainingSeriesUseCase := usecase.ProvideQueryTrainingSeriesUC(dbRepository)
+	adminTopUpCreditsUseCase := usecase.ProvideAdminTopUpCreditsUC(dbRepository)
+	queryCreditLedgerUseCase := usecase.ProvideQueryCreditLedgerUC(dbRepository)
+	bookMakeUpUseCase := usecase.ProvideBookMakeUpUC(dbRepository)
+	queryMakeUpCreditsUseCase := usecase.ProvideQueryMakeUpCreditsUC(dbRepository)
+	recordPaymentUseCase := usecase.ProvideRecordPaymentUC(dbRepository)
+	createStudentUseCase := usecase.ProvideCreateStudentUC(dbRepository)
+	updateStudentUseCase := usecase.ProvideUpdateStudentUC(dbRepository)
+	deleteStudentUseCase := usecase.ProvideDeleteStudentUC(dbRepository)
+	mergeStudentsUseCase := usecase.ProvideMergeStudentsUC(dbRepository)
+	migrateChildNamesUseCase := usecase.ProvideMigrateChildNamesUC(dbRepository)
+	queryStudentsUseCase := usecase.ProvideQueryStudentsUC(dbRepository)
+	assignTrainDateTeamUseCase := usecase.ProvideAssignTrainDateTeamUC(dbRepository)
+	cancelTrainDateUseCase := usecase.ProvideCancelTrainDateUC(dbRepository)
+	rescheduleTrainDateUseCase := usecase.ProvideRescheduleTrainDateUC(dbRepository, serviceAggregator)
+	reconcileCapacityUseCase := usecase.ProvideReconcileCapacityUC(dbRepository)
+	createTeamUseCase := usecase.ProvideCreateTeamUC(dbRepository)
+	updateTeamUseCase := usecase.ProvideUpdateTeamUC(dbRepository)
+	updateTeamMembersUseCase := usecase.ProvideUpdateTeamMembersUC(dbRepository)
+	deleteTeamUseCase := usecase.ProvideDeleteTeamUC(dbRepository)
+	queryTeamsUseCase := usecase.ProvideQueryTeamsUC(dbRepository)
+	queryTeamMembersUseCase := usecase.ProvideQueryTeamMembersUC(dbRepository)
+	assignUserRolesUseCase := usecase.ProvideAssignUserRolesUC(dbRepository)
+	removeUserRolesUseCase := usecase.ProvideRemoveUserRolesUC(dbRepository)
+	queryUserRolesUseCase := usecase.ProvideQueryUserRolesUC(dbRepository)
+	resolveActorUseCase := usecase.ProvideResolveActorUC(dbRepository)
+	applyApptCreditUseCase := usecase.ProvideApplyApptCreditUC(dbRepository)
+	settleCreditLedgerUseCase := usecase.ProvideSettleCreditLedgerUC(dbRepository)
+	syncMakeUpCreditUseCase := usecase.ProvideSyncMakeUpCreditUC(dbRepository)
+	v := usecase.ProvideSubscribers(dbRepository, serviceAggregator, promoteWaitlistUseCase, applyApptCreditUseCase, settleCreditLedgerUseCase, syncMakeUpCreditUseCase)
+	queryDeadLettersUseCase := usecase.ProvideQueryDeadLettersUC(deadLetterStore)
+	getDeadLetterUseCase := usecase.ProvideGetDeadLetterUC(deadLetterStore)
+	replayDeadLetterUseCase := usecase.ProvideReplayDeadLetterUC(deadLetterStore, v)
+	discardDeadLetterUseCase := usecase.ProvideDiscardDeadLetterUC(deadLetterStore)
+	eventLog := event.ProvideEventLog(eventStore)
+	replayEventsUseCase := usecase.ProvideReplayEventsUC(eventLog, deadLetterStore, v)
+	archiver, err := ProvideArchiver(eventLog, database)
+	if err != nil {
+		return nil, err
+	}
+	archiveEventsUseCase := usecase.ProvideArchiveEventsUC(archiver)
+	redispatchEventUseCase := usecase.ProvideRedispatchEventUC(eventLog, deadLetterStore, v)
+	queryEventsUseCase := usecase.ProvideQueryEventsUC(eventLog)
+	getEventUseCase := usecase.ProvideGetEventUC(eventLog)
+	querySubscriberProgressUseCase := usecase.ProvideQuerySubscriberProgressUC(eventLog, v)
+	createWebhookEndpointUseCase := usecase.ProvideCreateWebhookEndpointUC(dbRepository)
+	updateWebhookEndpointUseCase := usecase.ProvideUpdateWebhookEndpointUC(dbRepository)
+	deleteWebhookEndpointUseCase := usecase.ProvideDeleteWebhookEndpointUC(dbRepository)
+	pingWebhookEndpointUseCase := usecase.ProvidePingWebhookEndpointUC(dbRepository, serviceAggregator)
+	queryWebhookEndpointsUseCase := usecase.ProvideQueryWebhookEndpointsUC(dbRepository)
+	queryWebhookDeliveriesUseCase := usecase.ProvideQueryWebhookDeliveriesUC(dbRepository)
 	idempotencyManager := usecase.ProvideIdempotencyManager()
 	registry := &usecase.Registry{
-		CreateTrainDate:            writeUseCase,
-		BatchCreateTrainDate:       coreWriteUseCase,
-		DeleteTrainDate:            writeUseCase2,
-		QueryFutureTrain:           readUseCase,
-		FindNearestTrainByTime:     coreReadUseCase,
-		FindTrainHasApptsById:      readUseCase2,
-		UserQueryFutureTrain:       readUseCase3,
-		UserQueryTrainByID:         readUseCase4,
-		AdminQueryTrainRange:       readUseCase5,
-		AdminQueryRecentTrain:      readUseCase6,
-		CreateAppt:                 createApptUseCase,
-		CheckIn:                    checkInUseCase,
-		CancelAppt:                 cancelApptUseCase,
-		CreateLeave:                createLeaveUseCase,
-		CancelLeave:                cancelLeaveUseCase,
-		AdminCheckIn:               adminCheckInUseCase,
-		AdminToggleCheckIn:         adminToggleCheckInUseCase,
-		AdminCreateLeave:           adminCreateLeaveUseCase,
-		AdminRestoreFromLeave:      adminRestoreFromLeaveUseCase,
-		AdminCreateWalkIn:          adminCreateWalkInUseCase,
-		AdminQueryStudents:         adminQueryStudentsUseCase,
-		AutoMarkAbsent:             autoMarkAbsentUseCase,
-		AdminBatchUpdateAttendance: adminBatchUpdateAttendanceUseCase,
-		QueryUserBookings:          readUseCase7,
-		GetUserMonthlyStats:        getUserMonthlyStatsUseCase,
-		QueryTwoWeeksSchedule:      queryTwoWeeksScheduleUseCase,
-		QueryAllUserApptStats:      readUseCase8,
-		BatchSyncMonthlyStats:      batchSyncMonthlyStatsUseCase,
-		QueryMonthlyUserReports:    queryMonthlyUserReportsUseCase,
-		GetBusinessAnalytics:       getBusinessAnalyticsUseCase,
-		GetUserDetail:              getUserDetailUseCase,
-		Bus:                        bus,
-		Subscribers:                v,
-		IdempotencyManager:         idempotencyManager,
+		CreateTrainDate:              writeUseCase,
+		BatchCreateTrainDate:         coreWriteUseCase,
+		DeleteTrainDate:              writeUseCase2,
+		UpdateTrainDateBookingPolicy: updateTrainDateBookingPolicyUseCase,
+		AssignTrainDateTeam:          assignTrainDateTeamUseCase,
+		CancelTrainDate:              cancelTrainDateUseCase,
+		RescheduleTrainDate:          rescheduleTrainDateUseCase,
+		ReconcileCapacity:            reconcileCapacityUseCase,
+		QueryFutureTrain:             readUseCase,
+		FindNearestTrainByTime:       coreReadUseCase,
+		FindTrainHasApptsById:        readUseCase2,
+		UserQueryFutureTrain:         readUseCase3,
+		UserQueryTrainByID:           readUseCase4,
+		AdminQueryTrainRange:         readUseCase5,
+		AdminQueryRecentTrain:        readUseCase6,
+		CreateAppt:                   createApptUseCase,
+		CheckIn:                      checkInUseCase,
+		CancelAppt:                   cancelApptUseCase,
+		CreateLeave:                  createLeaveUseCase,
+		CancelLeave:                  cancelLeaveUseCase,
+		UpdateLeaveReason:            updateLeaveReasonUseCase,
+		AdminCheckIn:                 adminCheckInUseCase,
+		AdminToggleCheckIn:           adminToggleCheckInUseCase,
+		AdminCreateLeave:             adminCreateLeaveUseCase,
+		AdminRestoreFromLeave:        adminRestoreFromLeaveUseCase,
+		ApproveLeave:                 approveLeaveUseCase,
+		RejectLeave:                  rejectLeaveUseCase,
+		AdminCreateWalkIn:            adminCreateWalkInUseCase,
+		AdminQueryStudents:           adminQueryStudentsUseCase,
+		AutoMarkAbsent:               autoMarkAbsentUseCase,
+		AdminBatchUpdateAttendance:   adminBatchUpdateAttendanceUseCase,
+		QueryUserBookings:            readUseCase7,
+		GetUserMonthlyStats:          getUserMonthlyStatsUseCase,
+		QueryTwoWeeksSchedule:        queryTwoWeeksScheduleUseCase,
+		QueryAllUserApptStats:        readUseCase8,
+		BatchSyncMonthlyStats:        batchSyncMonthlyStatsUseCase,
+		QueryMonthlyUserReports:      queryMonthlyUserReportsUseCase,
+		GetBusinessAnalytics:         getBusinessAnalyticsUseCase,
+		GetUserDetail:                getUserDetailUseCase,
+		JoinWaitlist:                 joinWaitlistUseCase,
+		LeaveWaitlist:                leaveWaitlistUseCase,
+		AdminReorderWaitlist:         adminReorderWaitlistUseCase,
+		PromoteWaitlist:              promoteWaitlistUseCase,
+		QueryWaitlist:                queryWaitlistUseCase,
+		CreateTrainingSeries:         createTrainingSeriesUseCase,
+		MaterializeTrainingSeries:    materializeTrainingSeriesUseCase,
+		UpdateTrainingSeries:         updateTrainingSeriesUseCase,
+		DeleteTrainingSeries:         deleteTrainingSeriesUseCase,
+		QueryTrainingSeries:          queryTrainingSeriesUseCase,
+		AdminTopUpCredits:            adminTopUpCreditsUseCase,
+		QueryCreditLedger:            queryCreditLedgerUseCase,
+		BookMakeUp:                   bookMakeUpUseCase,
+		QueryMakeUpCredits:           queryMakeUpCreditsUseCase,
+		RecordPayment:                recordPaymentUseCase,
+		CreateStudent:                createStudentUseCase,
+		UpdateStudent:                updateStudentUseCase,
+		DeleteStudent:                deleteStudentUseCase,
+		MergeStudents:                mergeStudentsUseCase,
+		MigrateChildNames:            migrateChildNamesUseCase,
+		QueryStudents:                queryStudentsUseCase,
+		CreateTeam:                   createTeamUseCase,
+		UpdateTeam:                   updateTeamUseCase,
+		UpdateTeamMembers:            updateTeamMembersUseCase,
+		DeleteTeam:                   deleteTeamUseCase,
+		QueryTeams:                   queryTeamsUseCase,
+		QueryTeamMembers:             queryTeamMembersUseCase,
+		AssignUserRoles:              assignUserRolesUseCase,
+		RemoveUserRoles:              removeUserRolesUseCase,
+		QueryUserRoles:               queryUserRolesUseCase,
+		ResolveActor:                 resolveActorUseCase,
+		QueryDeadLetters:             queryDeadLettersUseCase,
+		GetDeadLetter:                getDeadLetterUseCase,
+		ReplayDeadLetter:             replayDeadLetterUseCase,
+		DiscardDeadLetter:            discardDeadLetterUseCase,
+		ReplayEvents:                 replayEventsUseCase,
+		ArchiveEvents:                archiveEventsUseCase,
+		QueryEvents:                  queryEventsUseCase,
+		GetEvent:                     getEventUseCase,
+		QuerySubscriberProgress:      querySubscriberProgressUseCase,
+		RedispatchEvent:              redispatchEventUseCase,
+		CreateWebhookEndpoint:        createWebhookEndpointUseCase,
+		UpdateWebhookEndpoint:        updateWebhookEndpointUseCase,
+		DeleteWebhookEndpoint:        deleteWebhookEndpointUseCase,
+		PingWebhookEndpoint:          pingWebhookEndpointUseCase,
+		QueryWebhookEndpoints:        queryWebhookEndpointsUseCase,
+		QueryWebhookDeliveries:       queryWebhookDeliveriesUseCase,
+		Bus:                          bus,
+		Subscribers:                  v,
+		Relay:                        outboxRelay,
+		IdempotencyManager:           idempotencyManager,
 	}
 	return registry, nil
 }
@@ -292,5 +689,5 @@ func ProvideDatabase() *mongo.Database {
 }
 
 func toolSet() []server.ServerTool {
-	return []server.ServerTool{tool.ProvideCreateTrainingCoursesTool(), tool.ProvideDeleteLeaveByIdTool(), tool.ProvideQueryLeaveByDateTool(), tool.ProvideQueryTrainingByRangeTool()}
+	return []server.ServerTool{tool.ProvideCreateTrainingCoursesTool(), tool.ProvideCreateTrainingSeriesTool(), tool.ProvideUpdateTrainingSeriesTool(), tool.ProvideDeleteTrainingSeriesTool(), tool.ProvideDeleteLeaveByIdTool(), tool.ProvideQueryLeaveByDateTool(), tool.ProvideQueryTrainingByRangeTool(), tool.ProvideQueryTrainingSeriesTool()}
 }
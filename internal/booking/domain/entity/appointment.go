@@ -21,20 +21,27 @@ const (
 	StatusAbsent         appointmentStatus = "ABSENT"          // 缺席
 	StatusCancelled      appointmentStatus = "CANCELLED"       // 10分鐘內誤按取消
 	StatusCancelledLeave appointmentStatus = "CANCELLED_LEAVE" // 已請假
+	// 教練停課，不計入請假或缺席
+	StatusCancelledByCoach appointmentStatus = "CANCELLED_BY_COACH"
 
 	LeaveStatusNone     leaveStatus = "none" // No leave request or not applicable
 	LeaveStatusPending  leaveStatus = "pending"
 	LeaveStatusApproved leaveStatus = "approved"
 	LeaveStatusRejected leaveStatus = "rejected"
+
+	// 課程開始前未審核的請假由系統駁回
+	LeaveReviewerSystem = "system"
+	LeaveExpiredComment = "課程開始前未審核，請假已失效"
 )
 
 var (
 	apptStatusTrans = map[string]appointmentStatus{
-		string(StatusConfirmed):      StatusConfirmed,
-		string(StatusAttended):       StatusAttended,
-		string(StatusAbsent):         StatusAbsent,
-		string(StatusCancelled):      StatusCancelled,
-		string(StatusCancelledLeave): StatusCancelledLeave,
+		string(StatusConfirmed):        StatusConfirmed,
+		string(StatusAttended):         StatusAttended,
+		string(StatusAbsent):           StatusAbsent,
+		string(StatusCancelled):        StatusCancelled,
+		string(StatusCancelledLeave):   StatusCancelledLeave,
+		string(StatusCancelledByCoach): StatusCancelledByCoach,
 	}
 
 	leaveStatusTrans = map[string]leaveStatus{
@@ -69,7 +76,9 @@ type Appointment struct {
 	user        User
 	id          string
 	childName   string
+	studentID   string // 舊資料尚未對應學員時為空
 	trainingId  string
+	makeUpOf    string // 補課預約對應的請假預約 ID
 	contactInfo string
 	status      appointmentStatus
 	isWalkIn    bool
@@ -85,9 +94,26 @@ func NewLeaveInfo(reason string, status leaveStatus, createdAt time.Time) VO_Lea
 }
 
 type VO_LeaveInfo struct {
-	createdAt time.Time
-	reason    string
-	status    leaveStatus
+	createdAt     time.Time
+	reviewedAt    *time.Time
+	notifiedAt    *time.Time // 待審核時為通知教練的時間，審核後為通知家長結果的時間
+	reason        string
+	reviewedBy    string
+	reviewComment string
+	status        leaveStatus
+}
+
+// WithReview 還原教練審核紀錄
+func (l VO_LeaveInfo) WithReview(reviewedBy, comment string, reviewedAt *time.Time) VO_LeaveInfo {
+	l.reviewedBy = reviewedBy
+	l.reviewComment = comment
+	l.reviewedAt = reviewedAt
+	return l
+}
+
+func (l VO_LeaveInfo) WithNotifiedAt(notifiedAt *time.Time) VO_LeaveInfo {
+	l.notifiedAt = notifiedAt
+	return l
 }
 
 func (l VO_LeaveInfo) IsEmpty() bool {
@@ -106,6 +132,27 @@ func (leave VO_LeaveInfo) CreatedAt() time.Time {
 	return leave.createdAt
 }
 
+func (leave VO_LeaveInfo) ReviewedBy() string {
+	return leave.reviewedBy
+}
+
+func (leave VO_LeaveInfo) ReviewComment() string {
+	return leave.reviewComment
+}
+
+func (leave VO_LeaveInfo) ReviewedAt() *time.Time {
+	return leave.reviewedAt
+}
+
+func (leave VO_LeaveInfo) NotifiedAt() *time.Time {
+	return leave.notifiedAt
+}
+
+// IsReviewed 經教練審核，自動核准的請假沒有審核人
+func (leave VO_LeaveInfo) IsReviewed() bool {
+	return leave.reviewedBy != ""
+}
+
 func (a Appointment) Validate() error {
 	// 驗證姓名
 	name, ok := validator.ValidateName(a.childName, 1, 20)
@@ -190,6 +237,19 @@ func WithChildName(name string) apptOpt {
 	}
 }
 
+func WithApptStudentID(studentID string) apptOpt {
+	return func(appt *Appointment) {
+		appt.studentID = studentID
+	}
+}
+
+// WithMakeUpOf 以請假預約的補課資格預約
+func WithMakeUpOf(leaveApptID string) apptOpt {
+	return func(appt *Appointment) {
+		appt.makeUpOf = leaveApptID
+	}
+}
+
 func WithTrainingID(id string) apptOpt {
 	return func(appt *Appointment) {
 		appt.trainingId = id
@@ -239,14 +299,13 @@ func NewAppointment(opts ...apptOpt) (*Appointment, error) {
 	return newAppt, nil
 }
 
-const cancelTimeout = 24 * time.Hour
-
-func (a *Appointment) CancelAsMistake(userID string) error {
+// CancelAsMistake 家長誤按預約後於場次規則允許的時間內取消
+func (a *Appointment) CancelAsMistake(userID string, policy BookingPolicy) error {
 	if a.user.userID != userID {
 		return ErrAppointmentNotBelongToUser
 	}
-	if time.Since(a.createdAt) > cancelTimeout {
-		return ErrAppointmentCancelTimeout
+	if err := policy.checkCancel(a.createdAt, time.Now()); err != nil {
+		return err
 	}
 	if a.status != StatusConfirmed {
 		return ErrAppointmentInvalidStatus
@@ -257,21 +316,11 @@ func (a *Appointment) CancelAsMistake(userID string) error {
 	return nil
 }
 
-func (a *Appointment) validateOperationTime(trainingStartTime time.Time) error {
-	now := time.Now()
-	// 規則 1：開課前 30 分鐘內方可進行出席狀態變更
-	if now.Before(trainingStartTime.Add(-30 * time.Minute)) {
-		return ErrAppointmentCheckInNotOpen
+func (a *Appointment) AdminCheckIn(trainingStartTime time.Time, policy BookingPolicy) error {
+	if a.IsCancelledByCoach() {
+		return ErrAppointmentCancelledByCoach
 	}
-	// 規則 2：補登/補改只能在 7 天內
-	if now.After(trainingStartTime.Add(7 * 24 * time.Hour)) {
-		return ErrAppointmentCheckInTooLate
-	}
-	return nil
-}
-
-func (a *Appointment) AdminCheckIn(trainingStartTime time.Time) error {
-	if err := a.validateOperationTime(trainingStartTime); err != nil {
+	if err := policy.checkAttendance(trainingStartTime, time.Now()); err != nil {
 		return err
 	}
 	a.status = StatusAttended
@@ -282,8 +331,11 @@ func (a *Appointment) AdminCheckIn(trainingStartTime time.Time) error {
 	return nil
 }
 
-func (a *Appointment) AdminMarkAsAbsent(trainingStartTime time.Time) error {
-	if err := a.validateOperationTime(trainingStartTime); err != nil {
+func (a *Appointment) AdminMarkAsAbsent(trainingStartTime time.Time, policy BookingPolicy) error {
+	if a.IsCancelledByCoach() {
+		return ErrAppointmentCancelledByCoach
+	}
+	if err := policy.checkAttendance(trainingStartTime, time.Now()); err != nil {
 		return err
 	}
 	a.status = StatusAbsent
@@ -291,17 +343,10 @@ func (a *Appointment) AdminMarkAsAbsent(trainingStartTime time.Time) error {
 	return nil
 }
 
-func (a *Appointment) AppendLeaveRecord(reason string, trainingStartTime time.Time) error {
-	if reason == "" {
-		return ErrAppointmentLeaveReasonEmpty
-	}
-	// 1. 檢查規則：必須在 2 小時前
-	if time.Until(trainingStartTime) < 2*time.Hour {
-		return ErrAppointmentLeaveTooLate
-	}
-
-	if a.status != StatusConfirmed {
-		return ErrAppointmentCannotLeave
+func (a *Appointment) AppendLeaveRecord(reason string, trainingStartTime time.Time, policy BookingPolicy) error {
+	// 1. 檢查規則：必須在場次規定的請假期限前
+	if err := a.checkLeave(reason, trainingStartTime, policy); err != nil {
+		return err
 	}
 
 	// 2. 更新自身狀態
@@ -317,8 +362,117 @@ func (a *Appointment) AppendLeaveRecord(reason string, trainingStartTime time.Ti
 	return nil
 }
 
-func (a *Appointment) AdminAppendLeave(reason string, trainingStartTime time.Time) error {
-	if err := a.validateOperationTime(trainingStartTime); err != nil {
+// RequestLeave 需教練核准的請假，審核前維持預約狀態，不釋出名額
+func (a *Appointment) RequestLeave(reason string, trainingStartTime time.Time, policy BookingPolicy) error {
+	if err := a.checkLeave(reason, trainingStartTime, policy); err != nil {
+		return err
+	}
+	now := time.Now()
+	a.leave = VO_LeaveInfo{
+		reason:    reason,
+		status:    LeaveStatusPending,
+		createdAt: now,
+	}
+	a.updateAt = now
+	return nil
+}
+
+func (a *Appointment) checkLeave(reason string, trainingStartTime time.Time, policy BookingPolicy) error {
+	if reason == "" {
+		return ErrAppointmentLeaveReasonEmpty
+	}
+	if err := policy.checkLeave(trainingStartTime, time.Now()); err != nil {
+		return err
+	}
+	if a.status != StatusConfirmed {
+		return ErrAppointmentCannotLeave
+	}
+	if a.HasPendingLeave() {
+		return ErrAppointmentLeavePending
+	}
+	return nil
+}
+
+// ApproveLeave 教練核准請假，此時才轉為已請假並釋出名額
+func (a *Appointment) ApproveLeave(reviewerID, comment string, trainingStartTime time.Time) error {
+	if err := a.checkReview(reviewerID, trainingStartTime); err != nil {
+		return err
+	}
+	a.status = StatusCancelledLeave
+	a.review(LeaveStatusApproved, reviewerID, comment)
+	return nil
+}
+
+// RejectLeave 教練駁回請假，預約維持原狀態
+func (a *Appointment) RejectLeave(reviewerID, comment string, trainingStartTime time.Time) error {
+	if err := a.checkReview(reviewerID, trainingStartTime); err != nil {
+		return err
+	}
+	a.review(LeaveStatusRejected, reviewerID, comment)
+	return nil
+}
+
+// checkReview 課程開始後不可再審核，未審核的請假由缺席排程處理
+func (a *Appointment) checkReview(reviewerID string, trainingStartTime time.Time) error {
+	if reviewerID == "" {
+		return fmt.Errorf("%w: reviewer is empty", ErrAppointmentInvalid)
+	}
+	if a.IsCancelledByCoach() {
+		return ErrAppointmentCancelledByCoach
+	}
+	if !a.HasPendingLeave() {
+		return ErrAppointmentLeaveNotPending
+	}
+	if !time.Now().Before(trainingStartTime) {
+		return ErrAppointmentLeaveReviewClosed
+	}
+	return nil
+}
+
+// ExpireLeave 課程結束仍未審核的請假由系統駁回，不變更預約狀態，缺席與否由排程決定
+func (a *Appointment) ExpireLeave() error {
+	if a.leave.status != LeaveStatusPending {
+		return ErrAppointmentLeaveNotPending
+	}
+	a.review(LeaveStatusRejected, LeaveReviewerSystem, LeaveExpiredComment)
+	return nil
+}
+
+// review 審核後重置通知時間，改為通知家長審核結果
+func (a *Appointment) review(status leaveStatus, reviewerID, comment string) {
+	now := time.Now()
+	a.leave.status = status
+	a.leave.reviewedBy = reviewerID
+	a.leave.reviewComment = validator.SanitizeInput(comment)
+	a.leave.reviewedAt = &now
+	a.leave.notifiedAt = nil
+	a.updateAt = now
+}
+
+// HasPendingLeave 請假等待教練審核中
+func (a *Appointment) HasPendingLeave() bool {
+	return a.status == StatusConfirmed && a.leave.status == LeaveStatusPending
+}
+
+// HasPendingLeaveNotice 尚未推播的請假申請或審核結果
+func (a *Appointment) HasPendingLeaveNotice() bool {
+	if a.leave.notifiedAt != nil {
+		return false
+	}
+	return a.HasPendingLeave() || a.leave.IsReviewed()
+}
+
+// MarkLeaveNotified 記錄已推播請假申請或審核結果
+func (a *Appointment) MarkLeaveNotified() {
+	now := time.Now()
+	a.leave.notifiedAt = &now
+}
+
+func (a *Appointment) AdminAppendLeave(reason string, trainingStartTime time.Time, policy BookingPolicy) error {
+	if a.IsCancelledByCoach() {
+		return ErrAppointmentCancelledByCoach
+	}
+	if err := policy.checkAttendance(trainingStartTime, time.Now()); err != nil {
 		return err
 	}
 	a.status = StatusCancelledLeave
@@ -331,8 +485,11 @@ func (a *Appointment) AdminAppendLeave(reason string, trainingStartTime time.Tim
 	return nil
 }
 
-func (a *Appointment) AdminRestoreFromLeave(trainingStartTime time.Time) error {
-	if err := a.validateOperationTime(trainingStartTime); err != nil {
+func (a *Appointment) AdminRestoreFromLeave(trainingStartTime time.Time, policy BookingPolicy) error {
+	if a.IsCancelledByCoach() {
+		return ErrAppointmentCancelledByCoach
+	}
+	if err := policy.checkAttendance(trainingStartTime, time.Now()); err != nil {
 		return err
 	}
 	a.status = StatusConfirmed
@@ -345,6 +502,9 @@ func (a *Appointment) CancelLeave(userID string) error {
 	if a.user.userID != userID {
 		return ErrAppointmentNotBelongToUser
 	}
+	if a.IsCancelledByCoach() {
+		return ErrAppointmentCancelledByCoach
+	}
 	if a.leave.status != LeaveStatusApproved && a.leave.status != LeaveStatusPending {
 		return ErrAppointmentLeaveNotApproved
 	}
@@ -354,19 +514,93 @@ func (a *Appointment) CancelLeave(userID string) error {
 	return nil
 }
 
+// UpdateLeaveReason 家長修改待審核或已核准的請假原因，課程開始後不可修改；審核結果維持不變
+func (a *Appointment) UpdateLeaveReason(userID, reason string, trainingStartTime time.Time) error {
+	if a.user.userID != userID {
+		return ErrAppointmentNotBelongToUser
+	}
+	if a.IsCancelledByCoach() {
+		return ErrAppointmentCancelledByCoach
+	}
+	if !a.HasPendingLeave() && a.status != StatusCancelledLeave {
+		return ErrAppointmentLeaveNotApproved
+	}
+	reason = validator.SanitizeInput(reason)
+	if reason == "" {
+		return ErrAppointmentLeaveReasonEmpty
+	}
+	if !time.Now().Before(trainingStartTime) {
+		return ErrAppointmentLeaveTooLate
+	}
+	a.leave.reason = reason
+	a.updateAt = time.Now()
+	return nil
+}
+
+// ReleaseAfterReschedule 場次改期前預約的家長無法配合新時間時取消預約，不受取消時限限制
+func (a *Appointment) ReleaseAfterReschedule(userID string, reschedule TrainDateReschedule, trainingStartTime time.Time) error {
+	if a.user.userID != userID {
+		return ErrAppointmentNotBelongToUser
+	}
+	if reschedule.IsZero() || !a.createdAt.Before(reschedule.RescheduledAt()) {
+		return ErrAppointmentNotRescheduled
+	}
+	if a.status != StatusConfirmed {
+		return ErrAppointmentInvalidStatus
+	}
+	if time.Now().After(trainingStartTime) {
+		return ErrAppointmentCancelTimeout
+	}
+
+	a.status = StatusCancelled
+	a.updateAt = time.Now()
+	return nil
+}
+
+// CancelByCoach 場次停課，保留原本的請假紀錄供查詢
+func (a *Appointment) CancelByCoach() error {
+	if a.status == StatusCancelled {
+		return ErrAppointmentInvalidStatus
+	}
+	if a.IsCancelledByCoach() {
+		return nil
+	}
+	a.status = StatusCancelledByCoach
+	a.updateAt = time.Now()
+	return nil
+}
+
+func (a *Appointment) IsCancelledByCoach() bool {
+	return a.status == StatusCancelledByCoach
+}
+
+// OccupiesSeat 是否佔用場次名額，待審核的請假尚未釋出名額
+func (a *Appointment) OccupiesSeat() bool {
+	switch a.status {
+	case StatusConfirmed, StatusAttended, StatusAbsent:
+		return true
+	}
+	return false
+}
+
 // Error Definition
 var (
-	ErrAppointmentCheckInTooLate   = errors.New("APPOINTMENT_CHECKIN_TOO_LATE")
-	ErrAppointmentNotBelongToUser  = errors.New("APPOINTMENT_NOT_BELONG_TO_USER")
-	ErrAppointmentInvalid          = errors.New("APPOINTMENT_INVALID")
-	ErrAppointmentCancelTimeout    = errors.New("APPOINTMENT_CANCEL_TIMEOUT")
-	ErrAppointmentInvalidStatus    = errors.New("APPOINTMENT_INVALID_STATUS")
-	ErrAppointmentCheckInNotOpen   = errors.New("APPOINTMENT_CHECKIN_NOT_OPEN")
-	ErrAppointmentOnLeave          = errors.New("APPOINTMENT_ON_LEAVE")
-	ErrAppointmentLeaveTooLate     = errors.New("APPOINTMENT_LEAVE_TOO_LATE")
-	ErrAppointmentCannotLeave      = errors.New("APPOINTMENT_CANNOT_LEAVE")
-	ErrAppointmentLeaveReasonEmpty = errors.New("APPOINTMENT_LEAVE_REASON_EMPTY")
-	ErrAppointmentLeaveNotApproved = errors.New("APPOINTMENT_LEAVE_NOT_APPROVED")
+	ErrAppointmentCheckInTooLate    = errors.New("APPOINTMENT_CHECKIN_TOO_LATE")
+	ErrAppointmentNotBelongToUser   = errors.New("APPOINTMENT_NOT_BELONG_TO_USER")
+	ErrAppointmentInvalid           = errors.New("APPOINTMENT_INVALID")
+	ErrAppointmentCancelTimeout     = errors.New("APPOINTMENT_CANCEL_TIMEOUT")
+	ErrAppointmentInvalidStatus     = errors.New("APPOINTMENT_INVALID_STATUS")
+	ErrAppointmentCheckInNotOpen    = errors.New("APPOINTMENT_CHECKIN_NOT_OPEN")
+	ErrAppointmentOnLeave           = errors.New("APPOINTMENT_ON_LEAVE")
+	ErrAppointmentLeaveTooLate      = errors.New("APPOINTMENT_LEAVE_TOO_LATE")
+	ErrAppointmentCannotLeave       = errors.New("APPOINTMENT_CANNOT_LEAVE")
+	ErrAppointmentLeaveReasonEmpty  = errors.New("APPOINTMENT_LEAVE_REASON_EMPTY")
+	ErrAppointmentLeaveNotApproved  = errors.New("APPOINTMENT_LEAVE_NOT_APPROVED")
+	ErrAppointmentLeavePending      = errors.New("APPOINTMENT_LEAVE_PENDING")
+	ErrAppointmentLeaveNotPending   = errors.New("APPOINTMENT_LEAVE_NOT_PENDING")
+	ErrAppointmentLeaveReviewClosed = errors.New("APPOINTMENT_LEAVE_REVIEW_CLOSED")
+	ErrAppointmentCancelledByCoach  = errors.New("APPOINTMENT_CANCELLED_BY_COACH")
+	ErrAppointmentNotRescheduled    = errors.New("APPOINTMENT_NOT_RESCHEDULED")
 )
 
 // Getter
@@ -382,6 +616,21 @@ func (appt *Appointment) ChildName() string {
 	return appt.childName
 }
 
+func (appt *Appointment) StudentID() string {
+	return appt.studentID
+}
+
+// LinkStudent 將預約對應到學員，姓名同步為學員姓名以維持報表一致
+func (appt *Appointment) LinkStudent(s *Student) error {
+	if !s.BelongsTo(appt.user.userID) {
+		return ErrStudentNotBelongToUser
+	}
+	appt.studentID = s.ID()
+	appt.childName = s.Name()
+	appt.updateAt = time.Now()
+	return nil
+}
+
 func (appt *Appointment) TrainingID() string {
 	return appt.trainingId
 }
@@ -398,6 +647,15 @@ func (appt *Appointment) VerifiedAt() *time.Time {
 	return appt.verifiedAt
 }
 
+// MakeUpOf 補課預約對應的請假預約 ID，一般預約為空
+func (appt *Appointment) MakeUpOf() string {
+	return appt.makeUpOf
+}
+
+func (appt *Appointment) IsMakeUp() bool {
+	return appt.makeUpOf != ""
+}
+
 func (appt *Appointment) IsWalkIn() bool {
 	return appt.isWalkIn
 }
@@ -41,14 +41,14 @@ func TestAppointment_CancelAsMistake(t *testing.T) {
 	
 	t.Run("Success", func(t *testing.T) {
 		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
-		err := appt.CancelAsMistake("u1")
+		err := appt.CancelAsMistake("u1", DefaultBookingPolicy())
 		require.NoError(t, err)
 		assert.Equal(t, StatusCancelled, appt.Status())
 	})
 
 	t.Run("Fail_NotUser", func(t *testing.T) {
 		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
-		err := appt.CancelAsMistake("u2")
+		err := appt.CancelAsMistake("u2", DefaultBookingPolicy())
 		assert.ErrorIs(t, err, ErrAppointmentNotBelongToUser)
 	})
 
@@ -58,15 +58,15 @@ func TestAppointment_CancelAsMistake(t *testing.T) {
 		oldTime := time.Now().Add(-25 * time.Hour)
 		WithCreatedAt(oldTime)(appt)
 
-		err := appt.CancelAsMistake("u1")
+		err := appt.CancelAsMistake("u1", DefaultBookingPolicy())
 		assert.ErrorIs(t, err, ErrAppointmentCancelTimeout)
 	})
 
 	t.Run("Fail_InvalidStatus", func(t *testing.T) {
 		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
-		_ = appt.CancelAsMistake("u1") // Cancelled
+		_ = appt.CancelAsMistake("u1", DefaultBookingPolicy()) // Cancelled
 
-		err := appt.CancelAsMistake("u1")
+		err := appt.CancelAsMistake("u1", DefaultBookingPolicy())
 		assert.ErrorIs(t, err, ErrAppointmentInvalidStatus)
 	})
 }
@@ -80,7 +80,7 @@ func TestAppointment_AdminCheckIn(t *testing.T) {
 		// Training started 5 mins ago
 		trainStart := now.Add(-5 * time.Minute)
 		
-		err := appt.AdminCheckIn(trainStart)
+		err := appt.AdminCheckIn(trainStart, DefaultBookingPolicy())
 		require.NoError(t, err)
 		assert.Equal(t, StatusAttended, appt.Status())
 		assert.NotNil(t, appt.VerifiedAt())
@@ -91,7 +91,7 @@ func TestAppointment_AdminCheckIn(t *testing.T) {
 		// Training starts in 20 mins (allowed within 30min buffer)
 		trainStart := now.Add(20 * time.Minute)
 		
-		err := appt.AdminCheckIn(trainStart)
+		err := appt.AdminCheckIn(trainStart, DefaultBookingPolicy())
 		require.NoError(t, err)
 		assert.Equal(t, StatusAttended, appt.Status())
 	})
@@ -101,7 +101,7 @@ func TestAppointment_AdminCheckIn(t *testing.T) {
 		// Training starts in 40 mins (too early, outside 30min buffer)
 		trainStart := now.Add(40 * time.Minute)
 
-		err := appt.AdminCheckIn(trainStart)
+		err := appt.AdminCheckIn(trainStart, DefaultBookingPolicy())
 		assert.ErrorIs(t, err, ErrAppointmentCheckInNotOpen)
 	})
 
@@ -110,7 +110,7 @@ func TestAppointment_AdminCheckIn(t *testing.T) {
 		// Training started 8 days ago (limit is 7 days)
 		trainStart := now.Add(-8 * 24 * time.Hour)
 
-		err := appt.AdminCheckIn(trainStart)
+		err := appt.AdminCheckIn(trainStart, DefaultBookingPolicy())
 		assert.ErrorIs(t, err, ErrAppointmentCheckInTooLate)
 	})
 }
@@ -124,7 +124,7 @@ func TestAppointment_AppendLeaveRecord(t *testing.T) {
 		// Training starts in 3 hours (> 2 hours)
 		trainStart := now.Add(3 * time.Hour)
 
-		err := appt.AppendLeaveRecord("Sick", trainStart)
+		err := appt.AppendLeaveRecord("Sick", trainStart, DefaultBookingPolicy())
 		require.NoError(t, err)
 		assert.Equal(t, StatusCancelledLeave, appt.Status())
 		assert.Equal(t, "Sick", appt.LeaveInfo().Reason())
@@ -135,7 +135,7 @@ func TestAppointment_AppendLeaveRecord(t *testing.T) {
 		// Training starts in 1 hour (< 2 hours)
 		trainStart := now.Add(1 * time.Hour)
 
-		err := appt.AppendLeaveRecord("Sick", trainStart)
+		err := appt.AppendLeaveRecord("Sick", trainStart, DefaultBookingPolicy())
 		assert.ErrorIs(t, err, ErrAppointmentLeaveTooLate)
 	})
 
@@ -143,7 +143,7 @@ func TestAppointment_AppendLeaveRecord(t *testing.T) {
 		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
 		trainStart := now.Add(3 * time.Hour)
 
-		err := appt.AppendLeaveRecord("", trainStart)
+		err := appt.AppendLeaveRecord("", trainStart, DefaultBookingPolicy())
 		assert.ErrorIs(t, err, ErrAppointmentLeaveReasonEmpty)
 	})
 
@@ -152,7 +152,7 @@ func TestAppointment_AppendLeaveRecord(t *testing.T) {
 		WithStatus(StatusCancelled)(appt)
 		
 		trainStart := now.Add(3 * time.Hour)
-		err := appt.AppendLeaveRecord("Sick", trainStart)
+		err := appt.AppendLeaveRecord("Sick", trainStart, DefaultBookingPolicy())
 		assert.ErrorIs(t, err, ErrAppointmentCannotLeave)
 	})
 }
@@ -164,7 +164,7 @@ func TestAppointment_CancelLeave(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
 		// Setup leave
-		_ = appt.AppendLeaveRecord("Sick", now.Add(3*time.Hour))
+		_ = appt.AppendLeaveRecord("Sick", now.Add(3*time.Hour), DefaultBookingPolicy())
 
 		err := appt.CancelLeave("u1")
 		require.NoError(t, err)
@@ -174,7 +174,7 @@ func TestAppointment_CancelLeave(t *testing.T) {
 
 	t.Run("Fail_NotUser", func(t *testing.T) {
 		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
-		_ = appt.AppendLeaveRecord("Sick", now.Add(3*time.Hour))
+		_ = appt.AppendLeaveRecord("Sick", now.Add(3*time.Hour), DefaultBookingPolicy())
 
 		err := appt.CancelLeave("u2")
 		assert.ErrorIs(t, err, ErrAppointmentNotBelongToUser)
@@ -186,3 +186,190 @@ func TestAppointment_CancelLeave(t *testing.T) {
 		assert.ErrorIs(t, err, ErrAppointmentLeaveNotApproved)
 	})
 }
+
+func TestAppointment_UpdateLeaveReason(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+	trainStart := time.Now().Add(3 * time.Hour)
+
+	t.Run("Pending", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		require.NoError(t, appt.RequestLeave("Sick", trainStart, DefaultBookingPolicy()))
+
+		require.NoError(t, appt.UpdateLeaveReason("u1", "Fever", trainStart))
+		assert.Equal(t, "Fever", appt.LeaveInfo().Reason())
+		assert.True(t, appt.HasPendingLeave())
+	})
+
+	t.Run("Approved", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		require.NoError(t, appt.AppendLeaveRecord("Sick", trainStart, DefaultBookingPolicy()))
+
+		require.NoError(t, appt.UpdateLeaveReason("u1", "Fever", trainStart))
+		assert.Equal(t, "Fever", appt.LeaveInfo().Reason())
+		assert.Equal(t, StatusCancelledLeave, appt.Status())
+		assert.Equal(t, LeaveStatusApproved, appt.LeaveInfo().Status())
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		assert.ErrorIs(t, appt.UpdateLeaveReason("u1", "Fever", trainStart), ErrAppointmentLeaveNotApproved)
+
+		require.NoError(t, appt.RequestLeave("Sick", trainStart, DefaultBookingPolicy()))
+		assert.ErrorIs(t, appt.UpdateLeaveReason("u2", "Fever", trainStart), ErrAppointmentNotBelongToUser)
+		assert.ErrorIs(t, appt.UpdateLeaveReason("u1", "  ", trainStart), ErrAppointmentLeaveReasonEmpty)
+		assert.ErrorIs(t, appt.UpdateLeaveReason("u1", "Fever", time.Now().Add(-time.Minute)), ErrAppointmentLeaveTooLate)
+
+		require.NoError(t, appt.RejectLeave("c1", "", trainStart))
+		assert.ErrorIs(t, appt.UpdateLeaveReason("u1", "Fever", trainStart), ErrAppointmentLeaveNotApproved)
+		assert.Equal(t, "Sick", appt.LeaveInfo().Reason())
+	})
+}
+
+func TestAppointment_LeaveApproval(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+	trainStart := time.Now().Add(3 * time.Hour)
+
+	t.Run("Approve", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		require.NoError(t, appt.RequestLeave("Sick", trainStart, DefaultBookingPolicy()))
+		assert.Equal(t, StatusConfirmed, appt.Status())
+		assert.True(t, appt.HasPendingLeave())
+		assert.True(t, appt.HasPendingLeaveNotice())
+		assert.ErrorIs(t, appt.AppendLeaveRecord("Sick", trainStart, DefaultBookingPolicy()), ErrAppointmentLeavePending)
+
+		appt.MarkLeaveNotified()
+		assert.False(t, appt.HasPendingLeaveNotice())
+
+		require.NoError(t, appt.ApproveLeave("c1", "早日康復", trainStart))
+		assert.Equal(t, StatusCancelledLeave, appt.Status())
+		assert.Equal(t, LeaveStatusApproved, appt.LeaveInfo().Status())
+		assert.Equal(t, "c1", appt.LeaveInfo().ReviewedBy())
+		assert.Equal(t, "早日康復", appt.LeaveInfo().ReviewComment())
+		// 審核後改通知家長結果
+		assert.True(t, appt.HasPendingLeaveNotice())
+		assert.ErrorIs(t, appt.ApproveLeave("c1", "", trainStart), ErrAppointmentLeaveNotPending)
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		require.NoError(t, appt.RequestLeave("Sick", trainStart, DefaultBookingPolicy()))
+
+		require.NoError(t, appt.RejectLeave("c1", "", trainStart))
+		assert.Equal(t, StatusConfirmed, appt.Status())
+		assert.Equal(t, LeaveStatusRejected, appt.LeaveInfo().Status())
+		assert.False(t, appt.HasPendingLeave())
+		// 駁回後可重新申請
+		assert.NoError(t, appt.RequestLeave("Sick again", trainStart, DefaultBookingPolicy()))
+	})
+
+	t.Run("CancelPending", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		require.NoError(t, appt.RequestLeave("Sick", trainStart, DefaultBookingPolicy()))
+
+		require.NoError(t, appt.CancelLeave("u1"))
+		assert.Equal(t, StatusConfirmed, appt.Status())
+		assert.True(t, appt.LeaveInfo().IsEmpty())
+	})
+
+	t.Run("Fail_NoPendingLeave", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		assert.ErrorIs(t, appt.RejectLeave("c1", "", trainStart), ErrAppointmentLeaveNotPending)
+		assert.ErrorIs(t, appt.ApproveLeave("", "", trainStart), ErrAppointmentInvalid)
+	})
+
+	t.Run("Fail_SessionStarted", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		require.NoError(t, appt.RequestLeave("Sick", trainStart, DefaultBookingPolicy()))
+
+		started := time.Now().Add(-time.Minute)
+		assert.ErrorIs(t, appt.ApproveLeave("c1", "", started), ErrAppointmentLeaveReviewClosed)
+		assert.ErrorIs(t, appt.RejectLeave("c1", "", started), ErrAppointmentLeaveReviewClosed)
+		assert.True(t, appt.HasPendingLeave())
+	})
+
+	t.Run("Expire", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		assert.ErrorIs(t, appt.ExpireLeave(), ErrAppointmentLeaveNotPending)
+		require.NoError(t, appt.RequestLeave("Sick", trainStart, DefaultBookingPolicy()))
+		appt.MarkLeaveNotified()
+
+		require.NoError(t, appt.ExpireLeave())
+		assert.Equal(t, StatusConfirmed, appt.Status())
+		assert.Equal(t, LeaveStatusRejected, appt.LeaveInfo().Status())
+		assert.Equal(t, LeaveReviewerSystem, appt.LeaveInfo().ReviewedBy())
+		// 系統駁回也要通知家長
+		assert.True(t, appt.HasPendingLeaveNotice())
+	})
+}
+
+func TestAppointment_CancelByCoach(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+	now := time.Now()
+
+	t.Run("Success_FromLeave", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		require.NoError(t, appt.AppendLeaveRecord("Sick", now.Add(3*time.Hour), DefaultBookingPolicy()))
+
+		err := appt.CancelByCoach()
+		require.NoError(t, err)
+		assert.Equal(t, StatusCancelledByCoach, appt.Status())
+		assert.False(t, appt.LeaveInfo().IsEmpty())
+		assert.NoError(t, appt.CancelByCoach())
+	})
+
+	t.Run("Fail_Cancelled", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		WithStatus(StatusCancelled)(appt)
+		assert.ErrorIs(t, appt.CancelByCoach(), ErrAppointmentInvalidStatus)
+	})
+
+	t.Run("Fail_CheckInAfterCancel", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		require.NoError(t, appt.CancelByCoach())
+		assert.ErrorIs(t, appt.AdminCheckIn(now, DefaultBookingPolicy()), ErrAppointmentCancelledByCoach)
+		assert.ErrorIs(t, appt.CancelLeave("u1"), ErrAppointmentCancelledByCoach)
+	})
+}
+
+func TestAppointment_OccupiesSeat(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+	start := time.Now().Add(3 * time.Hour)
+
+	appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+	assert.True(t, appt.OccupiesSeat())
+	require.NoError(t, appt.RequestLeave("Sick", start, DefaultBookingPolicy()))
+	assert.True(t, appt.OccupiesSeat(), "待審核的請假仍佔用名額")
+	require.NoError(t, appt.ApproveLeave("coach1", "", start))
+	assert.False(t, appt.OccupiesSeat())
+
+	WithStatus(StatusAbsent)(appt)
+	assert.True(t, appt.OccupiesSeat())
+	WithStatus(StatusCancelledByCoach)(appt)
+	assert.False(t, appt.OccupiesSeat())
+}
+
+func TestAppointment_ReleaseAfterReschedule(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+	start := time.Now().Add(24 * time.Hour)
+	period, _ := NewTimeRange(start, start.Add(time.Hour))
+	later := time.Now().Add(time.Minute)
+	reschedule := NewTrainDateReschedule(period, "Gym", "coach1", later, false)
+
+	t.Run("Success", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		err := appt.ReleaseAfterReschedule("u1", reschedule, start)
+		require.NoError(t, err)
+		assert.Equal(t, StatusCancelled, appt.Status())
+	})
+
+	t.Run("Fail_BookedAfterReschedule", func(t *testing.T) {
+		earlier := NewTrainDateReschedule(period, "Gym", "coach1", time.Now().Add(-time.Hour), false)
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		assert.ErrorIs(t, appt.ReleaseAfterReschedule("u1", earlier, start), ErrAppointmentNotRescheduled)
+	})
+
+	t.Run("Fail_NotUser", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		assert.ErrorIs(t, appt.ReleaseAfterReschedule("u2", reschedule, start), ErrAppointmentNotBelongToUser)
+	})
+}
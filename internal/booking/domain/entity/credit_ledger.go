@@ -0,0 +1,515 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"seanAIgent/internal/util/validator"
+	"sort"
+	"time"
+)
+
+type creditAllocationStatus string
+
+func (s creditAllocationStatus) String() string {
+	return string(s)
+}
+
+const (
+	CreditAllocationReserved creditAllocationStatus = "RESERVED" // 預約時保留
+	CreditAllocationConsumed creditAllocationStatus = "CONSUMED" // 出席或缺席已扣除
+	CreditAllocationReleased creditAllocationStatus = "RELEASED" // 取消或請假已退還
+)
+
+var creditAllocationStatusTrans = map[string]creditAllocationStatus{
+	string(CreditAllocationReserved): CreditAllocationReserved,
+	string(CreditAllocationConsumed): CreditAllocationConsumed,
+	string(CreditAllocationReleased): CreditAllocationReleased,
+}
+
+func CreditAllocationStatusFromString(status string) (creditAllocationStatus, bool) {
+	s, ok := creditAllocationStatusTrans[status]
+	return s, ok
+}
+
+type creditEntryType string
+
+func (t creditEntryType) String() string {
+	return string(t)
+}
+
+const (
+	CreditEntryTopUp   creditEntryType = "TOPUP"   // 購買課程包
+	CreditEntryReserve creditEntryType = "RESERVE" // 預約保留
+	CreditEntryConsume creditEntryType = "CONSUME" // 上課扣除
+	CreditEntryRefund  creditEntryType = "REFUND"  // 退還
+)
+
+var creditEntryTypeTrans = map[string]creditEntryType{
+	string(CreditEntryTopUp):   CreditEntryTopUp,
+	string(CreditEntryReserve): CreditEntryReserve,
+	string(CreditEntryConsume): CreditEntryConsume,
+	string(CreditEntryRefund):  CreditEntryRefund,
+}
+
+func CreditEntryTypeFromString(t string) (creditEntryType, bool) {
+	s, ok := creditEntryTypeTrans[t]
+	return s, ok
+}
+
+// CreditPack 一次購買的課程包，childName 為空代表全家共用
+type CreditPack struct {
+	purchasedAt time.Time
+	expiresAt   time.Time
+	id          string
+	childName   string
+	note        string
+	quantity    int
+}
+
+func NewCreditPack(
+	id, childName string, quantity int, purchasedAt, expiresAt time.Time, note string,
+) (CreditPack, error) {
+	if id == "" {
+		return CreditPack{}, fmt.Errorf("%w: pack id is empty", ErrCreditPackInvalid)
+	}
+	if childName != "" {
+		cleaned, ok := validator.ValidateName(childName, 1, 20)
+		if !ok {
+			return CreditPack{}, fmt.Errorf("%w: child name is invalid", ErrCreditPackInvalid)
+		}
+		childName = cleaned
+	}
+	if quantity <= 0 {
+		return CreditPack{}, fmt.Errorf("%w: quantity must be positive", ErrCreditPackInvalid)
+	}
+	if !expiresAt.After(purchasedAt) {
+		return CreditPack{}, fmt.Errorf("%w: expires at must be after purchased at", ErrCreditPackInvalid)
+	}
+	return CreditPack{
+		id:          id,
+		childName:   childName,
+		quantity:    quantity,
+		purchasedAt: purchasedAt,
+		expiresAt:   expiresAt,
+		note:        note,
+	}, nil
+}
+
+func (p CreditPack) ID() string {
+	return p.id
+}
+
+func (p CreditPack) ChildName() string {
+	return p.childName
+}
+
+func (p CreditPack) Quantity() int {
+	return p.quantity
+}
+
+func (p CreditPack) PurchasedAt() time.Time {
+	return p.purchasedAt
+}
+
+func (p CreditPack) ExpiresAt() time.Time {
+	return p.expiresAt
+}
+
+func (p CreditPack) Note() string {
+	return p.note
+}
+
+// IsExpiredAt 到期日 (不含) 之後即不可使用
+func (p CreditPack) IsExpiredAt(t time.Time) bool {
+	return !t.Before(p.expiresAt)
+}
+
+// CreditAllocation 一筆預約所使用的堂數
+type CreditAllocation struct {
+	updatedAt time.Time
+	apptID    string
+	packID    string
+	childName string
+	status    creditAllocationStatus
+}
+
+func NewCreditAllocation(
+	apptID, packID, childName string, status creditAllocationStatus, updatedAt time.Time,
+) CreditAllocation {
+	return CreditAllocation{
+		apptID:    apptID,
+		packID:    packID,
+		childName: childName,
+		status:    status,
+		updatedAt: updatedAt,
+	}
+}
+
+func (a CreditAllocation) ApptID() string {
+	return a.apptID
+}
+
+func (a CreditAllocation) PackID() string {
+	return a.packID
+}
+
+func (a CreditAllocation) ChildName() string {
+	return a.childName
+}
+
+func (a CreditAllocation) Status() creditAllocationStatus {
+	return a.status
+}
+
+func (a CreditAllocation) UpdatedAt() time.Time {
+	return a.updatedAt
+}
+
+// 保留中或已扣除的堂數皆佔用課程包額度
+func (a CreditAllocation) isHolding() bool {
+	return a.status == CreditAllocationReserved || a.status == CreditAllocationConsumed
+}
+
+// CreditLedgerEntry 堂數異動紀錄
+type CreditLedgerEntry struct {
+	occurredAt time.Time
+	entryType  creditEntryType
+	packID     string
+	apptID     string
+	childName  string
+	note       string
+	amount     int
+}
+
+func NewCreditLedgerEntry(
+	entryType creditEntryType, packID, apptID, childName string, amount int, note string, occurredAt time.Time,
+) CreditLedgerEntry {
+	return CreditLedgerEntry{
+		entryType:  entryType,
+		packID:     packID,
+		apptID:     apptID,
+		childName:  childName,
+		amount:     amount,
+		note:       note,
+		occurredAt: occurredAt,
+	}
+}
+
+func (e CreditLedgerEntry) Type() creditEntryType {
+	return e.entryType
+}
+
+func (e CreditLedgerEntry) PackID() string {
+	return e.packID
+}
+
+func (e CreditLedgerEntry) ApptID() string {
+	return e.apptID
+}
+
+func (e CreditLedgerEntry) ChildName() string {
+	return e.childName
+}
+
+// Amount 對可用堂數的影響，購買與退還為正，保留為負
+func (e CreditLedgerEntry) Amount() int {
+	return e.amount
+}
+
+func (e CreditLedgerEntry) Note() string {
+	return e.note
+}
+
+func (e CreditLedgerEntry) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// CreditBalance 堂數餘額
+type CreditBalance struct {
+	NextExpiry *time.Time // 最近一個尚有餘額的課程包到期日
+	Available  int        // 可預約堂數
+	Reserved   int        // 已預約尚未上課
+	Consumed   int        // 已上課扣除
+	Expired    int        // 過期未使用
+}
+
+// CreditLedger 家長的課程包帳本 (Aggregate Root)，以 LINE 使用者為單位
+type CreditLedger struct {
+	updatedAt   time.Time
+	user        User
+	packs       []CreditPack
+	allocations []CreditAllocation
+	entries     []CreditLedgerEntry
+	version     int
+}
+
+type creditLedgerOpt func(*CreditLedger)
+
+func WithCreditLedgerUser(u User) creditLedgerOpt {
+	return func(l *CreditLedger) {
+		l.user = u
+	}
+}
+
+func WithCreditLedgerPacks(packs []CreditPack) creditLedgerOpt {
+	return func(l *CreditLedger) {
+		l.packs = packs
+	}
+}
+
+func WithCreditLedgerAllocations(allocations []CreditAllocation) creditLedgerOpt {
+	return func(l *CreditLedger) {
+		l.allocations = allocations
+	}
+}
+
+func WithCreditLedgerEntries(entries []CreditLedgerEntry) creditLedgerOpt {
+	return func(l *CreditLedger) {
+		l.entries = entries
+	}
+}
+
+func WithCreditLedgerVersion(version int) creditLedgerOpt {
+	return func(l *CreditLedger) {
+		l.version = version
+	}
+}
+
+func WithCreditLedgerUpdatedAt(t time.Time) creditLedgerOpt {
+	return func(l *CreditLedger) {
+		l.updatedAt = t
+	}
+}
+
+func NewCreditLedger(opts ...creditLedgerOpt) (*CreditLedger, error) {
+	l := &CreditLedger{
+		packs:       make([]CreditPack, 0),
+		allocations: make([]CreditAllocation, 0),
+		entries:     make([]CreditLedgerEntry, 0),
+		updatedAt:   time.Now(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.user.UserID() == "" {
+		return nil, fmt.Errorf("%w: user id is empty", ErrCreditLedgerInvalid)
+	}
+	return l, nil
+}
+
+// TopUp 管理員登錄購買的課程包
+func (l *CreditLedger) TopUp(
+	packID, childName string, quantity int, expiresAt time.Time, note string,
+) (CreditPack, error) {
+	now := time.Now()
+	for _, p := range l.packs {
+		if p.id == packID {
+			return CreditPack{}, fmt.Errorf("%w: pack %s already exists", ErrCreditPackInvalid, packID)
+		}
+	}
+	pack, err := NewCreditPack(packID, childName, quantity, now, expiresAt, note)
+	if err != nil {
+		return CreditPack{}, err
+	}
+	l.packs = append(l.packs, pack)
+	l.appendEntry(CreditEntryTopUp, pack.id, "", pack.childName, quantity, note, now)
+	return pack, nil
+}
+
+// Reserve 預約時保留一堂，優先使用該學員專屬的課程包，其次為全家共用，同類以最早到期者優先
+// 同一筆預約重複保留不會重複扣除
+func (l *CreditLedger) Reserve(apptID, childName string, trainingStart time.Time) error {
+	if apptID == "" {
+		return fmt.Errorf("%w: appointment id is empty", ErrCreditLedgerInvalid)
+	}
+	idx, found := l.findAllocation(apptID)
+	if found && l.allocations[idx].isHolding() {
+		return nil
+	}
+	pack, ok := l.pickPack(childName, trainingStart)
+	if !ok {
+		return ErrCreditInsufficient
+	}
+	now := time.Now()
+	alloc := NewCreditAllocation(apptID, pack.id, childName, CreditAllocationReserved, now)
+	if found {
+		l.allocations[idx] = alloc
+	} else {
+		l.allocations = append(l.allocations, alloc)
+	}
+	l.appendEntry(CreditEntryReserve, pack.id, apptID, childName, -1, "", now)
+	return nil
+}
+
+func (l *CreditLedger) pickPack(childName string, trainingStart time.Time) (CreditPack, bool) {
+	used := l.usedByPack()
+	candidates := make([]CreditPack, 0, len(l.packs))
+	for _, p := range l.packs {
+		if p.childName != "" && p.childName != childName {
+			continue
+		}
+		if p.IsExpiredAt(trainingStart) || p.quantity-used[p.id] <= 0 {
+			continue
+		}
+		candidates = append(candidates, p)
+	}
+	if len(candidates) == 0 {
+		return CreditPack{}, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iOwn, jOwn := candidates[i].childName != "", candidates[j].childName != ""
+		if iOwn != jOwn {
+			return iOwn
+		}
+		return candidates[i].expiresAt.Before(candidates[j].expiresAt)
+	})
+	return candidates[0], true
+}
+
+// Consume 出席或缺席後扣除保留的堂數
+func (l *CreditLedger) Consume(apptID string) error {
+	idx, found := l.findAllocation(apptID)
+	if !found {
+		return ErrCreditAllocationNotFound
+	}
+	alloc := &l.allocations[idx]
+	switch alloc.status {
+	case CreditAllocationConsumed:
+		return nil
+	case CreditAllocationReleased:
+		return ErrCreditAllocationInvalidStatus
+	}
+	now := time.Now()
+	alloc.status = CreditAllocationConsumed
+	alloc.updatedAt = now
+	l.appendEntry(CreditEntryConsume, alloc.packID, apptID, alloc.childName, 0, "", now)
+	return nil
+}
+
+// Refund 退還堂數，保留中與已扣除 (例如教練改為請假) 的堂數皆可退還
+func (l *CreditLedger) Refund(apptID, note string) error {
+	idx, found := l.findAllocation(apptID)
+	if !found {
+		return ErrCreditAllocationNotFound
+	}
+	alloc := &l.allocations[idx]
+	if alloc.status == CreditAllocationReleased {
+		return nil
+	}
+	now := time.Now()
+	alloc.status = CreditAllocationReleased
+	alloc.updatedAt = now
+	l.appendEntry(CreditEntryRefund, alloc.packID, apptID, alloc.childName, 1, note, now)
+	return nil
+}
+
+// HasAllocation 預約是否由課程包支付
+func (l *CreditLedger) HasAllocation(apptID string) bool {
+	_, found := l.findAllocation(apptID)
+	return found
+}
+
+// Remaining 課程包尚未使用的堂數
+func (l *CreditLedger) Remaining(packID string) int {
+	for _, p := range l.packs {
+		if p.id == packID {
+			return p.quantity - l.usedByPack()[packID]
+		}
+	}
+	return 0
+}
+
+// Balance 計算指定時間點的堂數餘額
+func (l *CreditLedger) Balance(at time.Time) CreditBalance {
+	var b CreditBalance
+	used := l.usedByPack()
+	for _, a := range l.allocations {
+		switch a.status {
+		case CreditAllocationReserved:
+			b.Reserved++
+		case CreditAllocationConsumed:
+			b.Consumed++
+		}
+	}
+	for _, p := range l.packs {
+		remain := p.quantity - used[p.id]
+		if remain <= 0 {
+			continue
+		}
+		if p.IsExpiredAt(at) {
+			b.Expired += remain
+			continue
+		}
+		b.Available += remain
+		if b.NextExpiry == nil || p.expiresAt.Before(*b.NextExpiry) {
+			expiry := p.expiresAt
+			b.NextExpiry = &expiry
+		}
+	}
+	return b
+}
+
+func (l *CreditLedger) usedByPack() map[string]int {
+	used := make(map[string]int, len(l.packs))
+	for _, a := range l.allocations {
+		if a.isHolding() {
+			used[a.packID]++
+		}
+	}
+	return used
+}
+
+func (l *CreditLedger) findAllocation(apptID string) (int, bool) {
+	for i, a := range l.allocations {
+		if a.apptID == apptID {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (l *CreditLedger) appendEntry(
+	entryType creditEntryType, packID, apptID, childName string, amount int, note string, at time.Time,
+) {
+	l.entries = append(l.entries, NewCreditLedgerEntry(entryType, packID, apptID, childName, amount, note, at))
+	l.updatedAt = at
+}
+
+// Getter
+func (l *CreditLedger) User() User {
+	return l.user
+}
+
+func (l *CreditLedger) UserID() string {
+	return l.user.UserID()
+}
+
+func (l *CreditLedger) Packs() []CreditPack {
+	return l.packs
+}
+
+func (l *CreditLedger) Allocations() []CreditAllocation {
+	return l.allocations
+}
+
+// Entries 依時間先後排列的異動紀錄
+func (l *CreditLedger) Entries() []CreditLedgerEntry {
+	return l.entries
+}
+
+func (l *CreditLedger) Version() int {
+	return l.version
+}
+
+func (l *CreditLedger) UpdatedAt() time.Time {
+	return l.updatedAt
+}
+
+// Error Definition
+var (
+	ErrCreditLedgerInvalid           = errors.New("CREDIT_LEDGER_INVALID")
+	ErrCreditPackInvalid             = errors.New("CREDIT_PACK_INVALID")
+	ErrCreditInsufficient            = errors.New("CREDIT_INSUFFICIENT")
+	ErrCreditAllocationNotFound      = errors.New("CREDIT_ALLOCATION_NOT_FOUND")
+	ErrCreditAllocationInvalidStatus = errors.New("CREDIT_ALLOCATION_INVALID_STATUS")
+)
@@ -0,0 +1,197 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCreditLedger(t *testing.T) *CreditLedger {
+	user, _ := NewUser("u1", "User")
+	l, err := NewCreditLedger(WithCreditLedgerUser(user))
+	require.NoError(t, err)
+	return l
+}
+
+func TestNewCreditLedger(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		assert.Equal(t, "u1", l.UserID())
+		assert.Equal(t, 0, l.Balance(time.Now()).Available)
+	})
+
+	t.Run("Fail_NoUser", func(t *testing.T) {
+		l, err := NewCreditLedger()
+		assert.ErrorIs(t, err, ErrCreditLedgerInvalid)
+		assert.Nil(t, l)
+	})
+}
+
+func TestCreditLedger_TopUp(t *testing.T) {
+	expires := time.Now().AddDate(0, 3, 0)
+
+	t.Run("Success", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		pack, err := l.TopUp("p1", "", 10, expires, "10 堂")
+		require.NoError(t, err)
+		assert.Equal(t, 10, pack.Quantity())
+		assert.Equal(t, 10, l.Balance(time.Now()).Available)
+		require.Len(t, l.Entries(), 1)
+		assert.Equal(t, CreditEntryTopUp, l.Entries()[0].Type())
+	})
+
+	t.Run("Fail_InvalidQuantity", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, err := l.TopUp("p1", "", 0, expires, "")
+		assert.ErrorIs(t, err, ErrCreditPackInvalid)
+	})
+
+	t.Run("Fail_AlreadyExpired", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, err := l.TopUp("p1", "", 5, time.Now().Add(-time.Hour), "")
+		assert.ErrorIs(t, err, ErrCreditPackInvalid)
+	})
+
+	t.Run("Fail_DuplicatePack", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 5, expires, "")
+		_, err := l.TopUp("p1", "", 5, expires, "")
+		assert.ErrorIs(t, err, ErrCreditPackInvalid)
+	})
+}
+
+func TestCreditLedger_Reserve(t *testing.T) {
+	start := time.Now().Add(48 * time.Hour)
+
+	t.Run("PreferChildPackThenEarliestExpiry", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("family-late", "", 5, time.Now().AddDate(0, 6, 0), "")
+		_, _ = l.TopUp("family-early", "", 5, time.Now().AddDate(0, 1, 0), "")
+		_, _ = l.TopUp("child", "Amy", 1, time.Now().AddDate(0, 6, 0), "")
+
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		require.NoError(t, l.Reserve("a2", "Amy", start))
+		require.NoError(t, l.Reserve("a3", "Bob", start))
+
+		allocs := l.Allocations()
+		require.Len(t, allocs, 3)
+		assert.Equal(t, "child", allocs[0].PackID())
+		assert.Equal(t, "family-early", allocs[1].PackID())
+		assert.Equal(t, "family-early", allocs[2].PackID())
+
+		b := l.Balance(time.Now())
+		assert.Equal(t, 8, b.Available)
+		assert.Equal(t, 3, b.Reserved)
+	})
+
+	t.Run("Idempotent", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 2, time.Now().AddDate(0, 1, 0), "")
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		assert.Equal(t, 1, l.Balance(time.Now()).Available)
+	})
+
+	t.Run("Fail_PackExpiredBeforeTraining", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 2, time.Now().Add(24*time.Hour), "")
+		err := l.Reserve("a1", "Amy", start)
+		assert.ErrorIs(t, err, ErrCreditInsufficient)
+	})
+
+	t.Run("Fail_OtherChildPack", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "Bob", 2, time.Now().AddDate(0, 1, 0), "")
+		err := l.Reserve("a1", "Amy", start)
+		assert.ErrorIs(t, err, ErrCreditInsufficient)
+	})
+
+	t.Run("Fail_UsedUp", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 1, time.Now().AddDate(0, 1, 0), "")
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		err := l.Reserve("a2", "Amy", start)
+		assert.ErrorIs(t, err, ErrCreditInsufficient)
+	})
+}
+
+func TestCreditLedger_ConsumeAndRefund(t *testing.T) {
+	start := time.Now().Add(48 * time.Hour)
+
+	t.Run("Consume", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 2, time.Now().AddDate(0, 1, 0), "")
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		require.NoError(t, l.Consume("a1"))
+		require.NoError(t, l.Consume("a1"))
+
+		b := l.Balance(time.Now())
+		assert.Equal(t, 1, b.Available)
+		assert.Equal(t, 0, b.Reserved)
+		assert.Equal(t, 1, b.Consumed)
+	})
+
+	t.Run("RefundReserved", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 2, time.Now().AddDate(0, 1, 0), "")
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		require.NoError(t, l.Refund("a1", "請假"))
+		require.NoError(t, l.Refund("a1", "請假"))
+		assert.Equal(t, 2, l.Balance(time.Now()).Available)
+	})
+
+	t.Run("RefundConsumed", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 1, time.Now().AddDate(0, 1, 0), "")
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		require.NoError(t, l.Consume("a1"))
+		require.NoError(t, l.Refund("a1", ""))
+		assert.Equal(t, 1, l.Balance(time.Now()).Available)
+	})
+
+	t.Run("ReserveAgainAfterRefund", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 1, time.Now().AddDate(0, 1, 0), "")
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		require.NoError(t, l.Refund("a1", ""))
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		assert.Len(t, l.Allocations(), 1)
+		assert.Equal(t, 0, l.Balance(time.Now()).Available)
+	})
+
+	t.Run("Fail_ConsumeReleased", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		_, _ = l.TopUp("p1", "", 1, time.Now().AddDate(0, 1, 0), "")
+		require.NoError(t, l.Reserve("a1", "Amy", start))
+		require.NoError(t, l.Refund("a1", ""))
+		assert.ErrorIs(t, l.Consume("a1"), ErrCreditAllocationInvalidStatus)
+	})
+
+	t.Run("Fail_NotFound", func(t *testing.T) {
+		l := newTestCreditLedger(t)
+		assert.ErrorIs(t, l.Consume("a1"), ErrCreditAllocationNotFound)
+		assert.ErrorIs(t, l.Refund("a1", ""), ErrCreditAllocationNotFound)
+	})
+}
+
+func TestCreditLedger_BalanceExpired(t *testing.T) {
+	l := newTestCreditLedger(t)
+	_, _ = l.TopUp("p1", "", 3, time.Now().Add(time.Hour), "")
+	_, _ = l.TopUp("p2", "", 2, time.Now().AddDate(0, 1, 0), "")
+
+	b := l.Balance(time.Now().Add(2 * time.Hour))
+	assert.Equal(t, 2, b.Available)
+	assert.Equal(t, 3, b.Expired)
+	require.NotNil(t, b.NextExpiry)
+	assert.Equal(t, l.Packs()[1].ExpiresAt(), *b.NextExpiry)
+}
+
+func TestCreditPolicy_RefundOnLeave(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	p := NewCreditPolicy(12 * time.Hour)
+	assert.True(t, p.RefundOnLeave(start.Add(-13*time.Hour), start))
+	assert.False(t, p.RefundOnLeave(start.Add(-11*time.Hour), start))
+	assert.True(t, DefaultCreditPolicy().RefundOnLeave(start.Add(-3*time.Hour), start))
+}
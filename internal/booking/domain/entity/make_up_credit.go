@@ -0,0 +1,303 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type makeUpCreditStatus string
+
+func (s makeUpCreditStatus) String() string {
+	return string(s)
+}
+
+const (
+	MakeUpCreditAvailable makeUpCreditStatus = "AVAILABLE" // 可預約補課
+	MakeUpCreditRedeemed  makeUpCreditStatus = "REDEEMED"  // 已預約補課
+	MakeUpCreditRevoked   makeUpCreditStatus = "REVOKED"   // 請假已取消，補課資格收回
+)
+
+var makeUpCreditStatusTrans = map[string]makeUpCreditStatus{
+	string(MakeUpCreditAvailable): MakeUpCreditAvailable,
+	string(MakeUpCreditRedeemed):  MakeUpCreditRedeemed,
+	string(MakeUpCreditRevoked):   MakeUpCreditRevoked,
+}
+
+func MakeUpCreditStatusFromString(status string) (makeUpCreditStatus, bool) {
+	s, ok := makeUpCreditStatusTrans[status]
+	return s, ok
+}
+
+// MakeUpCredit 核准請假後發放的補課資格 (Aggregate Root)，一筆請假只會有一筆補課資格
+type MakeUpCredit struct {
+	issuedAt        time.Time
+	expiresAt       time.Time
+	updatedAt       time.Time
+	redeemedAt      *time.Time
+	user            User
+	id              string
+	leaveApptID     string
+	leaveTrainingID string
+	studentID       string
+	childName       string
+	redeemedApptID  string
+	status          makeUpCreditStatus
+	version         int
+}
+
+type makeUpCreditOpt func(*MakeUpCredit)
+
+func WithMakeUpCreditID(id string) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.id = id
+	}
+}
+
+func WithMakeUpCreditUser(u User) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.user = u
+	}
+}
+
+// WithMakeUpCreditLeave 來源請假的預約與場次
+func WithMakeUpCreditLeave(apptID, trainingID string) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.leaveApptID = apptID
+		c.leaveTrainingID = trainingID
+	}
+}
+
+func WithMakeUpCreditStudent(studentID, childName string) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.studentID = studentID
+		c.childName = childName
+	}
+}
+
+func WithMakeUpCreditStatus(status makeUpCreditStatus) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.status = status
+	}
+}
+
+func WithMakeUpCreditIssuedAt(t time.Time) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.issuedAt = t
+	}
+}
+
+func WithMakeUpCreditExpiresAt(t time.Time) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.expiresAt = t
+	}
+}
+
+func WithMakeUpCreditRedeemed(apptID string, at *time.Time) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.redeemedApptID = apptID
+		c.redeemedAt = at
+	}
+}
+
+func WithMakeUpCreditUpdatedAt(t time.Time) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.updatedAt = t
+	}
+}
+
+func WithMakeUpCreditVersion(version int) makeUpCreditOpt {
+	return func(c *MakeUpCredit) {
+		c.version = version
+	}
+}
+
+func NewMakeUpCredit(opts ...makeUpCreditOpt) (*MakeUpCredit, error) {
+	now := time.Now()
+	c := &MakeUpCredit{
+		status:    MakeUpCreditAvailable,
+		issuedAt:  now,
+		updatedAt: now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// IssueMakeUpCredit 依已核准的請假發放補課資格，期限由請假場次的開課時間起算
+func IssueMakeUpCredit(
+	id string, leave *Appointment, leaveTrainingStart time.Time, policy MakeUpPolicy,
+) (*MakeUpCredit, error) {
+	if leave.Status() != StatusCancelledLeave || leave.LeaveInfo().Status() != LeaveStatusApproved {
+		return nil, ErrMakeUpCreditLeaveNotApproved
+	}
+	return NewMakeUpCredit(
+		WithMakeUpCreditID(id),
+		WithMakeUpCreditUser(leave.User()),
+		WithMakeUpCreditLeave(leave.ID(), leave.TrainingID()),
+		WithMakeUpCreditStudent(leave.StudentID(), leave.ChildName()),
+		WithMakeUpCreditExpiresAt(policy.ExpiresAt(leaveTrainingStart)),
+	)
+}
+
+func (c *MakeUpCredit) validate() error {
+	if c.id == "" {
+		return fmt.Errorf("%w: id is empty", ErrMakeUpCreditInvalid)
+	}
+	if c.user.UserID() == "" {
+		return fmt.Errorf("%w: user id is empty", ErrMakeUpCreditInvalid)
+	}
+	if c.leaveApptID == "" || c.leaveTrainingID == "" {
+		return fmt.Errorf("%w: leave appointment is empty", ErrMakeUpCreditInvalid)
+	}
+	if c.childName == "" {
+		return fmt.Errorf("%w: child name is empty", ErrMakeUpCreditInvalid)
+	}
+	if _, ok := makeUpCreditStatusTrans[string(c.status)]; !ok {
+		return fmt.Errorf("%w: unknown status %q", ErrMakeUpCreditInvalid, c.status)
+	}
+	if !c.expiresAt.After(c.issuedAt) {
+		return fmt.Errorf("%w: expires at must be after issued at", ErrMakeUpCreditInvalid)
+	}
+	if c.status == MakeUpCreditRedeemed && c.redeemedApptID == "" {
+		return fmt.Errorf("%w: redeemed appointment is empty", ErrMakeUpCreditInvalid)
+	}
+	return nil
+}
+
+// Redeem 以補課資格預約另一個場次，補課場次需在期限前開課
+func (c *MakeUpCredit) Redeem(apptID, trainingID string, trainingStart time.Time) error {
+	switch c.status {
+	case MakeUpCreditRedeemed:
+		return ErrMakeUpCreditRedeemed
+	case MakeUpCreditRevoked:
+		return ErrMakeUpCreditRevoked
+	}
+	if apptID == "" {
+		return fmt.Errorf("%w: appointment id is empty", ErrMakeUpCreditInvalid)
+	}
+	if trainingID == c.leaveTrainingID {
+		return ErrMakeUpCreditSameTraining
+	}
+	if c.IsExpiredAt(trainingStart) {
+		return ErrMakeUpCreditExpired
+	}
+	now := time.Now()
+	c.status = MakeUpCreditRedeemed
+	c.redeemedApptID = apptID
+	c.redeemedAt = &now
+	c.updatedAt = now
+	return nil
+}
+
+// ReleaseRedemption 補課預約取消或停課時恢復補課資格，非此補課的預約不處理
+func (c *MakeUpCredit) ReleaseRedemption(apptID string) bool {
+	if c.status != MakeUpCreditRedeemed || c.redeemedApptID != apptID {
+		return false
+	}
+	c.status = MakeUpCreditAvailable
+	c.redeemedApptID = ""
+	c.redeemedAt = nil
+	c.updatedAt = time.Now()
+	return true
+}
+
+// Revoke 家長取消請假恢復上課，已預約補課時需先取消補課
+func (c *MakeUpCredit) Revoke() error {
+	switch c.status {
+	case MakeUpCreditRevoked:
+		return nil
+	case MakeUpCreditRedeemed:
+		return ErrMakeUpCreditRedeemed
+	}
+	c.status = MakeUpCreditRevoked
+	c.updatedAt = time.Now()
+	return nil
+}
+
+// Reinstate 撤銷後再次請假時恢復補課資格
+func (c *MakeUpCredit) Reinstate() bool {
+	if c.status != MakeUpCreditRevoked {
+		return false
+	}
+	c.status = MakeUpCreditAvailable
+	c.updatedAt = time.Now()
+	return true
+}
+
+// IsExpiredAt 到期日 (不含) 之後開課的場次不可補課
+func (c *MakeUpCredit) IsExpiredAt(t time.Time) bool {
+	return !t.Before(c.expiresAt)
+}
+
+// IsAvailableAt 尚未使用、未撤銷且未過期
+func (c *MakeUpCredit) IsAvailableAt(t time.Time) bool {
+	return c.status == MakeUpCreditAvailable && !c.IsExpiredAt(t)
+}
+
+// Getter
+func (c *MakeUpCredit) ID() string {
+	return c.id
+}
+
+func (c *MakeUpCredit) User() User {
+	return c.user
+}
+
+func (c *MakeUpCredit) LeaveApptID() string {
+	return c.leaveApptID
+}
+
+func (c *MakeUpCredit) LeaveTrainingID() string {
+	return c.leaveTrainingID
+}
+
+func (c *MakeUpCredit) StudentID() string {
+	return c.studentID
+}
+
+func (c *MakeUpCredit) ChildName() string {
+	return c.childName
+}
+
+func (c *MakeUpCredit) Status() makeUpCreditStatus {
+	return c.status
+}
+
+func (c *MakeUpCredit) RedeemedApptID() string {
+	return c.redeemedApptID
+}
+
+func (c *MakeUpCredit) RedeemedAt() *time.Time {
+	return c.redeemedAt
+}
+
+func (c *MakeUpCredit) IssuedAt() time.Time {
+	return c.issuedAt
+}
+
+func (c *MakeUpCredit) ExpiresAt() time.Time {
+	return c.expiresAt
+}
+
+func (c *MakeUpCredit) UpdatedAt() time.Time {
+	return c.updatedAt
+}
+
+func (c *MakeUpCredit) Version() int {
+	return c.version
+}
+
+// Error Definition
+var (
+	ErrMakeUpCreditInvalid          = errors.New("MAKE_UP_CREDIT_INVALID")
+	ErrMakeUpCreditLeaveNotApproved = errors.New("MAKE_UP_CREDIT_LEAVE_NOT_APPROVED")
+	ErrMakeUpCreditRedeemed         = errors.New("MAKE_UP_CREDIT_REDEEMED")
+	ErrMakeUpCreditRevoked          = errors.New("MAKE_UP_CREDIT_REVOKED")
+	ErrMakeUpCreditExpired          = errors.New("MAKE_UP_CREDIT_EXPIRED")
+	ErrMakeUpCreditSameTraining     = errors.New("MAKE_UP_CREDIT_SAME_TRAINING")
+)
@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLeaveAppt(t *testing.T) (*Appointment, time.Time) {
+	user, _ := NewUser("u1", "User")
+	appt, err := NewAppointment(
+		WithCreateAppt("leave-1", "t1", user, "Child"),
+		WithApptStudentID("s1"),
+	)
+	require.NoError(t, err)
+	start := time.Now().Add(72 * time.Hour)
+	require.NoError(t, appt.AppendLeaveRecord("生病", start, DefaultBookingPolicy()))
+	return appt, start
+}
+
+func newTestMakeUpCredit(t *testing.T) (*MakeUpCredit, time.Time) {
+	appt, start := newTestLeaveAppt(t)
+	c, err := IssueMakeUpCredit("c1", appt, start, DefaultMakeUpPolicy())
+	require.NoError(t, err)
+	return c, start
+}
+
+func TestIssueMakeUpCredit(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, start := newTestMakeUpCredit(t)
+		assert.Equal(t, "c1", c.ID())
+		assert.Equal(t, "u1", c.User().UserID())
+		assert.Equal(t, "leave-1", c.LeaveApptID())
+		assert.Equal(t, "t1", c.LeaveTrainingID())
+		assert.Equal(t, "s1", c.StudentID())
+		assert.Equal(t, "Child", c.ChildName())
+		assert.Equal(t, MakeUpCreditAvailable, c.Status())
+		assert.Equal(t, start.Add(30*24*time.Hour), c.ExpiresAt())
+		assert.True(t, c.IsAvailableAt(time.Now()))
+	})
+
+	t.Run("Fail_NotOnLeave", func(t *testing.T) {
+		user, _ := NewUser("u1", "User")
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		_, err := IssueMakeUpCredit("c1", appt, time.Now(), DefaultMakeUpPolicy())
+		assert.ErrorIs(t, err, ErrMakeUpCreditLeaveNotApproved)
+	})
+}
+
+func TestMakeUpCredit_Redeem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, start := newTestMakeUpCredit(t)
+		require.NoError(t, c.Redeem("a2", "t2", start.Add(7*24*time.Hour)))
+		assert.Equal(t, MakeUpCreditRedeemed, c.Status())
+		assert.Equal(t, "a2", c.RedeemedApptID())
+		assert.NotNil(t, c.RedeemedAt())
+		assert.False(t, c.IsAvailableAt(time.Now()))
+	})
+
+	t.Run("Fail_AlreadyRedeemed", func(t *testing.T) {
+		c, start := newTestMakeUpCredit(t)
+		require.NoError(t, c.Redeem("a2", "t2", start.Add(time.Hour)))
+		err := c.Redeem("a3", "t3", start.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrMakeUpCreditRedeemed)
+	})
+
+	t.Run("Fail_SameTraining", func(t *testing.T) {
+		c, start := newTestMakeUpCredit(t)
+		err := c.Redeem("a2", "t1", start)
+		assert.ErrorIs(t, err, ErrMakeUpCreditSameTraining)
+	})
+
+	t.Run("Fail_Expired", func(t *testing.T) {
+		c, _ := newTestMakeUpCredit(t)
+		err := c.Redeem("a2", "t2", c.ExpiresAt())
+		assert.ErrorIs(t, err, ErrMakeUpCreditExpired)
+		assert.Equal(t, MakeUpCreditAvailable, c.Status())
+	})
+
+	t.Run("Fail_Revoked", func(t *testing.T) {
+		c, start := newTestMakeUpCredit(t)
+		require.NoError(t, c.Revoke())
+		err := c.Redeem("a2", "t2", start.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrMakeUpCreditRevoked)
+	})
+}
+
+func TestMakeUpCredit_ReleaseRedemption(t *testing.T) {
+	c, start := newTestMakeUpCredit(t)
+	require.NoError(t, c.Redeem("a2", "t2", start.Add(time.Hour)))
+
+	assert.False(t, c.ReleaseRedemption("other"))
+	assert.Equal(t, MakeUpCreditRedeemed, c.Status())
+
+	assert.True(t, c.ReleaseRedemption("a2"))
+	assert.Equal(t, MakeUpCreditAvailable, c.Status())
+	assert.Empty(t, c.RedeemedApptID())
+	assert.Nil(t, c.RedeemedAt())
+	assert.False(t, c.ReleaseRedemption("a2"))
+}
+
+func TestMakeUpCredit_Revoke(t *testing.T) {
+	t.Run("RevokeAndReinstate", func(t *testing.T) {
+		c, _ := newTestMakeUpCredit(t)
+		require.NoError(t, c.Revoke())
+		assert.Equal(t, MakeUpCreditRevoked, c.Status())
+		assert.NoError(t, c.Revoke())
+
+		assert.True(t, c.Reinstate())
+		assert.Equal(t, MakeUpCreditAvailable, c.Status())
+		assert.False(t, c.Reinstate())
+	})
+
+	t.Run("Fail_Redeemed", func(t *testing.T) {
+		c, start := newTestMakeUpCredit(t)
+		require.NoError(t, c.Redeem("a2", "t2", start.Add(time.Hour)))
+		assert.ErrorIs(t, c.Revoke(), ErrMakeUpCreditRedeemed)
+	})
+}
+
+func TestNewMakeUpCredit_Invalid(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+	now := time.Now()
+
+	_, err := NewMakeUpCredit(
+		WithMakeUpCreditID("c1"),
+		WithMakeUpCreditUser(user),
+		WithMakeUpCreditLeave("a1", "t1"),
+		WithMakeUpCreditStudent("", "Child"),
+		WithMakeUpCreditIssuedAt(now),
+		WithMakeUpCreditExpiresAt(now.Add(-time.Hour)),
+	)
+	assert.ErrorIs(t, err, ErrMakeUpCreditInvalid)
+
+	_, err = NewMakeUpCredit(
+		WithMakeUpCreditID("c1"),
+		WithMakeUpCreditUser(user),
+		WithMakeUpCreditLeave("a1", "t1"),
+		WithMakeUpCreditStudent("", "Child"),
+		WithMakeUpCreditExpiresAt(now.Add(time.Hour)),
+		WithMakeUpCreditStatus(MakeUpCreditRedeemed),
+	)
+	assert.ErrorIs(t, err, ErrMakeUpCreditInvalid)
+}
@@ -0,0 +1,269 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type paymentMethod string
+
+func (m paymentMethod) String() string {
+	return string(m)
+}
+
+const (
+	PaymentMethodCash         paymentMethod = "CASH"          // 現金
+	PaymentMethodBankTransfer paymentMethod = "BANK_TRANSFER" // 銀行轉帳，需填寫轉帳末五碼或交易序號
+)
+
+var paymentMethodTrans = map[string]paymentMethod{
+	string(PaymentMethodCash):         PaymentMethodCash,
+	string(PaymentMethodBankTransfer): PaymentMethodBankTransfer,
+}
+
+func PaymentMethodFromString(method string) (paymentMethod, bool) {
+	m, ok := paymentMethodTrans[method]
+	return m, ok
+}
+
+const maxPaymentReferenceLen = 50
+
+// Payment 一筆收款紀錄，建立後不可修改，更正需另登一筆
+type Payment struct {
+	paidAt    time.Time
+	createdAt time.Time
+	user      User
+	id        string
+	method    paymentMethod
+	reference string
+	note      string
+	year      int
+	month     int
+	amount    int
+}
+
+type paymentOpt func(*Payment)
+
+func WithPaymentID(id string) paymentOpt {
+	return func(p *Payment) {
+		p.id = id
+	}
+}
+
+func WithPaymentUser(u User) paymentOpt {
+	return func(p *Payment) {
+		p.user = u
+	}
+}
+
+// WithPaymentPeriod 收款所屬的帳務月份
+func WithPaymentPeriod(year, month int) paymentOpt {
+	return func(p *Payment) {
+		p.year = year
+		p.month = month
+	}
+}
+
+func WithPaymentAmount(amount int) paymentOpt {
+	return func(p *Payment) {
+		p.amount = amount
+	}
+}
+
+func WithPaymentMethod(method paymentMethod) paymentOpt {
+	return func(p *Payment) {
+		p.method = method
+	}
+}
+
+func WithPaymentReference(reference string) paymentOpt {
+	return func(p *Payment) {
+		p.reference = strings.TrimSpace(reference)
+	}
+}
+
+func WithPaymentNote(note string) paymentOpt {
+	return func(p *Payment) {
+		p.note = note
+	}
+}
+
+func WithPaymentPaidAt(t time.Time) paymentOpt {
+	return func(p *Payment) {
+		p.paidAt = t
+	}
+}
+
+func WithPaymentCreatedAt(t time.Time) paymentOpt {
+	return func(p *Payment) {
+		p.createdAt = t
+	}
+}
+
+func NewPayment(opts ...paymentOpt) (*Payment, error) {
+	p := &Payment{
+		createdAt: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.paidAt.IsZero() {
+		p.paidAt = p.createdAt
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Payment) validate() error {
+	if p.id == "" {
+		return fmt.Errorf("%w: id is empty", ErrPaymentInvalid)
+	}
+	if p.user.UserID() == "" {
+		return fmt.Errorf("%w: user id is empty", ErrPaymentInvalid)
+	}
+	if p.year < 2024 || p.month < 1 || p.month > 12 {
+		return fmt.Errorf("%w: invalid billing period %d-%d", ErrPaymentInvalid, p.year, p.month)
+	}
+	if p.amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrPaymentInvalid)
+	}
+	if _, ok := paymentMethodTrans[string(p.method)]; !ok {
+		return fmt.Errorf("%w: unknown method %q", ErrPaymentInvalid, p.method)
+	}
+	if p.method == PaymentMethodBankTransfer && p.reference == "" {
+		return fmt.Errorf("%w: bank transfer requires reference", ErrPaymentInvalid)
+	}
+	if len([]rune(p.reference)) > maxPaymentReferenceLen {
+		return fmt.Errorf("%w: reference is too long", ErrPaymentInvalid)
+	}
+	return nil
+}
+
+func (p *Payment) ID() string {
+	return p.id
+}
+
+func (p *Payment) User() User {
+	return p.user
+}
+
+func (p *Payment) Year() int {
+	return p.year
+}
+
+func (p *Payment) Month() int {
+	return p.month
+}
+
+func (p *Payment) Amount() int {
+	return p.amount
+}
+
+func (p *Payment) Method() paymentMethod {
+	return p.method
+}
+
+func (p *Payment) Reference() string {
+	return p.reference
+}
+
+func (p *Payment) Note() string {
+	return p.note
+}
+
+func (p *Payment) PaidAt() time.Time {
+	return p.paidAt
+}
+
+func (p *Payment) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+type billingStatus string
+
+func (s billingStatus) String() string {
+	return string(s)
+}
+
+const (
+	BillingStatusPaid    billingStatus = "PAID"    // 已結清
+	BillingStatusUnpaid  billingStatus = "UNPAID"  // 未結清，尚未超過繳費期限
+	BillingStatusOverdue billingStatus = "OVERDUE" // 超過繳費期限仍未結清
+)
+
+var billingStatusTrans = map[string]billingStatus{
+	string(BillingStatusPaid):    BillingStatusPaid,
+	string(BillingStatusUnpaid):  BillingStatusUnpaid,
+	string(BillingStatusOverdue): BillingStatusOverdue,
+}
+
+func BillingStatusFromString(status string) (billingStatus, bool) {
+	s, ok := billingStatusTrans[status]
+	return s, ok
+}
+
+// BillingStatement 家長單月帳單，依當月統計的上課次數與已登記的收款計算
+type BillingStatement struct {
+	dueAt         time.Time
+	userID        string
+	status        billingStatus
+	payments      []*Payment
+	year          int
+	month         int
+	billableCount int
+	amountDue     int
+	amountPaid    int
+}
+
+func (s *BillingStatement) UserID() string {
+	return s.userID
+}
+
+func (s *BillingStatement) Year() int {
+	return s.year
+}
+
+func (s *BillingStatement) Month() int {
+	return s.month
+}
+
+// BillableCount 計費堂數 (出席 + 缺席)
+func (s *BillingStatement) BillableCount() int {
+	return s.billableCount
+}
+
+func (s *BillingStatement) AmountDue() int {
+	return s.amountDue
+}
+
+func (s *BillingStatement) AmountPaid() int {
+	return s.amountPaid
+}
+
+// Outstanding 尚未繳清的金額，溢繳時為 0
+func (s *BillingStatement) Outstanding() int {
+	if s.amountPaid >= s.amountDue {
+		return 0
+	}
+	return s.amountDue - s.amountPaid
+}
+
+func (s *BillingStatement) DueAt() time.Time {
+	return s.dueAt
+}
+
+func (s *BillingStatement) Status() billingStatus {
+	return s.status
+}
+
+func (s *BillingStatement) Payments() []*Payment {
+	return s.payments
+}
+
+var (
+	ErrPaymentInvalid = errors.New("PAYMENT_INVALID")
+)
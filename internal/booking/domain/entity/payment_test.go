@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPayment(t *testing.T, id string, amount int) *Payment {
+	user, _ := NewUser("u1", "User")
+	p, err := NewPayment(
+		WithPaymentID(id),
+		WithPaymentUser(user),
+		WithPaymentPeriod(2026, 3),
+		WithPaymentAmount(amount),
+		WithPaymentMethod(PaymentMethodCash),
+	)
+	require.NoError(t, err)
+	return p
+}
+
+func TestNewPayment(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+
+	t.Run("Success_BankTransfer", func(t *testing.T) {
+		paidAt := time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+		p, err := NewPayment(
+			WithPaymentID("p1"),
+			WithPaymentUser(user),
+			WithPaymentPeriod(2026, 3),
+			WithPaymentAmount(1200),
+			WithPaymentMethod(PaymentMethodBankTransfer),
+			WithPaymentReference(" 12345 "),
+			WithPaymentPaidAt(paidAt),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "12345", p.Reference())
+		assert.Equal(t, paidAt, p.PaidAt())
+	})
+
+	t.Run("Success_CashDefaultPaidAt", func(t *testing.T) {
+		p := newTestPayment(t, "p1", 500)
+		assert.Equal(t, p.CreatedAt(), p.PaidAt())
+		assert.Equal(t, PaymentMethodCash, p.Method())
+	})
+
+	t.Run("Fail_BankTransferWithoutReference", func(t *testing.T) {
+		p, err := NewPayment(
+			WithPaymentID("p1"),
+			WithPaymentUser(user),
+			WithPaymentPeriod(2026, 3),
+			WithPaymentAmount(1200),
+			WithPaymentMethod(PaymentMethodBankTransfer),
+		)
+		assert.ErrorIs(t, err, ErrPaymentInvalid)
+		assert.Nil(t, p)
+	})
+
+	t.Run("Fail_InvalidFields", func(t *testing.T) {
+		cases := map[string][]paymentOpt{
+			"NoID":      {WithPaymentUser(user), WithPaymentPeriod(2026, 3), WithPaymentAmount(1), WithPaymentMethod(PaymentMethodCash)},
+			"NoUser":    {WithPaymentID("p1"), WithPaymentPeriod(2026, 3), WithPaymentAmount(1), WithPaymentMethod(PaymentMethodCash)},
+			"BadPeriod": {WithPaymentID("p1"), WithPaymentUser(user), WithPaymentPeriod(2026, 13), WithPaymentAmount(1), WithPaymentMethod(PaymentMethodCash)},
+			"NoAmount":  {WithPaymentID("p1"), WithPaymentUser(user), WithPaymentPeriod(2026, 3), WithPaymentMethod(PaymentMethodCash)},
+			"NoMethod":  {WithPaymentID("p1"), WithPaymentUser(user), WithPaymentPeriod(2026, 3), WithPaymentAmount(1)},
+		}
+		for name, opts := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := NewPayment(opts...)
+				assert.ErrorIs(t, err, ErrPaymentInvalid)
+			})
+		}
+	})
+}
+
+func TestBillingPolicy_Statement(t *testing.T) {
+	policy := NewBillingPolicy(300, 10, time.UTC)
+	stat := NewUserMonthlyStat("u1", "User", 2026, 3)
+	stat.AttendedCount = 3
+	stat.AbsentCount = 1
+	stat.LeaveCount = 2
+	beforeDue := time.Date(2026, 4, 10, 23, 0, 0, 0, time.UTC)
+	afterDue := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Unpaid", func(t *testing.T) {
+		s := policy.Statement(stat, nil, beforeDue)
+		assert.Equal(t, 4, s.BillableCount())
+		assert.Equal(t, 1200, s.AmountDue())
+		assert.Equal(t, 1200, s.Outstanding())
+		assert.Equal(t, BillingStatusUnpaid, s.Status())
+	})
+
+	t.Run("Overdue", func(t *testing.T) {
+		s := policy.Statement(stat, []*Payment{newTestPayment(t, "p1", 500)}, afterDue)
+		assert.Equal(t, 500, s.AmountPaid())
+		assert.Equal(t, 700, s.Outstanding())
+		assert.Equal(t, BillingStatusOverdue, s.Status())
+	})
+
+	t.Run("Paid", func(t *testing.T) {
+		payments := []*Payment{newTestPayment(t, "p1", 500), newTestPayment(t, "p2", 800)}
+		s := policy.Statement(stat, payments, afterDue)
+		assert.Equal(t, 1300, s.AmountPaid())
+		assert.Equal(t, 0, s.Outstanding())
+		assert.Equal(t, BillingStatusPaid, s.Status())
+		assert.Len(t, s.Payments(), 2)
+	})
+
+	t.Run("IgnoreOtherPeriod", func(t *testing.T) {
+		other, _ := NewUser("u2", "Other")
+		p, err := NewPayment(
+			WithPaymentID("p3"),
+			WithPaymentUser(other),
+			WithPaymentPeriod(2026, 3),
+			WithPaymentAmount(1200),
+			WithPaymentMethod(PaymentMethodCash),
+		)
+		require.NoError(t, err)
+		s := policy.Statement(stat, []*Payment{p}, beforeDue)
+		assert.Equal(t, 0, s.AmountPaid())
+		assert.Empty(t, s.Payments())
+	})
+
+	t.Run("NothingToBill", func(t *testing.T) {
+		empty := NewUserMonthlyStat("u1", "User", 2026, 3)
+		s := policy.Statement(empty, nil, afterDue)
+		assert.Equal(t, BillingStatusPaid, s.Status())
+	})
+}
+
+func TestBillingPolicy_DueAt(t *testing.T) {
+	policy := NewBillingPolicy(300, 31, time.UTC)
+	assert.Equal(t, defaultBillingDueDay, policy.DueDay())
+	assert.Equal(t, time.Date(2027, 1, 11, 0, 0, 0, 0, time.UTC), policy.DueAt(2026, 12))
+}
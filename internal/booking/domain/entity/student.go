@@ -0,0 +1,202 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"seanAIgent/internal/util/validator"
+)
+
+const (
+	maxStudentNameLen  = 20
+	maxStudentNotesLen = 200
+)
+
+// Student 家長帳號底下的學員 (孩子)，預約以學員 ID 關聯
+type Student struct {
+	createdAt time.Time
+	updatedAt time.Time
+	birthdate *time.Time
+	parent    User
+	id        string
+	name      string
+	nickname  string
+	notes     string
+}
+
+type studentOpt func(*Student)
+
+func WithStudentID(id string) studentOpt {
+	return func(s *Student) {
+		s.id = id
+	}
+}
+
+func WithStudentParent(parent User) studentOpt {
+	return func(s *Student) {
+		s.parent = parent
+	}
+}
+
+func WithStudentName(name string) studentOpt {
+	return func(s *Student) {
+		s.name = NormalizeStudentName(name)
+	}
+}
+
+func WithStudentNickname(nickname string) studentOpt {
+	return func(s *Student) {
+		s.nickname = NormalizeStudentName(nickname)
+	}
+}
+
+func WithStudentBirthdate(birthdate *time.Time) studentOpt {
+	return func(s *Student) {
+		s.birthdate = birthdate
+	}
+}
+
+func WithStudentNotes(notes string) studentOpt {
+	return func(s *Student) {
+		s.notes = validator.SanitizeInput(notes)
+	}
+}
+
+func WithStudentCreatedAt(t time.Time) studentOpt {
+	return func(s *Student) {
+		s.createdAt = t
+	}
+}
+
+func WithStudentUpdatedAt(t time.Time) studentOpt {
+	return func(s *Student) {
+		s.updatedAt = t
+	}
+}
+
+func NewStudent(opts ...studentOpt) (*Student, error) {
+	now := time.Now()
+	s := &Student{
+		createdAt: now,
+		updatedAt: now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NormalizeStudentName 去除前後與重複的空白，舊資料的 childName 比對也使用此規則
+func NormalizeStudentName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func (s *Student) validate() error {
+	if s.id == "" {
+		return fmt.Errorf("%w: id is empty", ErrStudentInvalid)
+	}
+	if s.parent.UserID() == "" {
+		return fmt.Errorf("%w: parent is empty", ErrStudentInvalid)
+	}
+	if _, ok := validator.ValidateName(s.name, 1, maxStudentNameLen); !ok {
+		return fmt.Errorf("%w: name is invalid or too long (max %d chars)", ErrStudentInvalid, maxStudentNameLen)
+	}
+	if _, ok := validator.ValidateName(s.nickname, 1, maxStudentNameLen); s.nickname != "" && !ok {
+		return fmt.Errorf("%w: nickname is invalid or too long", ErrStudentInvalid)
+	}
+	if len([]rune(s.notes)) > maxStudentNotesLen {
+		return fmt.Errorf("%w: notes is too long", ErrStudentInvalid)
+	}
+	if s.birthdate != nil && s.birthdate.After(time.Now()) {
+		return fmt.Errorf("%w: birthdate is in the future", ErrStudentInvalid)
+	}
+	return nil
+}
+
+// UpdateProfile 家長修改學員資料，驗證失敗時不會改動原資料
+func (s *Student) UpdateProfile(name, nickname string, birthdate *time.Time, notes string) error {
+	updated := *s
+	WithStudentName(name)(&updated)
+	WithStudentNickname(nickname)(&updated)
+	WithStudentBirthdate(birthdate)(&updated)
+	WithStudentNotes(notes)(&updated)
+	if err := updated.validate(); err != nil {
+		return err
+	}
+	updated.updatedAt = time.Now()
+	*s = updated
+	return nil
+}
+
+// BelongsTo 是否為該家長的學員
+func (s *Student) BelongsTo(userID string) bool {
+	return s.parent.UserID() == userID
+}
+
+// Matches 姓名或暱稱與輸入相同 (忽略多餘空白)
+func (s *Student) Matches(name string) bool {
+	n := NormalizeStudentName(name)
+	return n != "" && (n == s.name || n == s.nickname)
+}
+
+// FindStudentByName 依姓名或暱稱找出學員，找不到時回傳 nil
+func FindStudentByName(students []*Student, name string) *Student {
+	for _, s := range students {
+		if s.Matches(name) {
+			return s
+		}
+	}
+	return nil
+}
+
+func (s *Student) ID() string {
+	return s.id
+}
+
+func (s *Student) Parent() User {
+	return s.parent
+}
+
+func (s *Student) Name() string {
+	return s.name
+}
+
+func (s *Student) Nickname() string {
+	return s.nickname
+}
+
+// DisplayName 有暱稱時顯示暱稱
+func (s *Student) DisplayName() string {
+	if s.nickname != "" {
+		return s.nickname
+	}
+	return s.name
+}
+
+func (s *Student) Birthdate() *time.Time {
+	return s.birthdate
+}
+
+func (s *Student) Notes() string {
+	return s.notes
+}
+
+func (s *Student) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+func (s *Student) UpdatedAt() time.Time {
+	return s.updatedAt
+}
+
+var (
+	ErrStudentInvalid         = errors.New("STUDENT_INVALID")
+	ErrStudentNotBelongToUser = errors.New("STUDENT_NOT_BELONG_TO_USER")
+	ErrStudentMergeInvalid    = errors.New("STUDENT_MERGE_INVALID")
+	ErrStudentMergeConflict   = errors.New("STUDENT_MERGE_CONFLICT")
+)
@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStudent(t *testing.T) {
+	parent, _ := NewUser("u1", "Parent")
+
+	t.Run("Success_NormalizeName", func(t *testing.T) {
+		s, err := NewStudent(
+			WithStudentID("s1"),
+			WithStudentParent(parent),
+			WithStudentName("  王  小明 "),
+			WithStudentNickname("小明"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "王 小明", s.Name())
+		assert.Equal(t, "小明", s.DisplayName())
+		assert.True(t, s.BelongsTo("u1"))
+		assert.False(t, s.BelongsTo("u2"))
+	})
+
+	t.Run("Fail_MissingParent", func(t *testing.T) {
+		_, err := NewStudent(WithStudentID("s1"), WithStudentName("小明"))
+		assert.ErrorIs(t, err, ErrStudentInvalid)
+	})
+
+	t.Run("Fail_EmptyName", func(t *testing.T) {
+		_, err := NewStudent(WithStudentID("s1"), WithStudentParent(parent), WithStudentName("   "))
+		assert.ErrorIs(t, err, ErrStudentInvalid)
+	})
+
+	t.Run("Fail_FutureBirthdate", func(t *testing.T) {
+		future := time.Now().AddDate(1, 0, 0)
+		_, err := NewStudent(
+			WithStudentID("s1"),
+			WithStudentParent(parent),
+			WithStudentName("小明"),
+			WithStudentBirthdate(&future),
+		)
+		assert.ErrorIs(t, err, ErrStudentInvalid)
+	})
+}
+
+func TestStudent_UpdateProfile(t *testing.T) {
+	parent, _ := NewUser("u1", "Parent")
+	s, err := NewStudent(WithStudentID("s1"), WithStudentParent(parent), WithStudentName("小明"))
+	require.NoError(t, err)
+
+	t.Run("Fail_KeepOriginal", func(t *testing.T) {
+		err := s.UpdateProfile("", "", nil, "")
+		assert.ErrorIs(t, err, ErrStudentInvalid)
+		assert.Equal(t, "小明", s.Name())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		birthdate := time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, s.UpdateProfile("王小明", "明明", &birthdate, "花生過敏"))
+		assert.Equal(t, "王小明", s.Name())
+		assert.Equal(t, "明明", s.Nickname())
+		assert.Equal(t, "花生過敏", s.Notes())
+		assert.Equal(t, &birthdate, s.Birthdate())
+	})
+}
+
+func TestFindStudentByName(t *testing.T) {
+	parent, _ := NewUser("u1", "Parent")
+	s1, _ := NewStudent(WithStudentID("s1"), WithStudentParent(parent), WithStudentName("王小明"), WithStudentNickname("小明"))
+	s2, _ := NewStudent(WithStudentID("s2"), WithStudentParent(parent), WithStudentName("王小華"))
+	students := []*Student{s1, s2}
+
+	assert.Equal(t, s1, FindStudentByName(students, "小明 "))
+	assert.Equal(t, s2, FindStudentByName(students, "王小華"))
+	assert.Nil(t, FindStudentByName(students, "小華"))
+	assert.Nil(t, FindStudentByName(students, ""))
+}
+
+func TestAppointment_LinkStudent(t *testing.T) {
+	parent, _ := NewUser("u1", "Parent")
+	other, _ := NewUser("u2", "Other")
+	appt, _ := NewAppointment(WithCreateAppt("a1", "t1", parent, "小明 "))
+
+	t.Run("Fail_OtherParent", func(t *testing.T) {
+		s, _ := NewStudent(WithStudentID("s2"), WithStudentParent(other), WithStudentName("小明"))
+		assert.ErrorIs(t, appt.LinkStudent(s), ErrStudentNotBelongToUser)
+		assert.Empty(t, appt.StudentID())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		s, _ := NewStudent(WithStudentID("s1"), WithStudentParent(parent), WithStudentName("王小明"))
+		require.NoError(t, appt.LinkStudent(s))
+		assert.Equal(t, "s1", appt.StudentID())
+		assert.Equal(t, "王小明", appt.ChildName())
+	})
+}
@@ -0,0 +1,235 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const maxTeamNameLen = 30
+
+// Team 校隊或班級，綁定團隊的場次只開放成員預約，團隊教練的管理查詢只看得到自己團隊的資料
+type Team struct {
+	createdAt     time.Time
+	updatedAt     time.Time
+	headCoach     User
+	id            string
+	name          string
+	assistants    []User
+	memberIDs     []string // 學員 ID
+	leaveApproval bool     // 請假需教練核准後才釋出名額
+}
+
+type teamOpt func(*Team)
+
+func WithTeamID(id string) teamOpt {
+	return func(t *Team) {
+		t.id = id
+	}
+}
+
+func WithTeamName(name string) teamOpt {
+	return func(t *Team) {
+		t.name = strings.TrimSpace(name)
+	}
+}
+
+func WithTeamHeadCoach(coach User) teamOpt {
+	return func(t *Team) {
+		t.headCoach = coach
+	}
+}
+
+func WithTeamAssistants(assistants ...User) teamOpt {
+	return func(t *Team) {
+		t.assistants = assistants
+	}
+}
+
+func WithTeamMemberIDs(studentIDs ...string) teamOpt {
+	return func(t *Team) {
+		t.memberIDs = studentIDs
+	}
+}
+
+func WithTeamLeaveApproval(required bool) teamOpt {
+	return func(t *Team) {
+		t.leaveApproval = required
+	}
+}
+
+func WithTeamCreatedAt(createdAt time.Time) teamOpt {
+	return func(t *Team) {
+		t.createdAt = createdAt
+	}
+}
+
+func WithTeamUpdatedAt(updatedAt time.Time) teamOpt {
+	return func(t *Team) {
+		t.updatedAt = updatedAt
+	}
+}
+
+func NewTeam(opts ...teamOpt) (*Team, error) {
+	now := time.Now()
+	t := &Team{
+		createdAt: now,
+		updatedAt: now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Team) validate() error {
+	if t.id == "" {
+		return fmt.Errorf("%w: id is empty", ErrTeamInvalid)
+	}
+	if t.name == "" || len([]rune(t.name)) > maxTeamNameLen {
+		return fmt.Errorf("%w: name is empty or too long (max %d chars)", ErrTeamInvalid, maxTeamNameLen)
+	}
+	if t.headCoach.UserID() == "" {
+		return fmt.Errorf("%w: head coach is empty", ErrTeamInvalid)
+	}
+	return nil
+}
+
+// Rename 修改團隊名稱
+func (t *Team) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxTeamNameLen {
+		return fmt.Errorf("%w: name is empty or too long (max %d chars)", ErrTeamInvalid, maxTeamNameLen)
+	}
+	t.name = name
+	t.updatedAt = time.Now()
+	return nil
+}
+
+// ChangeHeadCoach 更換總教練，原本是助理教練時從助理名單移除
+func (t *Team) ChangeHeadCoach(coach User) error {
+	if coach.UserID() == "" {
+		return fmt.Errorf("%w: head coach is empty", ErrTeamInvalid)
+	}
+	t.headCoach = coach
+	t.assistants = slices.DeleteFunc(t.assistants, func(u User) bool {
+		return u.UserID() == coach.UserID()
+	})
+	t.updatedAt = time.Now()
+	return nil
+}
+
+// AddAssistant 新增助理教練，已是教練時不重複加入
+func (t *Team) AddAssistant(coach User) error {
+	if coach.UserID() == "" {
+		return fmt.Errorf("%w: assistant is empty", ErrTeamInvalid)
+	}
+	if t.IsCoach(coach.UserID()) {
+		return nil
+	}
+	t.assistants = append(t.assistants, coach)
+	t.updatedAt = time.Now()
+	return nil
+}
+
+func (t *Team) RemoveAssistant(userID string) {
+	t.assistants = slices.DeleteFunc(t.assistants, func(u User) bool {
+		return u.UserID() == userID
+	})
+	t.updatedAt = time.Now()
+}
+
+// AddMembers 加入成員學員，已是成員時略過
+func (t *Team) AddMembers(students ...*Student) {
+	for _, s := range students {
+		if !t.HasMember(s.ID()) {
+			t.memberIDs = append(t.memberIDs, s.ID())
+		}
+	}
+	t.updatedAt = time.Now()
+}
+
+func (t *Team) RemoveMember(studentID string) {
+	t.memberIDs = slices.DeleteFunc(t.memberIDs, func(id string) bool {
+		return id == studentID
+	})
+	t.updatedAt = time.Now()
+}
+
+// SetLeaveApproval 設定團隊場次的請假是否需教練核准
+func (t *Team) SetLeaveApproval(required bool) {
+	if t.leaveApproval == required {
+		return
+	}
+	t.leaveApproval = required
+	t.updatedAt = time.Now()
+}
+
+func (t *Team) HasMember(studentID string) bool {
+	return studentID != "" && slices.Contains(t.memberIDs, studentID)
+}
+
+// CheckMembers 綁定團隊的場次只有成員可以預約
+func (t *Team) CheckMembers(students ...*Student) error {
+	for _, s := range students {
+		if !t.HasMember(s.ID()) {
+			return fmt.Errorf("%w: %s is not a member of %s", ErrTeamMemberOnly, s.Name(), t.name)
+		}
+	}
+	return nil
+}
+
+// IsCoach 總教練或助理教練
+func (t *Team) IsCoach(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if t.headCoach.UserID() == userID {
+		return true
+	}
+	return slices.ContainsFunc(t.assistants, func(u User) bool {
+		return u.UserID() == userID
+	})
+}
+
+func (t *Team) ID() string {
+	return t.id
+}
+
+func (t *Team) Name() string {
+	return t.name
+}
+
+func (t *Team) HeadCoach() User {
+	return t.headCoach
+}
+
+func (t *Team) Assistants() []User {
+	return slices.Clone(t.assistants)
+}
+
+func (t *Team) MemberIDs() []string {
+	return slices.Clone(t.memberIDs)
+}
+
+func (t *Team) RequiresLeaveApproval() bool {
+	return t.leaveApproval
+}
+
+func (t *Team) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+func (t *Team) UpdatedAt() time.Time {
+	return t.updatedAt
+}
+
+var (
+	ErrTeamInvalid    = errors.New("TEAM_INVALID")
+	ErrTeamMemberOnly = errors.New("TEAM_MEMBER_ONLY")
+)
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTeam(t *testing.T) {
+	coach, _ := NewUser("c1", "Coach")
+
+	t.Run("Success", func(t *testing.T) {
+		team, err := NewTeam(WithTeamID("t1"), WithTeamName("  U12 校隊 "), WithTeamHeadCoach(coach))
+		require.NoError(t, err)
+		assert.Equal(t, "U12 校隊", team.Name())
+		assert.True(t, team.IsCoach("c1"))
+		assert.False(t, team.IsCoach(""))
+	})
+
+	t.Run("Fail_EmptyName", func(t *testing.T) {
+		_, err := NewTeam(WithTeamID("t1"), WithTeamName(" "), WithTeamHeadCoach(coach))
+		assert.ErrorIs(t, err, ErrTeamInvalid)
+	})
+
+	t.Run("Fail_MissingHeadCoach", func(t *testing.T) {
+		_, err := NewTeam(WithTeamID("t1"), WithTeamName("U12"))
+		assert.ErrorIs(t, err, ErrTeamInvalid)
+	})
+}
+
+func TestTeam_Coaches(t *testing.T) {
+	coach, _ := NewUser("c1", "Coach")
+	assistant, _ := NewUser("c2", "Assistant")
+	team, err := NewTeam(WithTeamID("t1"), WithTeamName("U12"), WithTeamHeadCoach(coach))
+	require.NoError(t, err)
+
+	require.NoError(t, team.AddAssistant(assistant))
+	require.NoError(t, team.AddAssistant(assistant))
+	require.NoError(t, team.AddAssistant(coach))
+	assert.Len(t, team.Assistants(), 1)
+	assert.True(t, team.IsCoach("c2"))
+
+	// 助理升為總教練時從助理名單移除
+	require.NoError(t, team.ChangeHeadCoach(assistant))
+	assert.Equal(t, "c2", team.HeadCoach().UserID())
+	assert.Empty(t, team.Assistants())
+	assert.False(t, team.IsCoach("c1"))
+}
+
+func TestTeam_CheckMembers(t *testing.T) {
+	coach, _ := NewUser("c1", "Coach")
+	parent, _ := NewUser("u1", "Parent")
+	member, _ := NewStudent(WithStudentID("s1"), WithStudentParent(parent), WithStudentName("小明"))
+	other, _ := NewStudent(WithStudentID("s2"), WithStudentParent(parent), WithStudentName("小華"))
+	team, err := NewTeam(WithTeamID("t1"), WithTeamName("U12"), WithTeamHeadCoach(coach))
+	require.NoError(t, err)
+
+	team.AddMembers(member, member)
+	assert.Equal(t, []string{"s1"}, team.MemberIDs())
+	assert.NoError(t, team.CheckMembers(member))
+	assert.ErrorIs(t, team.CheckMembers(member, other), ErrTeamMemberOnly)
+
+	team.RemoveMember("s1")
+	assert.ErrorIs(t, team.CheckMembers(member), ErrTeamMemberOnly)
+}
+
+func TestTeam_LeaveApproval(t *testing.T) {
+	coach, _ := NewUser("c1", "Coach")
+	team, err := NewTeam(WithTeamID("t1"), WithTeamName("U12"), WithTeamHeadCoach(coach))
+	require.NoError(t, err)
+	assert.False(t, team.RequiresLeaveApproval())
+
+	team.SetLeaveApproval(true)
+	assert.True(t, team.RequiresLeaveApproval())
+}
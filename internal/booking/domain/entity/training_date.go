@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"seanAIgent/internal/util/timeutil"
+	"seanAIgent/internal/util/validator"
 )
 
 type TrainDateStatus string
@@ -13,6 +14,8 @@ type TrainDateStatus string
 const (
 	TrainDateStatusActive   TrainDateStatus = "ACTIVE"
 	TrainDateStatusInactive TrainDateStatus = "INACTIVE"
+	// TrainDateStatusCancelled 教練停課，場次保留供家長查詢
+	TrainDateStatusCancelled TrainDateStatus = "CANCELLED"
 )
 
 type trainDateOpt func(*TrainDate) error
@@ -87,6 +90,46 @@ func WithTrainDateUpdatedAt(updatedAt time.Time) trainDateOpt {
 	}
 }
 
+// WithTrainDateSeriesID 由週期性課程產生的場次
+func WithTrainDateSeriesID(seriesID string) trainDateOpt {
+	return func(td *TrainDate) error {
+		td.seriesID = seriesID
+		return nil
+	}
+}
+
+// WithTrainDateBookingPolicy 場次的預約規則，未設定時使用 DefaultBookingPolicy
+func WithTrainDateBookingPolicy(policy BookingPolicy) trainDateOpt {
+	return func(td *TrainDate) error {
+		td.bookingPolicy = policy
+		return nil
+	}
+}
+
+// WithTrainDateTeamID 綁定團隊的場次只開放團隊成員預約
+func WithTrainDateTeamID(teamID string) trainDateOpt {
+	return func(td *TrainDate) error {
+		td.teamID = teamID
+		return nil
+	}
+}
+
+// WithTrainDateReschedule 最近一次改期的紀錄
+func WithTrainDateReschedule(reschedule TrainDateReschedule) trainDateOpt {
+	return func(td *TrainDate) error {
+		td.reschedule = reschedule
+		return nil
+	}
+}
+
+// WithTrainDateCancellation 停課資訊，需搭配 TrainDateStatusCancelled
+func WithTrainDateCancellation(cancellation TrainDateCancellation) trainDateOpt {
+	return func(td *TrainDate) error {
+		td.cancellation = cancellation
+		return nil
+	}
+}
+
 func WithBasicTrainDate(id, userID, location string, maxCapacity int, period TimeRange) trainDateOpt {
 	return func(td *TrainDate) error {
 		td.id = id
@@ -121,10 +164,15 @@ func NewTrainDate(opts ...trainDateOpt) (*TrainDate, error) {
 
 type TrainDate struct {
 	period            TimeRange
+	bookingPolicy     BookingPolicy
+	cancellation      TrainDateCancellation
+	reschedule        TrainDateReschedule
 	createdAt         time.Time
 	updatedAt         time.Time
 	id                string
 	userID            string
+	seriesID          string
+	teamID            string
 	location          string
 	timezone          string
 	status            TrainDateStatus
@@ -140,8 +188,95 @@ func (s *TrainDate) Delete() error {
 	return nil
 }
 
+// Cancel 教練停課，課程結束前皆可停課；預約需另行轉為停課狀態
+func (s *TrainDate) Cancel(reason, cancelledBy string) error {
+	reason = validator.SanitizeInput(reason)
+	if reason == "" {
+		return ErrTrainingCancelReasonEmpty
+	}
+	if s.IsCancelled() {
+		return ErrTrainingCancelled
+	}
+	now := time.Now()
+	if now.After(s.period.end) {
+		return ErrTrainingOver
+	}
+	s.status = TrainDateStatusCancelled
+	s.cancellation = TrainDateCancellation{
+		cancelledAt: now,
+		reason:      reason,
+		cancelledBy: cancelledBy,
+	}
+	s.updatedAt = now
+	return nil
+}
+
+// MarkCancelNoticeSent 已推播停課通知，避免重複發送
+func (s *TrainDate) MarkCancelNoticeSent() {
+	s.cancellation.noticeSent = true
+	s.updatedAt = time.Now()
+}
+
+// HasPendingCancelNotice 已停課但尚未通知家長
+func (s *TrainDate) HasPendingCancelNotice() bool {
+	return s.IsCancelled() && !s.cancellation.noticeSent
+}
+
+func (s *TrainDate) IsCancelled() bool {
+	return s.status == TrainDateStatusCancelled
+}
+
+// Reschedule 教練調整時間或地點，已預約的學員保留；location 留空代表不改地點
+func (s *TrainDate) Reschedule(location string, period TimeRange, rescheduledBy string) error {
+	if s.IsCancelled() {
+		return ErrTrainingCancelled
+	}
+	now := time.Now()
+	if now.After(s.period.start) || now.After(period.start) {
+		return ErrTrainingOver
+	}
+	location = validator.SanitizeInput(location)
+	if location == "" {
+		location = s.location
+	}
+	if location == s.location && period.start.Equal(s.period.start) && period.end.Equal(s.period.end) {
+		return ErrTrainingRescheduleNoChange
+	}
+	// 尚未通知家長前再次改期，通知內容仍以原本的時間為準
+	if !s.HasPendingRescheduleNotice() {
+		s.reschedule.previousPeriod = s.period
+		s.reschedule.previousLocation = s.location
+	}
+	s.reschedule.rescheduledAt = now
+	s.reschedule.rescheduledBy = rescheduledBy
+	s.reschedule.noticeSent = false
+	s.location = location
+	s.period = period
+	s.updatedAt = now
+	return nil
+}
+
+// MarkRescheduleNoticeSent 已推播改期通知，避免重複發送
+func (s *TrainDate) MarkRescheduleNoticeSent() {
+	s.reschedule.noticeSent = true
+	s.updatedAt = time.Now()
+}
+
+// HasPendingRescheduleNotice 已改期但尚未通知家長
+func (s *TrainDate) HasPendingRescheduleNotice() bool {
+	return !s.reschedule.IsZero() && !s.reschedule.noticeSent
+}
+
+// IsRescheduledAfter 場次是否在 t 之後改期，用於判斷預約是否可免責取消
+func (s *TrainDate) IsRescheduledAfter(t time.Time) bool {
+	return !s.reschedule.IsZero() && s.reschedule.rescheduledAt.After(t)
+}
+
 // ReserveSpot 預約名額 (關鍵行為)
 func (s *TrainDate) ReserveSpot(count int) error {
+	if s.IsCancelled() {
+		return ErrTrainingCancelled
+	}
 	if count <= 0 {
 		return ErrTrainingReserveCountInvalid
 	}
@@ -172,6 +307,50 @@ func (s *TrainDate) ReleaseSpot(count int) error {
 	return nil
 }
 
+// UpdateDetails 修改場次內容，已有人預約時不可改時間，名額也不可少於已預約人數
+func (s *TrainDate) UpdateDetails(location string, maxCapacity int, period TimeRange) error {
+	if s.IsCancelled() {
+		return ErrTrainingCancelled
+	}
+	booked := s.maxCapacity - s.availableCapacity
+	if maxCapacity < booked || maxCapacity <= 0 {
+		return ErrTrainingCapacityBelowBooked
+	}
+	if booked > 0 && (!period.start.Equal(s.period.start) || !period.end.Equal(s.period.end)) {
+		return ErrTrainingHasAppointments
+	}
+	s.location = location
+	s.maxCapacity = maxCapacity
+	s.availableCapacity = maxCapacity - booked
+	s.period = period
+	s.updatedAt = time.Now()
+	return nil
+}
+
+// UpdateBookingPolicy 修改場次的預約規則，只影響之後的操作
+func (s *TrainDate) UpdateBookingPolicy(policy BookingPolicy) {
+	s.bookingPolicy = policy
+	s.updatedAt = time.Now()
+}
+
+// AttachToSeries 將場次歸屬到指定的週期性課程
+func (s *TrainDate) AttachToSeries(seriesID string) {
+	s.seriesID = seriesID
+	s.updatedAt = time.Now()
+}
+
+// DetachFromSeries 單堂修改後不再跟隨系列異動
+func (s *TrainDate) DetachFromSeries() {
+	s.seriesID = ""
+	s.updatedAt = time.Now()
+}
+
+// AssignTeam 綁定團隊，傳入空字串代表開放所有人預約
+func (s *TrainDate) AssignTeam(teamID string) {
+	s.teamID = teamID
+	s.updatedAt = time.Now()
+}
+
 // CanVerifyAttendance 檢查目前是否處於教練點名時間 (前10分鐘)
 func (s *TrainDate) CanVerifyAttendance() bool {
 	now := time.Now()
@@ -206,6 +385,14 @@ func (p *TrainDate) UserID() string {
 	return p.userID
 }
 
+func (p *TrainDate) SeriesID() string {
+	return p.seriesID
+}
+
+func (p *TrainDate) TeamID() string {
+	return p.teamID
+}
+
 func (p *TrainDate) Location() string {
 	return p.location
 }
@@ -218,10 +405,29 @@ func (p *TrainDate) AvailableCapacity() int {
 	return p.availableCapacity
 }
 
+// CapacityDrift 記錄的剩餘名額與實際佔用名額推算值的差距，正數代表名額多算；
+// 行政人員可超額補登，推算值可能為負數
+func (p *TrainDate) CapacityDrift(occupied int) int {
+	return p.availableCapacity - (p.maxCapacity - occupied)
+}
+
 func (p *TrainDate) Period() TimeRange {
 	return p.period
 }
 
+// HasBookingPolicy 場次是否另行設定預約規則
+func (p *TrainDate) HasBookingPolicy() bool {
+	return !p.bookingPolicy.IsZero()
+}
+
+// BookingPolicy 場次的預約規則，舊資料未設定時回傳預設規則
+func (p *TrainDate) BookingPolicy() BookingPolicy {
+	if p.bookingPolicy.IsZero() {
+		return DefaultBookingPolicy()
+	}
+	return p.bookingPolicy
+}
+
 func (p *TrainDate) Timezone() string {
 	return p.timezone
 }
@@ -230,6 +436,14 @@ func (p *TrainDate) Status() TrainDateStatus {
 	return p.status
 }
 
+func (p *TrainDate) Cancellation() TrainDateCancellation {
+	return p.cancellation
+}
+
+func (p *TrainDate) LastReschedule() TrainDateReschedule {
+	return p.reschedule
+}
+
 func (p *TrainDate) CreatedAt() time.Time {
 	return p.createdAt
 }
@@ -249,4 +463,91 @@ var (
 	ErrTrainingReleaseCountInvalid      = errors.New("TRAINING_RELEASE_COUNT_INVALID")
 	ErrTrainingNotFound                 = errors.New("TRAINING_NOT_FOUND")
 	ErrTrainingHasAppointments          = errors.New("TRAINING_HAS_APPOINTMENTS")
+	ErrTrainingCapacityBelowBooked      = errors.New("TRAINING_CAPACITY_BELOW_BOOKED")
+	ErrBookingPolicyInvalid             = errors.New("BOOKING_POLICY_INVALID")
+	ErrTrainingCancelled                = errors.New("TRAINING_CANCELLED")
+	ErrTrainingCancelReasonEmpty        = errors.New("TRAINING_CANCEL_REASON_EMPTY")
+	ErrTrainingRescheduleNoChange       = errors.New("TRAINING_RESCHEDULE_NO_CHANGE")
 )
+
+// TrainDateCancellation 停課原因與通知狀態
+type TrainDateCancellation struct {
+	cancelledAt time.Time
+	reason      string
+	cancelledBy string
+	noticeSent  bool
+}
+
+func NewTrainDateCancellation(reason, cancelledBy string, cancelledAt time.Time, noticeSent bool) TrainDateCancellation {
+	return TrainDateCancellation{
+		cancelledAt: cancelledAt,
+		reason:      reason,
+		cancelledBy: cancelledBy,
+		noticeSent:  noticeSent,
+	}
+}
+
+func (c TrainDateCancellation) IsZero() bool {
+	return c.cancelledAt.IsZero()
+}
+
+func (c TrainDateCancellation) Reason() string {
+	return c.reason
+}
+
+func (c TrainDateCancellation) CancelledBy() string {
+	return c.cancelledBy
+}
+
+func (c TrainDateCancellation) CancelledAt() time.Time {
+	return c.cancelledAt
+}
+
+func (c TrainDateCancellation) NoticeSent() bool {
+	return c.noticeSent
+}
+
+// TrainDateReschedule 改期前的時間地點與通知狀態
+type TrainDateReschedule struct {
+	previousPeriod   TimeRange
+	rescheduledAt    time.Time
+	previousLocation string
+	rescheduledBy    string
+	noticeSent       bool
+}
+
+func NewTrainDateReschedule(
+	previousPeriod TimeRange, previousLocation, rescheduledBy string, rescheduledAt time.Time, noticeSent bool,
+) TrainDateReschedule {
+	return TrainDateReschedule{
+		previousPeriod:   previousPeriod,
+		rescheduledAt:    rescheduledAt,
+		previousLocation: previousLocation,
+		rescheduledBy:    rescheduledBy,
+		noticeSent:       noticeSent,
+	}
+}
+
+func (r TrainDateReschedule) IsZero() bool {
+	return r.rescheduledAt.IsZero()
+}
+
+func (r TrainDateReschedule) PreviousPeriod() TimeRange {
+	return r.previousPeriod
+}
+
+func (r TrainDateReschedule) PreviousLocation() string {
+	return r.previousLocation
+}
+
+func (r TrainDateReschedule) RescheduledBy() string {
+	return r.rescheduledBy
+}
+
+func (r TrainDateReschedule) RescheduledAt() time.Time {
+	return r.rescheduledAt
+}
+
+func (r TrainDateReschedule) NoticeSent() bool {
+	return r.noticeSent
+}
@@ -99,6 +99,26 @@ func TestTrainDate_ReleaseSpot(t *testing.T) {
 	})
 }
 
+func TestTrainDate_CapacityDrift(t *testing.T) {
+	now := time.Now()
+	period, _ := NewTimeRange(now.Add(time.Hour), now.Add(2*time.Hour))
+	td, _ := NewTrainDate(WithBasicTrainDate("id1", "coach1", "Gym", 10, period))
+	require.NoError(t, td.ReserveSpot(3)) // 7
+
+	assert.Equal(t, 0, td.CapacityDrift(3))
+	// 預約寫入失敗但名額已扣除
+	assert.Equal(t, -1, td.CapacityDrift(2))
+	// 取消後名額未歸還
+	assert.Equal(t, 1, td.CapacityDrift(4))
+	// 超額補登
+	overbooked, err := NewTrainDate(
+		WithBasicTrainDate("id2", "coach1", "Gym", 10, period),
+		WithTrainDateAvailableCapacity(-2),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 0, overbooked.CapacityDrift(12))
+}
+
 func TestTrainDate_CanVerifyAttendance(t *testing.T) {
 	now := time.Now()
 
@@ -151,3 +171,126 @@ func TestTrainDate_Delete(t *testing.T) {
 		assert.ErrorIs(t, err, ErrTrainingHasAppointments)
 	})
 }
+
+func TestTrainDate_UpdateDetails(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	period, _ := NewTimeRange(start, start.Add(time.Hour))
+	moved, _ := NewTimeRange(start.Add(time.Hour), start.Add(2*time.Hour))
+
+	t.Run("Success_NoAppointments", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "l", 10, period))
+		err := td.UpdateDetails("new", 8, moved)
+		require.NoError(t, err)
+		assert.Equal(t, "new", td.Location())
+		assert.Equal(t, 8, td.MaxCapacity())
+		assert.Equal(t, 8, td.AvailableCapacity())
+		assert.Equal(t, moved, td.Period())
+	})
+
+	t.Run("Success_KeepBookedCount", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "l", 10, period))
+		require.NoError(t, td.ReserveSpot(3))
+		err := td.UpdateDetails("l", 5, period)
+		require.NoError(t, err)
+		assert.Equal(t, 2, td.AvailableCapacity())
+	})
+
+	t.Run("Fail_CapacityBelowBooked", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "l", 10, period))
+		require.NoError(t, td.ReserveSpot(3))
+		err := td.UpdateDetails("l", 2, period)
+		assert.ErrorIs(t, err, ErrTrainingCapacityBelowBooked)
+	})
+
+	t.Run("Fail_MoveWithAppointments", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "l", 10, period))
+		require.NoError(t, td.ReserveSpot(1))
+		err := td.UpdateDetails("l", 10, moved)
+		assert.ErrorIs(t, err, ErrTrainingHasAppointments)
+	})
+}
+
+func TestTrainDate_Cancel(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	period, _ := NewTimeRange(start, start.Add(time.Hour))
+
+	t.Run("Success_HasAppointments", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "l", 10, period))
+		require.NoError(t, td.ReserveSpot(2))
+
+		err := td.Cancel(" 雨天 ", "coach1")
+		require.NoError(t, err)
+		assert.True(t, td.IsCancelled())
+		assert.Equal(t, "雨天", td.Cancellation().Reason())
+		assert.Equal(t, "coach1", td.Cancellation().CancelledBy())
+		assert.True(t, td.HasPendingCancelNotice())
+		assert.ErrorIs(t, td.ReserveSpot(1), ErrTrainingCancelled)
+
+		td.MarkCancelNoticeSent()
+		assert.False(t, td.HasPendingCancelNotice())
+	})
+
+	t.Run("Fail_EmptyReason", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "l", 10, period))
+		assert.ErrorIs(t, td.Cancel("  ", "coach1"), ErrTrainingCancelReasonEmpty)
+		assert.False(t, td.IsCancelled())
+	})
+
+	t.Run("Fail_AlreadyCancelled", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "l", 10, period))
+		require.NoError(t, td.Cancel("雨天", "coach1"))
+		assert.ErrorIs(t, td.Cancel("雨天", "coach1"), ErrTrainingCancelled)
+	})
+
+	t.Run("Fail_Over", func(t *testing.T) {
+		past, _ := NewTimeRange(time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "l", 10, past))
+		assert.ErrorIs(t, td.Cancel("雨天", "coach1"), ErrTrainingOver)
+	})
+}
+
+func TestTrainDate_Reschedule(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	period, _ := NewTimeRange(start, start.Add(time.Hour))
+	moved, _ := NewTimeRange(start.Add(time.Hour), start.Add(2*time.Hour))
+
+	t.Run("Success_KeepAppointments", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "Gym", 10, period))
+		require.NoError(t, td.ReserveSpot(3))
+
+		err := td.Reschedule("", moved, "coach1")
+		require.NoError(t, err)
+		assert.Equal(t, moved, td.Period())
+		assert.Equal(t, "Gym", td.Location())
+		assert.Equal(t, 7, td.AvailableCapacity())
+		assert.Equal(t, period, td.LastReschedule().PreviousPeriod())
+		assert.True(t, td.HasPendingRescheduleNotice())
+		assert.True(t, td.IsRescheduledAfter(time.Now().Add(-time.Minute)))
+
+		// 尚未通知前再次改期，保留原本的時間
+		require.NoError(t, td.Reschedule("Pool", period, "coach1"))
+		assert.Equal(t, period, td.LastReschedule().PreviousPeriod())
+		assert.Equal(t, "Gym", td.LastReschedule().PreviousLocation())
+
+		td.MarkRescheduleNoticeSent()
+		assert.False(t, td.HasPendingRescheduleNotice())
+	})
+
+	t.Run("Fail_NoChange", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "Gym", 10, period))
+		assert.ErrorIs(t, td.Reschedule("Gym", period, "coach1"), ErrTrainingRescheduleNoChange)
+		assert.True(t, td.LastReschedule().IsZero())
+	})
+
+	t.Run("Fail_ToPast", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "Gym", 10, period))
+		past, _ := NewTimeRange(time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))
+		assert.ErrorIs(t, td.Reschedule("", past, "coach1"), ErrTrainingOver)
+	})
+
+	t.Run("Fail_Cancelled", func(t *testing.T) {
+		td, _ := NewTrainDate(WithBasicTrainDate("id1", "c", "Gym", 10, period))
+		require.NoError(t, td.Cancel("雨天", "coach1"))
+		assert.ErrorIs(t, td.Reschedule("", moved, "coach1"), ErrTrainingCancelled)
+	})
+}
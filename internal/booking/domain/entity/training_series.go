@@ -0,0 +1,447 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"seanAIgent/internal/util/timeutil"
+)
+
+type TrainingSeriesStatus string
+
+const (
+	TrainingSeriesStatusActive TrainingSeriesStatus = "ACTIVE" // 持續產生場次
+	TrainingSeriesStatusEnded  TrainingSeriesStatus = "ENDED"  // 已結束，不再產生場次
+)
+
+const seriesClockLayout = "15:04"
+
+// SeriesOccurrence 系列課程中的某一堂，Date 為系列時區的日期
+type SeriesOccurrence struct {
+	Period TimeRange
+	Date   string
+}
+
+type trainingSeriesOpt func(*TrainingSeries) error
+
+func WithTrainingSeriesID(id string) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.id = id
+		return nil
+	}
+}
+
+func WithTrainingSeriesCoachID(coachID string) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.coachID = coachID
+		return nil
+	}
+}
+
+func WithTrainingSeriesLocation(location string) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.location = location
+		return nil
+	}
+}
+
+func WithTrainingSeriesCapacity(capacity int) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.capacity = capacity
+		return nil
+	}
+}
+
+func WithTrainingSeriesTimezone(timezone string) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.timezone = timezone
+		return nil
+	}
+}
+
+// WithTrainingSeriesSchedule 首堂日期 (YYYY-MM-DD) 與每堂的上課時間 (HH:MM)
+func WithTrainingSeriesSchedule(startDate, startClock, endClock string) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.startDate = startDate
+		s.startClock = startClock
+		s.endClock = endClock
+		return nil
+	}
+}
+
+func WithTrainingSeriesRule(rule RecurrenceRule) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.rule = rule
+		return nil
+	}
+}
+
+func WithTrainingSeriesExceptions(dates []string) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		for _, d := range dates {
+			if err := s.AddException(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func WithTrainingSeriesStatus(status TrainingSeriesStatus) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.status = status
+		return nil
+	}
+}
+
+func WithTrainingSeriesVersion(version int) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.version = version
+		return nil
+	}
+}
+
+func WithTrainingSeriesCreatedAt(t time.Time) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.createdAt = t
+		return nil
+	}
+}
+
+func WithTrainingSeriesUpdatedAt(t time.Time) trainingSeriesOpt {
+	return func(s *TrainingSeries) error {
+		s.updatedAt = t
+		return nil
+	}
+}
+
+func NewTrainingSeries(opts ...trainingSeriesOpt) (*TrainingSeries, error) {
+	now := time.Now()
+	s := &TrainingSeries{
+		status:    TrainingSeriesStatusActive,
+		createdAt: now,
+		updatedAt: now,
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// TrainingSeries 週期性課程，依重複規則滾動產生 TrainDate
+type TrainingSeries struct {
+	createdAt  time.Time
+	updatedAt  time.Time
+	loc        *time.Location
+	rule       RecurrenceRule
+	exceptions []string
+	id         string
+	coachID    string
+	location   string
+	timezone   string
+	startDate  string
+	startClock string
+	endClock   string
+	status     TrainingSeriesStatus
+	capacity   int
+	version    int
+}
+
+func (s *TrainingSeries) Validate() error {
+	if s.id == "" {
+		return fmt.Errorf("%w: id is empty", ErrTrainingSeriesInvalid)
+	}
+	if s.coachID == "" {
+		return fmt.Errorf("%w: coach id is empty", ErrTrainingSeriesInvalid)
+	}
+	if s.capacity <= 0 {
+		return fmt.Errorf("%w: capacity must be positive", ErrTrainingSeriesInvalid)
+	}
+	if s.rule.frequency == "" {
+		return fmt.Errorf("%w: recurrence rule is empty", ErrTrainingSeriesInvalid)
+	}
+	if s.timezone == "" {
+		s.timezone = "Asia/Taipei"
+	}
+	loc, err := timeutil.GetLocation(s.timezone)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTrainingSeriesInvalid, err)
+	}
+	s.loc = loc
+	if _, err := s.parseDate(s.startDate); err != nil {
+		return fmt.Errorf("%w: invalid start date %q", ErrTrainingSeriesInvalid, s.startDate)
+	}
+	return validateSeriesClock(s.startClock, s.endClock)
+}
+
+func validateSeriesClock(startClock, endClock string) error {
+	start, err := time.Parse(seriesClockLayout, startClock)
+	if err != nil {
+		return fmt.Errorf("%w: invalid start time %q", ErrTrainingSeriesInvalid, startClock)
+	}
+	end, err := time.Parse(seriesClockLayout, endClock)
+	if err != nil {
+		return fmt.Errorf("%w: invalid end time %q", ErrTrainingSeriesInvalid, endClock)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("%w: end time must be after start time", ErrTrainingSeriesInvalid)
+	}
+	return nil
+}
+
+func (s *TrainingSeries) parseDate(date string) (time.Time, error) {
+	return time.ParseInLocation(seriesDateLayout, date, s.loc)
+}
+
+// DateOf 回傳某個時間點在系列時區的日期
+func (s *TrainingSeries) DateOf(t time.Time) string {
+	return t.In(s.loc).Format(seriesDateLayout)
+}
+
+// Occurrences 取得 [from, to) 區間內應開課的場次，已排除例外日期
+func (s *TrainingSeries) Occurrences(from, to time.Time) []SeriesOccurrence {
+	return s.occurrences(from, to, false)
+}
+
+func (s *TrainingSeries) occurrences(from, to time.Time, withExceptions bool) []SeriesOccurrence {
+	anchor, _ := s.parseDate(s.startDate)
+	var untilDay time.Time
+	if s.rule.until != "" {
+		untilDay, _ = s.parseDate(s.rule.until)
+	}
+	var result []SeriesOccurrence
+	n := 0
+	for day := anchor; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !untilDay.IsZero() && day.After(untilDay) {
+			break
+		}
+		if s.rule.count > 0 && n >= s.rule.count {
+			break
+		}
+		if !s.rule.matches(day, anchor) {
+			continue
+		}
+		// 例外日期仍計入總堂數
+		n++
+		date := day.Format(seriesDateLayout)
+		period := s.periodOn(day)
+		if period.Start().Before(from) || !period.Start().Before(to) {
+			continue
+		}
+		if !withExceptions && s.IsException(date) {
+			continue
+		}
+		result = append(result, SeriesOccurrence{Date: date, Period: period})
+	}
+	return result
+}
+
+func (s *TrainingSeries) periodOn(day time.Time) TimeRange {
+	start, _ := time.Parse(seriesClockLayout, s.startClock)
+	end, _ := time.Parse(seriesClockLayout, s.endClock)
+	y, m, d := day.Date()
+	return TimeRange{
+		start: time.Date(y, m, d, start.Hour(), start.Minute(), 0, 0, s.loc),
+		end:   time.Date(y, m, d, end.Hour(), end.Minute(), 0, 0, s.loc),
+	}
+}
+
+// OccurrenceOn 查詢某日期是否為規則內的場次 (不論是否為例外日期)
+func (s *TrainingSeries) OccurrenceOn(date string) (SeriesOccurrence, bool) {
+	day, err := s.parseDate(date)
+	if err != nil {
+		return SeriesOccurrence{}, false
+	}
+	occs := s.occurrences(day, day.AddDate(0, 0, 1), true)
+	if len(occs) == 0 {
+		return SeriesOccurrence{}, false
+	}
+	return occs[0], true
+}
+
+func (s *TrainingSeries) IsException(date string) bool {
+	for _, d := range s.exceptions {
+		if d == date {
+			return true
+		}
+	}
+	return false
+}
+
+// AddException 加入例外日期，該日不再產生場次
+func (s *TrainingSeries) AddException(date string) error {
+	if _, err := time.Parse(seriesDateLayout, date); err != nil {
+		return fmt.Errorf("%w: invalid exception date %q", ErrTrainingSeriesInvalid, date)
+	}
+	if s.IsException(date) {
+		return nil
+	}
+	s.exceptions = append(s.exceptions, date)
+	sort.Strings(s.exceptions)
+	s.updatedAt = time.Now()
+	return nil
+}
+
+// UpdateTemplate 修改之後產生的場次內容
+func (s *TrainingSeries) UpdateTemplate(location string, capacity int, startClock, endClock string) error {
+	if s.status == TrainingSeriesStatusEnded {
+		return ErrTrainingSeriesEnded
+	}
+	if capacity <= 0 {
+		return fmt.Errorf("%w: capacity must be positive", ErrTrainingSeriesInvalid)
+	}
+	if err := validateSeriesClock(startClock, endClock); err != nil {
+		return err
+	}
+	s.location = location
+	s.capacity = capacity
+	s.startClock = startClock
+	s.endClock = endClock
+	s.updatedAt = time.Now()
+	return nil
+}
+
+// EndBefore 讓系列在指定場次前結束，用於「這堂及之後」的修改與刪除
+func (s *TrainingSeries) EndBefore(date string) error {
+	if s.status == TrainingSeriesStatusEnded {
+		return ErrTrainingSeriesEnded
+	}
+	if _, ok := s.OccurrenceOn(date); !ok {
+		return ErrTrainingSeriesNotOccurrence
+	}
+	if date <= s.startDate {
+		return fmt.Errorf("%w: cannot end before the first occurrence", ErrTrainingSeriesNotOccurrence)
+	}
+	day, _ := s.parseDate(date)
+	s.rule.until = day.AddDate(0, 0, -1).Format(seriesDateLayout)
+	s.rule.count = 0
+	kept := make([]string, 0, len(s.exceptions))
+	for _, d := range s.exceptions {
+		if d < date {
+			kept = append(kept, d)
+		}
+	}
+	s.exceptions = kept
+	s.updatedAt = time.Now()
+	return nil
+}
+
+// Split 從指定場次切出新的系列，原系列在該場次前結束
+func (s *TrainingSeries) Split(newID, date string) (*TrainingSeries, error) {
+	if _, ok := s.OccurrenceOn(date); !ok {
+		return nil, ErrTrainingSeriesNotOccurrence
+	}
+	rule := s.rule
+	if rule.count > 0 {
+		day, _ := s.parseDate(date)
+		anchor, _ := s.parseDate(s.startDate)
+		rule.count -= len(s.occurrences(anchor, day, true))
+	}
+	exceptions := make([]string, 0, len(s.exceptions))
+	for _, d := range s.exceptions {
+		if d >= date {
+			exceptions = append(exceptions, d)
+		}
+	}
+	next, err := NewTrainingSeries(
+		WithTrainingSeriesID(newID),
+		WithTrainingSeriesCoachID(s.coachID),
+		WithTrainingSeriesLocation(s.location),
+		WithTrainingSeriesCapacity(s.capacity),
+		WithTrainingSeriesTimezone(s.timezone),
+		WithTrainingSeriesSchedule(date, s.startClock, s.endClock),
+		WithTrainingSeriesRule(rule),
+		WithTrainingSeriesExceptions(exceptions),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EndBefore(date); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// End 結束整個系列
+func (s *TrainingSeries) End() {
+	s.status = TrainingSeriesStatusEnded
+	s.updatedAt = time.Now()
+}
+
+func (s *TrainingSeries) IsActive() bool {
+	return s.status == TrainingSeriesStatusActive
+}
+
+// Getter
+func (s *TrainingSeries) ID() string {
+	return s.id
+}
+
+func (s *TrainingSeries) CoachID() string {
+	return s.coachID
+}
+
+func (s *TrainingSeries) Location() string {
+	return s.location
+}
+
+func (s *TrainingSeries) Capacity() int {
+	return s.capacity
+}
+
+func (s *TrainingSeries) Timezone() string {
+	return s.timezone
+}
+
+func (s *TrainingSeries) StartDate() string {
+	return s.startDate
+}
+
+func (s *TrainingSeries) StartClock() string {
+	return s.startClock
+}
+
+func (s *TrainingSeries) EndClock() string {
+	return s.endClock
+}
+
+func (s *TrainingSeries) Rule() RecurrenceRule {
+	return s.rule
+}
+
+func (s *TrainingSeries) Exceptions() []string {
+	exceptions := make([]string, len(s.exceptions))
+	copy(exceptions, s.exceptions)
+	return exceptions
+}
+
+func (s *TrainingSeries) Status() TrainingSeriesStatus {
+	return s.status
+}
+
+func (s *TrainingSeries) Version() int {
+	return s.version
+}
+
+func (s *TrainingSeries) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+func (s *TrainingSeries) UpdatedAt() time.Time {
+	return s.updatedAt
+}
+
+// Error Definition
+var (
+	ErrTrainingSeriesInvalid       = errors.New("TRAINING_SERIES_INVALID")
+	ErrTrainingSeriesEnded         = errors.New("TRAINING_SERIES_ENDED")
+	ErrTrainingSeriesNotOccurrence = errors.New("TRAINING_SERIES_NOT_OCCURRENCE")
+	ErrRecurrenceRuleInvalid       = errors.New("RECURRENCE_RULE_INVALID")
+)
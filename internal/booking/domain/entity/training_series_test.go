@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSeries(t *testing.T, freq RecurrenceFrequency, until string, count int) *TrainingSeries {
+	t.Helper()
+	rule, err := NewRecurrenceRule(freq, []time.Weekday{time.Sunday}, until, count)
+	require.NoError(t, err)
+	s, err := NewTrainingSeries(
+		WithTrainingSeriesID("s1"),
+		WithTrainingSeriesCoachID("coach1"),
+		WithTrainingSeriesLocation("TKU"),
+		WithTrainingSeriesCapacity(10),
+		WithTrainingSeriesTimezone("Asia/Taipei"),
+		// 2026-01-04 為週日
+		WithTrainingSeriesSchedule("2026-01-04", "14:00", "16:00"),
+		WithTrainingSeriesRule(rule),
+	)
+	require.NoError(t, err)
+	return s
+}
+
+func occurrenceDates(occs []SeriesOccurrence) []string {
+	dates := make([]string, 0, len(occs))
+	for _, o := range occs {
+		dates = append(dates, o.Date)
+	}
+	return dates
+}
+
+func TestNewRecurrenceRule(t *testing.T) {
+	t.Run("Success_DedupAndSort", func(t *testing.T) {
+		rule, err := NewRecurrenceRule(RecurrenceWeekly,
+			[]time.Weekday{time.Saturday, time.Monday, time.Saturday}, "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Weekday{time.Monday, time.Saturday}, rule.Weekdays())
+	})
+
+	t.Run("Fail_Invalid", func(t *testing.T) {
+		_, err := NewRecurrenceRule("DAILY", []time.Weekday{time.Monday}, "", 0)
+		assert.ErrorIs(t, err, ErrRecurrenceRuleInvalid)
+		_, err = NewRecurrenceRule(RecurrenceWeekly, nil, "", 0)
+		assert.ErrorIs(t, err, ErrRecurrenceRuleInvalid)
+		_, err = NewRecurrenceRule(RecurrenceWeekly, []time.Weekday{time.Monday}, "2026/01/01", 0)
+		assert.ErrorIs(t, err, ErrRecurrenceRuleInvalid)
+	})
+}
+
+func TestNewTrainingSeries_Fail(t *testing.T) {
+	rule, _ := NewRecurrenceRule(RecurrenceWeekly, []time.Weekday{time.Sunday}, "", 0)
+	_, err := NewTrainingSeries(
+		WithTrainingSeriesID("s1"),
+		WithTrainingSeriesCoachID("coach1"),
+		WithTrainingSeriesCapacity(10),
+		WithTrainingSeriesSchedule("2026-01-04", "16:00", "14:00"),
+		WithTrainingSeriesRule(rule),
+	)
+	assert.ErrorIs(t, err, ErrTrainingSeriesInvalid)
+}
+
+func TestTrainingSeries_Occurrences(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Taipei")
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, loc)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, loc)
+
+	t.Run("Weekly", func(t *testing.T) {
+		s := newTestSeries(t, RecurrenceWeekly, "", 0)
+		occs := s.Occurrences(from, to)
+		assert.Equal(t, []string{"2026-01-04", "2026-01-11", "2026-01-18", "2026-01-25"}, occurrenceDates(occs))
+		assert.Equal(t, time.Date(2026, 1, 4, 14, 0, 0, 0, loc), occs[0].Period.Start())
+		assert.Equal(t, time.Date(2026, 1, 4, 16, 0, 0, 0, loc), occs[0].Period.End())
+	})
+
+	t.Run("Biweekly", func(t *testing.T) {
+		s := newTestSeries(t, RecurrenceBiweekly, "", 0)
+		assert.Equal(t, []string{"2026-01-04", "2026-01-18"}, occurrenceDates(s.Occurrences(from, to)))
+	})
+
+	t.Run("UntilAndCount", func(t *testing.T) {
+		s := newTestSeries(t, RecurrenceWeekly, "2026-01-18", 0)
+		assert.Equal(t, []string{"2026-01-04", "2026-01-11", "2026-01-18"}, occurrenceDates(s.Occurrences(from, to)))
+
+		s = newTestSeries(t, RecurrenceWeekly, "", 2)
+		assert.Equal(t, []string{"2026-01-04", "2026-01-11"}, occurrenceDates(s.Occurrences(from, to)))
+	})
+
+	t.Run("ExceptionStillCounted", func(t *testing.T) {
+		s := newTestSeries(t, RecurrenceWeekly, "", 3)
+		require.NoError(t, s.AddException("2026-01-11"))
+		assert.Equal(t, []string{"2026-01-04", "2026-01-18"}, occurrenceDates(s.Occurrences(from, to)))
+
+		_, ok := s.OccurrenceOn("2026-01-11")
+		assert.True(t, ok)
+		_, ok = s.OccurrenceOn("2026-01-12")
+		assert.False(t, ok)
+	})
+}
+
+func TestTrainingSeries_Split(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Taipei")
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, loc)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, loc)
+
+	t.Run("Success", func(t *testing.T) {
+		s := newTestSeries(t, RecurrenceWeekly, "", 4)
+		require.NoError(t, s.AddException("2026-01-25"))
+
+		next, err := s.Split("s2", "2026-01-18")
+		require.NoError(t, err)
+		assert.Equal(t, "2026-01-17", s.Rule().Until())
+		assert.Empty(t, s.Exceptions())
+		assert.Equal(t, []string{"2026-01-04", "2026-01-11"}, occurrenceDates(s.Occurrences(from, to)))
+
+		assert.Equal(t, "2026-01-18", next.StartDate())
+		assert.Equal(t, 2, next.Rule().Count())
+		assert.Equal(t, []string{"2026-01-25"}, next.Exceptions())
+		assert.Equal(t, []string{"2026-01-18"}, occurrenceDates(next.Occurrences(from, to)))
+	})
+
+	t.Run("Fail_NotOccurrence", func(t *testing.T) {
+		s := newTestSeries(t, RecurrenceWeekly, "", 0)
+		_, err := s.Split("s2", "2026-01-19")
+		assert.ErrorIs(t, err, ErrTrainingSeriesNotOccurrence)
+		_, err = s.Split("s2", "2026-01-04")
+		assert.ErrorIs(t, err, ErrTrainingSeriesNotOccurrence)
+	})
+}
+
+func TestTrainingSeries_UpdateTemplate(t *testing.T) {
+	s := newTestSeries(t, RecurrenceWeekly, "", 0)
+	require.NoError(t, s.UpdateTemplate("Gym", 12, "15:00", "17:00"))
+	assert.Equal(t, "Gym", s.Location())
+	assert.Equal(t, 12, s.Capacity())
+	assert.Equal(t, "15:00", s.StartClock())
+
+	assert.ErrorIs(t, s.UpdateTemplate("Gym", 0, "15:00", "17:00"), ErrTrainingSeriesInvalid)
+
+	s.End()
+	assert.ErrorIs(t, s.UpdateTemplate("Gym", 12, "15:00", "17:00"), ErrTrainingSeriesEnded)
+}
@@ -14,6 +14,7 @@ type UserMonthlyStat struct {
 	AttendedCount    int          `json:"attended_count" bson:"attended_count"`
 	AbsentCount      int          `json:"absent_count" bson:"absent_count"`
 	LeaveCount       int          `json:"leave_count" bson:"leave_count"`
+	MakeUpCount      int          `json:"make_up_count" bson:"make_up_count"` // 補課預約數，已含在 TotalBookings
 	Children         []ChildStat  `json:"children" bson:"children"`
 	LastUpdatedAt    time.Time    `json:"last_updated_at" bson:"last_updated_at"`
 }
@@ -24,6 +25,7 @@ type ChildStat struct {
 	AttendedCount int    `json:"attended_count" bson:"attended_count"`
 	AbsentCount   int    `json:"absent_count" bson:"absent_count"`
 	LeaveCount    int    `json:"leave_count" bson:"leave_count"`
+	MakeUpCount   int    `json:"make_up_count" bson:"make_up_count"`
 }
 
 // MonthlyBusinessStat 用於經營分析看板的全域統計
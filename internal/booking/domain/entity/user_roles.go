@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// UserRoles LINE 使用者的後台角色；沒有角色紀錄的 LINE 管理員視為負責人
+type UserRoles struct {
+	updatedAt time.Time
+	user      User
+	updatedBy string // 最後指派角色的使用者 ID
+	roles     []Role
+}
+
+type userRolesOpt func(*UserRoles)
+
+func WithUserRolesUser(user User) userRolesOpt {
+	return func(u *UserRoles) {
+		u.user = user
+	}
+}
+
+func WithUserRolesRoles(roles ...Role) userRolesOpt {
+	return func(u *UserRoles) {
+		u.roles = roles
+	}
+}
+
+func WithUserRolesUpdatedBy(userID string) userRolesOpt {
+	return func(u *UserRoles) {
+		u.updatedBy = userID
+	}
+}
+
+func WithUserRolesUpdatedAt(updatedAt time.Time) userRolesOpt {
+	return func(u *UserRoles) {
+		u.updatedAt = updatedAt
+	}
+}
+
+func NewUserRoles(opts ...userRolesOpt) (*UserRoles, error) {
+	u := &UserRoles{
+		updatedAt: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	if u.user.UserID() == "" {
+		return nil, fmt.Errorf("%w: user is empty", ErrUserRolesInvalid)
+	}
+	roles, err := normalizeRoles(u.roles)
+	if err != nil {
+		return nil, err
+	}
+	u.roles = roles
+	return u, nil
+}
+
+func normalizeRoles(roles []Role) ([]Role, error) {
+	normalized := make([]Role, 0, len(roles))
+	for _, r := range roles {
+		if _, err := ParseRole(string(r)); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(normalized, r) {
+			normalized = append(normalized, r)
+		}
+	}
+	return normalized, nil
+}
+
+// Assign 取代原本的角色
+func (u *UserRoles) Assign(roles []Role, by string) error {
+	normalized, err := normalizeRoles(roles)
+	if err != nil {
+		return err
+	}
+	u.roles = normalized
+	u.updatedBy = by
+	u.updatedAt = time.Now()
+	return nil
+}
+
+func (u *UserRoles) Can(p Permission) bool {
+	return slices.Contains(u.Permissions(), p)
+}
+
+func (u *UserRoles) Permissions() []Permission {
+	return PermissionsOf(u.roles...)
+}
+
+// IsEmpty 已移除所有角色
+func (u *UserRoles) IsEmpty() bool {
+	return len(u.roles) == 0
+}
+
+func (u *UserRoles) User() User {
+	return u.user
+}
+
+func (u *UserRoles) Roles() []Role {
+	return slices.Clone(u.roles)
+}
+
+func (u *UserRoles) UpdatedBy() string {
+	return u.updatedBy
+}
+
+func (u *UserRoles) UpdatedAt() time.Time {
+	return u.updatedAt
+}
+
+var (
+	ErrUserRolesInvalid = errors.New("USER_ROLES_INVALID")
+)
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUserRoles(t *testing.T) {
+	user, _ := NewUser("u1", "Assistant")
+
+	t.Run("Success_Dedupe", func(t *testing.T) {
+		roles, err := NewUserRoles(
+			WithUserRolesUser(user),
+			WithUserRolesRoles(RoleAssistantCoach, RoleAssistantCoach),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []Role{RoleAssistantCoach}, roles.Roles())
+		assert.True(t, roles.Can(PermAttendanceWrite))
+		assert.False(t, roles.Can(PermTrainingWrite))
+		assert.False(t, roles.Can(PermReportExport))
+	})
+
+	t.Run("Fail_UnknownRole", func(t *testing.T) {
+		_, err := NewUserRoles(WithUserRolesUser(user), WithUserRolesRoles(Role("superuser")))
+		assert.ErrorIs(t, err, ErrUserRolesInvalid)
+	})
+
+	t.Run("Fail_MissingUser", func(t *testing.T) {
+		_, err := NewUserRoles(WithUserRolesRoles(RoleOwner))
+		assert.ErrorIs(t, err, ErrUserRolesInvalid)
+	})
+}
+
+func TestUserRoles_Assign(t *testing.T) {
+	user, _ := NewUser("u1", "Coach")
+	roles, err := NewUserRoles(WithUserRolesUser(user), WithUserRolesRoles(RoleHeadCoach))
+	require.NoError(t, err)
+
+	require.NoError(t, roles.Assign(nil, "owner1"))
+	assert.True(t, roles.IsEmpty())
+	assert.Empty(t, roles.Permissions())
+	assert.Equal(t, "owner1", roles.UpdatedBy())
+
+	assert.ErrorIs(t, roles.Assign([]Role{"superuser"}, "owner1"), ErrUserRolesInvalid)
+	assert.True(t, roles.IsEmpty())
+}
+
+func TestPermissionsOf(t *testing.T) {
+	perms := PermissionsOf(RoleAssistantCoach, RoleStaff)
+	assert.Contains(t, perms, PermAttendanceWrite)
+	assert.Contains(t, perms, PermBillingWrite)
+	assert.NotContains(t, perms, PermTrainingWrite)
+	assert.NotContains(t, perms, PermRoleWrite)
+	assert.Len(t, perms, 8)
+
+	assert.ElementsMatch(t, allPermissions, PermissionsOf(RoleOwner))
+}
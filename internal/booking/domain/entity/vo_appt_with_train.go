@@ -25,6 +25,8 @@ type TrainDateUI struct {
 	Timezone          string    `json:"timezone"`
 	Capacity          int       `json:"capacity"`
 	AvailableCapacity int       `json:"available_capacity"`
+	// 場次改期時間，之前建立的預約可免責取消
+	RescheduledAt *time.Time `json:"rescheduled_at,omitempty"`
 }
 
 type LeaveInfoUI struct {
@@ -0,0 +1,72 @@
+package entity
+
+import "time"
+
+const defaultBillingDueDay = 10
+
+// BillingPolicy 月結計費規則，出席與缺席皆計費，請假不計費
+type BillingPolicy struct {
+	loc           *time.Location
+	pricePerClass int // 每堂費用
+	dueDay        int // 次月幾號前需繳清
+}
+
+func NewBillingPolicy(pricePerClass, dueDay int, loc *time.Location) BillingPolicy {
+	if pricePerClass < 0 {
+		pricePerClass = 0
+	}
+	// 避免 2 月等短月份溢位到下個月
+	if dueDay < 1 || dueDay > 28 {
+		dueDay = defaultBillingDueDay
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return BillingPolicy{
+		pricePerClass: pricePerClass,
+		dueDay:        dueDay,
+		loc:           loc,
+	}
+}
+
+func (p BillingPolicy) PricePerClass() int {
+	return p.pricePerClass
+}
+
+func (p BillingPolicy) DueDay() int {
+	return p.dueDay
+}
+
+// DueAt 帳務月份的繳費期限，為次月 dueDay 當天結束
+func (p BillingPolicy) DueAt(year, month int) time.Time {
+	return time.Date(year, time.Month(month)+1, p.dueDay+1, 0, 0, 0, 0, p.loc)
+}
+
+// Statement 依月統計與該月的收款計算帳單狀態，payments 中不屬於該家長或該月份的紀錄會被忽略
+func (p BillingPolicy) Statement(stat *UserMonthlyStat, payments []*Payment, now time.Time) *BillingStatement {
+	s := &BillingStatement{
+		userID:        stat.UserID,
+		year:          stat.Year,
+		month:         stat.Month,
+		billableCount: stat.AttendedCount + stat.AbsentCount,
+		dueAt:         p.DueAt(stat.Year, stat.Month),
+		payments:      make([]*Payment, 0),
+	}
+	s.amountDue = s.billableCount * p.pricePerClass
+	for _, pay := range payments {
+		if pay.user.UserID() != stat.UserID || pay.year != stat.Year || pay.month != stat.Month {
+			continue
+		}
+		s.payments = append(s.payments, pay)
+		s.amountPaid += pay.amount
+	}
+	switch {
+	case s.amountPaid >= s.amountDue:
+		s.status = BillingStatusPaid
+	case now.Before(s.dueAt):
+		s.status = BillingStatusUnpaid
+	default:
+		s.status = BillingStatusOverdue
+	}
+	return s
+}
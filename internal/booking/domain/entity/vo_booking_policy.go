@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultCancelWindow    = 24 * time.Hour
+	defaultLeaveCutoff     = 2 * time.Hour
+	defaultCheckInOpen     = 30 * time.Minute
+	defaultAttendanceAmend = 7 * 24 * time.Hour
+)
+
+// BookingPolicy 場次的預約規則，各時間皆以場次開始時間或預約建立時間為基準
+type BookingPolicy struct {
+	cancelWindow    time.Duration // 預約建立後多久內可自行取消 (誤按)
+	leaveCutoff     time.Duration // 開課前多久以前可請假
+	checkInOpen     time.Duration // 開課前多久開放教練點名與異動出席狀態
+	attendanceAmend time.Duration // 開課後多久內可補登/補改出席狀態
+}
+
+func NewBookingPolicy(
+	cancelWindow, leaveCutoff, checkInOpen, attendanceAmend time.Duration,
+) (BookingPolicy, error) {
+	if cancelWindow < 0 || leaveCutoff < 0 || checkInOpen < 0 || attendanceAmend < 0 {
+		return BookingPolicy{}, fmt.Errorf("%w: duration must not be negative", ErrBookingPolicyInvalid)
+	}
+	return BookingPolicy{
+		cancelWindow:    cancelWindow,
+		leaveCutoff:     leaveCutoff,
+		checkInOpen:     checkInOpen,
+		attendanceAmend: attendanceAmend,
+	}, nil
+}
+
+// DefaultBookingPolicy 未另行設定的場次所使用的規則
+func DefaultBookingPolicy() BookingPolicy {
+	return BookingPolicy{
+		cancelWindow:    defaultCancelWindow,
+		leaveCutoff:     defaultLeaveCutoff,
+		checkInOpen:     defaultCheckInOpen,
+		attendanceAmend: defaultAttendanceAmend,
+	}
+}
+
+// IsZero 尚未設定任何規則
+func (p BookingPolicy) IsZero() bool {
+	return p == BookingPolicy{}
+}
+
+// checkCancel 預約建立後超過 cancelWindow 即不可自行取消
+func (p BookingPolicy) checkCancel(createdAt, now time.Time) error {
+	if now.Sub(createdAt) > p.cancelWindow {
+		return ErrAppointmentCancelTimeout
+	}
+	return nil
+}
+
+// checkLeave 開課前 leaveCutoff 內不可請假
+func (p BookingPolicy) checkLeave(trainingStart, now time.Time) error {
+	if trainingStart.Sub(now) < p.leaveCutoff {
+		return ErrAppointmentLeaveTooLate
+	}
+	return nil
+}
+
+// checkAttendance 教練異動出席狀態的時間範圍
+func (p BookingPolicy) checkAttendance(trainingStart, now time.Time) error {
+	if now.Before(trainingStart.Add(-p.checkInOpen)) {
+		return ErrAppointmentCheckInNotOpen
+	}
+	if now.After(trainingStart.Add(p.attendanceAmend)) {
+		return ErrAppointmentCheckInTooLate
+	}
+	return nil
+}
+
+func (p BookingPolicy) CancelWindow() time.Duration {
+	return p.cancelWindow
+}
+
+func (p BookingPolicy) LeaveCutoff() time.Duration {
+	return p.leaveCutoff
+}
+
+func (p BookingPolicy) CheckInOpen() time.Duration {
+	return p.checkInOpen
+}
+
+func (p BookingPolicy) AttendanceAmend() time.Duration {
+	return p.attendanceAmend
+}
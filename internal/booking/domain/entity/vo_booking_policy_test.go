@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBookingPolicy(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		p, err := NewBookingPolicy(time.Hour, 12*time.Hour, time.Hour, 48*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, p.CancelWindow())
+		assert.Equal(t, 12*time.Hour, p.LeaveCutoff())
+		assert.False(t, p.IsZero())
+	})
+
+	t.Run("Fail_Negative", func(t *testing.T) {
+		_, err := NewBookingPolicy(-time.Hour, 0, 0, 0)
+		assert.ErrorIs(t, err, ErrBookingPolicyInvalid)
+	})
+}
+
+func TestBookingPolicy_Enforced(t *testing.T) {
+	// 週末班：預約後 1 小時內可取消、開課前 12 小時截止請假、開課前 1 小時開放點名
+	policy, err := NewBookingPolicy(time.Hour, 12*time.Hour, time.Hour, time.Hour)
+	require.NoError(t, err)
+	user, _ := NewUser("u1", "User")
+	now := time.Now()
+
+	t.Run("CancelWindow", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		WithCreatedAt(now.Add(-2 * time.Hour))(appt)
+		assert.ErrorIs(t, appt.CancelAsMistake("u1", policy), ErrAppointmentCancelTimeout)
+		assert.NoError(t, appt.CancelAsMistake("u1", DefaultBookingPolicy()))
+	})
+
+	t.Run("LeaveCutoff", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		err := appt.AppendLeaveRecord("Sick", now.Add(6*time.Hour), policy)
+		assert.ErrorIs(t, err, ErrAppointmentLeaveTooLate)
+		assert.NoError(t, appt.AppendLeaveRecord("Sick", now.Add(6*time.Hour), DefaultBookingPolicy()))
+	})
+
+	t.Run("CheckInWindow", func(t *testing.T) {
+		appt, _ := NewAppointment(WithCreateAppt("a1", "t1", user, "Child"))
+		assert.NoError(t, appt.AdminCheckIn(now.Add(50*time.Minute), policy))
+
+		appt, _ = NewAppointment(WithCreateAppt("a2", "t1", user, "Child"))
+		assert.ErrorIs(t, appt.AdminCheckIn(now.Add(-2*time.Hour), policy), ErrAppointmentCheckInTooLate)
+	})
+}
+
+func TestTrainDate_BookingPolicy(t *testing.T) {
+	now := time.Now()
+	period, _ := NewTimeRange(now, now.Add(time.Hour))
+
+	t.Run("DefaultWhenUnset", func(t *testing.T) {
+		td, err := NewTrainDate(WithBasicTrainDate("id1", "coach1", "Gym", 10, period))
+		require.NoError(t, err)
+		assert.False(t, td.HasBookingPolicy())
+		assert.Equal(t, DefaultBookingPolicy(), td.BookingPolicy())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		td, err := NewTrainDate(WithBasicTrainDate("id1", "coach1", "Gym", 10, period))
+		require.NoError(t, err)
+		policy, _ := NewBookingPolicy(time.Hour, time.Hour, time.Hour, time.Hour)
+		td.UpdateBookingPolicy(policy)
+		assert.True(t, td.HasBookingPolicy())
+		assert.Equal(t, policy, td.BookingPolicy())
+	})
+}
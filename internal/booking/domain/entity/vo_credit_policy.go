@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+const defaultLeaveRefundCutoff = 2 * time.Hour
+
+// CreditPolicy 堂數退還規則
+type CreditPolicy struct {
+	leaveRefundCutoff time.Duration // 開課前多久以前請假可退還堂數
+}
+
+func NewCreditPolicy(leaveRefundCutoff time.Duration) CreditPolicy {
+	if leaveRefundCutoff < 0 {
+		leaveRefundCutoff = 0
+	}
+	return CreditPolicy{leaveRefundCutoff: leaveRefundCutoff}
+}
+
+// DefaultCreditPolicy 與家長請假期限一致，開課 2 小時前請假皆退還堂數
+func DefaultCreditPolicy() CreditPolicy {
+	return NewCreditPolicy(defaultLeaveRefundCutoff)
+}
+
+// RefundOnLeave 請假是否退還堂數，太晚請假 (例如教練於課後補登) 視同已上課扣除
+func (p CreditPolicy) RefundOnLeave(leaveAt, trainingStart time.Time) bool {
+	return !leaveAt.After(trainingStart.Add(-p.leaveRefundCutoff))
+}
+
+// ForTraining 場次的請假期限較短時以場次為準，家長依場次規定請假一律退還
+func (p CreditPolicy) ForTraining(booking BookingPolicy) CreditPolicy {
+	if booking.LeaveCutoff() < p.leaveRefundCutoff {
+		return NewCreditPolicy(booking.LeaveCutoff())
+	}
+	return p
+}
+
+func (p CreditPolicy) LeaveRefundCutoff() time.Duration {
+	return p.leaveRefundCutoff
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+const defaultMakeUpValidity = 30 * 24 * time.Hour
+
+// MakeUpPolicy 補課資格規則
+type MakeUpPolicy struct {
+	validity time.Duration // 請假場次開課後多久內可預約補課
+}
+
+func NewMakeUpPolicy(validity time.Duration) MakeUpPolicy {
+	if validity <= 0 {
+		validity = defaultMakeUpValidity
+	}
+	return MakeUpPolicy{validity: validity}
+}
+
+// DefaultMakeUpPolicy 請假場次開課後 30 天內可預約補課
+func DefaultMakeUpPolicy() MakeUpPolicy {
+	return NewMakeUpPolicy(defaultMakeUpValidity)
+}
+
+// ExpiresAt 補課場次需在此時間前開課
+func (p MakeUpPolicy) ExpiresAt(leaveTrainingStart time.Time) time.Time {
+	return leaveTrainingStart.Add(p.validity)
+}
+
+func (p MakeUpPolicy) Validity() time.Duration {
+	return p.validity
+}
@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly   RecurrenceFrequency = "WEEKLY"   // 每週
+	RecurrenceBiweekly RecurrenceFrequency = "BIWEEKLY" // 隔週
+)
+
+const seriesDateLayout = "2006-01-02"
+
+// RecurrenceRule 課程重複規則，until 與 count 可擇一或同時設定，以先到者為準
+type RecurrenceRule struct {
+	frequency RecurrenceFrequency
+	until     string // 結束日期 (含當日)，空字串代表不限
+	weekdays  []time.Weekday
+	count     int // 總堂數，0 代表不限
+}
+
+func NewRecurrenceRule(
+	frequency RecurrenceFrequency, weekdays []time.Weekday, until string, count int,
+) (RecurrenceRule, error) {
+	if frequency != RecurrenceWeekly && frequency != RecurrenceBiweekly {
+		return RecurrenceRule{}, fmt.Errorf("%w: unknown frequency %q", ErrRecurrenceRuleInvalid, frequency)
+	}
+	if len(weekdays) == 0 {
+		return RecurrenceRule{}, fmt.Errorf("%w: weekdays is empty", ErrRecurrenceRuleInvalid)
+	}
+	seen := make(map[time.Weekday]struct{}, len(weekdays))
+	days := make([]time.Weekday, 0, len(weekdays))
+	for _, d := range weekdays {
+		if d < time.Sunday || d > time.Saturday {
+			return RecurrenceRule{}, fmt.Errorf("%w: invalid weekday %d", ErrRecurrenceRuleInvalid, d)
+		}
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+	if until != "" {
+		if _, err := time.Parse(seriesDateLayout, until); err != nil {
+			return RecurrenceRule{}, fmt.Errorf("%w: invalid until date %q", ErrRecurrenceRuleInvalid, until)
+		}
+	}
+	if count < 0 {
+		return RecurrenceRule{}, fmt.Errorf("%w: count must not be negative", ErrRecurrenceRuleInvalid)
+	}
+	return RecurrenceRule{
+		frequency: frequency,
+		weekdays:  days,
+		until:     until,
+		count:     count,
+	}, nil
+}
+
+// matches 判斷某天是否符合規則，隔週以 anchor 所在週 (週一起算) 為第一週
+func (r RecurrenceRule) matches(day, anchor time.Time) bool {
+	matched := false
+	for _, d := range r.weekdays {
+		if day.Weekday() == d {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	if r.frequency == RecurrenceWeekly {
+		return true
+	}
+	weeks := daysBetween(weekStart(anchor), weekStart(day)) / 7
+	return weeks%2 == 0
+}
+
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// daysBetween 以日曆天計算，避免日光節約時間造成的誤差
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+func (r RecurrenceRule) Frequency() RecurrenceFrequency {
+	return r.frequency
+}
+
+func (r RecurrenceRule) Weekdays() []time.Weekday {
+	days := make([]time.Weekday, len(r.weekdays))
+	copy(days, r.weekdays)
+	return days
+}
+
+func (r RecurrenceRule) Until() string {
+	return r.until
+}
+
+func (r RecurrenceRule) Count() int {
+	return r.count
+}
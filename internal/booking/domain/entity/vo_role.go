@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Permission 後台操作權限，格式為「資源:動作」
+type Permission string
+
+const (
+	PermTrainingWrite   Permission = "training:write"   // 新增、刪除、修改場次與固定課表
+	PermAttendanceWrite Permission = "attendance:write" // 點名、請假、現場報名、候補排序
+	PermReportRead      Permission = "report:read"      // 場次看板、經營分析、數據月報表
+	PermReportExport    Permission = "report:export"    // 匯出月報表 CSV
+	PermUserPIIRead     Permission = "user:pii:read"    // 家長明細、學員姓名搜尋
+	PermBillingWrite    Permission = "billing:write"    // 儲值堂數、登記繳費
+	PermStudentWrite    Permission = "student:write"    // 合併學員
+	PermTeamWrite       Permission = "team:write"       // 團隊與成員管理
+	PermTeamAll         Permission = "team:all"         // 查看所有團隊的資料，沒有時只看得到自己帶的團隊
+	PermRoleWrite       Permission = "role:write"       // 指派後台角色
+	PermEventWrite      Permission = "event:write"      // 查看、重送與捨棄處理失敗的系統事件
+	PermWebhookWrite    Permission = "webhook:write"    // 管理對外 webhook 端點與測試送出
+)
+
+// Role 後台角色，權限由角色組合而成
+type Role string
+
+const (
+	RoleOwner          Role = "owner"
+	RoleHeadCoach      Role = "head_coach"
+	RoleAssistantCoach Role = "assistant_coach"
+	RoleStaff          Role = "staff"
+)
+
+var allPermissions = []Permission{
+	PermTrainingWrite, PermAttendanceWrite, PermReportRead, PermReportExport, PermUserPIIRead,
+	PermBillingWrite, PermStudentWrite, PermTeamWrite, PermTeamAll, PermRoleWrite, PermEventWrite,
+	PermWebhookWrite,
+}
+
+var rolePermissions = map[Role][]Permission{
+	RoleOwner: allPermissions,
+	RoleHeadCoach: {
+		PermTrainingWrite, PermAttendanceWrite, PermReportRead, PermReportExport, PermUserPIIRead,
+	},
+	RoleAssistantCoach: {
+		PermAttendanceWrite, PermReportRead,
+	},
+	RoleStaff: {
+		PermReportRead, PermReportExport, PermUserPIIRead, PermBillingWrite, PermStudentWrite,
+		PermTeamWrite, PermTeamAll,
+	},
+}
+
+var roleLabels = map[Role]string{
+	RoleOwner:          "負責人",
+	RoleHeadCoach:      "總教練",
+	RoleAssistantCoach: "助理教練",
+	RoleStaff:          "行政",
+}
+
+// AllRoles 依權限由大到小排列
+func AllRoles() []Role {
+	return []Role{RoleOwner, RoleHeadCoach, RoleAssistantCoach, RoleStaff}
+}
+
+func ParseRole(s string) (Role, error) {
+	r := Role(s)
+	if _, ok := rolePermissions[r]; !ok {
+		return "", fmt.Errorf("%w: unknown role %q", ErrUserRolesInvalid, s)
+	}
+	return r, nil
+}
+
+func (r Role) Permissions() []Permission {
+	return slices.Clone(rolePermissions[r])
+}
+
+func (r Role) Label() string {
+	if label, ok := roleLabels[r]; ok {
+		return label
+	}
+	return string(r)
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// PermissionsOf 合併多個角色的權限，去除重複
+func PermissionsOf(roles ...Role) []Permission {
+	perms := make([]Permission, 0, len(allPermissions))
+	for _, r := range roles {
+		for _, p := range rolePermissions[r] {
+			if !slices.Contains(perms, p) {
+				perms = append(perms, p)
+			}
+		}
+	}
+	return perms
+}
@@ -4,15 +4,49 @@ import "time"
 
 // 完整的預約狀態
 type TrainDateHasApptState struct {
-	StartDate         time.Time         `json:"start_date"`
-	EndDate           time.Time         `json:"end_date"`
-	ID                string            `json:"id"`
-	Date              string            `json:"date"`
-	Location          string            `json:"location"`
-	Timezone          string            `json:"timezone"`
-	UserAppointments  []UserAppointment `json:"user_appointments"`
-	Capacity          int               `json:"capacity"`
-	AvailableCapacity int               `json:"available_capacity"`
+	StartDate         time.Time             `json:"start_date"`
+	EndDate           time.Time             `json:"end_date"`
+	ID                string                `json:"id"`
+	Date              string                `json:"date"`
+	Location          string                `json:"location"`
+	Timezone          string                `json:"timezone"`
+	TeamID            string                `json:"team_id,omitempty"`
+	Status            string                `json:"status,omitempty"`
+	CancelReason      string                `json:"cancel_reason,omitempty"`
+	RescheduledAt     *time.Time            `json:"rescheduled_at,omitempty"`
+	UserAppointments  []UserAppointment     `json:"user_appointments"`
+	BookingPolicy     *BookingPolicyMinutes `json:"booking_policy,omitempty"`
+	Capacity          int                   `json:"capacity"`
+	AvailableCapacity int                   `json:"available_capacity"`
+}
+
+func (t *TrainDateHasApptState) IsCancelled() bool {
+	return t.Status == string(TrainDateStatusCancelled)
+}
+
+// BookingPolicyMinutes 查詢結果中場次自訂的預約規則，以分鐘表示，全為 0 代表未設定
+type BookingPolicyMinutes struct {
+	CancelWindow    int `json:"cancel_window"`
+	LeaveCutoff     int `json:"leave_cutoff"`
+	CheckInOpen     int `json:"check_in_open"`
+	AttendanceAmend int `json:"attendance_amend"`
+}
+
+// Policy 轉為 BookingPolicy，未設定或設定不正確時回傳預設規則
+func (m *BookingPolicyMinutes) Policy() BookingPolicy {
+	if m == nil {
+		return DefaultBookingPolicy()
+	}
+	policy, err := NewBookingPolicy(
+		time.Duration(m.CancelWindow)*time.Minute,
+		time.Duration(m.LeaveCutoff)*time.Minute,
+		time.Duration(m.CheckInOpen)*time.Minute,
+		time.Duration(m.AttendanceAmend)*time.Minute,
+	)
+	if err != nil || policy.IsZero() {
+		return DefaultBookingPolicy()
+	}
+	return policy
 }
 
 type UserAppointment struct {
@@ -28,6 +62,12 @@ type UserAppointment struct {
 	IsGuest     bool      `json:"is_guest"`
 	ContactInfo string    `json:"contact_info,omitempty"`
 	LeaveReason string    `json:"leave_reason,omitempty"`
+	LeaveStatus string    `json:"leave_status,omitempty"`
+}
+
+// IsLeavePending 請假等待教練審核，名額尚未釋出
+func (a UserAppointment) IsLeavePending() bool {
+	return a.LeaveStatus == string(LeaveStatusPending) && !a.IsOnLeave && !a.IsCheckedIn && !a.IsAbsent
 }
 
 // 使用者角度的預約狀態
@@ -42,4 +82,11 @@ type TrainDateHasUserApptState struct {
 	AllUsers          []string          `json:"other_users"`
 	Capacity          int               `json:"capacity"`
 	AvailableCapacity int               `json:"available_capacity"`
+	// 場次改期時間，之前建立的預約可免責取消
+	RescheduledAt *time.Time `json:"rescheduled_at,omitempty"`
+}
+
+// IsBookedBeforeReschedule 預約建立於場次改期之前
+func (t *TrainDateHasUserApptState) IsBookedBeforeReschedule(appt UserAppointment) bool {
+	return t.RescheduledAt != nil && appt.CreatedAt.Before(*t.RescheduledAt)
 }
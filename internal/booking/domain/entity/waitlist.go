@@ -0,0 +1,386 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"seanAIgent/internal/util/validator"
+	"time"
+)
+
+type waitlistEntryStatus string
+
+func (s waitlistEntryStatus) String() string {
+	return string(s)
+}
+
+const (
+	WaitlistEntryStatusWaiting  waitlistEntryStatus = "WAITING"  // 候補中
+	WaitlistEntryStatusPromoted waitlistEntryStatus = "PROMOTED" // 已遞補為正式預約
+)
+
+var waitlistEntryStatusTrans = map[string]waitlistEntryStatus{
+	string(WaitlistEntryStatusWaiting):  WaitlistEntryStatusWaiting,
+	string(WaitlistEntryStatusPromoted): WaitlistEntryStatusPromoted,
+}
+
+func WaitlistEntryStatusFromString(status string) (waitlistEntryStatus, bool) {
+	s, ok := waitlistEntryStatusTrans[status]
+	return s, ok
+}
+
+// WaitlistEntry 候補名單中的一位學員
+type WaitlistEntry struct {
+	joinedAt   time.Time
+	promotedAt *time.Time
+	notifiedAt *time.Time
+	user       User
+	id         string
+	childName  string
+	apptID     string
+	status     waitlistEntryStatus
+}
+
+type waitlistEntryOpt func(*WaitlistEntry)
+
+func WithWaitlistEntryID(id string) waitlistEntryOpt {
+	return func(e *WaitlistEntry) {
+		e.id = id
+	}
+}
+
+func WithWaitlistEntryUser(u User) waitlistEntryOpt {
+	return func(e *WaitlistEntry) {
+		e.user = u
+	}
+}
+
+func WithWaitlistEntryChildName(name string) waitlistEntryOpt {
+	return func(e *WaitlistEntry) {
+		cleaned, _ := validator.ValidateName(name, 1, 20)
+		e.childName = cleaned
+	}
+}
+
+func WithWaitlistEntryJoinedAt(t time.Time) waitlistEntryOpt {
+	return func(e *WaitlistEntry) {
+		e.joinedAt = t
+	}
+}
+
+func WithWaitlistEntryStatus(status waitlistEntryStatus) waitlistEntryOpt {
+	return func(e *WaitlistEntry) {
+		e.status = status
+	}
+}
+
+func WithWaitlistEntryPromoted(apptID string, promotedAt *time.Time) waitlistEntryOpt {
+	return func(e *WaitlistEntry) {
+		e.apptID = apptID
+		e.promotedAt = promotedAt
+	}
+}
+
+func WithWaitlistEntryNotifiedAt(t *time.Time) waitlistEntryOpt {
+	return func(e *WaitlistEntry) {
+		e.notifiedAt = t
+	}
+}
+
+func NewWaitlistEntry(opts ...waitlistEntryOpt) (*WaitlistEntry, error) {
+	e := &WaitlistEntry{
+		status: WaitlistEntryStatusWaiting,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *WaitlistEntry) Validate() error {
+	if e.id == "" {
+		return fmt.Errorf("%w: id is empty", ErrWaitlistEntryInvalid)
+	}
+	if e.childName == "" {
+		return fmt.Errorf("%w: child name is invalid or too long (max 20 chars)", ErrWaitlistEntryInvalid)
+	}
+	if e.user.UserID() == "" {
+		return fmt.Errorf("%w: user id is empty", ErrWaitlistEntryInvalid)
+	}
+	return nil
+}
+
+// Getter
+func (e *WaitlistEntry) ID() string {
+	return e.id
+}
+
+func (e *WaitlistEntry) User() User {
+	return e.user
+}
+
+func (e *WaitlistEntry) ChildName() string {
+	return e.childName
+}
+
+func (e *WaitlistEntry) Status() waitlistEntryStatus {
+	return e.status
+}
+
+func (e *WaitlistEntry) JoinedAt() time.Time {
+	return e.joinedAt
+}
+
+func (e *WaitlistEntry) PromotedAt() *time.Time {
+	return e.promotedAt
+}
+
+func (e *WaitlistEntry) NotifiedAt() *time.Time {
+	return e.notifiedAt
+}
+
+// ApptID 遞補成功後所建立的預約 ID
+func (e *WaitlistEntry) ApptID() string {
+	return e.apptID
+}
+
+func (e *WaitlistEntry) IsWaiting() bool {
+	return e.status == WaitlistEntryStatusWaiting
+}
+
+// Waitlist 單一課程場次的候補名單 (Aggregate Root)
+type Waitlist struct {
+	updatedAt  time.Time
+	trainingID string
+	entries    []*WaitlistEntry
+	version    int
+}
+
+type waitlistOpt func(*Waitlist)
+
+func WithWaitlistTrainingID(id string) waitlistOpt {
+	return func(w *Waitlist) {
+		w.trainingID = id
+	}
+}
+
+func WithWaitlistEntries(entries []*WaitlistEntry) waitlistOpt {
+	return func(w *Waitlist) {
+		w.entries = entries
+	}
+}
+
+func WithWaitlistVersion(version int) waitlistOpt {
+	return func(w *Waitlist) {
+		w.version = version
+	}
+}
+
+func WithWaitlistUpdatedAt(t time.Time) waitlistOpt {
+	return func(w *Waitlist) {
+		w.updatedAt = t
+	}
+}
+
+func NewWaitlist(opts ...waitlistOpt) (*Waitlist, error) {
+	w := &Waitlist{
+		entries:   make([]*WaitlistEntry, 0),
+		updatedAt: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.trainingID == "" {
+		return nil, fmt.Errorf("%w: training id is empty", ErrWaitlistInvalid)
+	}
+	return w, nil
+}
+
+// Join 加入候補，同一位家長的同一位學員不可重複候補
+func (w *Waitlist) Join(entryID string, user User, childName string) (*WaitlistEntry, error) {
+	entry, err := NewWaitlistEntry(
+		WithWaitlistEntryID(entryID),
+		WithWaitlistEntryUser(user),
+		WithWaitlistEntryChildName(childName),
+		WithWaitlistEntryJoinedAt(time.Now()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range w.entries {
+		if e.IsWaiting() && e.user.userID == user.userID && e.childName == entry.childName {
+			return nil, ErrWaitlistAlreadyJoined
+		}
+	}
+	w.entries = append(w.entries, entry)
+	w.updatedAt = time.Now()
+	return entry, nil
+}
+
+// Leave 家長自行退出候補
+func (w *Waitlist) Leave(entryID, userID string) error {
+	for i, e := range w.entries {
+		if e.id != entryID {
+			continue
+		}
+		if e.user.userID != userID {
+			return ErrWaitlistEntryNotBelongToUser
+		}
+		if !e.IsWaiting() {
+			return ErrWaitlistEntryInvalidStatus
+		}
+		w.entries = append(w.entries[:i], w.entries[i+1:]...)
+		w.updatedAt = time.Now()
+		return nil
+	}
+	return ErrWaitlistEntryNotFound
+}
+
+// Reorder 教練調整候補順序，entryIDs 必須剛好包含所有候補中的學員
+func (w *Waitlist) Reorder(entryIDs []string) error {
+	waiting := w.Waiting()
+	if len(entryIDs) != len(waiting) {
+		return ErrWaitlistReorderMismatch
+	}
+	byID := make(map[string]*WaitlistEntry, len(waiting))
+	for _, e := range waiting {
+		byID[e.id] = e
+	}
+	reordered := make([]*WaitlistEntry, 0, len(w.entries))
+	for _, id := range entryIDs {
+		e, ok := byID[id]
+		if !ok {
+			return ErrWaitlistReorderMismatch
+		}
+		delete(byID, id)
+		reordered = append(reordered, e)
+	}
+	// 已遞補的紀錄保留在名單後方，供通知使用
+	for _, e := range w.entries {
+		if !e.IsWaiting() {
+			reordered = append(reordered, e)
+		}
+	}
+	w.entries = reordered
+	w.updatedAt = time.Now()
+	return nil
+}
+
+// Next 取得下一位候補學員
+func (w *Waitlist) Next() (*WaitlistEntry, bool) {
+	for _, e := range w.entries {
+		if e.IsWaiting() {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// Promote 將候補學員標記為已遞補
+func (w *Waitlist) Promote(entryID, apptID string) error {
+	e, ok := w.findEntry(entryID)
+	if !ok {
+		return ErrWaitlistEntryNotFound
+	}
+	if !e.IsWaiting() {
+		return ErrWaitlistEntryInvalidStatus
+	}
+	now := time.Now()
+	e.status = WaitlistEntryStatusPromoted
+	e.apptID = apptID
+	e.promotedAt = &now
+	w.updatedAt = now
+	return nil
+}
+
+// RevertPromotion 遞補後建立預約失敗時，將學員放回原本的候補位置
+func (w *Waitlist) RevertPromotion(entryID string) error {
+	e, ok := w.findEntry(entryID)
+	if !ok {
+		return ErrWaitlistEntryNotFound
+	}
+	if e.status != WaitlistEntryStatusPromoted || e.notifiedAt != nil {
+		return ErrWaitlistEntryInvalidStatus
+	}
+	e.status = WaitlistEntryStatusWaiting
+	e.apptID = ""
+	e.promotedAt = nil
+	w.updatedAt = time.Now()
+	return nil
+}
+
+// MarkNotified 記錄已通知家長遞補結果
+func (w *Waitlist) MarkNotified(entryID string) error {
+	e, ok := w.findEntry(entryID)
+	if !ok {
+		return ErrWaitlistEntryNotFound
+	}
+	if e.status != WaitlistEntryStatusPromoted {
+		return ErrWaitlistEntryInvalidStatus
+	}
+	now := time.Now()
+	e.notifiedAt = &now
+	w.updatedAt = now
+	return nil
+}
+
+func (w *Waitlist) findEntry(entryID string) (*WaitlistEntry, bool) {
+	for _, e := range w.entries {
+		if e.id == entryID {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// Waiting 依順序回傳候補中的學員
+func (w *Waitlist) Waiting() []*WaitlistEntry {
+	res := make([]*WaitlistEntry, 0, len(w.entries))
+	for _, e := range w.entries {
+		if e.IsWaiting() {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// PendingNotices 已遞補但尚未通知家長的學員
+func (w *Waitlist) PendingNotices() []*WaitlistEntry {
+	res := make([]*WaitlistEntry, 0)
+	for _, e := range w.entries {
+		if e.status == WaitlistEntryStatusPromoted && e.notifiedAt == nil {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Getter
+func (w *Waitlist) TrainingID() string {
+	return w.trainingID
+}
+
+func (w *Waitlist) Entries() []*WaitlistEntry {
+	return w.entries
+}
+
+func (w *Waitlist) Version() int {
+	return w.version
+}
+
+func (w *Waitlist) UpdatedAt() time.Time {
+	return w.updatedAt
+}
+
+// Error Definition
+var (
+	ErrWaitlistInvalid              = errors.New("WAITLIST_INVALID")
+	ErrWaitlistEntryInvalid         = errors.New("WAITLIST_ENTRY_INVALID")
+	ErrWaitlistAlreadyJoined        = errors.New("WAITLIST_ALREADY_JOINED")
+	ErrWaitlistEntryNotFound        = errors.New("WAITLIST_ENTRY_NOT_FOUND")
+	ErrWaitlistEntryNotBelongToUser = errors.New("WAITLIST_ENTRY_NOT_BELONG_TO_USER")
+	ErrWaitlistEntryInvalidStatus   = errors.New("WAITLIST_ENTRY_INVALID_STATUS")
+	ErrWaitlistReorderMismatch      = errors.New("WAITLIST_REORDER_MISMATCH")
+)
@@ -0,0 +1,166 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWaitlist(t *testing.T) *Waitlist {
+	wl, err := NewWaitlist(WithWaitlistTrainingID("t1"))
+	require.NoError(t, err)
+	return wl
+}
+
+func TestNewWaitlist(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		wl, err := NewWaitlist(WithWaitlistTrainingID("t1"))
+		require.NoError(t, err)
+		assert.Equal(t, "t1", wl.TrainingID())
+		assert.Empty(t, wl.Entries())
+	})
+
+	t.Run("Fail_NoTrainingID", func(t *testing.T) {
+		wl, err := NewWaitlist()
+		assert.ErrorIs(t, err, ErrWaitlistInvalid)
+		assert.Nil(t, wl)
+	})
+}
+
+func TestWaitlist_Join(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+
+	t.Run("Success", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		entry, err := wl.Join("e1", user, "Child")
+		require.NoError(t, err)
+		assert.Equal(t, WaitlistEntryStatusWaiting, entry.Status())
+		assert.Len(t, wl.Waiting(), 1)
+	})
+
+	t.Run("Fail_AlreadyJoined", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "Child")
+		_, err := wl.Join("e2", user, "Child")
+		assert.ErrorIs(t, err, ErrWaitlistAlreadyJoined)
+	})
+
+	t.Run("Fail_InvalidName", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, err := wl.Join("e1", user, "")
+		assert.ErrorIs(t, err, ErrWaitlistEntryInvalid)
+	})
+}
+
+func TestWaitlist_Leave(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+
+	t.Run("Success", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "Child")
+		require.NoError(t, wl.Leave("e1", "u1"))
+		assert.Empty(t, wl.Waiting())
+	})
+
+	t.Run("Fail_NotUser", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "Child")
+		assert.ErrorIs(t, wl.Leave("e1", "u2"), ErrWaitlistEntryNotBelongToUser)
+	})
+
+	t.Run("Fail_NotFound", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		assert.ErrorIs(t, wl.Leave("e1", "u1"), ErrWaitlistEntryNotFound)
+	})
+}
+
+func TestWaitlist_Reorder(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+
+	t.Run("Success", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "A")
+		_, _ = wl.Join("e2", user, "B")
+		_, _ = wl.Join("e3", user, "C")
+
+		require.NoError(t, wl.Reorder([]string{"e3", "e1", "e2"}))
+		next, ok := wl.Next()
+		require.True(t, ok)
+		assert.Equal(t, "e3", next.ID())
+	})
+
+	t.Run("Fail_Mismatch", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "A")
+		_, _ = wl.Join("e2", user, "B")
+
+		assert.ErrorIs(t, wl.Reorder([]string{"e1"}), ErrWaitlistReorderMismatch)
+		assert.ErrorIs(t, wl.Reorder([]string{"e1", "e1"}), ErrWaitlistReorderMismatch)
+		assert.ErrorIs(t, wl.Reorder([]string{"e1", "x"}), ErrWaitlistReorderMismatch)
+	})
+
+	t.Run("KeepPromotedEntries", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "A")
+		_, _ = wl.Join("e2", user, "B")
+		_, _ = wl.Join("e3", user, "C")
+		require.NoError(t, wl.Promote("e1", "a1"))
+
+		require.NoError(t, wl.Reorder([]string{"e3", "e2"}))
+		assert.Len(t, wl.Entries(), 3)
+		assert.Len(t, wl.Waiting(), 2)
+	})
+}
+
+func TestWaitlist_Promote(t *testing.T) {
+	user, _ := NewUser("u1", "User")
+
+	t.Run("Success", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "A")
+		_, _ = wl.Join("e2", user, "B")
+
+		next, ok := wl.Next()
+		require.True(t, ok)
+		require.NoError(t, wl.Promote(next.ID(), "a1"))
+		assert.Equal(t, WaitlistEntryStatusPromoted, next.Status())
+		assert.Equal(t, "a1", next.ApptID())
+		assert.NotNil(t, next.PromotedAt())
+
+		next, ok = wl.Next()
+		require.True(t, ok)
+		assert.Equal(t, "e2", next.ID())
+		assert.Len(t, wl.PendingNotices(), 1)
+	})
+
+	t.Run("Fail_AlreadyPromoted", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "A")
+		_ = wl.Promote("e1", "a1")
+		assert.ErrorIs(t, wl.Promote("e1", "a2"), ErrWaitlistEntryInvalidStatus)
+	})
+
+	t.Run("MarkNotified", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "A")
+		assert.ErrorIs(t, wl.MarkNotified("e1"), ErrWaitlistEntryInvalidStatus)
+
+		_ = wl.Promote("e1", "a1")
+		require.NoError(t, wl.MarkNotified("e1"))
+		assert.Empty(t, wl.PendingNotices())
+	})
+
+	t.Run("RevertPromotion", func(t *testing.T) {
+		wl := newTestWaitlist(t)
+		_, _ = wl.Join("e1", user, "A")
+		assert.ErrorIs(t, wl.RevertPromotion("e1"), ErrWaitlistEntryInvalidStatus)
+
+		_ = wl.Promote("e1", "a1")
+		require.NoError(t, wl.RevertPromotion("e1"))
+		next, ok := wl.Next()
+		require.True(t, ok)
+		assert.Equal(t, "e1", next.ID())
+		assert.Empty(t, next.ApptID())
+	})
+}
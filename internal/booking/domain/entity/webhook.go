@@ -0,0 +1,221 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	maxWebhookNameLen = 30
+	webhookSecretLen  = 32
+)
+
+// WebhookEndpoint 外部系統接收領域事件的 HTTP 端點，請求以 secret 做 HMAC-SHA256 簽章
+type WebhookEndpoint struct {
+	createdAt time.Time
+	updatedAt time.Time
+	id        string
+	name      string
+	url       string
+	secret    string
+	createdBy string
+	topics    []string
+	enabled   bool
+}
+
+type webhookEndpointOpt func(*WebhookEndpoint)
+
+func WithWebhookEndpointID(id string) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.id = id
+	}
+}
+
+func WithWebhookEndpointName(name string) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.name = strings.TrimSpace(name)
+	}
+}
+
+func WithWebhookEndpointURL(u string) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.url = strings.TrimSpace(u)
+	}
+}
+
+func WithWebhookEndpointSecret(secret string) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.secret = secret
+	}
+}
+
+func WithWebhookEndpointTopics(topics ...string) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.topics = topics
+	}
+}
+
+func WithWebhookEndpointEnabled(enabled bool) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.enabled = enabled
+	}
+}
+
+func WithWebhookEndpointCreatedBy(userID string) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.createdBy = userID
+	}
+}
+
+func WithWebhookEndpointCreatedAt(createdAt time.Time) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.createdAt = createdAt
+	}
+}
+
+func WithWebhookEndpointUpdatedAt(updatedAt time.Time) webhookEndpointOpt {
+	return func(w *WebhookEndpoint) {
+		w.updatedAt = updatedAt
+	}
+}
+
+// NewWebhookEndpoint 未指定 secret 時自動產生
+func NewWebhookEndpoint(opts ...webhookEndpointOpt) (*WebhookEndpoint, error) {
+	now := time.Now()
+	w := &WebhookEndpoint{
+		createdAt: now,
+		updatedAt: now,
+		enabled:   true,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.id == "" {
+		return nil, fmt.Errorf("%w: id is empty", ErrWebhookEndpointInvalid)
+	}
+	if w.secret == "" {
+		w.secret = NewWebhookSecret()
+	}
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// NewWebhookSecret 產生簽章用的隨機金鑰
+func NewWebhookSecret() string {
+	b := make([]byte, webhookSecretLen)
+	_, _ = rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+func (w *WebhookEndpoint) validate() error {
+	if w.name == "" || len([]rune(w.name)) > maxWebhookNameLen {
+		return fmt.Errorf("%w: name is empty or too long", ErrWebhookEndpointInvalid)
+	}
+	u, err := url.Parse(w.url)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) url", ErrWebhookEndpointInvalid)
+	}
+	topics := make([]string, 0, len(w.topics))
+	for _, t := range w.topics {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(topics, t) {
+			topics = append(topics, t)
+		}
+	}
+	if len(topics) == 0 {
+		return fmt.Errorf("%w: no topics", ErrWebhookEndpointInvalid)
+	}
+	w.topics = topics
+	return nil
+}
+
+// Update 修改名稱、網址、主題與啟用狀態，secret 不變
+func (w *WebhookEndpoint) Update(name, u string, topics []string, enabled bool) error {
+	updated := *w
+	WithWebhookEndpointName(name)(&updated)
+	WithWebhookEndpointURL(u)(&updated)
+	updated.topics = topics
+	updated.enabled = enabled
+	if err := updated.validate(); err != nil {
+		return err
+	}
+	updated.updatedAt = time.Now()
+	*w = updated
+	return nil
+}
+
+// RotateSecret 更換金鑰，舊金鑰立即失效
+func (w *WebhookEndpoint) RotateSecret() {
+	w.secret = NewWebhookSecret()
+	w.updatedAt = time.Now()
+}
+
+// Receives 端點已啟用且訂閱了此主題
+func (w *WebhookEndpoint) Receives(topic string) bool {
+	return w.enabled && slices.Contains(w.topics, topic)
+}
+
+func (w *WebhookEndpoint) ID() string {
+	return w.id
+}
+
+func (w *WebhookEndpoint) Name() string {
+	return w.name
+}
+
+func (w *WebhookEndpoint) URL() string {
+	return w.url
+}
+
+func (w *WebhookEndpoint) Secret() string {
+	return w.secret
+}
+
+func (w *WebhookEndpoint) Topics() []string {
+	return slices.Clone(w.topics)
+}
+
+func (w *WebhookEndpoint) Enabled() bool {
+	return w.enabled
+}
+
+func (w *WebhookEndpoint) CreatedBy() string {
+	return w.createdBy
+}
+
+func (w *WebhookEndpoint) CreatedAt() time.Time {
+	return w.createdAt
+}
+
+func (w *WebhookEndpoint) UpdatedAt() time.Time {
+	return w.updatedAt
+}
+
+// WebhookDelivery 一次送出的紀錄，重試時每次各一筆
+type WebhookDelivery struct {
+	DeliveredAt time.Time
+	ID          string
+	EndpointID  string
+	EventID     string
+	Topic       string
+	Error       string
+	Attempt     int
+	StatusCode  int
+	Duration    time.Duration
+}
+
+// Succeeded 端點回應 2xx
+func (d *WebhookDelivery) Succeeded() bool {
+	return d.Error == "" && d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+var (
+	ErrWebhookEndpointInvalid = errors.New("WEBHOOK_ENDPOINT_INVALID")
+)
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookEndpoint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		w, err := NewWebhookEndpoint(
+			WithWebhookEndpointID("w1"),
+			WithWebhookEndpointName(" 會計系統 "),
+			WithWebhookEndpointURL("https://example.com/hook"),
+			WithWebhookEndpointTopics("a", " a ", "b", ""),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "會計系統", w.Name())
+		assert.Equal(t, []string{"a", "b"}, w.Topics())
+		assert.True(t, w.Enabled())
+		assert.True(t, strings.HasPrefix(w.Secret(), "whsec_"))
+	})
+
+	t.Run("Fail_URL", func(t *testing.T) {
+		for _, u := range []string{"", "example.com/hook", "ftp://example.com", "https://"} {
+			_, err := NewWebhookEndpoint(
+				WithWebhookEndpointID("w1"), WithWebhookEndpointName("n"),
+				WithWebhookEndpointURL(u), WithWebhookEndpointTopics("a"))
+			assert.ErrorIs(t, err, ErrWebhookEndpointInvalid, u)
+		}
+	})
+
+	t.Run("Fail_NoTopics", func(t *testing.T) {
+		_, err := NewWebhookEndpoint(
+			WithWebhookEndpointID("w1"), WithWebhookEndpointName("n"),
+			WithWebhookEndpointURL("https://example.com"), WithWebhookEndpointTopics(" "))
+		assert.ErrorIs(t, err, ErrWebhookEndpointInvalid)
+	})
+}
+
+func TestWebhookEndpoint_Update(t *testing.T) {
+	w, err := NewWebhookEndpoint(
+		WithWebhookEndpointID("w1"), WithWebhookEndpointName("n"),
+		WithWebhookEndpointURL("https://example.com"), WithWebhookEndpointTopics("a"))
+	require.NoError(t, err)
+	secret := w.Secret()
+
+	// 驗證失敗時不修改原本的設定
+	assert.ErrorIs(t, w.Update("n2", "not a url", []string{"b"}, false), ErrWebhookEndpointInvalid)
+	assert.Equal(t, "https://example.com", w.URL())
+	assert.True(t, w.Receives("a"))
+
+	require.NoError(t, w.Update("n2", "http://example.com/v2", []string{"b"}, false))
+	assert.Equal(t, "n2", w.Name())
+	assert.False(t, w.Receives("b"), "disabled endpoint receives nothing")
+	assert.Equal(t, secret, w.Secret())
+
+	w.RotateSecret()
+	assert.NotEqual(t, secret, w.Secret())
+}
+
+func TestWebhookDelivery_Succeeded(t *testing.T) {
+	assert.True(t, (&WebhookDelivery{StatusCode: 204}).Succeeded())
+	assert.False(t, (&WebhookDelivery{StatusCode: 500, Error: "500 Internal Server Error"}).Succeeded())
+	assert.False(t, (&WebhookDelivery{Error: "timeout"}).Succeeded())
+}
@@ -5,10 +5,38 @@ import (
 )
 
 const (
-	TopicAppointmentStatusChanged = "booking.appointment.status_changed"
+	TopicAppointmentStatusChanged  = "booking.appointment.status_changed"
 	TopicUserStatsRefreshRequested = "booking.stats.refresh_requested"
+	TopicWaitlistJoined            = "booking.waitlist.joined"
+	TopicTrainDateCreated          = "booking.train_date.created"
+	TopicTrainDateDeleted          = "booking.train_date.deleted"
+	TopicTrainDateCancelled        = "booking.train_date.cancelled"
+	TopicTrainDateRescheduled      = "booking.train_date.rescheduled"
+	TopicTrainDateUpdated          = "booking.train_date.updated"
+	TopicTrainDateCapacityChanged  = "booking.train_date.capacity_changed"
+	TopicLeaveRequested            = "booking.leave.requested"
+	TopicLeaveReviewed             = "booking.leave.reviewed"
+	TopicLeaveCancelled            = "booking.leave.cancelled"
+	TopicWalkInCreated             = "booking.walk_in.created"
+	TopicLeaveReasonChanged        = "booking.leave.reason_changed"
+	TopicGuestBooked               = "booking.guest.booked"
+	TopicStudentRenamed            = "booking.student.renamed"
+	TopicStudentsMerged            = "booking.student.merged"
+	TopicChildNamesMigrated        = "booking.student.child_names_migrated"
+	// TopicWebhookPing 後台測試 webhook 端點，只送到指定端點，不寫入事件紀錄
+	TopicWebhookPing = "webhook.ping"
 )
 
+// WebhookTopics 可轉送到外部 webhook 的主題
+func WebhookTopics() []string {
+	return []string{
+		TopicAppointmentStatusChanged,
+		TopicUserStatsRefreshRequested,
+		TopicTrainDateCreated,
+		TopicTrainDateDeleted,
+	}
+}
+
 // AppointmentStatusChanged 預約狀態變更事件 Payload
 type AppointmentStatusChanged struct {
 	BookingID  string    `json:"booking_id"`
@@ -27,3 +55,176 @@ type UserStatsRefreshRequested struct {
 	Reason     string    `json:"reason"`
 	OccurredAt time.Time `json:"occurred_at"`
 }
+
+// WaitlistJoined 家長加入候補，用於檢查是否已有空位可立即遞補
+type WaitlistJoined struct {
+	TrainingID string    `json:"training_id"`
+	UserID     string    `json:"user_id"`
+	EntryIDs   []string  `json:"entry_ids"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// TrainDateCreated 新增場次，批次新增時每個場次各一筆
+type TrainDateCreated struct {
+	TrainingID string    `json:"training_id"`
+	CoachID    string    `json:"coach_id"`
+	Location   string    `json:"location"`
+	Capacity   int       `json:"capacity"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	TeamID     string    `json:"team_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// TrainDateDeleted 刪除沒有預約的場次
+type TrainDateDeleted struct {
+	TrainingID string    `json:"training_id"`
+	CoachID    string    `json:"coach_id"`
+	Location   string    `json:"location"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	DeletedBy  string    `json:"deleted_by"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// TrainDateCancelled 教練停課，各預約另有 AppointmentStatusChanged 事件
+type TrainDateCancelled struct {
+	TrainingID      string    `json:"training_id"`
+	Reason          string    `json:"reason"`
+	CancelledBy     string    `json:"cancelled_by"`
+	AffectedUserIDs []string  `json:"affected_user_ids"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// TrainDateRescheduled 教練調整場次時間或地點，預約維持不變
+type TrainDateRescheduled struct {
+	TrainingID       string    `json:"training_id"`
+	PreviousStart    time.Time `json:"previous_start"`
+	PreviousEnd      time.Time `json:"previous_end"`
+	PreviousLocation string    `json:"previous_location"`
+	NewStart         time.Time `json:"new_start"`
+	NewEnd           time.Time `json:"new_end"`
+	NewLocation      string    `json:"new_location"`
+	RescheduledBy    string    `json:"rescheduled_by"`
+	AffectedUserIDs  []string  `json:"affected_user_ids"`
+	OccurredAt       time.Time `json:"occurred_at"`
+}
+
+// TrainDateUpdated 的異動欄位
+const (
+	TrainDateFieldBookingPolicy = "booking_policy"
+	TrainDateFieldTeam          = "team_id"
+	TrainDateFieldDetails       = "details"   // 尚無預約的系列場次修改地點、時間或名額
+	TrainDateFieldSeries        = "series_id" // 加入或脫離系列
+)
+
+// TrainDateUpdated 調整場次設定，已有預約的場次改時間地點另有 TrainDateRescheduled 事件
+type TrainDateUpdated struct {
+	TrainingID string    `json:"training_id"`
+	Fields     []string  `json:"fields"`
+	TeamID     string    `json:"team_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// TrainDateCapacityChanged 對帳修正剩餘名額，一般預約與取消的名額變動不另發事件
+type TrainDateCapacityChanged struct {
+	TrainingID        string    `json:"training_id"`
+	Capacity          int       `json:"capacity"`
+	PreviousAvailable int       `json:"previous_available"`
+	Available         int       `json:"available"`
+	Reason            string    `json:"reason"`
+	OccurredAt        time.Time `json:"occurred_at"`
+}
+
+// LeaveRequested 團隊設定請假需核准時，家長送出的請假申請，預約狀態不變
+type LeaveRequested struct {
+	BookingID  string    `json:"booking_id"`
+	UserID     string    `json:"user_id"`
+	TrainingID string    `json:"training_id"`
+	TeamID     string    `json:"team_id"`
+	Reason     string    `json:"reason"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// LeaveReviewed 教練審核請假，核准時另有 AppointmentStatusChanged 事件
+type LeaveReviewed struct {
+	BookingID  string    `json:"booking_id"`
+	UserID     string    `json:"user_id"`
+	TrainingID string    `json:"training_id"`
+	Approved   bool      `json:"approved"`
+	ReviewedBy string    `json:"reviewed_by"`
+	Comment    string    `json:"comment"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// LeaveCancelled 家長撤回請假，Released 為 true 代表已核准的請假扣回名額，另有 AppointmentStatusChanged 事件
+type LeaveCancelled struct {
+	BookingID  string    `json:"booking_id"`
+	UserID     string    `json:"user_id"`
+	TrainingID string    `json:"training_id"`
+	Released   bool      `json:"released"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// WalkInCreated 現場報到新增的預約，另有 AppointmentStatusChanged 事件；Guest 為 true 時 UserID 為 GUEST_ 加手機號碼
+type WalkInCreated struct {
+	BookingID  string    `json:"booking_id"`
+	UserID     string    `json:"user_id"`
+	TrainingID string    `json:"training_id"`
+	ChildName  string    `json:"child_name"`
+	Guest      bool      `json:"guest"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// LeaveReasonChanged 家長修改請假原因，預約與審核狀態不變
+type LeaveReasonChanged struct {
+	BookingID      string    `json:"booking_id"`
+	UserID         string    `json:"user_id"`
+	TrainingID     string    `json:"training_id"`
+	PreviousReason string    `json:"previous_reason"`
+	Reason         string    `json:"reason"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// GuestBooked 現場報到建立的體驗預約，與 WalkInCreated 同時發布，供後續聯繫體驗家長
+type GuestBooked struct {
+	BookingID   string    `json:"booking_id"`
+	UserID      string    `json:"user_id"`
+	TrainingID  string    `json:"training_id"`
+	ChildName   string    `json:"child_name"`
+	ParentName  string    `json:"parent_name"`
+	ContactInfo string    `json:"contact_info"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// StudentRenamed 學員改名，既有預約上的姓名已同步更新
+type StudentRenamed struct {
+	StudentID    string    `json:"student_id"`
+	UserID       string    `json:"user_id"`
+	PreviousName string    `json:"previous_name"`
+	Name         string    `json:"name"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// StudentsMerged 來源學員的預約併入目標學員，來源學員已刪除
+type StudentsMerged struct {
+	TargetID   string    `json:"target_id"`
+	UserID     string    `json:"user_id"`
+	SourceIDs  []string  `json:"source_ids"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ChildNamesMigrated 舊預約依孩童姓名對應到學員，影響多位家長，每次執行一筆
+type ChildNamesMigrated struct {
+	Linked          int       `json:"linked"`
+	CreatedStudents int       `json:"created_students"`
+	Failed          int       `json:"failed"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// WebhookPing 測試 webhook 端點的 Payload
+type WebhookPing struct {
+	EndpointID  string    `json:"endpoint_id"`
+	RequestedBy string    `json:"requested_by"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "seanAIgent/internal/event"
+
+// Payload 有不相容的調整時保留舊版結構，並在 init 登記轉換到新版的 upcaster，例如：
+//
+//	event.RegisterUpcaster(TopicLeaveReviewed, 1, event.UpcastJSON(func(v1 leaveReviewedV1) LeaveReviewed { ... }))
+//
+// 登記後新發布的事件為第 2 版，事件紀錄中的第 1 版在訂閱者解碼前轉換；
+// 同時在 testdata/events 加入新版的範例，舊版範例保留到事件紀錄過期為止。
+//
+// AppointmentStatusChanged 第 1 版包含加入 training_id 前寫入的紀錄，TrainingID 為空，訂閱者需略過。
+
+// EventSchemas 各主題目前的 Payload 型別，新增主題時一併加入
+func EventSchemas() []event.SchemaCheck {
+	return []event.SchemaCheck{
+		event.CheckSchema[AppointmentStatusChanged](TopicAppointmentStatusChanged),
+		event.CheckSchema[UserStatsRefreshRequested](TopicUserStatsRefreshRequested),
+		event.CheckSchema[WaitlistJoined](TopicWaitlistJoined),
+		event.CheckSchema[TrainDateCreated](TopicTrainDateCreated),
+		event.CheckSchema[TrainDateDeleted](TopicTrainDateDeleted),
+		event.CheckSchema[TrainDateCancelled](TopicTrainDateCancelled),
+		event.CheckSchema[TrainDateRescheduled](TopicTrainDateRescheduled),
+		event.CheckSchema[TrainDateUpdated](TopicTrainDateUpdated),
+		event.CheckSchema[TrainDateCapacityChanged](TopicTrainDateCapacityChanged),
+		event.CheckSchema[LeaveRequested](TopicLeaveRequested),
+		event.CheckSchema[LeaveReviewed](TopicLeaveReviewed),
+		event.CheckSchema[LeaveCancelled](TopicLeaveCancelled),
+		event.CheckSchema[WalkInCreated](TopicWalkInCreated),
+		event.CheckSchema[LeaveReasonChanged](TopicLeaveReasonChanged),
+		event.CheckSchema[GuestBooked](TopicGuestBooked),
+		event.CheckSchema[StudentRenamed](TopicStudentRenamed),
+		event.CheckSchema[StudentsMerged](TopicStudentsMerged),
+		event.CheckSchema[ChildNamesMigrated](TopicChildNamesMigrated),
+	}
+}
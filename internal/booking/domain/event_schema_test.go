@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// 範例檔名為 <topic>.v<version>[.<說明>].json
+var fixtureName = regexp.MustCompile(`^(.+)\.v(\d+)(\..+)?\.json$`)
+
+// TestEventSchemas 確認 testdata/events 中各版本的 Payload 都能以目前的型別解碼
+func TestEventSchemas(t *testing.T) {
+	ctx := t.Context()
+	files, err := filepath.Glob("testdata/events/*.json")
+	require.NoError(t, err)
+
+	store := event.NewMemoryStore(event.WithMemoryTTL(0))
+	latest := make(map[string]int)
+	for i, file := range files {
+		m := fixtureName.FindStringSubmatch(filepath.Base(file))
+		require.NotNil(t, m, "unexpected fixture name %s", file)
+		version, _ := strconv.Atoi(m[2])
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		id := "evt_" + strconv.Itoa(i)
+		require.NoError(t, store.Save(ctx, event.NewStoredEvent(id, m[1], version, time.Now(), data)))
+		latest[m[1]] = max(latest[m[1]], version)
+	}
+
+	report, err := event.VerifyStoredEvents(ctx, store, EventSchemas()...)
+	require.NoError(t, err)
+	assert.Empty(t, report.Failures)
+	assert.Empty(t, report.Unchecked, "topics missing from EventSchemas")
+	for _, c := range EventSchemas() {
+		assert.Equal(t, event.Schemas.CurrentVersion(c.Topic), latest[c.Topic],
+			"testdata/events needs a sample of the current version of %s", c.Topic)
+	}
+}
+
+// TestEventSchemas_EventLogs 設定 EVENT_LOGS_MONGO_URI 與 EVENT_LOGS_MONGO_DB 時，
+// 檢查實際 event_logs 保留期間內的事件
+func TestEventSchemas_EventLogs(t *testing.T) {
+	uri, dbName := os.Getenv("EVENT_LOGS_MONGO_URI"), os.Getenv("EVENT_LOGS_MONGO_DB")
+	if uri == "" || dbName == "" {
+		t.Skip("EVENT_LOGS_MONGO_URI or EVENT_LOGS_MONGO_DB not set")
+	}
+	ctx := t.Context()
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	store, err := event.NewMongoEventStore(client.Database(dbName))
+	require.NoError(t, err)
+	report, err := event.VerifyStoredEvents(ctx, store.(event.EventLog), EventSchemas()...)
+	require.NoError(t, err)
+	t.Logf("versions: %v, unchecked: %v", report.Versions, report.Unchecked)
+	for _, f := range report.Failures {
+		t.Errorf("cannot decode %s", f)
+	}
+	assert.Empty(t, report.Unchecked, "topics missing from EventSchemas")
+}
@@ -65,7 +65,10 @@ type AppointmentRepository interface {
 	FindApptByID(ctx context.Context, id string) (*entity.Appointment, RepoError)
 	FindApptsByFilter(ctx context.Context, filter FilterAppointment) ([]*entity.Appointment, RepoError)
 
-	MarkAbsentByTrainIDs(ctx context.Context, trainDateIDs []string) (affectedUserIDs []string, err RepoError)
+	// MarkAbsentByTrainIDs 將場次中仍為已確認的預約標記為缺席，回傳標記後的預約
+	MarkAbsentByTrainIDs(ctx context.Context, trainDateIDs []string) (absent []*entity.Appointment, err RepoError)
+	// ReassignStudent 將 fromStudentIDs 的預約改為 to 學員並同步姓名，同一場次已有 to 的預約時回傳 conflict
+	ReassignStudent(ctx context.Context, fromStudentIDs []string, to *entity.Student) RepoError
 
 	PageFindApptsWithTrainDateByFilterAndTrainFilter(
 		ctx context.Context,
@@ -109,3 +112,34 @@ type FilterAppointmentByUserID struct {
 }
 
 func (f FilterAppointmentByUserID) isCriteria() {}
+
+// 條件：指定學員的所有預約
+func NewFilterApptByStudentIDs(ids ...string) FilterAppointment {
+	return FilterApptByStudentIDs{StudentIDs: ids}
+}
+
+type FilterApptByStudentIDs struct {
+	StudentIDs []string
+}
+
+func (f FilterApptByStudentIDs) isCriteria() {}
+
+// 條件：尚未對應學員的舊預約，供資料轉換使用，ExcludeIDs 為無法轉換而略過的預約
+func NewFilterApptWithoutStudent(excludeIDs ...string) FilterAppointment {
+	return FilterApptWithoutStudent{ExcludeIDs: excludeIDs}
+}
+
+type FilterApptWithoutStudent struct {
+	ExcludeIDs []string
+}
+
+func (f FilterApptWithoutStudent) isCriteria() {}
+
+// 條件：請假申請或審核結果尚未推播
+func NewFilterApptHasPendingLeaveNotice() FilterAppointment {
+	return FilterApptHasPendingLeaveNotice{}
+}
+
+type FilterApptHasPendingLeaveNotice struct{}
+
+func (f FilterApptHasPendingLeaveNotice) isCriteria() {}
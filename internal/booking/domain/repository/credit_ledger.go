@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type CreditLedgerRepository interface {
+	// 以 version 做樂觀鎖，版本不符時回傳 ErrConflict
+	SaveCreditLedger(ctx context.Context, ledger *entity.CreditLedger) RepoError
+
+	FindCreditLedgerByUserID(ctx context.Context, userID string) (*entity.CreditLedger, RepoError)
+}
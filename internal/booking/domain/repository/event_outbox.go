@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"seanAIgent/internal/event"
+)
+
+// EventOutbox 將領域事件與業務資料寫入同一個交易，提交後才由 relay 分發給訂閱者
+type EventOutbox interface {
+	AddEvents(ctx context.Context, events ...event.Event) RepoError
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type MakeUpCreditRepository interface {
+	// 以 version 做樂觀鎖，版本不符時回傳 ErrConflict
+	SaveMakeUpCredit(ctx context.Context, credit *entity.MakeUpCredit) RepoError
+
+	FindMakeUpCreditByID(ctx context.Context, id string) (*entity.MakeUpCredit, RepoError)
+	FindMakeUpCreditsByFilter(ctx context.Context, filter FilterMakeUpCredit) ([]*entity.MakeUpCredit, RepoError)
+}
+
+// Filter
+type FilterMakeUpCredit interface {
+	isCriteria() // 標記用介面
+}
+
+// 條件 A：家長的所有補課資格
+func NewFilterMakeUpCreditByUserID(userID string) FilterMakeUpCredit {
+	return FilterMakeUpCreditByUserID{UserID: userID}
+}
+
+type FilterMakeUpCreditByUserID struct {
+	UserID string
+}
+
+func (f FilterMakeUpCreditByUserID) isCriteria() {}
+
+// 條件 B：某筆請假發放的補課資格
+func NewFilterMakeUpCreditByLeaveApptID(apptID string) FilterMakeUpCredit {
+	return FilterMakeUpCreditByLeaveApptID{ApptID: apptID}
+}
+
+type FilterMakeUpCreditByLeaveApptID struct {
+	ApptID string
+}
+
+func (f FilterMakeUpCreditByLeaveApptID) isCriteria() {}
+
+// 條件 C：某筆補課預約使用的補課資格
+func NewFilterMakeUpCreditByRedeemedApptID(apptID string) FilterMakeUpCredit {
+	return FilterMakeUpCreditByRedeemedApptID{ApptID: apptID}
+}
+
+type FilterMakeUpCreditByRedeemedApptID struct {
+	ApptID string
+}
+
+func (f FilterMakeUpCreditByRedeemedApptID) isCriteria() {}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type PaymentRepository interface {
+	// 收款紀錄只新增不修改
+	SavePayment(ctx context.Context, payment *entity.Payment) RepoError
+
+	FindPaymentsByFilter(ctx context.Context, filter FilterPayment) ([]*entity.Payment, RepoError)
+}
+
+// Filter
+type FilterPayment interface {
+	isCriteria() // 標記用介面
+}
+
+// 條件 A：某帳務月份的所有收款
+func NewFilterPaymentByPeriod(year, month int) FilterPayment {
+	return FilterPaymentByPeriod{Year: year, Month: month}
+}
+
+type FilterPaymentByPeriod struct {
+	Year  int
+	Month int
+}
+
+func (f FilterPaymentByPeriod) isCriteria() {}
+
+// 條件 B：某家長在某帳務月份的收款
+func NewFilterPaymentByUserPeriod(userID string, year, month int) FilterPayment {
+	return FilterPaymentByUserPeriod{UserID: userID, Year: year, Month: month}
+}
+
+type FilterPaymentByUserPeriod struct {
+	UserID string
+	Year   int
+	Month  int
+}
+
+func (f FilterPaymentByUserPeriod) isCriteria() {}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -23,3 +25,42 @@ func TestFilterApptsWithTrainDateByCursor(t *testing.T) {
 		assert.Equal(t, cursor.PageSize, decoded.PageSize)
 	})
 }
+
+func TestRunWithCompensation(t *testing.T) {
+	t.Run("RollbackInReverseOrder", func(t *testing.T) {
+		var undone []string
+		errSave := errors.New("save fail")
+		err := RunWithCompensation(context.Background(), func(ctx context.Context) error {
+			Compensate(ctx, func(context.Context) error { undone = append(undone, "deduct"); return nil })
+			Compensate(ctx, func(context.Context) error { undone = append(undone, "credit"); return nil })
+			return errSave
+		})
+		assert.ErrorIs(t, err, errSave)
+		assert.Equal(t, []string{"credit", "deduct"}, undone)
+	})
+
+	t.Run("NoRollbackOnSuccess", func(t *testing.T) {
+		called := false
+		err := RunWithCompensation(context.Background(), func(ctx context.Context) error {
+			Compensate(ctx, func(context.Context) error { called = true; return nil })
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("ReportUndoFailure", func(t *testing.T) {
+		errUndo := errors.New("undo fail")
+		err := RunWithCompensation(context.Background(), func(ctx context.Context) error {
+			Compensate(ctx, func(context.Context) error { return errUndo })
+			return errors.New("save fail")
+		})
+		assert.ErrorIs(t, err, errUndo)
+	})
+
+	t.Run("IgnoredOutsideCompensation", func(t *testing.T) {
+		ctx := context.Background()
+		Compensate(ctx, func(context.Context) error { return nil })
+		assert.False(t, InCompensation(ctx))
+	})
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type StudentRepository interface {
+	// 新增或更新學員
+	SaveStudent(ctx context.Context, student *entity.Student) RepoError
+	DeleteStudents(ctx context.Context, students []*entity.Student) RepoError
+
+	FindStudentByID(ctx context.Context, id string) (*entity.Student, RepoError)
+	FindStudentsByUserID(ctx context.Context, userID string) ([]*entity.Student, RepoError)
+	FindStudentsByIDs(ctx context.Context, ids []string) ([]*entity.Student, RepoError)
+}
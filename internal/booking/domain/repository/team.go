@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type TeamRepository interface {
+	// 新增或更新團隊
+	SaveTeam(ctx context.Context, team *entity.Team) RepoError
+	DeleteTeam(ctx context.Context, team *entity.Team) RepoError
+
+	FindTeamByID(ctx context.Context, id string) (*entity.Team, RepoError)
+	FindTeams(ctx context.Context) ([]*entity.Team, RepoError)
+	// 總教練或助理教練為該使用者的團隊
+	FindTeamsByCoach(ctx context.Context, userID string) ([]*entity.Team, RepoError)
+}
+
+type teamScopeKey struct{}
+
+// NoTeamScope 不會對應到任何團隊的限定值，沒有負責團隊的教練查詢結果一律為空
+const NoTeamScope = "000000000000000000000000"
+
+// WithTeamScope 標記查詢只限於某團隊，repository 實作需過濾掉其他團隊的資料
+func WithTeamScope(ctx context.Context, teamID string) context.Context {
+	return context.WithValue(ctx, teamScopeKey{}, teamID)
+}
+
+// TeamScopeFromContext 取得查詢限定的團隊，未限定時回傳 false
+func TeamScopeFromContext(ctx context.Context) (string, bool) {
+	teamID, ok := ctx.Value(teamScopeKey{}).(string)
+	return teamID, ok && teamID != ""
+}
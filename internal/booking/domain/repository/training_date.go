@@ -37,6 +37,8 @@ type TrainRepository interface {
 	AdminDeductCapacity(ctx context.Context, trainingID string, count int) RepoError
 	// 原子增加名額
 	IncreaseCapacity(ctx context.Context, trainingID string, count int) RepoError
+	// 對帳修正剩餘名額，剩餘名額已不是 from 時回傳 ErrConflict
+	ResetAvailableCapacity(ctx context.Context, trainingID string, from, to int) RepoError
 
 	CleanTrainCache(ctx context.Context, userID string) RepoError
 }
@@ -96,3 +98,66 @@ type FilterTrainDateByEndTime struct {
 }
 
 func (f FilterTrainDateByEndTime) isCriteria() {}
+
+// 條件 E：週期性課程在指定時間之後產生的場次
+func NewFilterTrainDateBySeriesID(seriesID string, after time.Time) FilterTrainDate {
+	return FilterTrainDateBySeriesID{
+		SeriesID: seriesID,
+		After:    after,
+	}
+}
+
+type FilterTrainDateBySeriesID struct {
+	After    time.Time
+	SeriesID string
+}
+
+func (f FilterTrainDateBySeriesID) isCriteria() {}
+
+// 條件 F：教練與指定時段重疊的場次
+func NewFilterTrainDateByCoachOverlap(coachID string, period entity.TimeRange) FilterTrainDate {
+	return FilterTrainDateByCoachOverlap{
+		CoachID: coachID,
+		Period:  period,
+	}
+}
+
+type FilterTrainDateByCoachOverlap struct {
+	Period  entity.TimeRange
+	CoachID string
+}
+
+func (f FilterTrainDateByCoachOverlap) isCriteria() {}
+
+// 條件 G：綁定團隊且在指定時間之後的場次
+func NewFilterTrainDateByTeamID(teamID string, after time.Time) FilterTrainDate {
+	return FilterTrainDateByTeamID{
+		TeamID: teamID,
+		After:  after,
+	}
+}
+
+type FilterTrainDateByTeamID struct {
+	After  time.Time
+	TeamID string
+}
+
+func (f FilterTrainDateByTeamID) isCriteria() {}
+
+// 條件 H：已停課但尚未推播通知的場次
+func NewFilterTrainDateHasPendingCancelNotice() FilterTrainDate {
+	return FilterTrainDateHasPendingCancelNotice{}
+}
+
+type FilterTrainDateHasPendingCancelNotice struct{}
+
+func (f FilterTrainDateHasPendingCancelNotice) isCriteria() {}
+
+// 條件 I：已改期但尚未推播通知的場次
+func NewFilterTrainDateHasPendingRescheduleNotice() FilterTrainDate {
+	return FilterTrainDateHasPendingRescheduleNotice{}
+}
+
+type FilterTrainDateHasPendingRescheduleNotice struct{}
+
+func (f FilterTrainDateHasPendingRescheduleNotice) isCriteria() {}
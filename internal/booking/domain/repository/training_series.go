@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type TrainingSeriesRepository interface {
+	// 以 version 做樂觀鎖，版本不符時回傳 ErrConflict
+	SaveTrainingSeries(ctx context.Context, series *entity.TrainingSeries) RepoError
+
+	FindTrainingSeriesByID(ctx context.Context, id string) (*entity.TrainingSeries, RepoError)
+	FindTrainingSeries(ctx context.Context, filter FilterTrainingSeries) ([]*entity.TrainingSeries, RepoError)
+}
+
+// Filter
+type FilterTrainingSeries interface {
+	isCriteria() // 標記用介面
+}
+
+// 條件 A：仍在產生場次的系列
+func NewFilterTrainingSeriesActive() FilterTrainingSeries {
+	return FilterTrainingSeriesActive{}
+}
+
+type FilterTrainingSeriesActive struct{}
+
+func (f FilterTrainingSeriesActive) isCriteria() {}
+
+// 條件 B：教練的所有進行中系列
+func NewFilterTrainingSeriesByCoachID(coachID string) FilterTrainingSeries {
+	return FilterTrainingSeriesByCoachID{CoachID: coachID}
+}
+
+type FilterTrainingSeriesByCoachID struct {
+	CoachID string
+}
+
+func (f FilterTrainingSeriesByCoachID) isCriteria() {}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// UnitOfWork 將多個 repository 寫入包成同一個交易，fn 回傳錯誤時全部回滾；
+// 巢狀呼叫會併入外層交易
+type UnitOfWork interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type compensationKey struct{}
+
+type compensations struct {
+	undo []func(ctx context.Context) error
+	mu   sync.Mutex
+}
+
+// Compensate 登記寫入成功後的反向操作，交易失敗時依登記的反序執行；
+// 只有不支援交易的部署才會開啟補償紀錄，其他情況直接忽略
+func Compensate(ctx context.Context, undo func(ctx context.Context) error) {
+	c, ok := ctx.Value(compensationKey{}).(*compensations)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	c.undo = append(c.undo, undo)
+	c.mu.Unlock()
+}
+
+// InCompensation context 是否已開啟補償紀錄
+func InCompensation(ctx context.Context) bool {
+	_, ok := ctx.Value(compensationKey{}).(*compensations)
+	return ok
+}
+
+// RunWithCompensation 不支援交易時的替代做法：fn 失敗時執行已登記的補償動作，
+// 補償失敗會與原錯誤一起回傳，供呼叫端記錄後以對帳指令修正
+func RunWithCompensation(ctx context.Context, fn func(ctx context.Context) error) error {
+	if InCompensation(ctx) {
+		return fn(ctx)
+	}
+	c := &compensations{}
+	err := fn(context.WithValue(ctx, compensationKey{}, c))
+	if err == nil {
+		return nil
+	}
+	// 請求可能已被取消，補償仍需完成
+	undoCtx := context.WithoutCancel(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	errs := []error{err}
+	for i := len(c.undo) - 1; i >= 0; i-- {
+		if undoErr := c.undo[i](undoCtx); undoErr != nil {
+			errs = append(errs, undoErr)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type UserRolesRepository interface {
+	// 新增或更新使用者的角色
+	SaveUserRoles(ctx context.Context, roles *entity.UserRoles) RepoError
+	DeleteUserRoles(ctx context.Context, userID string) RepoError
+
+	FindUserRoles(ctx context.Context, userID string) (*entity.UserRoles, RepoError)
+	FindAllUserRoles(ctx context.Context) ([]*entity.UserRoles, RepoError)
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type WaitlistRepository interface {
+	// 以 version 做樂觀鎖，版本不符時回傳 ErrConflict
+	SaveWaitlist(ctx context.Context, waitlist *entity.Waitlist) RepoError
+
+	FindWaitlistByTrainID(ctx context.Context, trainingID string) (*entity.Waitlist, RepoError)
+	FindWaitlistsByFilter(ctx context.Context, filter FilterWaitlist) ([]*entity.Waitlist, RepoError)
+}
+
+// Filter
+type FilterWaitlist interface {
+	isCriteria() // 標記用介面
+}
+
+// 條件 A：使用者目前候補中的場次
+func NewFilterWaitlistByUserID(userID string) FilterWaitlist {
+	return FilterWaitlistByUserID{UserID: userID}
+}
+
+type FilterWaitlistByUserID struct {
+	UserID string
+}
+
+func (f FilterWaitlistByUserID) isCriteria() {}
+
+// 條件 B：已遞補但尚未通知家長
+func NewFilterWaitlistHasPendingNotice() FilterWaitlist {
+	return FilterWaitlistHasPendingNotice{}
+}
+
+type FilterWaitlistHasPendingNotice struct{}
+
+func (f FilterWaitlistHasPendingNotice) isCriteria() {}
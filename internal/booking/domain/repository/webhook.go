@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+type WebhookRepository interface {
+	// 新增或更新端點
+	SaveWebhookEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) RepoError
+	DeleteWebhookEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) RepoError
+
+	FindWebhookEndpointByID(ctx context.Context, id string) (*entity.WebhookEndpoint, RepoError)
+	FindWebhookEndpoints(ctx context.Context) ([]*entity.WebhookEndpoint, RepoError)
+
+	// 送出紀錄保留 30 天
+	AddWebhookDelivery(ctx context.Context, delivery *entity.WebhookDelivery) RepoError
+	// FindWebhookDeliveries 依送出時間由新到舊，eventID 留空代表不限事件
+	FindWebhookDeliveries(
+		ctx context.Context, endpointID, eventID string, limit int,
+	) ([]*entity.WebhookDelivery, RepoError)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/repository"
+	"slices"
 )
 
 var (
@@ -16,6 +17,8 @@ type TrainDateService interface {
 	CheckTrainerAvailability(ctx context.Context, slot *entity.TrainDate) error
 	// 檢查教練在指定時間是否已經有其他訓練時段存在 (避免重疊)
 	CheckAnyOverlap(ctx context.Context, coachID string, tr []entity.TimeRange) error
+	// 同上，但忽略指定的場次 (例如修改時段時的場次本身)
+	CheckOverlapExcept(ctx context.Context, coachID string, tr entity.TimeRange, exceptIDs ...string) error
 }
 
 type trainDateService struct {
@@ -59,3 +62,21 @@ func (s *trainDateService) CheckAnyOverlap(ctx context.Context, coachID string,
 	}
 	return nil
 }
+
+func (s *trainDateService) CheckOverlapExcept(
+	ctx context.Context, coachID string, tr entity.TimeRange, exceptIDs ...string,
+) error {
+	trainings, err := s.repo.FindTrainDates(ctx, repository.NewFilterTrainDateByCoachOverlap(coachID, tr))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	for _, t := range trainings {
+		if !slices.Contains(exceptIDs, t.ID()) {
+			return ErrTrainerTimeOverlap
+		}
+	}
+	return nil
+}
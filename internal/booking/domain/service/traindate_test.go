@@ -108,6 +108,13 @@ func (m *MockTrainRepository) IncreaseCapacity(ctx context.Context, trainingID s
 	}
 	return args.Get(0).(repository.RepoError)
 }
+func (m *MockTrainRepository) ResetAvailableCapacity(ctx context.Context, trainingID string, from, to int) repository.RepoError {
+	args := m.Called(ctx, trainingID, from, to)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(repository.RepoError)
+}
 
 func (m *MockTrainRepository) FindPastTrainDateIDs(ctx context.Context, cutoff time.Time, limit uint16) ([]string, repository.RepoError) {
 	args := m.Called(ctx, cutoff, limit)
@@ -176,3 +183,31 @@ func TestCheckAnyOverlap(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestCheckOverlapExcept(t *testing.T) {
+	mockRepo := new(MockTrainRepository)
+	svc := NewTrainDateService(mockRepo)
+	ctx := context.Background()
+	now := time.Now()
+	tr, _ := entity.NewTimeRange(now, now.Add(time.Hour))
+	td, _ := entity.NewTrainDate(
+		entity.WithBasicTrainDate("id1", "coach1", "Gym", 10, tr),
+	)
+	filter := repository.NewFilterTrainDateByCoachOverlap("coach1", tr)
+
+	t.Run("Success_OnlySelf", func(t *testing.T) {
+		mockRepo.On("FindTrainDates", ctx, filter).Return([]*entity.TrainDate{td}, nil).Once()
+
+		err := svc.CheckOverlapExcept(ctx, "coach1", tr, "id1")
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail_Overlap", func(t *testing.T) {
+		mockRepo.On("FindTrainDates", ctx, filter).Return([]*entity.TrainDate{td}, nil).Once()
+
+		err := svc.CheckOverlapExcept(ctx, "coach1", tr, "id2")
+		assert.ErrorIs(t, err, ErrTrainerTimeOverlap)
+		mockRepo.AssertExpectations(t)
+	})
+}
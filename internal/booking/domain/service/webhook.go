@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/event"
+)
+
+// Webhook 請求標頭，接收端以 WebhookSignature 驗證來源並拒絕時間差過大的請求以防重送
+const (
+	WebhookHeaderEventID   = "X-Webhook-Event-Id"
+	WebhookHeaderTopic     = "X-Webhook-Topic"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+type WebhookSender interface {
+	// Send 送出一次，失敗時記錄在回傳的 WebhookDelivery，不回傳 error
+	Send(ctx context.Context, endpoint *entity.WebhookEndpoint, e event.Event, attempt int) *entity.WebhookDelivery
+}
+
+// WebhookSignature 以 "時間戳.內容" 計算 HMAC-SHA256，格式為 "sha256=<hex>"
+func WebhookSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
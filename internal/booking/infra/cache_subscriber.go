@@ -11,6 +11,15 @@ import (
 	"github.com/94peter/vulpes/log"
 )
 
+// cacheRetryPolicy 快取有到期時間，清理失敗不需長時間重試
+var cacheRetryPolicy = event.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
 type cacheSubscriber struct {
 	repo repository.TrainRepository
 	// 這裡可能還需要 StatsRepository 或在某個組合後的介面
@@ -19,31 +28,120 @@ type cacheSubscriber struct {
 
 func NewCacheSubscriber(repo repository.TrainRepository, statsRepo repository.StatsRepository) event.Subscriber {
 	handler := func(ctx context.Context, e event.Event, p domain.AppointmentStatusChanged) error {
-		// 背景清理任務
+		return cleanApptCache(repo, statsRepo, p.UserID, p.TrainingID, p.BookingID)
+	}
+
+	return event.WithRetryPolicy(
+		event.NewTypedSubscriber("cache_worker_v2", domain.TopicAppointmentStatusChanged, handler), cacheRetryPolicy)
+}
+
+// NewLeaveCacheSubscriber 請假申請與駁回不會改變預約狀態，但排程需顯示審核進度
+func NewLeaveCacheSubscriber(repo repository.TrainRepository, statsRepo repository.StatsRepository) []event.Subscriber {
+	requestedHandler := func(ctx context.Context, e event.Event, p domain.LeaveRequested) error {
+		return cleanApptCache(repo, statsRepo, p.UserID, p.TrainingID, p.BookingID)
+	}
+	reviewedHandler := func(ctx context.Context, e event.Event, p domain.LeaveReviewed) error {
+		return cleanApptCache(repo, statsRepo, p.UserID, p.TrainingID, p.BookingID)
+	}
+	// 撤回已核准的請假時 cache_worker_v2 也會清理，重複清理不影響結果
+	cancelledHandler := func(ctx context.Context, e event.Event, p domain.LeaveCancelled) error {
+		return cleanApptCache(repo, statsRepo, p.UserID, p.TrainingID, p.BookingID)
+	}
+	reasonChangedHandler := func(ctx context.Context, e event.Event, p domain.LeaveReasonChanged) error {
+		return cleanApptCache(repo, statsRepo, p.UserID, p.TrainingID, p.BookingID)
+	}
+
+	return []event.Subscriber{
+		event.WithRetryPolicy(
+			event.NewTypedSubscriber("cache_worker_leave_requested", domain.TopicLeaveRequested, requestedHandler), cacheRetryPolicy),
+		event.WithRetryPolicy(
+			event.NewTypedSubscriber("cache_worker_leave_reviewed", domain.TopicLeaveReviewed, reviewedHandler), cacheRetryPolicy),
+		event.WithRetryPolicy(
+			event.NewTypedSubscriber("cache_worker_leave_cancelled", domain.TopicLeaveCancelled, cancelledHandler), cacheRetryPolicy),
+		event.WithRetryPolicy(
+			event.NewTypedSubscriber("cache_worker_leave_reason_changed", domain.TopicLeaveReasonChanged, reasonChangedHandler), cacheRetryPolicy),
+	}
+}
+
+// NewStudentCacheSubscriber 學員改名或合併會改寫預約上的姓名，清除該家長的排程快取；
+// 舊資料對應學員影響多位家長，清除全部排程快取
+func NewStudentCacheSubscriber(repo repository.TrainRepository) []event.Subscriber {
+	renamedHandler := func(ctx context.Context, e event.Event, p domain.StudentRenamed) error {
+		return cleanUserTrainCache(repo, e, p.UserID)
+	}
+	mergedHandler := func(ctx context.Context, e event.Event, p domain.StudentsMerged) error {
+		return cleanUserTrainCache(repo, e, p.UserID)
+	}
+
+	return []event.Subscriber{
+		event.WithRetryPolicy(
+			event.NewTypedSubscriber("cache_worker_student_renamed", domain.TopicStudentRenamed, renamedHandler), cacheRetryPolicy),
+		event.WithRetryPolicy(
+			event.NewTypedSubscriber("cache_worker_students_merged", domain.TopicStudentsMerged, mergedHandler), cacheRetryPolicy),
+		newTrainDateCacheSubscriber[domain.ChildNamesMigrated](
+			repo, "cache_worker_child_names_migrated", domain.TopicChildNamesMigrated),
+	}
+}
+
+func cleanUserTrainCache(repo repository.TrainRepository, e event.Event, userID string) error {
+	bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := repo.CleanTrainCache(bgCtx, userID); err != nil {
+		return fmt.Errorf("CacheSubscriber: clean train cache fail (event: %s): %w", e.ID(), err)
+	}
+	return nil
+}
+
+// NewTrainDateCacheSubscriber 場次異動影響所有用戶的排程，清除全部排程快取；
+// 現場報到的預約由 cache_worker_v2 依 AppointmentStatusChanged 清理
+func NewTrainDateCacheSubscriber(repo repository.TrainRepository) []event.Subscriber {
+	return []event.Subscriber{
+		newTrainDateCacheSubscriber[domain.TrainDateCreated](repo, "cache_worker_train_date_created", domain.TopicTrainDateCreated),
+		newTrainDateCacheSubscriber[domain.TrainDateDeleted](repo, "cache_worker_train_date_deleted", domain.TopicTrainDateDeleted),
+		newTrainDateCacheSubscriber[domain.TrainDateCancelled](repo, "cache_worker_train_date_cancelled", domain.TopicTrainDateCancelled),
+		newTrainDateCacheSubscriber[domain.TrainDateRescheduled](repo, "cache_worker_train_date_rescheduled", domain.TopicTrainDateRescheduled),
+		newTrainDateCacheSubscriber[domain.TrainDateUpdated](repo, "cache_worker_train_date_updated", domain.TopicTrainDateUpdated),
+		newTrainDateCacheSubscriber[domain.TrainDateCapacityChanged](repo, "cache_worker_train_date_capacity", domain.TopicTrainDateCapacityChanged),
+	}
+}
+
+func newTrainDateCacheSubscriber[T any](repo repository.TrainRepository, id, topic string) event.Subscriber {
+	handler := func(ctx context.Context, e event.Event, _ T) error {
 		bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-
-		if p.TrainingID == "" {
-			log.Warnf("CacheSubscriber: TrainingID is empty for booking %s, might be legacy event. Skipping.", p.BookingID)
-			return nil
+		if err := repo.CleanTrainCache(bgCtx, ""); err != nil {
+			return fmt.Errorf("CacheSubscriber: clean train cache fail (event: %s): %w", e.ID(), err)
 		}
+		return nil
+	}
+	return event.WithRetryPolicy(event.NewTypedSubscriber(id, topic, handler), cacheRetryPolicy)
+}
 
-		trainDate, err := repo.FindTrainDateByID(bgCtx, p.TrainingID)
-		if err != nil {
-			return fmt.Errorf("CacheSubscriber: find traindate fail (ID: %s): %w", p.TrainingID, err)
-		}
+func cleanApptCache(
+	repo repository.TrainRepository, statsRepo repository.StatsRepository, userID, trainingID, bookingID string,
+) error {
+	// 背景清理任務
+	bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		startTime := trainDate.Period().Start()
-		
-		// 1. 清理該用戶的排程快取
-		_ = repo.CleanTrainCache(bgCtx, p.UserID)
-		
-		// 2. 清理統計快取
-		_ = statsRepo.CleanStatsCache(bgCtx, p.UserID, startTime.Year(), int(startTime.Month()))
-		
-		log.Infof("CacheSubscriber: cleaned cache for user %s, training %s", p.UserID, p.TrainingID)
+	if trainingID == "" {
+		log.Warnf("CacheSubscriber: TrainingID is empty for booking %s, might be legacy event. Skipping.", bookingID)
 		return nil
 	}
 
-	return event.NewTypedSubscriber("cache_worker_v2", domain.TopicAppointmentStatusChanged, handler)
+	trainDate, err := repo.FindTrainDateByID(bgCtx, trainingID)
+	if err != nil {
+		return fmt.Errorf("CacheSubscriber: find traindate fail (ID: %s): %w", trainingID, err)
+	}
+
+	startTime := trainDate.Period().Start()
+
+	// 1. 清理該用戶的排程快取
+	_ = repo.CleanTrainCache(bgCtx, userID)
+
+	// 2. 清理統計快取
+	_ = statsRepo.CleanStatsCache(bgCtx, userID, startTime.Year(), int(startTime.Month()))
+
+	log.Infof("CacheSubscriber: cleaned cache for user %s, training %s", userID, trainingID)
+	return nil
 }
@@ -0,0 +1,512 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	writeAppt "seanAIgent/internal/booking/usecase/appointment/write"
+	writeMakeUp "seanAIgent/internal/booking/usecase/makeup/write"
+	writeStudent "seanAIgent/internal/booking/usecase/student/write"
+	writeWaitlist "seanAIgent/internal/booking/usecase/waitlist/write"
+	"seanAIgent/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memCacheRepo 以記憶體保存預約相關資料，記錄寫入 outbox 的事件與被清理的快取；
+// 未用到的方法由內嵌的 interface 提供
+type memCacheRepo struct {
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.CreditLedgerRepository
+	repository.StudentRepository
+	repository.TeamRepository
+	repository.WaitlistRepository
+	repository.MakeUpCreditRepository
+	repository.StatsRepository
+	trainings    map[string]*entity.TrainDate
+	appts        map[string]*entity.Appointment
+	teams        map[string]*entity.Team
+	waitlists    map[string]*entity.Waitlist
+	credits      map[string]*entity.MakeUpCredit
+	students     map[string]*entity.Student
+	events       []event.Event
+	nextID       int
+	cleanedTrain []string
+	cleanedStats []string
+}
+
+func newMemCacheRepo() *memCacheRepo {
+	return &memCacheRepo{
+		trainings: make(map[string]*entity.TrainDate),
+		appts:     make(map[string]*entity.Appointment),
+		teams:     make(map[string]*entity.Team),
+		waitlists: make(map[string]*entity.Waitlist),
+		credits:   make(map[string]*entity.MakeUpCredit),
+		students:  make(map[string]*entity.Student),
+	}
+}
+
+func (r *memCacheRepo) GenerateID() string {
+	r.nextID++
+	return fmt.Sprintf("id-%03d", r.nextID)
+}
+
+func (r *memCacheRepo) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *memCacheRepo) AddEvents(ctx context.Context, events ...event.Event) repository.RepoError {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *memCacheRepo) CleanTrainCache(ctx context.Context, userID string) repository.RepoError {
+	r.cleanedTrain = append(r.cleanedTrain, userID)
+	return nil
+}
+
+func (r *memCacheRepo) CleanStatsCache(ctx context.Context, userID string, year, month int) repository.RepoError {
+	r.cleanedStats = append(r.cleanedStats, statsCacheKey(userID, year, month))
+	return nil
+}
+
+func (r *memCacheRepo) FindTrainDateByID(ctx context.Context, id string) (*entity.TrainDate, repository.RepoError) {
+	td, ok := r.trainings[id]
+	if !ok {
+		return nil, repository.NewRepoNotFoundError("train", "memory", "find_train_date_by_id", nil)
+	}
+	return td, nil
+}
+
+func (r *memCacheRepo) DeductCapacity(ctx context.Context, trainingID string, count int) repository.RepoError {
+	return nil
+}
+
+func (r *memCacheRepo) IncreaseCapacity(ctx context.Context, trainingID string, count int) repository.RepoError {
+	return nil
+}
+
+func (r *memCacheRepo) FindApptByID(ctx context.Context, id string) (*entity.Appointment, repository.RepoError) {
+	appt, ok := r.appts[id]
+	if !ok {
+		return nil, repository.NewRepoNotFoundError("appointment", "memory", "find_appt_by_id", nil)
+	}
+	return appt, nil
+}
+
+func (r *memCacheRepo) FindApptsByFilter(
+	ctx context.Context, filter repository.FilterAppointment,
+) ([]*entity.Appointment, repository.RepoError) {
+	f, ok := filter.(repository.FilterApptByTrainID)
+	if !ok {
+		return nil, repository.NewRepoInternalError("appointment", "memory", "find_appts", repository.ErrFilterNotImplemented)
+	}
+	var result []*entity.Appointment
+	for _, a := range r.appts {
+		if a.TrainingID() == f.TrainingID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (r *memCacheRepo) SaveAppointment(ctx context.Context, appt *entity.Appointment) repository.RepoError {
+	r.appts[appt.ID()] = appt
+	return nil
+}
+
+func (r *memCacheRepo) SaveManyAppointments(ctx context.Context, appts []*entity.Appointment) repository.RepoError {
+	for _, a := range appts {
+		r.appts[a.ID()] = a
+	}
+	return nil
+}
+
+func (r *memCacheRepo) UpdateAppt(ctx context.Context, appt *entity.Appointment) repository.RepoError {
+	return r.SaveAppointment(ctx, appt)
+}
+
+func (r *memCacheRepo) UpdateManyAppts(ctx context.Context, appts []*entity.Appointment) repository.RepoError {
+	return r.SaveManyAppointments(ctx, appts)
+}
+
+func (r *memCacheRepo) DeleteAppointment(ctx context.Context, appt *entity.Appointment) repository.RepoError {
+	delete(r.appts, appt.ID())
+	return nil
+}
+
+func (r *memCacheRepo) FindCreditLedgerByUserID(
+	ctx context.Context, userID string,
+) (*entity.CreditLedger, repository.RepoError) {
+	return nil, repository.NewRepoNotFoundError("credit_ledger", "memory", "find_credit_ledger_by_user_id", nil)
+}
+
+func (r *memCacheRepo) FindStudentByID(ctx context.Context, id string) (*entity.Student, repository.RepoError) {
+	student, ok := r.students[id]
+	if !ok {
+		return nil, repository.NewRepoNotFoundError("student", "memory", "find_student_by_id", nil)
+	}
+	return student, nil
+}
+
+func (r *memCacheRepo) FindStudentsByUserID(ctx context.Context, userID string) ([]*entity.Student, repository.RepoError) {
+	var result []*entity.Student
+	for _, s := range r.students {
+		if s.BelongsTo(userID) {
+			result = append(result, s)
+		}
+	}
+	if len(result) == 0 {
+		return nil, repository.NewRepoNotFoundError("student", "memory", "find_students_by_user_id", nil)
+	}
+	return result, nil
+}
+
+func (r *memCacheRepo) SaveStudent(ctx context.Context, student *entity.Student) repository.RepoError {
+	r.students[student.ID()] = student
+	return nil
+}
+
+func (r *memCacheRepo) DeleteStudents(ctx context.Context, students []*entity.Student) repository.RepoError {
+	for _, s := range students {
+		delete(r.students, s.ID())
+	}
+	return nil
+}
+
+func (r *memCacheRepo) ReassignStudent(ctx context.Context, fromStudentIDs []string, to *entity.Student) repository.RepoError {
+	return nil
+}
+
+func (r *memCacheRepo) FindTeamByID(ctx context.Context, id string) (*entity.Team, repository.RepoError) {
+	team, ok := r.teams[id]
+	if !ok {
+		return nil, repository.NewRepoNotFoundError("team", "memory", "find_team_by_id", nil)
+	}
+	return team, nil
+}
+
+func (r *memCacheRepo) FindWaitlistByTrainID(ctx context.Context, trainingID string) (*entity.Waitlist, repository.RepoError) {
+	wl, ok := r.waitlists[trainingID]
+	if !ok {
+		return nil, repository.NewRepoNotFoundError("waitlist", "memory", "find_waitlist_by_train_id", nil)
+	}
+	return wl, nil
+}
+
+func (r *memCacheRepo) SaveWaitlist(ctx context.Context, waitlist *entity.Waitlist) repository.RepoError {
+	r.waitlists[waitlist.TrainingID()] = waitlist
+	return nil
+}
+
+func (r *memCacheRepo) FindMakeUpCreditByID(ctx context.Context, id string) (*entity.MakeUpCredit, repository.RepoError) {
+	credit, ok := r.credits[id]
+	if !ok {
+		return nil, repository.NewRepoNotFoundError("make_up_credit", "memory", "find_make_up_credit_by_id", nil)
+	}
+	return credit, nil
+}
+
+func (r *memCacheRepo) SaveMakeUpCredit(ctx context.Context, credit *entity.MakeUpCredit) repository.RepoError {
+	r.credits[credit.ID()] = credit
+	return nil
+}
+
+// dispatch 將寫入 outbox 的事件交給訂閱同一主題的快取訂閱者
+func (r *memCacheRepo) dispatch(t *testing.T) {
+	t.Helper()
+	subscribers := append(NewLeaveCacheSubscriber(r, r), NewCacheSubscriber(r, r))
+	subscribers = append(subscribers, NewStudentCacheSubscriber(r)...)
+	for _, e := range r.events {
+		for _, s := range subscribers {
+			if s.Topic() == e.Topic() {
+				require.NoError(t, s.Handle(t.Context(), e))
+			}
+		}
+	}
+}
+
+// addTraining teamID 為空時建立一般場次
+func (r *memCacheRepo) addTraining(t *testing.T, start time.Time, teamID string) *entity.TrainDate {
+	t.Helper()
+	period, err := entity.NewTimeRange(start, start.Add(time.Hour))
+	require.NoError(t, err)
+	td, err := entity.NewTrainDate(
+		entity.WithBasicTrainDate(r.GenerateID(), "coach1", "Gym A", 10, period),
+		entity.WithTrainDateTeamID(teamID),
+	)
+	require.NoError(t, err)
+	r.trainings[td.ID()] = td
+	return td
+}
+
+func (r *memCacheRepo) addAppt(t *testing.T, training *entity.TrainDate) *entity.Appointment {
+	t.Helper()
+	appt, err := entity.NewAppointment(entity.WithCreateAppt(r.GenerateID(), training.ID(), testParent(t), "ChildA"))
+	require.NoError(t, err)
+	r.appts[appt.ID()] = appt
+	return appt
+}
+
+func (r *memCacheRepo) addStudent(t *testing.T, name string) *entity.Student {
+	t.Helper()
+	student, err := entity.NewStudent(
+		entity.WithStudentID(r.GenerateID()), entity.WithStudentParent(testParent(t)), entity.WithStudentName(name),
+	)
+	require.NoError(t, err)
+	r.students[student.ID()] = student
+	return student
+}
+
+func testParent(t *testing.T) entity.User {
+	t.Helper()
+	user, err := entity.NewUser("user1", "Parent")
+	require.NoError(t, err)
+	return user
+}
+
+func statsCacheKey(userID string, year, month int) string {
+	return fmt.Sprintf("%s/%d-%02d", userID, year, month)
+}
+
+// TestCacheSubscriber_UseCases 預約異動不直接清理快取，由訂閱者處理寫入 outbox 的事件後清除
+func TestCacheSubscriber_UseCases(t *testing.T) {
+	upcoming := time.Now().Add(72 * time.Hour)
+	// 教練點名的時間範圍內
+	ongoing := time.Now().Add(10 * time.Minute)
+
+	tests := []struct {
+		name string
+		// run 執行用例並回傳受影響的場次
+		run func(t *testing.T, repo *memCacheRepo) *entity.TrainDate
+	}{
+		{"CreateAppt", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, upcoming, "")
+			_, err := writeAppt.NewCreateApptUseCase(repo).Execute(t.Context(), writeAppt.ReqCreateAppt{
+				TrainDateID: td.ID(), User: testParent(t), ChildNames: []string{"ChildA"},
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"CancelAppt", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, upcoming, "")
+			appt := repo.addAppt(t, td)
+			_, err := writeAppt.NewCancelApptUseCase(repo).Execute(t.Context(), writeAppt.ReqCancelAppt{
+				ApptID: appt.ID(), UserID: "user1",
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"CreateLeave", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, upcoming, "")
+			appt := repo.addAppt(t, td)
+			_, err := writeAppt.NewCreateLeaveUseCase(repo).Execute(t.Context(), writeAppt.ReqCreateLeave{
+				AppointmentID: appt.ID(), User: testParent(t), Reason: "生病",
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		// 團隊設定請假需核准時只送出申請
+		{"RequestLeave", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			coach, err := entity.NewUser("coach1", "Coach")
+			require.NoError(t, err)
+			team, err := entity.NewTeam(
+				entity.WithTeamID("team1"), entity.WithTeamName("Team A"),
+				entity.WithTeamHeadCoach(coach), entity.WithTeamLeaveApproval(true),
+			)
+			require.NoError(t, err)
+			repo.teams[team.ID()] = team
+			td := repo.addTraining(t, upcoming, team.ID())
+			appt := repo.addAppt(t, td)
+			_, ucErr := writeAppt.NewCreateLeaveUseCase(repo).Execute(t.Context(), writeAppt.ReqCreateLeave{
+				AppointmentID: appt.ID(), User: testParent(t), Reason: "生病",
+			})
+			require.Nil(t, ucErr)
+			require.True(t, appt.HasPendingLeave())
+			return td
+		}},
+		{"UpdateLeaveReason", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, upcoming, "")
+			appt := repo.addAppt(t, td)
+			require.NoError(t, appt.RequestLeave("生病", td.Period().Start(), td.BookingPolicy()))
+			_, err := writeAppt.NewUpdateLeaveReasonUseCase(repo).Execute(t.Context(), writeAppt.ReqUpdateLeaveReason{
+				ApptID: appt.ID(), UserID: "user1", Reason: "發燒",
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"ApproveLeave", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, upcoming, "")
+			appt := repo.addAppt(t, td)
+			require.NoError(t, appt.RequestLeave("生病", td.Period().Start(), td.BookingPolicy()))
+			_, err := writeAppt.NewApproveLeaveUseCase(repo).Execute(t.Context(), writeAppt.ReqReviewLeave{
+				BookingID: appt.ID(), ReviewerID: "coach1",
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"RejectLeave", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, upcoming, "")
+			appt := repo.addAppt(t, td)
+			require.NoError(t, appt.RequestLeave("生病", td.Period().Start(), td.BookingPolicy()))
+			_, err := writeAppt.NewRejectLeaveUseCase(repo).Execute(t.Context(), writeAppt.ReqReviewLeave{
+				BookingID: appt.ID(), ReviewerID: "coach1",
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"AdminCreateLeave", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, ongoing, "")
+			appt := repo.addAppt(t, td)
+			_, err := writeAppt.NewAdminCreateLeaveUseCase(repo).Execute(t.Context(), writeAppt.ReqAdminCreateLeave{
+				BookingID: appt.ID(), Reason: "教練代請假",
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"AdminRestoreFromLeave", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, ongoing, "")
+			appt := repo.addAppt(t, td)
+			require.NoError(t, appt.AdminAppendLeave("教練代請假", td.Period().Start(), td.BookingPolicy()))
+			_, err := writeAppt.NewAdminRestoreFromLeaveUseCase(repo).Execute(t.Context(), writeAppt.ReqAdminRestoreFromLeave{
+				BookingID: appt.ID(),
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"AdminCheckIn", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, ongoing, "")
+			appt := repo.addAppt(t, td)
+			_, err := writeAppt.NewAdminCheckInUseCase(repo).Execute(t.Context(), writeAppt.ReqAdminCheckIn{
+				TrainDateID: td.ID(), CheckedInBookingIDs: []string{appt.ID()},
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"AdminToggleCheckIn", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, ongoing, "")
+			appt := repo.addAppt(t, td)
+			_, err := writeAppt.NewAdminToggleCheckInUseCase(repo).Execute(t.Context(), writeAppt.ReqAdminToggleCheckIn{
+				BookingID: appt.ID(),
+			})
+			require.Nil(t, err)
+			return td
+		}},
+		{"AdminBatchUpdateAttendance", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, ongoing, "")
+			appt := repo.addAppt(t, td)
+			n, err := writeAppt.NewAdminBatchUpdateAttendanceUseCase(repo).Execute(t.Context(), writeAppt.ReqAdminBatchUpdateAttendance{
+				SessionID: td.ID(), Updates: []writeAppt.AttendanceUpdate{{BookingID: appt.ID(), Status: "Absent"}},
+			})
+			require.Nil(t, err)
+			require.Equal(t, 1, n)
+			return td
+		}},
+		{"PromoteWaitlist", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, upcoming, "")
+			wl, err := entity.NewWaitlist(entity.WithWaitlistTrainingID(td.ID()))
+			require.NoError(t, err)
+			_, err = wl.Join(repo.GenerateID(), testParent(t), "ChildA")
+			require.NoError(t, err)
+			repo.waitlists[td.ID()] = wl
+			appts, ucErr := writeWaitlist.NewPromoteWaitlistUseCase(repo).Execute(t.Context(), writeWaitlist.ReqPromoteWaitlist{
+				TrainDateID: td.ID(),
+			})
+			require.Nil(t, ucErr)
+			require.Len(t, appts, 1)
+			return td
+		}},
+		{"BookMakeUp", func(t *testing.T, repo *memCacheRepo) *entity.TrainDate {
+			td := repo.addTraining(t, upcoming, "")
+			credit, err := entity.NewMakeUpCredit(
+				entity.WithMakeUpCreditID(repo.GenerateID()),
+				entity.WithMakeUpCreditUser(testParent(t)),
+				entity.WithMakeUpCreditLeave("leave-appt", "leave-training"),
+				entity.WithMakeUpCreditStudent("", "ChildA"),
+				entity.WithMakeUpCreditExpiresAt(time.Now().AddDate(0, 1, 0)),
+			)
+			require.NoError(t, err)
+			repo.credits[credit.ID()] = credit
+			_, ucErr := writeMakeUp.NewBookMakeUpUseCase(repo).Execute(t.Context(), writeMakeUp.ReqBookMakeUp{
+				User: testParent(t), CreditID: credit.ID(), TrainDateID: td.ID(),
+			})
+			require.Nil(t, ucErr)
+			return td
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemCacheRepo()
+			td := tt.run(t, repo)
+			require.NotEmpty(t, repo.events)
+			assert.Empty(t, repo.cleanedTrain)
+			assert.Empty(t, repo.cleanedStats)
+
+			repo.dispatch(t)
+			start := td.Period().Start()
+			assert.Contains(t, repo.cleanedTrain, "user1")
+			assert.Contains(t, repo.cleanedStats, statsCacheKey("user1", start.Year(), int(start.Month())))
+		})
+	}
+}
+
+// TestStudentCacheSubscriber 學員改名、合併與舊資料對應後由訂閱者清除排程快取
+func TestStudentCacheSubscriber(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo *memCacheRepo)
+		// cleaned 預期清除排程快取的 userID，空字串代表全部清除
+		cleaned string
+	}{
+		{"UpdateStudent", func(t *testing.T, repo *memCacheRepo) {
+			student := repo.addStudent(t, "ChildA")
+			_, err := writeStudent.NewUpdateStudentUseCase(repo).Execute(t.Context(), writeStudent.ReqUpdateStudent{
+				UserID: "user1", StudentID: student.ID(), Name: "ChildB",
+			})
+			require.Nil(t, err)
+		}, "user1"},
+		{"MergeStudents", func(t *testing.T, repo *memCacheRepo) {
+			target := repo.addStudent(t, "ChildA")
+			source := repo.addStudent(t, "ChildB")
+			_, err := writeStudent.NewMergeStudentsUseCase(repo).Execute(t.Context(), writeStudent.ReqMergeStudents{
+				TargetID: target.ID(), SourceIDs: []string{source.ID()},
+			})
+			require.Nil(t, err)
+		}, "user1"},
+		{"ChildNamesMigrated", func(t *testing.T, repo *memCacheRepo) {
+			repo.events = append(repo.events, event.NewTypedEvent(repo.GenerateID(), domain.TopicChildNamesMigrated,
+				domain.ChildNamesMigrated{Linked: 1, OccurredAt: time.Now()}))
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemCacheRepo()
+			tt.run(t, repo)
+			require.NotEmpty(t, repo.events)
+			assert.Empty(t, repo.cleanedTrain)
+
+			repo.dispatch(t)
+			assert.Equal(t, []string{tt.cleaned}, repo.cleanedTrain)
+		})
+	}
+}
+
+// TestStudentCacheSubscriber_NameUnchanged 只修改暱稱時不改寫預約，不發布事件
+func TestStudentCacheSubscriber_NameUnchanged(t *testing.T) {
+	repo := newMemCacheRepo()
+	student := repo.addStudent(t, "ChildA")
+	_, err := writeStudent.NewUpdateStudentUseCase(repo).Execute(t.Context(), writeStudent.ReqUpdateStudent{
+		UserID: "user1", StudentID: student.ID(), Name: "ChildA", Nickname: "A",
+	})
+	require.Nil(t, err)
+	assert.Empty(t, repo.events)
+}
@@ -0,0 +1,87 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	writeCredit "seanAIgent/internal/booking/usecase/credit/write"
+	"seanAIgent/internal/event"
+	"time"
+
+	"github.com/94peter/vulpes/log"
+)
+
+// ledgerRetryPolicy 堂數影響計費，重試約 4 分鐘後才轉入 dead letter
+var ledgerRetryPolicy = event.RetryPolicy{
+	MaxAttempts:    8,
+	InitialBackoff: 2 * time.Second,
+	MaxBackoff:     5 * time.Minute,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// ledgerWorkerPool 同一用戶的堂數異動依序處理，避免扣堂與結算交錯
+func ledgerWorkerPool(key func(event.Event) string) event.WorkerPoolConfig {
+	return event.WorkerPoolConfig{
+		Workers:   4,
+		QueueSize: 64,
+		OnFull:    event.BlockWhenFull,
+		Key:       key,
+	}
+}
+
+// NewCreditLedgerSubscriber 依預約狀態變更同步課程包堂數，批次更新後則結算已上課的保留堂數
+func NewCreditLedgerSubscriber(
+	applyUC writeCredit.ApplyApptCreditUseCase,
+	settleUC writeCredit.SettleCreditLedgerUseCase,
+) []event.Subscriber {
+	statusChangeHandler := func(ctx context.Context, e event.Event, p domain.AppointmentStatusChanged) error {
+		bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		applied, err := applyUC.Execute(bgCtx, writeCredit.ReqApplyApptCredit{
+			OccurredAt: p.OccurredAt,
+			BookingID:  p.BookingID,
+			UserID:     p.UserID,
+			TrainingID: p.TrainingID,
+			NewStatus:  p.NewStatus,
+		})
+		if err != nil {
+			// 堂數不足時 (例如候補遞補) 重試無法補扣，直接轉入 dead letter 由管理員儲值後重送
+			if errors.Is(err, entity.ErrCreditInsufficient) {
+				log.Warnf("CreditLedgerSubscriber: insufficient credits for user %s booking %s", p.UserID, p.BookingID)
+				return event.Permanent(fmt.Errorf("CreditLedgerSubscriber: apply credit fail (booking: %s): %w", p.BookingID, err))
+			}
+			return fmt.Errorf("CreditLedgerSubscriber: apply credit fail (booking: %s): %w", p.BookingID, err)
+		}
+		if applied {
+			log.Infof("CreditLedgerSubscriber: applied %s for booking %s", p.NewStatus, p.BookingID)
+		}
+		return nil
+	}
+
+	refreshHandler := func(ctx context.Context, e event.Event, p domain.UserStatsRefreshRequested) error {
+		bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		settled, err := settleUC.Execute(bgCtx, writeCredit.ReqSettleCreditLedger{UserID: p.UserID})
+		if err != nil {
+			return fmt.Errorf("CreditLedgerSubscriber: settle fail (user: %s): %w", p.UserID, err)
+		}
+		if settled > 0 {
+			log.Infof("CreditLedgerSubscriber: settled %d credits for user %s", settled, p.UserID)
+		}
+		return nil
+	}
+
+	return []event.Subscriber{
+		event.WithWorkerPool(event.WithRetryPolicy(
+			event.NewTypedSubscriber("credit_ledger_status_change", domain.TopicAppointmentStatusChanged, statusChangeHandler), ledgerRetryPolicy),
+			ledgerWorkerPool(event.KeyBy(func(p domain.AppointmentStatusChanged) string { return p.UserID }))),
+		event.WithWorkerPool(event.WithRetryPolicy(
+			event.NewTypedSubscriber("credit_ledger_settle", domain.TopicUserStatsRefreshRequested, refreshHandler), ledgerRetryPolicy),
+			ledgerWorkerPool(event.KeyBy(func(p domain.UserStatsRefreshRequested) string { return p.UserID }))),
+	}
+}
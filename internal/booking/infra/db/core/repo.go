@@ -9,4 +9,15 @@ type DbRepository interface {
 	repository.TrainRepository
 	repository.IdentityGenerator
 	repository.StatsRepository
+	repository.WaitlistRepository
+	repository.TrainingSeriesRepository
+	repository.CreditLedgerRepository
+	repository.PaymentRepository
+	repository.StudentRepository
+	repository.TeamRepository
+	repository.UserRolesRepository
+	repository.WebhookRepository
+	repository.MakeUpCreditRepository
+	repository.UnitOfWork
+	repository.EventOutbox
 }
@@ -46,6 +46,7 @@ func getProject() bson.D {
 				{"timezone", "$training_date_info.timezone"},
 				{"startDate", "$training_date_info.start_date"},
 				{"endDate", "$training_date_info.end_date"},
+				{"rescheduledAt", "$training_date_info.reschedule.rescheduled_at"},
 				// 根據你的 TrainDateUI 定義繼續增加欄位...
 			}},
 
@@ -34,6 +34,9 @@ var appointmentCollection = mgo.NewCollectDef(appointmentCollectionName, func()
 		{
 			Keys: bson.D{{Key: "training_date_id", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "student_id", Value: 1}},
+		},
 	}
 })
 
@@ -67,6 +70,7 @@ func withDomainAppt(appt *entity.Appointment) apptOpt {
 		model.UserID = appt.User().UserID()
 		model.UserName = appt.User().UserName()
 		model.ChildName = appt.ChildName()
+		model.StudentID = appt.StudentID()
 		model.Status = string(appt.Status())
 		model.TrainingDateId = trainID
 		model.CreatedAt = appt.CreatedAt()
@@ -75,11 +79,16 @@ func withDomainAppt(appt *entity.Appointment) apptOpt {
 		model.IsWalkIn = appt.IsWalkIn()
 		model.IsGuest = appt.IsGuest()
 		model.ContactInfo = appt.ContactInfo()
+		model.MakeUpOf = appt.MakeUpOf()
 		if info := appt.LeaveInfo(); !info.IsEmpty() {
 			model.Leave = &leaveInfo{
-				Reason:    info.Reason(),
-				Status:    string(info.Status()),
-				CreatedAt: info.CreatedAt(),
+				Reason:        info.Reason(),
+				Status:        string(info.Status()),
+				CreatedAt:     info.CreatedAt(),
+				ReviewedBy:    info.ReviewedBy(),
+				ReviewComment: info.ReviewComment(),
+				ReviewedAt:    info.ReviewedAt(),
+				NotifiedAt:    info.NotifiedAt(),
 			}
 		} else {
 			model.Leave = nil
@@ -114,6 +123,7 @@ type appointment struct {
 	// v2 field
 	V2Fields       `bson:",inline"`
 	ChildName      string        `bson:"child_name,omitempty"`
+	StudentID      string        `bson:"student_id,omitempty"`
 	UserName       string        `bson:"user_name"`
 	UserID         string        `bson:"user_id"`
 	TrainingDateId bson.ObjectID `bson:"training_date_id"`
@@ -128,12 +138,17 @@ type V2Fields struct {
 	IsWalkIn    bool       `bson:"is_walk_in"`
 	IsGuest     bool       `bson:"is_guest"`
 	ContactInfo string     `bson:"contact_info,omitempty"`
+	MakeUpOf    string     `bson:"make_up_of,omitempty"`
 }
 
 type leaveInfo struct {
-	CreatedAt time.Time `bson:"created_at"`
-	Reason    string    `bson:"reason"`
-	Status    string    `bson:"status"`
+	CreatedAt     time.Time  `bson:"created_at"`
+	ReviewedAt    *time.Time `bson:"reviewed_at,omitempty"`
+	NotifiedAt    *time.Time `bson:"notified_at,omitempty"`
+	Reason        string     `bson:"reason"`
+	Status        string     `bson:"status"`
+	ReviewedBy    string     `bson:"reviewed_by,omitempty"`
+	ReviewComment string     `bson:"review_comment,omitempty"`
 }
 
 func (s *appointment) toDomain() (*entity.Appointment, error) {
@@ -154,7 +169,9 @@ func (s *appointment) toDomain() (*entity.Appointment, error) {
 		leaveInfo = entity.NewLeaveInfo(
 			s.Leave.Reason,
 			status,
-			s.Leave.CreatedAt)
+			s.Leave.CreatedAt).
+			WithReview(s.Leave.ReviewedBy, s.Leave.ReviewComment, s.Leave.ReviewedAt).
+			WithNotifiedAt(s.Leave.NotifiedAt)
 	}
 	if s.UpdateAt.IsZero() {
 		s.UpdateAt = s.CreatedAt
@@ -163,6 +180,7 @@ func (s *appointment) toDomain() (*entity.Appointment, error) {
 		entity.WithApptID(s.ID.Hex()),
 		entity.WithUser(user),
 		entity.WithChildName(s.ChildName),
+		entity.WithApptStudentID(s.StudentID),
 		entity.WithTrainingID(s.TrainingDateId.Hex()),
 		entity.WithStatus(status),
 		entity.WithCreatedAt(s.CreatedAt),
@@ -171,6 +189,7 @@ func (s *appointment) toDomain() (*entity.Appointment, error) {
 		entity.WithLeaveInfo(leaveInfo),
 		entity.WithWalkIn(s.IsWalkIn),
 		entity.WithGuest(s.IsGuest, s.ContactInfo),
+		entity.WithMakeUpOf(s.MakeUpOf),
 	)
 }
 
@@ -206,6 +225,7 @@ func (*apptRepoImpl) SaveAppointment(
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	compensateInsert(ctx, op, modelAppt.ID)
 	return nil
 }
 
@@ -217,20 +237,34 @@ func (*apptRepoImpl) SaveManyAppointments(
 	if err != nil {
 		return newInternalError(op, fmt.Errorf("new bulk operation fail: %w", err))
 	}
+	ids := make([]bson.ObjectID, 0, len(appts))
 	for _, appt := range appts {
 		modelAppt, err := newModelAppt(withDomainAppt(appt))
 		if err != nil {
 			return newInternalError(op, err)
 		}
 		bulkOpts = bulkOpts.InsertOne(modelAppt)
+		ids = append(ids, modelAppt.ID)
 	}
 	_, err = bulkOpts.Execute(ctx)
 	if err != nil {
 		return newInternalError(op, fmt.Errorf("execute bulk operation fail: %w", err))
 	}
+	compensateInsert(ctx, op, ids...)
 	return nil
 }
 
+// compensateInsert 不支援交易時登記新增預約的刪除
+func compensateInsert(ctx context.Context, op string, ids ...bson.ObjectID) {
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		coll := mgo.GetDatabase().Collection(appointmentCollectionName)
+		if _, err := coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+			return newInternalError("compensate_"+op, err)
+		}
+		return nil
+	})
+}
+
 func (*apptRepoImpl) FindApptByID(
 	ctx context.Context, id string,
 ) (*entity.Appointment, repository.RepoError) {
@@ -266,13 +300,36 @@ func (*apptRepoImpl) DeleteAppointment(
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	var prev []bson.Raw
+	if repository.InCompensation(ctx) {
+		if prev, err = findRawAppts(ctx, []bson.ObjectID{modelAppt.ID}); err != nil {
+			return newInternalError(op, err)
+		}
+	}
 	_, err = mgo.DeleteById(ctx, modelAppt)
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	compensateDelete(ctx, op, prev)
 	return nil
 }
 
+// compensateDelete 不支援交易時登記刪除預約的還原，重新寫入刪除前的文件
+func compensateDelete(ctx context.Context, op string, prev []bson.Raw) {
+	if len(prev) == 0 {
+		return
+	}
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		coll := mgo.GetDatabase().Collection(appointmentCollectionName)
+		for _, doc := range prev {
+			if _, err := coll.InsertOne(ctx, doc); err != nil {
+				return newInternalError("compensate_"+op, err)
+			}
+		}
+		return nil
+	})
+}
+
 func (*apptRepoImpl) FindApptsByFilter(
 	ctx context.Context, filter repository.FilterAppointment,
 ) ([]*entity.Appointment, repository.RepoError) {
@@ -306,6 +363,7 @@ func getUpdateFieldFromModel(appt *appointment) (bson.M, error) {
 		"user_id":          appt.UserID,
 		"user_name":        appt.UserName,
 		"child_name":       appt.ChildName,
+		"student_id":       appt.StudentID,
 		"training_date_id": appt.TrainingDateId,
 		"status":           appt.Status,
 		"update_at":        appt.UpdateAt,
@@ -316,6 +374,7 @@ func getUpdateFieldFromModel(appt *appointment) (bson.M, error) {
 		"is_walk_in":       appt.IsWalkIn,
 		"is_guest":         appt.IsGuest,
 		"contact_info":     appt.ContactInfo,
+		"make_up_of":       appt.MakeUpOf,
 	}
 	return updateField, nil
 }
@@ -330,12 +389,19 @@ func (*apptRepoImpl) UpdateAppt(ctx context.Context, appt *entity.Appointment) r
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	var prev []bson.Raw
+	if repository.InCompensation(ctx) {
+		if prev, err = findRawAppts(ctx, []bson.ObjectID{modelAppt.ID}); err != nil {
+			return newInternalError(op, err)
+		}
+	}
 	_, err = mgo.UpdateById(ctx, modelAppt, bson.D{
 		{Key: "$set", Value: updateField},
 	})
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	compensateAppts(ctx, op, prev)
 	return nil
 }
 
@@ -347,6 +413,7 @@ func (*apptRepoImpl) UpdateManyAppts(
 	if err != nil {
 		return newInternalError(op, fmt.Errorf("new bulk operation fail: %w", err))
 	}
+	ids := make([]bson.ObjectID, 0, len(appts))
 	for _, appt := range appts {
 		modelAppt, err := newModelAppt(withDomainAppt(appt))
 		if err != nil {
@@ -359,15 +426,54 @@ func (*apptRepoImpl) UpdateManyAppts(
 		bulkOpts = bulkOpts.UpdateById(modelAppt.ID, bson.D{
 			{Key: "$set", Value: updateField},
 		})
+		ids = append(ids, modelAppt.ID)
+	}
+	var prev []bson.Raw
+	if repository.InCompensation(ctx) {
+		if prev, err = findRawAppts(ctx, ids); err != nil {
+			return newInternalError(op, err)
+		}
 	}
 	_, err = bulkOpts.Execute(ctx)
 	if err != nil {
 		return newInternalError(op, fmt.Errorf("execute bulk operation fail: %w", err))
 	}
+	compensateAppts(ctx, op, prev)
 	return nil
 }
 
-func (*apptRepoImpl) MarkAbsentByTrainIDs(ctx context.Context, trainDateIDs []string) ([]string, repository.RepoError) {
+// findRawAppts 取得更新前的原始文件，供不支援交易時還原
+func findRawAppts(ctx context.Context, ids []bson.ObjectID) ([]bson.Raw, error) {
+	cursor, err := mgo.GetDatabase().Collection(appointmentCollectionName).Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// compensateAppts 不支援交易時登記預約的還原，以更新前的文件整份覆蓋
+func compensateAppts(ctx context.Context, op string, prev []bson.Raw) {
+	if len(prev) == 0 {
+		return
+	}
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		coll := mgo.GetDatabase().Collection(appointmentCollectionName)
+		for _, doc := range prev {
+			if _, err := coll.ReplaceOne(ctx, bson.M{"_id": doc.Lookup("_id")}, doc); err != nil {
+				return newInternalError("compensate_"+op, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (*apptRepoImpl) MarkAbsentByTrainIDs(
+	ctx context.Context, trainDateIDs []string,
+) ([]*entity.Appointment, repository.RepoError) {
 	const op = "mark_absent_by_train_ids"
 
 	if len(trainDateIDs) == 0 {
@@ -383,55 +489,123 @@ func (*apptRepoImpl) MarkAbsentByTrainIDs(ctx context.Context, trainDateIDs []st
 		oids = append(oids, oid)
 	}
 
-	// 1. 找出受影響的用戶 ID
-	filter := bson.M{
+	// 1. 找出尚未出席的預約
+	cursor, err := mgo.GetDatabase().Collection(appointmentCollectionName).Find(ctx, bson.M{
 		"training_date_id": bson.M{"$in": oids},
 		"status":           "CONFIRMED",
-	}
-
-	cursor, err := mgo.GetDatabase().Collection(appointmentCollectionName).Find(ctx, filter, options.Find().SetProjection(bson.M{"user_id": 1}))
+	})
 	if err != nil {
 		return nil, newInternalError(op, err)
 	}
-	defer cursor.Close(ctx)
-
-	userIDMap := make(map[string]struct{})
-	for cursor.Next(ctx) {
-		var doc struct {
-			UserID string `bson:"user_id"`
-		}
-		if err := cursor.Decode(&doc); err == nil && doc.UserID != "" {
-			userIDMap[doc.UserID] = struct{}{}
-		}
+	var docs []*appointment
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, newInternalError(op, err)
 	}
-
-	if len(userIDMap) == 0 {
+	if len(docs) == 0 {
 		return nil, nil
 	}
 
-	// 2. 執行批次更新
-	appt, err := newModelAppt()
-	if err != nil {
-		return nil, newInternalError(op, err)
+	// 2. 只更新查到的預約，確保回傳的預約與實際更新的一致
+	now := time.Now()
+	ids := make([]bson.ObjectID, 0, len(docs))
+	appts := make([]*entity.Appointment, 0, len(docs))
+	for _, doc := range docs {
+		doc.Status = "ABSENT"
+		doc.UpdateAt = now
+		appt, err := doc.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		ids = append(ids, doc.ID)
+		appts = append(appts, appt)
 	}
 	update := bson.D{
 		{Key: "$set", Value: bson.D{
 			{Key: "status", Value: "ABSENT"},
-			{Key: "update_at", Value: time.Now()},
+			{Key: "update_at", Value: now},
 		}},
 	}
-	_, err = mgo.UpdateMany(ctx, appt, bson.D{
-		{Key: "training_date_id", Value: bson.D{{Key: "$in", Value: oids}}},
+	modelAppts, _ := newModelAppt()
+	_, err = mgo.UpdateMany(ctx, modelAppts, bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}},
 		{Key: "status", Value: "CONFIRMED"},
 	}, update)
 	if err != nil {
 		return nil, newInternalError(op, err)
 	}
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		_, err := mgo.UpdateMany(ctx, modelAppts, bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}},
+			{Key: "status", Value: "ABSENT"},
+		}, bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: "CONFIRMED"}}}})
+		if err != nil {
+			return newInternalError("compensate_"+op, err)
+		}
+		return nil
+	})
+	return appts, nil
+}
+
+func (*apptRepoImpl) ReassignStudent(
+	ctx context.Context, fromStudentIDs []string, to *entity.Student,
+) repository.RepoError {
+	const op = "reassign_student"
+	if len(fromStudentIDs) == 0 {
+		return nil
+	}
+	coll := mgo.GetDatabase().Collection(appointmentCollectionName)
 
-	userIDs := make([]string, 0, len(userIDMap))
-	for uid := range userIDMap {
-		userIDs = append(userIDs, uid)
+	// 合併前先確認來源與目標學員沒有預約同一場次，避免違反唯一索引造成部分更新
+	others := make([]string, 0, len(fromStudentIDs))
+	for _, id := range fromStudentIDs {
+		if id != to.ID() {
+			others = append(others, id)
+		}
+	}
+	if len(others) > 0 {
+		var targetTrainIDs []bson.ObjectID
+		err := coll.Distinct(ctx, "training_date_id", bson.M{"student_id": to.ID()}).Decode(&targetTrainIDs)
+		if err != nil {
+			return newInternalError(op, err)
+		}
+		if len(targetTrainIDs) > 0 {
+			count, err := coll.CountDocuments(ctx, bson.M{
+				"student_id":       bson.M{"$in": others},
+				"training_date_id": bson.M{"$in": targetTrainIDs},
+			})
+			if err != nil {
+				return newInternalError(op, err)
+			}
+			if count > 0 {
+				return newConflictError(op, fmt.Errorf("%d appointments share the same training", count))
+			}
+		}
 	}
 
-	return userIDs, nil
+	filter := bson.M{"student_id": bson.M{"$in": fromStudentIDs}}
+	var prev []bson.Raw
+	if repository.InCompensation(ctx) {
+		cursor, err := coll.Find(ctx, filter)
+		if err != nil {
+			return newInternalError(op, err)
+		}
+		if err := cursor.All(ctx, &prev); err != nil {
+			return newInternalError(op, err)
+		}
+	}
+	_, err := coll.UpdateMany(ctx, filter,
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "student_id", Value: to.ID()},
+			{Key: "child_name", Value: to.Name()},
+			{Key: "update_at", Value: time.Now()},
+		}}},
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return newConflictError(op, err)
+		}
+		return newInternalError(op, err)
+	}
+	compensateAppts(ctx, op, prev)
+	return nil
 }
@@ -144,6 +144,50 @@ func TestModelConversion(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "leave status is invalid")
 	})
+
+	t.Run("MakeUpOf_RoundTrip", func(t *testing.T) {
+		user, err := entity.NewUser("u1", "n1")
+		require.NoError(t, err)
+		domainAppt, err := entity.NewAppointment(
+			entity.WithCreateAppt(genID(), genID(), user, "Child"),
+			entity.WithMakeUpOf("leave-appt"),
+		)
+		require.NoError(t, err)
+
+		model, err := newModelAppt(withDomainAppt(domainAppt))
+		require.NoError(t, err)
+		assert.Equal(t, "leave-appt", model.MakeUpOf)
+
+		back, err := model.toDomain()
+		require.NoError(t, err)
+		assert.True(t, back.IsMakeUp())
+		assert.Equal(t, "leave-appt", back.MakeUpOf())
+	})
+
+	t.Run("LeaveReview_RoundTrip", func(t *testing.T) {
+		user, err := entity.NewUser("u1", "n1")
+		require.NoError(t, err)
+		domainAppt, err := entity.NewAppointment(
+			entity.WithCreateAppt(genID(), genID(), user, "Child"),
+		)
+		require.NoError(t, err)
+		start := time.Now().Add(3 * time.Hour)
+		require.NoError(t, domainAppt.RequestLeave("Sick", start, entity.DefaultBookingPolicy()))
+		require.NoError(t, domainAppt.RejectLeave("coach-1", "比賽前不可請假", start))
+
+		model, err := newModelAppt(withDomainAppt(domainAppt))
+		require.NoError(t, err)
+		require.NotNil(t, model.Leave)
+		assert.Equal(t, "coach-1", model.Leave.ReviewedBy)
+		assert.NotNil(t, model.Leave.ReviewedAt)
+		assert.Nil(t, model.Leave.NotifiedAt)
+
+		back, err := model.toDomain()
+		require.NoError(t, err)
+		assert.Equal(t, entity.LeaveStatusRejected, back.LeaveInfo().Status())
+		assert.Equal(t, "比賽前不可請假", back.LeaveInfo().ReviewComment())
+		assert.True(t, back.HasPendingLeaveNotice())
+	})
 }
 
 func TestAppointmentIntegrate(t *testing.T) {
@@ -277,7 +321,7 @@ func TestAppointmentIntegrate(t *testing.T) {
 		updatedAppts := make([]*entity.Appointment, 0, count)
 		startTime := time.Now().Add(-10 * time.Minute)
 		for _, appt := range appts {
-			_ = appt.AdminCheckIn(startTime)
+			_ = appt.AdminCheckIn(startTime, entity.DefaultBookingPolicy())
 			updatedAppts = append(updatedAppts, appt)
 		}
 
@@ -433,7 +477,7 @@ func TestAppointmentIntegrate(t *testing.T) {
 
 		// 2. Append Leave
 		reason := "Sick"
-		err = appt.AppendLeaveRecord(reason, trainingStart)
+		err = appt.AppendLeaveRecord(reason, trainingStart, entity.DefaultBookingPolicy())
 		require.NoError(t, err)
 
 		// 3. Update Repo
@@ -2,6 +2,7 @@ package appointment
 
 import (
 	"errors"
+	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/infra/db/mongo/core"
 	"seanAIgent/internal/util"
@@ -37,6 +38,34 @@ func getQueryByFilterAppt(filter repository.FilterAppointment) (bson.M, reposito
 		q = bson.M{"_id": bson.M{"$in": oids}}
 	case repository.FilterAppointmentByUserID:
 		q = bson.M{"user_id": f.UserID}
+	case repository.FilterApptByStudentIDs:
+		q = bson.M{"student_id": bson.M{"$in": f.StudentIDs}}
+	case repository.FilterApptWithoutStudent:
+		// 訪客預約不屬於任何家長的學員
+		q = bson.M{
+			"student_id": bson.M{"$in": []any{nil, ""}},
+			"is_guest":   bson.M{"$ne": true},
+		}
+		if len(f.ExcludeIDs) > 0 {
+			oids := make([]bson.ObjectID, 0, len(f.ExcludeIDs))
+			for _, id := range f.ExcludeIDs {
+				oid, err := bson.ObjectIDFromHex(id)
+				if err != nil {
+					return nil, newInvalidDocumentIDError("getQueryByFilterAppt", err)
+				}
+				oids = append(oids, oid)
+			}
+			q["_id"] = bson.M{"$nin": oids}
+		}
+	case repository.FilterApptHasPendingLeaveNotice:
+		// 待審核的請假通知教練，教練審核後通知家長結果
+		q = bson.M{
+			"leave.notified_at": bson.M{"$exists": false},
+			"$or": []bson.M{
+				{"status": string(entity.StatusConfirmed), "leave.status": string(entity.LeaveStatusPending)},
+				{"leave.reviewed_by": bson.M{"$exists": true}},
+			},
+		}
 	default:
 		// 處理未定義的 Filter 型別，避免靜默失敗
 		filterName := util.GetTypeName(filter)
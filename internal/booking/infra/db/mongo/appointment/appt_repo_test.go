@@ -36,6 +36,13 @@ func TestGetQueryByFilterAppt(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, bson.M{"user_id": "user-456"}, query)
 	})
+
+	t.Run("FilterApptHasPendingLeaveNotice", func(t *testing.T) {
+		query, err := getQueryByFilterAppt(repository.NewFilterApptHasPendingLeaveNotice())
+		assert.NoError(t, err)
+		assert.Equal(t, bson.M{"$exists": false}, query["leave.notified_at"])
+		assert.Len(t, query["$or"], 2)
+	})
 }
 
 var cleanDb func()
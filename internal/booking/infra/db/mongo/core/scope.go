@@ -0,0 +1,30 @@
+package core
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/domain/repository"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const TeamIDField = "team_id"
+
+// ApplyTeamScope context 限定團隊時加上 team_id 條件，避免團隊教練查到其他團隊的資料
+func ApplyTeamScope(ctx context.Context, q bson.M) bson.M {
+	teamID, ok := repository.TeamScopeFromContext(ctx)
+	if !ok {
+		return q
+	}
+	if q == nil {
+		q = bson.M{}
+	}
+	q[TeamIDField] = teamID
+	return q
+}
+
+// InTeamScope 單筆資料是否在 context 限定的團隊內，未限定時一律通過
+func InTeamScope(ctx context.Context, teamID string) bool {
+	scope, ok := repository.TeamScopeFromContext(ctx)
+	return !ok || scope == teamID
+}
@@ -0,0 +1,285 @@
+package credit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const creditLedgerCollectionName = "credit_ledger"
+
+var creditLedgerCollection = mgo.NewCollectDef(creditLedgerCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "allocations.appt_id", Value: 1}},
+		},
+	}
+})
+
+type creditLedgerOpt func(*creditLedger) error
+
+// 帳本以 LINE 使用者 ID 作為 _id，一位家長只會有一本帳本
+func withUserID(userID string) creditLedgerOpt {
+	return func(l *creditLedger) error {
+		if userID == "" {
+			return errors.New("user id is empty")
+		}
+		l.ID = userID
+		return nil
+	}
+}
+
+func withDomainCreditLedger(ledger *entity.CreditLedger) creditLedgerOpt {
+	return func(l *creditLedger) error {
+		if ledger == nil {
+			return errors.New("entity is nil")
+		}
+		l.ID = ledger.UserID()
+		l.UserName = ledger.User().UserName()
+		l.Version = ledger.Version()
+		l.UpdatedAt = ledger.UpdatedAt()
+		l.Packs = make([]*creditPack, 0, len(ledger.Packs()))
+		for _, p := range ledger.Packs() {
+			l.Packs = append(l.Packs, &creditPack{
+				ID:          p.ID(),
+				ChildName:   p.ChildName(),
+				Quantity:    p.Quantity(),
+				PurchasedAt: p.PurchasedAt(),
+				ExpiresAt:   p.ExpiresAt(),
+				Note:        p.Note(),
+			})
+		}
+		l.Allocations = make([]*creditAllocation, 0, len(ledger.Allocations()))
+		for _, a := range ledger.Allocations() {
+			l.Allocations = append(l.Allocations, &creditAllocation{
+				ApptID:    a.ApptID(),
+				PackID:    a.PackID(),
+				ChildName: a.ChildName(),
+				Status:    a.Status().String(),
+				UpdatedAt: a.UpdatedAt(),
+			})
+		}
+		l.Entries = make([]*creditEntry, 0, len(ledger.Entries()))
+		for _, e := range ledger.Entries() {
+			l.Entries = append(l.Entries, &creditEntry{
+				Type:       e.Type().String(),
+				PackID:     e.PackID(),
+				ApptID:     e.ApptID(),
+				ChildName:  e.ChildName(),
+				Amount:     e.Amount(),
+				Note:       e.Note(),
+				OccurredAt: e.OccurredAt(),
+			})
+		}
+		l.Migration.Status = mgo.MigrateStatusSuccess
+		l.Migration.Version = 1
+		l.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelCreditLedger(opts ...creditLedgerOpt) (*creditLedger, error) {
+	l := &creditLedger{
+		Index: creditLedgerCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(l); err != nil {
+			return nil, fmt.Errorf("new credit ledger fail: %w", err)
+		}
+	}
+	return l, nil
+}
+
+type creditLedger struct {
+	UpdatedAt   time.Time `bson:"updated_at"`
+	mgo.Index   `bson:"-"`
+	Migration   mgo.MigrationInfo   `bson:"_migration"`
+	ID          string              `bson:"_id"`
+	UserName    string              `bson:"user_name"`
+	Packs       []*creditPack       `bson:"packs"`
+	Allocations []*creditAllocation `bson:"allocations"`
+	Entries     []*creditEntry      `bson:"entries"`
+	Version     int                 `bson:"version"`
+}
+
+type creditPack struct {
+	PurchasedAt time.Time `bson:"purchased_at"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+	ID          string    `bson:"id"`
+	ChildName   string    `bson:"child_name,omitempty"`
+	Note        string    `bson:"note,omitempty"`
+	Quantity    int       `bson:"quantity"`
+}
+
+type creditAllocation struct {
+	UpdatedAt time.Time `bson:"updated_at"`
+	ApptID    string    `bson:"appt_id"`
+	PackID    string    `bson:"pack_id"`
+	ChildName string    `bson:"child_name"`
+	Status    string    `bson:"status"`
+}
+
+type creditEntry struct {
+	OccurredAt time.Time `bson:"occurred_at"`
+	Type       string    `bson:"type"`
+	PackID     string    `bson:"pack_id,omitempty"`
+	ApptID     string    `bson:"appt_id,omitempty"`
+	ChildName  string    `bson:"child_name,omitempty"`
+	Note       string    `bson:"note,omitempty"`
+	Amount     int       `bson:"amount"`
+}
+
+func (l *creditLedger) toDomain() (*entity.CreditLedger, error) {
+	user, err := entity.NewUser(l.ID, l.UserName)
+	if err != nil {
+		return nil, err
+	}
+	packs := make([]entity.CreditPack, 0, len(l.Packs))
+	for _, p := range l.Packs {
+		pack, err := entity.NewCreditPack(p.ID, p.ChildName, p.Quantity, p.PurchasedAt, p.ExpiresAt, p.Note)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, pack)
+	}
+	allocations := make([]entity.CreditAllocation, 0, len(l.Allocations))
+	for _, a := range l.Allocations {
+		status, ok := entity.CreditAllocationStatusFromString(a.Status)
+		if !ok {
+			return nil, fmt.Errorf("credit allocation status is invalid: %s", a.Status)
+		}
+		allocations = append(allocations,
+			entity.NewCreditAllocation(a.ApptID, a.PackID, a.ChildName, status, a.UpdatedAt))
+	}
+	entries := make([]entity.CreditLedgerEntry, 0, len(l.Entries))
+	for _, e := range l.Entries {
+		entryType, ok := entity.CreditEntryTypeFromString(e.Type)
+		if !ok {
+			return nil, fmt.Errorf("credit entry type is invalid: %s", e.Type)
+		}
+		entries = append(entries, entity.NewCreditLedgerEntry(
+			entryType, e.PackID, e.ApptID, e.ChildName, e.Amount, e.Note, e.OccurredAt))
+	}
+	return entity.NewCreditLedger(
+		entity.WithCreditLedgerUser(user),
+		entity.WithCreditLedgerPacks(packs),
+		entity.WithCreditLedgerAllocations(allocations),
+		entity.WithCreditLedgerEntries(entries),
+		entity.WithCreditLedgerVersion(l.Version),
+		entity.WithCreditLedgerUpdatedAt(l.UpdatedAt),
+	)
+}
+
+func (l *creditLedger) GetId() any {
+	if l.ID == "" {
+		return nil
+	}
+	return l.ID
+}
+
+func (l *creditLedger) SetId(id any) {
+	userID, ok := id.(string)
+	if !ok {
+		return
+	}
+	l.ID = userID
+}
+
+func (l *creditLedger) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*creditLedgerRepoImpl) SaveCreditLedger(
+	ctx context.Context, ledger *entity.CreditLedger,
+) repository.RepoError {
+	const op = "save_credit_ledger"
+	model, err := newModelCreditLedger(withDomainCreditLedger(ledger))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	filter := bson.M{"_id": model.ID, "version": model.Version}
+	update := bson.M{
+		"$set": bson.M{
+			"user_name":   model.UserName,
+			"packs":       model.Packs,
+			"allocations": model.Allocations,
+			"entries":     model.Entries,
+			"updated_at":  model.UpdatedAt,
+			"version":     model.Version + 1,
+			"_migration":  model.Migration,
+		},
+	}
+	coll := mgo.GetDatabase().Collection(creditLedgerCollectionName)
+	var prev bson.Raw
+	if model.Version > 0 && repository.InCompensation(ctx) {
+		prev, err = coll.FindOne(ctx, filter).Raw()
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return newInternalError(op, err)
+		}
+	}
+	// 新帳本 (version 0) 以 upsert 建立，其餘以版本號比對避免覆蓋他人的修改
+	opts := options.UpdateOne().SetUpsert(model.Version == 0)
+	result, err := coll.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return newConflictError(op, err)
+		}
+		return newInternalError(op, err)
+	}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		return newConflictError(op, fmt.Errorf("credit ledger %s version %d is outdated", ledger.UserID(), ledger.Version()))
+	}
+	compensateLedger(ctx, model.ID, model.Version+1, prev)
+	return nil
+}
+
+// compensateLedger 不支援交易時登記帳本的還原，新建立的帳本直接刪除；
+// 只還原本次寫入的版本，之後已被其他操作更新時不覆蓋
+func compensateLedger(ctx context.Context, id string, version int, prev bson.Raw) {
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		coll := mgo.GetDatabase().Collection(creditLedgerCollectionName)
+		filter := bson.M{"_id": id, "version": version}
+		var err error
+		if prev == nil {
+			_, err = coll.DeleteOne(ctx, filter)
+		} else {
+			_, err = coll.ReplaceOne(ctx, filter, prev)
+		}
+		if err != nil {
+			return newInternalError("compensate_save_credit_ledger", err)
+		}
+		return nil
+	})
+}
+
+func (*creditLedgerRepoImpl) FindCreditLedgerByUserID(
+	ctx context.Context, userID string,
+) (*entity.CreditLedger, repository.RepoError) {
+	const op = "find_credit_ledger_by_user_id"
+	model, err := newModelCreditLedger(withUserID(userID))
+	if err != nil {
+		return nil, newNotFoundError(op, err)
+	}
+	err = mgo.FindById(ctx, model)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	ledger, err := model.toDomain()
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	return ledger, nil
+}
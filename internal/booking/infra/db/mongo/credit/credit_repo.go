@@ -0,0 +1,27 @@
+package credit
+
+import (
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+)
+
+func NewCreditLedgerRepository() repository.CreditLedgerRepository {
+	return &creditLedgerRepoImpl{}
+}
+
+type creditLedgerRepoImpl struct {
+}
+
+const repoName = "credit_ledger"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
+
+func newConflictError(op string, err error) repository.RepoError {
+	return core.NewConflictError(repoName, op, err)
+}
@@ -0,0 +1,56 @@
+package credit
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelConversion(t *testing.T) {
+	user, err := entity.NewUser("user-123", "Test User")
+	require.NoError(t, err)
+
+	ledger, err := entity.NewCreditLedger(
+		entity.WithCreditLedgerUser(user),
+		entity.WithCreditLedgerVersion(2),
+	)
+	require.NoError(t, err)
+	_, err = ledger.TopUp("p1", "", 10, time.Now().AddDate(0, 3, 0), "10 堂")
+	require.NoError(t, err)
+	_, err = ledger.TopUp("p2", "ChildA", 4, time.Now().AddDate(0, 1, 0), "")
+	require.NoError(t, err)
+	start := time.Now().Add(48 * time.Hour)
+	require.NoError(t, ledger.Reserve("appt-1", "ChildA", start))
+	require.NoError(t, ledger.Reserve("appt-2", "ChildB", start))
+	require.NoError(t, ledger.Consume("appt-1"))
+
+	model, err := newModelCreditLedger(withDomainCreditLedger(ledger))
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", model.ID)
+	assert.Equal(t, 2, model.Version)
+	require.Len(t, model.Packs, 2)
+	require.Len(t, model.Allocations, 2)
+	assert.Equal(t, "CONSUMED", model.Allocations[0].Status)
+	assert.Equal(t, "p2", model.Allocations[0].PackID)
+	assert.Len(t, model.Entries, 5)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", back.UserID())
+	assert.Equal(t, "Test User", back.User().UserName())
+	assert.Equal(t, 2, back.Version())
+	assert.Equal(t, ledger.Balance(time.Now()), back.Balance(time.Now()))
+	assert.Len(t, back.Entries(), 5)
+}
+
+func TestModelConversion_InvalidStatus(t *testing.T) {
+	model, err := newModelCreditLedger(withUserID("user-123"))
+	require.NoError(t, err)
+	model.UserName = "Test User"
+	model.Allocations = []*creditAllocation{{ApptID: "a1", PackID: "p1", Status: "UNKNOWN"}}
+	_, err = model.toDomain()
+	assert.Error(t, err)
+}
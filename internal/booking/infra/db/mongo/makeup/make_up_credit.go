@@ -0,0 +1,246 @@
+package makeup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	makeUpCreditCollectionName = "make_up_credit"
+	transformIDFailMsg         = "transform id fail: %w"
+)
+
+var makeUpCreditCollection = mgo.NewCollectDef(makeUpCreditCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			// 一筆請假只發放一筆補課資格
+			Keys:    bson.D{{Key: "leave_appt_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "expires_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "redeemed_appt_id", Value: 1}},
+		},
+	}
+})
+
+type makeUpCreditOpt func(*makeUpCredit) error
+
+func withMakeUpCreditID(id string) makeUpCreditOpt {
+	return func(m *makeUpCredit) error {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		m.ID = oid
+		return nil
+	}
+}
+
+func withDomainMakeUpCredit(c *entity.MakeUpCredit) makeUpCreditOpt {
+	return func(m *makeUpCredit) error {
+		if c == nil {
+			return errors.New("entity is nil")
+		}
+		oid, err := bson.ObjectIDFromHex(c.ID())
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		m.ID = oid
+		m.UserID = c.User().UserID()
+		m.UserName = c.User().UserName()
+		m.LeaveApptID = c.LeaveApptID()
+		m.LeaveTrainingID = c.LeaveTrainingID()
+		m.StudentID = c.StudentID()
+		m.ChildName = c.ChildName()
+		m.Status = c.Status().String()
+		m.RedeemedApptID = c.RedeemedApptID()
+		m.RedeemedAt = c.RedeemedAt()
+		m.IssuedAt = c.IssuedAt()
+		m.ExpiresAt = c.ExpiresAt()
+		m.UpdatedAt = c.UpdatedAt()
+		m.Version = c.Version()
+		m.Migration.Status = mgo.MigrateStatusSuccess
+		m.Migration.Version = 1
+		m.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelMakeUpCredit(opts ...makeUpCreditOpt) (*makeUpCredit, error) {
+	m := &makeUpCredit{
+		Index: makeUpCreditCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			return nil, fmt.Errorf("new make up credit fail: %w", err)
+		}
+	}
+	return m, nil
+}
+
+type makeUpCredit struct {
+	IssuedAt        time.Time  `bson:"issued_at"`
+	ExpiresAt       time.Time  `bson:"expires_at"`
+	UpdatedAt       time.Time  `bson:"updated_at"`
+	RedeemedAt      *time.Time `bson:"redeemed_at,omitempty"`
+	mgo.Index       `bson:"-"`
+	Migration       mgo.MigrationInfo `bson:"_migration"`
+	UserID          string            `bson:"user_id"`
+	UserName        string            `bson:"user_name"`
+	LeaveApptID     string            `bson:"leave_appt_id"`
+	LeaveTrainingID string            `bson:"leave_training_id"`
+	StudentID       string            `bson:"student_id,omitempty"`
+	ChildName       string            `bson:"child_name"`
+	Status          string            `bson:"status"`
+	RedeemedApptID  string            `bson:"redeemed_appt_id,omitempty"`
+	Version         int               `bson:"version"`
+	ID              bson.ObjectID     `bson:"_id"`
+}
+
+func (m *makeUpCredit) toDomain() (*entity.MakeUpCredit, error) {
+	user, err := entity.NewUser(m.UserID, m.UserName)
+	if err != nil {
+		return nil, err
+	}
+	status, ok := entity.MakeUpCreditStatusFromString(m.Status)
+	if !ok {
+		return nil, fmt.Errorf("make up credit status is invalid: %s", m.Status)
+	}
+	return entity.NewMakeUpCredit(
+		entity.WithMakeUpCreditID(m.ID.Hex()),
+		entity.WithMakeUpCreditUser(user),
+		entity.WithMakeUpCreditLeave(m.LeaveApptID, m.LeaveTrainingID),
+		entity.WithMakeUpCreditStudent(m.StudentID, m.ChildName),
+		entity.WithMakeUpCreditStatus(status),
+		entity.WithMakeUpCreditRedeemed(m.RedeemedApptID, m.RedeemedAt),
+		entity.WithMakeUpCreditIssuedAt(m.IssuedAt),
+		entity.WithMakeUpCreditExpiresAt(m.ExpiresAt),
+		entity.WithMakeUpCreditUpdatedAt(m.UpdatedAt),
+		entity.WithMakeUpCreditVersion(m.Version),
+	)
+}
+
+func (m *makeUpCredit) GetId() any {
+	if m.ID.IsZero() {
+		return nil
+	}
+	return m.ID
+}
+
+func (m *makeUpCredit) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	m.ID = oid
+}
+
+func (m *makeUpCredit) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*makeUpCreditRepoImpl) SaveMakeUpCredit(
+	ctx context.Context, c *entity.MakeUpCredit,
+) repository.RepoError {
+	const op = "save_make_up_credit"
+	model, err := newModelMakeUpCredit(withDomainMakeUpCredit(c))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	filter := bson.M{"_id": model.ID, "version": model.Version}
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":           model.UserID,
+			"user_name":         model.UserName,
+			"leave_appt_id":     model.LeaveApptID,
+			"leave_training_id": model.LeaveTrainingID,
+			"student_id":        model.StudentID,
+			"child_name":        model.ChildName,
+			"status":            model.Status,
+			"redeemed_appt_id":  model.RedeemedApptID,
+			"redeemed_at":       model.RedeemedAt,
+			"issued_at":         model.IssuedAt,
+			"expires_at":        model.ExpiresAt,
+			"updated_at":        model.UpdatedAt,
+			"version":           model.Version + 1,
+			"_migration":        model.Migration,
+		},
+	}
+	// 新資格 (version 0) 以 upsert 建立，其餘以版本號比對避免重複使用
+	opts := options.UpdateOne().SetUpsert(model.Version == 0)
+	result, err := mgo.GetDatabase().Collection(makeUpCreditCollectionName).UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return newConflictError(op, err)
+		}
+		return newInternalError(op, err)
+	}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		return newConflictError(op, fmt.Errorf("make up credit %s version %d is outdated", c.ID(), c.Version()))
+	}
+	return nil
+}
+
+func (*makeUpCreditRepoImpl) FindMakeUpCreditByID(
+	ctx context.Context, id string,
+) (*entity.MakeUpCredit, repository.RepoError) {
+	const op = "find_make_up_credit_by_id"
+	model, err := newModelMakeUpCredit(withMakeUpCreditID(id))
+	if err != nil {
+		return nil, newInvalidDocumentIDError(op, err)
+	}
+	err = mgo.FindById(ctx, model)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	c, err := model.toDomain()
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	return c, nil
+}
+
+func (*makeUpCreditRepoImpl) FindMakeUpCreditsByFilter(
+	ctx context.Context, filter repository.FilterMakeUpCredit,
+) ([]*entity.MakeUpCredit, repository.RepoError) {
+	const op = "find_make_up_credits_by_filter"
+	q, repoErr := getQueryByFilterMakeUpCredit(filter)
+	if repoErr != nil {
+		return nil, repoErr
+	}
+	model, _ := newModelMakeUpCredit()
+	results, err := mgo.Find(ctx, model, q, core.DefaultLimit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	credits := make([]*entity.MakeUpCredit, 0, len(results))
+	for _, result := range results {
+		c, err := result.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		credits = append(credits, c)
+	}
+	return credits, nil
+}
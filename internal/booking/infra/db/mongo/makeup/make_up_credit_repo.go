@@ -0,0 +1,53 @@
+package makeup
+
+import (
+	"errors"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+	"seanAIgent/internal/util"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func NewMakeUpCreditRepository() repository.MakeUpCreditRepository {
+	return &makeUpCreditRepoImpl{}
+}
+
+type makeUpCreditRepoImpl struct {
+}
+
+func getQueryByFilterMakeUpCredit(filter repository.FilterMakeUpCredit) (bson.M, repository.RepoError) {
+	var q bson.M
+	switch f := filter.(type) {
+	case repository.FilterMakeUpCreditByUserID:
+		q = bson.M{"user_id": f.UserID}
+	case repository.FilterMakeUpCreditByLeaveApptID:
+		q = bson.M{"leave_appt_id": f.ApptID}
+	case repository.FilterMakeUpCreditByRedeemedApptID:
+		q = bson.M{"redeemed_appt_id": f.ApptID}
+	default:
+		// 處理未定義的 Filter 型別，避免靜默失敗
+		filterName := util.GetTypeName(filter)
+		return nil, newInternalError(
+			"getQueryByFilterMakeUpCredit", errors.New("Filter not implemented: "+filterName))
+	}
+	return q, nil
+}
+
+const repoName = "make_up_credit"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
+
+func newConflictError(op string, err error) repository.RepoError {
+	return core.NewConflictError(repoName, op, err)
+}
+
+func newInvalidDocumentIDError(op string, err error) repository.RepoError {
+	return core.NewInvalidDocumentIDError(repoName, op, err)
+}
@@ -0,0 +1,60 @@
+package makeup
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestModelConversion(t *testing.T) {
+	user, err := entity.NewUser("user-123", "Test User")
+	require.NoError(t, err)
+
+	id := bson.NewObjectID().Hex()
+	issuedAt := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	credit, err := entity.NewMakeUpCredit(
+		entity.WithMakeUpCreditID(id),
+		entity.WithMakeUpCreditUser(user),
+		entity.WithMakeUpCreditLeave("leave-1", "train-1"),
+		entity.WithMakeUpCreditStudent("student-1", "ChildA"),
+		entity.WithMakeUpCreditIssuedAt(issuedAt),
+		entity.WithMakeUpCreditExpiresAt(issuedAt.Add(30*24*time.Hour)),
+		entity.WithMakeUpCreditVersion(3),
+	)
+	require.NoError(t, err)
+	require.NoError(t, credit.Redeem("appt-2", "train-2", time.Now().Add(48*time.Hour)))
+
+	model, err := newModelMakeUpCredit(withDomainMakeUpCredit(credit))
+	require.NoError(t, err)
+	assert.Equal(t, id, model.ID.Hex())
+	assert.Equal(t, "REDEEMED", model.Status)
+	assert.Equal(t, "appt-2", model.RedeemedApptID)
+	assert.Equal(t, 3, model.Version)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, id, back.ID())
+	assert.Equal(t, "Test User", back.User().UserName())
+	assert.Equal(t, "leave-1", back.LeaveApptID())
+	assert.Equal(t, "train-1", back.LeaveTrainingID())
+	assert.Equal(t, "student-1", back.StudentID())
+	assert.Equal(t, "ChildA", back.ChildName())
+	assert.Equal(t, entity.MakeUpCreditRedeemed, back.Status())
+	assert.Equal(t, "appt-2", back.RedeemedApptID())
+	assert.Equal(t, credit.ExpiresAt(), back.ExpiresAt())
+	assert.Equal(t, 3, back.Version())
+}
+
+func TestModelConversion_InvalidStatus(t *testing.T) {
+	model, err := newModelMakeUpCredit(withMakeUpCreditID(bson.NewObjectID().Hex()))
+	require.NoError(t, err)
+	model.UserID = "user-123"
+	model.UserName = "Test User"
+	model.Status = "UNKNOWN"
+	_, err = model.toDomain()
+	assert.Error(t, err)
+}
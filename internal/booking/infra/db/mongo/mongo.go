@@ -4,17 +4,39 @@ import (
 	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/infra/db/core"
 	"seanAIgent/internal/booking/infra/db/mongo/appointment"
+	"seanAIgent/internal/booking/infra/db/mongo/credit"
+	"seanAIgent/internal/booking/infra/db/mongo/makeup"
+	"seanAIgent/internal/booking/infra/db/mongo/outbox"
+	"seanAIgent/internal/booking/infra/db/mongo/payment"
+	"seanAIgent/internal/booking/infra/db/mongo/role"
+	"seanAIgent/internal/booking/infra/db/mongo/series"
 	"seanAIgent/internal/booking/infra/db/mongo/stats"
+	"seanAIgent/internal/booking/infra/db/mongo/student"
+	"seanAIgent/internal/booking/infra/db/mongo/team"
 	"seanAIgent/internal/booking/infra/db/mongo/train"
+	"seanAIgent/internal/booking/infra/db/mongo/tx"
+	"seanAIgent/internal/booking/infra/db/mongo/waitlist"
+	"seanAIgent/internal/booking/infra/db/mongo/webhook"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 func NewRepoAndIdGenerate() core.DbRepository {
 	repoImpl := &dbRepoImpl{
-		AppointmentRepository: appointment.NewApptRepository(),
-		TrainRepository:       train.NewCachedTrainRepository(train.NewTrainRepository()),
-		StatsRepository:       stats.NewCachedStatsRepository(stats.NewStatsRepository()),
+		AppointmentRepository:    appointment.NewApptRepository(),
+		TrainRepository:          train.NewCachedTrainRepository(train.NewTrainRepository()),
+		StatsRepository:          stats.NewCachedStatsRepository(stats.NewStatsRepository()),
+		WaitlistRepository:       waitlist.NewWaitlistRepository(),
+		TrainingSeriesRepository: series.NewTrainingSeriesRepository(),
+		CreditLedgerRepository:   credit.NewCreditLedgerRepository(),
+		PaymentRepository:        payment.NewPaymentRepository(),
+		StudentRepository:        student.NewStudentRepository(),
+		TeamRepository:           team.NewTeamRepository(),
+		UserRolesRepository:      role.NewCachedUserRolesRepository(role.NewUserRolesRepository()),
+		WebhookRepository:        webhook.NewWebhookRepository(),
+		MakeUpCreditRepository:   makeup.NewMakeUpCreditRepository(),
+		UnitOfWork:               tx.NewUnitOfWork(),
+		EventOutbox:              outbox.NewEventOutbox(),
 	}
 	return repoImpl
 }
@@ -23,6 +45,17 @@ type dbRepoImpl struct {
 	repository.AppointmentRepository
 	repository.TrainRepository
 	repository.StatsRepository
+	repository.WaitlistRepository
+	repository.TrainingSeriesRepository
+	repository.CreditLedgerRepository
+	repository.PaymentRepository
+	repository.StudentRepository
+	repository.TeamRepository
+	repository.UserRolesRepository
+	repository.WebhookRepository
+	repository.MakeUpCreditRepository
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
 func (dbRepoImpl) GenerateID() string {
@@ -0,0 +1,40 @@
+package outbox
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+	"seanAIgent/internal/event"
+
+	"github.com/94peter/vulpes/db/mgo"
+)
+
+const repoName = "outbox"
+
+func NewEventOutbox() repository.EventOutbox {
+	return &eventOutboxImpl{}
+}
+
+type eventOutboxImpl struct{}
+
+// AddEvents 沿用 context 內的 session，在 UnitOfWork 內呼叫即與業務資料一起提交
+func (*eventOutboxImpl) AddEvents(ctx context.Context, events ...event.Event) repository.RepoError {
+	const op = "add_events"
+	outbox := event.NewMongoOutbox(mgo.GetDatabase())
+	if err := outbox.Add(ctx, events...); err != nil {
+		return core.NewInternalError(repoName, op, err)
+	}
+	// 不支援交易時事件已寫入，後續步驟失敗需收回尚未發送的事件
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID())
+	}
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		if err := outbox.Remove(ctx, ids...); err != nil {
+			return core.NewInternalError(repoName, "compensate_add_events", err)
+		}
+		return nil
+	})
+	return nil
+}
@@ -0,0 +1,174 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	paymentCollectionName = "payment"
+	transformIDFailMsg    = "transform id fail: %w"
+	// 單月收款筆數上限，與月報匯出的筆數上限一致
+	periodPaymentLimit = 1000
+)
+
+var paymentCollection = mgo.NewCollectDef(paymentCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "year", Value: 1}, {Key: "month", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "year", Value: 1}, {Key: "month", Value: 1}},
+		},
+	}
+})
+
+type paymentOpt func(*payment) error
+
+func withDomainPayment(p *entity.Payment) paymentOpt {
+	return func(model *payment) error {
+		if p == nil {
+			return errors.New("entity is nil")
+		}
+		oid, err := bson.ObjectIDFromHex(p.ID())
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		model.ID = oid
+		model.UserID = p.User().UserID()
+		model.UserName = p.User().UserName()
+		model.Year = p.Year()
+		model.Month = p.Month()
+		model.Amount = p.Amount()
+		model.Method = p.Method().String()
+		model.Reference = p.Reference()
+		model.Note = p.Note()
+		model.PaidAt = p.PaidAt()
+		model.CreatedAt = p.CreatedAt()
+		model.Migration.Status = mgo.MigrateStatusSuccess
+		model.Migration.Version = 1
+		model.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelPayment(opts ...paymentOpt) (*payment, error) {
+	p := &payment{
+		Index: paymentCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, fmt.Errorf("new payment fail: %w", err)
+		}
+	}
+	return p, nil
+}
+
+type payment struct {
+	PaidAt    time.Time `bson:"paid_at"`
+	CreatedAt time.Time `bson:"created_at"`
+	mgo.Index `bson:"-"`
+	Migration mgo.MigrationInfo `bson:"_migration"`
+	UserID    string            `bson:"user_id"`
+	UserName  string            `bson:"user_name"`
+	Method    string            `bson:"method"`
+	Reference string            `bson:"reference,omitempty"`
+	Note      string            `bson:"note,omitempty"`
+	Year      int               `bson:"year"`
+	Month     int               `bson:"month"`
+	Amount    int               `bson:"amount"`
+	ID        bson.ObjectID     `bson:"_id"`
+}
+
+func (p *payment) toDomain() (*entity.Payment, error) {
+	user, err := entity.NewUser(p.UserID, p.UserName)
+	if err != nil {
+		return nil, err
+	}
+	method, ok := entity.PaymentMethodFromString(p.Method)
+	if !ok {
+		return nil, fmt.Errorf("payment method is invalid: %s", p.Method)
+	}
+	return entity.NewPayment(
+		entity.WithPaymentID(p.ID.Hex()),
+		entity.WithPaymentUser(user),
+		entity.WithPaymentPeriod(p.Year, p.Month),
+		entity.WithPaymentAmount(p.Amount),
+		entity.WithPaymentMethod(method),
+		entity.WithPaymentReference(p.Reference),
+		entity.WithPaymentNote(p.Note),
+		entity.WithPaymentPaidAt(p.PaidAt),
+		entity.WithPaymentCreatedAt(p.CreatedAt),
+	)
+}
+
+func (p *payment) GetId() any {
+	if p.ID.IsZero() {
+		return nil
+	}
+	return p.ID
+}
+
+func (p *payment) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	p.ID = oid
+}
+
+func (p *payment) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*paymentRepoImpl) SavePayment(
+	ctx context.Context, p *entity.Payment,
+) repository.RepoError {
+	const op = "save_payment"
+	model, err := newModelPayment(withDomainPayment(p))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	_, err = mgo.Save(ctx, model)
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	return nil
+}
+
+func (*paymentRepoImpl) FindPaymentsByFilter(
+	ctx context.Context, filter repository.FilterPayment,
+) ([]*entity.Payment, repository.RepoError) {
+	const op = "find_payments_by_filter"
+	q, repoErr := getQueryByFilterPayment(filter)
+	if repoErr != nil {
+		return nil, repoErr
+	}
+	model, _ := newModelPayment()
+	results, err := mgo.Find(ctx, model, q, periodPaymentLimit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	payments := make([]*entity.Payment, 0, len(results))
+	for _, result := range results {
+		p, err := result.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		payments = append(payments, p)
+	}
+	return payments, nil
+}
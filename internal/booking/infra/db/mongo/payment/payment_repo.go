@@ -0,0 +1,43 @@
+package payment
+
+import (
+	"errors"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+	"seanAIgent/internal/util"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func NewPaymentRepository() repository.PaymentRepository {
+	return &paymentRepoImpl{}
+}
+
+type paymentRepoImpl struct {
+}
+
+func getQueryByFilterPayment(filter repository.FilterPayment) (bson.M, repository.RepoError) {
+	var q bson.M
+	switch f := filter.(type) {
+	case repository.FilterPaymentByPeriod:
+		q = bson.M{"year": f.Year, "month": f.Month}
+	case repository.FilterPaymentByUserPeriod:
+		q = bson.M{"user_id": f.UserID, "year": f.Year, "month": f.Month}
+	default:
+		// 處理未定義的 Filter 型別，避免靜默失敗
+		filterName := util.GetTypeName(filter)
+		return nil, newInternalError(
+			"getQueryByFilterPayment", errors.New("Filter not implemented: "+filterName))
+	}
+	return q, nil
+}
+
+const repoName = "payment"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
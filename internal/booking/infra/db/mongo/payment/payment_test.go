@@ -0,0 +1,57 @@
+package payment
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestModelConversion(t *testing.T) {
+	id := bson.NewObjectID().Hex()
+	user, err := entity.NewUser("user-123", "Test User")
+	require.NoError(t, err)
+	paidAt := time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+
+	p, err := entity.NewPayment(
+		entity.WithPaymentID(id),
+		entity.WithPaymentUser(user),
+		entity.WithPaymentPeriod(2026, 3),
+		entity.WithPaymentAmount(1200),
+		entity.WithPaymentMethod(entity.PaymentMethodBankTransfer),
+		entity.WithPaymentReference("12345"),
+		entity.WithPaymentNote("三月學費"),
+		entity.WithPaymentPaidAt(paidAt),
+	)
+	require.NoError(t, err)
+
+	model, err := newModelPayment(withDomainPayment(p))
+	require.NoError(t, err)
+	assert.Equal(t, id, model.ID.Hex())
+	assert.Equal(t, "BANK_TRANSFER", model.Method)
+	assert.Equal(t, 3, model.Month)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, id, back.ID())
+	assert.Equal(t, "user-123", back.User().UserID())
+	assert.Equal(t, 1200, back.Amount())
+	assert.Equal(t, entity.PaymentMethodBankTransfer, back.Method())
+	assert.Equal(t, "12345", back.Reference())
+	assert.Equal(t, paidAt, back.PaidAt())
+}
+
+func TestGetQueryByFilterPayment(t *testing.T) {
+	q, err := getQueryByFilterPayment(nil)
+	assert.Nil(t, q)
+	assert.Error(t, err)
+
+	q, err = getQueryByFilterPayment(repository.NewFilterPaymentByUserPeriod("u1", 2026, 3))
+	require.Nil(t, err)
+	assert.Equal(t, "u1", q["user_id"])
+	assert.Equal(t, 3, q["month"])
+}
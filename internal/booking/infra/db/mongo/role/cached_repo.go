@@ -0,0 +1,72 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"golang.org/x/sync/singleflight"
+)
+
+// cachedUserRolesRepo 每個請求都會查詢登入者的角色，短暫快取避免每次都打到資料庫；
+// 多台主機時其他主機最多延遲 1 分鐘才會套用新的角色
+type cachedUserRolesRepo struct {
+	delegate repository.UserRolesRepository
+	cache    *cache.Cache
+	sfGroup  *singleflight.Group
+}
+
+func NewCachedUserRolesRepository(delegate repository.UserRolesRepository) repository.UserRolesRepository {
+	return &cachedUserRolesRepo{
+		delegate: delegate,
+		cache:    cache.New(time.Minute, 5*time.Minute),
+		sfGroup:  &singleflight.Group{},
+	}
+}
+
+func (r *cachedUserRolesRepo) SaveUserRoles(ctx context.Context, u *entity.UserRoles) repository.RepoError {
+	defer r.cache.Delete(u.User().UserID())
+	return r.delegate.SaveUserRoles(ctx, u)
+}
+
+func (r *cachedUserRolesRepo) DeleteUserRoles(ctx context.Context, userID string) repository.RepoError {
+	defer r.cache.Delete(userID)
+	return r.delegate.DeleteUserRoles(ctx, userID)
+}
+
+// FindUserRoles 查無角色也會快取 (存 nil)，一般使用者佔大多數
+func (r *cachedUserRolesRepo) FindUserRoles(
+	ctx context.Context, userID string,
+) (*entity.UserRoles, repository.RepoError) {
+	if val, found := r.cache.Get(userID); found {
+		if val == nil {
+			return nil, errNotFoundCached
+		}
+		return val.(*entity.UserRoles), nil
+	}
+
+	res, err, _ := r.sfGroup.Do(userID, func() (interface{}, error) {
+		data, repoErr := r.delegate.FindUserRoles(ctx, userID)
+		if repoErr != nil {
+			if errors.Is(repoErr, repository.ErrNotFound) {
+				r.cache.Set(userID, nil, cache.DefaultExpiration)
+			}
+			return nil, repoErr
+		}
+		r.cache.Set(userID, data, cache.DefaultExpiration)
+		return data, nil
+	})
+	if err != nil {
+		return nil, err.(repository.RepoError)
+	}
+	return res.(*entity.UserRoles), nil
+}
+
+func (r *cachedUserRolesRepo) FindAllUserRoles(ctx context.Context) ([]*entity.UserRoles, repository.RepoError) {
+	return r.delegate.FindAllUserRoles(ctx)
+}
+
+var errNotFoundCached = newNotFoundError("find_user_roles", errors.New("not found (cached)"))
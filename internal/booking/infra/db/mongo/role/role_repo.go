@@ -0,0 +1,23 @@
+package role
+
+import (
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+)
+
+func NewUserRolesRepository() repository.UserRolesRepository {
+	return &userRolesRepoImpl{}
+}
+
+type userRolesRepoImpl struct {
+}
+
+const repoName = "user_roles"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
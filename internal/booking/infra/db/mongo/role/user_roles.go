@@ -0,0 +1,192 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const userRolesCollectionName = "user_roles"
+
+var userRolesCollection = mgo.NewCollectDef(userRolesCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+})
+
+type userRolesOpt func(*userRoles) error
+
+func withDomainUserRoles(u *entity.UserRoles) userRolesOpt {
+	return func(model *userRoles) error {
+		if u == nil {
+			return errors.New("entity is nil")
+		}
+		model.UserID = u.User().UserID()
+		model.UserName = u.User().UserName()
+		model.Roles = make([]string, 0, len(u.Roles()))
+		for _, r := range u.Roles() {
+			model.Roles = append(model.Roles, r.String())
+		}
+		model.UpdatedBy = u.UpdatedBy()
+		model.UpdatedAt = u.UpdatedAt()
+		model.Migration.Status = mgo.MigrateStatusSuccess
+		model.Migration.Version = 1
+		model.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelUserRoles(opts ...userRolesOpt) (*userRoles, error) {
+	u := &userRoles{
+		Index: userRolesCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(u); err != nil {
+			return nil, fmt.Errorf("new user roles fail: %w", err)
+		}
+	}
+	return u, nil
+}
+
+type userRoles struct {
+	UpdatedAt time.Time `bson:"updated_at"`
+	mgo.Index `bson:"-"`
+	Migration mgo.MigrationInfo `bson:"_migration"`
+	UserID    string            `bson:"user_id"`
+	UserName  string            `bson:"user_name"`
+	UpdatedBy string            `bson:"updated_by"`
+	Roles     []string          `bson:"roles"`
+	ID        bson.ObjectID     `bson:"_id,omitempty"`
+}
+
+func (u *userRoles) toDomain() (*entity.UserRoles, error) {
+	user, err := entity.NewUser(u.UserID, u.UserName)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]entity.Role, 0, len(u.Roles))
+	for _, r := range u.Roles {
+		role, err := entity.ParseRole(r)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return entity.NewUserRoles(
+		entity.WithUserRolesUser(user),
+		entity.WithUserRolesRoles(roles...),
+		entity.WithUserRolesUpdatedBy(u.UpdatedBy),
+		entity.WithUserRolesUpdatedAt(u.UpdatedAt),
+	)
+}
+
+func (u *userRoles) GetId() any {
+	if u.ID.IsZero() {
+		return nil
+	}
+	return u.ID
+}
+
+func (u *userRoles) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	u.ID = oid
+}
+
+func (u *userRoles) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*userRolesRepoImpl) SaveUserRoles(
+	ctx context.Context, u *entity.UserRoles,
+) repository.RepoError {
+	const op = "save_user_roles"
+	model, err := newModelUserRoles(withDomainUserRoles(u))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"user_name":  model.UserName,
+			"roles":      model.Roles,
+			"updated_by": model.UpdatedBy,
+			"updated_at": model.UpdatedAt,
+			"_migration": model.Migration,
+		},
+	}
+	_, err = mgo.GetDatabase().Collection(userRolesCollectionName).UpdateOne(
+		ctx, bson.M{"user_id": model.UserID}, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	return nil
+}
+
+func (*userRolesRepoImpl) DeleteUserRoles(
+	ctx context.Context, userID string,
+) repository.RepoError {
+	const op = "delete_user_roles"
+	_, err := mgo.GetDatabase().Collection(userRolesCollectionName).DeleteOne(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	return nil
+}
+
+func (r *userRolesRepoImpl) FindUserRoles(
+	ctx context.Context, userID string,
+) (*entity.UserRoles, repository.RepoError) {
+	const op = "find_user_roles"
+	results, err := r.find(ctx, op, bson.M{"user_id": userID}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, newNotFoundError(op, mongo.ErrNoDocuments)
+	}
+	return results[0], nil
+}
+
+func (r *userRolesRepoImpl) FindAllUserRoles(
+	ctx context.Context,
+) ([]*entity.UserRoles, repository.RepoError) {
+	return r.find(ctx, "find_all_user_roles", bson.M{}, core.DefaultLimit)
+}
+
+func (*userRolesRepoImpl) find(
+	ctx context.Context, op string, q bson.M, limit uint16,
+) ([]*entity.UserRoles, repository.RepoError) {
+	model, _ := newModelUserRoles()
+	results, err := mgo.Find(ctx, model, q, limit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	roles := make([]*entity.UserRoles, 0, len(results))
+	for _, result := range results {
+		u, err := result.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		roles = append(roles, u)
+	}
+	return roles, nil
+}
@@ -0,0 +1,37 @@
+package role
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelConversion(t *testing.T) {
+	user, err := entity.NewUser("coach-2", "Amy")
+	require.NoError(t, err)
+	roles, err := entity.NewUserRoles(
+		entity.WithUserRolesUser(user),
+		entity.WithUserRolesRoles(entity.RoleAssistantCoach, entity.RoleStaff, entity.RoleStaff),
+		entity.WithUserRolesUpdatedBy("owner-1"),
+	)
+	require.NoError(t, err)
+
+	model, err := newModelUserRoles(withDomainUserRoles(roles))
+	require.NoError(t, err)
+	assert.Equal(t, "coach-2", model.UserID)
+	assert.Equal(t, []string{"assistant_coach", "staff"}, model.Roles)
+	assert.Equal(t, "owner-1", model.UpdatedBy)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, "Amy", back.User().UserName())
+	assert.True(t, back.Can(entity.PermAttendanceWrite))
+	assert.True(t, back.Can(entity.PermBillingWrite))
+	assert.False(t, back.Can(entity.PermTrainingWrite))
+
+	model.Roles = append(model.Roles, "superuser")
+	_, err = model.toDomain()
+	assert.ErrorIs(t, err, entity.ErrUserRolesInvalid)
+}
@@ -0,0 +1,283 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	seriesCollectionName = "training_series"
+	transformIDFailMsg   = "transform id fail: %w"
+)
+
+var seriesCollection = mgo.NewCollectDef(seriesCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "coach_id", Value: 1}, {Key: "status", Value: 1}},
+		},
+	}
+})
+
+type seriesOpt func(*trainingSeries) error
+
+func withSeriesID(id string) seriesOpt {
+	return func(s *trainingSeries) error {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		s.ID = oid
+		return nil
+	}
+}
+
+func withDomainSeries(series *entity.TrainingSeries) seriesOpt {
+	return func(s *trainingSeries) error {
+		if series == nil {
+			return errors.New("entity is nil")
+		}
+		oid, err := bson.ObjectIDFromHex(series.ID())
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		rule := series.Rule()
+		weekdays := make([]int, 0, len(rule.Weekdays()))
+		for _, d := range rule.Weekdays() {
+			weekdays = append(weekdays, int(d))
+		}
+		s.ID = oid
+		s.CoachID = series.CoachID()
+		s.Location = series.Location()
+		s.Capacity = series.Capacity()
+		s.Timezone = series.Timezone()
+		s.StartDate = series.StartDate()
+		s.StartTime = series.StartClock()
+		s.EndTime = series.EndClock()
+		s.Frequency = string(rule.Frequency())
+		s.Weekdays = weekdays
+		s.Until = rule.Until()
+		s.Count = rule.Count()
+		s.Exceptions = series.Exceptions()
+		s.Status = string(series.Status())
+		s.Version = series.Version()
+		s.CreatedAt = series.CreatedAt()
+		s.UpdatedAt = series.UpdatedAt()
+		s.Migration.Status = mgo.MigrateStatusSuccess
+		s.Migration.Version = 1
+		s.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelSeries(opts ...seriesOpt) (*trainingSeries, error) {
+	s := &trainingSeries{
+		Index: seriesCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, fmt.Errorf("new training series fail: %w", err)
+		}
+	}
+	return s, nil
+}
+
+type trainingSeries struct {
+	CreatedAt  time.Time `bson:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+	mgo.Index  `bson:"-"`
+	Migration  mgo.MigrationInfo `bson:"_migration"`
+	Weekdays   []int             `bson:"weekdays"`
+	Exceptions []string          `bson:"exceptions"`
+	CoachID    string            `bson:"coach_id"`
+	Location   string            `bson:"location"`
+	Timezone   string            `bson:"timezone"`
+	StartDate  string            `bson:"start_date"`
+	StartTime  string            `bson:"start_time"`
+	EndTime    string            `bson:"end_time"`
+	Frequency  string            `bson:"frequency"`
+	Until      string            `bson:"until,omitempty"`
+	Status     string            `bson:"status"`
+	Capacity   int               `bson:"capacity"`
+	Count      int               `bson:"count"`
+	Version    int               `bson:"version"`
+	ID         bson.ObjectID     `bson:"_id"`
+}
+
+func (s *trainingSeries) toDomain() (*entity.TrainingSeries, error) {
+	weekdays := make([]time.Weekday, 0, len(s.Weekdays))
+	for _, d := range s.Weekdays {
+		weekdays = append(weekdays, time.Weekday(d))
+	}
+	rule, err := entity.NewRecurrenceRule(
+		entity.RecurrenceFrequency(s.Frequency), weekdays, s.Until, s.Count)
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewTrainingSeries(
+		entity.WithTrainingSeriesID(s.ID.Hex()),
+		entity.WithTrainingSeriesCoachID(s.CoachID),
+		entity.WithTrainingSeriesLocation(s.Location),
+		entity.WithTrainingSeriesCapacity(s.Capacity),
+		entity.WithTrainingSeriesTimezone(s.Timezone),
+		entity.WithTrainingSeriesSchedule(s.StartDate, s.StartTime, s.EndTime),
+		entity.WithTrainingSeriesRule(rule),
+		entity.WithTrainingSeriesExceptions(s.Exceptions),
+		entity.WithTrainingSeriesStatus(entity.TrainingSeriesStatus(s.Status)),
+		entity.WithTrainingSeriesVersion(s.Version),
+		entity.WithTrainingSeriesCreatedAt(s.CreatedAt),
+		entity.WithTrainingSeriesUpdatedAt(s.UpdatedAt),
+	)
+}
+
+func (s *trainingSeries) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *trainingSeries) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (s *trainingSeries) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*seriesRepoImpl) SaveTrainingSeries(
+	ctx context.Context, series *entity.TrainingSeries,
+) repository.RepoError {
+	const op = "save_training_series"
+	model, err := newModelSeries(withDomainSeries(series))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	filter := bson.M{"_id": model.ID, "version": model.Version}
+	update := bson.M{
+		"$set": bson.M{
+			"coach_id":   model.CoachID,
+			"location":   model.Location,
+			"capacity":   model.Capacity,
+			"timezone":   model.Timezone,
+			"start_date": model.StartDate,
+			"start_time": model.StartTime,
+			"end_time":   model.EndTime,
+			"frequency":  model.Frequency,
+			"weekdays":   model.Weekdays,
+			"until":      model.Until,
+			"count":      model.Count,
+			"exceptions": model.Exceptions,
+			"status":     model.Status,
+			"created_at": model.CreatedAt,
+			"updated_at": model.UpdatedAt,
+			"version":    model.Version + 1,
+			"_migration": model.Migration,
+		},
+	}
+	coll := mgo.GetDatabase().Collection(seriesCollectionName)
+	var prev bson.Raw
+	if model.Version > 0 && repository.InCompensation(ctx) {
+		prev, err = coll.FindOne(ctx, filter).Raw()
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return newInternalError(op, err)
+		}
+	}
+	// 新系列 (version 0) 以 upsert 建立，其餘以版本號比對避免覆蓋他人的修改
+	opts := options.UpdateOne().SetUpsert(model.Version == 0)
+	result, err := coll.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return newConflictError(op, err)
+		}
+		return newInternalError(op, err)
+	}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		return newConflictError(op, fmt.Errorf("training series %s version %d is outdated", series.ID(), series.Version()))
+	}
+	compensateSeries(ctx, model.ID, model.Version+1, prev)
+	return nil
+}
+
+// compensateSeries 不支援交易時登記系列的還原，新建立的系列直接刪除；
+// 只還原本次寫入的版本，之後已被其他操作更新時不覆蓋
+func compensateSeries(ctx context.Context, id bson.ObjectID, version int, prev bson.Raw) {
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		coll := mgo.GetDatabase().Collection(seriesCollectionName)
+		filter := bson.M{"_id": id, "version": version}
+		var err error
+		if prev == nil {
+			_, err = coll.DeleteOne(ctx, filter)
+		} else {
+			_, err = coll.ReplaceOne(ctx, filter, prev)
+		}
+		if err != nil {
+			return newInternalError("compensate_save_training_series", err)
+		}
+		return nil
+	})
+}
+
+func (*seriesRepoImpl) FindTrainingSeriesByID(
+	ctx context.Context, id string,
+) (*entity.TrainingSeries, repository.RepoError) {
+	const op = "find_training_series_by_id"
+	model, err := newModelSeries(withSeriesID(id))
+	if err != nil {
+		return nil, newInvalidDocumentIDError(op, err)
+	}
+	err = mgo.FindById(ctx, model)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	series, err := model.toDomain()
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	return series, nil
+}
+
+func (*seriesRepoImpl) FindTrainingSeries(
+	ctx context.Context, filter repository.FilterTrainingSeries,
+) ([]*entity.TrainingSeries, repository.RepoError) {
+	const op = "find_training_series"
+	q, repoErr := getQueryByFilterTrainingSeries(filter)
+	if repoErr != nil {
+		return nil, repoErr
+	}
+	model, _ := newModelSeries()
+	results, err := mgo.Find(ctx, model, q, core.DefaultLimit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	seriesList := make([]*entity.TrainingSeries, 0, len(results))
+	for _, result := range results {
+		series, err := result.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		seriesList = append(seriesList, series)
+	}
+	return seriesList, nil
+}
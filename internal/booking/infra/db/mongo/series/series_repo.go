@@ -0,0 +1,55 @@
+package series
+
+import (
+	"errors"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+	"seanAIgent/internal/util"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func NewTrainingSeriesRepository() repository.TrainingSeriesRepository {
+	return &seriesRepoImpl{}
+}
+
+type seriesRepoImpl struct {
+}
+
+func getQueryByFilterTrainingSeries(filter repository.FilterTrainingSeries) (bson.M, repository.RepoError) {
+	var q bson.M
+	switch f := filter.(type) {
+	case repository.FilterTrainingSeriesActive:
+		q = bson.M{"status": string(entity.TrainingSeriesStatusActive)}
+	case repository.FilterTrainingSeriesByCoachID:
+		q = bson.M{
+			"coach_id": f.CoachID,
+			"status":   string(entity.TrainingSeriesStatusActive),
+		}
+	default:
+		// 處理未定義的 Filter 型別，避免靜默失敗
+		filterName := util.GetTypeName(filter)
+		return nil, newInternalError(
+			"getQueryByFilterTrainingSeries", errors.New("Filter not implemented: "+filterName))
+	}
+	return q, nil
+}
+
+const repoName = "training_series"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
+
+func newConflictError(op string, err error) repository.RepoError {
+	return core.NewConflictError(repoName, op, err)
+}
+
+func newInvalidDocumentIDError(op string, err error) repository.RepoError {
+	return core.NewInvalidDocumentIDError(repoName, op, err)
+}
@@ -0,0 +1,55 @@
+package series
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestModelConversion(t *testing.T) {
+	id := bson.NewObjectID().Hex()
+	rule, err := entity.NewRecurrenceRule(entity.RecurrenceBiweekly,
+		[]time.Weekday{time.Sunday, time.Wednesday}, "2026-06-30", 0)
+	require.NoError(t, err)
+	s, err := entity.NewTrainingSeries(
+		entity.WithTrainingSeriesID(id),
+		entity.WithTrainingSeriesCoachID("coach1"),
+		entity.WithTrainingSeriesLocation("TKU"),
+		entity.WithTrainingSeriesCapacity(10),
+		entity.WithTrainingSeriesTimezone("Asia/Taipei"),
+		entity.WithTrainingSeriesSchedule("2026-01-04", "14:00", "16:00"),
+		entity.WithTrainingSeriesRule(rule),
+		entity.WithTrainingSeriesExceptions([]string{"2026-02-15"}),
+		entity.WithTrainingSeriesVersion(2),
+	)
+	require.NoError(t, err)
+
+	model, err := newModelSeries(withDomainSeries(s))
+	require.NoError(t, err)
+	assert.Equal(t, id, model.ID.Hex())
+	assert.Equal(t, []int{0, 3}, model.Weekdays)
+	assert.Equal(t, "BIWEEKLY", model.Frequency)
+	assert.Equal(t, 2, model.Version)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, s.StartDate(), back.StartDate())
+	assert.Equal(t, s.Rule(), back.Rule())
+	assert.Equal(t, []string{"2026-02-15"}, back.Exceptions())
+	assert.Equal(t, 2, back.Version())
+}
+
+func TestGetQueryByFilterTrainingSeries(t *testing.T) {
+	q, err := getQueryByFilterTrainingSeries(nil)
+	assert.Nil(t, q)
+	assert.Error(t, err)
+
+	q, err = getQueryByFilterTrainingSeries(repository.NewFilterTrainingSeriesByCoachID("c1"))
+	require.Nil(t, err)
+	assert.Equal(t, "c1", q["coach_id"])
+}
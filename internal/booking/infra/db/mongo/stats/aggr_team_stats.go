@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"context"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+	"slices"
+	"strings"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// findTeamMonthlyStats 團隊的月統計，依場次即時聚合後再做搜尋與分頁
+func (s *statsRepoImpl) findTeamMonthlyStats(
+	ctx context.Context, year, month int, skip, limit int64, search string,
+) ([]*entity.UserMonthlyStat, int64, repository.RepoError) {
+	stats, repoErr := s.aggregateMonthlyStats(ctx, "", year, month)
+	if repoErr != nil {
+		return nil, 0, repoErr
+	}
+	if search != "" {
+		keyword := strings.ToLower(search)
+		stats = slices.DeleteFunc(stats, func(st *entity.UserMonthlyStat) bool {
+			return !strings.Contains(strings.ToLower(st.UserName), keyword)
+		})
+	}
+	slices.SortFunc(stats, func(a, b *entity.UserMonthlyStat) int {
+		return strings.Compare(a.UserName, b.UserName)
+	})
+	total := int64(len(stats))
+	if skip >= total {
+		return []*entity.UserMonthlyStat{}, total, nil
+	}
+	end := total
+	if limit > 0 && skip+limit < total {
+		end = skip + limit
+	}
+	return stats[skip:end], total, nil
+}
+
+// getTeamHistoricalAnalytics 團隊的歷史趨勢，以台北時間的場次月份分組
+func (*statsRepoImpl) getTeamHistoricalAnalytics(
+	ctx context.Context, monthsLimit int,
+) ([]*entity.MonthlyBusinessStat, repository.RepoError) {
+	const op = "get_team_historical_analytics"
+	countStatus := func(status string) bson.D {
+		return bson.D{{"$sum", bson.D{
+			{"$cond", bson.D{
+				{"if", bson.M{"$eq": []interface{}{"$appointments.status", status}}},
+				{"then", 1},
+				{"else", 0},
+			}},
+		}}}
+	}
+	pipe := mongo.Pipeline{
+		{{"$match", core.ApplyTeamScope(ctx, bson.M{})}},
+		{{"$lookup", bson.D{
+			{"from", "appointment"},
+			{"localField", "_id"},
+			{"foreignField", "training_date_id"},
+			{"as", "appointments"},
+		}}},
+		{{"$unwind", "$appointments"}},
+		excludeCoachCancelled,
+		{{"$group", bson.D{
+			{"_id", bson.M{
+				"year":  bson.M{"$year": bson.M{"date": "$start_date", "timezone": "Asia/Taipei"}},
+				"month": bson.M{"$month": bson.M{"date": "$start_date", "timezone": "Asia/Taipei"}},
+			}},
+			{"total_bookings", bson.D{{"$sum", 1}}},
+			{"attended_count", countStatus("ATTENDED")},
+			{"leave_count", countStatus("CANCELLED_LEAVE")},
+			{"users", bson.D{{"$addToSet", "$appointments.user_id"}}},
+		}}},
+		{{"$sort", bson.D{
+			{"_id.year", -1},
+			{"_id.month", -1},
+		}}},
+		{{"$limit", monthsLimit}},
+		{{"$project", bson.D{
+			{"_id", 0},
+			{"year", "$_id.year"},
+			{"month", "$_id.month"},
+			{"total_bookings", "$total_bookings"},
+			{"attended_count", "$attended_count"},
+			{"leave_count", "$leave_count"},
+			{"active_users", bson.D{{"$size", "$users"}}},
+		}}},
+	}
+	results, err := mgo.PipeFindByPipeline[*entity.MonthlyBusinessStat](
+		ctx, "training_date", pipe, core.DefaultLimit,
+	)
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	return results, nil
+}
@@ -12,6 +12,11 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// excludeCoachCancelled 教練停課的預約不計入預約、請假與缺席
+var excludeCoachCancelled = bson.D{{"$match", bson.M{
+	"appointments.status": bson.M{"$ne": entity.StatusCancelledByCoach.String()},
+}}}
+
 func getPipeline(q bson.M, userID string) mongo.Pipeline {
 	matchAppt := bson.M{}
 	if userID != "" {
@@ -27,6 +32,7 @@ func getPipeline(q bson.M, userID string) mongo.Pipeline {
 			{"as", "appointments"},
 		}}},
 		{{"$unwind", "$appointments"}},
+		excludeCoachCancelled,
 	}
 	if userID != "" {
 		pipe = append(pipe, bson.D{{"$match", matchAppt}})
@@ -124,7 +130,7 @@ func (*statsRepoImpl) GetAllUserApptStats(
 	}
 	const op = "get_all_user_appt_stats"
 	result, err := mgo.PipeFindByPipeline[*entity.UserApptStats](
-		ctx, "training_date", getPipeline(q, ""), core.DefaultLimit,
+		ctx, "training_date", getPipeline(core.ApplyTeamScope(ctx, q), ""), core.DefaultLimit,
 	)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -144,7 +150,7 @@ func (*statsRepoImpl) GetUserApptStats(
 	}
 	const op = "get_user_appt_stats"
 	result, err := mgo.PipeFindByPipeline[*entity.UserApptStats](
-		ctx, "training_date", getPipeline(q, userID), 1,
+		ctx, "training_date", getPipeline(core.ApplyTeamScope(ctx, q), userID), 1,
 	)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -190,7 +196,7 @@ func (s *statsRepoImpl) aggregateMonthlyStats(ctx context.Context, userID string
 	}
 
 	pipe := mongo.Pipeline{
-		{{"$match", match}},
+		{{"$match", core.ApplyTeamScope(ctx, match)}},
 		{{"$lookup", bson.D{
 			{"from", "appointment"},
 			{"localField", "_id"},
@@ -198,6 +204,7 @@ func (s *statsRepoImpl) aggregateMonthlyStats(ctx context.Context, userID string
 			{"as", "appointments"},
 		}}},
 		{{"$unwind", "$appointments"}},
+		excludeCoachCancelled,
 	}
 
 	if userID != "" {
@@ -240,6 +247,18 @@ func (s *statsRepoImpl) aggregateMonthlyStats(ctx context.Context, userID string
 					}},
 				}},
 			}},
+			// 以請假補課資格預約的場次
+			{"make_up_count", bson.D{
+				{"$sum", bson.D{
+					{"$cond", bson.D{
+						{"if", bson.M{"$ne": []interface{}{
+							bson.M{"$ifNull": []interface{}{"$appointments.make_up_of", ""}}, "",
+						}}},
+						{"then", 1},
+						{"else", 0},
+					}},
+				}},
+			}},
 		}}},
 		// 第二步：按用戶分組，將孩子數據聚合為陣列
 		{{"$group", bson.D{
@@ -249,12 +268,14 @@ func (s *statsRepoImpl) aggregateMonthlyStats(ctx context.Context, userID string
 			{"attended_count", bson.D{{"$sum", "$attended_count"}}},
 			{"absent_count", bson.D{{"$sum", "$absent_count"}}},
 			{"leave_count", bson.D{{"$sum", "$leave_count"}}},
+			{"make_up_count", bson.D{{"$sum", "$make_up_count"}}},
 			{"children", bson.D{{"$push", bson.D{
 				{"child_name", "$_id.child_name"},
 				{"total_bookings", "$total_bookings"},
 				{"attended_count", "$attended_count"},
 				{"absent_count", "$absent_count"},
 				{"leave_count", "$leave_count"},
+				{"make_up_count", "$make_up_count"},
 			}}}},
 		}}},
 		{{"$project", bson.D{
@@ -267,6 +288,7 @@ func (s *statsRepoImpl) aggregateMonthlyStats(ctx context.Context, userID string
 			{"attended_count", "$attended_count"},
 			{"absent_count", "$absent_count"},
 			{"leave_count", "$leave_count"},
+			{"make_up_count", "$make_up_count"},
 			{"children", "$children"},
 			{"last_updated_at", bson.D{{"$literal", time.Now()}}},
 		}}},
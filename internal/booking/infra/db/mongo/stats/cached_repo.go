@@ -96,6 +96,9 @@ func (r *cachedStatsRepo) AggregateAllUsersMonthlyStats(ctx context.Context, yea
 func (r *cachedStatsRepo) GetHistoricalAnalytics(ctx context.Context, monthsLimit int) ([]*entity.MonthlyBusinessStat, repository.RepoError) {
 	// 趨勢數據變動頻率低，可以快取
 	cacheKey := "historical_analytics:" + strconv.Itoa(monthsLimit)
+	if teamID, ok := repository.TeamScopeFromContext(ctx); ok {
+		cacheKey += ":team:" + teamID
+	}
 	if val, found := r.cache.Get(cacheKey); found {
 		return val.([]*entity.MonthlyBusinessStat), nil
 	}
@@ -105,6 +105,10 @@ func (s *statsRepoImpl) FindMonthlyStats(
 	ctx context.Context, year, month int, skip, limit int64, search string,
 ) ([]*entity.UserMonthlyStat, int64, repository.RepoError) {
 	const op = "find_monthly_stats"
+	if _, ok := repository.TeamScopeFromContext(ctx); ok {
+		// 月統計未區分團隊，限定團隊時改由場次即時計算
+		return s.findTeamMonthlyStats(ctx, year, month, skip, limit, search)
+	}
 	filter := bson.M{
 		"year":  year,
 		"month": month,
@@ -141,6 +145,9 @@ func (s *statsRepoImpl) FindMonthlyStats(
 
 func (s *statsRepoImpl) GetHistoricalAnalytics(ctx context.Context, monthsLimit int) ([]*entity.MonthlyBusinessStat, repository.RepoError) {
 	const op = "get_historical_analytics"
+	if _, ok := repository.TeamScopeFromContext(ctx); ok {
+		return s.getTeamHistoricalAnalytics(ctx, monthsLimit)
+	}
 
 	pipe := mongo.Pipeline{
 		{{"$group", bson.D{
@@ -0,0 +1,306 @@
+package student
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	studentCollectionName = "student"
+	transformIDFailMsg    = "transform id fail: %w"
+)
+
+var studentCollection = mgo.NewCollectDef(studentCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			// 同一家長底下姓名不可重複，重複的舊資料需先合併
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+})
+
+type studentOpt func(*student) error
+
+func withStudentID(id string) studentOpt {
+	return func(s *student) error {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		s.ID = oid
+		return nil
+	}
+}
+
+func withDomainStudent(s *entity.Student) studentOpt {
+	return func(model *student) error {
+		if s == nil {
+			return errors.New("entity is nil")
+		}
+		oid, err := bson.ObjectIDFromHex(s.ID())
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		model.ID = oid
+		model.UserID = s.Parent().UserID()
+		model.UserName = s.Parent().UserName()
+		model.Name = s.Name()
+		model.Nickname = s.Nickname()
+		model.Birthdate = s.Birthdate()
+		model.Notes = s.Notes()
+		model.CreatedAt = s.CreatedAt()
+		model.UpdatedAt = s.UpdatedAt()
+		model.Migration.Status = mgo.MigrateStatusSuccess
+		model.Migration.Version = 1
+		model.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelStudent(opts ...studentOpt) (*student, error) {
+	s := &student{
+		Index: studentCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, fmt.Errorf("new student fail: %w", err)
+		}
+	}
+	return s, nil
+}
+
+type student struct {
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+	Birthdate *time.Time `bson:"birthdate,omitempty"`
+	mgo.Index `bson:"-"`
+	Migration mgo.MigrationInfo `bson:"_migration"`
+	UserID    string            `bson:"user_id"`
+	UserName  string            `bson:"user_name"`
+	Name      string            `bson:"name"`
+	Nickname  string            `bson:"nickname,omitempty"`
+	Notes     string            `bson:"notes,omitempty"`
+	ID        bson.ObjectID     `bson:"_id"`
+}
+
+func (s *student) toDomain() (*entity.Student, error) {
+	parent, err := entity.NewUser(s.UserID, s.UserName)
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewStudent(
+		entity.WithStudentID(s.ID.Hex()),
+		entity.WithStudentParent(parent),
+		entity.WithStudentName(s.Name),
+		entity.WithStudentNickname(s.Nickname),
+		entity.WithStudentBirthdate(s.Birthdate),
+		entity.WithStudentNotes(s.Notes),
+		entity.WithStudentCreatedAt(s.CreatedAt),
+		entity.WithStudentUpdatedAt(s.UpdatedAt),
+	)
+}
+
+func (s *student) GetId() any {
+	if s.ID.IsZero() {
+		return nil
+	}
+	return s.ID
+}
+
+func (s *student) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	s.ID = oid
+}
+
+func (s *student) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*studentRepoImpl) SaveStudent(
+	ctx context.Context, s *entity.Student,
+) repository.RepoError {
+	const op = "save_student"
+	model, err := newModelStudent(withDomainStudent(s))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":    model.UserID,
+			"user_name":  model.UserName,
+			"name":       model.Name,
+			"nickname":   model.Nickname,
+			"birthdate":  model.Birthdate,
+			"notes":      model.Notes,
+			"created_at": model.CreatedAt,
+			"updated_at": model.UpdatedAt,
+			"_migration": model.Migration,
+		},
+	}
+	coll := mgo.GetDatabase().Collection(studentCollectionName)
+	var prev bson.Raw
+	if repository.InCompensation(ctx) {
+		prev, err = coll.FindOne(ctx, bson.M{"_id": model.ID}).Raw()
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return newInternalError(op, err)
+		}
+	}
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": model.ID}, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return newConflictError(op, err)
+		}
+		return newInternalError(op, err)
+	}
+	if result.UpsertedCount > 0 {
+		prev = nil
+	}
+	compensateStudent(ctx, model.ID, prev)
+	return nil
+}
+
+// compensateStudent 不支援交易時登記學員的還原，新建立的學員直接刪除
+func compensateStudent(ctx context.Context, id bson.ObjectID, prev bson.Raw) {
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		coll := mgo.GetDatabase().Collection(studentCollectionName)
+		var err error
+		if prev == nil {
+			_, err = coll.DeleteOne(ctx, bson.M{"_id": id})
+		} else {
+			_, err = coll.ReplaceOne(ctx, bson.M{"_id": id}, prev)
+		}
+		if err != nil {
+			return newInternalError("compensate_save_student", err)
+		}
+		return nil
+	})
+}
+
+func (*studentRepoImpl) DeleteStudents(
+	ctx context.Context, students []*entity.Student,
+) repository.RepoError {
+	const op = "delete_students"
+	if len(students) == 0 {
+		return nil
+	}
+	oids := make([]bson.ObjectID, 0, len(students))
+	for _, s := range students {
+		oid, err := bson.ObjectIDFromHex(s.ID())
+		if err != nil {
+			return newInvalidDocumentIDError(op, err)
+		}
+		oids = append(oids, oid)
+	}
+	coll := mgo.GetDatabase().Collection(studentCollectionName)
+	filter := bson.M{"_id": bson.M{"$in": oids}}
+	var prev []bson.Raw
+	if repository.InCompensation(ctx) {
+		cursor, err := coll.Find(ctx, filter)
+		if err != nil {
+			return newInternalError(op, err)
+		}
+		if err := cursor.All(ctx, &prev); err != nil {
+			return newInternalError(op, err)
+		}
+	}
+	if _, err := coll.DeleteMany(ctx, filter); err != nil {
+		return newInternalError(op, err)
+	}
+	if len(prev) > 0 {
+		// 不支援交易時登記還原，將刪除的學員重新寫回
+		repository.Compensate(ctx, func(ctx context.Context) error {
+			coll := mgo.GetDatabase().Collection(studentCollectionName)
+			for _, doc := range prev {
+				if _, err := coll.InsertOne(ctx, doc); err != nil {
+					return newInternalError("compensate_"+op, err)
+				}
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+func (*studentRepoImpl) FindStudentByID(
+	ctx context.Context, id string,
+) (*entity.Student, repository.RepoError) {
+	const op = "find_student_by_id"
+	model, err := newModelStudent(withStudentID(id))
+	if err != nil {
+		return nil, newInvalidDocumentIDError(op, err)
+	}
+	err = mgo.FindById(ctx, model)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	s, err := model.toDomain()
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	return s, nil
+}
+
+func (r *studentRepoImpl) FindStudentsByUserID(
+	ctx context.Context, userID string,
+) ([]*entity.Student, repository.RepoError) {
+	return r.findStudents(ctx, "find_students_by_user_id", bson.M{"user_id": userID}, core.DefaultLimit)
+}
+
+func (r *studentRepoImpl) FindStudentsByIDs(
+	ctx context.Context, ids []string,
+) ([]*entity.Student, repository.RepoError) {
+	const op = "find_students_by_ids"
+	if len(ids) == 0 {
+		return []*entity.Student{}, nil
+	}
+	oids := make([]bson.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, newInvalidDocumentIDError(op, err)
+		}
+		oids = append(oids, oid)
+	}
+	return r.findStudents(ctx, op, bson.M{"_id": bson.M{"$in": oids}}, uint16(len(oids)))
+}
+
+func (*studentRepoImpl) findStudents(
+	ctx context.Context, op string, q bson.M, limit uint16,
+) ([]*entity.Student, repository.RepoError) {
+	model, _ := newModelStudent()
+	results, err := mgo.Find(ctx, model, q, limit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	students := make([]*entity.Student, 0, len(results))
+	for _, result := range results {
+		s, err := result.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		students = append(students, s)
+	}
+	return students, nil
+}
@@ -0,0 +1,31 @@
+package student
+
+import (
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+)
+
+func NewStudentRepository() repository.StudentRepository {
+	return &studentRepoImpl{}
+}
+
+type studentRepoImpl struct {
+}
+
+const repoName = "student"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
+
+func newConflictError(op string, err error) repository.RepoError {
+	return core.NewConflictError(repoName, op, err)
+}
+
+func newInvalidDocumentIDError(op string, err error) repository.RepoError {
+	return core.NewInvalidDocumentIDError(repoName, op, err)
+}
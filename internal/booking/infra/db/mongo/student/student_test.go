@@ -0,0 +1,42 @@
+package student
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestModelConversion(t *testing.T) {
+	id := bson.NewObjectID().Hex()
+	parent, err := entity.NewUser("user-123", "Test User")
+	require.NoError(t, err)
+	birthdate := time.Date(2016, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := entity.NewStudent(
+		entity.WithStudentID(id),
+		entity.WithStudentParent(parent),
+		entity.WithStudentName(" 王小明 "),
+		entity.WithStudentNickname("小明"),
+		entity.WithStudentBirthdate(&birthdate),
+		entity.WithStudentNotes("氣喘"),
+	)
+	require.NoError(t, err)
+
+	model, err := newModelStudent(withDomainStudent(s))
+	require.NoError(t, err)
+	assert.Equal(t, id, model.ID.Hex())
+	assert.Equal(t, "user-123", model.UserID)
+	assert.Equal(t, "王小明", model.Name)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, id, back.ID())
+	assert.Equal(t, "王小明", back.Name())
+	assert.Equal(t, "小明", back.DisplayName())
+	assert.Equal(t, birthdate, *back.Birthdate())
+	assert.Equal(t, "氣喘", back.Notes())
+}
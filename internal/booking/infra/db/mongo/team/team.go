@@ -0,0 +1,279 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	teamCollectionName = "team"
+	transformIDFailMsg = "transform id fail: %w"
+)
+
+var teamCollection = mgo.NewCollectDef(teamCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "head_coach.user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "assistants.user_id", Value: 1}},
+		},
+	}
+})
+
+type teamOpt func(*team) error
+
+func withTeamID(id string) teamOpt {
+	return func(t *team) error {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		t.ID = oid
+		return nil
+	}
+}
+
+func withDomainTeam(t *entity.Team) teamOpt {
+	return func(model *team) error {
+		if t == nil {
+			return errors.New("entity is nil")
+		}
+		oid, err := bson.ObjectIDFromHex(t.ID())
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		model.ID = oid
+		model.Name = t.Name()
+		model.HeadCoach = newModelCoach(t.HeadCoach())
+		model.Assistants = make([]coach, 0, len(t.Assistants()))
+		for _, a := range t.Assistants() {
+			model.Assistants = append(model.Assistants, newModelCoach(a))
+		}
+		model.MemberIDs = t.MemberIDs()
+		if model.MemberIDs == nil {
+			model.MemberIDs = []string{}
+		}
+		model.LeaveApproval = t.RequiresLeaveApproval()
+		model.CreatedAt = t.CreatedAt()
+		model.UpdatedAt = t.UpdatedAt()
+		model.Migration.Status = mgo.MigrateStatusSuccess
+		model.Migration.Version = 1
+		model.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelTeam(opts ...teamOpt) (*team, error) {
+	t := &team{
+		Index: teamCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(t); err != nil {
+			return nil, fmt.Errorf("new team fail: %w", err)
+		}
+	}
+	return t, nil
+}
+
+type coach struct {
+	UserID   string `bson:"user_id"`
+	UserName string `bson:"user_name"`
+}
+
+func newModelCoach(u entity.User) coach {
+	return coach{UserID: u.UserID(), UserName: u.UserName()}
+}
+
+func (c coach) toDomain() (entity.User, error) {
+	return entity.NewUser(c.UserID, c.UserName)
+}
+
+type team struct {
+	CreatedAt     time.Time `bson:"created_at"`
+	UpdatedAt     time.Time `bson:"updated_at"`
+	mgo.Index     `bson:"-"`
+	Migration     mgo.MigrationInfo `bson:"_migration"`
+	HeadCoach     coach             `bson:"head_coach"`
+	Name          string            `bson:"name"`
+	Assistants    []coach           `bson:"assistants"`
+	MemberIDs     []string          `bson:"member_ids"`
+	ID            bson.ObjectID     `bson:"_id"`
+	LeaveApproval bool              `bson:"leave_approval"`
+}
+
+func (t *team) toDomain() (*entity.Team, error) {
+	headCoach, err := t.HeadCoach.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	assistants := make([]entity.User, 0, len(t.Assistants))
+	for _, a := range t.Assistants {
+		u, err := a.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		assistants = append(assistants, u)
+	}
+	return entity.NewTeam(
+		entity.WithTeamID(t.ID.Hex()),
+		entity.WithTeamName(t.Name),
+		entity.WithTeamHeadCoach(headCoach),
+		entity.WithTeamAssistants(assistants...),
+		entity.WithTeamMemberIDs(t.MemberIDs...),
+		entity.WithTeamLeaveApproval(t.LeaveApproval),
+		entity.WithTeamCreatedAt(t.CreatedAt),
+		entity.WithTeamUpdatedAt(t.UpdatedAt),
+	)
+}
+
+func (t *team) GetId() any {
+	if t.ID.IsZero() {
+		return nil
+	}
+	return t.ID
+}
+
+func (t *team) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	t.ID = oid
+}
+
+func (t *team) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*teamRepoImpl) SaveTeam(
+	ctx context.Context, t *entity.Team,
+) repository.RepoError {
+	const op = "save_team"
+	model, err := newModelTeam(withDomainTeam(t))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"name":           model.Name,
+			"head_coach":     model.HeadCoach,
+			"assistants":     model.Assistants,
+			"member_ids":     model.MemberIDs,
+			"leave_approval": model.LeaveApproval,
+			"created_at":     model.CreatedAt,
+			"updated_at":     model.UpdatedAt,
+			"_migration":     model.Migration,
+		},
+	}
+	_, err = mgo.GetDatabase().Collection(teamCollectionName).UpdateOne(
+		ctx, bson.M{"_id": model.ID}, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return newConflictError(op, err)
+		}
+		return newInternalError(op, err)
+	}
+	return nil
+}
+
+func (*teamRepoImpl) DeleteTeam(
+	ctx context.Context, t *entity.Team,
+) repository.RepoError {
+	const op = "delete_team"
+	oid, err := bson.ObjectIDFromHex(t.ID())
+	if err != nil {
+		return newInvalidDocumentIDError(op, err)
+	}
+	_, err = mgo.GetDatabase().Collection(teamCollectionName).DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	return nil
+}
+
+func (*teamRepoImpl) FindTeamByID(
+	ctx context.Context, id string,
+) (*entity.Team, repository.RepoError) {
+	const op = "find_team_by_id"
+	model, err := newModelTeam(withTeamID(id))
+	if err != nil {
+		return nil, newInvalidDocumentIDError(op, err)
+	}
+	err = mgo.FindById(ctx, model)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	if !core.InTeamScope(ctx, model.ID.Hex()) {
+		return nil, newNotFoundError(op, mongo.ErrNoDocuments)
+	}
+	t, err := model.toDomain()
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	return t, nil
+}
+
+func (r *teamRepoImpl) FindTeams(
+	ctx context.Context,
+) ([]*entity.Team, repository.RepoError) {
+	q := bson.M{}
+	if teamID, ok := repository.TeamScopeFromContext(ctx); ok {
+		oid, err := bson.ObjectIDFromHex(teamID)
+		if err != nil {
+			return nil, newInvalidDocumentIDError("find_teams", err)
+		}
+		q["_id"] = oid
+	}
+	return r.findTeams(ctx, "find_teams", q)
+}
+
+func (r *teamRepoImpl) FindTeamsByCoach(
+	ctx context.Context, userID string,
+) ([]*entity.Team, repository.RepoError) {
+	return r.findTeams(ctx, "find_teams_by_coach", bson.M{"$or": []bson.M{
+		{"head_coach.user_id": userID},
+		{"assistants.user_id": userID},
+	}})
+}
+
+func (*teamRepoImpl) findTeams(
+	ctx context.Context, op string, q bson.M,
+) ([]*entity.Team, repository.RepoError) {
+	model, _ := newModelTeam()
+	results, err := mgo.Find(ctx, model, q, core.DefaultLimit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	teams := make([]*entity.Team, 0, len(results))
+	for _, result := range results {
+		t, err := result.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		teams = append(teams, t)
+	}
+	return teams, nil
+}
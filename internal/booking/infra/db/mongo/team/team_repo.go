@@ -0,0 +1,31 @@
+package team
+
+import (
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+)
+
+func NewTeamRepository() repository.TeamRepository {
+	return &teamRepoImpl{}
+}
+
+type teamRepoImpl struct {
+}
+
+const repoName = "team"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
+
+func newConflictError(op string, err error) repository.RepoError {
+	return core.NewConflictError(repoName, op, err)
+}
+
+func newInvalidDocumentIDError(op string, err error) repository.RepoError {
+	return core.NewInvalidDocumentIDError(repoName, op, err)
+}
@@ -0,0 +1,45 @@
+package team
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestModelConversion(t *testing.T) {
+	id := bson.NewObjectID().Hex()
+	headCoach, err := entity.NewUser("coach-1", "Sean")
+	require.NoError(t, err)
+	assistant, err := entity.NewUser("coach-2", "Amy")
+	require.NoError(t, err)
+
+	team, err := entity.NewTeam(
+		entity.WithTeamID(id),
+		entity.WithTeamName(" 校隊 A "),
+		entity.WithTeamHeadCoach(headCoach),
+		entity.WithTeamAssistants(assistant),
+		entity.WithTeamMemberIDs("student-1", "student-2"),
+		entity.WithTeamLeaveApproval(true),
+	)
+	require.NoError(t, err)
+
+	model, err := newModelTeam(withDomainTeam(team))
+	require.NoError(t, err)
+	assert.Equal(t, id, model.ID.Hex())
+	assert.Equal(t, "校隊 A", model.Name)
+	assert.Equal(t, "coach-1", model.HeadCoach.UserID)
+	require.Len(t, model.Assistants, 1)
+	assert.Equal(t, "Amy", model.Assistants[0].UserName)
+	assert.True(t, model.LeaveApproval)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, id, back.ID())
+	assert.Equal(t, "校隊 A", back.Name())
+	assert.True(t, back.IsCoach("coach-2"))
+	assert.Equal(t, []string{"student-1", "student-2"}, back.MemberIDs())
+	assert.True(t, back.RequiresLeaveApproval())
+}
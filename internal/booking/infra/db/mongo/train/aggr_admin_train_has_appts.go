@@ -30,6 +30,16 @@ func getPipelineTrainDateHasApptState(q bson.M) mongo.Pipeline {
 			{"startDate", "$start_date"},
 			{"endDate", "$end_date"},
 			{"timezone", "$timezone"},
+			{"teamId", "$team_id"},
+			{"status", "$status"},
+			{"cancelReason", "$cancellation.reason"},
+			{"rescheduledAt", "$reschedule.rescheduled_at"},
+			{"bookingPolicy", bson.D{
+				{"cancelWindow", "$booking_policy.cancel_window_minutes"},
+				{"leaveCutoff", "$booking_policy.leave_cutoff_minutes"},
+				{"checkInOpen", "$booking_policy.check_in_open_minutes"},
+				{"attendanceAmend", "$booking_policy.attendance_amend_minutes"},
+			}},
 			{"userAppointments", bson.D{
 				{"$map", bson.D{
 					{"input", "$appointments"},
@@ -48,6 +58,7 @@ func getPipelineTrainDateHasApptState(q bson.M) mongo.Pipeline {
 						{"contactInfo", "$$appt.contact_info"},
 						{"createdAt", "$$appt.created_at"},
 						{"leaveReason", "$$appt.leave.reason"},
+						{"leaveStatus", "$$appt.leave.status"},
 						// 如果還有其他不一致的欄位，在這裡進行轉換
 					}},
 				}},
@@ -65,7 +76,7 @@ func (*trainRepoImpl) QueryTrainDateHasAppointmentState(
 		return nil, repoErr
 	}
 	result, mgoErr := mgo.PipeFindByPipeline[*entity.TrainDateHasApptState](
-		ctx, TrainDateCollectionName, getPipelineTrainDateHasApptState(q), core.DefaultLimit,
+		ctx, TrainDateCollectionName, getPipelineTrainDateHasApptState(core.ApplyTeamScope(ctx, q)), core.DefaultLimit,
 	)
 	if mgoErr != nil {
 		return nil, newInternalError("query_train_date_has_appointment_state", mgoErr)
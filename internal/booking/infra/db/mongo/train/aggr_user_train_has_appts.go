@@ -11,9 +11,14 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// getUserPipelineTrainDateHasApptState 停課的場次不開放預約，家長於「我的預約」查看停課通知
 func getUserPipelineTrainDateHasApptState(userID string, q bson.M) mongo.Pipeline {
+	match := bson.M{"status": bson.M{"$ne": string(entity.TrainDateStatusCancelled)}}
+	for k, v := range q {
+		match[k] = v
+	}
 	pipeline := mongo.Pipeline{
-		{{"$match", q}},
+		{{"$match", match}},
 		{{"$lookup", bson.D{
 			{"from", "appointment"},
 			{"localField", "_id"},
@@ -29,6 +34,7 @@ func getUserPipelineTrainDateHasApptState(userID string, q bson.M) mongo.Pipelin
 			{"startDate", "$start_date"},
 			{"endDate", "$end_date"},
 			{"timezone", "$timezone"},
+			{"rescheduledAt", "$reschedule.rescheduled_at"},
 
 			// B. UserAppointments: 先 Filter 該用戶，再 Map 格式化欄位
 			{"userAppointments", bson.D{
@@ -54,6 +60,7 @@ func getUserPipelineTrainDateHasApptState(userID string, q bson.M) mongo.Pipelin
 						{"contactInfo", "$$u.contact_info"},
 						{"createdAt", "$$u.created_at"},
 						{"leaveReason", "$$u.leave.reason"},
+						{"leaveStatus", "$$u.leave.status"},
 						// 在此處添加其他 UserAppointment 需要的欄位
 					}},
 				}},
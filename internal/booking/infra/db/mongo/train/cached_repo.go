@@ -123,6 +123,9 @@ func (r *cachedTrainRepo) AdminDeductCapacity(ctx context.Context, trainingID st
 func (r *cachedTrainRepo) IncreaseCapacity(ctx context.Context, trainingID string, count int) repository.RepoError {
 	return r.delegate.IncreaseCapacity(ctx, trainingID, count)
 }
+func (r *cachedTrainRepo) ResetAvailableCapacity(ctx context.Context, trainingID string, from, to int) repository.RepoError {
+	return r.delegate.ResetAvailableCapacity(ctx, trainingID, from, to)
+}
 func (r *cachedTrainRepo) FindPastTrainDateIDs(ctx context.Context, cutoff time.Time, limit uint16) ([]string, repository.RepoError) {
 	return r.delegate.FindPastTrainDateIDs(ctx, cutoff, limit)
 }
@@ -29,6 +29,12 @@ var trainingDateCollection = mgo.NewCollectDef(TrainDateCollectionName, func() [
 			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "start_date", Value: 1}, {Key: "end_date", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		{
+			Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "start_date", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "team_id", Value: 1}, {Key: "start_date", Value: 1}},
+		},
 	}
 })
 
@@ -42,6 +48,8 @@ func withDomainTrainDate(training *entity.TrainDate) trainingDateOpt {
 		}
 		td.ID = oid
 		td.UserID = training.UserID()
+		td.SeriesID = training.SeriesID()
+		td.TeamID = training.TeamID()
 		td.Date = training.Period().Start().Format("2006-01-02")
 		td.Location = training.Location()
 		td.Capacity = training.MaxCapacity()
@@ -52,6 +60,27 @@ func withDomainTrainDate(training *entity.TrainDate) trainingDateOpt {
 		td.Status = string(training.Status())
 		td.CreatedAt = training.CreatedAt()
 		td.UpdatedAt = training.UpdatedAt()
+		if training.HasBookingPolicy() {
+			td.BookingPolicy = newModelBookingPolicy(training.BookingPolicy())
+		}
+		if c := training.Cancellation(); !c.IsZero() {
+			td.Cancellation = &cancellation{
+				CancelledAt: c.CancelledAt(),
+				Reason:      c.Reason(),
+				CancelledBy: c.CancelledBy(),
+				NoticeSent:  c.NoticeSent(),
+			}
+		}
+		if r := training.LastReschedule(); !r.IsZero() {
+			td.Reschedule = &reschedule{
+				PreviousStart:    r.PreviousPeriod().Start(),
+				PreviousEnd:      r.PreviousPeriod().End(),
+				PreviousLocation: r.PreviousLocation(),
+				RescheduledAt:    r.RescheduledAt(),
+				RescheduledBy:    r.RescheduledBy(),
+				NoticeSent:       r.NoticeSent(),
+			}
+		}
 		return nil
 	}
 }
@@ -106,16 +135,97 @@ type trainDate struct {
 	Date              string            `bson:"date"`
 	Status            string            `bson:"status"`
 	UserID            string            `bson:"user_id"`
+	SeriesID          string            `bson:"series_id,omitempty"`
+	TeamID            string            `bson:"team_id,omitempty"`
+	BookingPolicy     *bookingPolicy    `bson:"booking_policy,omitempty"`
+	Cancellation      *cancellation     `bson:"cancellation,omitempty"`
+	Reschedule        *reschedule       `bson:"reschedule,omitempty"`
 	AvailableCapacity int               `bson:"available_capacity"`
 	Capacity          int               `bson:"capacity"`
 	ID                bson.ObjectID     `bson:"_id"`
 }
 
+// bookingPolicy 以分鐘儲存，方便直接於資料庫檢視與調整
+type bookingPolicy struct {
+	CancelWindowMinutes    int64 `bson:"cancel_window_minutes"`
+	LeaveCutoffMinutes     int64 `bson:"leave_cutoff_minutes"`
+	CheckInOpenMinutes     int64 `bson:"check_in_open_minutes"`
+	AttendanceAmendMinutes int64 `bson:"attendance_amend_minutes"`
+}
+
+// cancellation 停課資訊，notice_sent 供通知排程查詢
+type cancellation struct {
+	CancelledAt time.Time `bson:"cancelled_at"`
+	Reason      string    `bson:"reason"`
+	CancelledBy string    `bson:"cancelled_by"`
+	NoticeSent  bool      `bson:"notice_sent"`
+}
+
+// reschedule 最近一次改期前的時間地點，notice_sent 供通知排程查詢
+type reschedule struct {
+	PreviousStart    time.Time `bson:"previous_start"`
+	PreviousEnd      time.Time `bson:"previous_end"`
+	RescheduledAt    time.Time `bson:"rescheduled_at"`
+	PreviousLocation string    `bson:"previous_location"`
+	RescheduledBy    string    `bson:"rescheduled_by"`
+	NoticeSent       bool      `bson:"notice_sent"`
+}
+
+func (r *reschedule) toDomain() (entity.TrainDateReschedule, error) {
+	previous, err := entity.NewTimeRange(r.PreviousStart, r.PreviousEnd)
+	if err != nil {
+		return entity.TrainDateReschedule{}, err
+	}
+	return entity.NewTrainDateReschedule(
+		previous, r.PreviousLocation, r.RescheduledBy, r.RescheduledAt, r.NoticeSent), nil
+}
+
+func newModelBookingPolicy(p entity.BookingPolicy) *bookingPolicy {
+	return &bookingPolicy{
+		CancelWindowMinutes:    int64(p.CancelWindow() / time.Minute),
+		LeaveCutoffMinutes:     int64(p.LeaveCutoff() / time.Minute),
+		CheckInOpenMinutes:     int64(p.CheckInOpen() / time.Minute),
+		AttendanceAmendMinutes: int64(p.AttendanceAmend() / time.Minute),
+	}
+}
+
+func (p *bookingPolicy) toDomain() (entity.BookingPolicy, error) {
+	return entity.NewBookingPolicy(
+		time.Duration(p.CancelWindowMinutes)*time.Minute,
+		time.Duration(p.LeaveCutoffMinutes)*time.Minute,
+		time.Duration(p.CheckInOpenMinutes)*time.Minute,
+		time.Duration(p.AttendanceAmendMinutes)*time.Minute,
+	)
+}
+
 func (s *trainDate) toDomain() (*entity.TrainDate, error) {
 	timeRange, err := entity.NewTimeRange(s.StartDate, s.EndDate)
 	if err != nil {
 		return nil, err
 	}
+	var policy entity.BookingPolicy
+	if s.BookingPolicy != nil {
+		policy, err = s.BookingPolicy.toDomain()
+		if err != nil {
+			return nil, err
+		}
+	}
+	status := entity.TrainDateStatusActive
+	if s.Status != "" {
+		status = entity.TrainDateStatus(s.Status)
+	}
+	var cancelled entity.TrainDateCancellation
+	if s.Cancellation != nil {
+		cancelled = entity.NewTrainDateCancellation(
+			s.Cancellation.Reason, s.Cancellation.CancelledBy, s.Cancellation.CancelledAt, s.Cancellation.NoticeSent)
+	}
+	var rescheduled entity.TrainDateReschedule
+	if s.Reschedule != nil {
+		rescheduled, err = s.Reschedule.toDomain()
+		if err != nil {
+			return nil, err
+		}
+	}
 	trainDate, err := entity.NewTrainDate(
 		entity.WithTrainDateID(s.ID.Hex()),
 		entity.WithTrainDateUserID(s.UserID),
@@ -126,6 +236,12 @@ func (s *trainDate) toDomain() (*entity.TrainDate, error) {
 		entity.WithTrainDateCreatedAt(s.CreatedAt),
 		entity.WithTrainDateUpdatedAt(s.UpdatedAt),
 		entity.WithTrainDateTimezone(s.Timezone),
+		entity.WithTrainDateSeriesID(s.SeriesID),
+		entity.WithTrainDateTeamID(s.TeamID),
+		entity.WithTrainDateBookingPolicy(policy),
+		entity.WithTrainDateStatus(status),
+		entity.WithTrainDateCancellation(cancelled),
+		entity.WithTrainDateReschedule(rescheduled),
 	)
 	if err != nil {
 		return nil, err
@@ -167,10 +283,16 @@ func (*trainRepoImpl) SaveTrainDate(
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	ids := []bson.ObjectID{modelTraining.ID}
+	prev, err := findRawTrainDates(ctx, ids)
+	if err != nil {
+		return newInternalError(op, err)
+	}
 	_, err = mgo.Save(ctx, modelTraining)
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	compensateTrainDates(ctx, op, ids, prev)
 	return nil
 }
 
@@ -182,6 +304,7 @@ func (*trainRepoImpl) SaveManyTrainDates(
 	if err != nil {
 		return newInternalError(op, fmt.Errorf("new bulk operation fail: %w", err))
 	}
+	ids := make([]bson.ObjectID, 0, len(trainings))
 	for _, training := range trainings {
 		modelTraining, err := newTrainDate(
 			withDomainTrainDate(training),
@@ -195,11 +318,13 @@ func (*trainRepoImpl) SaveManyTrainDates(
 			return newInternalError(op, err)
 		}
 		bulkOpts = bulkOpts.InsertOne(modelTraining)
+		ids = append(ids, modelTraining.ID)
 	}
 	_, err = bulkOpts.Execute(ctx)
 	if err != nil {
 		return newInternalError(op, fmt.Errorf("execute bulk operation fail: %w", err))
 	}
+	compensateTrainDates(ctx, op, ids, nil)
 	return nil
 }
 
@@ -211,10 +336,16 @@ func (*trainRepoImpl) DeleteTrainingDate(
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	ids := []bson.ObjectID{modelTraining.ID}
+	prev, err := findRawTrainDates(ctx, ids)
+	if err != nil {
+		return newInternalError(op, err)
+	}
 	_, err = mgo.DeleteById(ctx, modelTraining)
 	if err != nil {
 		return newInternalError(op, err)
 	}
+	compensateTrainDates(ctx, op, ids, prev)
 	return nil
 }
 
@@ -233,6 +364,9 @@ func (*trainRepoImpl) FindTrainDateByID(
 		}
 		return nil, newInternalError(op, fmt.Errorf("mgo find fail: %w", err))
 	}
+	if !core.InTeamScope(ctx, doc.TeamID) {
+		return nil, newNotFoundError(op, mongo.ErrNoDocuments)
+	}
 	trainDate, err := doc.toDomain()
 	if err != nil {
 		return nil, newInternalError(op, fmt.Errorf("trans to domain fail: %w", err))
@@ -253,7 +387,7 @@ func (*trainRepoImpl) FindTrainDates(
 	if err != nil {
 		return nil, newInternalError(op, err)
 	}
-	docs, err := mgo.Find(ctx, modelTrainDates, q, core.DefaultLimit)
+	docs, err := mgo.Find(ctx, modelTrainDates, core.ApplyTeamScope(ctx, q), core.DefaultLimit)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, newNotFoundError(op, err)
@@ -326,6 +460,7 @@ func (*trainRepoImpl) DeductCapacity(
 	filter := bson.D{
 		{Key: "_id", Value: oid},
 		{Key: "available_capacity", Value: bson.M{"$gte": count}},
+		{Key: "status", Value: bson.M{"$ne": string(entity.TrainDateStatusCancelled)}},
 	}
 	update := bson.D{
 		{Key: "$inc", Value: bson.D{{Key: "available_capacity", Value: -count}}},
@@ -339,6 +474,7 @@ func (*trainRepoImpl) DeductCapacity(
 	if updatedCount == 0 {
 		return newNotFoundError(op, fmt.Errorf("no document updated"))
 	}
+	compensateCapacity(ctx, oid, count)
 	return nil
 }
 
@@ -366,6 +502,7 @@ func (*trainRepoImpl) AdminDeductCapacity(
 	if updatedCount == 0 {
 		return newNotFoundError(op, fmt.Errorf("no document updated"))
 	}
+	compensateCapacity(ctx, oid, count)
 	return nil
 }
 
@@ -392,9 +529,55 @@ func (*trainRepoImpl) IncreaseCapacity(
 	if updatedCount == 0 {
 		return newNotFoundError(op, fmt.Errorf("no document updated"))
 	}
+	compensateCapacity(ctx, oid, -count)
 	return nil
 }
 
+func (*trainRepoImpl) ResetAvailableCapacity(
+	ctx context.Context, trainingID string, from, to int,
+) repository.RepoError {
+	const op = "reset_available_capacity"
+	oid, err := bson.ObjectIDFromHex(trainingID)
+	if err != nil {
+		return newInvalidDocumentIDError(op, err)
+	}
+	// 以原本的剩餘名額比對，避免覆蓋對帳期間的預約異動
+	filter := bson.D{
+		{Key: "_id", Value: oid},
+		{Key: "available_capacity", Value: from},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "available_capacity", Value: to},
+			{Key: "updated_at", Value: time.Now()},
+		}},
+	}
+	doc, _ := newTrainDate()
+	updatedCount, err := mgo.UpdateOne(ctx, doc, filter, update)
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	if updatedCount == 0 {
+		return newConflictError(op, fmt.Errorf("available capacity changed"))
+	}
+	return nil
+}
+
+// compensateCapacity 不支援交易時登記名額的反向調整，不檢查剩餘名額以確保一定能回復
+func compensateCapacity(ctx context.Context, oid bson.ObjectID, count int) {
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		update := bson.D{
+			{Key: "$inc", Value: bson.D{{Key: "available_capacity", Value: count}}},
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+		}
+		doc, _ := newTrainDate()
+		if _, err := mgo.UpdateOne(ctx, doc, bson.D{{Key: "_id", Value: oid}}, update); err != nil {
+			return newInternalError("compensate_capacity", err)
+		}
+		return nil
+	})
+}
+
 func (*trainRepoImpl) FindPastTrainDateIDs(ctx context.Context, cutoff time.Time, limit uint16) ([]string, repository.RepoError) {
 	const op = "find_past_train_date_ids"
 	modelTrainDates, err := newTrainDate()
@@ -430,6 +613,7 @@ func (*trainRepoImpl) UpdateManyTrainDates(
 	if err != nil {
 		return newInternalError(op, fmt.Errorf("new bulk operation fail: %w", err))
 	}
+	ids := make([]bson.ObjectID, 0, len(trainings))
 	for _, training := range trainings {
 		modelTraining, err := newTrainDate(
 			withDomainTrainDate(training),
@@ -446,17 +630,67 @@ func (*trainRepoImpl) UpdateManyTrainDates(
 		bulkOpts = bulkOpts.UpdateById(modelTraining.ID, bson.D{
 			{Key: "$set", Value: updateField},
 		})
+		ids = append(ids, modelTraining.ID)
+	}
+	prev, err := findRawTrainDates(ctx, ids)
+	if err != nil {
+		return newInternalError(op, err)
 	}
 	_, err = bulkOpts.Execute(ctx)
 	if err != nil {
 		return newInternalError(op, fmt.Errorf("execute bulk operation fail: %w", err))
 	}
+	compensateTrainDates(ctx, op, ids, prev)
 	return nil
 }
 
+// findRawTrainDates 不支援交易時先取得寫入前的原始文件，其他情況不查詢
+func findRawTrainDates(ctx context.Context, ids []bson.ObjectID) (map[bson.ObjectID]bson.Raw, error) {
+	if !repository.InCompensation(ctx) {
+		return nil, nil
+	}
+	cursor, err := mgo.GetDatabase().Collection(TrainDateCollectionName).Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	prev := make(map[bson.ObjectID]bson.Raw, len(docs))
+	for _, doc := range docs {
+		if oid, ok := doc.Lookup("_id").ObjectIDOK(); ok {
+			prev[oid] = doc
+		}
+	}
+	return prev, nil
+}
+
+// compensateTrainDates 不支援交易時登記場次的還原，寫入前不存在的場次直接刪除
+func compensateTrainDates(ctx context.Context, op string, ids []bson.ObjectID, prev map[bson.ObjectID]bson.Raw) {
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		coll := mgo.GetDatabase().Collection(TrainDateCollectionName)
+		for _, id := range ids {
+			var err error
+			if doc, ok := prev[id]; ok {
+				_, err = coll.ReplaceOne(ctx, bson.M{"_id": id}, doc, options.Replace().SetUpsert(true))
+			} else {
+				_, err = coll.DeleteOne(ctx, bson.M{"_id": id})
+			}
+			if err != nil {
+				return newInternalError("compensate_"+op, err)
+			}
+		}
+		return nil
+	})
+}
+
 func getUpdateFieldFromModel(training *trainDate) bson.M {
 	updateField := bson.M{
 		"user_id":            training.UserID,
+		"series_id":          training.SeriesID,
+		"team_id":            training.TeamID,
+		"date":               training.Date,
 		"location":           training.Location,
 		"capacity":           training.Capacity,
 		"available_capacity": training.AvailableCapacity,
@@ -468,5 +702,14 @@ func getUpdateFieldFromModel(training *trainDate) bson.M {
 		"updated_at":         training.UpdatedAt,
 		"_migration":         training.Migration,
 	}
+	if training.BookingPolicy != nil {
+		updateField["booking_policy"] = training.BookingPolicy
+	}
+	if training.Cancellation != nil {
+		updateField["cancellation"] = training.Cancellation
+	}
+	if training.Reschedule != nil {
+		updateField["reschedule"] = training.Reschedule
+	}
 	return updateField
 }
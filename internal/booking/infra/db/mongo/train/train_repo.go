@@ -3,6 +3,7 @@ package train
 import (
 	"context"
 	"fmt"
+	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/infra/db/mongo/core"
 
@@ -35,6 +36,20 @@ func getQueryByFilterTrainDate(filter repository.FilterTrainDate) (bson.M, repos
 		q = bson.M{"_id": bson.M{"$in": oids}}
 	case repository.FilterTrainDateByEndTime:
 		q = bson.M{"end_date": bson.M{"$gt": f.Start}}
+	case repository.FilterTrainDateBySeriesID:
+		q = bson.M{"series_id": f.SeriesID, "start_date": bson.M{"$gte": f.After}}
+	case repository.FilterTrainDateByTeamID:
+		q = bson.M{"team_id": f.TeamID, "start_date": bson.M{"$gte": f.After}}
+	case repository.FilterTrainDateByCoachOverlap:
+		q = bson.M{
+			"user_id":    f.CoachID,
+			"start_date": bson.M{"$lt": f.Period.End()},
+			"end_date":   bson.M{"$gt": f.Period.Start()},
+		}
+	case repository.FilterTrainDateHasPendingCancelNotice:
+		q = bson.M{"status": string(entity.TrainDateStatusCancelled), "cancellation.notice_sent": false}
+	case repository.FilterTrainDateHasPendingRescheduleNotice:
+		q = bson.M{"status": bson.M{"$ne": string(entity.TrainDateStatusCancelled)}, "reschedule.notice_sent": false}
 	default:
 		// 處理未定義的 Filter 型別，避免靜默失敗
 		return nil, newInternalError("getQueryByFilterTrainDate",
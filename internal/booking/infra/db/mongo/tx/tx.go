@@ -0,0 +1,82 @@
+// MongoDB 多文件交易，單機部署不支援交易時改以補償動作回復
+package tx
+
+import (
+	"context"
+	"sync"
+
+	"seanAIgent/internal/booking/domain/repository"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"github.com/94peter/vulpes/log"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func NewUnitOfWork() repository.UnitOfWork {
+	return &unitOfWork{}
+}
+
+type unitOfWork struct {
+	mu        sync.Mutex
+	checked   bool
+	supported bool
+}
+
+func (u *unitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 已在交易或補償流程內時併入外層
+	if mongo.SessionFromContext(ctx) != nil || repository.InCompensation(ctx) {
+		return fn(ctx)
+	}
+	if !u.supportsTx(ctx) {
+		err := repository.RunWithCompensation(ctx, fn)
+		if err != nil {
+			log.Warnf("unit of work rolled back by compensation: %v", err)
+		}
+		return err
+	}
+
+	session, err := mgo.GetDatabase().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.WithoutCancel(ctx))
+	if err := session.StartTransaction(); err != nil {
+		return err
+	}
+	// 不自動重試：步驟會修改記憶體中的實體，重跑的結果不可預期，寫入衝突直接回報給呼叫端
+	sessCtx := mongo.NewSessionContext(ctx, session)
+	if err := fn(sessCtx); err != nil {
+		_ = session.AbortTransaction(context.WithoutCancel(sessCtx))
+		return err
+	}
+	return session.CommitTransaction(sessCtx)
+}
+
+// supportsTx 只有 replica set 或 mongos 支援交易，查詢失敗時先以補償處理並於下次重試
+func (u *unitOfWork) supportsTx(ctx context.Context) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.checked {
+		return u.supported
+	}
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := mgo.GetDatabase().RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false
+	}
+	u.checked = true
+	u.supported = isTxTopology(hello.SetName, hello.Msg)
+	if !u.supported {
+		log.Warn("mongodb standalone deployment, unit of work falls back to compensation")
+	}
+	return u.supported
+}
+
+func isTxTopology(setName, msg string) bool {
+	return setName != "" || msg == "isdbgrid"
+}
@@ -0,0 +1,156 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/appointment"
+	"seanAIgent/internal/booking/infra/db/mongo/credit"
+	"seanAIgent/internal/booking/infra/db/mongo/outbox"
+	"seanAIgent/internal/booking/infra/db/mongo/student"
+	"seanAIgent/internal/booking/infra/db/mongo/train"
+	"seanAIgent/internal/event"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestUnitOfWork_StandaloneCompensation(t *testing.T) {
+	drapAllDb, closeFunc, err := mgo.InitTestContainer(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFunc()
+	defer drapAllDb()
+
+	ctx := t.Context()
+	trainRepo := train.NewTrainRepository()
+	apptRepo := appointment.NewApptRepository()
+	ledgerRepo := credit.NewCreditLedgerRepository()
+	eventOutbox := outbox.NewEventOutbox()
+	studentRepo := student.NewStudentRepository()
+	// 單機部署不支援交易，所有寫入改以補償回復
+	uow := &unitOfWork{checked: true, supported: false}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	period, err := entity.NewTimeRange(start, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	trainID := bson.NewObjectID().Hex()
+	trainDate, err := entity.NewTrainDate(entity.WithBasicTrainDate(trainID, "coach1", "Gym A", 10, period))
+	require.NoError(t, err)
+	require.NoError(t, trainRepo.SaveTrainDate(ctx, trainDate))
+
+	user, err := entity.NewUser("user-tx", "User TX")
+	require.NoError(t, err)
+	ledger, err := entity.NewCreditLedger(entity.WithCreditLedgerUser(user))
+	require.NoError(t, err)
+	_, err = ledger.TopUp("p1", "", 10, start.AddDate(0, 3, 0), "10 堂")
+	require.NoError(t, err)
+	require.NoError(t, ledgerRepo.SaveCreditLedger(ctx, ledger))
+	saved, err := ledgerRepo.FindCreditLedgerByUserID(ctx, user.UserID())
+	require.NoError(t, err)
+
+	// 1. 既有預約，交易中改為教練停課
+	existing, err := entity.NewAppointment(
+		entity.WithCreateAppt(bson.NewObjectID().Hex(), trainID, user, "ChildA"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, apptRepo.SaveAppointment(ctx, existing))
+
+	// 2. 既有預約，交易中由家長取消並刪除
+	deleted, err := entity.NewAppointment(
+		entity.WithCreateAppt(bson.NewObjectID().Hex(), trainID, user, "ChildC"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, apptRepo.SaveAppointment(ctx, deleted))
+	require.NoError(t, deleted.CancelAsMistake(user.UserID(), entity.DefaultBookingPolicy()))
+
+	newStudent, err := entity.NewStudent(
+		entity.WithStudentID(bson.NewObjectID().Hex()),
+		entity.WithStudentParent(user),
+		entity.WithStudentName("ChildB"),
+	)
+	require.NoError(t, err)
+
+	created, err := entity.NewAppointment(
+		entity.WithCreateAppt(bson.NewObjectID().Hex(), trainID, user, "ChildB"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, saved.Reserve(created.ID(), created.ChildName(), start))
+
+	errLastStep := errors.New("last step fail")
+	err = uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := studentRepo.SaveStudent(ctx, newStudent); err != nil {
+			return err
+		}
+		if err := trainRepo.DeductCapacity(ctx, trainID, 1); err != nil {
+			return err
+		}
+		if err := apptRepo.SaveManyAppointments(ctx, []*entity.Appointment{created}); err != nil {
+			return err
+		}
+		cancelled, err := apptRepo.FindApptByID(ctx, existing.ID())
+		if err != nil {
+			return err
+		}
+		if err := cancelled.CancelByCoach(); err != nil {
+			return err
+		}
+		if err := apptRepo.UpdateManyAppts(ctx, []*entity.Appointment{cancelled}); err != nil {
+			return err
+		}
+		if err := apptRepo.DeleteAppointment(ctx, deleted); err != nil {
+			return err
+		}
+		if err := ledgerRepo.SaveCreditLedger(ctx, saved); err != nil {
+			return err
+		}
+		if err := eventOutbox.AddEvents(ctx, event.NewTypedEvent(bson.NewObjectID().Hex(),
+			domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+				BookingID:  created.ID(),
+				UserID:     user.UserID(),
+				TrainingID: trainID,
+				NewStatus:  created.Status().String(),
+				OccurredAt: time.Now(),
+			})); err != nil {
+			return err
+		}
+		return errLastStep
+	})
+	require.ErrorIs(t, err, errLastStep)
+
+	// 3. 所有寫入都已回復
+	found, err := trainRepo.FindTrainDateByID(ctx, trainID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, found.AvailableCapacity())
+
+	_, err = apptRepo.FindApptByID(ctx, created.ID())
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = studentRepo.FindStudentByID(ctx, newStudent.ID())
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	restored, err := apptRepo.FindApptByID(ctx, existing.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusConfirmed, restored.Status())
+
+	undeleted, err := apptRepo.FindApptByID(ctx, deleted.ID())
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusConfirmed, undeleted.Status())
+
+	reloaded, err := ledgerRepo.FindCreditLedgerByUserID(ctx, user.UserID())
+	require.NoError(t, err)
+	assert.Equal(t, saved.Version(), reloaded.Version())
+	assert.False(t, reloaded.HasAllocation(created.ID()))
+
+	backlog, err := event.NewMongoOutbox(mgo.GetDatabase()).Backlog(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, backlog.Pending)
+}
@@ -0,0 +1,247 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	waitlistCollectionName = "waitlist"
+	transformIDFailMsg     = "transform id fail: %w"
+)
+
+var waitlistCollection = mgo.NewCollectDef(waitlistCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "entries.user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "entries.status", Value: 1}, {Key: "entries.notified_at", Value: 1}},
+		},
+	}
+})
+
+type waitlistOpt func(*waitlist) error
+
+// 候補名單以課程場次 ID 作為 _id，一個場次只會有一份名單
+func withTrainingID(id string) waitlistOpt {
+	return func(w *waitlist) error {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		w.ID = oid
+		return nil
+	}
+}
+
+func withDomainWaitlist(wl *entity.Waitlist) waitlistOpt {
+	return func(w *waitlist) error {
+		if wl == nil {
+			return errors.New("entity is nil")
+		}
+		oid, err := bson.ObjectIDFromHex(wl.TrainingID())
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		w.ID = oid
+		w.Version = wl.Version()
+		w.UpdatedAt = wl.UpdatedAt()
+		w.Entries = make([]*waitlistEntry, 0, len(wl.Entries()))
+		for _, e := range wl.Entries() {
+			w.Entries = append(w.Entries, &waitlistEntry{
+				ID:         e.ID(),
+				UserID:     e.User().UserID(),
+				UserName:   e.User().UserName(),
+				ChildName:  e.ChildName(),
+				Status:     e.Status().String(),
+				ApptID:     e.ApptID(),
+				JoinedAt:   e.JoinedAt(),
+				PromotedAt: e.PromotedAt(),
+				NotifiedAt: e.NotifiedAt(),
+			})
+		}
+		w.Migration.Status = mgo.MigrateStatusSuccess
+		w.Migration.Version = 1
+		w.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelWaitlist(opts ...waitlistOpt) (*waitlist, error) {
+	w := &waitlist{
+		Index: waitlistCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(w); err != nil {
+			return nil, fmt.Errorf("new waitlist fail: %w", err)
+		}
+	}
+	return w, nil
+}
+
+type waitlist struct {
+	UpdatedAt time.Time `bson:"updated_at"`
+	mgo.Index `bson:"-"`
+	Migration mgo.MigrationInfo `bson:"_migration"`
+	Entries   []*waitlistEntry  `bson:"entries"`
+	Version   int               `bson:"version"`
+	ID        bson.ObjectID     `bson:"_id"`
+}
+
+type waitlistEntry struct {
+	JoinedAt   time.Time  `bson:"joined_at"`
+	PromotedAt *time.Time `bson:"promoted_at,omitempty"`
+	NotifiedAt *time.Time `bson:"notified_at,omitempty"`
+	ID         string     `bson:"id"`
+	UserID     string     `bson:"user_id"`
+	UserName   string     `bson:"user_name"`
+	ChildName  string     `bson:"child_name"`
+	Status     string     `bson:"status"`
+	ApptID     string     `bson:"appt_id,omitempty"`
+}
+
+func (w *waitlist) toDomain() (*entity.Waitlist, error) {
+	entries := make([]*entity.WaitlistEntry, 0, len(w.Entries))
+	for _, e := range w.Entries {
+		user, err := entity.NewUser(e.UserID, e.UserName)
+		if err != nil {
+			return nil, err
+		}
+		status, ok := entity.WaitlistEntryStatusFromString(e.Status)
+		if !ok {
+			return nil, fmt.Errorf("waitlist entry status is invalid: %s", e.Status)
+		}
+		entry, err := entity.NewWaitlistEntry(
+			entity.WithWaitlistEntryID(e.ID),
+			entity.WithWaitlistEntryUser(user),
+			entity.WithWaitlistEntryChildName(e.ChildName),
+			entity.WithWaitlistEntryJoinedAt(e.JoinedAt),
+			entity.WithWaitlistEntryStatus(status),
+			entity.WithWaitlistEntryPromoted(e.ApptID, e.PromotedAt),
+			entity.WithWaitlistEntryNotifiedAt(e.NotifiedAt),
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entity.NewWaitlist(
+		entity.WithWaitlistTrainingID(w.ID.Hex()),
+		entity.WithWaitlistEntries(entries),
+		entity.WithWaitlistVersion(w.Version),
+		entity.WithWaitlistUpdatedAt(w.UpdatedAt),
+	)
+}
+
+func (w *waitlist) GetId() any {
+	if w.ID.IsZero() {
+		return nil
+	}
+	return w.ID
+}
+
+func (w *waitlist) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	w.ID = oid
+}
+
+func (w *waitlist) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*waitlistRepoImpl) SaveWaitlist(
+	ctx context.Context, wl *entity.Waitlist,
+) repository.RepoError {
+	const op = "save_waitlist"
+	model, err := newModelWaitlist(withDomainWaitlist(wl))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	filter := bson.M{"_id": model.ID, "version": model.Version}
+	update := bson.M{
+		"$set": bson.M{
+			"entries":    model.Entries,
+			"updated_at": model.UpdatedAt,
+			"version":    model.Version + 1,
+			"_migration": model.Migration,
+		},
+	}
+	// 新名單 (version 0) 以 upsert 建立，其餘以版本號比對避免覆蓋他人的修改
+	opts := options.UpdateOne().SetUpsert(model.Version == 0)
+	result, err := mgo.GetDatabase().Collection(waitlistCollectionName).UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return newConflictError(op, err)
+		}
+		return newInternalError(op, err)
+	}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		return newConflictError(op, fmt.Errorf("waitlist %s version %d is outdated", wl.TrainingID(), wl.Version()))
+	}
+	return nil
+}
+
+func (*waitlistRepoImpl) FindWaitlistByTrainID(
+	ctx context.Context, trainingID string,
+) (*entity.Waitlist, repository.RepoError) {
+	const op = "find_waitlist_by_train_id"
+	model, err := newModelWaitlist(withTrainingID(trainingID))
+	if err != nil {
+		return nil, newInvalidDocumentIDError(op, err)
+	}
+	err = mgo.FindById(ctx, model)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	wl, err := model.toDomain()
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	return wl, nil
+}
+
+func (*waitlistRepoImpl) FindWaitlistsByFilter(
+	ctx context.Context, filter repository.FilterWaitlist,
+) ([]*entity.Waitlist, repository.RepoError) {
+	const op = "find_waitlists_by_filter"
+	q, repoErr := getQueryByFilterWaitlist(filter)
+	if repoErr != nil {
+		return nil, repoErr
+	}
+	model, _ := newModelWaitlist()
+	results, err := mgo.Find(ctx, model, q, core.DefaultLimit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	waitlists := make([]*entity.Waitlist, 0, len(results))
+	for _, result := range results {
+		wl, err := result.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		waitlists = append(waitlists, wl)
+	}
+	return waitlists, nil
+}
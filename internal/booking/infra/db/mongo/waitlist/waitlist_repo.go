@@ -0,0 +1,58 @@
+package waitlist
+
+import (
+	"errors"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+	"seanAIgent/internal/util"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func NewWaitlistRepository() repository.WaitlistRepository {
+	return &waitlistRepoImpl{}
+}
+
+type waitlistRepoImpl struct {
+}
+
+func getQueryByFilterWaitlist(filter repository.FilterWaitlist) (bson.M, repository.RepoError) {
+	var q bson.M
+	switch f := filter.(type) {
+	case repository.FilterWaitlistByUserID:
+		q = bson.M{"entries": bson.M{"$elemMatch": bson.M{
+			"user_id": f.UserID,
+			"status":  entity.WaitlistEntryStatusWaiting.String(),
+		}}}
+	case repository.FilterWaitlistHasPendingNotice:
+		q = bson.M{"entries": bson.M{"$elemMatch": bson.M{
+			"status":      entity.WaitlistEntryStatusPromoted.String(),
+			"notified_at": bson.M{"$exists": false},
+		}}}
+	default:
+		// 處理未定義的 Filter 型別，避免靜默失敗
+		filterName := util.GetTypeName(filter)
+		return nil, newInternalError(
+			"getQueryByFilterWaitlist", errors.New("Filter not implemented: "+filterName))
+	}
+	return q, nil
+}
+
+const repoName = "waitlist"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
+
+func newConflictError(op string, err error) repository.RepoError {
+	return core.NewConflictError(repoName, op, err)
+}
+
+func newInvalidDocumentIDError(op string, err error) repository.RepoError {
+	return core.NewInvalidDocumentIDError(repoName, op, err)
+}
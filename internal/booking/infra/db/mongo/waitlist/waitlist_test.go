@@ -0,0 +1,56 @@
+package waitlist
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestModelConversion(t *testing.T) {
+	trainID := bson.NewObjectID().Hex()
+	user, err := entity.NewUser("user-123", "Test User")
+	require.NoError(t, err)
+
+	wl, err := entity.NewWaitlist(
+		entity.WithWaitlistTrainingID(trainID),
+		entity.WithWaitlistVersion(3),
+	)
+	require.NoError(t, err)
+	_, err = wl.Join("e1", user, "ChildA")
+	require.NoError(t, err)
+	_, err = wl.Join("e2", user, "ChildB")
+	require.NoError(t, err)
+	require.NoError(t, wl.Promote("e1", "appt-1"))
+
+	model, err := newModelWaitlist(withDomainWaitlist(wl))
+	require.NoError(t, err)
+	assert.Equal(t, trainID, model.ID.Hex())
+	assert.Equal(t, 3, model.Version)
+	require.Len(t, model.Entries, 2)
+	assert.Equal(t, "PROMOTED", model.Entries[0].Status)
+	assert.Equal(t, "appt-1", model.Entries[0].ApptID)
+	assert.Equal(t, "WAITING", model.Entries[1].Status)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, trainID, back.TrainingID())
+	assert.Equal(t, 3, back.Version())
+	require.Len(t, back.Waiting(), 1)
+	assert.Equal(t, "e2", back.Waiting()[0].ID())
+	require.Len(t, back.PendingNotices(), 1)
+	assert.Equal(t, "appt-1", back.PendingNotices()[0].ApptID())
+}
+
+func TestGetQueryByFilterWaitlist(t *testing.T) {
+	q, err := getQueryByFilterWaitlist(nil)
+	assert.Nil(t, q)
+	assert.Error(t, err)
+
+	q, err = getQueryByFilterWaitlist(repository.NewFilterWaitlistByUserID("u1"))
+	require.Nil(t, err)
+	assert.Contains(t, q, "entries")
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	deliveryCollectionName = "webhook_deliveries"
+	deliveryTTL            = 30 * 24 * time.Hour
+)
+
+func init() {
+	mgo.RegisterIndex(mgo.NewCollectDef(deliveryCollectionName, func() []mongo.IndexModel {
+		return []mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "endpoint_id", Value: 1},
+					{Key: "event_id", Value: 1},
+					{Key: "delivered_at", Value: -1},
+				},
+			},
+			{
+				Keys: bson.D{
+					{Key: "endpoint_id", Value: 1},
+					{Key: "delivered_at", Value: -1},
+				},
+			},
+			{
+				Keys:    bson.D{{Key: "delivered_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(int32(deliveryTTL.Seconds())),
+			},
+		}
+	}))
+}
+
+type delivery struct {
+	DeliveredAt time.Time     `bson:"delivered_at"`
+	EndpointID  string        `bson:"endpoint_id"`
+	EventID     string        `bson:"event_id"`
+	Topic       string        `bson:"topic"`
+	Error       string        `bson:"error,omitempty"`
+	Attempt     int           `bson:"attempt"`
+	StatusCode  int           `bson:"status_code"`
+	DurationMS  int64         `bson:"duration_ms"`
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+}
+
+func newModelDelivery(d *entity.WebhookDelivery) *delivery {
+	return &delivery{
+		DeliveredAt: d.DeliveredAt,
+		EndpointID:  d.EndpointID,
+		EventID:     d.EventID,
+		Topic:       d.Topic,
+		Error:       d.Error,
+		Attempt:     d.Attempt,
+		StatusCode:  d.StatusCode,
+		DurationMS:  d.Duration.Milliseconds(),
+	}
+}
+
+func (d *delivery) toDomain() *entity.WebhookDelivery {
+	return &entity.WebhookDelivery{
+		DeliveredAt: d.DeliveredAt,
+		ID:          d.ID.Hex(),
+		EndpointID:  d.EndpointID,
+		EventID:     d.EventID,
+		Topic:       d.Topic,
+		Error:       d.Error,
+		Attempt:     d.Attempt,
+		StatusCode:  d.StatusCode,
+		Duration:    time.Duration(d.DurationMS) * time.Millisecond,
+	}
+}
+
+func (*webhookRepoImpl) AddWebhookDelivery(
+	ctx context.Context, d *entity.WebhookDelivery,
+) repository.RepoError {
+	const op = "add_webhook_delivery"
+	model := newModelDelivery(d)
+	res, err := mgo.GetDatabase().Collection(deliveryCollectionName).InsertOne(ctx, model)
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	if oid, ok := res.InsertedID.(bson.ObjectID); ok {
+		d.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (*webhookRepoImpl) FindWebhookDeliveries(
+	ctx context.Context, endpointID, eventID string, limit int,
+) ([]*entity.WebhookDelivery, repository.RepoError) {
+	const op = "find_webhook_deliveries"
+	q := bson.M{"endpoint_id": endpointID}
+	if eventID != "" {
+		q["event_id"] = eventID
+	}
+	if limit <= 0 {
+		limit = core.DefaultLimit
+	}
+	cursor, err := mgo.GetDatabase().Collection(deliveryCollectionName).Find(ctx, q,
+		options.Find().SetSort(bson.D{{Key: "delivered_at", Value: -1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	var docs []delivery
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, newInternalError(op, err)
+	}
+	deliveries := make([]*entity.WebhookDelivery, 0, len(docs))
+	for i := range docs {
+		deliveries = append(deliveries, docs[i].toDomain())
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,217 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+
+	"github.com/94peter/vulpes/db/mgo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	endpointCollectionName = "webhook_endpoints"
+	transformIDFailMsg     = "transform id fail: %w"
+)
+
+var endpointCollection = mgo.NewCollectDef(endpointCollectionName, func() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "topics", Value: 1}},
+		},
+	}
+})
+
+type endpointOpt func(*endpoint) error
+
+func withEndpointID(id string) endpointOpt {
+	return func(e *endpoint) error {
+		oid, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		e.ID = oid
+		return nil
+	}
+}
+
+func withDomainEndpoint(w *entity.WebhookEndpoint) endpointOpt {
+	return func(model *endpoint) error {
+		if w == nil {
+			return errors.New("entity is nil")
+		}
+		oid, err := bson.ObjectIDFromHex(w.ID())
+		if err != nil {
+			return fmt.Errorf(transformIDFailMsg, err)
+		}
+		model.ID = oid
+		model.Name = w.Name()
+		model.URL = w.URL()
+		model.Secret = w.Secret()
+		model.Topics = w.Topics()
+		model.Enabled = w.Enabled()
+		model.CreatedBy = w.CreatedBy()
+		model.CreatedAt = w.CreatedAt()
+		model.UpdatedAt = w.UpdatedAt()
+		model.Migration.Status = mgo.MigrateStatusSuccess
+		model.Migration.Version = 1
+		model.Migration.LastRun = time.Now()
+		return nil
+	}
+}
+
+func newModelEndpoint(opts ...endpointOpt) (*endpoint, error) {
+	e := &endpoint{
+		Index: endpointCollection,
+	}
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, fmt.Errorf("new webhook endpoint fail: %w", err)
+		}
+	}
+	return e, nil
+}
+
+type endpoint struct {
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+	mgo.Index `bson:"-"`
+	Migration mgo.MigrationInfo `bson:"_migration"`
+	Name      string            `bson:"name"`
+	URL       string            `bson:"url"`
+	Secret    string            `bson:"secret"`
+	CreatedBy string            `bson:"created_by"`
+	Topics    []string          `bson:"topics"`
+	ID        bson.ObjectID     `bson:"_id"`
+	Enabled   bool              `bson:"enabled"`
+}
+
+func (e *endpoint) toDomain() (*entity.WebhookEndpoint, error) {
+	return entity.NewWebhookEndpoint(
+		entity.WithWebhookEndpointID(e.ID.Hex()),
+		entity.WithWebhookEndpointName(e.Name),
+		entity.WithWebhookEndpointURL(e.URL),
+		entity.WithWebhookEndpointSecret(e.Secret),
+		entity.WithWebhookEndpointTopics(e.Topics...),
+		entity.WithWebhookEndpointEnabled(e.Enabled),
+		entity.WithWebhookEndpointCreatedBy(e.CreatedBy),
+		entity.WithWebhookEndpointCreatedAt(e.CreatedAt),
+		entity.WithWebhookEndpointUpdatedAt(e.UpdatedAt),
+	)
+}
+
+func (e *endpoint) GetId() any {
+	if e.ID.IsZero() {
+		return nil
+	}
+	return e.ID
+}
+
+func (e *endpoint) SetId(id any) {
+	oid, ok := id.(bson.ObjectID)
+	if !ok {
+		return
+	}
+	e.ID = oid
+}
+
+func (e *endpoint) Validate() error {
+	return nil
+}
+
+// repo impl
+func (*webhookRepoImpl) SaveWebhookEndpoint(
+	ctx context.Context, w *entity.WebhookEndpoint,
+) repository.RepoError {
+	const op = "save_webhook_endpoint"
+	model, err := newModelEndpoint(withDomainEndpoint(w))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"name":       model.Name,
+			"url":        model.URL,
+			"secret":     model.Secret,
+			"topics":     model.Topics,
+			"enabled":    model.Enabled,
+			"created_by": model.CreatedBy,
+			"created_at": model.CreatedAt,
+			"updated_at": model.UpdatedAt,
+			"_migration": model.Migration,
+		},
+	}
+	_, err = mgo.GetDatabase().Collection(endpointCollectionName).UpdateOne(
+		ctx, bson.M{"_id": model.ID}, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	return nil
+}
+
+func (*webhookRepoImpl) DeleteWebhookEndpoint(
+	ctx context.Context, w *entity.WebhookEndpoint,
+) repository.RepoError {
+	const op = "delete_webhook_endpoint"
+	oid, err := bson.ObjectIDFromHex(w.ID())
+	if err != nil {
+		return newInvalidDocumentIDError(op, err)
+	}
+	_, err = mgo.GetDatabase().Collection(endpointCollectionName).DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return newInternalError(op, err)
+	}
+	return nil
+}
+
+func (*webhookRepoImpl) FindWebhookEndpointByID(
+	ctx context.Context, id string,
+) (*entity.WebhookEndpoint, repository.RepoError) {
+	const op = "find_webhook_endpoint_by_id"
+	model, err := newModelEndpoint(withEndpointID(id))
+	if err != nil {
+		return nil, newInvalidDocumentIDError(op, err)
+	}
+	err = mgo.FindById(ctx, model)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	w, err := model.toDomain()
+	if err != nil {
+		return nil, newInternalError(op, err)
+	}
+	return w, nil
+}
+
+func (*webhookRepoImpl) FindWebhookEndpoints(
+	ctx context.Context,
+) ([]*entity.WebhookEndpoint, repository.RepoError) {
+	const op = "find_webhook_endpoints"
+	model, _ := newModelEndpoint()
+	results, err := mgo.Find(ctx, model, bson.M{}, core.DefaultLimit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, newNotFoundError(op, err)
+		}
+		return nil, newInternalError(op, err)
+	}
+	endpoints := make([]*entity.WebhookEndpoint, 0, len(results))
+	for _, result := range results {
+		w, err := result.toDomain()
+		if err != nil {
+			return nil, newInternalError(op, err)
+		}
+		endpoints = append(endpoints, w)
+	}
+	return endpoints, nil
+}
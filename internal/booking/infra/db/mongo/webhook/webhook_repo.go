@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/infra/db/mongo/core"
+)
+
+func NewWebhookRepository() repository.WebhookRepository {
+	return &webhookRepoImpl{}
+}
+
+type webhookRepoImpl struct {
+}
+
+const repoName = "webhook"
+
+func newInternalError(op string, err error) repository.RepoError {
+	return core.NewInternalError(repoName, op, err)
+}
+
+func newNotFoundError(op string, err error) repository.RepoError {
+	return core.NewNotFoundError(repoName, op, err)
+}
+
+func newInvalidDocumentIDError(op string, err error) repository.RepoError {
+	return core.NewInvalidDocumentIDError(repoName, op, err)
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestModelConversion(t *testing.T) {
+	id := bson.NewObjectID().Hex()
+	w, err := entity.NewWebhookEndpoint(
+		entity.WithWebhookEndpointID(id),
+		entity.WithWebhookEndpointName("會計系統"),
+		entity.WithWebhookEndpointURL("https://example.com/hook"),
+		entity.WithWebhookEndpointSecret("whsec_test"),
+		entity.WithWebhookEndpointTopics("a", "b"),
+		entity.WithWebhookEndpointEnabled(false),
+		entity.WithWebhookEndpointCreatedBy("admin-1"),
+	)
+	require.NoError(t, err)
+
+	model, err := newModelEndpoint(withDomainEndpoint(w))
+	require.NoError(t, err)
+	assert.Equal(t, id, model.ID.Hex())
+	assert.Equal(t, "whsec_test", model.Secret)
+	assert.False(t, model.Enabled)
+
+	back, err := model.toDomain()
+	require.NoError(t, err)
+	assert.Equal(t, id, back.ID())
+	assert.Equal(t, "https://example.com/hook", back.URL())
+	assert.Equal(t, "whsec_test", back.Secret())
+	assert.Equal(t, []string{"a", "b"}, back.Topics())
+	assert.False(t, back.Enabled(), "disabled endpoint stays disabled")
+	assert.Equal(t, "admin-1", back.CreatedBy())
+}
+
+func TestDeliveryConversion(t *testing.T) {
+	d := &entity.WebhookDelivery{
+		DeliveredAt: time.Now(),
+		EndpointID:  "w1",
+		EventID:     "e1",
+		Topic:       "a",
+		Attempt:     2,
+		StatusCode:  200,
+		Duration:    1500 * time.Millisecond,
+	}
+	model := newModelDelivery(d)
+	assert.Equal(t, int64(1500), model.DurationMS)
+
+	back := model.toDomain()
+	assert.Equal(t, d.Duration, back.Duration)
+	assert.Equal(t, 2, back.Attempt)
+	assert.True(t, back.Succeeded())
+}
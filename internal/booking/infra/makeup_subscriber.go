@@ -0,0 +1,47 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	writeMakeUp "seanAIgent/internal/booking/usecase/makeup/write"
+	"seanAIgent/internal/event"
+	"time"
+
+	"github.com/94peter/vulpes/log"
+)
+
+// NewMakeUpCreditSubscriber 依預約狀態變更發放、收回或釋放補課資格
+func NewMakeUpCreditSubscriber(syncUC writeMakeUp.SyncMakeUpCreditUseCase) []event.Subscriber {
+	handler := func(ctx context.Context, e event.Event, p domain.AppointmentStatusChanged) error {
+		bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		synced, err := syncUC.Execute(bgCtx, writeMakeUp.ReqSyncMakeUpCredit{
+			OccurredAt: p.OccurredAt,
+			BookingID:  p.BookingID,
+			UserID:     p.UserID,
+			TrainingID: p.TrainingID,
+			OldStatus:  p.OldStatus,
+			NewStatus:  p.NewStatus,
+		})
+		if err != nil {
+			// 後台恢復出席時補課已預約，保留補課由管理員另行處理
+			if errors.Is(err, entity.ErrMakeUpCreditRedeemed) {
+				log.Warnf("MakeUpCreditSubscriber: make-up already booked for leave %s", p.BookingID)
+				return nil
+			}
+			return fmt.Errorf("MakeUpCreditSubscriber: sync fail (booking: %s): %w", p.BookingID, err)
+		}
+		if synced {
+			log.Infof("MakeUpCreditSubscriber: synced %s for booking %s", p.NewStatus, p.BookingID)
+		}
+		return nil
+	}
+
+	return []event.Subscriber{
+		event.NewTypedSubscriber("make_up_credit_status_change", domain.TopicAppointmentStatusChanged, handler),
+	}
+}
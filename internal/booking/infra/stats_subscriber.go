@@ -45,8 +45,22 @@ func NewUserMonthlyStatsSubscriber(
 	}
 
 	return []event.Subscriber{
-		event.NewTypedSubscriber("user_monthly_stats_status_change", domain.TopicAppointmentStatusChanged, statusChangeHandler),
-		event.NewTypedSubscriber("user_monthly_stats_refresh", domain.TopicUserStatsRefreshRequested, refreshHandler),
+		event.WithWorkerPool(
+			event.NewTypedSubscriber("user_monthly_stats_status_change", domain.TopicAppointmentStatusChanged, statusChangeHandler),
+			statsWorkerPool(event.KeyBy(func(p domain.AppointmentStatusChanged) string { return p.UserID }))),
+		event.WithWorkerPool(
+			event.NewTypedSubscriber("user_monthly_stats_refresh", domain.TopicUserStatsRefreshRequested, refreshHandler),
+			statsWorkerPool(event.KeyBy(func(p domain.UserStatsRefreshRequested) string { return p.UserID }))),
+	}
+}
+
+// statsWorkerPool 同一用戶的統計依序計算；重算結果相同，批次更新塞滿佇列時改由追趕補算
+func statsWorkerPool(key func(event.Event) string) event.WorkerPoolConfig {
+	return event.WorkerPoolConfig{
+		Workers:   4,
+		QueueSize: 128,
+		OnFull:    event.CatchUpWhenFull,
+		Key:       key,
 	}
 }
 
@@ -0,0 +1,52 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	writeWaitlist "seanAIgent/internal/booking/usecase/waitlist/write"
+	"seanAIgent/internal/event"
+	"time"
+
+	"github.com/94peter/vulpes/log"
+)
+
+// NewWaitlistPromotionSubscriber 名額釋出 (取消預約、請假) 或有人加入候補時，嘗試遞補候補名單
+func NewWaitlistPromotionSubscriber(promoteUC writeWaitlist.PromoteWaitlistUseCase) []event.Subscriber {
+	promote := func(trainingID string) error {
+		bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		appts, err := promoteUC.Execute(bgCtx, writeWaitlist.ReqPromoteWaitlist{TrainDateID: trainingID})
+		if err != nil {
+			return fmt.Errorf("WaitlistPromotionSubscriber: promote fail (ID: %s): %w", trainingID, err)
+		}
+		if len(appts) > 0 {
+			log.Infof("WaitlistPromotionSubscriber: promoted %d appointments for training %s", len(appts), trainingID)
+		}
+		return nil
+	}
+
+	statusChangeHandler := func(ctx context.Context, e event.Event, p domain.AppointmentStatusChanged) error {
+		if p.TrainingID == "" {
+			return nil
+		}
+		if p.NewStatus != entity.StatusCancelled.String() && p.NewStatus != entity.StatusCancelledLeave.String() {
+			return nil
+		}
+		return promote(p.TrainingID)
+	}
+
+	joinedHandler := func(ctx context.Context, e event.Event, p domain.WaitlistJoined) error {
+		if p.TrainingID == "" {
+			return nil
+		}
+		return promote(p.TrainingID)
+	}
+
+	return []event.Subscriber{
+		event.NewTypedSubscriber("waitlist_promotion", domain.TopicAppointmentStatusChanged, statusChangeHandler),
+		event.NewTypedSubscriber("waitlist_promotion_on_join", domain.TopicWaitlistJoined, joinedHandler),
+	}
+}
@@ -0,0 +1,178 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/event"
+	"time"
+
+	"github.com/94peter/vulpes/log"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// webhookRetryPolicy 對方服務暫時無法連線時，約 8 分鐘內重試，之後進入 dead letter
+var webhookRetryPolicy = event.RetryPolicy{
+	MaxAttempts:    6,
+	InitialBackoff: 5 * time.Second,
+	MaxBackoff:     5 * time.Minute,
+	Multiplier:     3,
+	Jitter:         0.2,
+}
+
+// webhookWorkerPool 外部服務回應慢時不阻塞其他訂閱者，改由追趕補送
+var webhookWorkerPool = event.WorkerPoolConfig{
+	Workers:   2,
+	QueueSize: 64,
+	OnFull:    event.CatchUpWhenFull,
+}
+
+// webhookBody 送出的 JSON，data 為目前版本的 Payload
+type webhookBody struct {
+	ID         string          `json:"id"`
+	Topic      string          `json:"topic"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type webhookSender struct {
+	client *http.Client
+}
+
+func NewWebhookSender() service.WebhookSender {
+	return &webhookSender{client: &http.Client{Timeout: webhookTimeout}}
+}
+
+func (s *webhookSender) Send(
+	ctx context.Context, endpoint *entity.WebhookEndpoint, e event.Event, attempt int,
+) *entity.WebhookDelivery {
+	d := &entity.WebhookDelivery{
+		DeliveredAt: time.Now(),
+		EndpointID:  endpoint.ID(),
+		EventID:     e.ID(),
+		Topic:       e.Topic(),
+		Attempt:     attempt,
+	}
+	req, err := s.newRequest(ctx, endpoint, e, d.DeliveredAt)
+	if err != nil {
+		d.Error = err.Error()
+		return d
+	}
+	resp, err := s.client.Do(req)
+	d.Duration = time.Since(d.DeliveredAt)
+	if err != nil {
+		d.Error = err.Error()
+		return d
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	d.StatusCode = resp.StatusCode
+	if !d.Succeeded() {
+		d.Error = resp.Status
+	}
+	return d
+}
+
+func (s *webhookSender) newRequest(
+	ctx context.Context, endpoint *entity.WebhookEndpoint, e event.Event, now time.Time,
+) (*http.Request, error) {
+	data, err := event.Schemas.Upcast(e.Topic(), e.Version(), e.Data())
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(webhookBody{
+		ID:         e.ID(),
+		Topic:      e.Topic(),
+		Version:    event.Schemas.CurrentVersion(e.Topic()),
+		OccurredAt: e.OccurredAt(),
+		Data:       data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	ts := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SeanAIgent-Webhook/1.0")
+	req.Header.Set(service.WebhookHeaderEventID, e.ID())
+	req.Header.Set(service.WebhookHeaderTopic, e.Topic())
+	req.Header.Set(service.WebhookHeaderTimestamp, fmt.Sprint(ts))
+	req.Header.Set(service.WebhookHeaderSignature, service.WebhookSignature(endpoint.Secret(), ts, body))
+	return req, nil
+}
+
+// NewWebhookSubscribers 每個可轉送的主題一個訂閱者，端點於執行期間由後台新增，每次處理時重新讀取
+func NewWebhookSubscribers(repo repository.WebhookRepository, sender service.WebhookSender) []event.Subscriber {
+	subs := make([]event.Subscriber, 0, len(domain.WebhookTopics()))
+	for _, topic := range domain.WebhookTopics() {
+		subs = append(subs, event.WithWorkerPool(
+			event.WithRetryPolicy(&webhookSubscriber{topic: topic, repo: repo, sender: sender}, webhookRetryPolicy),
+			webhookWorkerPool))
+	}
+	return subs
+}
+
+type webhookSubscriber struct {
+	repo   repository.WebhookRepository
+	sender service.WebhookSender
+	topic  string
+}
+
+func (s *webhookSubscriber) ID() string    { return "webhook:" + s.topic }
+func (s *webhookSubscriber) Topic() string { return s.topic }
+
+// Handle 重試時略過已成功送達的端點，只補送失敗的端點
+func (s *webhookSubscriber) Handle(ctx context.Context, e event.Event) error {
+	endpoints, err := s.repo.FindWebhookEndpoints(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("WebhookSubscriber: find endpoints fail: %w", err)
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		if !endpoint.Receives(e.Topic()) {
+			continue
+		}
+		past, err := s.repo.FindWebhookDeliveries(ctx, endpoint.ID(), e.ID(), 0)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("%s: find deliveries fail: %w", endpoint.Name(), err))
+			continue
+		}
+		if delivered(past) {
+			continue
+		}
+		d := s.sender.Send(ctx, endpoint, e, len(past)+1)
+		if err := s.repo.AddWebhookDelivery(ctx, d); err != nil {
+			log.Warnf("WebhookSubscriber: save delivery of %s to %s fail: %v", e.ID(), endpoint.Name(), err)
+		}
+		if !d.Succeeded() {
+			errs = append(errs, fmt.Errorf("%s: %s", endpoint.Name(), d.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func delivered(deliveries []*entity.WebhookDelivery) bool {
+	for _, d := range deliveries {
+		if d.Succeeded() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// 教練審核請假
+package linemsg
+
+import (
+	"context"
+	"fmt"
+	"seanAIgent/internal/booking/domain/entity"
+	writeAppt "seanAIgent/internal/booking/usecase/appointment/write"
+	"seanAIgent/internal/booking/usecase/core"
+	readRole "seanAIgent/internal/booking/usecase/role/read"
+	"strings"
+
+	"github.com/94peter/botreplyer/provider/line/reply/textreply"
+	"github.com/94peter/botreplyer/session"
+	"github.com/gin-contrib/sessions"
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+// 請假申請推播的快速回覆會送出「關鍵字 預約ID」，教練也可以自行在後面加上備註
+const (
+	LeaveApproveKeyword = "核准請假"
+	LeaveRejectKeyword  = "駁回請假"
+)
+
+// LeaveReviewCommand 產生快速回覆送出的審核指令
+func LeaveReviewCommand(approve bool, bookingID string) string {
+	if approve {
+		return LeaveApproveKeyword + " " + bookingID
+	}
+	return LeaveRejectKeyword + " " + bookingID
+}
+
+func NewLeaveReviewReply(
+	resolveActorUC readRole.ResolveActorUseCase,
+	approveLeaveUC writeAppt.ApproveLeaveUseCase,
+	rejectLeaveUC writeAppt.RejectLeaveUseCase,
+) textreply.LineKeywordReply {
+	return &leaveReviewReply{
+		resolveActorUC: resolveActorUC,
+		approveLeaveUC: approveLeaveUC,
+		rejectLeaveUC:  rejectLeaveUC,
+	}
+}
+
+type leaveReviewReply struct {
+	resolveActorUC readRole.ResolveActorUseCase
+	approveLeaveUC writeAppt.ApproveLeaveUseCase
+	rejectLeaveUC  writeAppt.RejectLeaveUseCase
+}
+
+func (r *leaveReviewReply) MessageTextReply(
+	ctx context.Context, typ linebot.EventSourceType,
+	groupID, userID, msg string, mysession sessions.Session,
+) ([]linebot.SendingMessage, textreply.DelayedMessage, error) {
+	fields := strings.Fields(msg)
+	if len(fields) < 2 || (fields[0] != LeaveApproveKeyword && fields[0] != LeaveRejectKeyword) {
+		return nil, nil, nil
+	}
+	// 審核只在私訊中進行，避免在群組公開請假內容
+	if typ != linebot.EventSourceTypeUser {
+		return nil, nil, nil
+	}
+	approve := fields[0] == LeaveApproveKeyword
+	bookingID := fields[1]
+	comment := strings.Join(fields[2:], " ")
+
+	// 以教練身分執行，權限與團隊教練的檢查交由 use case
+	actor, ucErr := r.resolveActorUC.Execute(ctx, readRole.ReqResolveActor{
+		UserID:        userID,
+		IsLineAdmin:   session.IsAdmin(mysession),
+		WithTeamRoles: true,
+	})
+	if ucErr != nil {
+		return nil, nil, ucErr
+	}
+	ctx = core.WithActor(ctx, actor)
+
+	req := writeAppt.ReqReviewLeave{
+		BookingID:  bookingID,
+		ReviewerID: userID,
+		Comment:    comment,
+	}
+	var appt *entity.Appointment
+	if approve {
+		appt, ucErr = r.approveLeaveUC.Execute(ctx, req)
+	} else {
+		appt, ucErr = r.rejectLeaveUC.Execute(ctx, req)
+	}
+	if ucErr != nil {
+		return []linebot.SendingMessage{
+			linebot.NewTextMessage("審核失敗：" + ucErr.Message()),
+		}, nil, nil
+	}
+
+	result := "已駁回"
+	if approve {
+		result = "已核准"
+	}
+	text := fmt.Sprintf("%s %s 的請假，會通知家長審核結果。", result, appt.ChildName())
+	if comment != "" {
+		text += "\n📝 備註：" + appt.LeaveInfo().ReviewComment()
+	}
+	return []linebot.SendingMessage{linebot.NewTextMessage(text)}, nil, nil
+}
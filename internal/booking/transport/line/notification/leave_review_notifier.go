@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/transport/line/linemsg"
+
+	"github.com/94peter/botreplyer/provider/line/notify"
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+type LeaveReviewNotifier interface {
+	notify.LineNotify
+}
+
+type leaveReviewRepo interface {
+	repository.AppointmentRepository
+	repository.TrainRepository
+	repository.TeamRepository
+}
+
+func NewLeaveReviewNotifier(repo leaveReviewRepo) LeaveReviewNotifier {
+	return &leaveReview{repo: repo}
+}
+
+// 請假審核推播：待審核的申請通知團隊教練，審核後通知家長結果
+type leaveReview struct {
+	repo leaveReviewRepo
+}
+
+func (n *leaveReview) GetNotification(ctx context.Context) []*notify.NotificationContent {
+	appts, err := n.repo.FindApptsByFilter(ctx, repository.NewFilterApptHasPendingLeaveNotice())
+	if err != nil {
+		return nil
+	}
+
+	var notifications []*notify.NotificationContent
+	for _, appt := range appts {
+		if !appt.HasPendingLeaveNotice() {
+			continue
+		}
+		trainDate, err := n.repo.FindTrainDateByID(ctx, appt.TrainingID())
+		if err != nil {
+			continue
+		}
+		var coaches []entity.User
+		if appt.HasPendingLeave() {
+			var findErr error
+			coaches, findErr = n.findCoaches(ctx, trainDate)
+			if findErr != nil {
+				continue
+			}
+		}
+
+		// 先記錄已通知再送出，避免重複推播
+		appt.MarkLeaveNotified()
+		if err := n.repo.UpdateAppt(ctx, appt); err != nil {
+			continue
+		}
+
+		start := trainDate.StartDateWithTimeZone()
+		end := trainDate.EndDateWithTimeZone()
+		session := fmt.Sprintf("📅 %s %s-%s\n📍 %s",
+			start.Format("01/02"), start.Format("15:04"), end.Format("15:04"), trainDate.Location())
+		leave := appt.LeaveInfo()
+
+		if appt.HasPendingLeave() {
+			msgText := fmt.Sprintf("📝 請假申請\n\n%s（家長 %s）申請請假\n\n%s\n💬 原因：%s\n\n核准後才會釋出名額，也可以在點名頁審核。",
+				appt.ChildName(), appt.User().UserName(), session, leave.Reason())
+			quickReply := linebot.NewQuickReplyItems(
+				linebot.NewQuickReplyButton("", linebot.NewMessageAction("核准", linemsg.LeaveReviewCommand(true, appt.ID()))),
+				linebot.NewQuickReplyButton("", linebot.NewMessageAction("駁回", linemsg.LeaveReviewCommand(false, appt.ID()))),
+			)
+			for _, coach := range coaches {
+				notifications = append(notifications, &notify.NotificationContent{
+					UserIDs: coach.UserID(),
+					Message: []linebot.SendingMessage{linebot.NewTextMessage(msgText).WithQuickReplies(quickReply)},
+				})
+			}
+			continue
+		}
+
+		result := "教練已核准，名額已釋出，不會列入缺席紀錄。"
+		if leave.Status() == entity.LeaveStatusRejected {
+			result = "教練未核准這次請假，預約維持不變，請記得準時出席唷！"
+		}
+		msgText := fmt.Sprintf("嗨 %s 👋\n\n%s 的請假申請審核結果：\n\n%s\n\n%s",
+			appt.User().UserName(), appt.ChildName(), session, result)
+		if leave.ReviewComment() != "" {
+			msgText += "\n💬 教練備註：" + leave.ReviewComment()
+		}
+		notifications = append(notifications, &notify.NotificationContent{
+			UserIDs: appt.User().UserID(),
+			Message: []linebot.SendingMessage{linebot.NewTextMessage(msgText)},
+		})
+	}
+	return notifications
+}
+
+// findCoaches 團隊的總教練與助理教練，團隊已刪除時沒有人可通知
+func (n *leaveReview) findCoaches(ctx context.Context, trainDate *entity.TrainDate) ([]entity.User, error) {
+	if trainDate.TeamID() == "" {
+		return nil, nil
+	}
+	team, err := n.repo.FindTeamByID(ctx, trainDate.TeamID())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return append([]entity.User{team.HeadCoach()}, team.Assistants()...), nil
+}
@@ -0,0 +1,94 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"strings"
+
+	"github.com/94peter/botreplyer/provider/line/notify"
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+type TrainDateCancelledNotifier interface {
+	notify.LineNotify
+}
+
+type trainDateCancelledRepo interface {
+	repository.TrainRepository
+	repository.AppointmentRepository
+}
+
+// NewTrainDateCancelledNotifier groupID 為綁定的 LINE 群組，空字串代表只通知家長
+func NewTrainDateCancelledNotifier(repo trainDateCancelledRepo, groupID string) TrainDateCancelledNotifier {
+	return &trainDateCancelled{repo: repo, groupID: groupID}
+}
+
+// 教練停課推播通知
+type trainDateCancelled struct {
+	repo    trainDateCancelledRepo
+	groupID string
+}
+
+func (n *trainDateCancelled) GetNotification(ctx context.Context) []*notify.NotificationContent {
+	trainDates, err := n.repo.FindTrainDates(ctx, repository.NewFilterTrainDateHasPendingCancelNotice())
+	if err != nil {
+		return nil
+	}
+
+	var notifications []*notify.NotificationContent
+	for _, trainDate := range trainDates {
+		appts, err := n.repo.FindApptsByFilter(ctx, repository.NewFilterApptByTrainID(trainDate.ID()))
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+
+		// 先記錄已通知再送出，避免重複推播
+		trainDate.MarkCancelNoticeSent()
+		if err := n.repo.UpdateManyTrainDates(ctx, []*entity.TrainDate{trainDate}); err != nil {
+			continue
+		}
+
+		start := trainDate.StartDateWithTimeZone()
+		end := trainDate.EndDateWithTimeZone()
+		session := fmt.Sprintf("📅 %s %s-%s\n📍 %s",
+			start.Format("01/02"), start.Format("15:04"), end.Format("15:04"), trainDate.Location())
+		reason := trainDate.Cancellation().Reason()
+
+		// 同一位家長的多位學員合併為一則訊息
+		var userIDs []string
+		users := make(map[string]entity.User)
+		children := make(map[string][]string)
+		for _, appt := range appts {
+			if !appt.IsCancelledByCoach() {
+				continue
+			}
+			userID := appt.User().UserID()
+			if _, ok := users[userID]; !ok {
+				userIDs = append(userIDs, userID)
+				users[userID] = appt.User()
+			}
+			children[userID] = append(children[userID], appt.ChildName())
+		}
+		for _, userID := range userIDs {
+			msgText := fmt.Sprintf("嗨 %s 👋\n\n很抱歉，%s 預約的課程停課了 🙏\n\n%s\n📝 原因：%s\n\n課程包堂數已自動退還，不列入請假或缺席紀錄。",
+				users[userID].UserName(), strings.Join(children[userID], "、"), session, reason)
+			notifications = append(notifications, &notify.NotificationContent{
+				UserIDs: userID,
+				Message: []linebot.SendingMessage{linebot.NewTextMessage(msgText)},
+			})
+		}
+
+		if n.groupID != "" {
+			msgText := fmt.Sprintf("📢 停課通知\n\n%s\n📝 原因：%s\n\n已通知 %d 位預約的家長。",
+				session, reason, len(userIDs))
+			notifications = append(notifications, &notify.NotificationContent{
+				UserIDs: n.groupID,
+				Message: []linebot.SendingMessage{linebot.NewTextMessage(msgText)},
+			})
+		}
+	}
+	return notifications
+}
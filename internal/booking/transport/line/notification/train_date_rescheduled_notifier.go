@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/util/timeutil"
+	"strings"
+
+	"github.com/94peter/botreplyer/provider/line/notify"
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+type TrainDateRescheduledNotifier interface {
+	notify.LineNotify
+}
+
+type trainDateRescheduledRepo interface {
+	repository.TrainRepository
+	repository.AppointmentRepository
+}
+
+func NewTrainDateRescheduledNotifier(repo trainDateRescheduledRepo) TrainDateRescheduledNotifier {
+	return &trainDateRescheduled{repo: repo}
+}
+
+// 場次改期推播通知
+type trainDateRescheduled struct {
+	repo trainDateRescheduledRepo
+}
+
+func (n *trainDateRescheduled) GetNotification(ctx context.Context) []*notify.NotificationContent {
+	trainDates, err := n.repo.FindTrainDates(ctx, repository.NewFilterTrainDateHasPendingRescheduleNotice())
+	if err != nil {
+		return nil
+	}
+
+	var notifications []*notify.NotificationContent
+	for _, trainDate := range trainDates {
+		appts, err := n.repo.FindApptsByFilter(ctx, repository.NewFilterApptByTrainID(trainDate.ID()))
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+
+		// 先記錄已通知再送出，避免重複推播
+		reschedule := trainDate.LastReschedule()
+		trainDate.MarkRescheduleNoticeSent()
+		if err := n.repo.UpdateManyTrainDates(ctx, []*entity.TrainDate{trainDate}); err != nil {
+			continue
+		}
+
+		prevStart := timeutil.ToLocation(reschedule.PreviousPeriod().Start(), trainDate.Timezone())
+		prevEnd := timeutil.ToLocation(reschedule.PreviousPeriod().End(), trainDate.Timezone())
+		start := trainDate.StartDateWithTimeZone()
+		end := trainDate.EndDateWithTimeZone()
+
+		// 同一位家長的多位學員合併為一則訊息，改期後才預約的家長不需通知
+		var userIDs []string
+		users := make(map[string]entity.User)
+		children := make(map[string][]string)
+		for _, appt := range appts {
+			if appt.Status() == entity.StatusCancelled || !appt.CreatedAt().Before(reschedule.RescheduledAt()) {
+				continue
+			}
+			userID := appt.User().UserID()
+			if _, ok := users[userID]; !ok {
+				userIDs = append(userIDs, userID)
+				users[userID] = appt.User()
+			}
+			children[userID] = append(children[userID], appt.ChildName())
+		}
+		for _, userID := range userIDs {
+			msgText := fmt.Sprintf("嗨 %s 👋\n\n%s 預約的課程時間或地點有異動：\n\n原本：%s %s-%s @%s\n👉 改為：%s %s-%s @%s\n\n預約會自動保留；如果無法配合新的時間，可到預約頁面免費取消唷！",
+				users[userID].UserName(), strings.Join(children[userID], "、"),
+				prevStart.Format("01/02"), prevStart.Format("15:04"), prevEnd.Format("15:04"), reschedule.PreviousLocation(),
+				start.Format("01/02"), start.Format("15:04"), end.Format("15:04"), trainDate.Location())
+			notifications = append(notifications, &notify.NotificationContent{
+				UserIDs: userID,
+				Message: []linebot.SendingMessage{linebot.NewTextMessage(msgText)},
+			})
+		}
+	}
+	return notifications
+}
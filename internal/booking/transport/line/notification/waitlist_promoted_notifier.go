@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"seanAIgent/internal/booking/domain/repository"
+
+	"github.com/94peter/botreplyer/provider/line/notify"
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+type WaitlistPromotedNotifier interface {
+	notify.LineNotify
+}
+
+type waitlistPromotedRepo interface {
+	repository.WaitlistRepository
+	repository.TrainRepository
+}
+
+func NewWaitlistPromotedNotifier(repo waitlistPromotedRepo) WaitlistPromotedNotifier {
+	return &waitlistPromoted{repo: repo}
+}
+
+// 候補遞補成功推播通知
+type waitlistPromoted struct {
+	repo waitlistPromotedRepo
+}
+
+func (n *waitlistPromoted) GetNotification(ctx context.Context) []*notify.NotificationContent {
+	waitlists, err := n.repo.FindWaitlistsByFilter(ctx, repository.NewFilterWaitlistHasPendingNotice())
+	if err != nil {
+		return nil
+	}
+
+	var notifications []*notify.NotificationContent
+	for _, wl := range waitlists {
+		entries := wl.PendingNotices()
+		if len(entries) == 0 {
+			continue
+		}
+		trainDate, err := n.repo.FindTrainDateByID(ctx, wl.TrainingID())
+		if err != nil {
+			continue
+		}
+		start := trainDate.StartDateWithTimeZone()
+		end := trainDate.EndDateWithTimeZone()
+
+		// 先記錄已通知再送出，避免重複推播
+		for _, entry := range entries {
+			_ = wl.MarkNotified(entry.ID())
+		}
+		if err := n.repo.SaveWaitlist(ctx, wl); err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			msgText := fmt.Sprintf("嗨 %s 👋，好消息！\n\n%s 候補的課程有名額釋出，已經幫你遞補成功囉 🎉\n\n📅 %s %s-%s\n📍 %s\n\n如果無法出席，記得到預約頁面取消或請假唷！",
+				entry.User().UserName(), entry.ChildName(),
+				start.Format("01/02"), start.Format("15:04"), end.Format("15:04"),
+				trainDate.Location())
+
+			notifications = append(notifications, &notify.NotificationContent{
+				UserIDs: entry.User().UserID(),
+				Message: []linebot.SendingMessage{linebot.NewTextMessage(msgText)},
+			})
+		}
+	}
+	return notifications
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"seanAIgent/internal/booking/domain/entity"
 	writeTrain "seanAIgent/internal/booking/usecase/traindate/write"
 	"seanAIgent/internal/util/timeutil"
 
@@ -54,6 +55,27 @@ func ProvideCreateTrainingCoursesTool() server.ServerTool {
 					"required": []string{"date", "start_time", "end_time"},
 				}),
 			),
+			mcp.WithObject("booking_policy",
+				mcp.Description("Optional booking rules in minutes for these sessions; omit to use the system default"),
+				mcp.Properties(map[string]any{
+					"cancel_window_minutes": map[string]any{
+						"type":        "integer",
+						"description": "Minutes after booking during which the user may cancel",
+					},
+					"leave_cutoff_minutes": map[string]any{
+						"type":        "integer",
+						"description": "Leave requests close this many minutes before the session starts",
+					},
+					"check_in_open_minutes": map[string]any{
+						"type":        "integer",
+						"description": "Check-in opens this many minutes before the session starts",
+					},
+					"attendance_amend_minutes": map[string]any{
+						"type":        "integer",
+						"description": "Attendance can be amended until this many minutes after the session starts",
+					},
+				}),
+			),
 		),
 		Handler: mcp.NewTypedToolHandler(createTrainingCoursesHandler),
 	}
@@ -65,6 +87,32 @@ type createTrainingCoursesArgs struct {
 	TimeZone string      `json:"time_zone"`
 	Schedule []*schedule `json:"schedule"`
 	Capacity int         `json:"capacity"`
+
+	BookingPolicy *bookingPolicyArgs `json:"booking_policy"`
+}
+
+type bookingPolicyArgs struct {
+	CancelWindowMinutes    int `json:"cancel_window_minutes"`
+	LeaveCutoffMinutes     int `json:"leave_cutoff_minutes"`
+	CheckInOpenMinutes     int `json:"check_in_open_minutes"`
+	AttendanceAmendMinutes int `json:"attendance_amend_minutes"`
+}
+
+// toDomain 未提供時回傳 nil，由 use case 套用系統預設
+func (a *bookingPolicyArgs) toDomain() (*entity.BookingPolicy, error) {
+	if a == nil {
+		return nil, nil
+	}
+	policy, err := entity.NewBookingPolicy(
+		time.Duration(a.CancelWindowMinutes)*time.Minute,
+		time.Duration(a.LeaveCutoffMinutes)*time.Minute,
+		time.Duration(a.CheckInOpenMinutes)*time.Minute,
+		time.Duration(a.AttendanceAmendMinutes)*time.Minute,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
 }
 
 type schedule struct {
@@ -83,7 +131,10 @@ func (s *schedule) GetEndTime(timeZone string) (time.Time, error) {
 
 func createTrainingCoursesHandler(ctx context.Context, request mcp.CallToolRequest, args createTrainingCoursesArgs) (*mcp.CallToolResult, error) {
 	var startTime, endTime time.Time
-	var err error
+	policy, err := args.BookingPolicy.toDomain()
+	if err != nil {
+		return nil, err
+	}
 	reqs := make([]writeTrain.ReqCreateTrainDate, len(args.Schedule))
 	for i, schedule := range args.Schedule {
 		startTime, err = schedule.GetStartTime(args.TimeZone)
@@ -101,6 +152,8 @@ func createTrainingCoursesHandler(ctx context.Context, request mcp.CallToolReque
 			CoachID:   args.UserId,
 			Location:  args.Location,
 			Capacity:  args.Capacity,
+
+			BookingPolicy: policy,
 		}
 	}
 	_, err = batchCreateTrainDateUC.Execute(ctx, reqs)
@@ -0,0 +1,124 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"seanAIgent/internal/booking/domain/entity"
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
+)
+
+func ProvideCreateTrainingSeriesTool() server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool(
+			"create_recurring_course_sessions",
+			mcp.WithDescription("Create a recurring course (e.g. every Sunday 14:00-16:00). Sessions are generated automatically on a rolling basis, so use this instead of create_course_sessions when the user describes a repeating schedule."),
+			mcp.WithString("line_user_id",
+				mcp.Required(),
+				mcp.Description("Line user ID of the person creating the sessions"),
+			),
+			mcp.WithString("location",
+				mcp.Required(),
+				mcp.Description("Location or room of the course sessions"),
+			),
+			mcp.WithString("time_zone",
+				mcp.DefaultString("Asia/Taipei"),
+				mcp.Description("Time zone of user's location"),
+			),
+			mcp.WithNumber("capacity",
+				mcp.Required(),
+				mcp.Description("Maximum number of participants per session"),
+			),
+			mcp.WithString("frequency",
+				mcp.DefaultString("weekly"),
+				mcp.Enum("weekly", "biweekly"),
+				mcp.Description("How often the course repeats"),
+			),
+			mcp.WithArray("weekdays",
+				mcp.Required(),
+				mcp.Description("Days of week the course takes place, 0 = Sunday ... 6 = Saturday"),
+				mcp.Items(map[string]any{"type": "integer", "minimum": 0, "maximum": 6}),
+			),
+			mcp.WithString("start_date",
+				mcp.Required(),
+				mcp.Description("First date of the course in YYYY-MM-DD format"),
+			),
+			mcp.WithString("start_time",
+				mcp.Required(),
+				mcp.Description("Start time in HH:MM format"),
+			),
+			mcp.WithString("end_time",
+				mcp.Required(),
+				mcp.Description("End time in HH:MM format"),
+			),
+			mcp.WithString("until",
+				mcp.Description("Last date of the course in YYYY-MM-DD format. Omit for no end date"),
+			),
+			mcp.WithNumber("count",
+				mcp.Description("Total number of sessions. Omit for no limit"),
+			),
+			mcp.WithArray("exceptions",
+				mcp.Description("Dates (YYYY-MM-DD) to skip, e.g. holidays"),
+				mcp.Items(map[string]any{"type": "string"}),
+			),
+		),
+		Handler: mcp.NewTypedToolHandler(createTrainingSeriesHandler),
+	}
+}
+
+type createTrainingSeriesArgs struct {
+	UserId     string   `json:"line_user_id"`
+	Location   string   `json:"location"`
+	TimeZone   string   `json:"time_zone"`
+	Frequency  string   `json:"frequency"`
+	StartDate  string   `json:"start_date"`
+	StartTime  string   `json:"start_time"`
+	EndTime    string   `json:"end_time"`
+	Until      string   `json:"until"`
+	Weekdays   []int    `json:"weekdays"`
+	Exceptions []string `json:"exceptions"`
+	Capacity   int      `json:"capacity"`
+	Count      int      `json:"count"`
+}
+
+func createTrainingSeriesHandler(ctx context.Context, request mcp.CallToolRequest, args createTrainingSeriesArgs) (*mcp.CallToolResult, error) {
+	weekdays := make([]time.Weekday, 0, len(args.Weekdays))
+	for _, d := range args.Weekdays {
+		weekdays = append(weekdays, time.Weekday(d))
+	}
+	frequency := entity.RecurrenceWeekly
+	if strings.EqualFold(args.Frequency, "biweekly") {
+		frequency = entity.RecurrenceBiweekly
+	}
+	resp, err := createTrainingSeriesUC.Execute(ctx, writeSeries.ReqCreateTrainingSeries{
+		CoachID:    args.UserId,
+		Location:   args.Location,
+		Timezone:   args.TimeZone,
+		Capacity:   args.Capacity,
+		Frequency:  frequency,
+		Weekdays:   weekdays,
+		StartDate:  args.StartDate,
+		StartTime:  args.StartTime,
+		EndTime:    args.EndTime,
+		Until:      args.Until,
+		Count:      args.Count,
+		Exceptions: args.Exceptions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	msg := fmt.Sprintf("ok, series %s created with %d sessions", resp.Series.ID(), len(resp.Created))
+	if len(resp.Skipped) > 0 {
+		dates := make([]string, 0, len(resp.Skipped))
+		for _, s := range resp.Skipped {
+			dates = append(dates, s.Date)
+		}
+		msg += fmt.Sprintf(", skipped because of existing sessions: %s", strings.Join(dates, ", "))
+	}
+	return mcp.NewToolResultText(msg), nil
+}
@@ -0,0 +1,62 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
+)
+
+func ProvideDeleteTrainingSeriesTool() server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool(
+			"delete_recurring_course_sessions",
+			mcp.WithDescription("Remove sessions of a recurring course. Use scope THIS for a single session, FOLLOWING to end the course from that session on, or ALL to stop the whole course. Sessions that already have bookings are kept and reported back."),
+			mcp.WithString("line_user_id",
+				mcp.Required(),
+				mcp.Description("Line user ID of the coach who owns the course"),
+			),
+			mcp.WithString("series_id",
+				mcp.Required(),
+				mcp.Description("ID of the recurring course, see query_recurring_courses"),
+			),
+			mcp.WithString("scope",
+				mcp.Required(),
+				mcp.Enum(string(writeSeries.SeriesEditScopeThis), string(writeSeries.SeriesEditScopeFollowing), string(writeSeries.SeriesEditScopeAll)),
+				mcp.Description("Which sessions to remove"),
+			),
+			mcp.WithString("occurrence_date",
+				mcp.Description("Date of the session to remove in YYYY-MM-DD format. Required unless scope is ALL"),
+			),
+		),
+		Handler: mcp.NewTypedToolHandler(deleteTrainingSeriesHandler),
+	}
+}
+
+type deleteTrainingSeriesArgs struct {
+	UserId         string `json:"line_user_id"`
+	SeriesID       string `json:"series_id"`
+	Scope          string `json:"scope"`
+	OccurrenceDate string `json:"occurrence_date"`
+}
+
+func deleteTrainingSeriesHandler(ctx context.Context, request mcp.CallToolRequest, args deleteTrainingSeriesArgs) (*mcp.CallToolResult, error) {
+	resp, err := deleteTrainingSeriesUC.Execute(ctx, writeSeries.ReqDeleteTrainingSeries{
+		SeriesID:       args.SeriesID,
+		CoachID:        args.UserId,
+		OccurrenceDate: args.OccurrenceDate,
+		Scope:          writeSeries.SeriesEditScope(strings.ToUpper(args.Scope)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	msg := fmt.Sprintf("ok, %d sessions deleted", len(resp.Deleted))
+	if len(resp.Kept) > 0 {
+		msg += ", kept because of existing bookings: " + skippedOccurrencesText(resp.Kept)
+	}
+	return mcp.NewToolResultText(msg), nil
+}
@@ -0,0 +1,61 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	readSeries "seanAIgent/internal/booking/usecase/series/read"
+)
+
+func ProvideQueryTrainingSeriesTool() server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool(
+			"query_recurring_courses",
+			mcp.WithDescription("List the active recurring courses of a coach. Use this to find the series ID before updating or deleting recurring course sessions."),
+			mcp.WithString("line_user_id",
+				mcp.Required(),
+				mcp.Description("Line user ID of the coach"),
+			),
+		),
+		Handler: mcp.NewTypedToolHandler(queryTrainingSeriesHandler),
+	}
+}
+
+type queryTrainingSeriesArgs struct {
+	UserId string `json:"line_user_id"`
+}
+
+func queryTrainingSeriesHandler(ctx context.Context, request mcp.CallToolRequest, args queryTrainingSeriesArgs) (*mcp.CallToolResult, error) {
+	seriesList, err := queryTrainingSeriesUC.Execute(ctx, readSeries.ReqQueryTrainingSeries{
+		CoachID: args.UserId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(seriesList) == 0 {
+		return mcp.NewToolResultText("no recurring courses"), nil
+	}
+	lines := make([]string, 0, len(seriesList))
+	for _, s := range seriesList {
+		rule := s.Rule()
+		weekdays := make([]string, 0, len(rule.Weekdays()))
+		for _, d := range rule.Weekdays() {
+			weekdays = append(weekdays, d.String())
+		}
+		line := fmt.Sprintf("series %s: %s %s %s-%s at %s, capacity %d, from %s",
+			s.ID(), rule.Frequency(), strings.Join(weekdays, "/"), s.StartClock(), s.EndClock(),
+			s.Location(), s.Capacity(), s.StartDate())
+		if rule.Until() != "" {
+			line += " until " + rule.Until()
+		}
+		if len(s.Exceptions()) > 0 {
+			line += ", skipped dates: " + strings.Join(s.Exceptions(), ", ")
+		}
+		lines = append(lines, line)
+	}
+	return mcp.NewToolResultText(strings.Join(lines, "\n")), nil
+}
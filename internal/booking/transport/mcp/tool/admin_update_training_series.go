@@ -0,0 +1,94 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
+)
+
+func ProvideUpdateTrainingSeriesTool() server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool(
+			"update_recurring_course_sessions",
+			mcp.WithDescription("Change the location, time or capacity of a recurring course. Use scope THIS for a single session, FOLLOWING for the session and all later ones, or ALL for the whole course. Sessions that already have bookings keep their time and are reported back."),
+			mcp.WithString("line_user_id",
+				mcp.Required(),
+				mcp.Description("Line user ID of the coach who owns the course"),
+			),
+			mcp.WithString("series_id",
+				mcp.Required(),
+				mcp.Description("ID of the recurring course, see query_recurring_courses"),
+			),
+			mcp.WithString("scope",
+				mcp.Required(),
+				mcp.Enum(string(writeSeries.SeriesEditScopeThis), string(writeSeries.SeriesEditScopeFollowing), string(writeSeries.SeriesEditScopeAll)),
+				mcp.Description("Which sessions to change"),
+			),
+			mcp.WithString("occurrence_date",
+				mcp.Description("Date of the session to change in YYYY-MM-DD format. Required unless scope is ALL"),
+			),
+			mcp.WithString("location",
+				mcp.Required(),
+				mcp.Description("Location or room of the course sessions"),
+			),
+			mcp.WithString("start_time",
+				mcp.Required(),
+				mcp.Description("Start time in HH:MM format"),
+			),
+			mcp.WithString("end_time",
+				mcp.Required(),
+				mcp.Description("End time in HH:MM format"),
+			),
+			mcp.WithNumber("capacity",
+				mcp.Required(),
+				mcp.Description("Maximum number of participants per session"),
+			),
+		),
+		Handler: mcp.NewTypedToolHandler(updateTrainingSeriesHandler),
+	}
+}
+
+type updateTrainingSeriesArgs struct {
+	UserId         string `json:"line_user_id"`
+	SeriesID       string `json:"series_id"`
+	Scope          string `json:"scope"`
+	OccurrenceDate string `json:"occurrence_date"`
+	Location       string `json:"location"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	Capacity       int    `json:"capacity"`
+}
+
+func updateTrainingSeriesHandler(ctx context.Context, request mcp.CallToolRequest, args updateTrainingSeriesArgs) (*mcp.CallToolResult, error) {
+	resp, err := updateTrainingSeriesUC.Execute(ctx, writeSeries.ReqUpdateTrainingSeries{
+		SeriesID:       args.SeriesID,
+		CoachID:        args.UserId,
+		OccurrenceDate: args.OccurrenceDate,
+		Scope:          writeSeries.SeriesEditScope(strings.ToUpper(args.Scope)),
+		Location:       args.Location,
+		StartTime:      args.StartTime,
+		EndTime:        args.EndTime,
+		Capacity:       args.Capacity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	msg := fmt.Sprintf("ok, series %s updated %d sessions", resp.Series.ID(), len(resp.Updated)+len(resp.Created))
+	if len(resp.Skipped) > 0 {
+		msg += ", not changed: " + skippedOccurrencesText(resp.Skipped)
+	}
+	return mcp.NewToolResultText(msg), nil
+}
+
+func skippedOccurrencesText(skipped []writeSeries.SkippedOccurrence) string {
+	items := make([]string, 0, len(skipped))
+	for _, s := range skipped {
+		items = append(items, fmt.Sprintf("%s (%s)", s.Date, s.Reason))
+	}
+	return strings.Join(items, ", ")
+}
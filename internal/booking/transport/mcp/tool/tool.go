@@ -5,6 +5,8 @@ import (
 	"seanAIgent/internal/booking/usecase"
 	writeAppt "seanAIgent/internal/booking/usecase/appointment/write"
 	"seanAIgent/internal/booking/usecase/core"
+	readSeries "seanAIgent/internal/booking/usecase/series/read"
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
 	readTrain "seanAIgent/internal/booking/usecase/traindate/read"
 	writeTrain "seanAIgent/internal/booking/usecase/traindate/write"
 )
@@ -12,9 +14,17 @@ import (
 var batchCreateTrainDateUC core.WriteUseCase[[]writeTrain.ReqCreateTrainDate, []*entity.TrainDate]
 var cancelLeaveUC core.WriteUseCase[writeAppt.ReqCancelLeave, *entity.Appointment]
 var adminQueryTrainRangeUC core.ReadUseCase[readTrain.ReqAdminQueryTrainRange, []*entity.TrainDateHasApptState]
+var createTrainingSeriesUC writeSeries.CreateTrainingSeriesUseCase
+var updateTrainingSeriesUC writeSeries.UpdateTrainingSeriesUseCase
+var deleteTrainingSeriesUC writeSeries.DeleteTrainingSeriesUseCase
+var queryTrainingSeriesUC readSeries.QueryTrainingSeriesUseCase
 
 func InitTool(registry *usecase.Registry) {
 	batchCreateTrainDateUC = registry.BatchCreateTrainDate
 	cancelLeaveUC = registry.CancelLeave
 	adminQueryTrainRangeUC = registry.AdminQueryTrainRange
+	createTrainingSeriesUC = registry.CreateTrainingSeries
+	updateTrainingSeriesUC = registry.UpdateTrainingSeries
+	deleteTrainingSeriesUC = registry.DeleteTrainingSeries
+	queryTrainingSeriesUC = registry.QueryTrainingSeries
 }
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"seanAIgent/internal/booking/domain/entity"
+	uccore "seanAIgent/internal/booking/usecase/core"
+	readRole "seanAIgent/internal/booking/usecase/role/read"
+
+	"github.com/94peter/vulpes/log"
+	"github.com/gin-gonic/gin"
+)
+
+// ActorMiddleware 解析登入者的後台權限放入 request context，use case 的權限裝飾器依此判斷；
+// 需放在 LINE 登入與管理員判斷的中介層之後
+func ActorMiddleware(resolveActorUC readRole.ResolveActorUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		actor, err := resolveActorUC.Execute(ctx, readRole.ReqResolveActor{
+			UserID:      getUserID(c),
+			IsLineAdmin: isAdmin(c),
+		})
+		if err != nil {
+			// 查詢失敗時不給任何權限
+			log.Errorf("resolve actor fail: %v", err)
+			actor = uccore.NewActor(getUserID(c))
+		}
+		c.Request = c.Request.WithContext(uccore.WithActor(ctx, actor))
+		c.Next()
+	}
+}
+
+// hasPermission 未經過 ActorMiddleware 時沿用 LINE 管理員判斷
+func hasPermission(c *gin.Context, perms ...entity.Permission) bool {
+	actor, ok := uccore.ActorFromContext(c.Request.Context())
+	if !ok {
+		return isAdmin(c)
+	}
+	return actor.Can(perms...)
+}
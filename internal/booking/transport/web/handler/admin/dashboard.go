@@ -12,13 +12,32 @@ import (
 	"seanAIgent/internal/booking/usecase"
 	writeAppt "seanAIgent/internal/booking/usecase/appointment/write"
 	uccore "seanAIgent/internal/booking/usecase/core"
+	writeCredit "seanAIgent/internal/booking/usecase/credit/write"
+	readDeadLetter "seanAIgent/internal/booking/usecase/deadletter/read"
+	writeDeadLetter "seanAIgent/internal/booking/usecase/deadletter/write"
+	readEventLog "seanAIgent/internal/booking/usecase/eventlog/read"
+	writeEventLog "seanAIgent/internal/booking/usecase/eventlog/write"
+	writePayment "seanAIgent/internal/booking/usecase/payment/write"
+	readRole "seanAIgent/internal/booking/usecase/role/read"
+	writeRole "seanAIgent/internal/booking/usecase/role/write"
+	readSeries "seanAIgent/internal/booking/usecase/series/read"
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
 	readStats "seanAIgent/internal/booking/usecase/stats/read"
+	readStudent "seanAIgent/internal/booking/usecase/student/read"
+	writeStudent "seanAIgent/internal/booking/usecase/student/write"
+	readTeam "seanAIgent/internal/booking/usecase/team/read"
+	writeTeam "seanAIgent/internal/booking/usecase/team/write"
 	readTrain "seanAIgent/internal/booking/usecase/traindate/read"
+	readWaitlist "seanAIgent/internal/booking/usecase/waitlist/read"
+	writeWaitlist "seanAIgent/internal/booking/usecase/waitlist/write"
+	readWebhook "seanAIgent/internal/booking/usecase/webhook/read"
+	writeWebhook "seanAIgent/internal/booking/usecase/webhook/write"
 	"seanAIgent/templates"
 	"seanAIgent/templates/admin"
 
 	"github.com/94peter/botreplyer/provider/line/mid"
 	"github.com/94peter/vulpes/ezapi"
+	"github.com/94peter/vulpes/log"
 	"github.com/gin-gonic/gin"
 )
 
@@ -30,6 +49,8 @@ func NewAdminApi(registry *usecase.Registry) handler.WebAPI {
 		adminToggleCheckInUC:         registry.AdminToggleCheckIn,
 		adminCreateLeaveUC:           registry.AdminCreateLeave,
 		adminRestoreFromLeaveUC:      registry.AdminRestoreFromLeave,
+		approveLeaveUC:               registry.ApproveLeave,
+		rejectLeaveUC:                registry.RejectLeave,
 		adminCreateWalkInUC:          registry.AdminCreateWalkIn,
 		adminQueryStudentsUC:         registry.AdminQueryStudents,
 		adminBatchUpdateAttendanceUC: registry.AdminBatchUpdateAttendance,
@@ -38,6 +59,39 @@ func NewAdminApi(registry *usecase.Registry) handler.WebAPI {
 		queryMonthlyUserReportsUC:    registry.QueryMonthlyUserReports,
 		getBusinessAnalyticsUC:       registry.GetBusinessAnalytics,
 		getUserDetailUC:              registry.GetUserDetail,
+		queryWaitlistUC:              registry.QueryWaitlist,
+		adminReorderWaitlistUC:       registry.AdminReorderWaitlist,
+		adminTopUpCreditsUC:          registry.AdminTopUpCredits,
+		recordPaymentUC:              registry.RecordPayment,
+		queryStudentsUC:              registry.QueryStudents,
+		mergeStudentsUC:              registry.MergeStudents,
+		queryTeamsUC:                 registry.QueryTeams,
+		queryTeamMembersUC:           registry.QueryTeamMembers,
+		createTeamUC:                 registry.CreateTeam,
+		updateTeamUC:                 registry.UpdateTeam,
+		updateTeamMembersUC:          registry.UpdateTeamMembers,
+		deleteTeamUC:                 registry.DeleteTeam,
+		resolveActorUC:               registry.ResolveActor,
+		queryUserRolesUC:             registry.QueryUserRoles,
+		assignUserRolesUC:            registry.AssignUserRoles,
+		removeUserRolesUC:            registry.RemoveUserRoles,
+		queryDeadLettersUC:           registry.QueryDeadLetters,
+		getDeadLetterUC:              registry.GetDeadLetter,
+		replayDeadLetterUC:           registry.ReplayDeadLetter,
+		discardDeadLetterUC:          registry.DiscardDeadLetter,
+		queryEventsUC:                registry.QueryEvents,
+		getEventUC:                   registry.GetEvent,
+		querySubscriberProgressUC:    registry.QuerySubscriberProgress,
+		redispatchEventUC:            registry.RedispatchEvent,
+		queryWebhookEndpointsUC:      registry.QueryWebhookEndpoints,
+		queryWebhookDeliveriesUC:     registry.QueryWebhookDeliveries,
+		createWebhookEndpointUC:      registry.CreateWebhookEndpoint,
+		updateWebhookEndpointUC:      registry.UpdateWebhookEndpoint,
+		deleteWebhookEndpointUC:      registry.DeleteWebhookEndpoint,
+		pingWebhookEndpointUC:        registry.PingWebhookEndpoint,
+		queryTrainingSeriesUC:        registry.QueryTrainingSeries,
+		updateTrainingSeriesUC:       registry.UpdateTrainingSeries,
+		deleteTrainingSeriesUC:       registry.DeleteTrainingSeries,
 	}
 }
 
@@ -48,6 +102,8 @@ type adminAPI struct {
 	adminToggleCheckInUC         writeAppt.AdminToggleCheckInUseCase
 	adminCreateLeaveUC           writeAppt.AdminCreateLeaveUseCase
 	adminRestoreFromLeaveUC      writeAppt.AdminRestoreFromLeaveUseCase
+	approveLeaveUC               writeAppt.ApproveLeaveUseCase
+	rejectLeaveUC                writeAppt.RejectLeaveUseCase
 	adminCreateWalkInUC          writeAppt.AdminCreateWalkInUseCase
 	adminQueryStudentsUC         readStats.AdminQueryStudentsUseCase
 	adminBatchUpdateAttendanceUC writeAppt.AdminBatchUpdateAttendanceUseCase
@@ -56,40 +112,88 @@ type adminAPI struct {
 	queryMonthlyUserReportsUC    readStats.QueryMonthlyUserReportsUseCase
 	getBusinessAnalyticsUC       readStats.GetBusinessAnalyticsUseCase
 	getUserDetailUC              readStats.GetUserDetailUseCase
+	queryWaitlistUC              readWaitlist.QueryWaitlistUseCase
+	adminReorderWaitlistUC       writeWaitlist.AdminReorderWaitlistUseCase
+	adminTopUpCreditsUC          writeCredit.AdminTopUpCreditsUseCase
+	recordPaymentUC              writePayment.RecordPaymentUseCase
+	queryStudentsUC              readStudent.QueryStudentsUseCase
+	mergeStudentsUC              writeStudent.MergeStudentsUseCase
+	queryTeamsUC                 readTeam.QueryTeamsUseCase
+	queryTeamMembersUC           readTeam.QueryTeamMembersUseCase
+	createTeamUC                 writeTeam.CreateTeamUseCase
+	updateTeamUC                 writeTeam.UpdateTeamUseCase
+	updateTeamMembersUC          writeTeam.UpdateTeamMembersUseCase
+	deleteTeamUC                 writeTeam.DeleteTeamUseCase
+	resolveActorUC               readRole.ResolveActorUseCase
+	queryUserRolesUC             readRole.QueryUserRolesUseCase
+	assignUserRolesUC            writeRole.AssignUserRolesUseCase
+	removeUserRolesUC            writeRole.RemoveUserRolesUseCase
+	queryDeadLettersUC           readDeadLetter.QueryDeadLettersUseCase
+	getDeadLetterUC              readDeadLetter.GetDeadLetterUseCase
+	replayDeadLetterUC           writeDeadLetter.ReplayDeadLetterUseCase
+	discardDeadLetterUC          writeDeadLetter.DiscardDeadLetterUseCase
+	queryEventsUC                readEventLog.QueryEventsUseCase
+	getEventUC                   readEventLog.GetEventUseCase
+	querySubscriberProgressUC    readEventLog.QuerySubscriberProgressUseCase
+	redispatchEventUC            writeEventLog.RedispatchEventUseCase
+	queryWebhookEndpointsUC      readWebhook.QueryWebhookEndpointsUseCase
+	queryWebhookDeliveriesUC     readWebhook.QueryWebhookDeliveriesUseCase
+	createWebhookEndpointUC      writeWebhook.CreateWebhookEndpointUseCase
+	updateWebhookEndpointUC      writeWebhook.UpdateWebhookEndpointUseCase
+	deleteWebhookEndpointUC      writeWebhook.DeleteWebhookEndpointUseCase
+	pingWebhookEndpointUC        writeWebhook.PingWebhookEndpointUseCase
+	queryTrainingSeriesUC        readSeries.QueryTrainingSeriesUseCase
+	updateTrainingSeriesUC       writeSeries.UpdateTrainingSeriesUseCase
+	deleteTrainingSeriesUC       writeSeries.DeleteTrainingSeriesUseCase
 	once                         sync.Once
 }
 
 func (api *adminAPI) InitRouter(r ezapi.Router) {
 	api.once.Do(func() {
-		r.GET("/v2/admin/dashboard", api.getDashboard)
-		r.GET("/:lang/v2/admin/dashboard", api.getDashboard)
+		r.GET("/v2/admin/dashboard", api.requirePermission(entity.PermReportRead), api.getDashboard)
+		r.GET("/:lang/v2/admin/dashboard", api.requirePermission(entity.PermReportRead), api.getDashboard)
 		api.adminGroup(r)
 	})
 }
 
 func (api *adminAPI) adminGroup(r ezapi.Router) {
-	r.GET("/v2/admin/analytics", api.getAnalytics)
-	r.GET("/:lang/v2/admin/analytics", api.getAnalytics)
-	r.GET("/v2/admin/checkin/:sessionId", api.getCheckinPage)
-	r.GET("/:lang/v2/admin/checkin/:sessionId", api.getCheckinPage)
-	r.POST("/v2/admin/checkin/submit", api.submitCheckin)
-	r.POST("/v2/admin/checkin/toggle", api.toggleCheckin)
-	r.POST("/v2/admin/checkin/leave", api.createLeave)
-	r.POST("/v2/admin/checkin/restore", api.restoreFromLeave)
-	r.POST("/v2/admin/checkin/walkin", api.createWalkIn)
-	r.POST("/v2/admin/checkin/batch-update", api.batchUpdateAttendance)
-	r.GET("/v2/admin/students/search", api.searchStudents)
-
-	r.GET("/v2/admin/users/report", api.getUserReport)
-	r.GET("/:lang/v2/admin/users/report", api.getUserReport)
-	r.GET("/v2/admin/users/report/export", api.exportUserReport)
-	r.GET("/v2/admin/users/:userId", api.getUserDetail)
-	r.GET("/:lang/v2/admin/users/:userId", api.getUserDetail)
+	r.GET("/v2/admin/analytics", api.requirePermission(entity.PermReportRead), api.getAnalytics)
+	r.GET("/:lang/v2/admin/analytics", api.requirePermission(entity.PermReportRead), api.getAnalytics)
+	r.GET("/v2/admin/checkin/:sessionId", api.requirePermission(entity.PermAttendanceWrite), api.getCheckinPage)
+	r.GET("/:lang/v2/admin/checkin/:sessionId", api.requirePermission(entity.PermAttendanceWrite), api.getCheckinPage)
+	r.POST("/v2/admin/checkin/submit", api.requirePermission(entity.PermAttendanceWrite), api.submitCheckin)
+	r.POST("/v2/admin/checkin/toggle", api.requirePermission(entity.PermAttendanceWrite), api.toggleCheckin)
+	r.POST("/v2/admin/checkin/leave", api.requirePermission(entity.PermAttendanceWrite), api.createLeave)
+	r.POST("/v2/admin/checkin/restore", api.requirePermission(entity.PermAttendanceWrite), api.restoreFromLeave)
+	r.POST("/v2/admin/checkin/leave/approve", api.requirePermission(entity.PermAttendanceWrite), api.approveLeave)
+	r.POST("/v2/admin/checkin/leave/reject", api.requirePermission(entity.PermAttendanceWrite), api.rejectLeave)
+	r.POST("/v2/admin/checkin/walkin", api.requirePermission(entity.PermAttendanceWrite), api.createWalkIn)
+	r.POST("/v2/admin/checkin/batch-update", api.requirePermission(entity.PermAttendanceWrite), api.batchUpdateAttendance)
+	r.POST("/v2/admin/checkin/waitlist/reorder", api.requirePermission(entity.PermAttendanceWrite), api.reorderWaitlist)
+	r.GET("/v2/admin/students/search", api.requirePermission(entity.PermAttendanceWrite), api.searchStudents)
+
+	r.GET("/v2/admin/users/report", api.requirePermission(entity.PermReportRead), api.getUserReport)
+	r.GET("/:lang/v2/admin/users/report", api.requirePermission(entity.PermReportRead), api.getUserReport)
+	r.GET("/v2/admin/users/report/export", api.requirePermission(entity.PermReportExport), api.exportUserReport)
+	r.GET("/v2/admin/users/:userId", api.requirePermission(entity.PermUserPIIRead), api.getUserDetail)
+	r.GET("/:lang/v2/admin/users/:userId", api.requirePermission(entity.PermUserPIIRead), api.getUserDetail)
+	r.POST("/v2/admin/users/:userId/credits", api.requirePermission(entity.PermBillingWrite), api.topUpCredits)
+	r.POST("/v2/admin/users/:userId/payments", api.requirePermission(entity.PermBillingWrite), api.recordPayment)
+	r.POST("/v2/admin/users/:userId/students/merge", api.requirePermission(entity.PermStudentWrite), api.mergeStudents)
+	api.teamGroup(r)
+	api.roleGroup(r)
+	api.deadLetterGroup(r)
+	api.eventConsoleGroup(r)
+	api.webhookGroup(r)
+	api.seriesGroup(r)
 }
 
 func (api *adminAPI) exportUserReport(c *gin.Context) {
-	if !isAdmin(c) {
-		c.Status(http.StatusUnauthorized)
+	ctx, teamFilter, ok := api.resolveTeamScope(c)
+	if !ok {
+		if !c.Writer.Written() {
+			c.Status(http.StatusUnauthorized)
+		}
 		return
 	}
 	now := time.Now()
@@ -101,11 +205,13 @@ func (api *adminAPI) exportUserReport(c *gin.Context) {
 	fmt.Sscanf(monthStr, "%d", &month)
 
 	// 1. 獲取該月所有資料 (不分頁)
-	resp, err := api.queryMonthlyUserReportsUC.Execute(c.Request.Context(), readStats.ReqQueryMonthlyUserReports{
-		Year:  year,
-		Month: month,
-		Page:  1,
-		Limit: 1000, // 假設單月家長不超過 1000 位
+	resp, err := api.queryMonthlyUserReportsUC.Execute(ctx, readStats.ReqQueryMonthlyUserReports{
+		Year:          year,
+		Month:         month,
+		Page:          1,
+		Limit:         1000, // 假設單月家長不超過 1000 位
+		PaymentStatus: c.Query("status"),
+		TeamID:        teamFilter.TeamID,
 	})
 
 	if err != nil {
@@ -121,7 +227,7 @@ func (api *adminAPI) exportUserReport(c *gin.Context) {
 	// 寫入 UTF-8 BOM 以免 Excel 亂碼
 	c.Writer.Write([]byte{0xEF, 0xBB, 0xBF})
 
-	fmt.Fprintln(c.Writer, "家長姓名,UserID,孩子姓名,總預約,出席次數,請假次數,缺席次數,出席率")
+	fmt.Fprintln(c.Writer, "家長姓名,UserID,孩子姓名,總預約,出席次數,請假次數,缺席次數,補課次數,出席率,計費堂數,應收金額,已收金額,繳費狀態")
 
 	for _, u := range resp.UserStats {
 		// 寫入家長匯總列
@@ -129,8 +235,14 @@ func (api *adminAPI) exportUserReport(c *gin.Context) {
 		if u.TotalBookings > 0 {
 			parentRate = float64(u.AttendedCount) / float64(u.TotalBookings)
 		}
-		fmt.Fprintf(c.Writer, "%s,%s,---(家長匯總)---,%d,%d,%d,%d,%.2f%%\n",
-			u.UserName, u.UserID, u.TotalBookings, u.AttendedCount, u.LeaveCount, u.AbsentCount, parentRate*100)
+		fmt.Fprintf(c.Writer, "%s,%s,---(家長匯總)---,%d,%d,%d,%d,%d,%.2f%%",
+			u.UserName, u.UserID, u.TotalBookings, u.AttendedCount, u.LeaveCount, u.AbsentCount, u.MakeUpCount, parentRate*100)
+		if b, ok := resp.Billing[u.UserID]; ok {
+			fmt.Fprintf(c.Writer, ",%d,%d,%d,%s\n",
+				b.BillableCount(), b.AmountDue(), b.AmountPaid(), billingStatusLabel(b.Status().String()))
+		} else {
+			fmt.Fprintln(c.Writer, ",,,,")
+		}
 
 		// 寫入孩子明細列
 		for _, child := range u.Children {
@@ -138,8 +250,8 @@ func (api *adminAPI) exportUserReport(c *gin.Context) {
 			if child.TotalBookings > 0 {
 				childRate = float64(child.AttendedCount) / float64(child.TotalBookings)
 			}
-			fmt.Fprintf(c.Writer, ",,%s,%d,%d,%d,%d,%.2f%%\n",
-				child.ChildName, child.TotalBookings, child.AttendedCount, child.LeaveCount, child.AbsentCount, childLimitRate(childRate)*100)
+			fmt.Fprintf(c.Writer, ",,%s,%d,%d,%d,%d,%d,%.2f%%,,,,\n",
+				child.ChildName, child.TotalBookings, child.AttendedCount, child.LeaveCount, child.AbsentCount, child.MakeUpCount, childLimitRate(childRate)*100)
 		}
 	}
 }
@@ -157,6 +269,8 @@ func apptToRecord(appt *entity.Appointment) *admin.CheckinRecord {
 		status = "Leave"
 	} else if appt.Status() == entity.StatusAbsent {
 		status = "Absent"
+	} else if appt.IsCancelledByCoach() {
+		status = "CoachCancelled"
 	}
 	return &admin.CheckinRecord{
 		BookingID:   appt.ID(),
@@ -196,17 +310,33 @@ func (api *adminAPI) getCheckinPage(c *gin.Context) {
 		}
 
 		bookings = append(bookings, &admin.CheckinRecord{
-			BookingID:   appt.ID,
-			ChildName:   appt.ChildName,
-			ParentName:  appt.UserName,
-			Status:      status,
-			IsWalkIn:    appt.IsWalkIn,
-			IsGuest:     appt.IsGuest,
-			ContactInfo: appt.ContactInfo,
-			LeaveReason: appt.LeaveReason,
+			BookingID:    appt.ID,
+			ChildName:    appt.ChildName,
+			ParentName:   appt.UserName,
+			Status:       status,
+			IsWalkIn:     appt.IsWalkIn,
+			IsGuest:      appt.IsGuest,
+			ContactInfo:  appt.ContactInfo,
+			LeaveReason:  appt.LeaveReason,
+			LeavePending: appt.IsLeavePending(),
 		})
 	}
 
+	var waitlist []*admin.WaitlistRecord
+	wl, errUC := api.queryWaitlistUC.Execute(c.Request.Context(), readWaitlist.ReqQueryWaitlist{
+		TrainDateID: sessionID,
+	})
+	if errUC == nil {
+		for _, entry := range wl.Waiting() {
+			waitlist = append(waitlist, &admin.WaitlistRecord{
+				EntryID:       entry.ID(),
+				ChildName:     entry.ChildName(),
+				ParentName:    entry.User().UserName(),
+				JoinedDisplay: entry.JoinedAt().Format("01/02 15:04"),
+			})
+		}
+	}
+
 	model := &admin.CheckinPageModel{
 		SessionID:   sessionID,
 		DateDisplay: trainData.StartDate.Format("2006/01/02"),
@@ -214,6 +344,7 @@ func (api *adminAPI) getCheckinPage(c *gin.Context) {
 		Location:    trainData.Location,
 		Capacity:    trainData.Capacity,
 		Bookings:    bookings,
+		Waitlist:    waitlist,
 		IsStarted:   time.Now().After(trainData.StartDate),
 	}
 
@@ -240,11 +371,6 @@ func (api *adminAPI) submitCheckin(c *gin.Context) {
 }
 
 func (api *adminAPI) toggleCheckin(c *gin.Context) {
-	if !mid.IsAdmin(c) {
-		c.Status(http.StatusUnauthorized)
-		return
-	}
-
 	bookingID := c.PostForm("bookingId")
 	_, err := api.adminToggleCheckInUC.Execute(c.Request.Context(), writeAppt.ReqAdminToggleCheckIn{
 		BookingID: bookingID,
@@ -259,11 +385,6 @@ func (api *adminAPI) toggleCheckin(c *gin.Context) {
 }
 
 func (api *adminAPI) createLeave(c *gin.Context) {
-	if !mid.IsAdmin(c) {
-		c.Status(http.StatusUnauthorized)
-		return
-	}
-
 	bookingID := c.PostForm("bookingId")
 	_, err := api.adminCreateLeaveUC.Execute(c.Request.Context(), writeAppt.ReqAdminCreateLeave{
 		BookingID: bookingID,
@@ -278,11 +399,6 @@ func (api *adminAPI) createLeave(c *gin.Context) {
 }
 
 func (api *adminAPI) restoreFromLeave(c *gin.Context) {
-	if !mid.IsAdmin(c) {
-		c.Status(http.StatusUnauthorized)
-		return
-	}
-
 	bookingID := c.PostForm("bookingId")
 	_, err := api.adminRestoreFromLeaveUC.Execute(c.Request.Context(), writeAppt.ReqAdminRestoreFromLeave{
 		BookingID: bookingID,
@@ -295,12 +411,40 @@ func (api *adminAPI) restoreFromLeave(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
-func (api *adminAPI) createWalkIn(c *gin.Context) {
-	if !mid.IsAdmin(c) {
-		c.Status(http.StatusUnauthorized)
+type reviewLeaveInput struct {
+	BookingID string `json:"bookingId"`
+	Comment   string `json:"comment"`
+}
+
+func (api *adminAPI) approveLeave(c *gin.Context) {
+	api.reviewLeave(c, api.approveLeaveUC)
+}
+
+func (api *adminAPI) rejectLeave(c *gin.Context) {
+	api.reviewLeave(c, api.rejectLeaveUC)
+}
+
+func (api *adminAPI) reviewLeave(c *gin.Context, uc uccore.WriteUseCase[writeAppt.ReqReviewLeave, *entity.Appointment]) {
+	var req reviewLeaveInput
+	if err := c.ShouldBindJSON(&req); err != nil || req.BookingID == "" {
+		c.Status(http.StatusBadRequest)
 		return
 	}
 
+	_, err := uc.Execute(c.Request.Context(), writeAppt.ReqReviewLeave{
+		BookingID:  req.BookingID,
+		ReviewerID: getUserID(c),
+		Comment:    req.Comment,
+	})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (api *adminAPI) createWalkIn(c *gin.Context) {
 	var req writeAppt.ReqAdminCreateWalkIn
 	if err := c.ShouldBind(&req); err != nil {
 		c.Status(http.StatusBadRequest)
@@ -325,11 +469,6 @@ func (api *adminAPI) renderStatsOOB(c *gin.Context, sessionID string) {
 }
 
 func (api *adminAPI) batchUpdateAttendance(c *gin.Context) {
-	if !mid.IsAdmin(c) {
-		c.Status(http.StatusUnauthorized)
-		return
-	}
-
 	var req writeAppt.ReqAdminBatchUpdateAttendance
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.Status(http.StatusBadRequest)
@@ -348,11 +487,29 @@ func (api *adminAPI) batchUpdateAttendance(c *gin.Context) {
 	})
 }
 
-func (api *adminAPI) searchStudents(c *gin.Context) {
-	if !isAdmin(c) {
-		c.Status(http.StatusUnauthorized)
+func (api *adminAPI) reorderWaitlist(c *gin.Context) {
+	var req struct {
+		SessionID string   `json:"sessionId"`
+		EntryIDs  []string `json:"entryIds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	_, err := api.adminReorderWaitlistUC.Execute(c.Request.Context(), writeWaitlist.ReqAdminReorderWaitlist{
+		TrainDateID: req.SessionID,
+		EntryIDs:    req.EntryIDs,
+	})
+	if err != nil {
+		handler.ErrorHandler(c, err)
 		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (api *adminAPI) searchStudents(c *gin.Context) {
 	keyword := c.Query("q")
 	sessionID := c.Query("sessionId") // Pass this from frontend
 	if keyword == "" {
@@ -377,11 +534,13 @@ func (api *adminAPI) searchStudents(c *gin.Context) {
 
 func (api *adminAPI) getAnalytics(c *gin.Context) {
 	lineliffid := lineutil.GetAdminDashboardLiffId()
-	if !checkUser(c, lineliffid) {
+	ctx, teamFilter, ok := api.checkTeamUser(c, lineliffid)
+	if !ok {
 		return
 	}
-	resp, err := api.getBusinessAnalyticsUC.Execute(c.Request.Context(), readStats.ReqGetBusinessAnalytics{
+	resp, err := api.getBusinessAnalyticsUC.Execute(ctx, readStats.ReqGetBusinessAnalytics{
 		MonthsLimit: 12,
+		TeamID:      teamFilter.TeamID,
 	})
 	if err != nil {
 		handler.ErrorHandler(c, err)
@@ -408,6 +567,7 @@ func (api *adminAPI) getAnalytics(c *gin.Context) {
 	model := &admin.AnalyticsModel{
 		HistoricalStats: historicalStats,
 		Metrics:         metrics,
+		TeamFilter:      teamFilter,
 	}
 
 	com := templates.Layout(
@@ -430,41 +590,45 @@ func (api *adminAPI) getAnalytics(c *gin.Context) {
 func checkUser(c *gin.Context, lineliffid string) bool {
 	userID := getUserID(c)
 	if userID == "" {
-		com := templates.Layout(
-			nil,
-			lineliffid,
-			&templates.OgMeta{
-				Title:       "訓練場次看板 | Sean AIgent",
-				Description: "即時監控訓練場次預約與簽到狀態",
-				Image:       "",
-			},
-		)
-
-		c.Render(http.StatusOK, handler.Renderer{
-			Ctx:       c.Request.Context(),
-			Status:    http.StatusOK,
-			Component: com,
-		})
-		return false
-	}
-	if !isAdmin(c) {
+		renderLiffLogin(c, lineliffid, http.StatusOK)
 		return false
 	}
 	return true
 }
 
+// renderLiffLogin 未登入時顯示 LIFF 頁面，登入後重新載入
+func renderLiffLogin(c *gin.Context, lineliffid string, status int) {
+	com := templates.Layout(
+		nil,
+		lineliffid,
+		&templates.OgMeta{
+			Title:       "訓練場次看板 | Sean AIgent",
+			Description: "即時監控訓練場次預約與簽到狀態",
+			Image:       "",
+		},
+	)
+
+	c.Render(status, handler.Renderer{
+		Ctx:       c.Request.Context(),
+		Status:    status,
+		Component: com,
+	})
+}
+
 func (api *adminAPI) getDashboard(c *gin.Context) {
 	lineliffid := lineutil.GetAdminDashboardLiffId()
-	if !checkUser(c, lineliffid) {
+	ctx, teamFilter, ok := api.checkTeamUser(c, lineliffid)
+	if !ok {
 		return
 	}
 	now := time.Now()
 	startTime := now.AddDate(0, 0, -7)
 	endTime := now.AddDate(0, 0, 7)
 
-	trainDates, err := api.adminQueryTrainRangeUC.Execute(c.Request.Context(), readTrain.ReqAdminQueryTrainRange{
+	trainDates, err := api.adminQueryTrainRangeUC.Execute(ctx, readTrain.ReqAdminQueryTrainRange{
 		StartTime: startTime,
 		EndTime:   endTime,
+		TeamID:    teamFilter.TeamID,
 	})
 	if err != nil {
 		c.String(http.StatusInternalServerError, "Failed to load dashboard data")
@@ -475,6 +639,7 @@ func (api *adminAPI) getDashboard(c *gin.Context) {
 	todayEnd := todayStart.AddDate(0, 0, 1)
 
 	var todaySessions, upcomingSessions, pastSessions []*admin.SessionSummary
+	names := teamNames(teamFilter)
 
 	for _, td := range trainDates {
 		s := &admin.SessionSummary{
@@ -484,6 +649,10 @@ func (api *adminAPI) getDashboard(c *gin.Context) {
 			Location:    td.Location,
 			Capacity:    td.Capacity,
 			BookedCount: len(td.UserAppointments),
+			TeamName:    names[td.TeamID],
+
+			IsCancelled:  td.IsCancelled(),
+			CancelReason: td.CancelReason,
 		}
 
 		var attended, leave int
@@ -498,6 +667,9 @@ func (api *adminAPI) getDashboard(c *gin.Context) {
 		s.AttendedCount = attended
 		s.LeaveCount = leave
 		s.PendingCount = s.BookedCount - attended - leave
+		if s.IsCancelled {
+			s.PendingCount = 0
+		}
 
 		if !td.StartDate.Before(todayStart) && td.StartDate.Before(todayEnd) {
 			s.DateDisplay = td.StartDate.Format("01/02 (今日)")
@@ -513,6 +685,7 @@ func (api *adminAPI) getDashboard(c *gin.Context) {
 		TodaySessions:    todaySessions,
 		UpcomingSessions: upcomingSessions,
 		PastSessions:     pastSessions,
+		TeamFilter:       teamFilter,
 	}
 
 	com := templates.Layout(
@@ -641,6 +814,9 @@ func (api *adminAPI) getUserDetail(c *gin.Context) {
 		AvailableMonths: availableMonths,
 		FilterStats:     filterStats,
 		MonthlyRecords:  filteredRecords,
+		Credit:          toUserCredit(resp.Credit),
+		Students:        api.queryUserStudents(c, userID),
+		Teams:           api.queryTeamOptions(c),
 	}
 
 	com := templates.Layout(
@@ -660,9 +836,141 @@ func (api *adminAPI) getUserDetail(c *gin.Context) {
 	})
 }
 
+// queryUserStudents 查詢失敗不影響明細頁顯示
+func (api *adminAPI) queryUserStudents(c *gin.Context, userID string) []*admin.UserStudentRow {
+	students, err := api.queryStudentsUC.Execute(c.Request.Context(), readStudent.ReqQueryStudents{UserID: userID})
+	if err != nil {
+		log.Errorf("query students fail: %v", err)
+		return nil
+	}
+	rows := make([]*admin.UserStudentRow, 0, len(students))
+	for _, s := range students {
+		row := &admin.UserStudentRow{
+			ID:       s.ID(),
+			Name:     s.Name(),
+			Nickname: s.Nickname(),
+			Notes:    s.Notes(),
+		}
+		if s.Birthdate() != nil {
+			row.Birthdate = s.Birthdate().In(taipeiLoc).Format("2006/01/02")
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// queryTeamOptions 查詢失敗不影響明細頁顯示
+func (api *adminAPI) queryTeamOptions(c *gin.Context) []*admin.TeamOption {
+	teams, err := api.queryTeamsUC.Execute(c.Request.Context(), readTeam.ReqQueryTeams{})
+	if err != nil {
+		log.Errorf("query teams fail: %v", err)
+		return nil
+	}
+	return toTeamOptions(teams)
+}
+
+func (api *adminAPI) mergeStudents(c *gin.Context) {
+	var req struct {
+		TargetID  string   `json:"targetId"`
+		SourceIDs []string `json:"sourceIds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	_, ucErr := api.mergeStudentsUC.Execute(c.Request.Context(), writeStudent.ReqMergeStudents{
+		TargetID:  req.TargetID,
+		SourceIDs: req.SourceIDs,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func toUserCredit(vo *readStats.UserCreditVO) *admin.UserCredit {
+	if vo == nil {
+		return nil
+	}
+	credit := &admin.UserCredit{
+		Available:  vo.Available,
+		Reserved:   vo.Reserved,
+		Consumed:   vo.Consumed,
+		Expired:    vo.Expired,
+		NextExpiry: vo.NextExpiry,
+		Packs:      make([]*admin.CreditPackRow, 0, len(vo.Packs)),
+		History:    make([]*admin.CreditHistoryRow, 0, len(vo.History)),
+	}
+	for _, p := range vo.Packs {
+		credit.Packs = append(credit.Packs, &admin.CreditPackRow{
+			ChildName:   p.ChildName,
+			Quantity:    p.Quantity,
+			Remaining:   p.Remaining,
+			PurchasedAt: p.PurchasedAt,
+			ExpiresAt:   p.ExpiresAt,
+			Note:        p.Note,
+			IsExpired:   p.IsExpired,
+		})
+	}
+	for _, h := range vo.History {
+		credit.History = append(credit.History, &admin.CreditHistoryRow{
+			Type:       h.Type,
+			ChildName:  h.ChildName,
+			Amount:     h.Amount,
+			Note:       h.Note,
+			OccurredAt: h.OccurredAt,
+		})
+	}
+	return credit
+}
+
+var taipeiLoc = time.FixedZone("Asia/Taipei", 8*60*60)
+
+func (api *adminAPI) topUpCredits(c *gin.Context) {
+	var req struct {
+		UserName  string `json:"userName"`
+		ChildName string `json:"childName"`
+		ExpiresAt string `json:"expiresAt"` // 2006-01-02，當日結束前有效
+		Note      string `json:"note"`
+		Quantity  int    `json:"quantity"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	user, err := entity.NewUser(c.Param("userId"), req.UserName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "使用者資料不正確"})
+		return
+	}
+	expiresDate, err := time.ParseInLocation("2006-01-02", req.ExpiresAt, taipeiLoc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "到期日格式不正確"})
+		return
+	}
+
+	_, ucErr := api.adminTopUpCreditsUC.Execute(c.Request.Context(), writeCredit.ReqAdminTopUpCredits{
+		ExpiresAt: expiresDate.AddDate(0, 0, 1),
+		User:      user,
+		ChildName: req.ChildName,
+		Note:      req.Note,
+		Quantity:  req.Quantity,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
 func (api *adminAPI) getUserReport(c *gin.Context) {
 	lineliffid := lineutil.GetAdminDashboardLiffId()
-	if !checkUser(c, lineliffid) {
+	ctx, teamFilter, ok := api.checkTeamUser(c, lineliffid)
+	if !ok {
 		return
 	}
 	now := time.Now()
@@ -670,6 +978,7 @@ func (api *adminAPI) getUserReport(c *gin.Context) {
 	monthStr := c.DefaultQuery("month", fmt.Sprintf("%d", int(now.Month())))
 	pageStr := c.DefaultQuery("page", "1")
 	search := c.Query("search")
+	paymentStatus := c.Query("status")
 
 	var year, month int
 	var page int64
@@ -677,12 +986,14 @@ func (api *adminAPI) getUserReport(c *gin.Context) {
 	fmt.Sscanf(monthStr, "%d", &month)
 	fmt.Sscanf(pageStr, "%d", &page)
 
-	resp, err := api.queryMonthlyUserReportsUC.Execute(c.Request.Context(), readStats.ReqQueryMonthlyUserReports{
+	resp, err := api.queryMonthlyUserReportsUC.Execute(ctx, readStats.ReqQueryMonthlyUserReports{
 		Year:   year,
 		Month:  month,
 		Page:   page,
-		Limit:  50, // 預設每頁 50 筆
-		Search: search,
+		Limit:         50, // 預設每頁 50 筆
+		Search:        search,
+		PaymentStatus: paymentStatus,
+		TeamID:        teamFilter.TeamID,
 	})
 
 	if err != nil {
@@ -704,6 +1015,7 @@ func (api *adminAPI) getUserReport(c *gin.Context) {
 				Attended:       cs.AttendedCount,
 				Leave:          cs.LeaveCount,
 				Absent:         cs.AbsentCount,
+				MakeUp:         cs.MakeUpCount,
 				AttendanceRate: rate,
 			})
 		}
@@ -720,15 +1032,19 @@ func (api *adminAPI) getUserReport(c *gin.Context) {
 			TotalAttended:   s.AttendedCount,
 			TotalLeave:      s.LeaveCount,
 			TotalAbsent:     s.AbsentCount,
+			TotalMakeUp:     s.MakeUpCount,
 			AttendanceRate:  parentRate,
 			Children:        children,
+			Billing:         toUserBilling(resp.Billing[s.UserID]),
 		})
 	}
 
 	model := &admin.UserReportModel{
-		Year:      year,
-		Month:     month,
-		UserStats: userStats,
+		Year:          year,
+		Month:         month,
+		PaymentStatus: paymentStatus,
+		UserStats:     userStats,
+		TeamFilter:    teamFilter,
 	}
 
 	com := templates.Layout(
@@ -748,6 +1064,98 @@ func (api *adminAPI) getUserReport(c *gin.Context) {
 	})
 }
 
+func (api *adminAPI) recordPayment(c *gin.Context) {
+	var req struct {
+		UserName  string `json:"userName"`
+		Method    string `json:"method"`
+		Reference string `json:"reference"`
+		PaidAt    string `json:"paidAt"` // 2006-01-02
+		Note      string `json:"note"`
+		Year      int    `json:"year"`
+		Month     int    `json:"month"`
+		Amount    int    `json:"amount"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	user, err := entity.NewUser(c.Param("userId"), req.UserName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "使用者資料不正確"})
+		return
+	}
+	paidAt := time.Now()
+	if req.PaidAt != "" {
+		paidAt, err = time.ParseInLocation("2006-01-02", req.PaidAt, taipeiLoc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "收款日期格式不正確"})
+			return
+		}
+	}
+
+	_, ucErr := api.recordPaymentUC.Execute(c.Request.Context(), writePayment.ReqRecordPayment{
+		PaidAt:    paidAt,
+		User:      user,
+		Method:    req.Method,
+		Reference: req.Reference,
+		Note:      req.Note,
+		Year:      req.Year,
+		Month:     req.Month,
+		Amount:    req.Amount,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+var billingStatusLabels = map[string]string{
+	entity.BillingStatusPaid.String():    "已繳清",
+	entity.BillingStatusUnpaid.String():  "未繳費",
+	entity.BillingStatusOverdue.String(): "逾期未繳",
+}
+
+func billingStatusLabel(status string) string {
+	if label, ok := billingStatusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+var paymentMethodLabels = map[string]string{
+	entity.PaymentMethodCash.String():         "現金",
+	entity.PaymentMethodBankTransfer.String(): "銀行轉帳",
+}
+
+func toUserBilling(s *entity.BillingStatement) *admin.UserBilling {
+	if s == nil {
+		return nil
+	}
+	payments := make([]*admin.PaymentRecord, 0, len(s.Payments()))
+	for _, p := range s.Payments() {
+		payments = append(payments, &admin.PaymentRecord{
+			PaidAt:    p.PaidAt().In(taipeiLoc).Format("2006/01/02"),
+			Method:    paymentMethodLabels[p.Method().String()],
+			Reference: p.Reference(),
+			Note:      p.Note(),
+			Amount:    p.Amount(),
+		})
+	}
+	// DueAt 為期限隔日 0 點，畫面上顯示最後繳費日
+	return &admin.UserBilling{
+		Status:        s.Status().String(),
+		StatusLabel:   billingStatusLabel(s.Status().String()),
+		DueAt:         s.DueAt().In(taipeiLoc).AddDate(0, 0, -1).Format("2006/01/02"),
+		BillableCount: s.BillableCount(),
+		AmountDue:     s.AmountDue(),
+		AmountPaid:    s.AmountPaid(),
+		Outstanding:   s.Outstanding(),
+		Payments:      payments,
+	}
+}
+
 func isAdmin(c *gin.Context) bool {
 	return mid.IsAdmin(c)
 }
@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/transport/web/handler"
+	readDeadLetter "seanAIgent/internal/booking/usecase/deadletter/read"
+	writeDeadLetter "seanAIgent/internal/booking/usecase/deadletter/write"
+	"seanAIgent/internal/event"
+
+	"github.com/94peter/vulpes/ezapi"
+	"github.com/gin-gonic/gin"
+)
+
+func (api *adminAPI) deadLetterGroup(r ezapi.Router) {
+	r.GET("/v2/admin/events/dead-letters", api.requirePermission(entity.PermEventWrite), api.listDeadLetters)
+	r.GET("/v2/admin/events/dead-letters/:id", api.requirePermission(entity.PermEventWrite), api.getDeadLetter)
+	r.POST("/v2/admin/events/dead-letters/:id/replay", api.requirePermission(entity.PermEventWrite), api.replayDeadLetter)
+	r.DELETE("/v2/admin/events/dead-letters/:id", api.requirePermission(entity.PermEventWrite), api.discardDeadLetter)
+}
+
+type deadLetterResp struct {
+	ID            string `json:"id"`
+	SubscriberID  string `json:"subscriberId"`
+	EventID       string `json:"eventId"`
+	Topic         string `json:"topic"`
+	SchemaVersion int    `json:"schemaVersion"`
+	OccurredAt    string `json:"occurredAt"`
+	Error         string `json:"error"`
+	Attempts      int    `json:"attempts"`
+	FirstFailedAt string `json:"firstFailedAt"`
+	LastFailedAt  string `json:"lastFailedAt"`
+	// Data 只在查詢單筆時回傳
+	Data string `json:"data,omitempty"`
+}
+
+func newDeadLetterResp(dl event.DeadLetter, withData bool) deadLetterResp {
+	resp := deadLetterResp{
+		ID:            dl.ID,
+		SubscriberID:  dl.SubscriberID,
+		EventID:       dl.EventID,
+		Topic:         dl.Topic,
+		SchemaVersion: dl.Event().Version(),
+		OccurredAt:    dl.OccurredAt.Format(time.RFC3339),
+		Error:         dl.Error,
+		Attempts:      dl.Attempts,
+		FirstFailedAt: dl.FirstFailedAt.Format(time.RFC3339),
+		LastFailedAt:  dl.LastFailedAt.Format(time.RFC3339),
+	}
+	if withData {
+		resp.Data = string(dl.Data)
+	}
+	return resp
+}
+
+// listDeadLetters 查詢沒有登入頁面可導向，未登入直接回 401
+func (api *adminAPI) listDeadLetters(c *gin.Context) {
+	if getUserID(c) == "" {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	letters, err := api.queryDeadLettersUC.Execute(c.Request.Context(), readDeadLetter.ReqQueryDeadLetters{
+		SubscriberID: c.Query("subscriber"),
+		Topic:        c.Query("topic"),
+		Limit:        limit,
+	})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+	items := make([]deadLetterResp, 0, len(letters))
+	for _, dl := range letters {
+		items = append(items, newDeadLetterResp(dl, false))
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "items": items})
+}
+
+func (api *adminAPI) getDeadLetter(c *gin.Context) {
+	if getUserID(c) == "" {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	dl, err := api.getDeadLetterUC.Execute(c.Request.Context(), readDeadLetter.ReqGetDeadLetter{ID: c.Param("id")})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "item": newDeadLetterResp(*dl, true)})
+}
+
+func (api *adminAPI) replayDeadLetter(c *gin.Context) {
+	_, err := api.replayDeadLetterUC.Execute(c.Request.Context(), writeDeadLetter.ReqReplayDeadLetter{ID: c.Param("id")})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (api *adminAPI) discardDeadLetter(c *gin.Context) {
+	_, err := api.discardDeadLetterUC.Execute(c.Request.Context(), writeDeadLetter.ReqDiscardDeadLetter{ID: c.Param("id")})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/transport/util/lineutil"
+	"seanAIgent/internal/booking/transport/web/handler"
+	readEventLog "seanAIgent/internal/booking/usecase/eventlog/read"
+	writeEventLog "seanAIgent/internal/booking/usecase/eventlog/write"
+	"seanAIgent/internal/event"
+	"seanAIgent/templates"
+	"seanAIgent/templates/admin"
+
+	"github.com/94peter/vulpes/ezapi"
+	"github.com/gin-gonic/gin"
+)
+
+func (api *adminAPI) eventConsoleGroup(r ezapi.Router) {
+	r.GET("/v2/admin/events", api.requirePermission(entity.PermEventWrite), api.getEventConsole)
+	r.GET("/:lang/v2/admin/events", api.requirePermission(entity.PermEventWrite), api.getEventConsole)
+	r.GET("/v2/admin/events/logs", api.requirePermission(entity.PermEventWrite), api.listEvents)
+	r.GET("/v2/admin/events/logs/:id", api.requirePermission(entity.PermEventWrite), api.getEvent)
+	r.POST("/v2/admin/events/logs/:id/redispatch", api.requirePermission(entity.PermEventWrite), api.redispatchEvent)
+}
+
+type eventResp struct {
+	ID            string `json:"id"`
+	Topic         string `json:"topic"`
+	SchemaVersion int    `json:"schemaVersion"`
+	OccurredAt    string `json:"occurredAt"`
+}
+
+func newEventResp(e event.Event) eventResp {
+	return eventResp{
+		ID:            e.ID(),
+		Topic:         e.Topic(),
+		SchemaVersion: e.Version(),
+		OccurredAt:    e.OccurredAt().In(taipeiLoc).Format(time.DateTime),
+	}
+}
+
+func (api *adminAPI) getEventConsole(c *gin.Context) {
+	lineliffid := lineutil.GetAdminDashboardLiffId()
+	if !checkUser(c, lineliffid) {
+		return
+	}
+	ctx := c.Request.Context()
+	statuses, err := api.querySubscriberProgressUC.Execute(ctx, readEventLog.ReqQuerySubscriberProgress{})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+
+	model := &admin.EventsPageModel{Subscribers: make([]*admin.EventSubscriberRow, 0, len(statuses))}
+	for _, s := range statuses {
+		row := &admin.EventSubscriberRow{
+			ID:          s.SubscriberID,
+			Topic:       s.Topic,
+			Registered:  s.Registered,
+			LastEventID: s.LastEventID,
+			Pending:     s.Pending,
+			Lag:         formatLag(s.Lag),
+		}
+		if !s.LastEventAt.IsZero() {
+			row.LastEventAt = s.LastEventAt.In(taipeiLoc).Format("2006/01/02 15:04:05")
+		}
+		if !s.UpdatedAt.IsZero() {
+			row.UpdatedAt = s.UpdatedAt.In(taipeiLoc).Format("2006/01/02 15:04:05")
+		}
+		model.Subscribers = append(model.Subscribers, row)
+		if s.Topic != "" && !slices.Contains(model.Topics, s.Topic) {
+			model.Topics = append(model.Topics, s.Topic)
+		}
+	}
+	slices.Sort(model.Topics)
+
+	com := templates.Layout(
+		admin.AdminEvents(model),
+		lineliffid,
+		&templates.OgMeta{
+			Title:       "事件紀錄 | Sean AIgent",
+			Description: "查詢事件紀錄與訂閱者進度",
+			Image:       "",
+		},
+	)
+
+	c.Render(http.StatusOK, handler.Renderer{
+		Ctx:       ctx,
+		Status:    http.StatusOK,
+		Component: com,
+	})
+}
+
+// formatLag 沒有待處理事件時顯示為空
+func formatLag(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.Round(time.Second).String()
+}
+
+// listEvents from、to 為台北時間的日期 (YYYY-MM-DD)，to 當天包含在內
+func (api *adminAPI) listEvents(c *gin.Context) {
+	if getUserID(c) == "" {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	req := readEventLog.ReqQueryEvents{
+		Topic:     c.Query("topic"),
+		UserID:    c.Query("user"),
+		BookingID: c.Query("booking"),
+		BeforeID:  c.Query("before"),
+	}
+	req.Limit, _ = strconv.Atoi(c.Query("limit"))
+	if from := c.Query("from"); from != "" {
+		t, err := time.ParseInLocation(time.DateOnly, from, taipeiLoc)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		req.From = t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.ParseInLocation(time.DateOnly, to, taipeiLoc)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		req.To = t.AddDate(0, 0, 1)
+	}
+
+	resp, ucErr := api.queryEventsUC.Execute(c.Request.Context(), req)
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+	items := make([]eventResp, 0, len(resp.Events))
+	for _, e := range resp.Events {
+		items = append(items, newEventResp(e))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"items":     items,
+		"next":      resp.NextBeforeID,
+		"truncated": resp.Truncated,
+	})
+}
+
+func (api *adminAPI) getEvent(c *gin.Context) {
+	if getUserID(c) == "" {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	detail, err := api.getEventUC.Execute(c.Request.Context(), readEventLog.ReqGetEvent{ID: c.Param("id")})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+	// 非 JSON 的自訂格式 (Marshaler) 原樣顯示
+	payload := string(detail.Payload)
+	var buf bytes.Buffer
+	if json.Indent(&buf, detail.Payload, "", "  ") == nil {
+		payload = buf.String()
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"item":           newEventResp(detail.Event),
+		"currentVersion": detail.CurrentVersion,
+		"payload":        payload,
+		"upcastError":    detail.UpcastErr,
+	})
+}
+
+type redispatchEventReq struct {
+	SubscriberID string `json:"subscriberId"`
+}
+
+func (api *adminAPI) redispatchEvent(c *gin.Context) {
+	var req redispatchEventReq
+	if err := c.ShouldBindJSON(&req); err != nil || req.SubscriberID == "" {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	_, err := api.redispatchEventUC.Execute(c.Request.Context(), writeEventLog.ReqRedispatchEvent{
+		EventID:      c.Param("id"),
+		SubscriberID: req.SubscriberID,
+	})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/transport/util/lineutil"
+	"seanAIgent/internal/booking/transport/web/handler"
+	uccore "seanAIgent/internal/booking/usecase/core"
+	readRole "seanAIgent/internal/booking/usecase/role/read"
+	writeRole "seanAIgent/internal/booking/usecase/role/write"
+	"seanAIgent/templates"
+	"seanAIgent/templates/admin"
+
+	"github.com/94peter/vulpes/ezapi"
+	"github.com/gin-gonic/gin"
+)
+
+func (api *adminAPI) roleGroup(r ezapi.Router) {
+	r.GET("/v2/admin/roles", api.requirePermission(entity.PermRoleWrite), api.getRoles)
+	r.GET("/:lang/v2/admin/roles", api.requirePermission(entity.PermRoleWrite), api.getRoles)
+	r.PUT("/v2/admin/roles/:userId", api.requirePermission(entity.PermRoleWrite), api.assignRoles)
+	r.DELETE("/v2/admin/roles/:userId", api.requirePermission(entity.PermRoleWrite), api.removeRoles)
+}
+
+// requirePermission 後台路由的權限檢查，放行的請求一定帶有 Actor；用例在沒有 Actor 時視為系統操作，
+// 未登入的請求一律以 401 拒絕，GET 仍回傳 LIFF 頁面讓瀏覽器登入後重新載入。
+// 全域中介層不查詢團隊，權限不足時再以團隊教練身分解析一次
+func (api *adminAPI) requirePermission(perms ...entity.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := getUserID(c)
+		if userID == "" {
+			if c.Request.Method == http.MethodGet {
+				renderLiffLogin(c, lineutil.GetAdminDashboardLiffId(), http.StatusUnauthorized)
+				c.Abort()
+				return
+			}
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		ctx := c.Request.Context()
+		if actor, ok := uccore.ActorFromContext(ctx); ok && actor.Can(perms...) {
+			c.Next()
+			return
+		}
+		actor, err := api.resolveActorUC.Execute(ctx, readRole.ReqResolveActor{
+			UserID:        userID,
+			IsLineAdmin:   isAdmin(c),
+			WithTeamRoles: true,
+		})
+		if err != nil {
+			handler.ErrorHandler(c, err)
+			c.Abort()
+			return
+		}
+		if !actor.Can(perms...) {
+			handler.ErrorHandler(c, uccore.ErrPermissionRequired)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(uccore.WithActor(ctx, actor))
+		c.Next()
+	}
+}
+
+// actorCan 取得 requirePermission 解析後的權限
+func actorCan(c *gin.Context, perms ...entity.Permission) bool {
+	actor, ok := uccore.ActorFromContext(c.Request.Context())
+	if !ok {
+		return isAdmin(c)
+	}
+	return actor.Can(perms...)
+}
+
+func (api *adminAPI) getRoles(c *gin.Context) {
+	lineliffid := lineutil.GetAdminDashboardLiffId()
+	if !checkUser(c, lineliffid) {
+		return
+	}
+	ctx := c.Request.Context()
+	userRoles, err := api.queryUserRolesUC.Execute(ctx, readRole.ReqQueryUserRoles{})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+
+	model := &admin.RolesPageModel{
+		Users:   make([]*admin.UserRolesRow, 0, len(userRoles)),
+		Options: make([]*admin.RoleOption, 0, len(entity.AllRoles())),
+	}
+	for _, r := range entity.AllRoles() {
+		option := &admin.RoleOption{Role: r.String(), Label: r.Label()}
+		for _, p := range r.Permissions() {
+			option.Permissions = append(option.Permissions, string(p))
+		}
+		model.Options = append(model.Options, option)
+	}
+	for _, u := range userRoles {
+		row := &admin.UserRolesRow{
+			UserID:    u.User().UserID(),
+			UserName:  u.User().UserName(),
+			UpdatedBy: u.UpdatedBy(),
+			UpdatedAt: u.UpdatedAt().In(taipeiLoc).Format("2006/01/02 15:04"),
+		}
+		for _, r := range u.Roles() {
+			row.Roles = append(row.Roles, r.String())
+		}
+		model.Users = append(model.Users, row)
+	}
+
+	com := templates.Layout(
+		admin.AdminRoles(model),
+		lineliffid,
+		&templates.OgMeta{
+			Title:       "角色與權限 | Sean AIgent",
+			Description: "指派後台角色",
+			Image:       "",
+		},
+	)
+
+	c.Render(http.StatusOK, handler.Renderer{
+		Ctx:       ctx,
+		Status:    http.StatusOK,
+		Component: com,
+	})
+}
+
+func (api *adminAPI) assignRoles(c *gin.Context) {
+	var req struct {
+		UserName string   `json:"userName"`
+		Roles    []string `json:"roles"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	userName := strings.TrimSpace(req.UserName)
+	if userName == "" {
+		userName = c.Param("userId")
+	}
+	user, err := entity.NewUser(c.Param("userId"), userName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "使用者資料不正確"})
+		return
+	}
+
+	_, ucErr := api.assignUserRolesUC.Execute(c.Request.Context(), writeRole.ReqAssignUserRoles{
+		User:       user,
+		OperatorID: getUserID(c),
+		Roles:      req.Roles,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (api *adminAPI) removeRoles(c *gin.Context) {
+	_, ucErr := api.removeUserRolesUC.Execute(c.Request.Context(), writeRole.ReqRemoveUserRoles{
+		UserID:     c.Param("userId"),
+		OperatorID: getUserID(c),
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"net/http"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/transport/web/handler"
+	readSeries "seanAIgent/internal/booking/usecase/series/read"
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
+
+	"github.com/94peter/vulpes/ezapi"
+	"github.com/gin-gonic/gin"
+)
+
+func (api *adminAPI) seriesGroup(r ezapi.Router) {
+	r.GET("/v2/admin/series", api.requirePermission(entity.PermTrainingWrite), api.listSeries)
+	r.PUT("/v2/admin/series/:seriesId", api.requirePermission(entity.PermTrainingWrite), api.updateSeries)
+	r.DELETE("/v2/admin/series/:seriesId", api.requirePermission(entity.PermTrainingWrite), api.deleteSeries)
+}
+
+type seriesResp struct {
+	ID         string   `json:"id"`
+	CoachID    string   `json:"coachId"`
+	Location   string   `json:"location"`
+	Timezone   string   `json:"timezone"`
+	Frequency  string   `json:"frequency"`
+	StartDate  string   `json:"startDate"`
+	StartTime  string   `json:"startTime"`
+	EndTime    string   `json:"endTime"`
+	Until      string   `json:"until"`
+	Weekdays   []int    `json:"weekdays"`
+	Exceptions []string `json:"exceptions"`
+	Capacity   int      `json:"capacity"`
+	Count      int      `json:"count"`
+}
+
+func newSeriesResp(s *entity.TrainingSeries) seriesResp {
+	rule := s.Rule()
+	weekdays := make([]int, 0, len(rule.Weekdays()))
+	for _, d := range rule.Weekdays() {
+		weekdays = append(weekdays, int(d))
+	}
+	return seriesResp{
+		ID:         s.ID(),
+		CoachID:    s.CoachID(),
+		Location:   s.Location(),
+		Timezone:   s.Timezone(),
+		Frequency:  string(rule.Frequency()),
+		StartDate:  s.StartDate(),
+		StartTime:  s.StartClock(),
+		EndTime:    s.EndClock(),
+		Until:      rule.Until(),
+		Weekdays:   weekdays,
+		Exceptions: s.Exceptions(),
+		Capacity:   s.Capacity(),
+		Count:      rule.Count(),
+	}
+}
+
+type skippedOccurrenceResp struct {
+	Date        string `json:"date"`
+	TrainDateID string `json:"trainDateId"`
+	Reason      string `json:"reason"`
+}
+
+func newSkippedOccurrenceResps(skipped []writeSeries.SkippedOccurrence) []skippedOccurrenceResp {
+	resps := make([]skippedOccurrenceResp, 0, len(skipped))
+	for _, s := range skipped {
+		resps = append(resps, skippedOccurrenceResp{Date: s.Date, TrainDateID: s.TrainDateID, Reason: s.Reason})
+	}
+	return resps
+}
+
+type updateSeriesReq struct {
+	OccurrenceDate string `json:"occurrenceDate"`
+	Scope          string `json:"scope"`
+	Location       string `json:"location"`
+	StartTime      string `json:"startTime"`
+	EndTime        string `json:"endTime"`
+	Capacity       int    `json:"capacity"`
+}
+
+type deleteSeriesReq struct {
+	OccurrenceDate string `json:"occurrenceDate"`
+	Scope          string `json:"scope"`
+}
+
+// listSeries 未指定 coachId 時查詢登入者自己的系列課程
+func (api *adminAPI) listSeries(c *gin.Context) {
+	coachID := c.Query("coachId")
+	if coachID == "" {
+		coachID = getUserID(c)
+	}
+	seriesList, err := api.queryTrainingSeriesUC.Execute(c.Request.Context(), readSeries.ReqQueryTrainingSeries{
+		CoachID: coachID,
+	})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+
+	resps := make([]seriesResp, 0, len(seriesList))
+	for _, s := range seriesList {
+		resps = append(resps, newSeriesResp(s))
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "series": resps})
+}
+
+// updateSeries 管理員不限定教練，可修改任何人的系列課程
+func (api *adminAPI) updateSeries(c *gin.Context) {
+	var req updateSeriesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	resp, ucErr := api.updateTrainingSeriesUC.Execute(c.Request.Context(), writeSeries.ReqUpdateTrainingSeries{
+		SeriesID:       c.Param("seriesId"),
+		OccurrenceDate: req.OccurrenceDate,
+		Scope:          writeSeries.SeriesEditScope(req.Scope),
+		Location:       req.Location,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Capacity:       req.Capacity,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"series":  newSeriesResp(resp.Series),
+		"updated": len(resp.Updated),
+		"created": len(resp.Created),
+		"skipped": newSkippedOccurrenceResps(resp.Skipped),
+	})
+}
+
+func (api *adminAPI) deleteSeries(c *gin.Context) {
+	var req deleteSeriesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	resp, ucErr := api.deleteTrainingSeriesUC.Execute(c.Request.Context(), writeSeries.ReqDeleteTrainingSeries{
+		SeriesID:       c.Param("seriesId"),
+		OccurrenceDate: req.OccurrenceDate,
+		Scope:          writeSeries.SeriesEditScope(req.Scope),
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"deleted": len(resp.Deleted),
+		"kept":    newSkippedOccurrenceResps(resp.Kept),
+	})
+}
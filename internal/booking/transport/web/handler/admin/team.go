@@ -0,0 +1,271 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/transport/util/lineutil"
+	"seanAIgent/internal/booking/transport/web/handler"
+	uccore "seanAIgent/internal/booking/usecase/core"
+	readTeam "seanAIgent/internal/booking/usecase/team/read"
+	writeTeam "seanAIgent/internal/booking/usecase/team/write"
+	"seanAIgent/templates"
+	"seanAIgent/templates/admin"
+
+	"github.com/94peter/vulpes/ezapi"
+	"github.com/94peter/vulpes/log"
+	"github.com/gin-gonic/gin"
+)
+
+func (api *adminAPI) teamGroup(r ezapi.Router) {
+	r.GET("/v2/admin/teams", api.requirePermission(entity.PermTeamWrite), api.getTeams)
+	r.GET("/:lang/v2/admin/teams", api.requirePermission(entity.PermTeamWrite), api.getTeams)
+	r.POST("/v2/admin/teams", api.requirePermission(entity.PermTeamWrite), api.createTeam)
+	r.PUT("/v2/admin/teams/:teamId", api.requirePermission(entity.PermTeamWrite), api.updateTeam)
+	r.DELETE("/v2/admin/teams/:teamId", api.requirePermission(entity.PermTeamWrite), api.deleteTeam)
+	r.POST("/v2/admin/teams/:teamId/members", api.requirePermission(entity.PermTeamWrite), api.updateTeamMembers)
+}
+
+// checkTeamUser 具 team:all 權限可切換全部團隊；其餘使用者若為團隊教練，查詢會被限定在自己負責的團隊
+func (api *adminAPI) checkTeamUser(c *gin.Context, lineliffid string) (context.Context, *admin.TeamFilterModel, bool) {
+	if !checkUser(c, lineliffid) {
+		return nil, nil, false
+	}
+	return api.resolveTeamScope(c)
+}
+
+func (api *adminAPI) resolveTeamScope(c *gin.Context) (context.Context, *admin.TeamFilterModel, bool) {
+	ctx := c.Request.Context()
+	teamID := c.Query("team")
+	if actorCan(c, entity.PermTeamAll) {
+		teams, err := api.queryTeamsUC.Execute(ctx, readTeam.ReqQueryTeams{})
+		if err != nil {
+			// 團隊清單僅用於篩選，查詢失敗不影響全域管理員
+			log.Errorf("query teams fail: %v", err)
+		}
+		scoped, ucErr := uccore.ScopeToTeam(ctx, teamID)
+		if ucErr != nil {
+			handler.ErrorHandler(c, ucErr)
+			return nil, nil, false
+		}
+		return scoped, &admin.TeamFilterModel{
+			Teams:      toTeamOptions(teams),
+			TeamID:     teamID,
+			CanViewAll: true,
+		}, true
+	}
+
+	userID := getUserID(c)
+	if userID == "" {
+		return nil, nil, false
+	}
+	teams, err := api.queryTeamsUC.Execute(ctx, readTeam.ReqQueryTeams{CoachUserID: userID})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return nil, nil, false
+	}
+	if len(teams) == 0 {
+		// 有權限但沒有負責的團隊，查詢結果為空；需看全部資料的角色應具 team:all 權限
+		return uccore.ScopeToNoTeam(ctx), &admin.TeamFilterModel{}, true
+	}
+	selected := teams[0].ID()
+	for _, t := range teams {
+		if t.ID() == teamID {
+			selected = teamID
+			break
+		}
+	}
+	scoped, ucErr := uccore.ScopeToTeam(ctx, selected)
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return nil, nil, false
+	}
+	return scoped, &admin.TeamFilterModel{
+		Teams:  toTeamOptions(teams),
+		TeamID: selected,
+	}, true
+}
+
+func toTeamOptions(teams []*entity.Team) []*admin.TeamOption {
+	options := make([]*admin.TeamOption, 0, len(teams))
+	for _, t := range teams {
+		options = append(options, &admin.TeamOption{ID: t.ID(), Name: t.Name()})
+	}
+	return options
+}
+
+func teamNames(filter *admin.TeamFilterModel) map[string]string {
+	names := make(map[string]string, len(filter.Teams))
+	for _, t := range filter.Teams {
+		names[t.ID] = t.Name
+	}
+	return names
+}
+
+func (api *adminAPI) getTeams(c *gin.Context) {
+	lineliffid := lineutil.GetAdminDashboardLiffId()
+	if !checkUser(c, lineliffid) {
+		return
+	}
+	ctx := c.Request.Context()
+	teams, err := api.queryTeamsUC.Execute(ctx, readTeam.ReqQueryTeams{})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+
+	model := &admin.TeamsPageModel{Teams: make([]*admin.TeamRow, 0, len(teams))}
+	for _, t := range teams {
+		row := &admin.TeamRow{
+			ID:            t.ID(),
+			Name:          t.Name(),
+			HeadCoachID:   t.HeadCoach().UserID(),
+			HeadCoachName: t.HeadCoach().UserName(),
+			LeaveApproval: t.RequiresLeaveApproval(),
+		}
+		for _, a := range t.Assistants() {
+			row.Assistants = append(row.Assistants, &admin.TeamCoach{UserID: a.UserID(), UserName: a.UserName()})
+		}
+		members, err := api.queryTeamMembersUC.Execute(ctx, readTeam.ReqQueryTeamMembers{TeamID: t.ID()})
+		if err != nil {
+			log.Errorf("query team members fail: %v", err)
+		}
+		for _, m := range members {
+			row.Members = append(row.Members, &admin.TeamMember{
+				StudentID:  m.ID(),
+				Name:       m.DisplayName(),
+				ParentName: m.Parent().UserName(),
+			})
+		}
+		model.Teams = append(model.Teams, row)
+	}
+
+	com := templates.Layout(
+		admin.AdminTeams(model),
+		lineliffid,
+		&templates.OgMeta{
+			Title:       "團隊管理 | Sean AIgent",
+			Description: "管理團隊教練與成員",
+			Image:       "",
+		},
+	)
+
+	c.Render(http.StatusOK, handler.Renderer{
+		Ctx:       ctx,
+		Status:    http.StatusOK,
+		Component: com,
+	})
+}
+
+type teamInput struct {
+	Name          string             `json:"name"`
+	HeadCoachID   string             `json:"headCoachId"`
+	HeadCoachName string             `json:"headCoachName"`
+	Assistants    []*admin.TeamCoach `json:"assistants"`
+	LeaveApproval bool               `json:"leaveApproval"`
+}
+
+func (in *teamInput) coaches() (entity.User, []entity.User, error) {
+	headCoach, err := entity.NewUser(in.HeadCoachID, in.HeadCoachName)
+	if err != nil {
+		return entity.User{}, nil, err
+	}
+	assistants := make([]entity.User, 0, len(in.Assistants))
+	for _, a := range in.Assistants {
+		assistant, err := entity.NewUser(a.UserID, a.UserName)
+		if err != nil {
+			return entity.User{}, nil, err
+		}
+		assistants = append(assistants, assistant)
+	}
+	return headCoach, assistants, nil
+}
+
+func (api *adminAPI) createTeam(c *gin.Context) {
+	var req teamInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	headCoach, assistants, err := req.coaches()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "教練資料不正確"})
+		return
+	}
+
+	team, ucErr := api.createTeamUC.Execute(c.Request.Context(), writeTeam.ReqCreateTeam{
+		HeadCoach:     headCoach,
+		Name:          req.Name,
+		Assistants:    assistants,
+		LeaveApproval: req.LeaveApproval,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "teamId": team.ID()})
+}
+
+func (api *adminAPI) updateTeam(c *gin.Context) {
+	var req teamInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	headCoach, assistants, err := req.coaches()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "教練資料不正確"})
+		return
+	}
+
+	_, ucErr := api.updateTeamUC.Execute(c.Request.Context(), writeTeam.ReqUpdateTeam{
+		HeadCoach:     headCoach,
+		TeamID:        c.Param("teamId"),
+		Name:          req.Name,
+		Assistants:    assistants,
+		LeaveApproval: req.LeaveApproval,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (api *adminAPI) deleteTeam(c *gin.Context) {
+	_, ucErr := api.deleteTeamUC.Execute(c.Request.Context(), writeTeam.ReqDeleteTeam{
+		TeamID: c.Param("teamId"),
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (api *adminAPI) updateTeamMembers(c *gin.Context) {
+	var req struct {
+		AddStudentIDs    []string `json:"addStudentIds"`
+		RemoveStudentIDs []string `json:"removeStudentIds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	_, ucErr := api.updateTeamMembersUC.Execute(c.Request.Context(), writeTeam.ReqUpdateTeamMembers{
+		TeamID:           c.Param("teamId"),
+		AddStudentIDs:    req.AddStudentIDs,
+		RemoveStudentIDs: req.RemoveStudentIDs,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,207 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/transport/util/lineutil"
+	"seanAIgent/internal/booking/transport/web/handler"
+	readWebhook "seanAIgent/internal/booking/usecase/webhook/read"
+	writeWebhook "seanAIgent/internal/booking/usecase/webhook/write"
+	"seanAIgent/templates"
+	"seanAIgent/templates/admin"
+
+	"github.com/94peter/vulpes/ezapi"
+	"github.com/gin-gonic/gin"
+)
+
+var webhookTopicLabels = map[string]string{
+	domain.TopicAppointmentStatusChanged:  "預約狀態變更",
+	domain.TopicUserStatsRefreshRequested: "學員統計更新",
+	domain.TopicTrainDateCreated:          "新增場次",
+	domain.TopicTrainDateDeleted:          "刪除場次",
+}
+
+func (api *adminAPI) webhookGroup(r ezapi.Router) {
+	r.GET("/v2/admin/webhooks", api.requirePermission(entity.PermWebhookWrite), api.getWebhooks)
+	r.GET("/:lang/v2/admin/webhooks", api.requirePermission(entity.PermWebhookWrite), api.getWebhooks)
+	r.POST("/v2/admin/webhooks", api.requirePermission(entity.PermWebhookWrite), api.createWebhook)
+	r.PUT("/v2/admin/webhooks/:id", api.requirePermission(entity.PermWebhookWrite), api.updateWebhook)
+	r.DELETE("/v2/admin/webhooks/:id", api.requirePermission(entity.PermWebhookWrite), api.deleteWebhook)
+	r.POST("/v2/admin/webhooks/:id/ping", api.requirePermission(entity.PermWebhookWrite), api.pingWebhook)
+	r.GET("/v2/admin/webhooks/:id/deliveries", api.requirePermission(entity.PermWebhookWrite), api.listWebhookDeliveries)
+}
+
+type webhookDeliveryResp struct {
+	ID          string `json:"id"`
+	EventID     string `json:"eventId"`
+	Topic       string `json:"topic"`
+	Error       string `json:"error"`
+	DeliveredAt string `json:"deliveredAt"`
+	Attempt     int    `json:"attempt"`
+	StatusCode  int    `json:"statusCode"`
+	DurationMS  int64  `json:"durationMs"`
+	Succeeded   bool   `json:"succeeded"`
+}
+
+func newWebhookDeliveryResp(d *entity.WebhookDelivery) webhookDeliveryResp {
+	return webhookDeliveryResp{
+		ID:          d.ID,
+		EventID:     d.EventID,
+		Topic:       d.Topic,
+		Error:       d.Error,
+		DeliveredAt: d.DeliveredAt.In(taipeiLoc).Format(time.DateTime),
+		Attempt:     d.Attempt,
+		StatusCode:  d.StatusCode,
+		DurationMS:  d.Duration.Milliseconds(),
+		Succeeded:   d.Succeeded(),
+	}
+}
+
+type webhookReq struct {
+	Name         string   `json:"name"`
+	URL          string   `json:"url"`
+	Topics       []string `json:"topics"`
+	Enabled      bool     `json:"enabled"`
+	RotateSecret bool     `json:"rotateSecret"`
+}
+
+func (api *adminAPI) getWebhooks(c *gin.Context) {
+	lineliffid := lineutil.GetAdminDashboardLiffId()
+	if !checkUser(c, lineliffid) {
+		return
+	}
+	ctx := c.Request.Context()
+	endpoints, err := api.queryWebhookEndpointsUC.Execute(ctx, readWebhook.ReqQueryWebhookEndpoints{})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+
+	model := &admin.WebhooksPageModel{
+		Endpoints: make([]*admin.WebhookRow, 0, len(endpoints)),
+		Topics:    make([]*admin.WebhookTopicOption, 0, len(domain.WebhookTopics())),
+	}
+	for _, t := range domain.WebhookTopics() {
+		model.Topics = append(model.Topics, &admin.WebhookTopicOption{Topic: t, Label: webhookTopicLabels[t]})
+	}
+	for _, e := range endpoints {
+		model.Endpoints = append(model.Endpoints, &admin.WebhookRow{
+			ID:        e.ID(),
+			Name:      e.Name(),
+			URL:       e.URL(),
+			Secret:    e.Secret(),
+			Topics:    e.Topics(),
+			Enabled:   e.Enabled(),
+			UpdatedAt: e.UpdatedAt().In(taipeiLoc).Format("2006/01/02 15:04"),
+		})
+	}
+
+	com := templates.Layout(
+		admin.AdminWebhooks(model),
+		lineliffid,
+		&templates.OgMeta{
+			Title:       "Webhook | Sean AIgent",
+			Description: "管理對外通知端點",
+			Image:       "",
+		},
+	)
+
+	c.Render(http.StatusOK, handler.Renderer{
+		Ctx:       ctx,
+		Status:    http.StatusOK,
+		Component: com,
+	})
+}
+
+func (api *adminAPI) createWebhook(c *gin.Context) {
+	var req webhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	endpoint, ucErr := api.createWebhookEndpointUC.Execute(c.Request.Context(), writeWebhook.ReqCreateWebhookEndpoint{
+		Name:       req.Name,
+		URL:        req.URL,
+		Topics:     req.Topics,
+		OperatorID: getUserID(c),
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": endpoint.ID(), "secret": endpoint.Secret()})
+}
+
+func (api *adminAPI) updateWebhook(c *gin.Context) {
+	var req webhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	endpoint, ucErr := api.updateWebhookEndpointUC.Execute(c.Request.Context(), writeWebhook.ReqUpdateWebhookEndpoint{
+		ID:           c.Param("id"),
+		Name:         req.Name,
+		URL:          req.URL,
+		Topics:       req.Topics,
+		Enabled:      req.Enabled,
+		RotateSecret: req.RotateSecret,
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "secret": endpoint.Secret()})
+}
+
+func (api *adminAPI) deleteWebhook(c *gin.Context) {
+	_, ucErr := api.deleteWebhookEndpointUC.Execute(c.Request.Context(), writeWebhook.ReqDeleteWebhookEndpoint{
+		ID: c.Param("id"),
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// pingWebhook 對方回應失敗時仍回 200，由 delivery.succeeded 判斷結果
+func (api *adminAPI) pingWebhook(c *gin.Context) {
+	delivery, ucErr := api.pingWebhookEndpointUC.Execute(c.Request.Context(), writeWebhook.ReqPingWebhookEndpoint{
+		ID:         c.Param("id"),
+		OperatorID: getUserID(c),
+	})
+	if ucErr != nil {
+		handler.ErrorHandler(c, ucErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "delivery": newWebhookDeliveryResp(delivery)})
+}
+
+func (api *adminAPI) listWebhookDeliveries(c *gin.Context) {
+	if getUserID(c) == "" {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	deliveries, err := api.queryWebhookDeliveriesUC.Execute(c.Request.Context(), readWebhook.ReqQueryWebhookDeliveries{
+		EndpointID: c.Param("id"),
+		Limit:      limit,
+	})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+	items := make([]webhookDeliveryResp, 0, len(deliveries))
+	for _, d := range deliveries {
+		items = append(items, newWebhookDeliveryResp(d))
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "items": items})
+}
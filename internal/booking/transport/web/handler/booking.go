@@ -23,6 +23,7 @@ import (
 	readAppt "seanAIgent/internal/booking/usecase/appointment/read"
 	writeAppt "seanAIgent/internal/booking/usecase/appointment/write"
 	uccore "seanAIgent/internal/booking/usecase/core"
+	readCredit "seanAIgent/internal/booking/usecase/credit/read"
 	readTrain "seanAIgent/internal/booking/usecase/traindate/read"
 	"seanAIgent/internal/util/timeutil"
 	"seanAIgent/templates"
@@ -43,6 +44,7 @@ func NewBookingUseCaseSet(registry *usecase.Registry) BookingUseCaseSet {
 		findNearestTrainByTimeUC: registry.FindNearestTrainByTime,
 		findTranHasApptByIdUC:    registry.FindTrainHasApptsById,
 		checkinUC:                registry.CheckIn,
+		queryCreditLedgerUC:      registry.QueryCreditLedger,
 	}
 }
 
@@ -65,6 +67,7 @@ type BookingUseCaseSet struct {
 	findTranHasApptByIdUC uccore.ReadUseCase[
 		readTrain.ReqFindTrainHasApptsById, *entity.TrainDateHasApptState,
 	]
+	queryCreditLedgerUC readCredit.QueryCreditLedgerUseCase
 }
 
 func NewBookingApi(enableCSRF bool, bookingUseCaseSet BookingUseCaseSet) WebAPI {
@@ -120,6 +123,13 @@ func (api *bookingAPI) getMyBookingsPage(c *gin.Context) {
 
 	viewModel := modelToMyBookingsViewModel(dbBookings.Appts, dbBookings.Cursor)
 	viewModel.EnableCSRF = api.enableCSRF
+	if balance := queryCreditBalance(c, api.queryCreditLedgerUC, userId); balance != nil {
+		viewModel.Credit = &myBookings.CreditBalance{
+			Available:  balance.Available,
+			Reserved:   balance.Reserved,
+			NextExpiry: balance.NextExpiry,
+		}
+	}
 
 	com := templates.Layout(
 		myBookings.MyBookingsPage(viewModel),
@@ -659,7 +669,7 @@ func (api *bookingAPI) getCheckinPage(c *gin.Context) {
 
 	var viewModel *checkin.CheckinPageModel
 	var queryTime time.Time
-	if !isAdmin(c) {
+	if !hasPermission(c, entity.PermAttendanceWrite) {
 		viewModel = &checkin.CheckinPageModel{
 			ErrorMessage: "您沒有權限開啟此頁面。",
 		}
@@ -713,7 +723,7 @@ func (api *bookingAPI) getCheckinPage(c *gin.Context) {
 }
 
 func (api *bookingAPI) submitCheckin(c *gin.Context) {
-	if !isAdmin(c) {
+	if !hasPermission(c, entity.PermAttendanceWrite) {
 		addToastTrigger(c, "權限不足", "您沒有權限進行此操作。", "error")
 		c.Status(http.StatusUnauthorized)
 		return
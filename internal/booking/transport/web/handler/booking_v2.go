@@ -12,8 +12,15 @@ import (
 	"seanAIgent/internal/booking/usecase"
 	readappt "seanAIgent/internal/booking/usecase/appointment/read"
 	writeappt "seanAIgent/internal/booking/usecase/appointment/write"
+	readcredit "seanAIgent/internal/booking/usecase/credit/read"
+	readmakeup "seanAIgent/internal/booking/usecase/makeup/read"
+	writemakeup "seanAIgent/internal/booking/usecase/makeup/write"
 	readstats "seanAIgent/internal/booking/usecase/stats/read"
+	readstudent "seanAIgent/internal/booking/usecase/student/read"
+	writestudent "seanAIgent/internal/booking/usecase/student/write"
 	readtrain "seanAIgent/internal/booking/usecase/traindate/read"
+	readwaitlist "seanAIgent/internal/booking/usecase/waitlist/read"
+	writewaitlist "seanAIgent/internal/booking/usecase/waitlist/write"
 	"seanAIgent/templates"
 	"seanAIgent/templates/forms/booking_v2"
 
@@ -30,10 +37,21 @@ func NewV2BookingUseCaseSet(registry *usecase.Registry) V2BookingUseCaseSet {
 		cancelApptUC:            registry.CancelAppt,
 		submitLeaveUC:           registry.CreateLeave,
 		cancelLeaveUC:           registry.CancelLeave,
+		updateLeaveReasonUC:     registry.UpdateLeaveReason,
 		getUserMonthlyStatsUC:   registry.GetUserMonthlyStats,
 		queryTwoWeeksScheduleUC: registry.QueryTwoWeeksSchedule,
 		queryUserBookingsUC:     registry.QueryUserBookings,
 		userQueryTrainByIDUC:    registry.UserQueryTrainByID,
+		joinWaitlistUC:          registry.JoinWaitlist,
+		leaveWaitlistUC:         registry.LeaveWaitlist,
+		queryWaitlistUC:         registry.QueryWaitlist,
+		queryCreditLedgerUC:     registry.QueryCreditLedger,
+		bookMakeUpUC:            registry.BookMakeUp,
+		queryMakeUpCreditsUC:    registry.QueryMakeUpCredits,
+		queryStudentsUC:         registry.QueryStudents,
+		createStudentUC:         registry.CreateStudent,
+		updateStudentUC:         registry.UpdateStudent,
+		deleteStudentUC:         registry.DeleteStudent,
 		idempotencyManager:      registry.IdempotencyManager,
 	}
 }
@@ -43,10 +61,21 @@ type V2BookingUseCaseSet struct {
 	cancelApptUC            writeappt.CancelApptUseCase
 	submitLeaveUC           writeappt.CreateLeaveUseCase
 	cancelLeaveUC           writeappt.CancelLeaveUseCase
+	updateLeaveReasonUC     writeappt.UpdateLeaveReasonUseCase
 	getUserMonthlyStatsUC   readstats.GetUserMonthlyStatsUseCase
 	queryTwoWeeksScheduleUC readtrain.QueryTwoWeeksScheduleUseCase
 	queryUserBookingsUC     readappt.QueryUserBookingsUseCase
 	userQueryTrainByIDUC    readtrain.UserQueryTrainByIDUseCase
+	joinWaitlistUC          writewaitlist.JoinWaitlistUseCase
+	leaveWaitlistUC         writewaitlist.LeaveWaitlistUseCase
+	queryWaitlistUC         readwaitlist.QueryWaitlistUseCase
+	queryCreditLedgerUC     readcredit.QueryCreditLedgerUseCase
+	bookMakeUpUC            writemakeup.BookMakeUpUseCase
+	queryMakeUpCreditsUC    readmakeup.QueryMakeUpCreditsUseCase
+	queryStudentsUC         readstudent.QueryStudentsUseCase
+	createStudentUC         writestudent.CreateStudentUseCase
+	updateStudentUC         writestudent.UpdateStudentUseCase
+	deleteStudentUC         writestudent.DeleteStudentUseCase
 	idempotencyManager      usecase.IdempotencyManager
 }
 
@@ -74,11 +103,19 @@ func (api *v2BookingAPI) InitRouter(r ezapi.Router) {
 		r.POST("/api/v2/bookings", api.createBookingV2)
 		r.DELETE("/api/v2/bookings/:bookingId", api.cancelBookingV2)
 		r.POST("/api/v2/bookings/:bookingId/leave", api.submitLeaveV2)
+		r.PUT("/api/v2/bookings/:bookingId/leave", api.updateLeaveReasonV2)
 		r.DELETE("/api/v2/bookings/:bookingId/leave", api.cancelLeaveV2)
 		r.GET("/api/v2/my-bookings", api.getMyBookingsV2)
 		r.GET("/api/v2/calendar/weeks", api.getCalendarWeeksV2)
 		r.GET("/api/v2/calendar/stats", api.getCalendarStatsV2)
 		r.GET("/api/v2/calendar/slots/:slotId", api.getSlotInfoV2)
+		r.POST("/api/v2/waitlist", api.joinWaitlistV2)
+		r.DELETE("/api/v2/waitlist/:slotId/entries/:entryId", api.leaveWaitlistV2)
+		r.POST("/api/v2/make-ups", api.createMakeUpV2)
+		r.GET("/api/v2/students", api.listStudentsV2)
+		r.POST("/api/v2/students", api.createStudentV2)
+		r.PUT("/api/v2/students/:studentId", api.updateStudentV2)
+		r.DELETE("/api/v2/students/:studentId", api.deleteStudentV2)
 	})
 }
 
@@ -112,6 +149,8 @@ func (api *v2BookingAPI) getSlotInfoV2(c *gin.Context) {
 			status = "Leave"
 		} else if appt.IsCheckedIn {
 			status = "CheckedIn"
+		} else if appt.IsLeavePending() {
+			status = "LeavePending"
 		} else if time.Now().After(trainDate.EndDate) {
 			status = "Absent"
 		}
@@ -124,6 +163,27 @@ func (api *v2BookingAPI) getSlotInfoV2(c *gin.Context) {
 		})
 	}
 
+	// 額滿時一併列出使用者候補中的學員
+	if userID != "" && trainDate.AvailableCapacity <= 0 {
+		wl, errUC := api.queryWaitlistUC.Execute(c.Request.Context(), readwaitlist.ReqQueryWaitlist{
+			TrainDateID: slotID,
+		})
+		if errUC == nil {
+			for _, entry := range wl.Waiting() {
+				if entry.User().UserID() != userID {
+					continue
+				}
+				attendees = append(attendees, &booking_v2.Attendee{
+					Name:        entry.ChildName(),
+					Status:      "Waitlist",
+					BookingID:   entry.ID(),
+					BookingTime: entry.JoinedAt(),
+					SlotID:      slotID,
+				})
+			}
+		}
+	}
+
 	c.JSON(http.StatusOK, &booking_v2.SlotData{
 		ID:             trainDate.ID,
 		TimeDisplay:    startDate.Format("15:04"),
@@ -177,6 +237,7 @@ func (api *v2BookingAPI) getBookingV2Form(c *gin.Context) {
 	var stats *readstats.UserMonthlyStatsVO
 	var weeks []*readtrain.WeekVO
 	var userBookings *readappt.RespQueryUserBookings
+	students := []*booking_v2.StudentOption{}
 
 	g, gCtx := errgroup.WithContext(ctx)
 
@@ -251,6 +312,24 @@ func (api *v2BookingAPI) getBookingV2Form(c *gin.Context) {
 		return err
 	})
 
+	// 4. Fetch students for quick selection
+	g.Go(func() error {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Errorf("getBookingV2Form students panic: %v", r)
+			}
+		}()
+		if userID == "" {
+			return nil
+		}
+		result, err := api.queryStudentsUC.Execute(gCtx, readstudent.ReqQueryStudents{UserID: userID})
+		if err != nil {
+			return err
+		}
+		students = studentsToOptions(result)
+		return nil
+	})
+
 	if err := g.Wait(); err != nil {
 		c.Error(err)
 		return
@@ -264,7 +343,7 @@ func (api *v2BookingAPI) getBookingV2Form(c *gin.Context) {
 		CurrentUser: &booking_v2.UserContext{
 			DisplayName:      getUserDisplayName(c),
 			UserID:           userID,
-			FrequentChildren: statsToFrequentChildren(stats),
+			Students:    students,
 		},
 		Stats:      statsToStatsSummary(stats),
 		MyBookings: apptsToMyBookingsV2(userBookings.Appts),
@@ -286,10 +365,14 @@ func (api *v2BookingAPI) getBookingV2Form(c *gin.Context) {
 	c.Render(http.StatusOK, r)
 }
 
-func statsToFrequentChildren(stats *readstats.UserMonthlyStatsVO) []string {
-	res := make([]string, 0)
-	for _, child := range stats.Children {
-		res = append(res, child.Name)
+func studentsToOptions(students []*entity.Student) []*booking_v2.StudentOption {
+	res := make([]*booking_v2.StudentOption, 0, len(students))
+	for _, s := range students {
+		res = append(res, &booking_v2.StudentOption{
+			ID:          s.ID(),
+			Name:        s.Name(),
+			DisplayName: s.DisplayName(),
+		})
 	}
 	return res
 }
@@ -348,6 +431,9 @@ func apptsToMyBookingsV2(appts []*entity.AppointmentWithTrainDate) []*booking_v2
 }
 
 func uiApptStatusTransform(appt *entity.AppointmentWithTrainDate) string {
+	if appt.Status == entity.StatusCancelledByCoach.String() {
+		return "CoachCancelled"
+	}
 	if appt.IsOnLeave {
 		return "Leave"
 	}
@@ -357,10 +443,16 @@ func uiApptStatusTransform(appt *entity.AppointmentWithTrainDate) string {
 	if appt.Status == string(entity.StatusAttended) {
 		return "CheckedIn"
 	}
+	if appt.Status == entity.StatusConfirmed.String() && appt.LeaveInfo.Status == string(entity.LeaveStatusPending) {
+		return "LeavePending"
+	}
 	// If the course has ended and user didn't check in or leave, it's Absent
 	if time.Now().After(appt.TrainDate.EndDate) {
 		return "Absent"
 	}
+	if appt.TrainDate.RescheduledAt != nil && appt.CreatedAt.Before(*appt.TrainDate.RescheduledAt) {
+		return "Rescheduled"
+	}
 	return "Booked"
 }
 
@@ -430,6 +522,7 @@ func (api *v2BookingAPI) createBookingV2(c *gin.Context) {
 
 	var req struct {
 		SlotID       string   `json:"slot_id"`
+		StudentIDs   []string `json:"student_ids"`
 		StudentNames []string `json:"student_names"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -452,6 +545,7 @@ func (api *v2BookingAPI) createBookingV2(c *gin.Context) {
 	appts, errUC := api.createApptUC.Execute(c.Request.Context(), writeappt.ReqCreateAppt{
 		TrainDateID: req.SlotID,
 		User:        domainUser,
+		StudentIDs:  req.StudentIDs,
 		ChildNames:  req.StudentNames,
 	})
 	if errUC != nil {
@@ -477,6 +571,64 @@ func (api *v2BookingAPI) createBookingV2(c *gin.Context) {
 	})
 }
 
+// createMakeUpV2 以請假取得的補課資格預約其他場次
+func (api *v2BookingAPI) createMakeUpV2(c *gin.Context) {
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if idempotencyKey != "" && !api.idempotencyManager.CheckAndSet(idempotencyKey) {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": "請求正在處理中，請勿重複送出"})
+		return
+	}
+
+	var isSuccess bool
+	defer func() {
+		if !isSuccess && idempotencyKey != "" {
+			api.idempotencyManager.Delete(idempotencyKey)
+		}
+	}()
+
+	var req struct {
+		SlotID   string `json:"slot_id"`
+		CreditID string `json:"credit_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.SlotID == "" || req.CreditID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+
+	userId := getUserID(c)
+	if userId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "User not logged in"})
+		return
+	}
+
+	domainUser, err := entity.NewUser(userId, getUserDisplayName(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to create user"})
+		return
+	}
+	appt, errUC := api.bookMakeUpUC.Execute(c.Request.Context(), writemakeup.ReqBookMakeUp{
+		User:        domainUser,
+		CreditID:    req.CreditID,
+		TrainDateID: req.SlotID,
+	})
+	if errUC != nil {
+		c.JSON(GetStatus(errUC.Type()), gin.H{"success": false, "message": errUC.Message()})
+		return
+	}
+
+	isSuccess = true
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "補課預約成功",
+		"new_bookings": []gin.H{{
+			"booking_id":   appt.ID(),
+			"name":         appt.ChildName(),
+			"status":       uiBookingStatusTransform(appt),
+			"booking_time": time.Now().Format(time.RFC3339),
+		}},
+	})
+}
+
 func uiBookingStatusTransform(appt *entity.Appointment) string {
 	switch appt.Status() {
 	case entity.StatusConfirmed:
@@ -574,7 +726,7 @@ func (api *v2BookingAPI) submitLeaveV2(c *gin.Context) {
 		return
 	}
 
-	_, errUC := api.submitLeaveUC.Execute(c.Request.Context(), writeappt.ReqCreateLeave{
+	appt, errUC := api.submitLeaveUC.Execute(c.Request.Context(), writeappt.ReqCreateLeave{
 		AppointmentID: bookingID,
 		User:          domainUser,
 		Reason:        req.Reason,
@@ -585,9 +737,14 @@ func (api *v2BookingAPI) submitLeaveV2(c *gin.Context) {
 	}
 
 	isSuccess = true
+	msg := "請假申請已送出"
+	if appt.HasPendingLeave() {
+		msg = "請假申請已送出，待教練核准後生效"
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "請假申請已送出",
+		"message": msg,
+		"pending": appt.HasPendingLeave(),
 	})
 }
 
@@ -635,6 +792,151 @@ func (api *v2BookingAPI) cancelLeaveV2(c *gin.Context) {
 	})
 }
 
+func (api *v2BookingAPI) updateLeaveReasonV2(c *gin.Context) {
+	// 檢查冪等性 Key
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if idempotencyKey != "" && !api.idempotencyManager.CheckAndSet(idempotencyKey) {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": "請求正在處理中，請勿重複送出"})
+		return
+	}
+
+	// 旗標與 defer 處理
+	var isSuccess bool
+	defer func() {
+		if !isSuccess && idempotencyKey != "" {
+			api.idempotencyManager.Delete(idempotencyKey)
+		}
+	}()
+
+	bookingID := c.Param("bookingId")
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if bookingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Booking ID required"})
+		return
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "User not logged in"})
+		return
+	}
+
+	appt, errUC := api.updateLeaveReasonUC.Execute(c.Request.Context(), writeappt.ReqUpdateLeaveReason{
+		ApptID: bookingID,
+		UserID: userID,
+		Reason: req.Reason,
+	})
+	if errUC != nil {
+		c.JSON(GetStatus(errUC.Type()), gin.H{"success": false, "message": errUC.Message()})
+		return
+	}
+
+	isSuccess = true
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "請假原因已更新",
+		"reason":  appt.LeaveInfo().Reason(),
+	})
+}
+
+func (api *v2BookingAPI) joinWaitlistV2(c *gin.Context) {
+	// 檢查冪等性 Key
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if idempotencyKey != "" && !api.idempotencyManager.CheckAndSet(idempotencyKey) {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": "請求正在處理中，請勿重複送出"})
+		return
+	}
+
+	// 旗標與 defer 處理
+	var isSuccess bool
+	defer func() {
+		if !isSuccess && idempotencyKey != "" {
+			api.idempotencyManager.Delete(idempotencyKey)
+		}
+	}()
+
+	var req struct {
+		SlotID       string   `json:"slot_id"`
+		StudentNames []string `json:"student_names"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+
+	userId := getUserID(c)
+	if userId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "User not logged in"})
+		return
+	}
+
+	domainUser, err := entity.NewUser(userId, getUserDisplayName(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to create user"})
+		return
+	}
+	entries, errUC := api.joinWaitlistUC.Execute(c.Request.Context(), writewaitlist.ReqJoinWaitlist{
+		TrainDateID: req.SlotID,
+		User:        domainUser,
+		ChildNames:  req.StudentNames,
+	})
+	if errUC != nil {
+		c.JSON(GetStatus(errUC.Type()), gin.H{"success": false, "message": errUC.Message()})
+		return
+	}
+
+	newEntries := make([]gin.H, 0, len(entries))
+	for _, entry := range entries {
+		newEntries = append(newEntries, gin.H{
+			"booking_id":   entry.ID(),
+			"name":         entry.ChildName(),
+			"status":       "Waitlist",
+			"booking_time": entry.JoinedAt().Format(time.RFC3339),
+		})
+	}
+
+	isSuccess = true
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "已加入候補",
+		"new_entries": newEntries,
+	})
+}
+
+func (api *v2BookingAPI) leaveWaitlistV2(c *gin.Context) {
+	slotID := c.Param("slotId")
+	entryID := c.Param("entryId")
+	if slotID == "" || entryID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Waitlist entry required"})
+		return
+	}
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "User not logged in"})
+		return
+	}
+
+	_, errUC := api.leaveWaitlistUC.Execute(c.Request.Context(), writewaitlist.ReqLeaveWaitlist{
+		TrainDateID: slotID,
+		EntryID:     entryID,
+		UserID:      userID,
+	})
+	if errUC != nil {
+		c.JSON(GetStatus(errUC.Type()), gin.H{"success": false, "message": errUC.Message()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "已退出候補",
+	})
+}
+
 func (api *v2BookingAPI) getMyBookingsV2(c *gin.Context) {
 	listType := c.Query("type") // "upcoming" or "history"
 	userID := getUserID(c)
@@ -682,6 +984,9 @@ func (api *v2BookingAPI) getMyBookingsV2(c *gin.Context) {
 		"items":       items,
 		"next_cursor": resp.Cursor,
 		"has_more":    resp.Cursor != "",
+		"credit":      queryCreditBalance(c, api.queryCreditLedgerUC, userID),
+		// 補課資格與預約時間無關，兩個分頁都回傳
+		"make_up_credits": queryMakeUpCredits(c, api.queryMakeUpCreditsUC, userID),
 	})
 }
 
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/94peter/vulpes/log"
+	"github.com/gin-gonic/gin"
+
+	readCredit "seanAIgent/internal/booking/usecase/credit/read"
+	"seanAIgent/internal/util/timeutil"
+)
+
+// creditBalanceView 家長課程包餘額，未購買課程包時為 nil
+type creditBalanceView struct {
+	NextExpiry string `json:"next_expiry,omitempty"`
+	Available  int    `json:"available"`
+	Reserved   int    `json:"reserved"`
+}
+
+// queryCreditBalance 查詢失敗不影響頁面顯示，僅記錄錯誤
+func queryCreditBalance(
+	c *gin.Context, uc readCredit.QueryCreditLedgerUseCase, userID string,
+) *creditBalanceView {
+	if uc == nil || userID == "" {
+		return nil
+	}
+	ledger, err := uc.Execute(c.Request.Context(), readCredit.ReqQueryCreditLedger{UserID: userID})
+	if err != nil {
+		log.Errorf("query credit ledger fail: %v", err)
+		return nil
+	}
+	if ledger == nil {
+		return nil
+	}
+	balance := ledger.Balance(time.Now())
+	view := &creditBalanceView{
+		Available: balance.Available,
+		Reserved:  balance.Reserved,
+	}
+	if balance.NextExpiry != nil {
+		view.NextExpiry = timeutil.ToLocation(*balance.NextExpiry, "Asia/Taipei").Format("2006/01/02")
+	}
+	return view
+}
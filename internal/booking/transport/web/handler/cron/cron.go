@@ -1,6 +1,7 @@
 package cron
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
@@ -8,6 +9,8 @@ import (
 	"seanAIgent/internal/booking/transport/web/handler"
 	"seanAIgent/internal/booking/usecase"
 	writeAppt "seanAIgent/internal/booking/usecase/appointment/write"
+	writeEventLog "seanAIgent/internal/booking/usecase/eventlog/write"
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
 	writeStats "seanAIgent/internal/booking/usecase/stats/write"
 
 	"github.com/94peter/vulpes/ezapi"
@@ -18,12 +21,16 @@ func NewCronApi(registry *usecase.Registry) handler.WebAPI {
 	return &cronAPI{
 		autoMarkAbsentUC:         registry.AutoMarkAbsent,
 		batchSyncMonthlyStatsUC: registry.BatchSyncMonthlyStats,
+		materializeSeriesUC:     registry.MaterializeTrainingSeries,
+		archiveEventsUC:         registry.ArchiveEvents,
 	}
 }
 
 type cronAPI struct {
 	autoMarkAbsentUC         writeAppt.AutoMarkAbsentUseCase
 	batchSyncMonthlyStatsUC writeStats.BatchSyncMonthlyStatsUseCase
+	materializeSeriesUC     writeSeries.MaterializeTrainingSeriesUseCase
+	archiveEventsUC         writeEventLog.ArchiveEventsUseCase
 	once                     sync.Once
 }
 
@@ -32,6 +39,8 @@ func (api *cronAPI) InitRouter(r ezapi.Router) {
 		// 統一以 /cron 開頭
 		r.POST("/cron/mark-absent", api.triggerAutoAbsent)
 		r.POST("/cron/sync-all-stats", api.triggerSyncStats)
+		r.POST("/cron/materialize-series", api.triggerMaterializeSeries)
+		r.POST("/cron/archive-events", api.triggerArchiveEvents)
 	})
 }
 
@@ -87,3 +96,76 @@ func (api *cronAPI) triggerSyncStats(c *gin.Context) {
 		"executed_at":       time.Now().Format(time.RFC3339),
 	})
 }
+
+func (api *cronAPI) triggerMaterializeSeries(c *gin.Context) {
+	if api.materializeSeriesUC == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "materialize training series use case is not initialized"})
+		return
+	}
+
+	var req struct {
+		HorizonWeeks int `json:"horizon_weeks"`
+	}
+	// 沒有傳入時使用預設週數
+	_ = c.ShouldBindJSON(&req)
+
+	resp, err := api.materializeSeriesUC.Execute(c.Request.Context(), writeSeries.ReqMaterializeTrainingSeries{
+		HorizonWeeks: req.HorizonWeeks,
+	})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+
+	skipped := make([]gin.H, 0, len(resp.Skipped))
+	for _, s := range resp.Skipped {
+		skipped = append(skipped, gin.H{
+			"series_id": s.SeriesID,
+			"date":      s.Date,
+			"reason":    s.Reason,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"series_count":      resp.SeriesCount,
+		"created_count":     resp.CreatedCount,
+		"skipped":           skipped,
+		"failed_series_ids": resp.FailedSeriesIDs,
+		"executed_at":       time.Now().Format(time.RFC3339),
+	})
+}
+
+func (api *cronAPI) triggerArchiveEvents(c *gin.Context) {
+	if api.archiveEventsUC == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "archive events use case is not initialized"})
+		return
+	}
+
+	var req struct {
+		OlderThanDays int `json:"older_than_days"`
+	}
+	// 沒有傳入時使用預設天數
+	_ = c.ShouldBindJSON(&req)
+
+	// 首次封存可能超過排程呼叫的逾時，不隨請求取消；中斷時下次執行接續
+	ctx := context.WithoutCancel(c.Request.Context())
+	resp, err := api.archiveEventsUC.Execute(ctx, writeEventLog.ReqArchiveEvents{OlderThanDays: req.OlderThanDays})
+	if err != nil {
+		handler.ErrorHandler(c, err)
+		return
+	}
+
+	files := make([]gin.H, 0, len(resp.Entries))
+	for _, e := range resp.Entries {
+		files = append(files, gin.H{
+			"key":   e.Key,
+			"count": e.Count,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"before":         resp.Before.Format(time.RFC3339),
+		"archived_files": files,
+		"executed_at":    time.Now().Format(time.RFC3339),
+	})
+}
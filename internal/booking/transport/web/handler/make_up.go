@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/94peter/vulpes/log"
+	"github.com/gin-gonic/gin"
+
+	readMakeUp "seanAIgent/internal/booking/usecase/makeup/read"
+	"seanAIgent/internal/util/timeutil"
+)
+
+// makeUpCreditView 家長尚可預約的補課資格
+type makeUpCreditView struct {
+	ID        string `json:"id"`
+	ChildName string `json:"child_name"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// queryMakeUpCredits 查詢失敗不影響頁面顯示，僅記錄錯誤
+func queryMakeUpCredits(
+	c *gin.Context, uc readMakeUp.QueryMakeUpCreditsUseCase, userID string,
+) []*makeUpCreditView {
+	views := make([]*makeUpCreditView, 0)
+	if uc == nil || userID == "" {
+		return views
+	}
+	credits, err := uc.Execute(c.Request.Context(), readMakeUp.ReqQueryMakeUpCredits{
+		UserID:        userID,
+		OnlyAvailable: true,
+	})
+	if err != nil {
+		log.Errorf("query make up credits fail: %v", err)
+		return views
+	}
+	for _, credit := range credits {
+		views = append(views, &makeUpCreditView{
+			ID:        credit.ID(),
+			ChildName: credit.ChildName(),
+			ExpiresAt: timeutil.ToLocation(credit.ExpiresAt(), "Asia/Taipei").Format("2006/01/02"),
+		})
+	}
+	return views
+}
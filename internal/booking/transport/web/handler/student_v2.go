@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	readstudent "seanAIgent/internal/booking/usecase/student/read"
+	writestudent "seanAIgent/internal/booking/usecase/student/write"
+
+	"github.com/gin-gonic/gin"
+)
+
+// studentV2Request 生日格式為 YYYY-MM-DD，可留空
+type studentV2Request struct {
+	Name      string `json:"name"`
+	Nickname  string `json:"nickname"`
+	Birthdate string `json:"birthdate"`
+	Notes     string `json:"notes"`
+}
+
+func (r studentV2Request) birthdate() (*time.Time, bool) {
+	if r.Birthdate == "" {
+		return nil, true
+	}
+	t, err := time.ParseInLocation("2006-01-02", r.Birthdate, taipeiLoc)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
+
+func studentToV2(s *entity.Student) gin.H {
+	birthdate := ""
+	if s.Birthdate() != nil {
+		birthdate = s.Birthdate().In(taipeiLoc).Format("2006-01-02")
+	}
+	return gin.H{
+		"id":           s.ID(),
+		"name":         s.Name(),
+		"nickname":     s.Nickname(),
+		"display_name": s.DisplayName(),
+		"birthdate":    birthdate,
+		"notes":        s.Notes(),
+	}
+}
+
+func (api *v2BookingAPI) listStudentsV2(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "User not logged in"})
+		return
+	}
+	students, errUC := api.queryStudentsUC.Execute(c.Request.Context(), readstudent.ReqQueryStudents{UserID: userID})
+	if errUC != nil {
+		c.JSON(GetStatus(errUC.Type()), gin.H{"success": false, "message": errUC.Message()})
+		return
+	}
+	result := make([]gin.H, 0, len(students))
+	for _, s := range students {
+		result = append(result, studentToV2(s))
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "students": result})
+}
+
+func (api *v2BookingAPI) createStudentV2(c *gin.Context) {
+	var req studentV2Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+	birthdate, ok := req.birthdate()
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "生日格式不正確"})
+		return
+	}
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "User not logged in"})
+		return
+	}
+	parent, err := entity.NewUser(userID, getUserDisplayName(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to create user"})
+		return
+	}
+	student, errUC := api.createStudentUC.Execute(c.Request.Context(), writestudent.ReqCreateStudent{
+		Parent:    parent,
+		Name:      req.Name,
+		Nickname:  req.Nickname,
+		Birthdate: birthdate,
+		Notes:     req.Notes,
+	})
+	if errUC != nil {
+		c.JSON(GetStatus(errUC.Type()), gin.H{"success": false, "message": errUC.Message()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "學員已新增", "student": studentToV2(student)})
+}
+
+func (api *v2BookingAPI) updateStudentV2(c *gin.Context) {
+	var req studentV2Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+	birthdate, ok := req.birthdate()
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "生日格式不正確"})
+		return
+	}
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "User not logged in"})
+		return
+	}
+	student, errUC := api.updateStudentUC.Execute(c.Request.Context(), writestudent.ReqUpdateStudent{
+		UserID:    userID,
+		StudentID: c.Param("studentId"),
+		Name:      req.Name,
+		Nickname:  req.Nickname,
+		Birthdate: birthdate,
+		Notes:     req.Notes,
+	})
+	if errUC != nil {
+		c.JSON(GetStatus(errUC.Type()), gin.H{"success": false, "message": errUC.Message()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "學員已更新", "student": studentToV2(student)})
+}
+
+func (api *v2BookingAPI) deleteStudentV2(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "User not logged in"})
+		return
+	}
+	_, errUC := api.deleteStudentUC.Execute(c.Request.Context(), writestudent.ReqDeleteStudent{
+		UserID:    userID,
+		StudentID: c.Param("studentId"),
+	})
+	if errUC != nil {
+		c.JSON(GetStatus(errUC.Type()), gin.H{"success": false, "message": errUC.Message()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "學員已刪除"})
+}
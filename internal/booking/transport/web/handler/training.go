@@ -3,6 +3,8 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/usecase"
 	uccore "seanAIgent/internal/booking/usecase/core"
+	readTeam "seanAIgent/internal/booking/usecase/team/read"
 	readTrain "seanAIgent/internal/booking/usecase/traindate/read"
 	writeTrain "seanAIgent/internal/booking/usecase/traindate/write"
 	"seanAIgent/internal/booking/transport/util/lineutil"
@@ -26,6 +29,11 @@ func NewTrainingUseCaseSet(registry *usecase.Registry) TrainingUseCaseSet {
 		batchCreateTrainDateUC: registry.BatchCreateTrainDate,
 		deleteTrainDateUC:      registry.DeleteTrainDate,
 		queryFutureTrainUC:     registry.QueryFutureTrain,
+		updateBookingPolicyUC:  registry.UpdateTrainDateBookingPolicy,
+		assignTeamUC:           registry.AssignTrainDateTeam,
+		cancelTrainDateUC:      registry.CancelTrainDate,
+		rescheduleTrainDateUC:  registry.RescheduleTrainDate,
+		queryTeamsUC:           registry.QueryTeams,
 	}
 }
 
@@ -33,6 +41,11 @@ type TrainingUseCaseSet struct {
 	batchCreateTrainDateUC uccore.WriteUseCase[[]writeTrain.ReqCreateTrainDate, []*entity.TrainDate]
 	deleteTrainDateUC      uccore.WriteUseCase[writeTrain.ReqDeleteTrainDate, *entity.TrainDate]
 	queryFutureTrainUC     uccore.ReadUseCase[readTrain.ReqQueryFutureTrain, []*entity.TrainDateHasApptState]
+	updateBookingPolicyUC  writeTrain.UpdateTrainDateBookingPolicyUseCase
+	assignTeamUC           writeTrain.AssignTrainDateTeamUseCase
+	cancelTrainDateUC      writeTrain.CancelTrainDateUseCase
+	rescheduleTrainDateUC  writeTrain.RescheduleTrainDateUseCase
+	queryTeamsUC           readTeam.QueryTeamsUseCase
 }
 
 func NewTrainingApi(enableCSRF bool, schedule TrainingUseCaseSet) WebAPI {
@@ -55,6 +68,10 @@ func (api *trainingAPI) InitRouter(r ezapi.Router) {
 
 		r.POST("/training-date/add", api.addTrainingDate)
 		r.POST("/training-date/delete", api.deleteTrainingDate)
+		r.POST("/training-date/booking-policy", api.updateBookingPolicy)
+		r.POST("/training-date/team", api.assignTeam)
+		r.POST("/training-date/cancel", api.cancelTrainingDate)
+		r.POST("/training-date/reschedule", api.rescheduleTrainingDate)
 	})
 }
 
@@ -68,9 +85,10 @@ func (api *trainingAPI) postErrorHandler(c *gin.Context, showErr error) {
 		return
 	}
 
+	teams := api.teamOptions(c)
 	r := newTemplRenderer(
 		c.Request.Context(), http.StatusOK,
-		manageTrainingDate.AllTrainingDateRow(sliceDbTrainingDateToTrainingDate(dbTrainingDate), true, api.enableCSRF),
+		manageTrainingDate.AllTrainingDateRow(sliceDbTrainingDateToTrainingDate(dbTrainingDate, teams), true, api.enableCSRF, teams),
 	)
 	c.Render(http.StatusOK, r)
 	addToastTrigger(c, "操作失敗", showErr.Error(), "error")
@@ -115,9 +133,10 @@ func (api *trainingAPI) addTrainingDate(c *gin.Context) {
 		return
 	}
 
+	teams := api.teamOptions(c)
 	r := newTemplRenderer(
 		c.Request.Context(), http.StatusOK,
-		manageTrainingDate.AllTrainingDateRow(sliceDbTrainingDateToTrainingDate(trainingDates), true, api.enableCSRF),
+		manageTrainingDate.AllTrainingDateRow(sliceDbTrainingDateToTrainingDate(trainingDates, teams), true, api.enableCSRF, teams),
 	)
 	c.Render(http.StatusOK, r)
 }
@@ -140,9 +159,149 @@ func (api *trainingAPI) deleteTrainingDate(c *gin.Context) {
 	c.Writer.WriteHeader(http.StatusOK)
 }
 
+func (api *trainingAPI) updateBookingPolicy(c *gin.Context) {
+	if !hasPermission(c, entity.PermTrainingWrite) {
+		api.postErrorHandler(c, fmt.Errorf("permission denied"))
+		return
+	}
+	var input manageTrainingDate.InputUpdateBookingPolicy
+	err := c.ShouldBind(&input)
+	if err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	minutes, err := inputBookingPolicyToMinutes(&input.InputBookingPolicy)
+	if err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	_, err = api.updateBookingPolicyUC.Execute(c.Request.Context(), writeTrain.ReqUpdateTrainDateBookingPolicy{
+		TrainDateID:            input.ID,
+		CancelWindowMinutes:    minutes[0],
+		LeaveCutoffMinutes:     minutes[1],
+		CheckInOpenMinutes:     minutes[2],
+		AttendanceAmendMinutes: minutes[3],
+	})
+	if err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	api.renderTrainingDateRow(c, input.ID, "預約規則已更新")
+}
+
+func (api *trainingAPI) assignTeam(c *gin.Context) {
+	if !hasPermission(c, entity.PermTrainingWrite) {
+		api.postErrorHandler(c, fmt.Errorf("permission denied"))
+		return
+	}
+	var input manageTrainingDate.InputAssignTeam
+	if err := c.ShouldBind(&input); err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	_, err := api.assignTeamUC.Execute(c.Request.Context(), writeTrain.ReqAssignTrainDateTeam{
+		TrainDateID: input.ID,
+		TeamID:      input.TeamID,
+	})
+	if err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	api.renderTrainingDateRow(c, input.ID, "團隊設定已更新")
+}
+
+func (api *trainingAPI) rescheduleTrainingDate(c *gin.Context) {
+	if !hasPermission(c, entity.PermTrainingWrite) {
+		api.postErrorHandler(c, fmt.Errorf("permission denied"))
+		return
+	}
+	var input manageTrainingDate.InputRescheduleTrainingDate
+	if err := c.ShouldBind(&input); err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	_, err := api.rescheduleTrainDateUC.Execute(c.Request.Context(), writeTrain.ReqRescheduleTrainDate{
+		TrainDateID: input.ID,
+		OperatorID:  getUserID(c),
+		Location:    input.Location,
+		Date:        input.Date,
+		StartTime:   input.Start,
+		EndTime:     input.End,
+	})
+	if err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	api.renderTrainingDateRow(c, input.ID, "已改期並通知家長")
+}
+
+func (api *trainingAPI) cancelTrainingDate(c *gin.Context) {
+	if !hasPermission(c, entity.PermTrainingWrite) {
+		api.postErrorHandler(c, fmt.Errorf("permission denied"))
+		return
+	}
+	var input manageTrainingDate.InputCancelTrainingDate
+	if err := c.ShouldBind(&input); err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	_, err := api.cancelTrainDateUC.Execute(c.Request.Context(), writeTrain.ReqCancelTrainDate{
+		TrainDateID: input.ID,
+		OperatorID:  getUserID(c),
+		Reason:      input.Reason,
+	})
+	if err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	api.renderTrainingDateRow(c, input.ID, "已停課並通知家長")
+}
+
+// teamOptions 可綁定的團隊，查詢失敗時只記錄錯誤，頁面仍可管理公開場次
+func (api *trainingAPI) teamOptions(c *gin.Context) []*manageTrainingDate.TeamOption {
+	teams, err := api.queryTeamsUC.Execute(c.Request.Context(), readTeam.ReqQueryTeams{})
+	if err != nil {
+		log.Errorf("query teams fail: %v", err)
+		return nil
+	}
+	options := make([]*manageTrainingDate.TeamOption, 0, len(teams))
+	for _, t := range teams {
+		options = append(options, &manageTrainingDate.TeamOption{ID: t.ID(), Name: t.Name()})
+	}
+	return options
+}
+
+// renderTrainingDateRow 重新繪製單一時段，時段已不在未來清單時回傳空內容
+func (api *trainingAPI) renderTrainingDateRow(c *gin.Context, id, successMsg string) {
+	trainingDates, err := api.queryFutureTrainUC.Execute(
+		c.Request.Context(),
+		readTrain.ReqQueryFutureTrain{
+			TimeAfter: time.Now(),
+		},
+	)
+	if err != nil {
+		api.postErrorHandler(c, err)
+		return
+	}
+	teams := api.teamOptions(c)
+	for _, v := range trainingDates {
+		if v.ID != id {
+			continue
+		}
+		r := newTemplRenderer(
+			c.Request.Context(), http.StatusOK,
+			manageTrainingDate.TrainingDateRow(dbTrainingDateToTrainingDate(v, teams), true, api.enableCSRF, teams),
+		)
+		addToastTrigger(c, "操作成功", successMsg, "success")
+		c.Render(http.StatusOK, r)
+		return
+	}
+	c.Writer.WriteHeader(http.StatusOK)
+}
+
 func (api *trainingAPI) getForm(c *gin.Context) {
 	lineliffid := lineutil.GetTrainingDataLiffId()
-	isAdmin := isAdmin(c)
+	isAdmin := hasPermission(c, entity.PermTrainingWrite)
 
 	dbTrainingDate, err := api.queryFutureTrainUC.Execute(
 		c.Request.Context(),
@@ -155,16 +314,21 @@ func (api *trainingAPI) getForm(c *gin.Context) {
 		return
 	}
 
+	var teams []*manageTrainingDate.TeamOption
+	if isAdmin {
+		teams = api.teamOptions(c)
+	}
 	defaultDate := roundUpToNearest5Min(time.Now())
 	com := templates.Layout(
 		manageTrainingDate.MultiTrainingDateForm(
 			&manageTrainingDate.MultiTrainingDateFormModel{
-				Dates: sliceDbTrainingDateToTrainingDate(dbTrainingDate),
+				Dates: sliceDbTrainingDateToTrainingDate(dbTrainingDate, teams),
 				DefaultDate: &manageTrainingDate.InputTrainingDate{
 					Date:  defaultDate.Format("2006-01-02"),
 					Start: defaultDate.Format("15:04"),
 					End:   defaultDate.Add(1 * time.Hour).Format("15:04"),
 				},
+				Teams:      teams,
 				IsAdmin:    isAdmin,
 				LiffId:     lineliffid,
 				EnableCSRF: api.enableCSRF,
@@ -180,17 +344,22 @@ func (api *trainingAPI) getForm(c *gin.Context) {
 	c.Render(http.StatusOK, r)
 }
 
-func sliceDbTrainingDateToTrainingDate(dbTrainingDate []*entity.TrainDateHasApptState) []*manageTrainingDate.TrainingDate {
+func sliceDbTrainingDateToTrainingDate(
+	dbTrainingDate []*entity.TrainDateHasApptState, teams []*manageTrainingDate.TeamOption,
+) []*manageTrainingDate.TrainingDate {
 	trainingDate := make([]*manageTrainingDate.TrainingDate, len(dbTrainingDate))
 	for i, v := range dbTrainingDate {
-		trainingDate[i] = dbTrainingDateToTrainingDate(v)
+		trainingDate[i] = dbTrainingDateToTrainingDate(v, teams)
 	}
 	return trainingDate
 }
 
-func dbTrainingDateToTrainingDate(dbTrainingDate *entity.TrainDateHasApptState) *manageTrainingDate.TrainingDate {
+func dbTrainingDateToTrainingDate(
+	dbTrainingDate *entity.TrainDateHasApptState, teams []*manageTrainingDate.TeamOption,
+) *manageTrainingDate.TrainingDate {
 	startDate := timeutil.ToLocation(dbTrainingDate.StartDate, dbTrainingDate.Timezone)
 	endDate := timeutil.ToLocation(dbTrainingDate.EndDate, dbTrainingDate.Timezone)
+	policy := dbTrainingDate.BookingPolicy.Policy()
 	return &manageTrainingDate.TrainingDate{
 		ID:            dbTrainingDate.ID,
 		Date:          startDate.Format("2006/01/02"),
@@ -200,7 +369,66 @@ func dbTrainingDateToTrainingDate(dbTrainingDate *entity.TrainDateHasApptState)
 		Capacity:      dbTrainingDate.Capacity,
 		BookedCount:   len(dbTrainingDate.UserAppointments),
 		FormattedDate: formattedDate(startDate),
+
+		CancelWindowMinutes:    int(policy.CancelWindow() / time.Minute),
+		LeaveCutoffMinutes:     int(policy.LeaveCutoff() / time.Minute),
+		CheckInOpenMinutes:     int(policy.CheckInOpen() / time.Minute),
+		AttendanceAmendMinutes: int(policy.AttendanceAmend() / time.Minute),
+		HasCustomPolicy:        policy != entity.DefaultBookingPolicy(),
+		TeamID:                 dbTrainingDate.TeamID,
+		TeamName:               teamName(teams, dbTrainingDate.TeamID),
+		IsCancelled:            dbTrainingDate.IsCancelled(),
+		CancelReason:           dbTrainingDate.CancelReason,
+		ISODate:                startDate.Format("2006-01-02"),
+		Rescheduled:            dbTrainingDate.RescheduledAt != nil,
+	}
+}
+
+// teamName 找不到團隊 (已刪除或無權限查詢) 時顯示 ID
+func teamName(teams []*manageTrainingDate.TeamOption, teamID string) string {
+	if teamID == "" {
+		return ""
+	}
+	for _, t := range teams {
+		if t.ID == teamID {
+			return t.Name
+		}
 	}
+	return teamID
+}
+
+// inputBookingPolicyToMinutes 依序回傳可取消、截止請假、開放點名、可補登的分鐘數，需全部填寫
+func inputBookingPolicyToMinutes(input *manageTrainingDate.InputBookingPolicy) ([4]int, error) {
+	var minutes [4]int
+	for i, v := range []string{input.CancelWindow, input.LeaveCutoff, input.CheckInOpen, input.AttendanceAmend} {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || n < 0 {
+			return minutes, fmt.Errorf("預約規則需完整填寫且不可為負數")
+		}
+		minutes[i] = n
+	}
+	return minutes, nil
+}
+
+// inputBookingPolicy 新增時段時的自訂預約規則，未填寫時回傳 nil 以使用系統預設
+func inputBookingPolicy(input *manageTrainingDate.InputBookingPolicy) (*entity.BookingPolicy, error) {
+	if input.IsEmpty() {
+		return nil, nil
+	}
+	minutes, err := inputBookingPolicyToMinutes(input)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := entity.NewBookingPolicy(
+		time.Duration(minutes[0])*time.Minute,
+		time.Duration(minutes[1])*time.Minute,
+		time.Duration(minutes[2])*time.Minute,
+		time.Duration(minutes[3])*time.Minute,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
 }
 
 func inputTrainingDateToReqCreateTrainDateSlice(
@@ -210,6 +438,10 @@ func inputTrainingDateToReqCreateTrainDateSlice(
 	if err != nil {
 		return nil, err
 	}
+	policy, err := inputBookingPolicy(&trainingDate.InputBookingPolicy)
+	if err != nil {
+		return nil, err
+	}
 	dbTrainingDates := make([]writeTrain.ReqCreateTrainDate, len(dateTimeRangeSlice))
 	for i, v := range dateTimeRangeSlice {
 		dbTrainingDate := writeTrain.ReqCreateTrainDate{
@@ -218,6 +450,9 @@ func inputTrainingDateToReqCreateTrainDateSlice(
 			Capacity:  trainingDate.Capacity,
 			StartTime: v.Start,
 			EndTime:   v.End,
+
+			BookingPolicy: policy,
+			TeamID:        trainingDate.TeamID,
 		}
 		dbTrainingDates[i] = dbTrainingDate
 	}
@@ -13,6 +13,7 @@ import (
 	lineMid "github.com/94peter/botreplyer/provider/line/mid"
 	"github.com/94peter/vulpes/ezapi"
 	"github.com/94peter/vulpes/log"
+	"github.com/gin-gonic/gin"
 )
 
 type WebService interface {
@@ -58,7 +59,8 @@ func InitWeb(
 	}
 	cfg.routerGroup = router
 	return &webService{
-		cfg: cfg,
+		cfg:      cfg,
+		actorMid: handler.ActorMiddleware(registry.ResolveActor),
 	}
 }
 
@@ -89,7 +91,8 @@ type Config struct {
 }
 
 type webService struct {
-	cfg Config
+	actorMid gin.HandlerFunc
+	cfg      Config
 }
 
 func (s *webService) Run(ctx context.Context) {
@@ -102,6 +105,7 @@ func (s *webService) Run(ctx context.Context) {
 			lineMid.LineLiff(),
 			lineMid.I18n("zh-tw", locales.LocaleExist),
 			lineMid.CheckAdmin(botreplyer.GetFollowStore()),
+			s.actorMid,
 		),
 		ezapi.WithSession(
 			s.cfg.session.Enable,
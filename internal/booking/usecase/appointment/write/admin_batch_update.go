@@ -23,13 +23,12 @@ type ReqAdminBatchUpdateAttendance struct {
 
 type AdminBatchUpdateAttendanceUseCase core.WriteUseCase[ReqAdminBatchUpdateAttendance, int]
 
-func NewAdminBatchUpdateAttendanceUseCase(repo adminCheckInUseCaseRepo, bus event.Bus) AdminBatchUpdateAttendanceUseCase {
-	return &adminBatchUpdateAttendanceUseCase{repo: repo, bus: bus}
+func NewAdminBatchUpdateAttendanceUseCase(repo adminCheckInUseCaseRepo) AdminBatchUpdateAttendanceUseCase {
+	return &adminBatchUpdateAttendanceUseCase{repo: repo}
 }
 
 type adminBatchUpdateAttendanceUseCase struct {
 	repo adminCheckInUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *adminBatchUpdateAttendanceUseCase) Name() string {
@@ -53,9 +52,10 @@ func (uc *adminBatchUpdateAttendanceUseCase) Execute(ctx context.Context, req Re
 	}
 
 	var toUpdate []*entity.Appointment
-	affectedUsers := make(map[string]struct{})
+	var events []event.Event
 
 	startTime := train.Period().Start()
+	policy := train.BookingPolicy()
 
 	for _, up := range req.Updates {
 		appt, ok := apptMap[up.BookingID]
@@ -67,13 +67,13 @@ func (uc *adminBatchUpdateAttendanceUseCase) Execute(ctx context.Context, req Re
 		var opErr error
 		switch up.Status {
 		case "CheckedIn":
-			opErr = appt.AdminCheckIn(startTime)
+			opErr = appt.AdminCheckIn(startTime, policy)
 		case "Leave":
-			opErr = appt.AdminAppendLeave("教練批次標記請假", startTime)
+			opErr = appt.AdminAppendLeave("教練批次標記請假", startTime, policy)
 		case "Absent":
-			opErr = appt.AdminMarkAsAbsent(startTime)
+			opErr = appt.AdminMarkAsAbsent(startTime, policy)
 		case "Pending":
-			opErr = appt.AdminRestoreFromLeave(startTime)
+			opErr = appt.AdminRestoreFromLeave(startTime, policy)
 		default:
 			continue
 		}
@@ -83,28 +83,26 @@ func (uc *adminBatchUpdateAttendanceUseCase) Execute(ctx context.Context, req Re
 		}
 
 		toUpdate = append(toUpdate, appt)
-		affectedUsers[appt.User().UserID()] = struct{}{}
 
-		// 發送領域事件
-		evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
 			BookingID:  appt.ID(),
 			UserID:     appt.User().UserID(),
 			TrainingID: appt.TrainingID(),
 			OldStatus:  oldStatus,
 			NewStatus:  appt.Status().String(),
 			OccurredAt: time.Now(),
-		})
-		uc.bus.Publish(ctx, evt)
+		}))
 	}
 
 	if len(toUpdate) > 0 {
-		if err := uc.repo.UpdateManyAppts(ctx, toUpdate); err != nil {
-			return 0, ErrCheckInUpdateApptFail.Wrap(err)
-		}
-		// 手動清理快取
-		for uid := range affectedUsers {
-			_ = uc.repo.CleanTrainCache(ctx, uid)
-			_ = uc.repo.CleanStatsCache(ctx, uid, startTime.Year(), int(startTime.Month()))
+		ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+			if err := uc.repo.UpdateManyAppts(ctx, toUpdate); err != nil {
+				return ErrCheckInUpdateApptFail.Wrap(err)
+			}
+			return core.AddEvents(ctx, uc.repo, events...)
+		})
+		if ucErr != nil {
+			return 0, ucErr
 		}
 	}
 
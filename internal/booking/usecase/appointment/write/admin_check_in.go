@@ -24,19 +24,18 @@ type adminCheckInUseCaseRepo interface {
 	repository.IdentityGenerator
 	repository.AppointmentRepository
 	repository.TrainRepository
-	repository.StatsRepository
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
-func NewAdminCheckInUseCase(repo adminCheckInUseCaseRepo, bus event.Bus) AdminCheckInUseCase {
+func NewAdminCheckInUseCase(repo adminCheckInUseCaseRepo) AdminCheckInUseCase {
 	return &adminCheckInUseCase{
 		repo: repo,
-		bus:  bus,
 	}
 }
 
 type adminCheckInUseCase struct {
 	repo adminCheckInUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *adminCheckInUseCase) Name() string {
@@ -62,38 +61,36 @@ func (uc *adminCheckInUseCase) Execute(
 	}
 
 	var updated []*entity.Appointment
-	affectedUserIDs := make(map[string]struct{})
+	var events []event.Event
 
 	for _, id := range req.CheckedInBookingIDs {
 		if a, ok := apptMap[id]; ok {
 			oldStatus := a.Status().String()
-			if err := a.AdminCheckIn(train.Period().Start()); err != nil {
+			if err := a.AdminCheckIn(train.Period().Start(), train.BookingPolicy()); err != nil {
 				return nil, ErrCheckInDomainError.Wrap(err)
 			}
 			updated = append(updated, a)
-			affectedUserIDs[a.User().UserID()] = struct{}{}
 
-			// 發送領域事件
-			evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+			events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
 				BookingID:  a.ID(),
 				UserID:     a.User().UserID(),
 				TrainingID: a.TrainingID(),
 				OldStatus:  oldStatus,
 				NewStatus:  a.Status().String(),
 				OccurredAt: time.Now(),
-			})
-			uc.bus.Publish(ctx, evt)
+			}))
 		}
 	}
 
 	if len(updated) > 0 {
-		if err := uc.repo.UpdateManyAppts(ctx, updated); err != nil {
-			return nil, ErrCheckInUpdateApptFail.Wrap(err)
-		}
-		// 手動清理快取
-		for uid := range affectedUserIDs {
-			_ = uc.repo.CleanTrainCache(ctx, uid)
-			_ = uc.repo.CleanStatsCache(ctx, uid, train.Period().Start().Year(), int(train.Period().Start().Month()))
+		ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+			if err := uc.repo.UpdateManyAppts(ctx, updated); err != nil {
+				return ErrCheckInUpdateApptFail.Wrap(err)
+			}
+			return core.AddEvents(ctx, uc.repo, events...)
+		})
+		if ucErr != nil {
+			return nil, ucErr
 		}
 	}
 
@@ -107,13 +104,12 @@ type ReqAdminToggleCheckIn struct {
 
 type AdminToggleCheckInUseCase core.WriteUseCase[ReqAdminToggleCheckIn, *entity.Appointment]
 
-func NewAdminToggleCheckInUseCase(repo adminCheckInUseCaseRepo, bus event.Bus) AdminToggleCheckInUseCase {
-	return &adminToggleCheckInUseCase{repo: repo, bus: bus}
+func NewAdminToggleCheckInUseCase(repo adminCheckInUseCaseRepo) AdminToggleCheckInUseCase {
+	return &adminToggleCheckInUseCase{repo: repo}
 }
 
 type adminToggleCheckInUseCase struct {
 	repo adminCheckInUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *adminToggleCheckInUseCase) Name() string {
@@ -133,11 +129,11 @@ func (uc *adminToggleCheckInUseCase) Execute(ctx context.Context, req ReqAdminTo
 
 	oldStatus := appt.Status().String()
 	if appt.Status() == entity.StatusAttended {
-		if err := appt.AdminRestoreFromLeave(train.Period().Start()); err != nil {
+		if err := appt.AdminRestoreFromLeave(train.Period().Start(), train.BookingPolicy()); err != nil {
 			return nil, ErrCheckInDomainError.Wrap(err)
 		}
 	} else {
-		if err := appt.AdminCheckIn(train.Period().Start()); err != nil {
+		if err := appt.AdminCheckIn(train.Period().Start(), train.BookingPolicy()); err != nil {
 			if errors.Is(err, entity.ErrAppointmentCheckInNotOpen) {
 				return nil, ErrCheckInNotOpen.Wrap(err)
 			}
@@ -148,15 +144,7 @@ func (uc *adminToggleCheckInUseCase) Execute(ctx context.Context, req ReqAdminTo
 		}
 	}
 
-	if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
-		return nil, ErrCheckInUpdateApptFail.Wrap(err)
-	}
-
-	// 手動清理快取
-	_ = uc.repo.CleanTrainCache(ctx, appt.User().UserID())
-	_ = uc.repo.CleanStatsCache(ctx, appt.User().UserID(), train.Period().Start().Year(), int(train.Period().Start().Month()))
-
-	// 發送領域事件
+	// 更新預約並寫入領域事件
 	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
 		BookingID:  appt.ID(),
 		UserID:     appt.User().UserID(),
@@ -165,7 +153,15 @@ func (uc *adminToggleCheckInUseCase) Execute(ctx context.Context, req ReqAdminTo
 		NewStatus:  appt.Status().String(),
 		OccurredAt: time.Now(),
 	})
-	uc.bus.Publish(ctx, evt)
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
+			return ErrCheckInUpdateApptFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
 
 	return appt, nil
 }
@@ -23,13 +23,12 @@ type ReqAdminCreateWalkIn struct {
 
 type AdminCreateWalkInUseCase core.WriteUseCase[ReqAdminCreateWalkIn, *entity.Appointment]
 
-func NewAdminCreateWalkInUseCase(repo adminCheckInUseCaseRepo, bus event.Bus) AdminCreateWalkInUseCase {
-	return &adminCreateWalkInUseCase{repo: repo, bus: bus}
+func NewAdminCreateWalkInUseCase(repo adminCheckInUseCaseRepo) AdminCreateWalkInUseCase {
+	return &adminCreateWalkInUseCase{repo: repo}
 }
 
 type adminCreateWalkInUseCase struct {
 	repo adminCheckInUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *adminCreateWalkInUseCase) Name() string {
@@ -42,6 +41,9 @@ func (uc *adminCreateWalkInUseCase) Execute(ctx context.Context, req ReqAdminCre
 	if err != nil {
 		return nil, ErrCreateApptTrainDateNotFound.Wrap(err)
 	}
+	if train.IsCancelled() {
+		return nil, ErrCreateApptTrainingCancelled
+	}
 
 	// 2. Prepare User Entity & Validate Contact Info for Guests
 	userID := req.UserID
@@ -78,7 +80,7 @@ func (uc *adminCreateWalkInUseCase) Execute(ctx context.Context, req ReqAdminCre
 
 	oldStatus := appt.Status().String()
 	// 4. Admin auto-checkin (with consolidated constraints)
-	if err := appt.AdminCheckIn(train.Period().Start()); err != nil {
+	if err := appt.AdminCheckIn(train.Period().Start(), train.BookingPolicy()); err != nil {
 		if errors.Is(err, entity.ErrAppointmentCheckInNotOpen) {
 			return nil, ErrWalkInNotOpen.Wrap(err)
 		}
@@ -88,29 +90,49 @@ func (uc *adminCreateWalkInUseCase) Execute(ctx context.Context, req ReqAdminCre
 		return nil, ErrCheckInDomainError.Wrap(err)
 	}
 
-	// 5. Save & Deduct Capacity (Admin version allows overbooking)
-	if err := uc.repo.AdminDeductCapacity(ctx, req.TrainDateID, 1); err != nil {
-		return nil, ErrCreateApptSaveApptFail.Wrap(err)
+	// 5. Save & Deduct Capacity (Admin version allows overbooking)，快取由訂閱者清理
+	now := time.Now()
+	events := []event.Event{
+		event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+			BookingID:  appt.ID(),
+			UserID:     userID,
+			TrainingID: req.TrainDateID,
+			OldStatus:  oldStatus,
+			NewStatus:  appt.Status().String(),
+			OccurredAt: now,
+		}),
+		event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicWalkInCreated, domain.WalkInCreated{
+			BookingID:  appt.ID(),
+			UserID:     userID,
+			TrainingID: req.TrainDateID,
+			ChildName:  req.ChildName,
+			Guest:      isGuest,
+			OccurredAt: now,
+		}),
 	}
-
-	if err := uc.repo.SaveAppointment(ctx, appt); err != nil {
-		return nil, ErrCreateApptSaveApptFail.Wrap(err)
+	if isGuest {
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicGuestBooked, domain.GuestBooked{
+			BookingID:   appt.ID(),
+			UserID:      userID,
+			TrainingID:  req.TrainDateID,
+			ChildName:   req.ChildName,
+			ParentName:  userName,
+			ContactInfo: contactInfo,
+			OccurredAt:  now,
+		}))
 	}
-
-	// 手動清理快取
-	_ = uc.repo.CleanTrainCache(ctx, userID)
-	_ = uc.repo.CleanStatsCache(ctx, userID, train.Period().Start().Year(), int(train.Period().Start().Month()))
-
-	// 發送領域事件
-	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
-		BookingID:  appt.ID(),
-		UserID:     userID,
-		TrainingID: req.TrainDateID,
-		OldStatus:  oldStatus,
-		NewStatus:  appt.Status().String(),
-		OccurredAt: time.Now(),
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.AdminDeductCapacity(ctx, req.TrainDateID, 1); err != nil {
+			return ErrCreateApptSaveApptFail.Wrap(err)
+		}
+		if err := uc.repo.SaveAppointment(ctx, appt); err != nil {
+			return ErrCreateApptSaveApptFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, events...)
 	})
-	uc.bus.Publish(ctx, evt)
+	if ucErr != nil {
+		return nil, ucErr
+	}
 
 	return appt, nil
 }
@@ -17,13 +17,12 @@ type ReqAdminCreateLeave struct {
 
 type AdminCreateLeaveUseCase core.WriteUseCase[ReqAdminCreateLeave, *entity.Appointment]
 
-func NewAdminCreateLeaveUseCase(repo adminCheckInUseCaseRepo, bus event.Bus) AdminCreateLeaveUseCase {
-	return &adminCreateLeaveUseCase{repo: repo, bus: bus}
+func NewAdminCreateLeaveUseCase(repo adminCheckInUseCaseRepo) AdminCreateLeaveUseCase {
+	return &adminCreateLeaveUseCase{repo: repo}
 }
 
 type adminCreateLeaveUseCase struct {
 	repo adminCheckInUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *adminCreateLeaveUseCase) Name() string {
@@ -42,19 +41,11 @@ func (uc *adminCreateLeaveUseCase) Execute(ctx context.Context, req ReqAdminCrea
 	}
 
 	oldStatus := appt.Status().String()
-	if err := appt.AdminAppendLeave(req.Reason, train.Period().Start()); err != nil {
+	if err := appt.AdminAppendLeave(req.Reason, train.Period().Start(), train.BookingPolicy()); err != nil {
 		return nil, core.NewUseCaseError("ADMIN_LEAVE", "DOMAIN_FAIL", "無法執行請假操作", core.ErrInvalidInput).Wrap(err)
 	}
 
-	if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
-		return nil, ErrCheckInUpdateApptFail.Wrap(err)
-	}
-
-	// 手動清理快取
-	_ = uc.repo.CleanTrainCache(ctx, appt.User().UserID())
-	_ = uc.repo.CleanStatsCache(ctx, appt.User().UserID(), train.Period().Start().Year(), int(train.Period().Start().Month()))
-
-	// 發送領域事件
+	// 更新預約並寫入領域事件
 	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
 		BookingID:  appt.ID(),
 		UserID:     appt.User().UserID(),
@@ -63,7 +54,15 @@ func (uc *adminCreateLeaveUseCase) Execute(ctx context.Context, req ReqAdminCrea
 		NewStatus:  appt.Status().String(),
 		OccurredAt: time.Now(),
 	})
-	uc.bus.Publish(ctx, evt)
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
+			return ErrCheckInUpdateApptFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
 
 	return appt, nil
 }
@@ -74,13 +73,12 @@ type ReqAdminRestoreFromLeave struct {
 
 type AdminRestoreFromLeaveUseCase core.WriteUseCase[ReqAdminRestoreFromLeave, *entity.Appointment]
 
-func NewAdminRestoreFromLeaveUseCase(repo adminCheckInUseCaseRepo, bus event.Bus) AdminRestoreFromLeaveUseCase {
-	return &adminRestoreFromLeaveUseCase{repo: repo, bus: bus}
+func NewAdminRestoreFromLeaveUseCase(repo adminCheckInUseCaseRepo) AdminRestoreFromLeaveUseCase {
+	return &adminRestoreFromLeaveUseCase{repo: repo}
 }
 
 type adminRestoreFromLeaveUseCase struct {
 	repo adminCheckInUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *adminRestoreFromLeaveUseCase) Name() string {
@@ -99,19 +97,11 @@ func (uc *adminRestoreFromLeaveUseCase) Execute(ctx context.Context, req ReqAdmi
 	}
 
 	oldStatus := appt.Status().String()
-	if err := appt.AdminRestoreFromLeave(train.Period().Start()); err != nil {
+	if err := appt.AdminRestoreFromLeave(train.Period().Start(), train.BookingPolicy()); err != nil {
 		return nil, core.NewUseCaseError("ADMIN_RESTORE", "DOMAIN_FAIL", "無法還原預約狀態", core.ErrInvalidInput).Wrap(err)
 	}
 
-	if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
-		return nil, ErrCheckInUpdateApptFail.Wrap(err)
-	}
-
-	// 手動清理快取
-	_ = uc.repo.CleanTrainCache(ctx, appt.User().UserID())
-	_ = uc.repo.CleanStatsCache(ctx, appt.User().UserID(), train.Period().Start().Year(), int(train.Period().Start().Month()))
-
-	// 發送領域事件
+	// 更新預約並寫入領域事件
 	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
 		BookingID:  appt.ID(),
 		UserID:     appt.User().UserID(),
@@ -120,7 +110,15 @@ func (uc *adminRestoreFromLeaveUseCase) Execute(ctx context.Context, req ReqAdmi
 		NewStatus:  appt.Status().String(),
 		OccurredAt: time.Now(),
 	})
-	uc.bus.Publish(ctx, evt)
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
+			return ErrCheckInUpdateApptFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
 
 	return appt, nil
 }
@@ -3,6 +3,7 @@ package write
 import (
 	"context"
 	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/usecase/core"
 	"seanAIgent/internal/event"
@@ -15,18 +16,18 @@ type autoMarkAbsentUseCaseRepo interface {
 	repository.TrainRepository
 	repository.AppointmentRepository
 	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
-func NewAutoMarkAbsentUseCase(repo autoMarkAbsentUseCaseRepo, bus event.Bus) AutoMarkAbsentUseCase {
+func NewAutoMarkAbsentUseCase(repo autoMarkAbsentUseCaseRepo) AutoMarkAbsentUseCase {
 	return &autoMarkAbsentUseCase{
 		repo: repo,
-		bus:  bus,
 	}
 }
 
 type autoMarkAbsentUseCase struct {
 	repo autoMarkAbsentUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *autoMarkAbsentUseCase) Name() string {
@@ -41,7 +42,7 @@ func (uc *autoMarkAbsentUseCase) Execute(ctx context.Context, _ struct{}) (int64
 	allCount := int64(0)
 	var finalErr core.UseCaseError
 
-	affectedUserIDs := make(map[string]struct{})
+	refreshedUserIDs := make(map[string]struct{})
 
 	// 1. 從 TrainRepo 查出所有過期的課程 ID
 	for {
@@ -55,37 +56,100 @@ func (uc *autoMarkAbsentUseCase) Execute(ctx context.Context, _ struct{}) (int64
 			break
 		}
 
-		// 2. 丟到 ApptRepo 更新為 ABSENT，並取得受影響用戶
-		uids, err := uc.repo.MarkAbsentByTrainIDs(ctx, pastIDs)
-		if err != nil {
-			finalErr = core.NewUseCaseError("AUTO_ABSENT", "BATCH_UPDATE_FAIL", "更新預約時中斷", core.ErrInternal).Wrap(err)
+		// 2. 更新為 ABSENT 與寫入事件在同一個交易內，每批各自提交
+		var count int64
+		ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+			absent, err := uc.repo.MarkAbsentByTrainIDs(ctx, pastIDs)
+			if err != nil {
+				return core.NewUseCaseError("AUTO_ABSENT", "BATCH_UPDATE_FAIL", "更新預約時中斷", core.ErrInternal).Wrap(err)
+			}
+			count = int64(len(absent))
+			// 課程結束仍待審核的請假不會再有人審核，由系統駁回並通知家長，預約照常記為缺席
+			expired := expireLeaves(absent)
+			if len(expired) > 0 {
+				if err := uc.repo.UpdateManyAppts(ctx, expired); err != nil {
+					return core.NewUseCaseError("AUTO_ABSENT", "EXPIRE_LEAVE_FAIL", "更新未審核請假時中斷", core.ErrInternal).Wrap(err)
+				}
+			}
+			events := uc.newEvents(absent, refreshedUserIDs)
+			return core.AddEvents(ctx, uc.repo, append(events, uc.newLeaveExpiredEvents(expired)...)...)
+		})
+		if ucErr != nil {
+			finalErr = ucErr
 			break
 		}
-		
-		for _, uid := range uids {
-			affectedUserIDs[uid] = struct{}{}
-		}
-		allCount += int64(len(uids))
+		allCount += count
 
 		if len(pastIDs) < int(batchSize) {
 			break
 		}
 	}
 
-	// 3. 發送重新聚合請求事件
+	return allCount, finalErr
+}
+
+// newEvents 每筆預約各發一個狀態變更事件，供堂數扣除與快取清理；
+// 受影響用戶另發重新聚合請求，已在前幾批發過的用戶略過
+func (uc *autoMarkAbsentUseCase) newEvents(
+	absent []*entity.Appointment, refreshed map[string]struct{},
+) []event.Event {
 	now := time.Now()
-	for uid := range affectedUserIDs {
+	events := make([]event.Event, 0, len(absent))
+	var userIDs []string
+	for _, appt := range absent {
+		uid := appt.User().UserID()
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+			BookingID:  appt.ID(),
+			UserID:     uid,
+			TrainingID: appt.TrainingID(),
+			OldStatus:  entity.StatusConfirmed.String(),
+			NewStatus:  appt.Status().String(),
+			OccurredAt: appt.UpdateAt(),
+		}))
+		if _, ok := refreshed[uid]; !ok {
+			refreshed[uid] = struct{}{}
+			userIDs = append(userIDs, uid)
+		}
+	}
+	for _, uid := range userIDs {
 		// 這裡為了簡化，目前假設是重新計算當月
 		// TODO: 更好的做法是從受影響的 pastIDs 算出對應的年月
-		evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicUserStatsRefreshRequested, domain.UserStatsRefreshRequested{
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicUserStatsRefreshRequested, domain.UserStatsRefreshRequested{
 			UserID:     uid,
 			Year:       now.Year(),
 			Month:      int(now.Month()),
 			Reason:     "AutoMarkAbsent",
 			OccurredAt: now,
-		})
-		uc.bus.Publish(ctx, evt)
+		}))
 	}
+	return events
+}
 
-	return allCount, finalErr
+// expireLeaves 駁回缺席預約中仍待審核的請假，回傳有變更的預約
+func expireLeaves(absent []*entity.Appointment) []*entity.Appointment {
+	var expired []*entity.Appointment
+	for _, appt := range absent {
+		if appt.ExpireLeave() == nil {
+			expired = append(expired, appt)
+		}
+	}
+	return expired
+}
+
+// newLeaveExpiredEvents 系統駁回同樣發出審核事件，供快取清理
+func (uc *autoMarkAbsentUseCase) newLeaveExpiredEvents(expired []*entity.Appointment) []event.Event {
+	events := make([]event.Event, 0, len(expired))
+	for _, appt := range expired {
+		leave := appt.LeaveInfo()
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicLeaveReviewed, domain.LeaveReviewed{
+			BookingID:  appt.ID(),
+			UserID:     appt.User().UserID(),
+			TrainingID: appt.TrainingID(),
+			Approved:   false,
+			ReviewedBy: leave.ReviewedBy(),
+			Comment:    leave.ReviewComment(),
+			OccurredAt: appt.UpdateAt(),
+		}))
+	}
+	return events
 }
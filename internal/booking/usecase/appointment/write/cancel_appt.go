@@ -22,20 +22,19 @@ type CancelApptUseCase core.WriteUseCase[ReqCancelAppt, *entity.Appointment]
 type cancelApptUseCaseRepo interface {
 	repository.AppointmentRepository
 	repository.TrainRepository
-	repository.StatsRepository
 	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
-func NewCancelApptUseCase(repo cancelApptUseCaseRepo, bus event.Bus) CancelApptUseCase {
+func NewCancelApptUseCase(repo cancelApptUseCaseRepo) CancelApptUseCase {
 	return &cancelApptUseCase{
 		repo: repo,
-		bus:  bus,
 	}
 }
 
 type cancelApptUseCase struct {
 	repo cancelApptUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *cancelApptUseCase) Name() string {
@@ -53,45 +52,45 @@ func (uc *cancelApptUseCase) Execute(
 		return nil, ErrCancelApptFindApptFail.Wrap(repoErr)
 	}
 	
-	oldStatus := appt.Status().String()
-	err := appt.CancelAsMistake(req.UserID)
-	if err != nil {
-		return nil, ErrCancelApptCancelApptFail.Wrap(err)
-	}
-
-	// 取得課程資訊以獲得 startTime
+	// 取得課程資訊以獲得 startTime 與取消規則
 	trainDate, repoErr := uc.repo.FindTrainDateByID(ctx, appt.TrainingID())
 	if repoErr != nil {
 		return nil, ErrCancelApptTrainDateNotFound.Wrap(repoErr)
 	}
 
-	// 2. 增加名額
-	repoErr = uc.repo.IncreaseCapacity(ctx, appt.TrainingID(), 1)
-	if repoErr != nil {
-		return nil, ErrCancelApptIncreaseCapacityFail.Wrap(repoErr)
+	oldStatus := appt.Status().String()
+	var err error
+	if trainDate.IsRescheduledAfter(appt.CreatedAt()) {
+		// 場次改期前的預約，無法配合新時間可免責取消
+		err = appt.ReleaseAfterReschedule(req.UserID, trainDate.LastReschedule(), trainDate.Period().Start())
+	} else {
+		err = appt.CancelAsMistake(req.UserID, trainDate.BookingPolicy())
 	}
-	// 3. 刪除 appointment
-	repoErr = uc.repo.DeleteAppointment(ctx, appt)
-	if repoErr != nil {
-		// rollback
-		_ = uc.repo.DeductCapacity(ctx, appt.TrainingID(), 1)
-		return nil, ErrCancelApptDeleteApptFail.Wrap(repoErr)
+	if err != nil {
+		return nil, ErrCancelApptCancelApptFail.Wrap(err)
 	}
 
-	// 手動清理快取
-	_ = uc.repo.CleanTrainCache(ctx, req.UserID)
-	_ = uc.repo.CleanStatsCache(ctx, req.UserID, trainDate.Period().Start().Year(), int(trainDate.Period().Start().Month()))
-
-	// 發送領域事件
 	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
 		BookingID:  appt.ID(),
 		UserID:     appt.User().UserID(),
 		TrainingID: appt.TrainingID(),
 		OldStatus:  oldStatus,
-		NewStatus:  "Canceled",
+		NewStatus:  entity.StatusCancelled.String(),
 		OccurredAt: time.Now(),
 	})
-	uc.bus.Publish(ctx, evt)
+	// 2. 增加名額、刪除 appointment 並寫入領域事件
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.IncreaseCapacity(ctx, appt.TrainingID(), 1); err != nil {
+			return ErrCancelApptIncreaseCapacityFail.Wrap(err)
+		}
+		if err := uc.repo.DeleteAppointment(ctx, appt); err != nil {
+			return ErrCancelApptDeleteApptFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
 
 	return appt, nil
 }
@@ -22,14 +22,15 @@ type CancelLeaveUseCase core.WriteUseCase[ReqCancelLeave, *entity.Appointment]
 type cancelLeaveUseCaseRepo interface {
 	repository.TrainRepository
 	repository.AppointmentRepository
-	repository.StatsRepository
+	repository.MakeUpCreditRepository
 	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
-func NewCancelLeaveUseCase(repo cancelLeaveUseCaseRepo, bus event.Bus) CancelLeaveUseCase {
+func NewCancelLeaveUseCase(repo cancelLeaveUseCaseRepo) CancelLeaveUseCase {
 	return &cancelLeaveUseCase{
 		repo: repo,
-		bus:  bus,
 	}
 }
 
@@ -46,11 +47,14 @@ var (
 		"CANCEL_LEAVE", "UPDATE_APPOINTMENT_FAIL", "update appointment fail", core.ErrInternal)
 	ErrCancelLeaveTrainDateNotFound = core.NewDBError(
 		"CANCEL_LEAVE", "TRAIN_DATE_NOT_FOUND", "train date not found", core.ErrNotFound)
+	ErrCancelLeaveFindMakeUpCreditFail = core.NewDBError(
+		"CANCEL_LEAVE", "FIND_MAKE_UP_CREDIT_FAIL", "find make up credit fail", core.ErrInternal)
+	ErrCancelLeaveMakeUpBooked = core.NewUseCaseError(
+		"CANCEL_LEAVE", "MAKE_UP_BOOKED", "已預約補課，請先取消補課", core.ErrConflict)
 )
 
 type cancelLeaveUseCase struct {
 	repo cancelLeaveUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *cancelLeaveUseCase) Name() string {
@@ -67,46 +71,64 @@ func (uc *cancelLeaveUseCase) Execute(
 		return nil, ErrCancelLeaveFindApptFail.Wrap(repoErr)
 	}
 	
-	// 取得課程資訊以獲得 startTime
-	trainDate, repoErr := uc.repo.FindTrainDateByID(ctx, appt.TrainingID())
-	if repoErr != nil {
+	// 確認課程仍存在
+	if _, repoErr := uc.repo.FindTrainDateByID(ctx, appt.TrainingID()); repoErr != nil {
 		return nil, ErrCancelLeaveTrainDateNotFound.Wrap(repoErr)
 	}
 
+	// 已用此請假預約補課時不可恢復上課
+	credits, findErr := uc.repo.FindMakeUpCreditsByFilter(ctx, repository.NewFilterMakeUpCreditByLeaveApptID(appt.ID()))
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		return nil, ErrCancelLeaveFindMakeUpCreditFail.Wrap(findErr)
+	}
+	for _, c := range credits {
+		if c.Status() == entity.MakeUpCreditRedeemed {
+			return nil, ErrCancelLeaveMakeUpBooked
+		}
+	}
+
 	oldStatus := appt.Status().String()
+	// 待審核的請假尚未釋出名額，撤回時不需扣回
+	released := appt.Status() == entity.StatusCancelledLeave
 	// 這裡會檢查 req.UserID 是否為預約本人
 	err := appt.CancelLeave(req.UserID)
 	if err != nil {
 		return nil, ErrCancelLeaveCancelLeaveFail.Wrap(err)
 	}
 	
-	// 2. 扣除名額
-	repoErr = uc.repo.DeductCapacity(ctx, appt.TrainingID(), 1)
-	if repoErr != nil {
-		return nil, ErrCancelLeaveDeductCapacityFail.Wrap(repoErr)
-	}
-	
-	// 3. 更新 appointment
-	repoErr = uc.repo.UpdateAppt(ctx, appt)
-	if repoErr != nil {
-		_ = uc.repo.IncreaseCapacity(ctx, appt.TrainingID(), 1)
-		return nil, ErrCancelLeaveUpdateApptFail.Wrap(repoErr)
-	}
-
-	// 手動清理快取
-	_ = uc.repo.CleanTrainCache(ctx, appt.User().UserID())
-	_ = uc.repo.CleanStatsCache(ctx, appt.User().UserID(), trainDate.Period().Start().Year(), int(trainDate.Period().Start().Month()))
-
-	// 發送領域事件
-	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+	// 2. 扣除名額、更新 appointment 並寫入領域事件，快取由訂閱者清理
+	now := time.Now()
+	events := []event.Event{event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicLeaveCancelled, domain.LeaveCancelled{
 		BookingID:  appt.ID(),
 		UserID:     appt.User().UserID(),
 		TrainingID: appt.TrainingID(),
-		OldStatus:  oldStatus,
-		NewStatus:  appt.Status().String(),
-		OccurredAt: time.Now(),
+		Released:   released,
+		OccurredAt: now,
+	})}
+	if released {
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+			BookingID:  appt.ID(),
+			UserID:     appt.User().UserID(),
+			TrainingID: appt.TrainingID(),
+			OldStatus:  oldStatus,
+			NewStatus:  appt.Status().String(),
+			OccurredAt: now,
+		}))
+	}
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if released {
+			if err := uc.repo.DeductCapacity(ctx, appt.TrainingID(), 1); err != nil {
+				return ErrCancelLeaveDeductCapacityFail.Wrap(err)
+			}
+		}
+		if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
+			return ErrCancelLeaveUpdateApptFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, events...)
 	})
-	uc.bus.Publish(ctx, evt)
+	if ucErr != nil {
+		return nil, ucErr
+	}
 
 	return appt, nil
 }
@@ -3,6 +3,7 @@ package write
 import (
 	"context"
 	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/usecase/core"
 )
 
@@ -16,14 +17,16 @@ type ReqCheckIn struct {
 // This is kept for V1 compatibility but delegates logic to AdminCheckIn logic.
 type CheckInUseCase core.WriteUseCase[ReqCheckIn, []*entity.Appointment]
 
-func NewCheckInUseCase(adminUC AdminCheckInUseCase) CheckInUseCase {
+func NewCheckInUseCase(adminUC AdminCheckInUseCase, uow repository.UnitOfWork) CheckInUseCase {
 	return &checkInUseCaseLegacy{
 		adminUC: adminUC,
+		uow:     uow,
 	}
 }
 
 type checkInUseCaseLegacy struct {
 	adminUC AdminCheckInUseCase
+	uow     repository.UnitOfWork
 }
 
 func (uc *checkInUseCaseLegacy) Name() string {
@@ -34,8 +37,18 @@ func (uc *checkInUseCaseLegacy) Execute(
 	ctx context.Context, req ReqCheckIn,
 ) ([]*entity.Appointment, core.UseCaseError) {
 	// Delegation to the new consolidated logic
-	return uc.adminUC.Execute(ctx, ReqAdminCheckIn{
-		TrainDateID:         req.TrainDateID,
-		CheckedInBookingIDs: req.CheckedInBookingIDs,
+	// 委派的寫入併入同一個交易，與其他寫入用例一致
+	var appts []*entity.Appointment
+	ucErr := core.WithinTx(ctx, uc.uow, func(ctx context.Context) core.UseCaseError {
+		var err core.UseCaseError
+		appts, err = uc.adminUC.Execute(ctx, ReqAdminCheckIn{
+			TrainDateID:         req.TrainDateID,
+			CheckedInBookingIDs: req.CheckedInBookingIDs,
+		})
+		return err
 	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	return appts, nil
 }
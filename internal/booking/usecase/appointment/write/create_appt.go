@@ -2,6 +2,7 @@ package write
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"seanAIgent/internal/booking/domain"
@@ -11,9 +12,11 @@ import (
 	"seanAIgent/internal/event"
 )
 
+// ReqCreateAppt StudentIDs 為已建立的學員，ChildNames 為新輸入的姓名，找不到同名學員時自動建立
 type ReqCreateAppt struct {
 	TrainDateID string
 	User        entity.User
+	StudentIDs  []string
 	ChildNames  []string
 }
 
@@ -23,19 +26,21 @@ type createApptUseCaseRepo interface {
 	repository.IdentityGenerator
 	repository.TrainRepository
 	repository.AppointmentRepository
-	repository.StatsRepository
+	repository.CreditLedgerRepository
+	repository.StudentRepository
+	repository.TeamRepository
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
-func NewCreateApptUseCase(repo createApptUseCaseRepo, bus event.Bus) CreateApptUseCase {
+func NewCreateApptUseCase(repo createApptUseCaseRepo) CreateApptUseCase {
 	return &createApptUseCase{
 		repo: repo,
-		bus:  bus,
 	}
 }
 
 type createApptUseCase struct {
 	repo createApptUseCaseRepo
-	bus  event.Bus
 }
 
 func (uc *createApptUseCase) Name() string {
@@ -49,52 +54,169 @@ func (uc *createApptUseCase) Execute(
 	if err != nil {
 		return nil, ErrCreateApptTrainDateNotFound.Wrap(err)
 	}
+	if trainDate.IsCancelled() {
+		return nil, ErrCreateApptTrainingCancelled
+	}
 
-	apptCount := len(req.ChildNames)
+	team, ucErr := uc.findTrainDateTeam(ctx, trainDate)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	students, newStudents, ucErr := uc.resolveStudents(ctx, req, team)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	if team != nil {
+		if err := team.CheckMembers(students...); err != nil {
+			return nil, ErrCreateApptTeamMemberOnly.Wrap(err)
+		}
+	}
+	apptCount := len(students)
 	// new appointments
 	appointments := make([]*entity.Appointment, 0, apptCount)
-	for _, childName := range req.ChildNames {
+	for _, student := range students {
 		apptID := uc.repo.GenerateID()
 		appt, err := entity.NewAppointment(
 			entity.WithCreateAppt(
-				apptID, req.TrainDateID, req.User, childName,
-			))
+				apptID, req.TrainDateID, req.User, student.Name(),
+			),
+			entity.WithApptStudentID(student.ID()),
+		)
 		if err != nil {
 			return nil, ErrCreateApptNewDomainEntityFail.Wrap(err)
 		}
 		appointments = append(appointments, appt)
 	}
-	// find training date deduct capacity, if failed do not save appointments
-	err = uc.repo.DeductCapacity(ctx, req.TrainDateID, apptCount)
-	if err != nil {
-		return nil, ErrCreateApptDeductCapacityFail.Wrap(err)
+	// 已購買課程包的家長需有足夠堂數才可預約
+	ledger, err := uc.repo.FindCreditLedgerByUserID(ctx, req.User.UserID())
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCreateApptFindCreditLedgerFail.Wrap(err)
 	}
-	// save appointments
-	err = uc.repo.SaveManyAppointments(ctx, appointments)
-	if err != nil {
-		return nil, ErrCreateApptSaveApptFail.Wrap(err)
+	if ledger != nil {
+		for _, appt := range appointments {
+			reserveErr := ledger.Reserve(appt.ID(), appt.ChildName(), trainDate.Period().Start())
+			if errors.Is(reserveErr, entity.ErrCreditInsufficient) {
+				return nil, ErrCreateApptCreditInsufficient
+			}
+			if reserveErr != nil {
+				return nil, ErrCreateApptNewDomainEntityFail.Wrap(reserveErr)
+			}
+		}
 	}
-
-	// 暫時保留同步清理邏輯以確保即時性，改為手動呼叫 repo 清理
-	_ = uc.repo.CleanTrainCache(ctx, req.User.UserID())
-	_ = uc.repo.CleanStatsCache(ctx, req.User.UserID(), trainDate.Period().Start().Year(), int(trainDate.Period().Start().Month()))
-
-	// 發送領域事件
+	events := make([]event.Event, 0, len(appointments))
 	for _, appt := range appointments {
-		evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
 			BookingID:  appt.ID(),
 			UserID:     appt.User().UserID(),
 			TrainingID: appt.TrainingID(),
 			OldStatus:  "",
 			NewStatus:  appt.Status().String(),
 			OccurredAt: time.Now(),
-		})
-		uc.bus.Publish(ctx, evt)
+		}))
+	}
+	// 新學員、扣除名額、儲存預約、保留堂數與領域事件在同一個交易內，任一步失敗時全部回復
+	ucErr = core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		for _, s := range newStudents {
+			if err := uc.repo.SaveStudent(ctx, s); err != nil {
+				return ErrCreateApptSaveStudentFail.Wrap(err)
+			}
+		}
+		if err := uc.repo.DeductCapacity(ctx, req.TrainDateID, apptCount); err != nil {
+			return ErrCreateApptDeductCapacityFail.Wrap(err)
+		}
+		if err := uc.repo.SaveManyAppointments(ctx, appointments); err != nil {
+			return ErrCreateApptSaveApptFail.Wrap(err)
+		}
+		if ledger != nil {
+			if err := uc.repo.SaveCreditLedger(ctx, ledger); err != nil {
+				if errors.Is(err, repository.ErrConflict) {
+					return ErrCreateApptCreditLedgerConflict.Wrap(err)
+				}
+				return ErrCreateApptSaveCreditLedgerFail.Wrap(err)
+			}
+		}
+		return core.AddEvents(ctx, uc.repo, events...)
+	})
+	if ucErr != nil {
+		return nil, ucErr
 	}
 
 	return appointments, nil
 }
 
+// findTrainDateTeam 場次未綁定團隊時回傳 nil
+func (uc *createApptUseCase) findTrainDateTeam(
+	ctx context.Context, trainDate *entity.TrainDate,
+) (*entity.Team, core.UseCaseError) {
+	if trainDate.TeamID() == "" {
+		return nil, nil
+	}
+	team, err := uc.repo.FindTeamByID(ctx, trainDate.TeamID())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCreateApptTeamMemberOnly.Wrap(err)
+		}
+		return nil, ErrCreateApptFindTeamFail.Wrap(err)
+	}
+	return team, nil
+}
+
+// resolveStudents 將學員 ID 與新輸入的姓名轉為學員，同一學員只預約一次；
+// 新姓名建立的學員另外回傳，與預約在同一個交易內寫入。
+// 團隊場次不會自動建立學員，新姓名一定不是成員
+func (uc *createApptUseCase) resolveStudents(
+	ctx context.Context, req ReqCreateAppt, team *entity.Team,
+) ([]*entity.Student, []*entity.Student, core.UseCaseError) {
+	owned, findErr := uc.repo.FindStudentsByUserID(ctx, req.User.UserID())
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		return nil, nil, ErrCreateApptFindStudentFail.Wrap(findErr)
+	}
+	byID := make(map[string]*entity.Student, len(owned))
+	for _, s := range owned {
+		byID[s.ID()] = s
+	}
+	seen := map[string]bool{}
+	students := make([]*entity.Student, 0, len(req.StudentIDs)+len(req.ChildNames))
+	add := func(s *entity.Student) {
+		if !seen[s.ID()] {
+			seen[s.ID()] = true
+			students = append(students, s)
+		}
+	}
+	var created []*entity.Student
+	for _, id := range req.StudentIDs {
+		s, ok := byID[id]
+		if !ok {
+			return nil, nil, ErrCreateApptStudentNotBelongToUser
+		}
+		add(s)
+	}
+	for _, name := range req.ChildNames {
+		if s := entity.FindStudentByName(owned, name); s != nil {
+			add(s)
+			continue
+		}
+		if team != nil {
+			return nil, nil, ErrCreateApptTeamMemberOnly
+		}
+		s, err := entity.NewStudent(
+			entity.WithStudentID(uc.repo.GenerateID()),
+			entity.WithStudentParent(req.User),
+			entity.WithStudentName(name),
+		)
+		if err != nil {
+			return nil, nil, ErrCreateApptNewDomainEntityFail.Wrap(err)
+		}
+		created = append(created, s)
+		owned = append(owned, s)
+		add(s)
+	}
+	if len(students) == 0 {
+		return nil, nil, ErrCreateApptNoStudent
+	}
+	return students, created, nil
+}
+
 var (
 	ErrCreateApptTrainDateNotFound = core.NewDBError(
 		"CREATE_APPT", "TRAIN_DATE_NOT_FOUND", "train date not found", core.ErrNotFound)
@@ -104,4 +226,26 @@ var (
 		"CREATE_APPT", "DEDUCT_CAPACITY_FAIL", "deduct capacity fail", core.ErrConflict)
 	ErrCreateApptSaveApptFail = core.NewDBError(
 		"CREATE_APPT", "SAVE_APPOINTMENT_FAIL", "save appointment fail", core.ErrInternal)
+	ErrCreateApptFindCreditLedgerFail = core.NewDBError(
+		"CREATE_APPT", "FIND_CREDIT_LEDGER_FAIL", "find credit ledger fail", core.ErrInternal)
+	ErrCreateApptSaveCreditLedgerFail = core.NewDBError(
+		"CREATE_APPT", "SAVE_CREDIT_LEDGER_FAIL", "save credit ledger fail", core.ErrInternal)
+	ErrCreateApptCreditLedgerConflict = core.NewDBError(
+		"CREATE_APPT", "CREDIT_LEDGER_CONFLICT", "課程包堂數已被更新，請重新預約", core.ErrConflict)
+	ErrCreateApptFindStudentFail = core.NewDBError(
+		"CREATE_APPT", "FIND_STUDENT_FAIL", "find student fail", core.ErrInternal)
+	ErrCreateApptSaveStudentFail = core.NewDBError(
+		"CREATE_APPT", "SAVE_STUDENT_FAIL", "save student fail", core.ErrInternal)
+	ErrCreateApptStudentNotBelongToUser = core.NewUseCaseError(
+		"CREATE_APPT", "STUDENT_NOT_BELONG_TO_USER", "學員不屬於此帳號", core.ErrForbidden)
+	ErrCreateApptNoStudent = core.NewUseCaseError(
+		"CREATE_APPT", "NO_STUDENT", "請選擇至少一位學員", core.ErrInvalidInput)
+	ErrCreateApptCreditInsufficient = core.NewUseCaseError(
+		"CREATE_APPT", "CREDIT_INSUFFICIENT", "課程包堂數不足，請聯繫教練購買", core.ErrConflict)
+	ErrCreateApptFindTeamFail = core.NewDBError(
+		"CREATE_APPT", "FIND_TEAM_FAIL", "find team fail", core.ErrInternal)
+	ErrCreateApptTeamMemberOnly = core.NewUseCaseError(
+		"CREATE_APPT", "TEAM_MEMBER_ONLY", "此場次僅開放團隊成員預約", core.ErrForbidden)
+	ErrCreateApptTrainingCancelled = core.NewUseCaseError(
+		"CREATE_APPT", "TRAINING_CANCELLED", "此場次已停課", core.ErrConflict)
 )
@@ -23,19 +23,19 @@ type CreateLeaveUseCase core.WriteUseCase[ReqCreateLeave, *entity.Appointment]
 type createLeaveUseCaseRepo interface {
 	repository.AppointmentRepository
 	repository.TrainRepository
-	repository.StatsRepository
+	repository.TeamRepository
 	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
 type createLeaveUseCase struct {
 	repo createLeaveUseCaseRepo
-	bus  event.Bus
 }
 
-func NewCreateLeaveUseCase(repo createLeaveUseCaseRepo, bus event.Bus) CreateLeaveUseCase {
+func NewCreateLeaveUseCase(repo createLeaveUseCaseRepo) CreateLeaveUseCase {
 	return &createLeaveUseCase{
 		repo: repo,
-		bus:  bus,
 	}
 }
 
@@ -66,39 +66,86 @@ func (uc *createLeaveUseCase) Execute(
 		return nil, ErrCreateLeaveTrainDateNotFound.Wrap(err)
 	}
 
+	// 團隊設定請假需核准時，先送出申請不釋出名額
+	approval, ucErr := uc.requiresApproval(ctx, trainDate)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	if approval {
+		return uc.requestLeave(ctx, appt, trainDate, req.Reason)
+	}
+
 	oldStatus := appt.Status().String()
-	err = appt.AppendLeaveRecord(req.Reason, trainDate.Period().Start())
+	err = appt.AppendLeaveRecord(req.Reason, trainDate.Period().Start(), trainDate.BookingPolicy())
 	if err != nil {
 		return nil, core.NewUseCaseError("CREATE_LEAVE", "DOMAIN_FAIL", "目前時間不允許執行請假操作", core.ErrInvalidInput).Wrap(err)
 	}
 
-	// increase training date capacity
-	err = uc.repo.IncreaseCapacity(ctx, trainDate.ID(), 1)
-	if err != nil {
-		return nil, ErrCreateLeaveIncreaseCapacityFail.Wrap(err)
+	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+		BookingID:  appt.ID(),
+		UserID:     appt.User().UserID(),
+		TrainingID: appt.TrainingID(),
+		OldStatus:  oldStatus,
+		NewStatus:  appt.Status().String(),
+		OccurredAt: time.Now(),
+	})
+	// 釋出名額、更新請假資訊並寫入領域事件
+	ucErr = core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.IncreaseCapacity(ctx, trainDate.ID(), 1); err != nil {
+			return ErrCreateLeaveIncreaseCapacityFail.Wrap(err)
+		}
+		if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
+			return ErrCreateLeaveSaveLeaveFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
 	}
-	// update appt leave info
-	err = uc.repo.UpdateAppt(ctx, appt)
+
+	return appt, nil
+}
+
+// requiresApproval 場次未綁定團隊或團隊已刪除時直接核准
+func (uc *createLeaveUseCase) requiresApproval(
+	ctx context.Context, trainDate *entity.TrainDate,
+) (bool, core.UseCaseError) {
+	if trainDate.TeamID() == "" {
+		return false, nil
+	}
+	team, err := uc.repo.FindTeamByID(ctx, trainDate.TeamID())
 	if err != nil {
-		// rollback
-		_ = uc.repo.DeductCapacity(ctx, trainDate.ID(), 1)
-		return nil, ErrCreateLeaveSaveLeaveFail.Wrap(err)
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, ErrCreateLeaveFindTeamFail.Wrap(err)
 	}
+	return team.RequiresLeaveApproval(), nil
+}
 
-	// 手動清理快取
-	_ = uc.repo.CleanTrainCache(ctx, req.User.UserID())
-	_ = uc.repo.CleanStatsCache(ctx, req.User.UserID(), trainDate.Period().Start().Year(), int(trainDate.Period().Start().Month()))
-
-	// 發送領域事件
-	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+func (uc *createLeaveUseCase) requestLeave(
+	ctx context.Context, appt *entity.Appointment, trainDate *entity.TrainDate, reason string,
+) (*entity.Appointment, core.UseCaseError) {
+	if err := appt.RequestLeave(reason, trainDate.Period().Start(), trainDate.BookingPolicy()); err != nil {
+		return nil, core.NewUseCaseError("CREATE_LEAVE", "DOMAIN_FAIL", "目前時間不允許執行請假操作", core.ErrInvalidInput).Wrap(err)
+	}
+	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicLeaveRequested, domain.LeaveRequested{
 		BookingID:  appt.ID(),
 		UserID:     appt.User().UserID(),
 		TrainingID: appt.TrainingID(),
-		OldStatus:  oldStatus,
-		NewStatus:  appt.Status().String(),
+		TeamID:     trainDate.TeamID(),
+		Reason:     appt.LeaveInfo().Reason(),
 		OccurredAt: time.Now(),
 	})
-	uc.bus.Publish(ctx, evt)
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
+			return ErrCreateLeaveSaveLeaveFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
 
 	return appt, nil
 }
@@ -120,6 +167,8 @@ var (
 		"CREATE_LEAVE", "INCREASE_CAPACITY_FAIL", "increase capacity fail", core.ErrConflict)
 	ErrCreateLeaveUpdateApptFail = core.NewDBError(
 		"CREATE_LEAVE", "UPDATE_APPOINTMENT_FAIL", "update appointment fail", core.ErrInternal)
+	ErrCreateLeaveFindTeamFail = core.NewDBError(
+		"CREATE_LEAVE", "FIND_TEAM_FAIL", "find team fail", core.ErrInternal)
 	ErrCreateLeavePermissionDenied = core.NewUseCaseError(
 		"CREATE_LEAVE", "PERMISSION_DENIED", "permission denied", core.ErrPermissionDenied)
 )
@@ -0,0 +1,174 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+// ReqReviewLeave 教練審核請假申請，Comment 選填，會一併通知家長
+type ReqReviewLeave struct {
+	BookingID  string
+	ReviewerID string
+	Comment    string
+}
+
+type ApproveLeaveUseCase core.WriteUseCase[ReqReviewLeave, *entity.Appointment]
+
+type RejectLeaveUseCase core.WriteUseCase[ReqReviewLeave, *entity.Appointment]
+
+type reviewLeaveUseCaseRepo interface {
+	repository.AppointmentRepository
+	repository.TrainRepository
+	repository.TeamRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewApproveLeaveUseCase(repo reviewLeaveUseCaseRepo) ApproveLeaveUseCase {
+	return &reviewLeaveUseCase{repo: repo, approve: true}
+}
+
+func NewRejectLeaveUseCase(repo reviewLeaveUseCaseRepo) RejectLeaveUseCase {
+	return &reviewLeaveUseCase{repo: repo}
+}
+
+type reviewLeaveUseCase struct {
+	repo    reviewLeaveUseCaseRepo
+	approve bool
+}
+
+func (uc *reviewLeaveUseCase) Name() string {
+	if uc.approve {
+		return "ApproveLeave"
+	}
+	return "RejectLeave"
+}
+
+// Execute 核准時才釋出名額，駁回時預約維持原狀態
+func (uc *reviewLeaveUseCase) Execute(
+	ctx context.Context, req ReqReviewLeave,
+) (*entity.Appointment, core.UseCaseError) {
+	appt, err := uc.repo.FindApptByID(ctx, req.BookingID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReviewLeaveApptNotFound
+		}
+		return nil, ErrReviewLeaveFindApptFail.Wrap(err)
+	}
+	trainDate, err := uc.repo.FindTrainDateByID(ctx, appt.TrainingID())
+	if err != nil {
+		return nil, ErrReviewLeaveTrainDateNotFound.Wrap(err)
+	}
+	if ucErr := uc.checkReviewer(ctx, trainDate, req.ReviewerID); ucErr != nil {
+		return nil, ucErr
+	}
+
+	oldStatus := appt.Status().String()
+	var domainErr error
+	if uc.approve {
+		domainErr = appt.ApproveLeave(req.ReviewerID, req.Comment, trainDate.Period().Start())
+	} else {
+		domainErr = appt.RejectLeave(req.ReviewerID, req.Comment, trainDate.Period().Start())
+	}
+	if domainErr != nil {
+		if errors.Is(domainErr, entity.ErrAppointmentLeaveNotPending) {
+			return nil, ErrReviewLeaveNotPending.Wrap(domainErr)
+		}
+		if errors.Is(domainErr, entity.ErrAppointmentLeaveReviewClosed) {
+			return nil, ErrReviewLeaveSessionStarted.Wrap(domainErr)
+		}
+		return nil, ErrReviewLeaveDomainFail.Wrap(domainErr)
+	}
+
+	// 核准時由狀態變更事件觸發候補遞補、堂數與補課額度
+	now := time.Now()
+	var events []event.Event
+	if uc.approve {
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+			BookingID:  appt.ID(),
+			UserID:     appt.User().UserID(),
+			TrainingID: appt.TrainingID(),
+			OldStatus:  oldStatus,
+			NewStatus:  appt.Status().String(),
+			OccurredAt: now,
+		}))
+	}
+	events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicLeaveReviewed, domain.LeaveReviewed{
+		BookingID:  appt.ID(),
+		UserID:     appt.User().UserID(),
+		TrainingID: appt.TrainingID(),
+		Approved:   uc.approve,
+		ReviewedBy: req.ReviewerID,
+		Comment:    appt.LeaveInfo().ReviewComment(),
+		OccurredAt: now,
+	}))
+
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if uc.approve {
+			if err := uc.repo.IncreaseCapacity(ctx, trainDate.ID(), 1); err != nil {
+				return ErrReviewLeaveIncreaseCapacityFail.Wrap(err)
+			}
+		}
+		if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
+			return ErrReviewLeaveUpdateApptFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, events...)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+
+	return appt, nil
+}
+
+// checkReviewer 團隊場次只有該團隊教練或可管理全部團隊的人員可以審核，內部呼叫沒有 Actor 時不檢查
+func (uc *reviewLeaveUseCase) checkReviewer(
+	ctx context.Context, trainDate *entity.TrainDate, reviewerID string,
+) core.UseCaseError {
+	actor, ok := core.ActorFromContext(ctx)
+	if !ok || actor.Can(entity.PermTeamAll) || trainDate.TeamID() == "" {
+		return nil
+	}
+	team, err := uc.repo.FindTeamByID(ctx, trainDate.TeamID())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return ErrReviewLeaveFindTeamFail.Wrap(err)
+	}
+	if !team.IsCoach(reviewerID) {
+		return ErrReviewLeaveNotCoach
+	}
+	return nil
+}
+
+var (
+	ErrReviewLeaveApptNotFound = core.NewUseCaseError(
+		"REVIEW_LEAVE", "APPOINTMENT_NOT_FOUND", "找不到此預約", core.ErrNotFound)
+	ErrReviewLeaveFindApptFail = core.NewDBError(
+		"REVIEW_LEAVE", "FIND_APPOINTMENT_FAIL", "find appointment fail", core.ErrInternal)
+	ErrReviewLeaveTrainDateNotFound = core.NewDBError(
+		"REVIEW_LEAVE", "TRAIN_DATE_NOT_FOUND", "train date not found", core.ErrNotFound)
+	ErrReviewLeaveFindTeamFail = core.NewDBError(
+		"REVIEW_LEAVE", "FIND_TEAM_FAIL", "find team fail", core.ErrInternal)
+	ErrReviewLeaveNotCoach = core.NewUseCaseError(
+		"REVIEW_LEAVE", "NOT_COACH", "只有團隊教練可以審核請假", core.ErrForbidden)
+	ErrReviewLeaveNotPending = core.NewUseCaseError(
+		"REVIEW_LEAVE", "NOT_PENDING", "請假已審核或已撤回", core.ErrConflict)
+	ErrReviewLeaveSessionStarted = core.NewUseCaseError(
+		"REVIEW_LEAVE", "SESSION_STARTED", "課程已開始，無法審核請假", core.ErrConflict)
+	ErrReviewLeaveDomainFail = core.NewDomainError(
+		"REVIEW_LEAVE", "DOMAIN_FAIL", "無法審核此請假", core.ErrInvalidInput)
+	ErrReviewLeaveIncreaseCapacityFail = core.NewDBError(
+		"REVIEW_LEAVE", "INCREASE_CAPACITY_FAIL", "increase capacity fail", core.ErrConflict)
+	ErrReviewLeaveUpdateApptFail = core.NewDBError(
+		"REVIEW_LEAVE", "UPDATE_APPOINTMENT_FAIL", "update appointment fail", core.ErrInternal)
+)
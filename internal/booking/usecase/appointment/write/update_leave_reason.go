@@ -0,0 +1,97 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqUpdateLeaveReason struct {
+	ApptID string
+	UserID string
+	Reason string
+}
+
+type UpdateLeaveReasonUseCase core.WriteUseCase[ReqUpdateLeaveReason, *entity.Appointment]
+
+type updateLeaveReasonUseCaseRepo interface {
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewUpdateLeaveReasonUseCase(repo updateLeaveReasonUseCaseRepo) UpdateLeaveReasonUseCase {
+	return &updateLeaveReasonUseCase{
+		repo: repo,
+	}
+}
+
+var (
+	ErrUpdateLeaveReasonApptNotFound = core.NewDBError(
+		"UPDATE_LEAVE_REASON", "APPOINTMENT_NOT_FOUND", "appointment not found", core.ErrNotFound)
+	ErrUpdateLeaveReasonFindApptFail = core.NewDBError(
+		"UPDATE_LEAVE_REASON", "FIND_APPOINTMENT_FAIL", "find appointment fail", core.ErrInternal)
+	ErrUpdateLeaveReasonTrainDateNotFound = core.NewDBError(
+		"UPDATE_LEAVE_REASON", "TRAIN_DATE_NOT_FOUND", "train date not found", core.ErrNotFound)
+	ErrUpdateLeaveReasonUpdateFail = core.NewDomainError(
+		"UPDATE_LEAVE_REASON", "UPDATE_LEAVE_REASON_FAIL", "update leave reason fail", core.ErrInvalidInput)
+	ErrUpdateLeaveReasonUpdateApptFail = core.NewDBError(
+		"UPDATE_LEAVE_REASON", "UPDATE_APPOINTMENT_FAIL", "update appointment fail", core.ErrInternal)
+)
+
+type updateLeaveReasonUseCase struct {
+	repo updateLeaveReasonUseCaseRepo
+}
+
+func (uc *updateLeaveReasonUseCase) Name() string {
+	return "UpdateLeaveReason"
+}
+
+func (uc *updateLeaveReasonUseCase) Execute(
+	ctx context.Context, req ReqUpdateLeaveReason) (*entity.Appointment, core.UseCaseError) {
+	appt, repoErr := uc.repo.FindApptByID(ctx, req.ApptID)
+	if repoErr != nil {
+		if errors.Is(repoErr, repository.ErrNotFound) {
+			return nil, ErrUpdateLeaveReasonApptNotFound
+		}
+		return nil, ErrUpdateLeaveReasonFindApptFail.Wrap(repoErr)
+	}
+	trainDate, repoErr := uc.repo.FindTrainDateByID(ctx, appt.TrainingID())
+	if repoErr != nil {
+		return nil, ErrUpdateLeaveReasonTrainDateNotFound.Wrap(repoErr)
+	}
+
+	previousReason := appt.LeaveInfo().Reason()
+	// 這裡會檢查 req.UserID 是否為預約本人
+	if err := appt.UpdateLeaveReason(req.UserID, req.Reason, trainDate.Period().Start()); err != nil {
+		return nil, ErrUpdateLeaveReasonUpdateFail.Wrap(err)
+	}
+
+	// 更新 appointment 並寫入領域事件，快取由訂閱者清理
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.UpdateAppt(ctx, appt); err != nil {
+			return ErrUpdateLeaveReasonUpdateApptFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicLeaveReasonChanged, domain.LeaveReasonChanged{
+			BookingID:      appt.ID(),
+			UserID:         appt.User().UserID(),
+			TrainingID:     appt.TrainingID(),
+			PreviousReason: previousReason,
+			Reason:         appt.LeaveInfo().Reason(),
+			OccurredAt:     time.Now(),
+		}))
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+
+	return appt, nil
+}
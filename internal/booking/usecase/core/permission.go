@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"seanAIgent/internal/booking/domain/entity"
+)
+
+// Actor 發出請求的使用者與其後台權限，由 web 中介層解析後放入 context
+type Actor struct {
+	UserID      string
+	permissions []entity.Permission
+}
+
+func NewActor(userID string, perms ...entity.Permission) *Actor {
+	return &Actor{UserID: userID, permissions: perms}
+}
+
+func (a *Actor) Can(perms ...entity.Permission) bool {
+	for _, p := range perms {
+		if !slices.Contains(a.permissions, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *Actor) Permissions() []entity.Permission {
+	return slices.Clone(a.permissions)
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext 排程、CLI、MCP 等內部呼叫沒有 Actor，視為系統操作
+func ActorFromContext(ctx context.Context) (*Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(*Actor)
+	return actor, ok && actor != nil
+}
+
+func checkPermission(ctx context.Context, name string, perms []entity.Permission) UseCaseError {
+	actor, ok := ActorFromContext(ctx)
+	if !ok || actor.Can(perms...) {
+		return nil
+	}
+	return ErrPermissionRequired.Wrap(fmt.Errorf("%s requires %v (user %q)", name, perms, actor.UserID))
+}
+
+type writePermissionDecorator[T any, R any] struct {
+	next  WriteUseCase[T, R]
+	perms []entity.Permission
+}
+
+func (d *writePermissionDecorator[T, R]) Name() string {
+	return d.next.Name()
+}
+
+func (d *writePermissionDecorator[T, R]) Execute(ctx context.Context, input T) (R, UseCaseError) {
+	if err := checkPermission(ctx, d.next.Name(), d.perms); err != nil {
+		var zero R
+		return zero, err
+	}
+	return d.next.Execute(ctx, input)
+}
+
+// WithWritePermission 執行前檢查 context 中的 Actor 具備所有權限
+func WithWritePermission[T any, R any](next WriteUseCase[T, R], perms ...entity.Permission) WriteUseCase[T, R] {
+	return &writePermissionDecorator[T, R]{next: next, perms: perms}
+}
+
+type readPermissionDecorator[I any, O any] struct {
+	next  ReadUseCase[I, O]
+	perms []entity.Permission
+}
+
+func (d *readPermissionDecorator[I, O]) Name() string {
+	return d.next.Name()
+}
+
+func (d *readPermissionDecorator[I, O]) Execute(ctx context.Context, input I) (O, UseCaseError) {
+	if err := checkPermission(ctx, d.next.Name(), d.perms); err != nil {
+		var zero O
+		return zero, err
+	}
+	return d.next.Execute(ctx, input)
+}
+
+func WithReadPermission[I any, O any](next ReadUseCase[I, O], perms ...entity.Permission) ReadUseCase[I, O] {
+	return &readPermissionDecorator[I, O]{next: next, perms: perms}
+}
+
+var ErrPermissionRequired = NewUseCaseError(
+	"PERMISSION", "REQUIRED", "權限不足，請聯絡負責人開通", ErrForbidden)
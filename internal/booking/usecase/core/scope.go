@@ -0,0 +1,31 @@
+package core
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/domain/repository"
+)
+
+// ScopeToTeam 套用管理查詢的團隊篩選，teamID 為空時不篩選；
+// context 已限定團隊 (團隊教練) 時不可改查其他團隊
+func ScopeToTeam(ctx context.Context, teamID string) (context.Context, UseCaseError) {
+	scope, scoped := repository.TeamScopeFromContext(ctx)
+	if scoped {
+		if teamID != "" && teamID != scope {
+			return nil, ErrTeamScopeForbidden
+		}
+		return ctx, nil
+	}
+	if teamID == "" {
+		return ctx, nil
+	}
+	return repository.WithTeamScope(ctx, teamID), nil
+}
+
+// ScopeToNoTeam 沒有負責團隊的教練看不到任何團隊的資料
+func ScopeToNoTeam(ctx context.Context) context.Context {
+	return repository.WithTeamScope(ctx, repository.NoTeamScope)
+}
+
+var ErrTeamScopeForbidden = NewUseCaseError(
+	"TEAM_SCOPE", "FORBIDDEN", "無權限查看其他團隊的資料", ErrForbidden)
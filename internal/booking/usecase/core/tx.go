@@ -0,0 +1,43 @@
+package core
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/event"
+)
+
+var (
+	ErrTxFail       = NewDBError("TX", "TX_FAIL", "transaction fail", ErrInternal)
+	ErrAddEventFail = NewDBError("TX", "ADD_EVENT_FAIL", "add event to outbox fail", ErrInternal)
+)
+
+// WithinTx 在同一個交易內執行寫入步驟，步驟回傳的錯誤原樣傳回；
+// 步驟成功但提交失敗時回傳 ErrTxFail
+func WithinTx(
+	ctx context.Context, uow repository.UnitOfWork, fn func(ctx context.Context) UseCaseError,
+) UseCaseError {
+	var ucErr UseCaseError
+	err := uow.WithinTx(ctx, func(ctx context.Context) error {
+		ucErr = fn(ctx)
+		if ucErr != nil {
+			return ucErr
+		}
+		return nil
+	})
+	if ucErr != nil {
+		return ucErr
+	}
+	if err != nil {
+		return ErrTxFail.Wrap(err)
+	}
+	return nil
+}
+
+// AddEvents 將領域事件寫入 outbox，在 WithinTx 內呼叫時與業務資料一起提交
+func AddEvents(ctx context.Context, outbox repository.EventOutbox, events ...event.Event) UseCaseError {
+	if err := outbox.AddEvents(ctx, events...); err != nil {
+		return ErrAddEventFail.Wrap(err)
+	}
+	return nil
+}
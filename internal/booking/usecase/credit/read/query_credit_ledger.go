@@ -0,0 +1,52 @@
+package read
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqQueryCreditLedger struct {
+	UserID string
+}
+
+type QueryCreditLedgerUseCase core.ReadUseCase[ReqQueryCreditLedger, *entity.CreditLedger]
+
+type queryCreditLedgerUseCase struct {
+	repo repository.CreditLedgerRepository
+}
+
+func NewQueryCreditLedgerUseCase(repo repository.CreditLedgerRepository) QueryCreditLedgerUseCase {
+	return &queryCreditLedgerUseCase{repo: repo}
+}
+
+func (uc *queryCreditLedgerUseCase) Name() string {
+	return "QueryCreditLedger"
+}
+
+// Execute 查詢家長的課程包帳本，尚未購買任何課程包時回傳 nil (單堂計費)
+func (uc *queryCreditLedgerUseCase) Execute(
+	ctx context.Context, req ReqQueryCreditLedger,
+) (*entity.CreditLedger, core.UseCaseError) {
+	if req.UserID == "" {
+		return nil, ErrQueryCreditLedgerInvalidInput
+	}
+	ledger, err := uc.repo.FindCreditLedgerByUserID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, ErrQueryCreditLedgerFail.Wrap(err)
+	}
+	return ledger, nil
+}
+
+var (
+	ErrQueryCreditLedgerFail = core.NewDBError(
+		"QUERY_CREDIT_LEDGER", "QUERY_FAIL", "query credit ledger fail", core.ErrInternal)
+	ErrQueryCreditLedgerInvalidInput = core.NewUseCaseError(
+		"QUERY_CREDIT_LEDGER", "INVALID_INPUT", "user id is required", core.ErrInvalidInput)
+)
@@ -0,0 +1,82 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqAdminTopUpCredits struct {
+	ExpiresAt time.Time
+	User      entity.User
+	ChildName string // 空字串代表全家共用
+	Note      string
+	Quantity  int
+}
+
+type AdminTopUpCreditsUseCase core.WriteUseCase[ReqAdminTopUpCredits, *entity.CreditLedger]
+
+type adminTopUpCreditsUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.CreditLedgerRepository
+}
+
+func NewAdminTopUpCreditsUseCase(repo adminTopUpCreditsUseCaseRepo) AdminTopUpCreditsUseCase {
+	return &adminTopUpCreditsUseCase{
+		repo: repo,
+	}
+}
+
+type adminTopUpCreditsUseCase struct {
+	repo adminTopUpCreditsUseCaseRepo
+}
+
+func (uc *adminTopUpCreditsUseCase) Name() string {
+	return "AdminTopUpCredits"
+}
+
+func (uc *adminTopUpCreditsUseCase) Execute(
+	ctx context.Context, req ReqAdminTopUpCredits,
+) (*entity.CreditLedger, core.UseCaseError) {
+	ledger, err := uc.repo.FindCreditLedgerByUserID(ctx, req.User.UserID())
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAdminTopUpCreditsFindLedgerFail.Wrap(err)
+		}
+		var newErr error
+		ledger, newErr = entity.NewCreditLedger(entity.WithCreditLedgerUser(req.User))
+		if newErr != nil {
+			return nil, ErrAdminTopUpCreditsDomainFail.Wrap(newErr)
+		}
+	}
+
+	if _, topUpErr := ledger.TopUp(
+		uc.repo.GenerateID(), req.ChildName, req.Quantity, req.ExpiresAt, req.Note,
+	); topUpErr != nil {
+		return nil, ErrAdminTopUpCreditsDomainFail.Wrap(topUpErr)
+	}
+
+	err = uc.repo.SaveCreditLedger(ctx, ledger)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrAdminTopUpCreditsConflict.Wrap(err)
+		}
+		return nil, ErrAdminTopUpCreditsSaveFail.Wrap(err)
+	}
+	return ledger, nil
+}
+
+var (
+	ErrAdminTopUpCreditsFindLedgerFail = core.NewDBError(
+		"ADMIN_TOP_UP_CREDITS", "FIND_LEDGER_FAIL", "find credit ledger fail", core.ErrInternal)
+	ErrAdminTopUpCreditsDomainFail = core.NewDomainError(
+		"ADMIN_TOP_UP_CREDITS", "DOMAIN_ERROR", "堂數或到期日不正確", core.ErrInvalidInput)
+	ErrAdminTopUpCreditsConflict = core.NewDBError(
+		"ADMIN_TOP_UP_CREDITS", "CONFLICT", "帳本已被更新，請重新操作", core.ErrConflict)
+	ErrAdminTopUpCreditsSaveFail = core.NewDBError(
+		"ADMIN_TOP_UP_CREDITS", "SAVE_LEDGER_FAIL", "save credit ledger fail", core.ErrInternal)
+)
@@ -0,0 +1,166 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqApplyApptCredit struct {
+	OccurredAt time.Time
+	BookingID  string
+	UserID     string
+	TrainingID string
+	NewStatus  string
+}
+
+// ApplyApptCreditUseCase 依預約狀態變更保留、扣除或退還堂數，回傳 false 代表帳本無需異動
+type ApplyApptCreditUseCase core.WriteUseCase[ReqApplyApptCredit, bool]
+
+type applyApptCreditUseCaseRepo interface {
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.CreditLedgerRepository
+}
+
+func NewApplyApptCreditUseCase(repo applyApptCreditUseCaseRepo, policy entity.CreditPolicy) ApplyApptCreditUseCase {
+	return &applyApptCreditUseCase{
+		repo:   repo,
+		policy: policy,
+	}
+}
+
+type applyApptCreditUseCase struct {
+	repo   applyApptCreditUseCaseRepo
+	policy entity.CreditPolicy
+}
+
+func (uc *applyApptCreditUseCase) Name() string {
+	return "ApplyApptCredit"
+}
+
+func (uc *applyApptCreditUseCase) Execute(
+	ctx context.Context, req ReqApplyApptCredit,
+) (bool, core.UseCaseError) {
+	ledger, err := uc.repo.FindCreditLedgerByUserID(ctx, req.UserID)
+	if err != nil {
+		// 未購買課程包的家長以單堂計費
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, ErrApplyApptCreditFindLedgerFail.Wrap(err)
+	}
+
+	entryCount := len(ledger.Entries())
+	var applyErr error
+	switch req.NewStatus {
+	case entity.StatusConfirmed.String():
+		// 候補遞補、取消請假、教練恢復出席等情況補上保留
+		applyErr = uc.reserve(ctx, ledger, req.BookingID)
+	case entity.StatusAttended.String(), entity.StatusAbsent.String():
+		if !ledger.HasAllocation(req.BookingID) {
+			// 現場報名等未經預約流程的紀錄，先保留再扣除
+			if applyErr = uc.reserve(ctx, ledger, req.BookingID); applyErr != nil {
+				break
+			}
+		}
+		applyErr = ledger.Consume(req.BookingID)
+	case entity.StatusCancelledLeave.String():
+		if !ledger.HasAllocation(req.BookingID) {
+			return false, nil
+		}
+		applyErr = uc.leave(ctx, ledger, req)
+	case entity.StatusCancelled.String():
+		if !ledger.HasAllocation(req.BookingID) {
+			return false, nil
+		}
+		applyErr = ledger.Refund(req.BookingID, "取消預約")
+	case entity.StatusCancelledByCoach.String():
+		if !ledger.HasAllocation(req.BookingID) {
+			return false, nil
+		}
+		applyErr = ledger.Refund(req.BookingID, "教練停課")
+	default:
+		return false, nil
+	}
+	if applyErr != nil {
+		if errors.Is(applyErr, entity.ErrCreditInsufficient) {
+			return false, ErrApplyApptCreditInsufficient.Wrap(applyErr)
+		}
+		var ucErr core.UseCaseError
+		if errors.As(applyErr, &ucErr) {
+			return false, ucErr
+		}
+		return false, ErrApplyApptCreditDomainFail.Wrap(applyErr)
+	}
+	// 重複事件 (例如預約時已保留) 不會新增異動紀錄，無需儲存
+	if len(ledger.Entries()) == entryCount {
+		return false, nil
+	}
+
+	err = uc.repo.SaveCreditLedger(ctx, ledger)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return false, ErrApplyApptCreditConflict.Wrap(err)
+		}
+		return false, ErrApplyApptCreditSaveFail.Wrap(err)
+	}
+	return true, nil
+}
+
+func (uc *applyApptCreditUseCase) reserve(ctx context.Context, ledger *entity.CreditLedger, apptID string) error {
+	appt, err := uc.repo.FindApptByID(ctx, apptID)
+	if err != nil {
+		return ErrApplyApptCreditFindApptFail.Wrap(err)
+	}
+	trainDate, err := uc.repo.FindTrainDateByID(ctx, appt.TrainingID())
+	if err != nil {
+		return ErrApplyApptCreditFindTrainDateFail.Wrap(err)
+	}
+	return ledger.Reserve(apptID, appt.ChildName(), trainDate.Period().Start())
+}
+
+// leave 依退還規則決定請假退還或視同上課扣除；以家長送出請假的時間判斷，
+// 需核准的請假不因教練審核較晚而改為扣除，事件重試或重送也不影響結果
+func (uc *applyApptCreditUseCase) leave(
+	ctx context.Context, ledger *entity.CreditLedger, req ReqApplyApptCredit,
+) error {
+	appt, err := uc.repo.FindApptByID(ctx, req.BookingID)
+	if err != nil {
+		return ErrApplyApptCreditFindApptFail.Wrap(err)
+	}
+	trainDate, err := uc.repo.FindTrainDateByID(ctx, req.TrainingID)
+	if err != nil {
+		return ErrApplyApptCreditFindTrainDateFail.Wrap(err)
+	}
+	requestedAt := appt.LeaveInfo().CreatedAt()
+	if requestedAt.IsZero() {
+		requestedAt = req.OccurredAt
+	}
+	policy := uc.policy.ForTraining(trainDate.BookingPolicy())
+	if policy.RefundOnLeave(requestedAt, trainDate.Period().Start()) {
+		return ledger.Refund(req.BookingID, "請假")
+	}
+	return ledger.Consume(req.BookingID)
+}
+
+var (
+	ErrApplyApptCreditFindLedgerFail = core.NewDBError(
+		"APPLY_APPT_CREDIT", "FIND_LEDGER_FAIL", "find credit ledger fail", core.ErrInternal)
+	ErrApplyApptCreditFindApptFail = core.NewDBError(
+		"APPLY_APPT_CREDIT", "FIND_APPOINTMENT_FAIL", "find appointment fail", core.ErrInternal)
+	ErrApplyApptCreditFindTrainDateFail = core.NewDBError(
+		"APPLY_APPT_CREDIT", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrApplyApptCreditInsufficient = core.NewDomainError(
+		"APPLY_APPT_CREDIT", "INSUFFICIENT", "課程包堂數不足", core.ErrConflict)
+	ErrApplyApptCreditDomainFail = core.NewDomainError(
+		"APPLY_APPT_CREDIT", "DOMAIN_ERROR", "apply credit fail", core.ErrInvalidInput)
+	ErrApplyApptCreditConflict = core.NewDBError(
+		"APPLY_APPT_CREDIT", "CONFLICT", "credit ledger was updated concurrently", core.ErrConflict)
+	ErrApplyApptCreditSaveFail = core.NewDBError(
+		"APPLY_APPT_CREDIT", "SAVE_LEDGER_FAIL", "save credit ledger fail", core.ErrInternal)
+)
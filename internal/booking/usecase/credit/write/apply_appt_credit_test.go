@@ -0,0 +1,95 @@
+package write
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memCreditRepo 以記憶體保存單一場次、預約與帳本，未用到的方法由內嵌的 interface 提供
+type memCreditRepo struct {
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.CreditLedgerRepository
+	training *entity.TrainDate
+	appt     *entity.Appointment
+	ledger   *entity.CreditLedger
+}
+
+func (r *memCreditRepo) FindTrainDateByID(ctx context.Context, id string) (*entity.TrainDate, repository.RepoError) {
+	return r.training, nil
+}
+
+func (r *memCreditRepo) FindApptByID(ctx context.Context, id string) (*entity.Appointment, repository.RepoError) {
+	return r.appt, nil
+}
+
+func (r *memCreditRepo) FindCreditLedgerByUserID(ctx context.Context, userID string) (*entity.CreditLedger, repository.RepoError) {
+	return r.ledger, nil
+}
+
+func (r *memCreditRepo) SaveCreditLedger(ctx context.Context, ledger *entity.CreditLedger) repository.RepoError {
+	r.ledger = ledger
+	return nil
+}
+
+// newLeaveCreditRepo 開課前 3 小時已保留一堂，leaveAt 為家長送出請假的時間
+func newLeaveCreditRepo(t *testing.T, leaveAt time.Time) *memCreditRepo {
+	t.Helper()
+	start := time.Now().Add(3 * time.Hour)
+	period, err := entity.NewTimeRange(start, start.Add(time.Hour))
+	require.NoError(t, err)
+	td, err := entity.NewTrainDate(entity.WithBasicTrainDate("train1", "coach1", "Gym A", 10, period))
+	require.NoError(t, err)
+	user, err := entity.NewUser("user1", "Parent")
+	require.NoError(t, err)
+	appt, err := entity.NewAppointment(
+		entity.WithCreateAppt("appt1", td.ID(), user, "ChildA"),
+		entity.WithStatus(entity.StatusCancelledLeave),
+		entity.WithLeaveInfo(entity.NewLeaveInfo("生病", entity.LeaveStatusApproved, leaveAt)),
+	)
+	require.NoError(t, err)
+	ledger, err := entity.NewCreditLedger(entity.WithCreditLedgerUser(user))
+	require.NoError(t, err)
+	_, err = ledger.TopUp("pack1", "", 5, start.AddDate(0, 1, 0), "")
+	require.NoError(t, err)
+	require.NoError(t, ledger.Reserve(appt.ID(), "ChildA", start))
+	return &memCreditRepo{training: td, appt: appt, ledger: ledger}
+}
+
+// TestApplyApptCredit_LeaveRefundWindow 以送出請假的時間判斷是否退還，不受核准或事件處理的時間影響
+func TestApplyApptCredit_LeaveRefundWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		leaveAt time.Duration // 相對於現在
+		want    string
+	}{
+		{"RequestedBeforeCutoff", -time.Hour, entity.CreditAllocationReleased.String()},
+		{"RequestedAfterCutoff", 90 * time.Minute, entity.CreditAllocationConsumed.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newLeaveCreditRepo(t, time.Now().Add(tt.leaveAt))
+			uc := NewApplyApptCreditUseCase(repo, entity.DefaultCreditPolicy())
+
+			// 核准事件發生在開課前 1 小時，已超過退還期限
+			applied, err := uc.Execute(t.Context(), ReqApplyApptCredit{
+				OccurredAt: repo.training.Period().Start().Add(-time.Hour),
+				BookingID:  repo.appt.ID(),
+				UserID:     "user1",
+				TrainingID: repo.training.ID(),
+				NewStatus:  entity.StatusCancelledLeave.String(),
+			})
+			require.Nil(t, err)
+			assert.True(t, applied)
+			require.Len(t, repo.ledger.Allocations(), 1)
+			assert.Equal(t, tt.want, repo.ledger.Allocations()[0].Status().String())
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqSettleCreditLedger struct {
+	UserID string
+}
+
+// SettleCreditLedgerUseCase 將已出席或缺席但仍為保留狀態的堂數扣除，回傳扣除筆數
+// 用於自動標記缺席等批次更新，這類更新不會逐筆發送預約狀態變更事件
+type SettleCreditLedgerUseCase core.WriteUseCase[ReqSettleCreditLedger, int]
+
+type settleCreditLedgerUseCaseRepo interface {
+	repository.AppointmentRepository
+	repository.CreditLedgerRepository
+}
+
+func NewSettleCreditLedgerUseCase(repo settleCreditLedgerUseCaseRepo) SettleCreditLedgerUseCase {
+	return &settleCreditLedgerUseCase{
+		repo: repo,
+	}
+}
+
+type settleCreditLedgerUseCase struct {
+	repo settleCreditLedgerUseCaseRepo
+}
+
+func (uc *settleCreditLedgerUseCase) Name() string {
+	return "SettleCreditLedger"
+}
+
+func (uc *settleCreditLedgerUseCase) Execute(
+	ctx context.Context, req ReqSettleCreditLedger,
+) (int, core.UseCaseError) {
+	ledger, err := uc.repo.FindCreditLedgerByUserID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, ErrSettleCreditLedgerFindLedgerFail.Wrap(err)
+	}
+
+	settled := 0
+	for _, alloc := range ledger.Allocations() {
+		if alloc.Status() != entity.CreditAllocationReserved {
+			continue
+		}
+		appt, findErr := uc.repo.FindApptByID(ctx, alloc.ApptID())
+		if findErr != nil {
+			if errors.Is(findErr, repository.ErrNotFound) {
+				continue
+			}
+			return 0, ErrSettleCreditLedgerFindApptFail.Wrap(findErr)
+		}
+		if appt.Status() != entity.StatusAttended && appt.Status() != entity.StatusAbsent {
+			continue
+		}
+		if consumeErr := ledger.Consume(alloc.ApptID()); consumeErr != nil {
+			return 0, ErrSettleCreditLedgerDomainFail.Wrap(consumeErr)
+		}
+		settled++
+	}
+	if settled == 0 {
+		return 0, nil
+	}
+
+	err = uc.repo.SaveCreditLedger(ctx, ledger)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return 0, ErrSettleCreditLedgerConflict.Wrap(err)
+		}
+		return 0, ErrSettleCreditLedgerSaveFail.Wrap(err)
+	}
+	return settled, nil
+}
+
+var (
+	ErrSettleCreditLedgerFindLedgerFail = core.NewDBError(
+		"SETTLE_CREDIT_LEDGER", "FIND_LEDGER_FAIL", "find credit ledger fail", core.ErrInternal)
+	ErrSettleCreditLedgerFindApptFail = core.NewDBError(
+		"SETTLE_CREDIT_LEDGER", "FIND_APPOINTMENT_FAIL", "find appointment fail", core.ErrInternal)
+	ErrSettleCreditLedgerDomainFail = core.NewDomainError(
+		"SETTLE_CREDIT_LEDGER", "DOMAIN_ERROR", "consume credit fail", core.ErrInvalidInput)
+	ErrSettleCreditLedgerConflict = core.NewDBError(
+		"SETTLE_CREDIT_LEDGER", "CONFLICT", "credit ledger was updated concurrently", core.ErrConflict)
+	ErrSettleCreditLedgerSaveFail = core.NewDBError(
+		"SETTLE_CREDIT_LEDGER", "SAVE_LEDGER_FAIL", "save credit ledger fail", core.ErrInternal)
+)
@@ -0,0 +1,85 @@
+package read
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+// ReqQueryDeadLetters 欄位留空代表不篩選，Limit 預設 100 筆
+type ReqQueryDeadLetters struct {
+	SubscriberID string
+	Topic        string
+	Limit        int
+}
+
+type QueryDeadLettersUseCase core.ReadUseCase[ReqQueryDeadLetters, []event.DeadLetter]
+
+func NewQueryDeadLettersUseCase(store event.DeadLetterStore) QueryDeadLettersUseCase {
+	return &queryDeadLettersUseCase{store: store}
+}
+
+type queryDeadLettersUseCase struct {
+	store event.DeadLetterStore
+}
+
+func (uc *queryDeadLettersUseCase) Name() string {
+	return "QueryDeadLetters"
+}
+
+// Execute 依最後失敗時間由新到舊排列
+func (uc *queryDeadLettersUseCase) Execute(
+	ctx context.Context, req ReqQueryDeadLetters,
+) ([]event.DeadLetter, core.UseCaseError) {
+	letters, err := uc.store.List(ctx, event.DeadLetterFilter{
+		SubscriberID: req.SubscriberID,
+		Topic:        req.Topic,
+		Limit:        req.Limit,
+	})
+	if err != nil {
+		return nil, ErrQueryDeadLettersFail.Wrap(err)
+	}
+	return letters, nil
+}
+
+type ReqGetDeadLetter struct {
+	ID string
+}
+
+type GetDeadLetterUseCase core.ReadUseCase[ReqGetDeadLetter, *event.DeadLetter]
+
+func NewGetDeadLetterUseCase(store event.DeadLetterStore) GetDeadLetterUseCase {
+	return &getDeadLetterUseCase{store: store}
+}
+
+type getDeadLetterUseCase struct {
+	store event.DeadLetterStore
+}
+
+func (uc *getDeadLetterUseCase) Name() string {
+	return "GetDeadLetter"
+}
+
+func (uc *getDeadLetterUseCase) Execute(
+	ctx context.Context, req ReqGetDeadLetter,
+) (*event.DeadLetter, core.UseCaseError) {
+	dl, err := uc.store.Get(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, event.ErrDeadLetterNotFound) {
+			return nil, ErrGetDeadLetterNotFound
+		}
+		return nil, ErrGetDeadLetterFail.Wrap(err)
+	}
+	return &dl, nil
+}
+
+var (
+	ErrQueryDeadLettersFail = core.NewDBError(
+		"QUERY_DEAD_LETTERS", "QUERY_FAIL", "query dead letters fail", core.ErrInternal)
+	ErrGetDeadLetterNotFound = core.NewUseCaseError(
+		"GET_DEAD_LETTER", "NOT_FOUND", "找不到失敗事件", core.ErrNotFound)
+	ErrGetDeadLetterFail = core.NewDBError(
+		"GET_DEAD_LETTER", "GET_FAIL", "get dead letter fail", core.ErrInternal)
+)
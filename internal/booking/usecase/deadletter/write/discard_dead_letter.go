@@ -0,0 +1,45 @@
+package write
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqDiscardDeadLetter struct {
+	ID string
+}
+
+// DiscardDeadLetterUseCase 確認不需重送 (例如已手動修正資料) 時移除 dead letter
+type DiscardDeadLetterUseCase core.WriteUseCase[ReqDiscardDeadLetter, *event.DeadLetter]
+
+func NewDiscardDeadLetterUseCase(store event.DeadLetterStore) DiscardDeadLetterUseCase {
+	return &discardDeadLetterUseCase{store: store}
+}
+
+type discardDeadLetterUseCase struct {
+	store event.DeadLetterStore
+}
+
+func (uc *discardDeadLetterUseCase) Name() string {
+	return "DiscardDeadLetter"
+}
+
+func (uc *discardDeadLetterUseCase) Execute(
+	ctx context.Context, req ReqDiscardDeadLetter,
+) (*event.DeadLetter, core.UseCaseError) {
+	dl, ucErr := findDeadLetter(ctx, uc.store, req.ID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	if err := uc.store.Delete(ctx, dl.ID); err != nil {
+		return nil, ErrDiscardDeadLetterFail.Wrap(err)
+	}
+	return dl, nil
+}
+
+var (
+	ErrDiscardDeadLetterFail = core.NewDBError(
+		"DISCARD_DEAD_LETTER", "DELETE_FAIL", "delete dead letter fail", core.ErrInternal)
+)
@@ -0,0 +1,86 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqReplayDeadLetter struct {
+	ID string
+}
+
+type ReplayDeadLetterUseCase core.WriteUseCase[ReqReplayDeadLetter, *event.DeadLetter]
+
+// NewReplayDeadLetterUseCase 直接交給原本的訂閱者處理，不經過 Bus，
+// 因此在未啟動訂閱的程序 (網頁、CLI) 也能重送
+func NewReplayDeadLetterUseCase(
+	store event.DeadLetterStore, subscribers []event.Subscriber,
+) ReplayDeadLetterUseCase {
+	subs := make(map[string]event.Subscriber, len(subscribers))
+	for _, s := range subscribers {
+		subs[s.ID()] = s
+	}
+	return &replayDeadLetterUseCase{store: store, subscribers: subs}
+}
+
+type replayDeadLetterUseCase struct {
+	store       event.DeadLetterStore
+	subscribers map[string]event.Subscriber
+}
+
+func (uc *replayDeadLetterUseCase) Name() string {
+	return "ReplayDeadLetter"
+}
+
+// Execute 處理成功後移除 dead letter；再次失敗時累加嘗試次數並回傳錯誤
+func (uc *replayDeadLetterUseCase) Execute(
+	ctx context.Context, req ReqReplayDeadLetter,
+) (*event.DeadLetter, core.UseCaseError) {
+	dl, ucErr := findDeadLetter(ctx, uc.store, req.ID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	sub, ok := uc.subscribers[dl.SubscriberID]
+	if !ok {
+		return nil, ErrReplayDeadLetterUnknownSubscriber
+	}
+	if handleErr := event.Invoke(ctx, sub, dl.Event()); handleErr != nil {
+		if err := uc.store.Add(ctx, event.NewDeadLetter(dl.SubscriberID, dl.Event(), 1, handleErr)); err != nil {
+			return nil, ErrReplayDeadLetterSaveFail.Wrap(err)
+		}
+		return nil, ErrReplayDeadLetterHandleFail.Wrap(handleErr)
+	}
+	if err := uc.store.Delete(ctx, dl.ID); err != nil && !errors.Is(err, event.ErrDeadLetterNotFound) {
+		return nil, ErrReplayDeadLetterDeleteFail.Wrap(err)
+	}
+	return dl, nil
+}
+
+func findDeadLetter(ctx context.Context, store event.DeadLetterStore, id string) (*event.DeadLetter, core.UseCaseError) {
+	dl, err := store.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, event.ErrDeadLetterNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, ErrFindDeadLetterFail.Wrap(err)
+	}
+	return &dl, nil
+}
+
+var (
+	ErrDeadLetterNotFound = core.NewUseCaseError(
+		"DEAD_LETTER", "NOT_FOUND", "找不到失敗事件", core.ErrNotFound)
+	ErrFindDeadLetterFail = core.NewDBError(
+		"DEAD_LETTER", "FIND_FAIL", "find dead letter fail", core.ErrInternal)
+	ErrReplayDeadLetterUnknownSubscriber = core.NewUseCaseError(
+		"REPLAY_DEAD_LETTER", "UNKNOWN_SUBSCRIBER", "訂閱者已不存在，請改為捨棄", core.ErrConflict)
+	ErrReplayDeadLetterHandleFail = core.NewUseCaseError(
+		"REPLAY_DEAD_LETTER", "HANDLE_FAIL", "重送後仍處理失敗", core.ErrInternal)
+	ErrReplayDeadLetterSaveFail = core.NewDBError(
+		"REPLAY_DEAD_LETTER", "SAVE_FAIL", "save dead letter fail", core.ErrInternal)
+	ErrReplayDeadLetterDeleteFail = core.NewDBError(
+		"REPLAY_DEAD_LETTER", "DELETE_FAIL", "delete dead letter fail", core.ErrInternal)
+)
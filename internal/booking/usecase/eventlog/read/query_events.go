@@ -0,0 +1,203 @@
+package read
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+const (
+	defaultEventsLimit = 50
+	maxEventsLimit     = 200
+	// payloadScanLimit 依使用者、預約篩選時需解碼 Payload，最多往前掃描的事件數
+	payloadScanLimit = 5000
+	scanBatchSize    = 500
+)
+
+// ReqQueryEvents 欄位留空代表不篩選，依事件 ID 由新到舊排列
+type ReqQueryEvents struct {
+	Topic     string
+	UserID    string
+	BookingID string
+	// From、To 為事件發生時間的範圍 [From, To)
+	From time.Time
+	To   time.Time
+	// BeforeID 分頁用，傳入上一頁的 NextBeforeID
+	BeforeID string
+	Limit    int
+}
+
+// RespQueryEvents NextBeforeID 為空代表沒有更舊的事件；
+// Truncated 表示依 Payload 篩選時已達掃描上限，更舊的事件需以 NextBeforeID 繼續查詢
+type RespQueryEvents struct {
+	Events       []event.Event
+	NextBeforeID string
+	Truncated    bool
+}
+
+type QueryEventsUseCase core.ReadUseCase[ReqQueryEvents, *RespQueryEvents]
+
+func NewQueryEventsUseCase(eventLog event.EventLog) QueryEventsUseCase {
+	return &queryEventsUseCase{eventLog: eventLog}
+}
+
+type queryEventsUseCase struct {
+	eventLog event.EventLog
+}
+
+func (uc *queryEventsUseCase) Name() string {
+	return "QueryEvents"
+}
+
+// Execute 使用者、預約存在 Payload 內無法建立索引，改為分批解碼比對
+func (uc *queryEventsUseCase) Execute(
+	ctx context.Context, req ReqQueryEvents,
+) (*RespQueryEvents, core.UseCaseError) {
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return nil, ErrQueryEventsInvalidRange
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultEventsLimit
+	}
+	limit = min(limit, maxEventsLimit)
+
+	filter := event.EventFilter{
+		Topic:    req.Topic,
+		From:     req.From,
+		To:       req.To,
+		BeforeID: req.BeforeID,
+		Newest:   true,
+	}
+	if req.UserID == "" && req.BookingID == "" {
+		filter.Limit = limit + 1
+		events, err := uc.eventLog.FindEvents(ctx, filter)
+		if err != nil {
+			return nil, ErrQueryEventsFail.Wrap(err)
+		}
+		resp := &RespQueryEvents{Events: events}
+		if len(events) > limit {
+			resp.Events = events[:limit]
+			resp.NextBeforeID = resp.Events[limit-1].ID()
+		}
+		return resp, nil
+	}
+
+	resp := &RespQueryEvents{}
+	filter.Limit = scanBatchSize
+	for scanned := 0; scanned < payloadScanLimit; {
+		events, err := uc.eventLog.FindEvents(ctx, filter)
+		if err != nil {
+			return nil, ErrQueryEventsFail.Wrap(err)
+		}
+		for _, e := range events {
+			scanned++
+			filter.BeforeID = e.ID()
+			if matchPayload(e, req.UserID, req.BookingID) {
+				resp.Events = append(resp.Events, e)
+				if len(resp.Events) == limit {
+					resp.NextBeforeID = e.ID()
+					return resp, nil
+				}
+			}
+		}
+		if len(events) < scanBatchSize {
+			return resp, nil
+		}
+	}
+	resp.NextBeforeID = filter.BeforeID
+	resp.Truncated = true
+	return resp, nil
+}
+
+// eventRefs 各主題 Payload 共用的欄位名稱
+type eventRefs struct {
+	UserID          string   `json:"user_id"`
+	AffectedUserIDs []string `json:"affected_user_ids"`
+	BookingID       string   `json:"booking_id"`
+}
+
+// matchPayload 先轉為目前版本再比對，無法解碼的事件視為不符合
+func matchPayload(e event.Event, userID, bookingID string) bool {
+	data, err := event.Schemas.Upcast(e.Topic(), e.Version(), e.Data())
+	if err != nil {
+		return false
+	}
+	var refs eventRefs
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return false
+	}
+	if userID != "" && refs.UserID != userID && !slices.Contains(refs.AffectedUserIDs, userID) {
+		return false
+	}
+	return bookingID == "" || refs.BookingID == bookingID
+}
+
+type ReqGetEvent struct {
+	ID string
+}
+
+// EventDetail Payload 為轉換到目前版本的內容，無法轉換時為原始內容並附上 UpcastErr
+type EventDetail struct {
+	Event          event.Event
+	Payload        []byte
+	CurrentVersion int
+	UpcastErr      string
+}
+
+type GetEventUseCase core.ReadUseCase[ReqGetEvent, *EventDetail]
+
+func NewGetEventUseCase(eventLog event.EventLog) GetEventUseCase {
+	return &getEventUseCase{eventLog: eventLog}
+}
+
+type getEventUseCase struct {
+	eventLog event.EventLog
+}
+
+func (uc *getEventUseCase) Name() string {
+	return "GetEvent"
+}
+
+func (uc *getEventUseCase) Execute(
+	ctx context.Context, req ReqGetEvent,
+) (*EventDetail, core.UseCaseError) {
+	e, ucErr := findEvent(ctx, uc.eventLog, req.ID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	detail := &EventDetail{Event: e, Payload: e.Data(), CurrentVersion: event.Schemas.CurrentVersion(e.Topic())}
+	if data, err := event.Schemas.Upcast(e.Topic(), e.Version(), e.Data()); err != nil {
+		detail.UpcastErr = err.Error()
+	} else {
+		detail.Payload = data
+	}
+	return detail, nil
+}
+
+func findEvent(ctx context.Context, eventLog event.EventLog, id string) (event.Event, core.UseCaseError) {
+	e, err := eventLog.GetEvent(ctx, id)
+	if err != nil {
+		if errors.Is(err, event.ErrEventNotFound) {
+			return nil, ErrEventNotFound
+		}
+		return nil, ErrGetEventFail.Wrap(err)
+	}
+	return e, nil
+}
+
+var (
+	ErrQueryEventsInvalidRange = core.NewUseCaseError(
+		"QUERY_EVENTS", "INVALID_RANGE", "結束時間需晚於開始時間", core.ErrInvalidInput)
+	ErrQueryEventsFail = core.NewDBError(
+		"QUERY_EVENTS", "QUERY_FAIL", "query events fail", core.ErrInternal)
+	ErrEventNotFound = core.NewUseCaseError(
+		"GET_EVENT", "NOT_FOUND", "找不到事件，可能已超過保留期限", core.ErrNotFound)
+	ErrGetEventFail = core.NewDBError(
+		"GET_EVENT", "GET_FAIL", "get event fail", core.ErrInternal)
+)
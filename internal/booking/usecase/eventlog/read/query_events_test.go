@@ -0,0 +1,112 @@
+package read
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryEvents(t *testing.T) {
+	ctx := t.Context()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	store := event.NewMemoryStore(event.WithMemoryTTL(0))
+	payloads := []string{
+		`{"user_id":"u1","booking_id":"b1"}`,
+		`{"user_id":"u2","booking_id":"b2"}`,
+		`{"training_id":"t1","affected_user_ids":["u1","u3"]}`,
+		`not json`,
+		`{"user_id":"u1","booking_id":"b3"}`,
+	}
+	for i, data := range payloads {
+		id := fmt.Sprintf("evt_%02d", i+1)
+		require.NoError(t, store.Save(ctx, event.NewStoredEvent(id, "topic", 1, base.Add(time.Duration(i)*time.Hour), []byte(data))))
+	}
+	uc := NewQueryEventsUseCase(store)
+
+	t.Run("NewestFirstPages", func(t *testing.T) {
+		resp, err := uc.Execute(ctx, ReqQueryEvents{Limit: 3})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"evt_05", "evt_04", "evt_03"}, ids(resp.Events))
+		assert.Equal(t, "evt_03", resp.NextBeforeID)
+
+		resp, err = uc.Execute(ctx, ReqQueryEvents{Limit: 3, BeforeID: resp.NextBeforeID})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"evt_02", "evt_01"}, ids(resp.Events))
+		assert.Empty(t, resp.NextBeforeID)
+	})
+
+	// 使用者同時比對 user_id 與 affected_user_ids，無法解碼的事件略過
+	t.Run("FiltersByPayload", func(t *testing.T) {
+		resp, err := uc.Execute(ctx, ReqQueryEvents{UserID: "u1"})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"evt_05", "evt_03", "evt_01"}, ids(resp.Events))
+
+		resp, err = uc.Execute(ctx, ReqQueryEvents{UserID: "u1", BookingID: "b1"})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"evt_01"}, ids(resp.Events))
+
+		resp, err = uc.Execute(ctx, ReqQueryEvents{UserID: "u1", Limit: 1})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"evt_05"}, ids(resp.Events))
+		assert.Equal(t, "evt_05", resp.NextBeforeID)
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		_, err := uc.Execute(ctx, ReqQueryEvents{From: base, To: base})
+		assert.Equal(t, ErrQueryEventsInvalidRange, err)
+	})
+}
+
+type stubSubscriber struct{ id, topic string }
+
+func (s stubSubscriber) ID() string                                      { return s.id }
+func (s stubSubscriber) Topic() string                                   { return s.topic }
+func (s stubSubscriber) Handle(ctx context.Context, e event.Event) error { return nil }
+
+func TestQuerySubscriberProgress(t *testing.T) {
+	ctx := t.Context()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	store := event.NewMemoryStore(event.WithMemoryTTL(0))
+	for i, id := range []string{"evt_01", "evt_02", "evt_03"} {
+		require.NoError(t, store.Save(ctx, event.NewStoredEvent(id, "a", 1, base.Add(time.Duration(i)*time.Minute), []byte(`{}`))))
+	}
+	require.NoError(t, store.SetProgress(ctx, "behind", "evt_01"))
+	require.NoError(t, store.SetProgress(ctx, "removed", "evt_03"))
+
+	uc := NewQuerySubscriberProgressUseCase(store, []event.Subscriber{
+		stubSubscriber{id: "behind", topic: "a"},
+		stubSubscriber{id: "idle", topic: "b"},
+	}).(*querySubscriberProgressUseCase)
+	uc.now = func() time.Time { return base.Add(10 * time.Minute) }
+
+	statuses, err := uc.Execute(ctx, ReqQuerySubscriberProgress{})
+	require.Nil(t, err)
+	require.Len(t, statuses, 3)
+
+	behind := statuses[0]
+	assert.Equal(t, "behind", behind.SubscriberID)
+	assert.True(t, behind.Registered)
+	assert.Equal(t, base, behind.LastEventAt)
+	assert.EqualValues(t, 2, behind.Pending)
+	// 最早未處理的是 evt_02
+	assert.Equal(t, 9*time.Minute, behind.Lag)
+
+	assert.Equal(t, SubscriberStatus{SubscriberID: "idle", Topic: "b", Registered: true}, statuses[1])
+	assert.Equal(t, "removed", statuses[2].SubscriberID)
+	assert.False(t, statuses[2].Registered)
+	assert.Zero(t, statuses[2].Pending)
+}
+
+func ids(events []event.Event) []string {
+	result := make([]string, 0, len(events))
+	for _, e := range events {
+		result = append(result, e.ID())
+	}
+	return result
+}
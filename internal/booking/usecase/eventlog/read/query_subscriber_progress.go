@@ -0,0 +1,109 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqQuerySubscriberProgress struct{}
+
+// SubscriberStatus Registered 為 false 代表只剩進度紀錄、程式已不再訂閱；
+// Lag 為最早一筆未處理事件的等待時間，與 event.subscriber.lag 指標的定義相同
+type SubscriberStatus struct {
+	SubscriberID string
+	Topic        string
+	Registered   bool
+	LastEventID  string
+	// LastEventAt 最後處理事件的發生時間，事件已過期時為零值
+	LastEventAt time.Time
+	UpdatedAt   time.Time
+	Pending     int64
+	Lag         time.Duration
+}
+
+type QuerySubscriberProgressUseCase core.ReadUseCase[ReqQuerySubscriberProgress, []SubscriberStatus]
+
+func NewQuerySubscriberProgressUseCase(
+	eventLog event.EventLog, subscribers []event.Subscriber,
+) QuerySubscriberProgressUseCase {
+	return &querySubscriberProgressUseCase{eventLog: eventLog, subscribers: subscribers, now: time.Now}
+}
+
+type querySubscriberProgressUseCase struct {
+	eventLog    event.EventLog
+	subscribers []event.Subscriber
+	now         func() time.Time
+}
+
+func (uc *querySubscriberProgressUseCase) Name() string {
+	return "QuerySubscriberProgress"
+}
+
+// Execute 依訂閱者 ID 排列；未登記的訂閱者不知道主題，不計算待處理數
+func (uc *querySubscriberProgressUseCase) Execute(
+	ctx context.Context, _ ReqQuerySubscriberProgress,
+) ([]SubscriberStatus, core.UseCaseError) {
+	progress, err := uc.eventLog.FindProgress(ctx)
+	if err != nil {
+		return nil, ErrQuerySubscriberProgressFail.Wrap(err)
+	}
+	statuses := make(map[string]*SubscriberStatus, len(progress)+len(uc.subscribers))
+	for _, p := range progress {
+		statuses[p.SubscriberID] = &SubscriberStatus{
+			SubscriberID: p.SubscriberID,
+			LastEventID:  p.LastEventID,
+			UpdatedAt:    p.UpdatedAt,
+		}
+	}
+	for _, s := range uc.subscribers {
+		st, ok := statuses[s.ID()]
+		if !ok {
+			st = &SubscriberStatus{SubscriberID: s.ID()}
+			statuses[s.ID()] = st
+		}
+		st.Topic, st.Registered = s.Topic(), true
+	}
+
+	now := uc.now()
+	result := make([]SubscriberStatus, 0, len(statuses))
+	for _, st := range statuses {
+		if st.LastEventID != "" {
+			e, err := uc.eventLog.GetEvent(ctx, st.LastEventID)
+			if err != nil && !errors.Is(err, event.ErrEventNotFound) {
+				return nil, ErrQuerySubscriberProgressFail.Wrap(err)
+			}
+			if err == nil {
+				st.LastEventAt = e.OccurredAt()
+			}
+		}
+		if st.Registered {
+			// 與追趕相同，以事件 ID 判斷是否已處理
+			filter := event.EventFilter{Topic: st.Topic, AfterID: st.LastEventID}
+			if st.Pending, err = uc.eventLog.CountEvents(ctx, filter); err != nil {
+				return nil, ErrQuerySubscriberProgressFail.Wrap(err)
+			}
+			if st.Pending > 0 {
+				filter.Limit = 1
+				oldest, err := uc.eventLog.FindEvents(ctx, filter)
+				if err != nil {
+					return nil, ErrQuerySubscriberProgressFail.Wrap(err)
+				}
+				if len(oldest) > 0 {
+					st.Lag = max(now.Sub(oldest[0].OccurredAt()), 0)
+				}
+			}
+		}
+		result = append(result, *st)
+	}
+	slices.SortFunc(result, func(a, b SubscriberStatus) int { return strings.Compare(a.SubscriberID, b.SubscriberID) })
+	return result, nil
+}
+
+var ErrQuerySubscriberProgressFail = core.NewDBError(
+	"QUERY_SUBSCRIBER_PROGRESS", "QUERY_FAIL", "query subscriber progress fail", core.ErrInternal)
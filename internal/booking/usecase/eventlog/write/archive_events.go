@@ -0,0 +1,72 @@
+package write
+
+import (
+	"context"
+	"time"
+
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+const (
+	defaultArchiveOlderThanDays = 25
+	// eventLogRetentionDays event_logs 的 TTL，封存需在這之前完成
+	eventLogRetentionDays = 30
+)
+
+// ReqArchiveEvents OlderThanDays 為 0 時使用預設的 25 天，需小於事件紀錄保留的 30 天，預留排程失敗時重試的時間
+type ReqArchiveEvents struct {
+	OlderThanDays int
+}
+
+type RespArchiveEvents struct {
+	Before  time.Time
+	Entries []event.ArchiveEntry
+}
+
+type ArchiveEventsUseCase core.WriteUseCase[ReqArchiveEvents, *RespArchiveEvents]
+
+// EventArchiver 由 event.Archiver 實作
+type EventArchiver interface {
+	Archive(ctx context.Context, before time.Time) ([]event.ArchiveEntry, error)
+}
+
+func NewArchiveEventsUseCase(archiver EventArchiver) ArchiveEventsUseCase {
+	return &archiveEventsUseCase{archiver: archiver, now: time.Now}
+}
+
+type archiveEventsUseCase struct {
+	archiver EventArchiver
+	now      func() time.Time
+}
+
+func (uc *archiveEventsUseCase) Name() string {
+	return "ArchiveEvents"
+}
+
+// Execute 中途失敗時已上傳的檔案保留在清單中，下次執行接續
+func (uc *archiveEventsUseCase) Execute(
+	ctx context.Context, req ReqArchiveEvents,
+) (*RespArchiveEvents, core.UseCaseError) {
+	days := req.OlderThanDays
+	if days == 0 {
+		days = defaultArchiveOlderThanDays
+	}
+	if days < 0 || days >= eventLogRetentionDays {
+		return nil, ErrArchiveEventsInvalidInput
+	}
+	resp := &RespArchiveEvents{Before: uc.now().AddDate(0, 0, -days)}
+	entries, err := uc.archiver.Archive(ctx, resp.Before)
+	if err != nil {
+		return nil, ErrArchiveEventsFail.Wrap(err)
+	}
+	resp.Entries = entries
+	return resp, nil
+}
+
+var (
+	ErrArchiveEventsInvalidInput = core.NewUseCaseError(
+		"ARCHIVE_EVENTS", "INVALID_INPUT", "封存天數需介於 1 到 29 天", core.ErrInvalidInput)
+	ErrArchiveEventsFail = core.NewDBError(
+		"ARCHIVE_EVENTS", "ARCHIVE_FAIL", "archive events fail", core.ErrInternal)
+)
@@ -0,0 +1,38 @@
+package write
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubArchiver struct {
+	before time.Time
+}
+
+func (a *stubArchiver) Archive(ctx context.Context, before time.Time) ([]event.ArchiveEntry, error) {
+	a.before = before
+	return []event.ArchiveEntry{{Key: "event-archive/2026/03/01/topic.jsonl.gz", Count: 3}}, nil
+}
+
+func TestArchiveEvents(t *testing.T) {
+	ctx := t.Context()
+	now := time.Date(2026, 4, 1, 3, 30, 0, 0, time.UTC)
+	archiver := &stubArchiver{}
+	uc := &archiveEventsUseCase{archiver: archiver, now: func() time.Time { return now }}
+
+	resp, err := uc.Execute(ctx, ReqArchiveEvents{})
+	require.Nil(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -25), archiver.before)
+	assert.Len(t, resp.Entries, 1)
+
+	for _, days := range []int{-1, 30} {
+		_, err = uc.Execute(ctx, ReqArchiveEvents{OlderThanDays: days})
+		assert.Equal(t, ErrArchiveEventsInvalidInput, err)
+	}
+}
@@ -0,0 +1,80 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+// ReqRedispatchEvent 將單筆事件重新交給指定的訂閱者，不調整訂閱者進度
+type ReqRedispatchEvent struct {
+	EventID      string
+	SubscriberID string
+}
+
+type RedispatchEventUseCase core.WriteUseCase[ReqRedispatchEvent, event.Event]
+
+// NewRedispatchEventUseCase 與 ReplayEvents 相同，直接交給訂閱者處理而不經過 Bus
+func NewRedispatchEventUseCase(
+	eventLog event.EventLog, deadLetters event.DeadLetterStore, subscribers []event.Subscriber,
+) RedispatchEventUseCase {
+	subs := make(map[string]event.Subscriber, len(subscribers))
+	for _, s := range subscribers {
+		subs[s.ID()] = s
+	}
+	return &redispatchEventUseCase{eventLog: eventLog, deadLetters: deadLetters, subscribers: subs}
+}
+
+type redispatchEventUseCase struct {
+	eventLog    event.EventLog
+	deadLetters event.DeadLetterStore
+	subscribers map[string]event.Subscriber
+}
+
+func (uc *redispatchEventUseCase) Name() string {
+	return "RedispatchEvent"
+}
+
+// Execute 處理失敗時寫入 dead letter 並回傳錯誤
+func (uc *redispatchEventUseCase) Execute(
+	ctx context.Context, req ReqRedispatchEvent,
+) (event.Event, core.UseCaseError) {
+	sub, ok := uc.subscribers[req.SubscriberID]
+	if !ok {
+		return nil, ErrRedispatchEventUnknownSubscriber
+	}
+	e, err := uc.eventLog.GetEvent(ctx, req.EventID)
+	if err != nil {
+		if errors.Is(err, event.ErrEventNotFound) {
+			return nil, ErrRedispatchEventNotFound
+		}
+		return nil, ErrRedispatchEventFindFail.Wrap(err)
+	}
+	if e.Topic() != sub.Topic() {
+		return nil, ErrRedispatchEventTopicMismatch
+	}
+	if handleErr := event.Invoke(ctx, sub, e); handleErr != nil {
+		if err := uc.deadLetters.Add(ctx, event.NewDeadLetter(sub.ID(), e, 1, handleErr)); err != nil {
+			return nil, ErrRedispatchEventSaveDeadLetterFail.Wrap(err)
+		}
+		return nil, ErrRedispatchEventHandleFail.Wrap(handleErr)
+	}
+	return e, nil
+}
+
+var (
+	ErrRedispatchEventUnknownSubscriber = core.NewUseCaseError(
+		"REDISPATCH_EVENT", "UNKNOWN_SUBSCRIBER", "找不到訂閱者", core.ErrNotFound)
+	ErrRedispatchEventNotFound = core.NewUseCaseError(
+		"REDISPATCH_EVENT", "NOT_FOUND", "找不到事件，可能已超過保留期限", core.ErrNotFound)
+	ErrRedispatchEventTopicMismatch = core.NewUseCaseError(
+		"REDISPATCH_EVENT", "TOPIC_MISMATCH", "訂閱者未訂閱此主題", core.ErrInvalidInput)
+	ErrRedispatchEventHandleFail = core.NewUseCaseError(
+		"REDISPATCH_EVENT", "HANDLE_FAIL", "處理失敗，已寫入失敗事件", core.ErrInternal)
+	ErrRedispatchEventFindFail = core.NewDBError(
+		"REDISPATCH_EVENT", "FIND_FAIL", "find event fail", core.ErrInternal)
+	ErrRedispatchEventSaveDeadLetterFail = core.NewDBError(
+		"REDISPATCH_EVENT", "SAVE_DEAD_LETTER_FAIL", "save dead letter fail", core.ErrInternal)
+)
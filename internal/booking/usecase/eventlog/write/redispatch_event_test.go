@@ -0,0 +1,37 @@
+package write
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingSubscriber 模擬訂閱者的 bug，處理時 panic
+type panickingSubscriber struct {
+	recordingSubscriber
+}
+
+func (s *panickingSubscriber) Handle(ctx context.Context, e event.Event) error {
+	panic("nil map")
+}
+
+// 與 Bus 相同，訂閱者 panic 時轉為錯誤並寫入 dead letter，不中斷呼叫端
+func TestRedispatchEvent_SubscriberPanics(t *testing.T) {
+	ctx := t.Context()
+	store := event.NewMemoryStore(event.WithMemoryTTL(0))
+	require.NoError(t, store.Save(ctx, event.NewStoredEvent("evt_01", "topic", 1, time.Now(), []byte(`{}`))))
+	dls := &memDeadLetters{letters: make(map[string]event.DeadLetter)}
+	uc := NewRedispatchEventUseCase(store, dls, []event.Subscriber{&panickingSubscriber{}})
+
+	_, err := uc.Execute(ctx, ReqRedispatchEvent{EventID: "evt_01", SubscriberID: "recorder"})
+	require.NotNil(t, err)
+	assert.Equal(t, ErrRedispatchEventHandleFail.Code(), err.Code())
+	dl, dlErr := dls.Get(ctx, event.DeadLetterID("recorder", "evt_01"))
+	require.NoError(t, dlErr)
+	assert.Contains(t, dl.Error, "subscriber panicked")
+}
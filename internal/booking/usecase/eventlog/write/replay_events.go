@@ -0,0 +1,195 @@
+package write
+
+import (
+	"context"
+	"time"
+
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+const replayBatchSize = 500
+
+// ReqReplayEvents 將事件紀錄重新交給訂閱者處理，用於修正 bug 後重建統計、快取等投影
+type ReqReplayEvents struct {
+	SubscriberID string
+	// Topic 留空時使用訂閱者的主題
+	Topic string
+	// From、To 為事件發生時間的範圍 [From, To)，留空代表不限制
+	From time.Time
+	To   time.Time
+	// DryRun 只計算符合條件的事件數，不交給訂閱者處理
+	DryRun bool
+	// Rate 每秒最多重播的事件數，0 代表不限制
+	Rate int
+	// AdvanceProgress 完成後將訂閱者進度推進到最後一筆重播的事件，避免即時分發再處理一次；
+	// 需重播到最新的事件 (To 留空)，且 From 之前沒有訂閱者尚未處理的事件，進度不會往回調整
+	AdvanceProgress bool
+	// OnProgress 每處理完一筆事件呼叫一次，可為 nil
+	OnProgress func(RespReplayEvents)
+}
+
+// RespReplayEvents 處理失敗的事件寫入 dead letter，可再個別重送
+type RespReplayEvents struct {
+	Matched     int64
+	Replayed    int
+	Failed      int
+	LastEventID string
+}
+
+type ReplayEventsUseCase core.WriteUseCase[ReqReplayEvents, *RespReplayEvents]
+
+// NewReplayEventsUseCase 與 ReplayDeadLetter 相同，直接交給訂閱者處理而不經過 Bus
+func NewReplayEventsUseCase(
+	eventLog event.EventLog, deadLetters event.DeadLetterStore, subscribers []event.Subscriber,
+) ReplayEventsUseCase {
+	subs := make(map[string]event.Subscriber, len(subscribers))
+	for _, s := range subscribers {
+		subs[s.ID()] = s
+	}
+	return &replayEventsUseCase{eventLog: eventLog, deadLetters: deadLetters, subscribers: subs}
+}
+
+type replayEventsUseCase struct {
+	eventLog    event.EventLog
+	deadLetters event.DeadLetterStore
+	subscribers map[string]event.Subscriber
+}
+
+func (uc *replayEventsUseCase) Name() string {
+	return "ReplayEvents"
+}
+
+// Execute 依事件 ID 由舊到新重播；中斷時不調整進度
+func (uc *replayEventsUseCase) Execute(
+	ctx context.Context, req ReqReplayEvents,
+) (*RespReplayEvents, core.UseCaseError) {
+	sub, ok := uc.subscribers[req.SubscriberID]
+	if !ok {
+		return nil, ErrReplayEventsUnknownSubscriber
+	}
+	if req.Topic != "" && req.Topic != sub.Topic() {
+		return nil, ErrReplayEventsTopicMismatch
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return nil, ErrReplayEventsInvalidRange
+	}
+	if req.AdvanceProgress {
+		if ucErr := uc.checkAdvanceProgress(ctx, sub, req); ucErr != nil {
+			return nil, ucErr
+		}
+	}
+
+	filter := event.EventFilter{Topic: sub.Topic(), From: req.From, To: req.To}
+	matched, err := uc.eventLog.CountEvents(ctx, filter)
+	if err != nil {
+		return nil, ErrReplayEventsFindFail.Wrap(err)
+	}
+	resp := &RespReplayEvents{Matched: matched}
+	if req.DryRun || matched == 0 {
+		return resp, nil
+	}
+
+	var throttle <-chan time.Time
+	if req.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(req.Rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	filter.Limit = replayBatchSize
+	for {
+		events, err := uc.eventLog.FindEvents(ctx, filter)
+		if err != nil {
+			return nil, ErrReplayEventsFindFail.Wrap(err)
+		}
+		for _, e := range events {
+			if throttle != nil {
+				select {
+				case <-ctx.Done():
+					return nil, ErrReplayEventsInterrupted.Wrap(ctx.Err())
+				case <-throttle:
+				}
+			} else if ctx.Err() != nil {
+				return nil, ErrReplayEventsInterrupted.Wrap(ctx.Err())
+			}
+
+			if handleErr := event.Invoke(ctx, sub, e); handleErr != nil {
+				if err := uc.deadLetters.Add(ctx, event.NewDeadLetter(sub.ID(), e, 1, handleErr)); err != nil {
+					return nil, ErrReplayEventsSaveDeadLetterFail.Wrap(err)
+				}
+				resp.Failed++
+			} else {
+				resp.Replayed++
+			}
+			resp.LastEventID = e.ID()
+			if req.OnProgress != nil {
+				req.OnProgress(*resp)
+			}
+		}
+		if len(events) < replayBatchSize {
+			break
+		}
+		filter.AfterID = events[len(events)-1].ID()
+	}
+
+	// 只往後推進，重播期間即時分發已處理到更新的事件時維持原進度
+	if req.AdvanceProgress && resp.LastEventID != "" {
+		if err := uc.eventLog.UpdateProgress(ctx, sub.ID(), resp.LastEventID); err != nil {
+			return nil, ErrReplayEventsUpdateProgressFail.Wrap(err)
+		}
+	}
+	return resp, nil
+}
+
+// checkAdvanceProgress 有結束時間時之後的事件未重播；目前進度與 From 之間有未處理的事件時，
+// 推進進度會略過這些事件
+func (uc *replayEventsUseCase) checkAdvanceProgress(
+	ctx context.Context, sub event.Subscriber, req ReqReplayEvents,
+) core.UseCaseError {
+	if !req.To.IsZero() {
+		return ErrReplayEventsAdvanceProgressRange
+	}
+	if req.From.IsZero() {
+		return nil
+	}
+	progress, err := uc.eventLog.FindProgress(ctx)
+	if err != nil {
+		return ErrReplayEventsFindFail.Wrap(err)
+	}
+	var last string
+	for _, p := range progress {
+		if p.SubscriberID == sub.ID() {
+			last = p.LastEventID
+		}
+	}
+	skipped, err := uc.eventLog.CountEvents(ctx, event.EventFilter{Topic: sub.Topic(), AfterID: last, To: req.From})
+	if err != nil {
+		return ErrReplayEventsFindFail.Wrap(err)
+	}
+	if skipped > 0 {
+		return ErrReplayEventsAdvanceProgressGap
+	}
+	return nil
+}
+
+var (
+	ErrReplayEventsUnknownSubscriber = core.NewUseCaseError(
+		"REPLAY_EVENTS", "UNKNOWN_SUBSCRIBER", "找不到訂閱者", core.ErrNotFound)
+	ErrReplayEventsTopicMismatch = core.NewUseCaseError(
+		"REPLAY_EVENTS", "TOPIC_MISMATCH", "訂閱者未訂閱此主題", core.ErrInvalidInput)
+	ErrReplayEventsInvalidRange = core.NewUseCaseError(
+		"REPLAY_EVENTS", "INVALID_RANGE", "結束時間需晚於開始時間", core.ErrInvalidInput)
+	ErrReplayEventsAdvanceProgressRange = core.NewUseCaseError(
+		"REPLAY_EVENTS", "ADVANCE_PROGRESS_RANGE", "調整進度需重播到最新的事件，不可指定結束時間", core.ErrInvalidInput)
+	ErrReplayEventsAdvanceProgressGap = core.NewUseCaseError(
+		"REPLAY_EVENTS", "ADVANCE_PROGRESS_GAP", "訂閱者在開始時間之前還有未處理的事件，調整進度會略過這些事件", core.ErrConflict)
+	ErrReplayEventsInterrupted = core.NewUseCaseError(
+		"REPLAY_EVENTS", "INTERRUPTED", "重播中斷，進度未調整", core.ErrInternal)
+	ErrReplayEventsFindFail = core.NewDBError(
+		"REPLAY_EVENTS", "FIND_FAIL", "find events fail", core.ErrInternal)
+	ErrReplayEventsSaveDeadLetterFail = core.NewDBError(
+		"REPLAY_EVENTS", "SAVE_DEAD_LETTER_FAIL", "save dead letter fail", core.ErrInternal)
+	ErrReplayEventsUpdateProgressFail = core.NewDBError(
+		"REPLAY_EVENTS", "UPDATE_PROGRESS_FAIL", "update subscriber progress fail", core.ErrInternal)
+)
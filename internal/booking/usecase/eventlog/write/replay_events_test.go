@@ -0,0 +1,160 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memDeadLetters struct {
+	letters map[string]event.DeadLetter
+}
+
+func (m *memDeadLetters) Add(ctx context.Context, dl event.DeadLetter) error {
+	m.letters[dl.ID] = dl
+	return nil
+}
+
+func (m *memDeadLetters) List(ctx context.Context, filter event.DeadLetterFilter) ([]event.DeadLetter, error) {
+	return nil, nil
+}
+
+func (m *memDeadLetters) Get(ctx context.Context, id string) (event.DeadLetter, error) {
+	dl, ok := m.letters[id]
+	if !ok {
+		return event.DeadLetter{}, event.ErrDeadLetterNotFound
+	}
+	return dl, nil
+}
+
+func (m *memDeadLetters) Delete(ctx context.Context, id string) error {
+	delete(m.letters, id)
+	return nil
+}
+
+// recordingSubscriber 記錄處理過的事件，failID 的事件處理失敗
+type recordingSubscriber struct {
+	failID string
+	seen   []string
+}
+
+func (s *recordingSubscriber) ID() string    { return "recorder" }
+func (s *recordingSubscriber) Topic() string { return "topic" }
+func (s *recordingSubscriber) Handle(ctx context.Context, e event.Event) error {
+	if e.ID() == s.failID {
+		return errors.New("downstream unavailable")
+	}
+	s.seen = append(s.seen, e.ID())
+	return nil
+}
+
+func TestReplayEvents(t *testing.T) {
+	ctx := t.Context()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return base.Add(time.Duration(i) * time.Hour) }
+
+	setup := func(t *testing.T, progress string) (*event.MemoryStore, *memDeadLetters, *recordingSubscriber, ReplayEventsUseCase) {
+		t.Helper()
+		store := event.NewMemoryStore(event.WithMemoryTTL(0))
+		for i := 1; i <= 5; i++ {
+			id := fmt.Sprintf("evt_%02d", i)
+			require.NoError(t, store.Save(ctx, event.NewStoredEvent(id, "topic", 1, at(i), []byte(`{}`))))
+		}
+		if progress != "" {
+			require.NoError(t, store.SetProgress(ctx, "recorder", progress))
+		}
+		dls := &memDeadLetters{letters: make(map[string]event.DeadLetter)}
+		sub := &recordingSubscriber{}
+		return store, dls, sub, NewReplayEventsUseCase(store, dls, []event.Subscriber{sub})
+	}
+	progressOf := func(t *testing.T, store *event.MemoryStore) string {
+		t.Helper()
+		progress, err := store.FindProgress(ctx)
+		require.NoError(t, err)
+		for _, p := range progress {
+			if p.SubscriberID == "recorder" {
+				return p.LastEventID
+			}
+		}
+		return ""
+	}
+
+	// 未指定 --advance-progress 時重播舊的範圍不影響即時分發的進度
+	t.Run("RangeKeepsProgress", func(t *testing.T) {
+		store, _, sub, uc := setup(t, "evt_05")
+		resp, err := uc.Execute(ctx, ReqReplayEvents{SubscriberID: "recorder", From: at(2), To: at(4)})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"evt_02", "evt_03"}, sub.seen)
+		assert.EqualValues(t, 2, resp.Matched)
+		assert.Equal(t, "evt_05", progressOf(t, store))
+	})
+
+	t.Run("AdvanceProgressRejectsTo", func(t *testing.T) {
+		store, _, sub, uc := setup(t, "evt_05")
+		_, err := uc.Execute(ctx, ReqReplayEvents{SubscriberID: "recorder", From: at(2), To: at(4), AdvanceProgress: true})
+		assert.Equal(t, ErrReplayEventsAdvanceProgressRange, err)
+		assert.Empty(t, sub.seen)
+		assert.Equal(t, "evt_05", progressOf(t, store))
+	})
+
+	// 進度在 evt_01，evt_02 尚未處理，從 evt_03 重播後推進進度會略過 evt_02
+	t.Run("AdvanceProgressRejectsGap", func(t *testing.T) {
+		store, _, sub, uc := setup(t, "evt_01")
+		_, err := uc.Execute(ctx, ReqReplayEvents{SubscriberID: "recorder", From: at(3), AdvanceProgress: true})
+		assert.Equal(t, ErrReplayEventsAdvanceProgressGap, err)
+		assert.Empty(t, sub.seen)
+		assert.Equal(t, "evt_01", progressOf(t, store))
+	})
+
+	t.Run("AdvanceProgressAdvances", func(t *testing.T) {
+		store, dls, sub, uc := setup(t, "evt_02")
+		sub.failID = "evt_04"
+		resp, err := uc.Execute(ctx, ReqReplayEvents{SubscriberID: "recorder", From: at(3), AdvanceProgress: true})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"evt_03", "evt_05"}, sub.seen)
+		assert.Equal(t, 2, resp.Replayed)
+		assert.Equal(t, 1, resp.Failed)
+		// 失敗的事件已寫入 dead letter，與即時分發一樣視為已處理
+		_, dlErr := dls.Get(ctx, event.DeadLetterID("recorder", "evt_04"))
+		require.NoError(t, dlErr)
+		assert.Equal(t, "evt_05", progressOf(t, store))
+	})
+
+	// 重播期間即時分發已處理到更新的事件時不往回調整
+	t.Run("AdvanceProgressNeverMovesBack", func(t *testing.T) {
+		store, _, sub, uc := setup(t, "")
+		uc = NewReplayEventsUseCase(store, &memDeadLetters{letters: make(map[string]event.DeadLetter)},
+			[]event.Subscriber{&advancingSubscriber{recordingSubscriber: sub, store: store, advanceTo: "evt_09"}})
+		_, err := uc.Execute(ctx, ReqReplayEvents{SubscriberID: "recorder", AdvanceProgress: true})
+		require.Nil(t, err)
+		assert.Len(t, sub.seen, 5)
+		assert.Equal(t, "evt_09", progressOf(t, store))
+	})
+
+	t.Run("UnknownSubscriber", func(t *testing.T) {
+		_, _, _, uc := setup(t, "")
+		_, err := uc.Execute(ctx, ReqReplayEvents{SubscriberID: "missing"})
+		assert.Equal(t, ErrReplayEventsUnknownSubscriber, err)
+	})
+}
+
+// advancingSubscriber 模擬重播期間即時分發推進了進度
+type advancingSubscriber struct {
+	*recordingSubscriber
+	store     *event.MemoryStore
+	advanceTo string
+}
+
+func (s *advancingSubscriber) Handle(ctx context.Context, e event.Event) error {
+	if err := s.store.UpdateProgress(ctx, s.ID(), s.advanceTo); err != nil {
+		return err
+	}
+	return s.recordingSubscriber.Handle(ctx, e)
+}
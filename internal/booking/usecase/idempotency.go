@@ -11,16 +11,21 @@ type IdempotencyManager interface {
 	CheckAndSet(key string) bool
 	// Delete 移除 Key (通常用於發生錯誤需要重試時)
 	Delete(key string)
+	// Close 停止定期清理
+	Close()
 }
 
 type idempotencyManager struct {
 	processedKeys sync.Map // key: string, value: time.Time (處理時間)
 	ttl           time.Duration
+	done          chan struct{}
+	closeOnce     sync.Once
 }
 
 func NewIdempotencyManager(ttl time.Duration) IdempotencyManager {
 	m := &idempotencyManager{
-		ttl: ttl,
+		ttl:  ttl,
+		done: make(chan struct{}),
 	}
 	// 定期清理過期的 Key
 	go m.gc()
@@ -43,9 +48,19 @@ func (m *idempotencyManager) Delete(key string) {
 	m.processedKeys.Delete(key)
 }
 
+func (m *idempotencyManager) Close() {
+	m.closeOnce.Do(func() { close(m.done) })
+}
+
 func (m *idempotencyManager) gc() {
 	ticker := time.NewTicker(m.ttl / 2)
-	for range ticker.C {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		}
 		now := time.Now()
 		m.processedKeys.Range(func(key, value interface{}) bool {
 			if t, ok := value.(time.Time); ok {
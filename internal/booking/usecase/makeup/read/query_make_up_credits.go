@@ -0,0 +1,68 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqQueryMakeUpCredits struct {
+	UserID        string
+	OnlyAvailable bool
+}
+
+type QueryMakeUpCreditsUseCase core.ReadUseCase[ReqQueryMakeUpCredits, []*entity.MakeUpCredit]
+
+type queryMakeUpCreditsUseCase struct {
+	repo repository.MakeUpCreditRepository
+}
+
+func NewQueryMakeUpCreditsUseCase(repo repository.MakeUpCreditRepository) QueryMakeUpCreditsUseCase {
+	return &queryMakeUpCreditsUseCase{repo: repo}
+}
+
+func (uc *queryMakeUpCreditsUseCase) Name() string {
+	return "QueryMakeUpCredits"
+}
+
+// Execute 查詢家長的補課資格，依到期日排序；OnlyAvailable 只回傳尚可預約的資格
+func (uc *queryMakeUpCreditsUseCase) Execute(
+	ctx context.Context, req ReqQueryMakeUpCredits,
+) ([]*entity.MakeUpCredit, core.UseCaseError) {
+	if req.UserID == "" {
+		return nil, ErrQueryMakeUpCreditsInvalidInput
+	}
+	credits, err := uc.repo.FindMakeUpCreditsByFilter(ctx, repository.NewFilterMakeUpCreditByUserID(req.UserID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*entity.MakeUpCredit{}, nil
+		}
+		return nil, ErrQueryMakeUpCreditsFail.Wrap(err)
+	}
+	if req.OnlyAvailable {
+		now := time.Now()
+		available := make([]*entity.MakeUpCredit, 0, len(credits))
+		for _, c := range credits {
+			if c.IsAvailableAt(now) {
+				available = append(available, c)
+			}
+		}
+		credits = available
+	}
+	sort.Slice(credits, func(i, j int) bool {
+		return credits[i].ExpiresAt().Before(credits[j].ExpiresAt())
+	})
+	return credits, nil
+}
+
+var (
+	ErrQueryMakeUpCreditsFail = core.NewDBError(
+		"QUERY_MAKE_UP_CREDITS", "QUERY_FAIL", "query make up credits fail", core.ErrInternal)
+	ErrQueryMakeUpCreditsInvalidInput = core.NewUseCaseError(
+		"QUERY_MAKE_UP_CREDITS", "INVALID_INPUT", "user id is required", core.ErrInvalidInput)
+)
@@ -0,0 +1,230 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqBookMakeUp struct {
+	User        entity.User
+	CreditID    string
+	TrainDateID string
+}
+
+// BookMakeUpUseCase 以補課資格預約其他尚有名額的場次
+type BookMakeUpUseCase core.WriteUseCase[ReqBookMakeUp, *entity.Appointment]
+
+type bookMakeUpUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.TeamRepository
+	repository.MakeUpCreditRepository
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewBookMakeUpUseCase(repo bookMakeUpUseCaseRepo) BookMakeUpUseCase {
+	return &bookMakeUpUseCase{
+		repo: repo,
+	}
+}
+
+type bookMakeUpUseCase struct {
+	repo bookMakeUpUseCaseRepo
+}
+
+func (uc *bookMakeUpUseCase) Name() string {
+	return "BookMakeUp"
+}
+
+func (uc *bookMakeUpUseCase) Execute(
+	ctx context.Context, req ReqBookMakeUp,
+) (*entity.Appointment, core.UseCaseError) {
+	credit, findErr := uc.repo.FindMakeUpCreditByID(ctx, req.CreditID)
+	if findErr != nil {
+		if errors.Is(findErr, repository.ErrNotFound) || errors.Is(findErr, repository.ErrInvalidDocumentID) {
+			return nil, ErrBookMakeUpCreditNotFound
+		}
+		return nil, ErrBookMakeUpFindCreditFail.Wrap(findErr)
+	}
+	if credit.User().UserID() != req.User.UserID() {
+		return nil, ErrBookMakeUpCreditNotBelongToUser
+	}
+
+	trainDate, findErr := uc.repo.FindTrainDateByID(ctx, req.TrainDateID)
+	if findErr != nil {
+		return nil, ErrBookMakeUpTrainDateNotFound.Wrap(findErr)
+	}
+	if trainDate.IsCancelled() {
+		return nil, ErrBookMakeUpTrainingCancelled
+	}
+	if !time.Now().Before(trainDate.Period().Start()) {
+		return nil, ErrBookMakeUpTrainingStarted
+	}
+	if ucErr := uc.checkTeam(ctx, trainDate, credit); ucErr != nil {
+		return nil, ucErr
+	}
+	if ucErr := uc.checkDuplicate(ctx, trainDate, credit); ucErr != nil {
+		return nil, ucErr
+	}
+
+	appt, err := entity.NewAppointment(
+		entity.WithCreateAppt(uc.repo.GenerateID(), req.TrainDateID, credit.User(), credit.ChildName()),
+		entity.WithApptStudentID(credit.StudentID()),
+		entity.WithMakeUpOf(credit.LeaveApptID()),
+	)
+	if err != nil {
+		return nil, ErrBookMakeUpNewDomainEntityFail.Wrap(err)
+	}
+	if err := credit.Redeem(appt.ID(), trainDate.ID(), trainDate.Period().Start()); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrMakeUpCreditExpired):
+			return nil, ErrBookMakeUpCreditExpired
+		case errors.Is(err, entity.ErrMakeUpCreditSameTraining):
+			return nil, ErrBookMakeUpSameTraining
+		case errors.Is(err, entity.ErrMakeUpCreditRedeemed), errors.Is(err, entity.ErrMakeUpCreditRevoked):
+			return nil, ErrBookMakeUpCreditUsed
+		}
+		return nil, ErrBookMakeUpNewDomainEntityFail.Wrap(err)
+	}
+
+	// 領域事件隨預約一併寫入，課程包堂數與一般預約相同由訂閱者保留
+	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+		BookingID:  appt.ID(),
+		UserID:     appt.User().UserID(),
+		TrainingID: appt.TrainingID(),
+		OldStatus:  "",
+		NewStatus:  appt.Status().String(),
+		OccurredAt: time.Now(),
+	})
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		// 1. 扣除名額，失敗代表已額滿
+		if repoErr := uc.repo.DeductCapacity(ctx, req.TrainDateID, 1); repoErr != nil {
+			return ErrBookMakeUpDeductCapacityFail.Wrap(repoErr)
+		}
+		// 2. 以版本號鎖定補課資格，避免同一資格重複預約
+		if repoErr := uc.repo.SaveMakeUpCredit(ctx, credit); repoErr != nil {
+			if errors.Is(repoErr, repository.ErrConflict) {
+				return ErrBookMakeUpCreditUsed
+			}
+			return ErrBookMakeUpSaveCreditFail.Wrap(repoErr)
+		}
+		// 不支援交易時由補償歸還補課資格
+		repository.Compensate(ctx, func(ctx context.Context) error {
+			return uc.releaseRedemption(ctx, credit.ID(), appt.ID())
+		})
+		// 3. 儲存補課預約，失敗時名額與補課資格一併回復
+		if repoErr := uc.repo.SaveAppointment(ctx, appt); repoErr != nil {
+			return ErrBookMakeUpSaveApptFail.Wrap(repoErr)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+
+	return appt, nil
+}
+
+// releaseRedemption 重新讀取最新版本後歸還補課資格
+func (uc *bookMakeUpUseCase) releaseRedemption(ctx context.Context, creditID, apptID string) error {
+	reloaded, err := uc.repo.FindMakeUpCreditByID(ctx, creditID)
+	if err != nil {
+		return err
+	}
+	if !reloaded.ReleaseRedemption(apptID) {
+		return nil
+	}
+	if err := uc.repo.SaveMakeUpCredit(ctx, reloaded); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkTeam 團隊場次只有成員可以補課
+func (uc *bookMakeUpUseCase) checkTeam(
+	ctx context.Context, trainDate *entity.TrainDate, credit *entity.MakeUpCredit,
+) core.UseCaseError {
+	if trainDate.TeamID() == "" {
+		return nil
+	}
+	team, err := uc.repo.FindTeamByID(ctx, trainDate.TeamID())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrBookMakeUpTeamMemberOnly
+		}
+		return ErrBookMakeUpFindTeamFail.Wrap(err)
+	}
+	if !team.HasMember(credit.StudentID()) {
+		return ErrBookMakeUpTeamMemberOnly
+	}
+	return nil
+}
+
+// checkDuplicate 同一位學員在同一場次只能有一筆有效預約
+func (uc *bookMakeUpUseCase) checkDuplicate(
+	ctx context.Context, trainDate *entity.TrainDate, credit *entity.MakeUpCredit,
+) core.UseCaseError {
+	appts, err := uc.repo.FindApptsByFilter(ctx, repository.NewFilterApptByTrainID(trainDate.ID()))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return ErrBookMakeUpFindApptFail.Wrap(err)
+	}
+	for _, a := range appts {
+		if a.User().UserID() != credit.User().UserID() || a.Status() != entity.StatusConfirmed {
+			continue
+		}
+		sameStudent := credit.StudentID() != "" && a.StudentID() == credit.StudentID()
+		if sameStudent || a.ChildName() == credit.ChildName() {
+			return ErrBookMakeUpAlreadyBooked
+		}
+	}
+	return nil
+}
+
+var (
+	ErrBookMakeUpCreditNotFound = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "CREDIT_NOT_FOUND", "找不到補課資格", core.ErrNotFound)
+	ErrBookMakeUpFindCreditFail = core.NewDBError(
+		"BOOK_MAKE_UP", "FIND_CREDIT_FAIL", "find make up credit fail", core.ErrInternal)
+	ErrBookMakeUpCreditNotBelongToUser = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "CREDIT_NOT_BELONG_TO_USER", "補課資格不屬於此帳號", core.ErrForbidden)
+	ErrBookMakeUpCreditUsed = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "CREDIT_USED", "補課資格已使用或已失效", core.ErrConflict)
+	ErrBookMakeUpCreditExpired = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "CREDIT_EXPIRED", "補課場次超過補課期限", core.ErrInvalidInput)
+	ErrBookMakeUpSameTraining = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "SAME_TRAINING", "不可補課請假的同一場次", core.ErrInvalidInput)
+	ErrBookMakeUpTrainDateNotFound = core.NewDBError(
+		"BOOK_MAKE_UP", "TRAIN_DATE_NOT_FOUND", "train date not found", core.ErrNotFound)
+	ErrBookMakeUpTrainingCancelled = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "TRAINING_CANCELLED", "此場次已停課", core.ErrConflict)
+	ErrBookMakeUpTrainingStarted = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "TRAINING_STARTED", "此場次已開始", core.ErrConflict)
+	ErrBookMakeUpFindTeamFail = core.NewDBError(
+		"BOOK_MAKE_UP", "FIND_TEAM_FAIL", "find team fail", core.ErrInternal)
+	ErrBookMakeUpTeamMemberOnly = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "TEAM_MEMBER_ONLY", "此場次僅開放團隊成員預約", core.ErrForbidden)
+	ErrBookMakeUpFindApptFail = core.NewDBError(
+		"BOOK_MAKE_UP", "FIND_APPOINTMENT_FAIL", "find appointment fail", core.ErrInternal)
+	ErrBookMakeUpAlreadyBooked = core.NewUseCaseError(
+		"BOOK_MAKE_UP", "ALREADY_BOOKED", "學員已預約此場次", core.ErrConflict)
+	ErrBookMakeUpNewDomainEntityFail = core.NewDomainError(
+		"BOOK_MAKE_UP", "DOMAIN_ERROR", "new domain entity failed", core.ErrInvalidInput)
+	ErrBookMakeUpDeductCapacityFail = core.NewDBError(
+		"BOOK_MAKE_UP", "DEDUCT_CAPACITY_FAIL", "此場次已額滿", core.ErrConflict)
+	ErrBookMakeUpSaveCreditFail = core.NewDBError(
+		"BOOK_MAKE_UP", "SAVE_CREDIT_FAIL", "save make up credit fail", core.ErrInternal)
+	ErrBookMakeUpSaveApptFail = core.NewDBError(
+		"BOOK_MAKE_UP", "SAVE_APPOINTMENT_FAIL", "save appointment fail", core.ErrInternal)
+)
@@ -0,0 +1,164 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqSyncMakeUpCredit struct {
+	OccurredAt time.Time
+	BookingID  string
+	UserID     string
+	TrainingID string
+	OldStatus  string
+	NewStatus  string
+}
+
+// SyncMakeUpCreditUseCase 依預約狀態變更發放、收回或釋放補課資格，回傳 false 代表無需異動
+type SyncMakeUpCreditUseCase core.WriteUseCase[ReqSyncMakeUpCredit, bool]
+
+type syncMakeUpCreditUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.MakeUpCreditRepository
+}
+
+func NewSyncMakeUpCreditUseCase(repo syncMakeUpCreditUseCaseRepo, policy entity.MakeUpPolicy) SyncMakeUpCreditUseCase {
+	return &syncMakeUpCreditUseCase{
+		repo:   repo,
+		policy: policy,
+	}
+}
+
+type syncMakeUpCreditUseCase struct {
+	repo   syncMakeUpCreditUseCaseRepo
+	policy entity.MakeUpPolicy
+}
+
+func (uc *syncMakeUpCreditUseCase) Name() string {
+	return "SyncMakeUpCredit"
+}
+
+func (uc *syncMakeUpCreditUseCase) Execute(
+	ctx context.Context, req ReqSyncMakeUpCredit,
+) (bool, core.UseCaseError) {
+	switch {
+	case req.NewStatus == entity.StatusCancelledLeave.String():
+		return uc.issue(ctx, req.BookingID)
+	case req.OldStatus == entity.StatusCancelledLeave.String() && req.NewStatus == entity.StatusConfirmed.String():
+		// 取消請假或後台恢復出席
+		return uc.revoke(ctx, req.BookingID)
+	case req.NewStatus == entity.StatusCancelled.String(), req.NewStatus == entity.StatusCancelledByCoach.String():
+		return uc.release(ctx, req.BookingID)
+	}
+	return false, nil
+}
+
+// issue 核准請假後發放補課資格，同一筆請假再次請假時恢復原資格
+func (uc *syncMakeUpCreditUseCase) issue(ctx context.Context, apptID string) (bool, core.UseCaseError) {
+	credit, ucErr := uc.findOne(ctx, repository.NewFilterMakeUpCreditByLeaveApptID(apptID))
+	if ucErr != nil {
+		return false, ucErr
+	}
+	if credit != nil {
+		if !credit.Reinstate() {
+			return false, nil
+		}
+		return uc.save(ctx, credit)
+	}
+
+	appt, findErr := uc.repo.FindApptByID(ctx, apptID)
+	if findErr != nil {
+		if errors.Is(findErr, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, ErrSyncMakeUpCreditFindApptFail.Wrap(findErr)
+	}
+	// 待審核或已駁回的請假不發放
+	if appt.LeaveInfo().Status() != entity.LeaveStatusApproved {
+		return false, nil
+	}
+	trainDate, findErr := uc.repo.FindTrainDateByID(ctx, appt.TrainingID())
+	if findErr != nil {
+		return false, ErrSyncMakeUpCreditFindTrainDateFail.Wrap(findErr)
+	}
+	credit, err := entity.IssueMakeUpCredit(uc.repo.GenerateID(), appt, trainDate.Period().Start(), uc.policy)
+	if err != nil {
+		return false, ErrSyncMakeUpCreditDomainFail.Wrap(err)
+	}
+	return uc.save(ctx, credit)
+}
+
+func (uc *syncMakeUpCreditUseCase) revoke(ctx context.Context, apptID string) (bool, core.UseCaseError) {
+	credit, ucErr := uc.findOne(ctx, repository.NewFilterMakeUpCreditByLeaveApptID(apptID))
+	if ucErr != nil || credit == nil {
+		return false, ucErr
+	}
+	if credit.Status() == entity.MakeUpCreditRevoked {
+		return false, nil
+	}
+	if err := credit.Revoke(); err != nil {
+		return false, ErrSyncMakeUpCreditDomainFail.Wrap(err)
+	}
+	return uc.save(ctx, credit)
+}
+
+// release 補課預約取消或停課時恢復補課資格
+func (uc *syncMakeUpCreditUseCase) release(ctx context.Context, apptID string) (bool, core.UseCaseError) {
+	credit, ucErr := uc.findOne(ctx, repository.NewFilterMakeUpCreditByRedeemedApptID(apptID))
+	if ucErr != nil || credit == nil {
+		return false, ucErr
+	}
+	if !credit.ReleaseRedemption(apptID) {
+		return false, nil
+	}
+	return uc.save(ctx, credit)
+}
+
+func (uc *syncMakeUpCreditUseCase) findOne(
+	ctx context.Context, filter repository.FilterMakeUpCredit,
+) (*entity.MakeUpCredit, core.UseCaseError) {
+	credits, err := uc.repo.FindMakeUpCreditsByFilter(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, ErrSyncMakeUpCreditFindCreditFail.Wrap(err)
+	}
+	if len(credits) == 0 {
+		return nil, nil
+	}
+	return credits[0], nil
+}
+
+func (uc *syncMakeUpCreditUseCase) save(ctx context.Context, credit *entity.MakeUpCredit) (bool, core.UseCaseError) {
+	err := uc.repo.SaveMakeUpCredit(ctx, credit)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return false, ErrSyncMakeUpCreditConflict.Wrap(err)
+		}
+		return false, ErrSyncMakeUpCreditSaveFail.Wrap(err)
+	}
+	return true, nil
+}
+
+var (
+	ErrSyncMakeUpCreditFindApptFail = core.NewDBError(
+		"SYNC_MAKE_UP_CREDIT", "FIND_APPOINTMENT_FAIL", "find appointment fail", core.ErrInternal)
+	ErrSyncMakeUpCreditFindTrainDateFail = core.NewDBError(
+		"SYNC_MAKE_UP_CREDIT", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrSyncMakeUpCreditFindCreditFail = core.NewDBError(
+		"SYNC_MAKE_UP_CREDIT", "FIND_CREDIT_FAIL", "find make up credit fail", core.ErrInternal)
+	ErrSyncMakeUpCreditDomainFail = core.NewDomainError(
+		"SYNC_MAKE_UP_CREDIT", "DOMAIN_ERROR", "sync make up credit fail", core.ErrInvalidInput)
+	ErrSyncMakeUpCreditConflict = core.NewDBError(
+		"SYNC_MAKE_UP_CREDIT", "CONFLICT", "make up credit was updated concurrently", core.ErrConflict)
+	ErrSyncMakeUpCreditSaveFail = core.NewDBError(
+		"SYNC_MAKE_UP_CREDIT", "SAVE_CREDIT_FAIL", "save make up credit fail", core.ErrInternal)
+)
@@ -0,0 +1,77 @@
+package write
+
+import (
+	"context"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqRecordPayment struct {
+	PaidAt    time.Time
+	User      entity.User
+	Method    string // CASH 或 BANK_TRANSFER
+	Reference string // 轉帳末五碼或交易序號
+	Note      string
+	Year      int
+	Month     int
+	Amount    int
+}
+
+type RecordPaymentUseCase core.WriteUseCase[ReqRecordPayment, *entity.Payment]
+
+type recordPaymentUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.PaymentRepository
+}
+
+func NewRecordPaymentUseCase(repo recordPaymentUseCaseRepo) RecordPaymentUseCase {
+	return &recordPaymentUseCase{
+		repo: repo,
+	}
+}
+
+type recordPaymentUseCase struct {
+	repo recordPaymentUseCaseRepo
+}
+
+func (uc *recordPaymentUseCase) Name() string {
+	return "RecordPayment"
+}
+
+func (uc *recordPaymentUseCase) Execute(
+	ctx context.Context, req ReqRecordPayment,
+) (*entity.Payment, core.UseCaseError) {
+	method, ok := entity.PaymentMethodFromString(req.Method)
+	if !ok {
+		return nil, ErrRecordPaymentInvalidMethod
+	}
+	payment, err := entity.NewPayment(
+		entity.WithPaymentID(uc.repo.GenerateID()),
+		entity.WithPaymentUser(req.User),
+		entity.WithPaymentPeriod(req.Year, req.Month),
+		entity.WithPaymentAmount(req.Amount),
+		entity.WithPaymentMethod(method),
+		entity.WithPaymentReference(req.Reference),
+		entity.WithPaymentNote(req.Note),
+		entity.WithPaymentPaidAt(req.PaidAt),
+	)
+	if err != nil {
+		return nil, ErrRecordPaymentDomainFail.Wrap(err)
+	}
+	if saveErr := uc.repo.SavePayment(ctx, payment); saveErr != nil {
+		return nil, ErrRecordPaymentSaveFail.Wrap(saveErr)
+	}
+	return payment, nil
+}
+
+var (
+	ErrRecordPaymentInvalidMethod = core.NewUseCaseError(
+		"RECORD_PAYMENT", "INVALID_METHOD", "付款方式不正確", core.ErrInvalidInput)
+	ErrRecordPaymentDomainFail = core.NewDomainError(
+		"RECORD_PAYMENT", "DOMAIN_ERROR", "收款資料不正確，銀行轉帳需填寫轉帳帳號末五碼", core.ErrInvalidInput)
+	ErrRecordPaymentSaveFail = core.NewDBError(
+		"RECORD_PAYMENT", "SAVE_PAYMENT_FAIL", "save payment fail", core.ErrInternal)
+)
@@ -9,10 +9,31 @@ import (
 	readAppt "seanAIgent/internal/booking/usecase/appointment/read"
 	writeAppt "seanAIgent/internal/booking/usecase/appointment/write"
 	"seanAIgent/internal/booking/usecase/core"
+	readCredit "seanAIgent/internal/booking/usecase/credit/read"
+	writeCredit "seanAIgent/internal/booking/usecase/credit/write"
+	readDeadLetter "seanAIgent/internal/booking/usecase/deadletter/read"
+	writeDeadLetter "seanAIgent/internal/booking/usecase/deadletter/write"
+	readEventLog "seanAIgent/internal/booking/usecase/eventlog/read"
+	writeEventLog "seanAIgent/internal/booking/usecase/eventlog/write"
+	readMakeUp "seanAIgent/internal/booking/usecase/makeup/read"
+	writeMakeUp "seanAIgent/internal/booking/usecase/makeup/write"
+	writePayment "seanAIgent/internal/booking/usecase/payment/write"
+	readRole "seanAIgent/internal/booking/usecase/role/read"
+	writeRole "seanAIgent/internal/booking/usecase/role/write"
+	readSeries "seanAIgent/internal/booking/usecase/series/read"
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
 	readStats "seanAIgent/internal/booking/usecase/stats/read"
 	writeStats "seanAIgent/internal/booking/usecase/stats/write"
+	readStudent "seanAIgent/internal/booking/usecase/student/read"
+	writeStudent "seanAIgent/internal/booking/usecase/student/write"
+	readTeam "seanAIgent/internal/booking/usecase/team/read"
+	writeTeam "seanAIgent/internal/booking/usecase/team/write"
 	readTrain "seanAIgent/internal/booking/usecase/traindate/read"
 	writeTrain "seanAIgent/internal/booking/usecase/traindate/write"
+	readWaitlist "seanAIgent/internal/booking/usecase/waitlist/read"
+	writeWaitlist "seanAIgent/internal/booking/usecase/waitlist/write"
+	readWebhook "seanAIgent/internal/booking/usecase/webhook/read"
+	writeWebhook "seanAIgent/internal/booking/usecase/webhook/write"
 	"seanAIgent/internal/event"
 
 	"github.com/google/wire"
@@ -23,29 +44,78 @@ type Repository interface {
 	repository.TrainRepository
 	repository.AppointmentRepository
 	repository.StatsRepository
+	repository.WaitlistRepository
+	repository.TrainingSeriesRepository
+	repository.CreditLedgerRepository
+	repository.PaymentRepository
+	repository.StudentRepository
+	repository.TeamRepository
+	repository.UserRolesRepository
+	repository.WebhookRepository
+	repository.MakeUpCreditRepository
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
 type ServiceAggregator struct {
 	service.TrainDateService
+	service.WebhookSender
 }
 
 // 為每個 UseCase 定定義一個包裝過的 Provider
 func ProvideCreateTrainDateUC(
-	repo Repository, svc ServiceAggregator,
+	repo Repository, svc ServiceAggregator, bookingPolicy entity.BookingPolicy,
 ) core.WriteUseCase[writeTrain.ReqCreateTrainDate, *entity.TrainDate] {
-	return core.WithWriteOTel(writeTrain.NewCreateTrainDateUseCase(repo, svc))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTrain.NewCreateTrainDateUseCase(repo, svc, bookingPolicy), entity.PermTrainingWrite))
 }
 
 func ProvideBatchCreateTrainDateUC(
-	repo Repository, svc ServiceAggregator,
+	repo Repository, svc ServiceAggregator, bookingPolicy entity.BookingPolicy,
 ) core.WriteUseCase[[]writeTrain.ReqCreateTrainDate, []*entity.TrainDate] {
-	return core.WithWriteOTel(writeTrain.NewBatchCreateTrainDateUseCase(repo, svc))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTrain.NewBatchCreateTrainDateUseCase(repo, svc, bookingPolicy), entity.PermTrainingWrite))
 }
 
 func ProvideDeleteTrainDateUC(
 	repo Repository,
 ) core.WriteUseCase[writeTrain.ReqDeleteTrainDate, *entity.TrainDate] {
-	return core.WithWriteOTel(writeTrain.NewDeleteTrainDateUseCase(repo))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTrain.NewDeleteTrainDateUseCase(repo), entity.PermTrainingWrite))
+}
+
+func ProvideUpdateTrainDateBookingPolicyUC(
+	repo Repository,
+) writeTrain.UpdateTrainDateBookingPolicyUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTrain.NewUpdateTrainDateBookingPolicyUseCase(repo), entity.PermTrainingWrite))
+}
+
+func ProvideAssignTrainDateTeamUC(
+	repo Repository,
+) writeTrain.AssignTrainDateTeamUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTrain.NewAssignTrainDateTeamUseCase(repo), entity.PermTrainingWrite))
+}
+
+func ProvideCancelTrainDateUC(
+	repo Repository,
+) writeTrain.CancelTrainDateUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTrain.NewCancelTrainDateUseCase(repo), entity.PermTrainingWrite))
+}
+
+func ProvideRescheduleTrainDateUC(
+	repo Repository, svc ServiceAggregator,
+) writeTrain.RescheduleTrainDateUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTrain.NewRescheduleTrainDateUseCase(repo, svc), entity.PermTrainingWrite))
+}
+
+func ProvideReconcileCapacityUC(
+	repo Repository,
+) writeTrain.ReconcileCapacityUseCase {
+	return core.WithWriteOTel(writeTrain.NewReconcileCapacityUseCase(repo))
 }
 
 func ProvideQueryFutureTrainUC(
@@ -69,69 +139,91 @@ func ProvideUserQueryTrainByIDUC(
 // Appointment UseCase
 
 func ProvideCreateApptUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.CreateApptUseCase {
-	return core.WithWriteOTel(writeAppt.NewCreateApptUseCase(repo, bus))
+	return core.WithWriteOTel(writeAppt.NewCreateApptUseCase(repo))
 }
 
 func ProvideCancelApptUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.CancelApptUseCase {
-	return core.WithWriteOTel(writeAppt.NewCancelApptUseCase(repo, bus))
+	return core.WithWriteOTel(writeAppt.NewCancelApptUseCase(repo))
 }
 
 func ProvideCheckInUC(
 	adminUC writeAppt.AdminCheckInUseCase,
+	repo Repository,
 ) writeAppt.CheckInUseCase {
-	return core.WithWriteOTel(writeAppt.NewCheckInUseCase(adminUC))
+	return core.WithWriteOTel(writeAppt.NewCheckInUseCase(adminUC, repo))
 }
 
 func ProvideAdminCheckInUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.AdminCheckInUseCase {
-	return core.WithWriteOTel(writeAppt.NewAdminCheckInUseCase(repo, bus))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeAppt.NewAdminCheckInUseCase(repo), entity.PermAttendanceWrite))
 }
 
 func ProvideAdminToggleCheckInUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.AdminToggleCheckInUseCase {
-	return core.WithWriteOTel(writeAppt.NewAdminToggleCheckInUseCase(repo, bus))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeAppt.NewAdminToggleCheckInUseCase(repo), entity.PermAttendanceWrite))
 }
 
 func ProvideAdminCreateLeaveUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.AdminCreateLeaveUseCase {
-	return core.WithWriteOTel(writeAppt.NewAdminCreateLeaveUseCase(repo, bus))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeAppt.NewAdminCreateLeaveUseCase(repo), entity.PermAttendanceWrite))
 }
 
 func ProvideAdminRestoreFromLeaveUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.AdminRestoreFromLeaveUseCase {
-	return core.WithWriteOTel(writeAppt.NewAdminRestoreFromLeaveUseCase(repo, bus))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeAppt.NewAdminRestoreFromLeaveUseCase(repo), entity.PermAttendanceWrite))
+}
+
+func ProvideApproveLeaveUC(
+	repo Repository,
+) writeAppt.ApproveLeaveUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeAppt.NewApproveLeaveUseCase(repo), entity.PermAttendanceWrite))
+}
+
+func ProvideRejectLeaveUC(
+	repo Repository,
+) writeAppt.RejectLeaveUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeAppt.NewRejectLeaveUseCase(repo), entity.PermAttendanceWrite))
 }
 
 func ProvideAdminCreateWalkInUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.AdminCreateWalkInUseCase {
-	return core.WithWriteOTel(writeAppt.NewAdminCreateWalkInUseCase(repo, bus))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeAppt.NewAdminCreateWalkInUseCase(repo), entity.PermAttendanceWrite))
 }
 
 func ProvideAdminQueryStudentsUC(
 	repo Repository,
 ) readStats.AdminQueryStudentsUseCase {
-	return core.WithReadOTel(readStats.NewAdminQueryStudentsUseCase(repo))
+	return core.WithReadOTel(core.WithReadPermission(
+		readStats.NewAdminQueryStudentsUseCase(repo), entity.PermAttendanceWrite))
 }
 
 func ProvideAutoMarkAbsentUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.AutoMarkAbsentUseCase {
-	return core.WithWriteOTel(writeAppt.NewAutoMarkAbsentUseCase(repo, bus))
+	return core.WithWriteOTel(writeAppt.NewAutoMarkAbsentUseCase(repo))
 }
 
 func ProvideAdminBatchUpdateAttendanceUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.AdminBatchUpdateAttendanceUseCase {
-	return core.WithWriteOTel(writeAppt.NewAdminBatchUpdateAttendanceUseCase(repo, bus))
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeAppt.NewAdminBatchUpdateAttendanceUseCase(repo), entity.PermAttendanceWrite))
 }
 
 func ProvideQueryUserBookingsUC(
@@ -141,15 +233,21 @@ func ProvideQueryUserBookingsUC(
 }
 
 func ProvideCreateLeaveUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.CreateLeaveUseCase {
-	return core.WithWriteOTel(writeAppt.NewCreateLeaveUseCase(repo, bus))
+	return core.WithWriteOTel(writeAppt.NewCreateLeaveUseCase(repo))
 }
 
 func ProvideCancelLeaveUC(
-	repo Repository, bus event.Bus,
+	repo Repository,
 ) writeAppt.CancelLeaveUseCase {
-	return core.WithWriteOTel(writeAppt.NewCancelLeaveUseCase(repo, bus))
+	return core.WithWriteOTel(writeAppt.NewCancelLeaveUseCase(repo))
+}
+
+func ProvideUpdateLeaveReasonUC(
+	repo Repository,
+) writeAppt.UpdateLeaveReasonUseCase {
+	return core.WithWriteOTel(writeAppt.NewUpdateLeaveReasonUseCase(repo))
 }
 
 func ProvideFindNearestTrainByTimeUC(
@@ -167,7 +265,8 @@ func ProvideFindTrainHasApptsByIdUC(
 func ProvideAdminQueryTrainRangeUC(
 	repo Repository,
 ) core.ReadUseCase[readTrain.ReqAdminQueryTrainRange, []*entity.TrainDateHasApptState] {
-	return core.WithReadOTel(readTrain.NewAdminQueryTrainRangeUseCase(repo))
+	return core.WithReadOTel(core.WithReadPermission(
+		readTrain.NewAdminQueryTrainRangeUseCase(repo), entity.PermReportRead))
 }
 
 func ProvideGetUserMonthlyStatsUC(
@@ -201,30 +300,394 @@ func ProvideBatchSyncMonthlyStatsUC(
 }
 
 func ProvideQueryMonthlyUserReportsUC(
-	repo Repository,
+	repo Repository, policy entity.BillingPolicy,
 ) readStats.QueryMonthlyUserReportsUseCase {
-	return core.WithReadOTel(readStats.NewQueryMonthlyUserReportsUseCase(repo))
+	return core.WithReadOTel(core.WithReadPermission(
+		readStats.NewQueryMonthlyUserReportsUseCase(repo, policy), entity.PermReportRead))
 }
 
 func ProvideGetBusinessAnalyticsUC(
 	repo Repository,
 ) readStats.GetBusinessAnalyticsUseCase {
-	return core.WithReadOTel(readStats.NewGetBusinessAnalyticsUseCase(repo))
+	return core.WithReadOTel(core.WithReadPermission(
+		readStats.NewGetBusinessAnalyticsUseCase(repo), entity.PermReportRead))
 }
 
 func ProvideGetUserDetailUC(
 	repo Repository,
 ) readStats.GetUserDetailUseCase {
-	return core.WithReadOTel(readStats.NewGetUserDetailUseCase(repo))
+	return core.WithReadOTel(core.WithReadPermission(
+		readStats.NewGetUserDetailUseCase(repo), entity.PermUserPIIRead))
+}
+
+// Waitlist UseCase
+
+func ProvideJoinWaitlistUC(
+	repo Repository,
+) writeWaitlist.JoinWaitlistUseCase {
+	return core.WithWriteOTel(writeWaitlist.NewJoinWaitlistUseCase(repo))
+}
+
+func ProvideLeaveWaitlistUC(
+	repo Repository,
+) writeWaitlist.LeaveWaitlistUseCase {
+	return core.WithWriteOTel(writeWaitlist.NewLeaveWaitlistUseCase(repo))
+}
+
+func ProvideAdminReorderWaitlistUC(
+	repo Repository,
+) writeWaitlist.AdminReorderWaitlistUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeWaitlist.NewAdminReorderWaitlistUseCase(repo), entity.PermAttendanceWrite))
+}
+
+func ProvidePromoteWaitlistUC(
+	repo Repository,
+) writeWaitlist.PromoteWaitlistUseCase {
+	return core.WithWriteOTel(writeWaitlist.NewPromoteWaitlistUseCase(repo))
+}
+
+func ProvideQueryWaitlistUC(
+	repo Repository,
+) readWaitlist.QueryWaitlistUseCase {
+	return core.WithReadOTel(readWaitlist.NewQueryWaitlistUseCase(repo))
+}
+
+// Training Series UseCase
+
+func ProvideCreateTrainingSeriesUC(
+	repo Repository, svc ServiceAggregator, bookingPolicy entity.BookingPolicy,
+) writeSeries.CreateTrainingSeriesUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeSeries.NewCreateTrainingSeriesUseCase(repo, svc, bookingPolicy), entity.PermTrainingWrite))
+}
+
+func ProvideMaterializeTrainingSeriesUC(
+	repo Repository, svc ServiceAggregator, bookingPolicy entity.BookingPolicy,
+) writeSeries.MaterializeTrainingSeriesUseCase {
+	return core.WithWriteOTel(writeSeries.NewMaterializeTrainingSeriesUseCase(repo, svc, bookingPolicy))
+}
+
+func ProvideUpdateTrainingSeriesUC(
+	repo Repository, svc ServiceAggregator, bookingPolicy entity.BookingPolicy,
+) writeSeries.UpdateTrainingSeriesUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeSeries.NewUpdateTrainingSeriesUseCase(repo, svc, bookingPolicy), entity.PermTrainingWrite))
+}
+
+func ProvideDeleteTrainingSeriesUC(
+	repo Repository,
+) writeSeries.DeleteTrainingSeriesUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeSeries.NewDeleteTrainingSeriesUseCase(repo), entity.PermTrainingWrite))
+}
+
+func ProvideQueryTrainingSeriesUC(
+	repo Repository,
+) readSeries.QueryTrainingSeriesUseCase {
+	return core.WithReadOTel(readSeries.NewQueryTrainingSeriesUseCase(repo))
+}
+
+// Credit Ledger UseCase
+
+func ProvideAdminTopUpCreditsUC(
+	repo Repository,
+) writeCredit.AdminTopUpCreditsUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeCredit.NewAdminTopUpCreditsUseCase(repo), entity.PermBillingWrite))
+}
+
+func ProvideApplyApptCreditUC(
+	repo Repository,
+) writeCredit.ApplyApptCreditUseCase {
+	return core.WithWriteOTel(writeCredit.NewApplyApptCreditUseCase(repo, entity.DefaultCreditPolicy()))
+}
+
+func ProvideSettleCreditLedgerUC(
+	repo Repository,
+) writeCredit.SettleCreditLedgerUseCase {
+	return core.WithWriteOTel(writeCredit.NewSettleCreditLedgerUseCase(repo))
+}
+
+func ProvideQueryCreditLedgerUC(
+	repo Repository,
+) readCredit.QueryCreditLedgerUseCase {
+	return core.WithReadOTel(readCredit.NewQueryCreditLedgerUseCase(repo))
+}
+
+// MakeUp UseCase
+
+func ProvideSyncMakeUpCreditUC(
+	repo Repository,
+) writeMakeUp.SyncMakeUpCreditUseCase {
+	return core.WithWriteOTel(writeMakeUp.NewSyncMakeUpCreditUseCase(repo, entity.DefaultMakeUpPolicy()))
+}
+
+func ProvideBookMakeUpUC(
+	repo Repository,
+) writeMakeUp.BookMakeUpUseCase {
+	return core.WithWriteOTel(writeMakeUp.NewBookMakeUpUseCase(repo))
+}
+
+func ProvideQueryMakeUpCreditsUC(
+	repo Repository,
+) readMakeUp.QueryMakeUpCreditsUseCase {
+	return core.WithReadOTel(readMakeUp.NewQueryMakeUpCreditsUseCase(repo))
+}
+
+// Payment UseCase
+
+func ProvideRecordPaymentUC(
+	repo Repository,
+) writePayment.RecordPaymentUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writePayment.NewRecordPaymentUseCase(repo), entity.PermBillingWrite))
+}
+
+// Student UseCase
+
+func ProvideCreateStudentUC(
+	repo Repository,
+) writeStudent.CreateStudentUseCase {
+	return core.WithWriteOTel(writeStudent.NewCreateStudentUseCase(repo))
+}
+
+func ProvideUpdateStudentUC(
+	repo Repository,
+) writeStudent.UpdateStudentUseCase {
+	return core.WithWriteOTel(writeStudent.NewUpdateStudentUseCase(repo))
+}
+
+func ProvideDeleteStudentUC(
+	repo Repository,
+) writeStudent.DeleteStudentUseCase {
+	return core.WithWriteOTel(writeStudent.NewDeleteStudentUseCase(repo))
+}
+
+func ProvideMergeStudentsUC(
+	repo Repository,
+) writeStudent.MergeStudentsUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeStudent.NewMergeStudentsUseCase(repo), entity.PermStudentWrite))
+}
+
+func ProvideMigrateChildNamesUC(
+	repo Repository,
+) writeStudent.MigrateChildNamesUseCase {
+	return core.WithWriteOTel(writeStudent.NewMigrateChildNamesUseCase(repo))
+}
+
+func ProvideQueryStudentsUC(
+	repo Repository,
+) readStudent.QueryStudentsUseCase {
+	return core.WithReadOTel(readStudent.NewQueryStudentsUseCase(repo))
+}
+
+// Team UseCase
+
+func ProvideCreateTeamUC(
+	repo Repository,
+) writeTeam.CreateTeamUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTeam.NewCreateTeamUseCase(repo), entity.PermTeamWrite))
+}
+
+func ProvideUpdateTeamUC(
+	repo Repository,
+) writeTeam.UpdateTeamUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTeam.NewUpdateTeamUseCase(repo), entity.PermTeamWrite))
+}
+
+func ProvideUpdateTeamMembersUC(
+	repo Repository,
+) writeTeam.UpdateTeamMembersUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTeam.NewUpdateTeamMembersUseCase(repo), entity.PermTeamWrite))
+}
+
+func ProvideDeleteTeamUC(
+	repo Repository,
+) writeTeam.DeleteTeamUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeTeam.NewDeleteTeamUseCase(repo), entity.PermTeamWrite))
+}
+
+func ProvideQueryTeamsUC(
+	repo Repository,
+) readTeam.QueryTeamsUseCase {
+	return core.WithReadOTel(readTeam.NewQueryTeamsUseCase(repo))
+}
+
+func ProvideQueryTeamMembersUC(
+	repo Repository,
+) readTeam.QueryTeamMembersUseCase {
+	return core.WithReadOTel(readTeam.NewQueryTeamMembersUseCase(repo))
+}
+
+// Role UseCase
+
+func ProvideAssignUserRolesUC(
+	repo Repository,
+) writeRole.AssignUserRolesUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeRole.NewAssignUserRolesUseCase(repo), entity.PermRoleWrite))
+}
+
+func ProvideRemoveUserRolesUC(
+	repo Repository,
+) writeRole.RemoveUserRolesUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeRole.NewRemoveUserRolesUseCase(repo), entity.PermRoleWrite))
+}
+
+func ProvideQueryUserRolesUC(
+	repo Repository,
+) readRole.QueryUserRolesUseCase {
+	return core.WithReadOTel(core.WithReadPermission(
+		readRole.NewQueryUserRolesUseCase(repo), entity.PermRoleWrite))
+}
+
+// ProvideResolveActorUC 每個請求都會執行，不加 OTel 避免產生大量 span
+func ProvideResolveActorUC(
+	repo Repository,
+) readRole.ResolveActorUseCase {
+	return readRole.NewResolveActorUseCase(repo)
+}
+
+func ProvideQueryDeadLettersUC(
+	store event.DeadLetterStore,
+) readDeadLetter.QueryDeadLettersUseCase {
+	return core.WithReadOTel(core.WithReadPermission(
+		readDeadLetter.NewQueryDeadLettersUseCase(store), entity.PermEventWrite))
+}
+
+func ProvideGetDeadLetterUC(
+	store event.DeadLetterStore,
+) readDeadLetter.GetDeadLetterUseCase {
+	return core.WithReadOTel(core.WithReadPermission(
+		readDeadLetter.NewGetDeadLetterUseCase(store), entity.PermEventWrite))
+}
+
+func ProvideReplayDeadLetterUC(
+	store event.DeadLetterStore, subscribers []event.Subscriber,
+) writeDeadLetter.ReplayDeadLetterUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeDeadLetter.NewReplayDeadLetterUseCase(store, subscribers), entity.PermEventWrite))
+}
+
+func ProvideDiscardDeadLetterUC(
+	store event.DeadLetterStore,
+) writeDeadLetter.DiscardDeadLetterUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeDeadLetter.NewDiscardDeadLetterUseCase(store), entity.PermEventWrite))
+}
+
+func ProvideReplayEventsUC(
+	eventLog event.EventLog, deadLetters event.DeadLetterStore, subscribers []event.Subscriber,
+) writeEventLog.ReplayEventsUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeEventLog.NewReplayEventsUseCase(eventLog, deadLetters, subscribers), entity.PermEventWrite))
+}
+
+// ProvideArchiveEventsUC 由 cron 每天呼叫，事件紀錄因 TTL 刪除前封存
+func ProvideArchiveEventsUC(archiver *event.Archiver) writeEventLog.ArchiveEventsUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeEventLog.NewArchiveEventsUseCase(archiver), entity.PermEventWrite))
+}
+
+func ProvideRedispatchEventUC(
+	eventLog event.EventLog, deadLetters event.DeadLetterStore, subscribers []event.Subscriber,
+) writeEventLog.RedispatchEventUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeEventLog.NewRedispatchEventUseCase(eventLog, deadLetters, subscribers), entity.PermEventWrite))
+}
+
+func ProvideQueryEventsUC(
+	eventLog event.EventLog,
+) readEventLog.QueryEventsUseCase {
+	return core.WithReadOTel(core.WithReadPermission(
+		readEventLog.NewQueryEventsUseCase(eventLog), entity.PermEventWrite))
+}
+
+func ProvideGetEventUC(
+	eventLog event.EventLog,
+) readEventLog.GetEventUseCase {
+	return core.WithReadOTel(core.WithReadPermission(
+		readEventLog.NewGetEventUseCase(eventLog), entity.PermEventWrite))
+}
+
+func ProvideQuerySubscriberProgressUC(
+	eventLog event.EventLog, subscribers []event.Subscriber,
+) readEventLog.QuerySubscriberProgressUseCase {
+	return core.WithReadOTel(core.WithReadPermission(
+		readEventLog.NewQuerySubscriberProgressUseCase(eventLog, subscribers), entity.PermEventWrite))
+}
+
+// Webhook UseCase
+
+func ProvideCreateWebhookEndpointUC(
+	repo Repository,
+) writeWebhook.CreateWebhookEndpointUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeWebhook.NewCreateWebhookEndpointUseCase(repo), entity.PermWebhookWrite))
+}
+
+func ProvideUpdateWebhookEndpointUC(
+	repo Repository,
+) writeWebhook.UpdateWebhookEndpointUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeWebhook.NewUpdateWebhookEndpointUseCase(repo), entity.PermWebhookWrite))
+}
+
+func ProvideDeleteWebhookEndpointUC(
+	repo Repository,
+) writeWebhook.DeleteWebhookEndpointUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeWebhook.NewDeleteWebhookEndpointUseCase(repo), entity.PermWebhookWrite))
+}
+
+func ProvidePingWebhookEndpointUC(
+	repo Repository, svc ServiceAggregator,
+) writeWebhook.PingWebhookEndpointUseCase {
+	return core.WithWriteOTel(core.WithWritePermission(
+		writeWebhook.NewPingWebhookEndpointUseCase(repo, svc), entity.PermWebhookWrite))
+}
+
+// ProvideQueryWebhookEndpointsUC 回應包含 secret，查詢同樣需要管理權限
+func ProvideQueryWebhookEndpointsUC(
+	repo Repository,
+) readWebhook.QueryWebhookEndpointsUseCase {
+	return core.WithReadOTel(core.WithReadPermission(
+		readWebhook.NewQueryWebhookEndpointsUseCase(repo), entity.PermWebhookWrite))
+}
+
+func ProvideQueryWebhookDeliveriesUC(
+	repo Repository,
+) readWebhook.QueryWebhookDeliveriesUseCase {
+	return core.WithReadOTel(core.WithReadPermission(
+		readWebhook.NewQueryWebhookDeliveriesUseCase(repo), entity.PermWebhookWrite))
 }
 
 func ProvideSubscribers(
 	repo Repository,
+	svc ServiceAggregator,
+	promoteWaitlistUC writeWaitlist.PromoteWaitlistUseCase,
+	applyApptCreditUC writeCredit.ApplyApptCreditUseCase,
+	settleCreditLedgerUC writeCredit.SettleCreditLedgerUseCase,
+	syncMakeUpCreditUC writeMakeUp.SyncMakeUpCreditUseCase,
 ) []event.Subscriber {
 	subs := []event.Subscriber{
 		infra.NewCacheSubscriber(repo, repo),
 	}
+	subs = append(subs, infra.NewLeaveCacheSubscriber(repo, repo)...)
+	subs = append(subs, infra.NewTrainDateCacheSubscriber(repo)...)
+	subs = append(subs, infra.NewStudentCacheSubscriber(repo)...)
 	subs = append(subs, infra.NewUserMonthlyStatsSubscriber(repo, repo)...)
+	subs = append(subs, infra.NewWaitlistPromotionSubscriber(promoteWaitlistUC)...)
+	subs = append(subs, infra.NewCreditLedgerSubscriber(applyApptCreditUC, settleCreditLedgerUC)...)
+	subs = append(subs, infra.NewMakeUpCreditSubscriber(syncMakeUpCreditUC)...)
+	subs = append(subs, infra.NewWebhookSubscribers(repo, svc)...)
 	return subs
 }
 
@@ -232,6 +695,11 @@ var UseCaseSet = wire.NewSet(
 	ProvideCreateTrainDateUC,
 	ProvideBatchCreateTrainDateUC,
 	ProvideDeleteTrainDateUC,
+	ProvideUpdateTrainDateBookingPolicyUC,
+	ProvideAssignTrainDateTeamUC,
+	ProvideCancelTrainDateUC,
+	ProvideRescheduleTrainDateUC,
+	ProvideReconcileCapacityUC,
 	ProvideQueryFutureTrainUC,
 	ProvideUserQueryFutureTrainUC,
 	ProvideUserQueryTrainByIDUC,
@@ -251,9 +719,12 @@ var UseCaseSet = wire.NewSet(
 	ProvideAdminBatchUpdateAttendanceUC,
 	ProvideQueryUserBookingsUC,
 	ProvideCancelLeaveUC,
+	ProvideUpdateLeaveReasonUC,
 	ProvideCreateLeaveUC,
 	ProvideAdminCreateLeaveUC,
 	ProvideAdminRestoreFromLeaveUC,
+	ProvideApproveLeaveUC,
+	ProvideRejectLeaveUC,
 
 	ProvideGetUserMonthlyStatsUC,
 	ProvideQueryTwoWeeksScheduleUC,
@@ -263,6 +734,66 @@ var UseCaseSet = wire.NewSet(
 	ProvideGetBusinessAnalyticsUC,
 	ProvideGetUserDetailUC,
 
+	ProvideJoinWaitlistUC,
+	ProvideLeaveWaitlistUC,
+	ProvideAdminReorderWaitlistUC,
+	ProvidePromoteWaitlistUC,
+	ProvideQueryWaitlistUC,
+
+	ProvideCreateTrainingSeriesUC,
+	ProvideMaterializeTrainingSeriesUC,
+	ProvideUpdateTrainingSeriesUC,
+	ProvideDeleteTrainingSeriesUC,
+	ProvideQueryTrainingSeriesUC,
+
+	ProvideAdminTopUpCreditsUC,
+	ProvideApplyApptCreditUC,
+	ProvideSettleCreditLedgerUC,
+	ProvideQueryCreditLedgerUC,
+
+	ProvideSyncMakeUpCreditUC,
+	ProvideBookMakeUpUC,
+	ProvideQueryMakeUpCreditsUC,
+
+	ProvideRecordPaymentUC,
+
+	ProvideCreateStudentUC,
+	ProvideUpdateStudentUC,
+	ProvideDeleteStudentUC,
+	ProvideMergeStudentsUC,
+	ProvideMigrateChildNamesUC,
+	ProvideQueryStudentsUC,
+
+	ProvideCreateTeamUC,
+	ProvideUpdateTeamUC,
+	ProvideUpdateTeamMembersUC,
+	ProvideDeleteTeamUC,
+	ProvideQueryTeamsUC,
+	ProvideQueryTeamMembersUC,
+
+	ProvideAssignUserRolesUC,
+	ProvideRemoveUserRolesUC,
+	ProvideQueryUserRolesUC,
+	ProvideResolveActorUC,
+
+	ProvideQueryDeadLettersUC,
+	ProvideGetDeadLetterUC,
+	ProvideReplayDeadLetterUC,
+	ProvideDiscardDeadLetterUC,
+	ProvideReplayEventsUC,
+	ProvideArchiveEventsUC,
+	ProvideRedispatchEventUC,
+	ProvideQueryEventsUC,
+	ProvideGetEventUC,
+	ProvideQuerySubscriberProgressUC,
+
+	ProvideCreateWebhookEndpointUC,
+	ProvideUpdateWebhookEndpointUC,
+	ProvideDeleteWebhookEndpointUC,
+	ProvidePingWebhookEndpointUC,
+	ProvideQueryWebhookEndpointsUC,
+	ProvideQueryWebhookDeliveriesUC,
+
 	ProvideSubscribers,
 	event.EventSet,
 	ProvideIdempotencyManager,
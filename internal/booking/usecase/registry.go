@@ -6,10 +6,31 @@ import (
 	readAppt "seanAIgent/internal/booking/usecase/appointment/read"
 	writeAppt "seanAIgent/internal/booking/usecase/appointment/write"
 	"seanAIgent/internal/booking/usecase/core"
+	readCredit "seanAIgent/internal/booking/usecase/credit/read"
+	writeCredit "seanAIgent/internal/booking/usecase/credit/write"
+	readDeadLetter "seanAIgent/internal/booking/usecase/deadletter/read"
+	writeDeadLetter "seanAIgent/internal/booking/usecase/deadletter/write"
+	readEventLog "seanAIgent/internal/booking/usecase/eventlog/read"
+	writeEventLog "seanAIgent/internal/booking/usecase/eventlog/write"
+	readMakeUp "seanAIgent/internal/booking/usecase/makeup/read"
+	writeMakeUp "seanAIgent/internal/booking/usecase/makeup/write"
+	writePayment "seanAIgent/internal/booking/usecase/payment/write"
+	readRole "seanAIgent/internal/booking/usecase/role/read"
+	writeRole "seanAIgent/internal/booking/usecase/role/write"
+	readSeries "seanAIgent/internal/booking/usecase/series/read"
+	writeSeries "seanAIgent/internal/booking/usecase/series/write"
 	readStats "seanAIgent/internal/booking/usecase/stats/read"
 	writeStats "seanAIgent/internal/booking/usecase/stats/write"
+	readStudent "seanAIgent/internal/booking/usecase/student/read"
+	writeStudent "seanAIgent/internal/booking/usecase/student/write"
+	readTeam "seanAIgent/internal/booking/usecase/team/read"
+	writeTeam "seanAIgent/internal/booking/usecase/team/write"
 	readTrain "seanAIgent/internal/booking/usecase/traindate/read"
 	writeTrain "seanAIgent/internal/booking/usecase/traindate/write"
+	readWaitlist "seanAIgent/internal/booking/usecase/waitlist/read"
+	writeWaitlist "seanAIgent/internal/booking/usecase/waitlist/write"
+	readWebhook "seanAIgent/internal/booking/usecase/webhook/read"
+	writeWebhook "seanAIgent/internal/booking/usecase/webhook/write"
 	"seanAIgent/internal/event"
 )
 
@@ -17,6 +38,16 @@ type Registry struct {
 	CreateTrainDate      core.WriteUseCase[writeTrain.ReqCreateTrainDate, *entity.TrainDate]
 	BatchCreateTrainDate core.WriteUseCase[[]writeTrain.ReqCreateTrainDate, []*entity.TrainDate]
 	DeleteTrainDate      core.WriteUseCase[writeTrain.ReqDeleteTrainDate, *entity.TrainDate]
+	// UpdateTrainDateBookingPolicy 調整單一場次的預約規則
+	UpdateTrainDateBookingPolicy writeTrain.UpdateTrainDateBookingPolicyUseCase
+	// AssignTrainDateTeam 綁定或解除場次的團隊
+	AssignTrainDateTeam writeTrain.AssignTrainDateTeamUseCase
+	// CancelTrainDate 教練停課並通知已預約的家長
+	CancelTrainDate writeTrain.CancelTrainDateUseCase
+	// RescheduleTrainDate 調整場次時間或地點，保留既有預約
+	RescheduleTrainDate writeTrain.RescheduleTrainDateUseCase
+	// ReconcileCapacity 依實際預約檢查並修正場次剩餘名額
+	ReconcileCapacity writeTrain.ReconcileCapacityUseCase
 
 	QueryFutureTrain       core.ReadUseCase[readTrain.ReqQueryFutureTrain, []*entity.TrainDateHasApptState]
 	FindNearestTrainByTime core.ReadUseCase[readTrain.ReqFindNearestTrainByTime, *entity.TrainDateHasApptState]
@@ -31,11 +62,15 @@ type Registry struct {
 	CancelAppt  writeAppt.CancelApptUseCase
 	CreateLeave writeAppt.CreateLeaveUseCase
 	CancelLeave writeAppt.CancelLeaveUseCase
+	// UpdateLeaveReason 家長修改請假原因
+	UpdateLeaveReason writeAppt.UpdateLeaveReasonUseCase
 
 	AdminCheckIn          writeAppt.AdminCheckInUseCase
 	AdminToggleCheckIn    writeAppt.AdminToggleCheckInUseCase
 	AdminCreateLeave      writeAppt.AdminCreateLeaveUseCase
 	AdminRestoreFromLeave writeAppt.AdminRestoreFromLeaveUseCase
+	ApproveLeave          writeAppt.ApproveLeaveUseCase
+	RejectLeave           writeAppt.RejectLeaveUseCase
 	AdminCreateWalkIn     writeAppt.AdminCreateWalkInUseCase
 	AdminQueryStudents    readStats.AdminQueryStudentsUseCase
 	AutoMarkAbsent        writeAppt.AutoMarkAbsentUseCase
@@ -52,8 +87,70 @@ type Registry struct {
 	GetBusinessAnalytics    readStats.GetBusinessAnalyticsUseCase
 	GetUserDetail           readStats.GetUserDetailUseCase
 
-	Bus                event.Bus
-	Subscribers        []event.Subscriber
+	JoinWaitlist         writeWaitlist.JoinWaitlistUseCase
+	LeaveWaitlist        writeWaitlist.LeaveWaitlistUseCase
+	AdminReorderWaitlist writeWaitlist.AdminReorderWaitlistUseCase
+	PromoteWaitlist      writeWaitlist.PromoteWaitlistUseCase
+	QueryWaitlist        readWaitlist.QueryWaitlistUseCase
+
+	CreateTrainingSeries      writeSeries.CreateTrainingSeriesUseCase
+	MaterializeTrainingSeries writeSeries.MaterializeTrainingSeriesUseCase
+	UpdateTrainingSeries      writeSeries.UpdateTrainingSeriesUseCase
+	DeleteTrainingSeries      writeSeries.DeleteTrainingSeriesUseCase
+	QueryTrainingSeries       readSeries.QueryTrainingSeriesUseCase
+
+	AdminTopUpCredits writeCredit.AdminTopUpCreditsUseCase
+	QueryCreditLedger readCredit.QueryCreditLedgerUseCase
+
+	// 補課
+	BookMakeUp         writeMakeUp.BookMakeUpUseCase
+	QueryMakeUpCredits readMakeUp.QueryMakeUpCreditsUseCase
+
+	RecordPayment writePayment.RecordPaymentUseCase
+
+	CreateStudent     writeStudent.CreateStudentUseCase
+	UpdateStudent     writeStudent.UpdateStudentUseCase
+	DeleteStudent     writeStudent.DeleteStudentUseCase
+	MergeStudents     writeStudent.MergeStudentsUseCase
+	MigrateChildNames writeStudent.MigrateChildNamesUseCase
+	QueryStudents     readStudent.QueryStudentsUseCase
+
+	CreateTeam        writeTeam.CreateTeamUseCase
+	UpdateTeam        writeTeam.UpdateTeamUseCase
+	UpdateTeamMembers writeTeam.UpdateTeamMembersUseCase
+	DeleteTeam        writeTeam.DeleteTeamUseCase
+	QueryTeams        readTeam.QueryTeamsUseCase
+	QueryTeamMembers  readTeam.QueryTeamMembersUseCase
+
+	AssignUserRoles writeRole.AssignUserRolesUseCase
+	RemoveUserRoles writeRole.RemoveUserRolesUseCase
+	QueryUserRoles  readRole.QueryUserRolesUseCase
+	// ResolveActor 解析登入者的後台權限，供 web 中介層使用
+	ResolveActor readRole.ResolveActorUseCase
+
+	QueryDeadLetters  readDeadLetter.QueryDeadLettersUseCase
+	GetDeadLetter     readDeadLetter.GetDeadLetterUseCase
+	ReplayDeadLetter  writeDeadLetter.ReplayDeadLetterUseCase
+	DiscardDeadLetter writeDeadLetter.DiscardDeadLetterUseCase
+	ReplayEvents      writeEventLog.ReplayEventsUseCase
+	ArchiveEvents     writeEventLog.ArchiveEventsUseCase
+
+	QueryEvents             readEventLog.QueryEventsUseCase
+	GetEvent                readEventLog.GetEventUseCase
+	QuerySubscriberProgress readEventLog.QuerySubscriberProgressUseCase
+	RedispatchEvent         writeEventLog.RedispatchEventUseCase
+
+	CreateWebhookEndpoint  writeWebhook.CreateWebhookEndpointUseCase
+	UpdateWebhookEndpoint  writeWebhook.UpdateWebhookEndpointUseCase
+	DeleteWebhookEndpoint  writeWebhook.DeleteWebhookEndpointUseCase
+	PingWebhookEndpoint    writeWebhook.PingWebhookEndpointUseCase
+	QueryWebhookEndpoints  readWebhook.QueryWebhookEndpointsUseCase
+	QueryWebhookDeliveries readWebhook.QueryWebhookDeliveriesUseCase
+
+	Bus         event.Bus
+	Subscribers []event.Subscriber
+	// Relay 將 outbox 內的事件分發給訂閱者，只在訂閱者所在的程序啟動
+	Relay              *event.OutboxRelay
 	IdempotencyManager IdempotencyManager
 }
 
@@ -63,4 +160,18 @@ func (r *Registry) Start(ctx context.Context) {
 			r.Bus.Subscribe(s.Topic(), s)
 		}
 	}
+	if r.Relay != nil {
+		go r.Relay.Run(ctx)
+	}
+}
+
+// Close 在 Start 的 ctx 結束、relay 停止後呼叫，等待處理中的事件直到 ctx 結束
+func (r *Registry) Close(ctx context.Context) error {
+	if r.IdempotencyManager != nil {
+		r.IdempotencyManager.Close()
+	}
+	if r.Bus != nil {
+		return r.Bus.Close(ctx)
+	}
+	return nil
 }
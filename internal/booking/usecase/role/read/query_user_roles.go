@@ -0,0 +1,49 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqQueryUserRoles struct{}
+
+type QueryUserRolesUseCase core.ReadUseCase[ReqQueryUserRoles, []*entity.UserRoles]
+
+type queryUserRolesUseCase struct {
+	repo repository.UserRolesRepository
+}
+
+func NewQueryUserRolesUseCase(repo repository.UserRolesRepository) QueryUserRolesUseCase {
+	return &queryUserRolesUseCase{repo: repo}
+}
+
+func (uc *queryUserRolesUseCase) Name() string {
+	return "QueryUserRoles"
+}
+
+// Execute 依使用者名稱排序
+func (uc *queryUserRolesUseCase) Execute(
+	ctx context.Context, _ ReqQueryUserRoles,
+) ([]*entity.UserRoles, core.UseCaseError) {
+	roles, err := uc.repo.FindAllUserRoles(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*entity.UserRoles{}, nil
+		}
+		return nil, ErrQueryUserRolesFail.Wrap(err)
+	}
+	sort.SliceStable(roles, func(i, j int) bool {
+		return roles[i].User().UserName() < roles[j].User().UserName()
+	})
+	return roles, nil
+}
+
+var (
+	ErrQueryUserRolesFail = core.NewDBError(
+		"QUERY_USER_ROLES", "QUERY_FAIL", "query user roles fail", core.ErrInternal)
+)
@@ -0,0 +1,72 @@
+package read
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+// ReqResolveActor IsLineAdmin 為 LINE 官方帳號設定的管理員；
+// WithTeamRoles 時未指派角色的團隊教練可查看自己團隊的報表與點名，僅後台頁面需要
+type ReqResolveActor struct {
+	UserID        string
+	IsLineAdmin   bool
+	WithTeamRoles bool
+}
+
+type ResolveActorUseCase core.ReadUseCase[ReqResolveActor, *core.Actor]
+
+type resolveActorUseCaseRepo interface {
+	repository.UserRolesRepository
+	repository.TeamRepository
+}
+
+type resolveActorUseCase struct {
+	repo resolveActorUseCaseRepo
+}
+
+func NewResolveActorUseCase(repo resolveActorUseCaseRepo) ResolveActorUseCase {
+	return &resolveActorUseCase{repo: repo}
+}
+
+func (uc *resolveActorUseCase) Name() string {
+	return "ResolveActor"
+}
+
+// Execute 有角色紀錄時以角色為準；沒有紀錄的 LINE 管理員沿用原本的全部權限
+func (uc *resolveActorUseCase) Execute(
+	ctx context.Context, req ReqResolveActor,
+) (*core.Actor, core.UseCaseError) {
+	if req.UserID == "" {
+		return core.NewActor(""), nil
+	}
+	userRoles, err := uc.repo.FindUserRoles(ctx, req.UserID)
+	if err == nil {
+		return core.NewActor(req.UserID, userRoles.Permissions()...), nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrResolveActorFail.Wrap(err)
+	}
+	if req.IsLineAdmin {
+		return core.NewActor(req.UserID, entity.PermissionsOf(entity.RoleOwner)...), nil
+	}
+	if !req.WithTeamRoles {
+		return core.NewActor(req.UserID), nil
+	}
+	teams, err := uc.repo.FindTeamsByCoach(ctx, req.UserID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrResolveActorFail.Wrap(err)
+	}
+	if len(teams) == 0 {
+		return core.NewActor(req.UserID), nil
+	}
+	return core.NewActor(req.UserID, entity.PermissionsOf(entity.RoleAssistantCoach)...), nil
+}
+
+var (
+	ErrResolveActorFail = core.NewDBError(
+		"RESOLVE_ACTOR", "FIND_FAIL", "resolve actor fail", core.ErrInternal)
+)
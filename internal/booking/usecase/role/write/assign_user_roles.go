@@ -0,0 +1,90 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+// ReqAssignUserRoles Roles 會取代原本的角色，空白代表保留紀錄但不具任何後台權限
+type ReqAssignUserRoles struct {
+	User       entity.User
+	OperatorID string
+	Roles      []string
+}
+
+type AssignUserRolesUseCase core.WriteUseCase[ReqAssignUserRoles, *entity.UserRoles]
+
+type assignUserRolesUseCaseRepo interface {
+	repository.UserRolesRepository
+}
+
+func NewAssignUserRolesUseCase(repo assignUserRolesUseCaseRepo) AssignUserRolesUseCase {
+	return &assignUserRolesUseCase{repo: repo}
+}
+
+type assignUserRolesUseCase struct {
+	repo assignUserRolesUseCaseRepo
+}
+
+func (uc *assignUserRolesUseCase) Name() string {
+	return "AssignUserRoles"
+}
+
+// Execute 不可移除自己的角色管理權限，避免沒有人能再指派角色
+func (uc *assignUserRolesUseCase) Execute(
+	ctx context.Context, req ReqAssignUserRoles,
+) (*entity.UserRoles, core.UseCaseError) {
+	roles := make([]entity.Role, 0, len(req.Roles))
+	for _, r := range req.Roles {
+		role, err := entity.ParseRole(r)
+		if err != nil {
+			return nil, ErrAssignUserRolesDomainFail.Wrap(err)
+		}
+		roles = append(roles, role)
+	}
+	if req.User.UserID() == req.OperatorID &&
+		!slices.Contains(entity.PermissionsOf(roles...), entity.PermRoleWrite) {
+		return nil, ErrAssignUserRolesSelfLockout
+	}
+
+	userRoles, err := uc.repo.FindUserRoles(ctx, req.User.UserID())
+	switch {
+	case err == nil:
+		if domainErr := userRoles.Assign(roles, req.OperatorID); domainErr != nil {
+			return nil, ErrAssignUserRolesDomainFail.Wrap(domainErr)
+		}
+	case errors.Is(err, repository.ErrNotFound):
+		var domainErr error
+		userRoles, domainErr = entity.NewUserRoles(
+			entity.WithUserRolesUser(req.User),
+			entity.WithUserRolesRoles(roles...),
+			entity.WithUserRolesUpdatedBy(req.OperatorID),
+		)
+		if domainErr != nil {
+			return nil, ErrAssignUserRolesDomainFail.Wrap(domainErr)
+		}
+	default:
+		return nil, ErrAssignUserRolesFindFail.Wrap(err)
+	}
+
+	if saveErr := uc.repo.SaveUserRoles(ctx, userRoles); saveErr != nil {
+		return nil, ErrAssignUserRolesSaveFail.Wrap(saveErr)
+	}
+	return userRoles, nil
+}
+
+var (
+	ErrAssignUserRolesDomainFail = core.NewDomainError(
+		"ASSIGN_USER_ROLES", "DOMAIN_ERROR", "角色資料不正確", core.ErrInvalidInput)
+	ErrAssignUserRolesSelfLockout = core.NewUseCaseError(
+		"ASSIGN_USER_ROLES", "SELF_LOCKOUT", "不可移除自己的角色管理權限", core.ErrConflict)
+	ErrAssignUserRolesFindFail = core.NewDBError(
+		"ASSIGN_USER_ROLES", "FIND_FAIL", "find user roles fail", core.ErrInternal)
+	ErrAssignUserRolesSaveFail = core.NewDBError(
+		"ASSIGN_USER_ROLES", "SAVE_FAIL", "save user roles fail", core.ErrInternal)
+)
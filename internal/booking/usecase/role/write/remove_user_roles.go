@@ -0,0 +1,54 @@
+package write
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+// ReqRemoveUserRoles 刪除角色紀錄，LINE 管理員恢復為負責人，其他使用者不再具有後台權限
+type ReqRemoveUserRoles struct {
+	UserID     string
+	OperatorID string
+}
+
+type RemoveUserRolesUseCase core.WriteUseCase[ReqRemoveUserRoles, core.Empty]
+
+type removeUserRolesUseCaseRepo interface {
+	repository.UserRolesRepository
+}
+
+func NewRemoveUserRolesUseCase(repo removeUserRolesUseCaseRepo) RemoveUserRolesUseCase {
+	return &removeUserRolesUseCase{repo: repo}
+}
+
+type removeUserRolesUseCase struct {
+	repo removeUserRolesUseCaseRepo
+}
+
+func (uc *removeUserRolesUseCase) Name() string {
+	return "RemoveUserRoles"
+}
+
+func (uc *removeUserRolesUseCase) Execute(
+	ctx context.Context, req ReqRemoveUserRoles,
+) (core.Empty, core.UseCaseError) {
+	if req.UserID == "" {
+		return core.Empty{}, ErrRemoveUserRolesInvalidInput
+	}
+	if req.UserID == req.OperatorID {
+		return core.Empty{}, ErrAssignUserRolesSelfLockout
+	}
+	if err := uc.repo.DeleteUserRoles(ctx, req.UserID); err != nil {
+		return core.Empty{}, ErrRemoveUserRolesFail.Wrap(err)
+	}
+	return core.Empty{}, nil
+}
+
+var (
+	ErrRemoveUserRolesInvalidInput = core.NewUseCaseError(
+		"REMOVE_USER_ROLES", "INVALID_INPUT", "請指定使用者", core.ErrInvalidInput)
+	ErrRemoveUserRolesFail = core.NewDBError(
+		"REMOVE_USER_ROLES", "DELETE_FAIL", "delete user roles fail", core.ErrInternal)
+)
@@ -0,0 +1,52 @@
+package read
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqQueryTrainingSeries struct {
+	CoachID string
+}
+
+type QueryTrainingSeriesUseCase core.ReadUseCase[ReqQueryTrainingSeries, []*entity.TrainingSeries]
+
+type queryTrainingSeriesUseCase struct {
+	repo repository.TrainingSeriesRepository
+}
+
+func NewQueryTrainingSeriesUseCase(repo repository.TrainingSeriesRepository) QueryTrainingSeriesUseCase {
+	return &queryTrainingSeriesUseCase{repo: repo}
+}
+
+func (uc *queryTrainingSeriesUseCase) Name() string {
+	return "QueryTrainingSeries"
+}
+
+// Execute 查詢教練進行中的系列課程
+func (uc *queryTrainingSeriesUseCase) Execute(
+	ctx context.Context, req ReqQueryTrainingSeries,
+) ([]*entity.TrainingSeries, core.UseCaseError) {
+	if req.CoachID == "" {
+		return nil, ErrQueryTrainingSeriesInvalidInput
+	}
+	seriesList, err := uc.repo.FindTrainingSeries(ctx, repository.NewFilterTrainingSeriesByCoachID(req.CoachID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*entity.TrainingSeries{}, nil
+		}
+		return nil, ErrQueryTrainingSeriesFail.Wrap(err)
+	}
+	return seriesList, nil
+}
+
+var (
+	ErrQueryTrainingSeriesInvalidInput = core.NewUseCaseError(
+		"QUERY_TRAINING_SERIES", "INVALID_INPUT", "coach id is required", core.ErrInvalidInput)
+	ErrQueryTrainingSeriesFail = core.NewDBError(
+		"QUERY_TRAINING_SERIES", "QUERY_FAIL", "query training series fail", core.ErrInternal)
+)
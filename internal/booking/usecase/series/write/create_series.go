@@ -0,0 +1,126 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqCreateTrainingSeries struct {
+	Weekdays   []time.Weekday
+	Exceptions []string
+	CoachID    string
+	Location   string
+	Timezone   string
+	StartDate  string // YYYY-MM-DD
+	StartTime  string // HH:MM
+	EndTime    string // HH:MM
+	Until      string // YYYY-MM-DD，空字串代表不限
+	Frequency  entity.RecurrenceFrequency
+	Capacity   int
+	Count      int
+	// 建立後立即產生的週數，0 則使用 DefaultHorizonWeeks
+	HorizonWeeks int
+}
+
+func (r *ReqCreateTrainingSeries) Validate() error {
+	if r.CoachID == "" {
+		return errors.New("coach id is empty")
+	}
+	if r.Location == "" {
+		return errors.New("location is empty")
+	}
+	if r.Capacity <= 0 {
+		return errors.New("capacity is invalid")
+	}
+	if r.HorizonWeeks < 0 {
+		return errors.New("horizon weeks is invalid")
+	}
+	return nil
+}
+
+type RespCreateTrainingSeries struct {
+	Series  *entity.TrainingSeries
+	Created []*entity.TrainDate
+	Skipped []SkippedOccurrence
+}
+
+type CreateTrainingSeriesUseCase core.WriteUseCase[ReqCreateTrainingSeries, *RespCreateTrainingSeries]
+
+func NewCreateTrainingSeriesUseCase(
+	repo seriesRepo, trainSvc service.TrainDateService, defaultPolicy entity.BookingPolicy,
+) CreateTrainingSeriesUseCase {
+	return &createTrainingSeriesUseCase{
+		repo:         repo,
+		materializer: &seriesMaterializer{repo: repo, trainSvc: trainSvc, defaultPolicy: defaultPolicy},
+	}
+}
+
+type createTrainingSeriesUseCase struct {
+	repo         seriesRepo
+	materializer *seriesMaterializer
+}
+
+func (uc *createTrainingSeriesUseCase) Name() string {
+	return "CreateTrainingSeries"
+}
+
+func (uc *createTrainingSeriesUseCase) Execute(
+	ctx context.Context, req ReqCreateTrainingSeries,
+) (*RespCreateTrainingSeries, core.UseCaseError) {
+	if err := req.Validate(); err != nil {
+		return nil, ErrCreateTrainingSeriesInvalidInput.Wrap(err)
+	}
+	rule, err := entity.NewRecurrenceRule(req.Frequency, req.Weekdays, req.Until, req.Count)
+	if err != nil {
+		return nil, ErrCreateTrainingSeriesDomainFail.Wrap(err)
+	}
+	series, err := entity.NewTrainingSeries(
+		entity.WithTrainingSeriesID(uc.repo.GenerateID()),
+		entity.WithTrainingSeriesCoachID(req.CoachID),
+		entity.WithTrainingSeriesLocation(req.Location),
+		entity.WithTrainingSeriesCapacity(req.Capacity),
+		entity.WithTrainingSeriesTimezone(req.Timezone),
+		entity.WithTrainingSeriesSchedule(req.StartDate, req.StartTime, req.EndTime),
+		entity.WithTrainingSeriesRule(rule),
+		entity.WithTrainingSeriesExceptions(req.Exceptions),
+	)
+	if err != nil {
+		return nil, ErrCreateTrainingSeriesDomainFail.Wrap(err)
+	}
+
+	if err := uc.repo.SaveTrainingSeries(ctx, series); err != nil {
+		return nil, ErrCreateTrainingSeriesSaveFail.Wrap(err)
+	}
+
+	horizon := req.HorizonWeeks
+	if horizon == 0 {
+		horizon = DefaultHorizonWeeks
+	}
+	now := time.Now()
+	created, skipped, err := uc.materializer.materialize(ctx, series, now, now.AddDate(0, 0, 7*horizon))
+	if err != nil {
+		// 系列已建立，之後的排程仍會補產生場次
+		return nil, ErrCreateTrainingSeriesMaterializeFail.Wrap(err)
+	}
+	return &RespCreateTrainingSeries{
+		Series:  series,
+		Created: created,
+		Skipped: skipped,
+	}, nil
+}
+
+var (
+	ErrCreateTrainingSeriesInvalidInput = core.NewUseCaseError(
+		"CREATE_TRAINING_SERIES", "INVALID_INPUT", "invalid input", core.ErrInvalidInput)
+	ErrCreateTrainingSeriesDomainFail = core.NewDomainError(
+		"CREATE_TRAINING_SERIES", "DOMAIN_ERROR", "new domain entity failed", core.ErrInvalidInput)
+	ErrCreateTrainingSeriesSaveFail = core.NewDBError(
+		"CREATE_TRAINING_SERIES", "SAVE_SERIES_FAIL", "save training series fail", core.ErrInternal)
+	ErrCreateTrainingSeriesMaterializeFail = core.NewDBError(
+		"CREATE_TRAINING_SERIES", "MATERIALIZE_FAIL", "系列已建立，但產生場次失敗，稍後會自動重試", core.ErrInternal)
+)
@@ -0,0 +1,188 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqDeleteTrainingSeries struct {
+	SeriesID       string
+	CoachID        string
+	OccurrenceDate string // YYYY-MM-DD，Scope 為 ALL 時可省略
+	Scope          SeriesEditScope
+}
+
+func (r *ReqDeleteTrainingSeries) Validate() error {
+	if r.SeriesID == "" {
+		return errors.New("series id is empty")
+	}
+	if !r.Scope.isValid() {
+		return errors.New("scope is invalid")
+	}
+	if r.Scope != SeriesEditScopeAll && r.OccurrenceDate == "" {
+		return errors.New("occurrence date is empty")
+	}
+	return nil
+}
+
+type RespDeleteTrainingSeries struct {
+	Deleted []*entity.TrainDate
+	// 已有預約而保留的場次，需由教練個別處理
+	Kept []SkippedOccurrence
+}
+
+type DeleteTrainingSeriesUseCase core.WriteUseCase[ReqDeleteTrainingSeries, *RespDeleteTrainingSeries]
+
+func NewDeleteTrainingSeriesUseCase(repo seriesRepo) DeleteTrainingSeriesUseCase {
+	return &deleteTrainingSeriesUseCase{repo: repo}
+}
+
+type deleteTrainingSeriesUseCase struct {
+	repo seriesRepo
+}
+
+func (uc *deleteTrainingSeriesUseCase) Name() string {
+	return "DeleteTrainingSeries"
+}
+
+func (uc *deleteTrainingSeriesUseCase) Execute(
+	ctx context.Context, req ReqDeleteTrainingSeries,
+) (*RespDeleteTrainingSeries, core.UseCaseError) {
+	if err := req.Validate(); err != nil {
+		return nil, ErrDeleteTrainingSeriesInvalidInput.Wrap(err)
+	}
+	series, ucErr := findSeriesForEdit(ctx, uc.repo, req.SeriesID, req.CoachID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+
+	scope := req.Scope
+	if scope == SeriesEditScopeFollowing && req.OccurrenceDate == series.StartDate() {
+		scope = SeriesEditScopeAll
+	}
+	from := time.Now()
+	if scope != SeriesEditScopeAll {
+		occ, ok := series.OccurrenceOn(req.OccurrenceDate)
+		if !ok {
+			return nil, ErrDeleteTrainingSeriesNotOccurrence
+		}
+		from = occ.Period.Start()
+	}
+	trainings, findErr := uc.repo.FindTrainDates(ctx, repository.NewFilterTrainDateBySeriesID(series.ID(), from))
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		return nil, ErrDeleteTrainingSeriesFindTrainDateFail.Wrap(findErr)
+	}
+
+	switch scope {
+	case SeriesEditScopeThis:
+		if err := series.AddException(req.OccurrenceDate); err != nil {
+			return nil, ErrDeleteTrainingSeriesDomainFail.Wrap(err)
+		}
+		var target []*entity.TrainDate
+		for _, t := range trainings {
+			if series.DateOf(t.Period().Start()) == req.OccurrenceDate {
+				target = append(target, t)
+			}
+		}
+		trainings = target
+		// 單堂刪除時，已有預約直接拒絕，與刪除一般場次的行為一致
+		for _, t := range trainings {
+			if err := t.Delete(); err != nil {
+				return nil, ErrDeleteTrainingSeriesHasAppointments.Wrap(err)
+			}
+		}
+	case SeriesEditScopeFollowing:
+		if err := series.EndBefore(req.OccurrenceDate); err != nil {
+			return nil, ErrDeleteTrainingSeriesNotOccurrence.Wrap(err)
+		}
+	default:
+		series.End()
+	}
+
+	resp := &RespDeleteTrainingSeries{}
+	var detached []*entity.TrainDate
+	for _, t := range trainings {
+		if err := t.Delete(); err != nil {
+			// 已有預約的場次保留並脫離系列
+			t.DetachFromSeries()
+			detached = append(detached, t)
+			resp.Kept = append(resp.Kept, SkippedOccurrence{
+				SeriesID:    series.ID(),
+				Date:        series.DateOf(t.Period().Start()),
+				TrainDateID: t.ID(),
+				Reason:      SkipReasonHasAppointments,
+			})
+			continue
+		}
+		resp.Deleted = append(resp.Deleted, t)
+	}
+
+	// 快取由訂閱 TrainDateDeleted 與 TrainDateUpdated 的訂閱者清理
+	now := time.Now()
+	events := make([]event.Event, 0, len(trainings))
+	for _, t := range resp.Deleted {
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicTrainDateDeleted, domain.TrainDateDeleted{
+			TrainingID: t.ID(),
+			CoachID:    t.UserID(),
+			Location:   t.Location(),
+			StartTime:  t.Period().Start(),
+			EndTime:    t.Period().End(),
+			DeletedBy:  req.CoachID,
+			OccurredAt: now,
+		}))
+	}
+	for _, t := range detached {
+		events = append(events, newTrainDateUpdatedEvent(uc.repo.GenerateID(), t, domain.TrainDateFieldSeries))
+	}
+
+	// 系列結束與場次刪除在同一個交易內，任一步失敗時全部回復
+	ucErr = core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.SaveTrainingSeries(ctx, series); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				return ErrDeleteTrainingSeriesConflict.Wrap(err)
+			}
+			return ErrDeleteTrainingSeriesSaveFail.Wrap(err)
+		}
+		for _, t := range resp.Deleted {
+			if err := uc.repo.DeleteTrainingDate(ctx, t); err != nil {
+				return ErrDeleteTrainingSeriesDeleteTrainDateFail.Wrap(err)
+			}
+		}
+		if len(detached) > 0 {
+			if err := uc.repo.UpdateManyTrainDates(ctx, detached); err != nil {
+				return ErrDeleteTrainingSeriesDeleteTrainDateFail.Wrap(err)
+			}
+		}
+		return core.AddEvents(ctx, uc.repo, events...)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	return resp, nil
+}
+
+var (
+	ErrDeleteTrainingSeriesInvalidInput = core.NewUseCaseError(
+		"DELETE_TRAINING_SERIES", "INVALID_INPUT", "invalid input", core.ErrInvalidInput)
+	ErrDeleteTrainingSeriesNotOccurrence = core.NewUseCaseError(
+		"DELETE_TRAINING_SERIES", "NOT_OCCURRENCE", "該日期沒有這個系列的課程", core.ErrInvalidInput)
+	ErrDeleteTrainingSeriesDomainFail = core.NewDomainError(
+		"DELETE_TRAINING_SERIES", "DOMAIN_ERROR", "delete training series fail", core.ErrInvalidInput)
+	ErrDeleteTrainingSeriesHasAppointments = core.NewDomainError(
+		"DELETE_TRAINING_SERIES", "HAS_APPOINTMENTS", "已有學員預約，無法刪除這堂課", core.ErrConflict)
+	ErrDeleteTrainingSeriesFindTrainDateFail = core.NewDBError(
+		"DELETE_TRAINING_SERIES", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrDeleteTrainingSeriesConflict = core.NewDBError(
+		"DELETE_TRAINING_SERIES", "CONFLICT", "系列課程已被更新，請重新操作", core.ErrConflict)
+	ErrDeleteTrainingSeriesSaveFail = core.NewDBError(
+		"DELETE_TRAINING_SERIES", "SAVE_SERIES_FAIL", "save training series fail", core.ErrInternal)
+	ErrDeleteTrainingSeriesDeleteTrainDateFail = core.NewDBError(
+		"DELETE_TRAINING_SERIES", "DELETE_TRAIN_DATE_FAIL", "delete train date fail", core.ErrInternal)
+)
@@ -0,0 +1,34 @@
+package write
+
+import (
+	"testing"
+
+	"seanAIgent/internal/booking/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteTrainingSeries_Following(t *testing.T) {
+	repo, series, initial, _ := setupSeriesUpdate(t)
+	require.NoError(t, initial[2].ReserveSpot(1))
+	uc := NewDeleteTrainingSeriesUseCase(repo)
+
+	resp, err := uc.Execute(t.Context(), ReqDeleteTrainingSeries{
+		SeriesID: series.ID(), OccurrenceDate: weekDate(series, 1), Scope: SeriesEditScopeFollowing,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 1, repo.txCount)
+	assert.Equal(t, trainingIDs(initial[1], initial[3]), trainingIDs(resp.Deleted...))
+
+	// 已有預約的場次保留並脫離系列
+	require.Len(t, resp.Kept, 1)
+	assert.Equal(t, initial[2].ID(), resp.Kept[0].TrainDateID)
+	assert.Empty(t, initial[2].SeriesID())
+	assert.Equal(t, series.ID(), initial[0].SeriesID())
+
+	assert.Equal(t, map[string][]string{
+		domain.TopicTrainDateDeleted: trainingIDs(initial[1], initial[3]),
+		domain.TopicTrainDateUpdated: trainingIDs(initial[2]),
+	}, repo.publishedTrainingIDs(t))
+}
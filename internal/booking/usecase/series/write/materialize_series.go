@@ -0,0 +1,83 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqMaterializeTrainingSeries struct {
+	// 往後產生的週數，0 則使用 DefaultHorizonWeeks
+	HorizonWeeks int
+}
+
+type RespMaterializeTrainingSeries struct {
+	FailedSeriesIDs []string
+	Skipped         []SkippedOccurrence
+	SeriesCount     int
+	CreatedCount    int
+}
+
+type MaterializeTrainingSeriesUseCase core.WriteUseCase[ReqMaterializeTrainingSeries, *RespMaterializeTrainingSeries]
+
+func NewMaterializeTrainingSeriesUseCase(
+	repo seriesRepo, trainSvc service.TrainDateService, defaultPolicy entity.BookingPolicy,
+) MaterializeTrainingSeriesUseCase {
+	return &materializeTrainingSeriesUseCase{
+		repo:         repo,
+		materializer: &seriesMaterializer{repo: repo, trainSvc: trainSvc, defaultPolicy: defaultPolicy},
+	}
+}
+
+type materializeTrainingSeriesUseCase struct {
+	repo         seriesRepo
+	materializer *seriesMaterializer
+}
+
+func (uc *materializeTrainingSeriesUseCase) Name() string {
+	return "MaterializeTrainingSeries"
+}
+
+// Execute 由排程呼叫，為所有進行中的系列滾動產生未來 N 週的場次，
+// 單一系列失敗不影響其他系列
+func (uc *materializeTrainingSeriesUseCase) Execute(
+	ctx context.Context, req ReqMaterializeTrainingSeries,
+) (*RespMaterializeTrainingSeries, core.UseCaseError) {
+	if req.HorizonWeeks < 0 {
+		return nil, ErrMaterializeTrainingSeriesInvalidInput
+	}
+	horizon := req.HorizonWeeks
+	if horizon == 0 {
+		horizon = DefaultHorizonWeeks
+	}
+	seriesList, err := uc.repo.FindTrainingSeries(ctx, repository.NewFilterTrainingSeriesActive())
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrMaterializeTrainingSeriesFindFail.Wrap(err)
+	}
+
+	now := time.Now()
+	to := now.AddDate(0, 0, 7*horizon)
+	resp := &RespMaterializeTrainingSeries{SeriesCount: len(seriesList)}
+	for _, series := range seriesList {
+		created, skipped, err := uc.materializer.materialize(ctx, series, now, to)
+		if err != nil {
+			resp.FailedSeriesIDs = append(resp.FailedSeriesIDs, series.ID())
+			continue
+		}
+		resp.CreatedCount += len(created)
+		resp.Skipped = append(resp.Skipped, skipped...)
+	}
+	return resp, nil
+}
+
+var (
+	ErrMaterializeTrainingSeriesInvalidInput = core.NewUseCaseError(
+		"MATERIALIZE_TRAINING_SERIES", "INVALID_INPUT", "horizon weeks is invalid", core.ErrInvalidInput)
+	ErrMaterializeTrainingSeriesFindFail = core.NewDBError(
+		"MATERIALIZE_TRAINING_SERIES", "FIND_SERIES_FAIL", "find training series fail", core.ErrInternal)
+)
@@ -0,0 +1,224 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/event"
+)
+
+// DefaultHorizonWeeks 預設往後產生的週數
+const DefaultHorizonWeeks = 8
+
+// 場次未產生或未套用修改的原因
+const (
+	SkipReasonCoachBusy       = "COACH_BUSY"
+	SkipReasonHasAppointments = "HAS_APPOINTMENTS"
+)
+
+// SkippedOccurrence 未產生或未套用修改的場次
+type SkippedOccurrence struct {
+	SeriesID    string
+	Date        string
+	TrainDateID string
+	Reason      string
+}
+
+type seriesRepo interface {
+	repository.IdentityGenerator
+	repository.TrainRepository
+	repository.TrainingSeriesRepository
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+// seriesMaterializer 依系列規則補齊尚未產生的 TrainDate，與教練既有排程衝突的場次會略過
+type seriesMaterializer struct {
+	repo          seriesRepo
+	trainSvc      service.TrainDateService
+	defaultPolicy entity.BookingPolicy // 新產生場次的預約規則
+}
+
+func (m *seriesMaterializer) materialize(
+	ctx context.Context, series *entity.TrainingSeries, from, to time.Time,
+) ([]*entity.TrainDate, []SkippedOccurrence, error) {
+	if !series.IsActive() {
+		return nil, nil, nil
+	}
+	occs := series.Occurrences(from, to)
+	if len(occs) == 0 {
+		return nil, nil, nil
+	}
+	existing, err := m.findOccurrences(ctx, series, from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pending := make([]entity.SeriesOccurrence, 0, len(occs))
+	for _, occ := range occs {
+		if _, ok := existing[occ.Date]; !ok {
+			pending = append(pending, occ)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil, nil
+	}
+
+	// 先整批檢查，有衝突才逐堂確認，避免每堂都查一次 DB
+	ranges := make([]entity.TimeRange, 0, len(pending))
+	for _, occ := range pending {
+		ranges = append(ranges, occ.Period)
+	}
+	hasConflict := false
+	if err := m.trainSvc.CheckAnyOverlap(ctx, series.CoachID(), ranges); err != nil {
+		if !errors.Is(err, service.ErrTrainerTimeOverlap) {
+			return nil, nil, err
+		}
+		hasConflict = true
+	}
+
+	var created []*entity.TrainDate
+	var skipped []SkippedOccurrence
+	for _, occ := range pending {
+		if hasConflict {
+			if err := m.trainSvc.CheckOverlapExcept(ctx, series.CoachID(), occ.Period); err != nil {
+				if !errors.Is(err, service.ErrTrainerTimeOverlap) {
+					return nil, nil, err
+				}
+				skipped = append(skipped, SkippedOccurrence{
+					SeriesID: series.ID(),
+					Date:     occ.Date,
+					Reason:   SkipReasonCoachBusy,
+				})
+				continue
+			}
+		}
+		td, err := entity.NewTrainDate(
+			entity.WithBasicTrainDate(
+				m.repo.GenerateID(),
+				series.CoachID(),
+				series.Location(),
+				series.Capacity(),
+				occ.Period,
+			),
+			entity.WithTrainDateTimezone(series.Timezone()),
+			entity.WithTrainDateSeriesID(series.ID()),
+			entity.WithTrainDateBookingPolicy(m.defaultPolicy),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		created = append(created, td)
+	}
+	if len(created) == 0 {
+		return nil, skipped, nil
+	}
+	now := time.Now()
+	events := make([]event.Event, 0, len(created))
+	for _, td := range created {
+		events = append(events, newTrainDateCreatedEvent(m.repo.GenerateID(), td, now))
+	}
+	// 場次與事件一起寫入，快取由訂閱 TrainDateCreated 的訂閱者清理；
+	// 修改系列時併入外層交易
+	err = m.repo.WithinTx(ctx, func(ctx context.Context) error {
+		if err := m.repo.SaveManyTrainDates(ctx, created); err != nil {
+			return err
+		}
+		if err := m.repo.AddEvents(ctx, events...); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return created, skipped, nil
+}
+
+// findOccurrences 取得系列在 from 之後已產生的場次，以系列時區的日期為 key
+func (m *seriesMaterializer) findOccurrences(
+	ctx context.Context, series *entity.TrainingSeries, from time.Time,
+) (map[string]*entity.TrainDate, error) {
+	trainings, err := m.repo.FindTrainDates(ctx, repository.NewFilterTrainDateBySeriesID(series.ID(), from))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	result := make(map[string]*entity.TrainDate, len(trainings))
+	for _, t := range trainings {
+		result[series.DateOf(t.Period().Start())] = t
+	}
+	return result, nil
+}
+
+// applyTemplate 將系列的新設定套用到已產生的場次，
+// 已有預約而無法改時間或名額的場次會保留原狀並脫離系列，同時設為例外日期避免重複產生
+func (m *seriesMaterializer) applyTemplate(
+	ctx context.Context, target *entity.TrainingSeries, trainings []*entity.TrainDate,
+) ([]*entity.TrainDate, []SkippedOccurrence, error) {
+	ids := make([]string, 0, len(trainings))
+	for _, t := range trainings {
+		ids = append(ids, t.ID())
+	}
+
+	updated := make([]*entity.TrainDate, 0, len(trainings))
+	var skipped []SkippedOccurrence
+	for _, t := range trainings {
+		date := target.DateOf(t.Period().Start())
+		occ, ok := target.OccurrenceOn(date)
+		if !ok {
+			continue
+		}
+		reason := ""
+		if err := m.trainSvc.CheckOverlapExcept(ctx, target.CoachID(), occ.Period, ids...); err != nil {
+			if !errors.Is(err, service.ErrTrainerTimeOverlap) {
+				return nil, nil, err
+			}
+			reason = SkipReasonCoachBusy
+		} else if err := t.UpdateDetails(target.Location(), target.Capacity(), occ.Period); err != nil {
+			reason = SkipReasonHasAppointments
+		}
+		if reason != "" {
+			t.DetachFromSeries()
+			if err := target.AddException(date); err != nil {
+				return nil, nil, err
+			}
+			skipped = append(skipped, SkippedOccurrence{
+				SeriesID:    target.ID(),
+				Date:        date,
+				TrainDateID: t.ID(),
+				Reason:      reason,
+			})
+		} else {
+			t.AttachToSeries(target.ID())
+		}
+		updated = append(updated, t)
+	}
+	return updated, skipped, nil
+}
+
+func newTrainDateCreatedEvent(id string, t *entity.TrainDate, now time.Time) event.Event {
+	return event.NewTypedEvent(id, domain.TopicTrainDateCreated, domain.TrainDateCreated{
+		TrainingID: t.ID(),
+		CoachID:    t.UserID(),
+		Location:   t.Location(),
+		Capacity:   t.MaxCapacity(),
+		StartTime:  t.Period().Start(),
+		EndTime:    t.Period().End(),
+		TeamID:     t.TeamID(),
+		OccurredAt: now,
+	})
+}
+
+func newTrainDateUpdatedEvent(id string, t *entity.TrainDate, fields ...string) event.Event {
+	return event.NewTypedEvent(id, domain.TopicTrainDateUpdated, domain.TrainDateUpdated{
+		TrainingID: t.ID(),
+		Fields:     fields,
+		TeamID:     t.TeamID(),
+		OccurredAt: time.Now(),
+	})
+}
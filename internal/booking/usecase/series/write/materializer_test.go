@@ -0,0 +1,311 @@
+package write
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memSeriesRepo 以記憶體保存場次與系列，未用到的方法由內嵌的 interface 提供
+type memSeriesRepo struct {
+	repository.TrainRepository
+	repository.TrainingSeriesRepository
+	trainings map[string]*entity.TrainDate
+	series    map[string]*entity.TrainingSeries
+	events    []event.Event
+	nextID    int
+	txCount   int // 最外層交易的次數，巢狀呼叫併入外層
+	inTx      bool
+}
+
+func newMemSeriesRepo() *memSeriesRepo {
+	return &memSeriesRepo{
+		trainings: make(map[string]*entity.TrainDate),
+		series:    make(map[string]*entity.TrainingSeries),
+	}
+}
+
+func (r *memSeriesRepo) GenerateID() string {
+	r.nextID++
+	return fmt.Sprintf("id-%03d", r.nextID)
+}
+
+func (r *memSeriesRepo) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.inTx {
+		return fn(ctx)
+	}
+	r.inTx = true
+	defer func() { r.inTx = false }()
+	r.txCount++
+	return fn(ctx)
+}
+
+func (r *memSeriesRepo) SaveTrainDate(ctx context.Context, training *entity.TrainDate) repository.RepoError {
+	r.trainings[training.ID()] = training
+	return nil
+}
+
+func (r *memSeriesRepo) SaveManyTrainDates(ctx context.Context, trainings []*entity.TrainDate) repository.RepoError {
+	for _, t := range trainings {
+		r.trainings[t.ID()] = t
+	}
+	return nil
+}
+
+func (r *memSeriesRepo) UpdateManyTrainDates(ctx context.Context, trainings []*entity.TrainDate) repository.RepoError {
+	return r.SaveManyTrainDates(ctx, trainings)
+}
+
+func (r *memSeriesRepo) DeleteTrainingDate(ctx context.Context, training *entity.TrainDate) repository.RepoError {
+	delete(r.trainings, training.ID())
+	return nil
+}
+
+func (r *memSeriesRepo) FindTrainDates(
+	ctx context.Context, filter repository.FilterTrainDate,
+) ([]*entity.TrainDate, repository.RepoError) {
+	var result []*entity.TrainDate
+	for _, t := range r.trainings {
+		switch f := filter.(type) {
+		case repository.FilterTrainDateBySeriesID:
+			if t.SeriesID() == f.SeriesID && !t.Period().Start().Before(f.After) {
+				result = append(result, t)
+			}
+		case repository.FilterTrainDateByCoachOverlap:
+			if t.UserID() == f.CoachID && overlaps(t.Period(), f.Period) {
+				result = append(result, t)
+			}
+		default:
+			return nil, repository.NewRepoInternalError("train", "memory", "find_train_dates", repository.ErrFilterNotImplemented)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Period().Start().Before(result[j].Period().Start()) })
+	return result, nil
+}
+
+func (r *memSeriesRepo) HasAnyOverlap(
+	ctx context.Context, coachID string, trs []entity.TimeRange,
+) (bool, repository.RepoError) {
+	for _, t := range r.trainings {
+		for _, tr := range trs {
+			if t.UserID() == coachID && overlaps(t.Period(), tr) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (r *memSeriesRepo) AddEvents(ctx context.Context, events ...event.Event) repository.RepoError {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+// publishedTrainingIDs 已發布的場次事件，依主題分組並排序
+func (r *memSeriesRepo) publishedTrainingIDs(t *testing.T) map[string][]string {
+	t.Helper()
+	result := make(map[string][]string)
+	for _, e := range r.events {
+		var id string
+		switch e.Topic() {
+		case domain.TopicTrainDateCreated:
+			p, err := event.DecodePayload[domain.TrainDateCreated](e)
+			require.NoError(t, err)
+			id = p.TrainingID
+		case domain.TopicTrainDateUpdated:
+			p, err := event.DecodePayload[domain.TrainDateUpdated](e)
+			require.NoError(t, err)
+			id = p.TrainingID
+		case domain.TopicTrainDateDeleted:
+			p, err := event.DecodePayload[domain.TrainDateDeleted](e)
+			require.NoError(t, err)
+			id = p.TrainingID
+		}
+		result[e.Topic()] = append(result[e.Topic()], id)
+	}
+	for _, ids := range result {
+		sort.Strings(ids)
+	}
+	return result
+}
+
+func (r *memSeriesRepo) SaveTrainingSeries(ctx context.Context, series *entity.TrainingSeries) repository.RepoError {
+	r.series[series.ID()] = series
+	return nil
+}
+
+func (r *memSeriesRepo) FindTrainingSeriesByID(
+	ctx context.Context, id string,
+) (*entity.TrainingSeries, repository.RepoError) {
+	s, ok := r.series[id]
+	if !ok {
+		return nil, repository.NewRepoNotFoundError("series", "memory", "find_series_by_id", nil)
+	}
+	return s, nil
+}
+
+// seriesTrainings 依時間排序回傳系列的所有場次
+func (r *memSeriesRepo) seriesTrainings(seriesID string) []*entity.TrainDate {
+	trainings, _ := r.FindTrainDates(context.Background(), repository.NewFilterTrainDateBySeriesID(seriesID, time.Time{}))
+	return trainings
+}
+
+// addBusy 教練在 period 已有其他課程
+func (r *memSeriesRepo) addBusy(t *testing.T, coachID string, period entity.TimeRange) *entity.TrainDate {
+	t.Helper()
+	td, err := entity.NewTrainDate(entity.WithBasicTrainDate(r.GenerateID(), coachID, "Other Gym", 5, period))
+	require.NoError(t, err)
+	r.trainings[td.ID()] = td
+	return td
+}
+
+func overlaps(a, b entity.TimeRange) bool {
+	return a.Start().Before(b.End()) && a.End().After(b.Start())
+}
+
+// newTestSeries 明天起每週同一天 10:00-11:00 的系列
+func newTestSeries(t *testing.T, repo *memSeriesRepo) *entity.TrainingSeries {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	start := time.Now().In(loc).AddDate(0, 0, 1)
+	rule, err := entity.NewRecurrenceRule(entity.RecurrenceWeekly, []time.Weekday{start.Weekday()}, "", 0)
+	require.NoError(t, err)
+	series, err := entity.NewTrainingSeries(
+		entity.WithTrainingSeriesID(repo.GenerateID()),
+		entity.WithTrainingSeriesCoachID("coach1"),
+		entity.WithTrainingSeriesLocation("Gym A"),
+		entity.WithTrainingSeriesCapacity(10),
+		entity.WithTrainingSeriesTimezone("Asia/Taipei"),
+		entity.WithTrainingSeriesSchedule(start.Format(time.DateOnly), "10:00", "11:00"),
+		entity.WithTrainingSeriesRule(rule),
+	)
+	require.NoError(t, err)
+	repo.series[series.ID()] = series
+	return series
+}
+
+// weekDate 系列第 week 堂 (從 0 起算) 的日期
+func weekDate(series *entity.TrainingSeries, week int) string {
+	start, _ := time.Parse(time.DateOnly, series.StartDate())
+	return start.AddDate(0, 0, 7*week).Format(time.DateOnly)
+}
+
+func weeks(n int) time.Time {
+	return time.Now().AddDate(0, 0, 7*n)
+}
+
+func newTestMaterializer(repo *memSeriesRepo) *seriesMaterializer {
+	return &seriesMaterializer{
+		repo:          repo,
+		trainSvc:      service.NewTrainDateService(repo),
+		defaultPolicy: entity.DefaultBookingPolicy(),
+	}
+}
+
+func trainingIDs(trainings ...*entity.TrainDate) []string {
+	ids := make([]string, 0, len(trainings))
+	for _, t := range trainings {
+		ids = append(ids, t.ID())
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func trainingDates(series *entity.TrainingSeries, trainings []*entity.TrainDate) []string {
+	dates := make([]string, 0, len(trainings))
+	for _, t := range trainings {
+		dates = append(dates, series.DateOf(t.Period().Start()))
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+func TestSeriesMaterializer(t *testing.T) {
+	ctx := t.Context()
+
+	// 每次排程只補上視窗內新進入的場次，已產生的不會重複建立
+	t.Run("RollingWindow", func(t *testing.T) {
+		repo := newMemSeriesRepo()
+		series := newTestSeries(t, repo)
+		m := newTestMaterializer(repo)
+
+		created, skipped, err := m.materialize(ctx, series, time.Now(), weeks(2))
+		require.NoError(t, err)
+		assert.Empty(t, skipped)
+		assert.Equal(t, []string{weekDate(series, 0), weekDate(series, 1)}, trainingDates(series, created))
+
+		created, skipped, err = m.materialize(ctx, series, time.Now(), weeks(4))
+		require.NoError(t, err)
+		assert.Empty(t, skipped)
+		assert.Equal(t, []string{weekDate(series, 2), weekDate(series, 3)}, trainingDates(series, created))
+		assert.Len(t, repo.seriesTrainings(series.ID()), 4)
+		// 每個新場次都發布 TrainDateCreated，由訂閱者清理排程快取
+		assert.Equal(t, map[string][]string{
+			domain.TopicTrainDateCreated: trainingIDs(repo.seriesTrainings(series.ID())...),
+		}, repo.publishedTrainingIDs(t))
+
+		created, _, err = m.materialize(ctx, series, time.Now(), weeks(4))
+		require.NoError(t, err)
+		assert.Empty(t, created)
+	})
+
+	t.Run("SkipsExceptions", func(t *testing.T) {
+		repo := newMemSeriesRepo()
+		series := newTestSeries(t, repo)
+		m := newTestMaterializer(repo)
+		require.NoError(t, series.AddException(weekDate(series, 1)))
+
+		created, _, err := m.materialize(ctx, series, time.Now(), weeks(3))
+		require.NoError(t, err)
+		assert.Equal(t, []string{weekDate(series, 0), weekDate(series, 2)}, trainingDates(series, created))
+
+		// 例外日期之後加入時，已產生的場次不受影響，也不會再補產生
+		require.NoError(t, series.AddException(weekDate(series, 3)))
+		created, _, err = m.materialize(ctx, series, time.Now(), weeks(5))
+		require.NoError(t, err)
+		assert.Equal(t, []string{weekDate(series, 4)}, trainingDates(series, created))
+		assert.Equal(t,
+			[]string{weekDate(series, 0), weekDate(series, 2), weekDate(series, 4)},
+			trainingDates(series, repo.seriesTrainings(series.ID())))
+	})
+
+	// 整批檢查有衝突時逐堂確認，只略過與既有課程重疊的場次
+	t.Run("SkipsCoachBusy", func(t *testing.T) {
+		repo := newMemSeriesRepo()
+		series := newTestSeries(t, repo)
+		m := newTestMaterializer(repo)
+		occ, ok := series.OccurrenceOn(weekDate(series, 1))
+		require.True(t, ok)
+		repo.addBusy(t, series.CoachID(), occ.Period)
+
+		created, skipped, err := m.materialize(ctx, series, time.Now(), weeks(3))
+		require.NoError(t, err)
+		assert.Equal(t, []string{weekDate(series, 0), weekDate(series, 2)}, trainingDates(series, created))
+		require.Len(t, skipped, 1)
+		assert.Equal(t, weekDate(series, 1), skipped[0].Date)
+		assert.Equal(t, SkipReasonCoachBusy, skipped[0].Reason)
+	})
+
+	t.Run("EndedSeries", func(t *testing.T) {
+		repo := newMemSeriesRepo()
+		series := newTestSeries(t, repo)
+		series.End()
+
+		created, skipped, err := newTestMaterializer(repo).materialize(ctx, series, time.Now(), weeks(3))
+		require.NoError(t, err)
+		assert.Empty(t, created)
+		assert.Empty(t, skipped)
+	})
+}
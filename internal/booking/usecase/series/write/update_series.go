@@ -0,0 +1,385 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+	"seanAIgent/internal/util/timeutil"
+)
+
+type SeriesEditScope string
+
+const (
+	SeriesEditScopeThis      SeriesEditScope = "THIS"      // 只改這一堂
+	SeriesEditScopeFollowing SeriesEditScope = "FOLLOWING" // 這一堂及之後
+	SeriesEditScopeAll       SeriesEditScope = "ALL"       // 整個系列
+)
+
+func (s SeriesEditScope) isValid() bool {
+	return s == SeriesEditScopeThis || s == SeriesEditScopeFollowing || s == SeriesEditScopeAll
+}
+
+type ReqUpdateTrainingSeries struct {
+	SeriesID       string
+	CoachID        string
+	OccurrenceDate string // YYYY-MM-DD，Scope 為 ALL 時可省略
+	Scope          SeriesEditScope
+	Location       string
+	StartTime      string // HH:MM
+	EndTime        string // HH:MM
+	Capacity       int
+}
+
+func (r *ReqUpdateTrainingSeries) Validate() error {
+	if r.SeriesID == "" {
+		return errors.New("series id is empty")
+	}
+	if !r.Scope.isValid() {
+		return errors.New("scope is invalid")
+	}
+	if r.Scope != SeriesEditScopeAll && r.OccurrenceDate == "" {
+		return errors.New("occurrence date is empty")
+	}
+	if r.Location == "" {
+		return errors.New("location is empty")
+	}
+	if r.Capacity <= 0 {
+		return errors.New("capacity is invalid")
+	}
+	return nil
+}
+
+type RespUpdateTrainingSeries struct {
+	// 修改後負責後續場次的系列，「這堂及之後」會是切出的新系列
+	Series  *entity.TrainingSeries
+	Updated []*entity.TrainDate
+	Created []*entity.TrainDate
+	Skipped []SkippedOccurrence
+}
+
+type UpdateTrainingSeriesUseCase core.WriteUseCase[ReqUpdateTrainingSeries, *RespUpdateTrainingSeries]
+
+func NewUpdateTrainingSeriesUseCase(
+	repo seriesRepo, trainSvc service.TrainDateService, defaultPolicy entity.BookingPolicy,
+) UpdateTrainingSeriesUseCase {
+	return &updateTrainingSeriesUseCase{
+		repo:         repo,
+		trainSvc:     trainSvc,
+		materializer: &seriesMaterializer{repo: repo, trainSvc: trainSvc, defaultPolicy: defaultPolicy},
+	}
+}
+
+type updateTrainingSeriesUseCase struct {
+	repo         seriesRepo
+	trainSvc     service.TrainDateService
+	materializer *seriesMaterializer
+}
+
+func (uc *updateTrainingSeriesUseCase) Name() string {
+	return "UpdateTrainingSeries"
+}
+
+func (uc *updateTrainingSeriesUseCase) Execute(
+	ctx context.Context, req ReqUpdateTrainingSeries,
+) (*RespUpdateTrainingSeries, core.UseCaseError) {
+	if err := req.Validate(); err != nil {
+		return nil, ErrUpdateTrainingSeriesInvalidInput.Wrap(err)
+	}
+	series, ucErr := findSeriesForEdit(ctx, uc.repo, req.SeriesID, req.CoachID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+
+	scope := req.Scope
+	// 從第一堂開始的「這堂及之後」等同修改整個系列
+	if scope == SeriesEditScopeFollowing && req.OccurrenceDate == series.StartDate() {
+		scope = SeriesEditScopeAll
+	}
+	switch scope {
+	case SeriesEditScopeThis:
+		return uc.updateThis(ctx, series, req)
+	case SeriesEditScopeFollowing:
+		return uc.updateFollowing(ctx, series, req)
+	default:
+		return uc.updateAll(ctx, series, req)
+	}
+}
+
+// updateThis 單堂修改，該堂脫離系列並設為例外日期
+func (uc *updateTrainingSeriesUseCase) updateThis(
+	ctx context.Context, series *entity.TrainingSeries, req ReqUpdateTrainingSeries,
+) (*RespUpdateTrainingSeries, core.UseCaseError) {
+	occ, ok := series.OccurrenceOn(req.OccurrenceDate)
+	if !ok || series.IsException(req.OccurrenceDate) {
+		return nil, ErrUpdateTrainingSeriesNotOccurrence
+	}
+	start, err := timeutil.ParseDateTime(req.OccurrenceDate, req.StartTime, series.Timezone())
+	if err != nil {
+		return nil, ErrUpdateTrainingSeriesInvalidInput.Wrap(err)
+	}
+	end, err := timeutil.ParseDateTime(req.OccurrenceDate, req.EndTime, series.Timezone())
+	if err != nil {
+		return nil, ErrUpdateTrainingSeriesInvalidInput.Wrap(err)
+	}
+	period, err := entity.NewTimeRange(start, end)
+	if err != nil {
+		return nil, ErrUpdateTrainingSeriesInvalidInput.Wrap(err)
+	}
+
+	existing, err := uc.materializer.findOccurrences(ctx, series, occ.Period.Start())
+	if err != nil {
+		return nil, ErrUpdateTrainingSeriesFindTrainDateFail.Wrap(err)
+	}
+	training := existing[req.OccurrenceDate]
+	isNew := training == nil
+	if isNew {
+		// 尚未產生的場次直接建立為單堂課程
+		training, err = entity.NewTrainDate(
+			entity.WithBasicTrainDate(uc.repo.GenerateID(), series.CoachID(), req.Location, req.Capacity, period),
+			entity.WithTrainDateTimezone(series.Timezone()),
+			entity.WithTrainDateBookingPolicy(uc.materializer.defaultPolicy),
+		)
+		if err != nil {
+			return nil, ErrUpdateTrainingSeriesDomainFail.Wrap(err)
+		}
+	}
+	if err := uc.trainSvc.CheckOverlapExcept(ctx, series.CoachID(), period, training.ID()); err != nil {
+		return nil, ErrUpdateTrainingSeriesCoachBusy.Wrap(err)
+	}
+	if !isNew {
+		if err := training.UpdateDetails(req.Location, req.Capacity, period); err != nil {
+			return nil, mapUpdateTrainDateError(err)
+		}
+		training.DetachFromSeries()
+	}
+
+	if err := series.AddException(req.OccurrenceDate); err != nil {
+		return nil, ErrUpdateTrainingSeriesDomainFail.Wrap(err)
+	}
+	// 例外日期與場次一起寫入，避免場次已脫離系列但之後又被重新產生
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.saveSeries(ctx, series); err != nil {
+			return err
+		}
+		var err error
+		var evt event.Event
+		if isNew {
+			err = uc.repo.SaveTrainDate(ctx, training)
+			evt = newTrainDateCreatedEvent(uc.repo.GenerateID(), training, time.Now())
+		} else {
+			err = uc.repo.UpdateManyTrainDates(ctx, []*entity.TrainDate{training})
+			evt = newTrainDateUpdatedEvent(uc.repo.GenerateID(), training,
+				domain.TrainDateFieldDetails, domain.TrainDateFieldSeries)
+		}
+		if err != nil {
+			return ErrUpdateTrainingSeriesSaveTrainDateFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	return &RespUpdateTrainingSeries{
+		Series:  series,
+		Updated: []*entity.TrainDate{training},
+	}, nil
+}
+
+// updateFollowing 從該堂切出新系列，原系列在該堂前結束
+func (uc *updateTrainingSeriesUseCase) updateFollowing(
+	ctx context.Context, series *entity.TrainingSeries, req ReqUpdateTrainingSeries,
+) (*RespUpdateTrainingSeries, core.UseCaseError) {
+	occ, ok := series.OccurrenceOn(req.OccurrenceDate)
+	if !ok {
+		return nil, ErrUpdateTrainingSeriesNotOccurrence
+	}
+	existing, findErr := uc.repo.FindTrainDates(ctx,
+		repository.NewFilterTrainDateBySeriesID(series.ID(), occ.Period.Start()))
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		return nil, ErrUpdateTrainingSeriesFindTrainDateFail.Wrap(findErr)
+	}
+
+	next, err := series.Split(uc.repo.GenerateID(), req.OccurrenceDate)
+	if err != nil {
+		return nil, ErrUpdateTrainingSeriesNotOccurrence.Wrap(err)
+	}
+	if err := next.UpdateTemplate(req.Location, req.Capacity, req.StartTime, req.EndTime); err != nil {
+		return nil, ErrUpdateTrainingSeriesDomainFail.Wrap(err)
+	}
+	updated, skipped, err := uc.materializer.applyTemplate(ctx, next, existing)
+	if err != nil {
+		return nil, ErrUpdateTrainingSeriesApplyFail.Wrap(err)
+	}
+
+	// 原系列結束、新系列與場次的修改在同一個交易內，任一步失敗時全部回復
+	fields := []string{domain.TrainDateFieldDetails, domain.TrainDateFieldSeries}
+	return uc.finish(ctx, next, updated, skipped, fields, func(ctx context.Context) core.UseCaseError {
+		// 先存原系列，版本衝突時不需再寫入新系列
+		if err := uc.saveSeries(ctx, series); err != nil {
+			return err
+		}
+		return uc.saveSeries(ctx, next)
+	})
+}
+
+// updateAll 修改整個系列，只影響尚未開始的場次
+func (uc *updateTrainingSeriesUseCase) updateAll(
+	ctx context.Context, series *entity.TrainingSeries, req ReqUpdateTrainingSeries,
+) (*RespUpdateTrainingSeries, core.UseCaseError) {
+	existing, findErr := uc.repo.FindTrainDates(ctx,
+		repository.NewFilterTrainDateBySeriesID(series.ID(), time.Now()))
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		return nil, ErrUpdateTrainingSeriesFindTrainDateFail.Wrap(findErr)
+	}
+	if err := series.UpdateTemplate(req.Location, req.Capacity, req.StartTime, req.EndTime); err != nil {
+		return nil, ErrUpdateTrainingSeriesDomainFail.Wrap(err)
+	}
+	updated, skipped, err := uc.materializer.applyTemplate(ctx, series, existing)
+	if err != nil {
+		return nil, ErrUpdateTrainingSeriesApplyFail.Wrap(err)
+	}
+	fields := []string{domain.TrainDateFieldDetails}
+	return uc.finish(ctx, series, updated, skipped, fields, func(ctx context.Context) core.UseCaseError {
+		return uc.saveSeries(ctx, series)
+	})
+}
+
+// finish 在同一個交易內儲存系列、寫回已修改的場次，並補齊新設定下尚未產生的場次；
+// fields 為套用新設定的場次異動的欄位，未套用而脫離系列的場次只異動系列
+func (uc *updateTrainingSeriesUseCase) finish(
+	ctx context.Context, series *entity.TrainingSeries,
+	updated []*entity.TrainDate, skipped []SkippedOccurrence, fields []string,
+	saveSeries func(ctx context.Context) core.UseCaseError,
+) (*RespUpdateTrainingSeries, core.UseCaseError) {
+	detached := make(map[string]struct{}, len(skipped))
+	for _, s := range skipped {
+		detached[s.TrainDateID] = struct{}{}
+	}
+	events := make([]event.Event, 0, len(updated))
+	for _, t := range updated {
+		if _, ok := detached[t.ID()]; ok {
+			events = append(events, newTrainDateUpdatedEvent(uc.repo.GenerateID(), t, domain.TrainDateFieldSeries))
+			continue
+		}
+		events = append(events, newTrainDateUpdatedEvent(uc.repo.GenerateID(), t, fields...))
+	}
+
+	var created []*entity.TrainDate
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := saveSeries(ctx); err != nil {
+			return err
+		}
+		if len(updated) > 0 {
+			if err := uc.repo.UpdateManyTrainDates(ctx, updated); err != nil {
+				return ErrUpdateTrainingSeriesSaveTrainDateFail.Wrap(err)
+			}
+			if ucErr := core.AddEvents(ctx, uc.repo, events...); ucErr != nil {
+				return ucErr
+			}
+		}
+		now := time.Now()
+		var moreSkipped []SkippedOccurrence
+		var err error
+		created, moreSkipped, err = uc.materializer.materialize(
+			ctx, series, now, now.AddDate(0, 0, 7*DefaultHorizonWeeks))
+		if err != nil {
+			return ErrUpdateTrainingSeriesApplyFail.Wrap(err)
+		}
+		skipped = append(skipped, moreSkipped...)
+		return nil
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	return &RespUpdateTrainingSeries{
+		Series:  series,
+		Updated: updated,
+		Created: created,
+		Skipped: skipped,
+	}, nil
+}
+
+func (uc *updateTrainingSeriesUseCase) saveSeries(
+	ctx context.Context, series *entity.TrainingSeries,
+) core.UseCaseError {
+	err := uc.repo.SaveTrainingSeries(ctx, series)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return ErrUpdateTrainingSeriesConflict.Wrap(err)
+	}
+	return ErrUpdateTrainingSeriesSaveFail.Wrap(err)
+}
+
+// findSeriesForEdit 取得可修改的系列，並確認是該教練的系列
+func findSeriesForEdit(
+	ctx context.Context, repo repository.TrainingSeriesRepository, seriesID, coachID string,
+) (*entity.TrainingSeries, core.UseCaseError) {
+	series, err := repo.FindTrainingSeriesByID(ctx, seriesID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTrainingSeriesNotFound
+		}
+		return nil, ErrTrainingSeriesFindFail.Wrap(err)
+	}
+	if coachID != "" && series.CoachID() != coachID {
+		return nil, ErrTrainingSeriesPermissionDenied
+	}
+	if !series.IsActive() {
+		return nil, ErrTrainingSeriesEnded
+	}
+	return series, nil
+}
+
+func mapUpdateTrainDateError(err error) core.UseCaseError {
+	switch {
+	case errors.Is(err, entity.ErrTrainingHasAppointments):
+		return ErrUpdateTrainingSeriesHasAppointments.Wrap(err)
+	case errors.Is(err, entity.ErrTrainingCapacityBelowBooked):
+		return ErrUpdateTrainingSeriesCapacityBelowBooked.Wrap(err)
+	default:
+		return ErrUpdateTrainingSeriesDomainFail.Wrap(err)
+	}
+}
+
+var (
+	ErrTrainingSeriesNotFound = core.NewDBError(
+		"TRAINING_SERIES", "NOT_FOUND", "training series not found", core.ErrNotFound)
+	ErrTrainingSeriesFindFail = core.NewDBError(
+		"TRAINING_SERIES", "FIND_SERIES_FAIL", "find training series fail", core.ErrInternal)
+	ErrTrainingSeriesPermissionDenied = core.NewUseCaseError(
+		"TRAINING_SERIES", "PERMISSION_DENIED", "permission denied", core.ErrPermissionDenied)
+	ErrTrainingSeriesEnded = core.NewUseCaseError(
+		"TRAINING_SERIES", "SERIES_ENDED", "系列課程已結束", core.ErrConflict)
+
+	ErrUpdateTrainingSeriesInvalidInput = core.NewUseCaseError(
+		"UPDATE_TRAINING_SERIES", "INVALID_INPUT", "invalid input", core.ErrInvalidInput)
+	ErrUpdateTrainingSeriesNotOccurrence = core.NewUseCaseError(
+		"UPDATE_TRAINING_SERIES", "NOT_OCCURRENCE", "該日期沒有這個系列的課程", core.ErrInvalidInput)
+	ErrUpdateTrainingSeriesDomainFail = core.NewDomainError(
+		"UPDATE_TRAINING_SERIES", "DOMAIN_ERROR", "update training series fail", core.ErrInvalidInput)
+	ErrUpdateTrainingSeriesCoachBusy = core.NewDomainError(
+		"UPDATE_TRAINING_SERIES", "COACH_BUSY", "coach is busy", core.ErrConflict)
+	ErrUpdateTrainingSeriesHasAppointments = core.NewDomainError(
+		"UPDATE_TRAINING_SERIES", "HAS_APPOINTMENTS", "已有學員預約，無法修改上課時間", core.ErrConflict)
+	ErrUpdateTrainingSeriesCapacityBelowBooked = core.NewDomainError(
+		"UPDATE_TRAINING_SERIES", "CAPACITY_BELOW_BOOKED", "名額不可少於已預約人數", core.ErrConflict)
+	ErrUpdateTrainingSeriesFindTrainDateFail = core.NewDBError(
+		"UPDATE_TRAINING_SERIES", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrUpdateTrainingSeriesApplyFail = core.NewDBError(
+		"UPDATE_TRAINING_SERIES", "APPLY_FAIL", "apply series to train dates fail", core.ErrInternal)
+	ErrUpdateTrainingSeriesConflict = core.NewDBError(
+		"UPDATE_TRAINING_SERIES", "CONFLICT", "系列課程已被更新，請重新操作", core.ErrConflict)
+	ErrUpdateTrainingSeriesSaveFail = core.NewDBError(
+		"UPDATE_TRAINING_SERIES", "SAVE_SERIES_FAIL", "save training series fail", core.ErrInternal)
+	ErrUpdateTrainingSeriesSaveTrainDateFail = core.NewDBError(
+		"UPDATE_TRAINING_SERIES", "SAVE_TRAIN_DATE_FAIL", "save train date fail", core.ErrInternal)
+)
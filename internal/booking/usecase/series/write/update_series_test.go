@@ -0,0 +1,219 @@
+package write
+
+import (
+	"testing"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/util/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSeriesUpdate 建立系列並產生前 4 堂，回傳的場次依週次排列
+func setupSeriesUpdate(t *testing.T) (*memSeriesRepo, *entity.TrainingSeries, []*entity.TrainDate, UpdateTrainingSeriesUseCase) {
+	t.Helper()
+	repo := newMemSeriesRepo()
+	series := newTestSeries(t, repo)
+	initial, _, err := newTestMaterializer(repo).materialize(t.Context(), series, time.Now(), weeks(4))
+	require.NoError(t, err)
+	require.Len(t, initial, 4)
+	repo.txCount, repo.events = 0, nil
+	uc := NewUpdateTrainingSeriesUseCase(repo, service.NewTrainDateService(repo), entity.DefaultBookingPolicy())
+	return repo, series, initial, uc
+}
+
+func periodOn(t *testing.T, series *entity.TrainingSeries, date, startClock, endClock string) entity.TimeRange {
+	t.Helper()
+	start, err := timeutil.ParseDateTime(date, startClock, series.Timezone())
+	require.NoError(t, err)
+	end, err := timeutil.ParseDateTime(date, endClock, series.Timezone())
+	require.NoError(t, err)
+	period, err := entity.NewTimeRange(start, end)
+	require.NoError(t, err)
+	return period
+}
+
+func clockOf(series *entity.TrainingSeries, td *entity.TrainDate) string {
+	loc, _ := time.LoadLocation(series.Timezone())
+	return td.Period().Start().In(loc).Format("15:04")
+}
+
+func skippedReasons(skipped []SkippedOccurrence) map[string]string {
+	reasons := make(map[string]string, len(skipped))
+	for _, s := range skipped {
+		reasons[s.Date] = s.Reason
+	}
+	return reasons
+}
+
+func TestUpdateTrainingSeries_This(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("UpdatesOneOccurrence", func(t *testing.T) {
+		repo, series, initial, uc := setupSeriesUpdate(t)
+		resp, err := uc.Execute(ctx, ReqUpdateTrainingSeries{
+			SeriesID: series.ID(), OccurrenceDate: weekDate(series, 1), Scope: SeriesEditScopeThis,
+			Location: "Gym B", StartTime: "12:00", EndTime: "13:00", Capacity: 8,
+		})
+		require.Nil(t, err)
+		require.Len(t, resp.Updated, 1)
+		assert.Equal(t, initial[1].ID(), resp.Updated[0].ID())
+		assert.Empty(t, initial[1].SeriesID())
+		assert.Equal(t, "12:00", clockOf(series, initial[1]))
+		assert.Equal(t, "Gym B", initial[1].Location())
+		assert.True(t, repo.series[series.ID()].IsException(weekDate(series, 1)))
+		// 其他場次仍跟隨系列
+		assert.Equal(t, series.ID(), initial[2].SeriesID())
+		assert.Equal(t, "10:00", clockOf(series, initial[2]))
+		assert.Equal(t, 1, repo.txCount)
+		assert.Equal(t, map[string][]string{
+			domain.TopicTrainDateUpdated: trainingIDs(initial[1]),
+		}, repo.publishedTrainingIDs(t))
+	})
+
+	// 尚未產生的場次直接建立為單堂課程
+	t.Run("NotMaterializedYet", func(t *testing.T) {
+		repo, series, _, uc := setupSeriesUpdate(t)
+		resp, err := uc.Execute(ctx, ReqUpdateTrainingSeries{
+			SeriesID: series.ID(), OccurrenceDate: weekDate(series, 5), Scope: SeriesEditScopeThis,
+			Location: "Gym B", StartTime: "12:00", EndTime: "13:00", Capacity: 8,
+		})
+		require.Nil(t, err)
+		require.Len(t, resp.Updated, 1)
+		created := repo.trainings[resp.Updated[0].ID()]
+		require.NotNil(t, created)
+		assert.Empty(t, created.SeriesID())
+		assert.Equal(t, weekDate(series, 5), series.DateOf(created.Period().Start()))
+		assert.True(t, series.IsException(weekDate(series, 5)))
+		assert.Equal(t, map[string][]string{
+			domain.TopicTrainDateCreated: trainingIDs(created),
+		}, repo.publishedTrainingIDs(t))
+	})
+
+	t.Run("HasAppointments", func(t *testing.T) {
+		repo, series, initial, uc := setupSeriesUpdate(t)
+		require.NoError(t, initial[2].ReserveSpot(1))
+		_, err := uc.Execute(ctx, ReqUpdateTrainingSeries{
+			SeriesID: series.ID(), OccurrenceDate: weekDate(series, 2), Scope: SeriesEditScopeThis,
+			Location: "Gym A", StartTime: "12:00", EndTime: "13:00", Capacity: 10,
+		})
+		require.NotNil(t, err)
+		assert.Equal(t, ErrUpdateTrainingSeriesHasAppointments.Code(), err.Code())
+		assert.Equal(t, series.ID(), initial[2].SeriesID())
+		assert.False(t, series.IsException(weekDate(series, 2)))
+		assert.Zero(t, repo.txCount)
+	})
+
+	t.Run("CoachBusy", func(t *testing.T) {
+		repo, series, initial, uc := setupSeriesUpdate(t)
+		repo.addBusy(t, series.CoachID(), periodOn(t, series, weekDate(series, 1), "12:30", "13:30"))
+		_, err := uc.Execute(ctx, ReqUpdateTrainingSeries{
+			SeriesID: series.ID(), OccurrenceDate: weekDate(series, 1), Scope: SeriesEditScopeThis,
+			Location: "Gym A", StartTime: "12:00", EndTime: "13:00", Capacity: 10,
+		})
+		require.NotNil(t, err)
+		assert.Equal(t, ErrUpdateTrainingSeriesCoachBusy.Code(), err.Code())
+		assert.Equal(t, "10:00", clockOf(series, initial[1]))
+	})
+}
+
+func TestUpdateTrainingSeries_Following(t *testing.T) {
+	repo, series, initial, uc := setupSeriesUpdate(t)
+	require.NoError(t, initial[2].ReserveSpot(1))
+	// 新時段與第 3 堂 (已產生) 及第 5 堂 (尚未產生) 的其他課程重疊
+	repo.addBusy(t, series.CoachID(), periodOn(t, series, weekDate(series, 3), "12:00", "13:00"))
+	repo.addBusy(t, series.CoachID(), periodOn(t, series, weekDate(series, 5), "12:00", "13:00"))
+
+	resp, err := uc.Execute(t.Context(), ReqUpdateTrainingSeries{
+		SeriesID: series.ID(), OccurrenceDate: weekDate(series, 1), Scope: SeriesEditScopeFollowing,
+		Location: "Gym B", StartTime: "12:00", EndTime: "13:00", Capacity: 10,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 1, repo.txCount)
+
+	// 原系列在該堂前結束，之後的場次由新系列負責
+	next := resp.Series
+	assert.NotEqual(t, series.ID(), next.ID())
+	assert.Equal(t, weekDate(series, 1), next.StartDate())
+	assert.Equal(t, weekDate(series, 0), repo.series[series.ID()].Occurrences(time.Time{}, weeks(9))[0].Date)
+	assert.Len(t, repo.series[series.ID()].Occurrences(time.Time{}, weeks(9)), 1)
+	assert.Same(t, next, repo.series[next.ID()])
+
+	assert.Equal(t, series.ID(), initial[0].SeriesID())
+	assert.Equal(t, "10:00", clockOf(series, initial[0]))
+	assert.Equal(t, next.ID(), initial[1].SeriesID())
+	assert.Equal(t, "12:00", clockOf(series, initial[1]))
+	assert.Equal(t, "Gym B", initial[1].Location())
+
+	// 已有預約或與其他課程衝突的場次保留原狀並脫離系列
+	for _, week := range []int{2, 3} {
+		assert.Empty(t, initial[week].SeriesID())
+		assert.Equal(t, "10:00", clockOf(series, initial[week]))
+		assert.True(t, next.IsException(weekDate(series, week)))
+	}
+	assert.Equal(t, map[string]string{
+		weekDate(series, 2): SkipReasonHasAppointments,
+		weekDate(series, 3): SkipReasonCoachBusy,
+		weekDate(series, 5): SkipReasonCoachBusy,
+	}, skippedReasons(resp.Skipped))
+
+	assert.Equal(t,
+		[]string{weekDate(series, 4), weekDate(series, 6), weekDate(series, 7)},
+		trainingDates(series, resp.Created))
+	for _, td := range resp.Created {
+		assert.Equal(t, "12:00", clockOf(series, td))
+		assert.Equal(t, next.ID(), td.SeriesID())
+	}
+	// 修改與脫離系列的場次發布 TrainDateUpdated，新產生的發布 TrainDateCreated
+	assert.Equal(t, map[string][]string{
+		domain.TopicTrainDateUpdated: trainingIDs(initial[1:]...),
+		domain.TopicTrainDateCreated: trainingIDs(resp.Created...),
+	}, repo.publishedTrainingIDs(t))
+}
+
+func TestUpdateTrainingSeries_All(t *testing.T) {
+	repo, series, initial, uc := setupSeriesUpdate(t)
+	require.NoError(t, initial[1].ReserveSpot(1))
+	repo.addBusy(t, series.CoachID(), periodOn(t, series, weekDate(series, 2), "12:00", "13:00"))
+	repo.addBusy(t, series.CoachID(), periodOn(t, series, weekDate(series, 6), "12:00", "13:00"))
+
+	resp, err := uc.Execute(t.Context(), ReqUpdateTrainingSeries{
+		SeriesID: series.ID(), Scope: SeriesEditScopeAll,
+		Location: "Gym B", StartTime: "12:00", EndTime: "13:00", Capacity: 10,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 1, repo.txCount)
+
+	assert.Equal(t, series.ID(), resp.Series.ID())
+	assert.Equal(t, "Gym B", series.Location())
+	assert.Equal(t, "12:00", series.StartClock())
+
+	for _, week := range []int{0, 3} {
+		assert.Equal(t, series.ID(), initial[week].SeriesID())
+		assert.Equal(t, "12:00", clockOf(series, initial[week]))
+	}
+	for _, week := range []int{1, 2} {
+		assert.Empty(t, initial[week].SeriesID())
+		assert.Equal(t, "10:00", clockOf(series, initial[week]))
+		assert.True(t, series.IsException(weekDate(series, week)))
+	}
+	assert.Equal(t, map[string]string{
+		weekDate(series, 1): SkipReasonHasAppointments,
+		weekDate(series, 2): SkipReasonCoachBusy,
+		weekDate(series, 6): SkipReasonCoachBusy,
+	}, skippedReasons(resp.Skipped))
+
+	// 補齊新設定下視窗內尚未產生的場次
+	assert.Equal(t,
+		[]string{weekDate(series, 4), weekDate(series, 5), weekDate(series, 7)},
+		trainingDates(series, resp.Created))
+	assert.Len(t, repo.seriesTrainings(series.ID()), 5)
+	assert.Equal(t, map[string][]string{
+		domain.TopicTrainDateUpdated: trainingIDs(initial...),
+		domain.TopicTrainDateCreated: trainingIDs(resp.Created...),
+	}, repo.publishedTrainingIDs(t))
+}
@@ -9,6 +9,8 @@ import (
 
 type ReqGetBusinessAnalytics struct {
 	MonthsLimit int
+	// TeamID 只統計某團隊的場次，空字串代表不篩選
+	TeamID string
 }
 
 type RespGetBusinessAnalytics struct {
@@ -38,6 +40,10 @@ func (uc *getBusinessAnalyticsUseCase) Name() string {
 }
 
 func (uc *getBusinessAnalyticsUseCase) Execute(ctx context.Context, req ReqGetBusinessAnalytics) (*RespGetBusinessAnalytics, core.UseCaseError) {
+	ctx, scopeErr := core.ScopeToTeam(ctx, req.TeamID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
 	if req.MonthsLimit <= 0 {
 		req.MonthsLimit = 12
 	}
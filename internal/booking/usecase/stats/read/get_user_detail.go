@@ -2,11 +2,14 @@ package read
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/usecase/core"
 	"seanAIgent/internal/util/timeutil"
 	"sort"
+	"time"
 )
 
 type ReqGetUserDetail struct {
@@ -18,6 +21,7 @@ type RespGetUserDetail struct {
 	LineDisplayName string
 	OverallStats    UserOverallStatsVO
 	MonthlyRecords  []*UserMonthlyRecordVO
+	Credit          *UserCreditVO // 未購買課程包時為 nil
 }
 
 type UserOverallStatsVO struct {
@@ -42,13 +46,47 @@ type ChildBookingRecordVO struct {
 	Status    string
 }
 
+// UserCreditVO 課程包餘額與異動紀錄
+type UserCreditVO struct {
+	NextExpiry string
+	Packs      []*CreditPackVO
+	History    []*CreditHistoryVO // 由新到舊
+	Available  int
+	Reserved   int
+	Consumed   int
+	Expired    int
+}
+
+type CreditPackVO struct {
+	ChildName   string
+	PurchasedAt string
+	ExpiresAt   string
+	Note        string
+	Quantity    int
+	Remaining   int
+	IsExpired   bool
+}
+
+type CreditHistoryVO struct {
+	Type       string
+	ChildName  string
+	Note       string
+	OccurredAt string
+	Amount     int
+}
+
 type GetUserDetailUseCase core.ReadUseCase[ReqGetUserDetail, *RespGetUserDetail]
 
+type getUserDetailUseCaseRepo interface {
+	repository.StatsRepository
+	repository.CreditLedgerRepository
+}
+
 type getUserDetailUseCase struct {
-	repo repository.StatsRepository
+	repo getUserDetailUseCaseRepo
 }
 
-func NewGetUserDetailUseCase(repo repository.StatsRepository) GetUserDetailUseCase {
+func NewGetUserDetailUseCase(repo getUserDetailUseCaseRepo) GetUserDetailUseCase {
 	return &getUserDetailUseCase{repo: repo}
 }
 
@@ -125,5 +163,56 @@ func (uc *getUserDetailUseCase) Execute(ctx context.Context, req ReqGetUserDetai
 		})
 	}
 
+	// 4. 課程包帳本
+	ledger, err := uc.repo.FindCreditLedgerByUserID(ctx, req.UserID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, core.NewDBError("GET_USER_DETAIL", "FETCH_CREDIT_FAIL", "failed to fetch credit ledger", core.ErrInternal).Wrap(err)
+	}
+	if ledger != nil {
+		resp.Credit = toUserCreditVO(ledger, time.Now())
+	}
+
 	return resp, nil
 }
+
+func toUserCreditVO(ledger *entity.CreditLedger, now time.Time) *UserCreditVO {
+	const (
+		layout         = "2006/01/02"
+		creditTimezone = "Asia/Taipei"
+	)
+	balance := ledger.Balance(now)
+	vo := &UserCreditVO{
+		Available: balance.Available,
+		Reserved:  balance.Reserved,
+		Consumed:  balance.Consumed,
+		Expired:   balance.Expired,
+		Packs:     make([]*CreditPackVO, 0, len(ledger.Packs())),
+		History:   make([]*CreditHistoryVO, 0, len(ledger.Entries())),
+	}
+	if balance.NextExpiry != nil {
+		vo.NextExpiry = timeutil.ToLocation(*balance.NextExpiry, creditTimezone).Format(layout)
+	}
+	for _, p := range ledger.Packs() {
+		vo.Packs = append(vo.Packs, &CreditPackVO{
+			ChildName:   p.ChildName(),
+			PurchasedAt: timeutil.ToLocation(p.PurchasedAt(), creditTimezone).Format(layout),
+			ExpiresAt:   timeutil.ToLocation(p.ExpiresAt(), creditTimezone).Format(layout),
+			Note:        p.Note(),
+			Quantity:    p.Quantity(),
+			Remaining:   ledger.Remaining(p.ID()),
+			IsExpired:   p.IsExpiredAt(now),
+		})
+	}
+	entries := ledger.Entries()
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		vo.History = append(vo.History, &CreditHistoryVO{
+			Type:       e.Type().String(),
+			ChildName:  e.ChildName(),
+			Note:       e.Note(),
+			OccurredAt: timeutil.ToLocation(e.OccurredAt(), creditTimezone).Format("2006/01/02 15:04"),
+			Amount:     e.Amount(),
+		})
+	}
+	return vo
+}
@@ -2,6 +2,9 @@ package read
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/usecase/core"
@@ -13,21 +16,35 @@ type ReqQueryMonthlyUserReports struct {
 	Page   int64
 	Limit  int64
 	Search string
+	// PaymentStatus 依繳費狀態 (PAID/UNPAID/OVERDUE) 過濾，空字串代表不過濾
+	PaymentStatus string
+	// TeamID 只統計某團隊場次的預約，空字串代表不篩選
+	TeamID string
 }
 
 type RespQueryMonthlyUserReports struct {
+	// Billing 以 UserID 對應當月帳單
+	Billing   map[string]*entity.BillingStatement
 	UserStats []*entity.UserMonthlyStat
 	Total     int64
 }
 
 type QueryMonthlyUserReportsUseCase core.ReadUseCase[ReqQueryMonthlyUserReports, *RespQueryMonthlyUserReports]
 
+type queryMonthlyUserReportsUseCaseRepo interface {
+	repository.StatsRepository
+	repository.PaymentRepository
+}
+
 type queryMonthlyUserReportsUseCase struct {
-	repo repository.StatsRepository
+	repo   queryMonthlyUserReportsUseCaseRepo
+	policy entity.BillingPolicy
 }
 
-func NewQueryMonthlyUserReportsUseCase(repo repository.StatsRepository) QueryMonthlyUserReportsUseCase {
-	return &queryMonthlyUserReportsUseCase{repo: repo}
+func NewQueryMonthlyUserReportsUseCase(
+	repo queryMonthlyUserReportsUseCaseRepo, policy entity.BillingPolicy,
+) QueryMonthlyUserReportsUseCase {
+	return &queryMonthlyUserReportsUseCase{repo: repo, policy: policy}
 }
 
 func (uc *queryMonthlyUserReportsUseCase) Name() string {
@@ -35,18 +52,77 @@ func (uc *queryMonthlyUserReportsUseCase) Name() string {
 }
 
 func (uc *queryMonthlyUserReportsUseCase) Execute(ctx context.Context, req ReqQueryMonthlyUserReports) (*RespQueryMonthlyUserReports, core.UseCaseError) {
+	ctx, scopeErr := core.ScopeToTeam(ctx, req.TeamID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
+	filterStatus := req.PaymentStatus != ""
+	if filterStatus {
+		if _, ok := entity.BillingStatusFromString(req.PaymentStatus); !ok {
+			return nil, ErrQueryUserReportInvalidPaymentStatus
+		}
+	}
+
 	skip := (req.Page - 1) * req.Limit
 	if skip < 0 {
 		skip = 0
 	}
 
-	stats, total, err := uc.repo.FindMonthlyStats(ctx, req.Year, req.Month, skip, req.Limit, req.Search)
+	// 繳費狀態需與收款紀錄比對後才知道，過濾時先取出整月資料再於記憶體分頁
+	repoSkip, repoLimit := skip, req.Limit
+	if filterStatus {
+		repoSkip, repoLimit = 0, 0
+	}
+	stats, total, err := uc.repo.FindMonthlyStats(ctx, req.Year, req.Month, repoSkip, repoLimit, req.Search)
 	if err != nil {
-		return nil, core.NewDBError("QUERY_USER_REPORT", "FETCH_FAIL", "failed to fetch user reports", core.ErrInternal).Wrap(err)
+		return nil, ErrQueryUserReportFetchFail.Wrap(err)
+	}
+
+	payments, err := uc.repo.FindPaymentsByFilter(ctx, repository.NewFilterPaymentByPeriod(req.Year, req.Month))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrQueryUserReportFetchPaymentsFail.Wrap(err)
+	}
+
+	now := time.Now()
+	billing := make(map[string]*entity.BillingStatement, len(stats))
+	for _, s := range stats {
+		billing[s.UserID] = uc.policy.Statement(s, payments, now)
+	}
+
+	if filterStatus {
+		filtered := make([]*entity.UserMonthlyStat, 0, len(stats))
+		for _, s := range stats {
+			if billing[s.UserID].Status().String() == req.PaymentStatus {
+				filtered = append(filtered, s)
+			}
+		}
+		total = int64(len(filtered))
+		stats = paginate(filtered, skip, req.Limit)
 	}
 
 	return &RespQueryMonthlyUserReports{
 		UserStats: stats,
 		Total:     total,
+		Billing:   billing,
 	}, nil
 }
+
+func paginate(stats []*entity.UserMonthlyStat, skip, limit int64) []*entity.UserMonthlyStat {
+	if skip >= int64(len(stats)) {
+		return []*entity.UserMonthlyStat{}
+	}
+	end := int64(len(stats))
+	if limit > 0 && skip+limit < end {
+		end = skip + limit
+	}
+	return stats[skip:end]
+}
+
+var (
+	ErrQueryUserReportFetchFail = core.NewDBError(
+		"QUERY_USER_REPORT", "FETCH_FAIL", "failed to fetch user reports", core.ErrInternal)
+	ErrQueryUserReportFetchPaymentsFail = core.NewDBError(
+		"QUERY_USER_REPORT", "FETCH_PAYMENTS_FAIL", "failed to fetch payments", core.ErrInternal)
+	ErrQueryUserReportInvalidPaymentStatus = core.NewUseCaseError(
+		"QUERY_USER_REPORT", "INVALID_PAYMENT_STATUS", "繳費狀態不正確", core.ErrInvalidInput)
+)
@@ -0,0 +1,56 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqQueryStudents struct {
+	UserID string
+}
+
+type QueryStudentsUseCase core.ReadUseCase[ReqQueryStudents, []*entity.Student]
+
+type queryStudentsUseCase struct {
+	repo repository.StudentRepository
+}
+
+func NewQueryStudentsUseCase(repo repository.StudentRepository) QueryStudentsUseCase {
+	return &queryStudentsUseCase{repo: repo}
+}
+
+func (uc *queryStudentsUseCase) Name() string {
+	return "QueryStudents"
+}
+
+// Execute 查詢家長底下的學員，依建立時間排序
+func (uc *queryStudentsUseCase) Execute(
+	ctx context.Context, req ReqQueryStudents,
+) ([]*entity.Student, core.UseCaseError) {
+	if req.UserID == "" {
+		return nil, ErrQueryStudentsInvalidInput
+	}
+	students, err := uc.repo.FindStudentsByUserID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*entity.Student{}, nil
+		}
+		return nil, ErrQueryStudentsFail.Wrap(err)
+	}
+	sort.SliceStable(students, func(i, j int) bool {
+		return students[i].CreatedAt().Before(students[j].CreatedAt())
+	})
+	return students, nil
+}
+
+var (
+	ErrQueryStudentsFail = core.NewDBError(
+		"QUERY_STUDENTS", "QUERY_FAIL", "query students fail", core.ErrInternal)
+	ErrQueryStudentsInvalidInput = core.NewUseCaseError(
+		"QUERY_STUDENTS", "INVALID_INPUT", "user id is required", core.ErrInvalidInput)
+)
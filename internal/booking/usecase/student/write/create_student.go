@@ -0,0 +1,88 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqCreateStudent struct {
+	Birthdate *time.Time
+	Parent    entity.User
+	Name      string
+	Nickname  string
+	Notes     string
+}
+
+type CreateStudentUseCase core.WriteUseCase[ReqCreateStudent, *entity.Student]
+
+type createStudentUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.StudentRepository
+}
+
+func NewCreateStudentUseCase(repo createStudentUseCaseRepo) CreateStudentUseCase {
+	return &createStudentUseCase{repo: repo}
+}
+
+type createStudentUseCase struct {
+	repo createStudentUseCaseRepo
+}
+
+func (uc *createStudentUseCase) Name() string {
+	return "CreateStudent"
+}
+
+func (uc *createStudentUseCase) Execute(
+	ctx context.Context, req ReqCreateStudent,
+) (*entity.Student, core.UseCaseError) {
+	student, err := entity.NewStudent(
+		entity.WithStudentID(uc.repo.GenerateID()),
+		entity.WithStudentParent(req.Parent),
+		entity.WithStudentName(req.Name),
+		entity.WithStudentNickname(req.Nickname),
+		entity.WithStudentBirthdate(req.Birthdate),
+		entity.WithStudentNotes(req.Notes),
+	)
+	if err != nil {
+		return nil, ErrStudentDomainFail.Wrap(err)
+	}
+	siblings, findErr := uc.repo.FindStudentsByUserID(ctx, req.Parent.UserID())
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		return nil, ErrStudentFindFail.Wrap(findErr)
+	}
+	if hasDuplicateName(siblings, student) {
+		return nil, ErrStudentDuplicateName
+	}
+	if saveErr := uc.repo.SaveStudent(ctx, student); saveErr != nil {
+		return nil, ErrStudentSaveFail.Wrap(saveErr)
+	}
+	return student, nil
+}
+
+// hasDuplicateName 同一家長底下的其他學員已使用相同姓名
+func hasDuplicateName(siblings []*entity.Student, student *entity.Student) bool {
+	for _, s := range siblings {
+		if s.ID() != student.ID() && s.Matches(student.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrStudentDomainFail = core.NewDomainError(
+		"STUDENT", "DOMAIN_ERROR", "學員資料不正確，姓名需為 1-20 字", core.ErrInvalidInput)
+	ErrStudentFindFail = core.NewDBError(
+		"STUDENT", "FIND_STUDENT_FAIL", "find student fail", core.ErrInternal)
+	ErrStudentNotFound = core.NewUseCaseError(
+		"STUDENT", "NOT_FOUND", "找不到學員", core.ErrNotFound)
+	ErrStudentDuplicateName = core.NewUseCaseError(
+		"STUDENT", "DUPLICATE_NAME", "已有相同姓名的學員", core.ErrConflict)
+	ErrStudentSaveFail = core.NewDBError(
+		"STUDENT", "SAVE_STUDENT_FAIL", "save student fail", core.ErrInternal)
+)
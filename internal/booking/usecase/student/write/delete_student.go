@@ -0,0 +1,68 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqDeleteStudent struct {
+	UserID    string
+	StudentID string
+}
+
+type DeleteStudentUseCase core.WriteUseCase[ReqDeleteStudent, *entity.Student]
+
+type deleteStudentUseCaseRepo interface {
+	repository.StudentRepository
+	repository.AppointmentRepository
+}
+
+func NewDeleteStudentUseCase(repo deleteStudentUseCaseRepo) DeleteStudentUseCase {
+	return &deleteStudentUseCase{repo: repo}
+}
+
+type deleteStudentUseCase struct {
+	repo deleteStudentUseCaseRepo
+}
+
+func (uc *deleteStudentUseCase) Name() string {
+	return "DeleteStudent"
+}
+
+func (uc *deleteStudentUseCase) Execute(
+	ctx context.Context, req ReqDeleteStudent,
+) (*entity.Student, core.UseCaseError) {
+	student, findErr := uc.repo.FindStudentByID(ctx, req.StudentID)
+	if findErr != nil {
+		if errors.Is(findErr, repository.ErrNotFound) {
+			return nil, ErrStudentNotFound
+		}
+		return nil, ErrStudentFindFail.Wrap(findErr)
+	}
+	if !student.BelongsTo(req.UserID) {
+		return nil, ErrStudentNotBelongToUser
+	}
+	// 已有預約紀錄的學員保留，避免歷史紀錄失去對應
+	appts, findApptErr := uc.repo.FindApptsByFilter(ctx, repository.NewFilterApptByStudentIDs(student.ID()))
+	if findApptErr != nil && !errors.Is(findApptErr, repository.ErrNotFound) {
+		return nil, ErrStudentFindFail.Wrap(findApptErr)
+	}
+	if len(appts) > 0 {
+		return nil, ErrStudentHasAppointments
+	}
+	if delErr := uc.repo.DeleteStudents(ctx, []*entity.Student{student}); delErr != nil {
+		return nil, ErrStudentDeleteFail.Wrap(delErr)
+	}
+	return student, nil
+}
+
+var (
+	ErrStudentHasAppointments = core.NewUseCaseError(
+		"STUDENT", "HAS_APPOINTMENTS", "學員已有預約紀錄，無法刪除", core.ErrConflict)
+	ErrStudentDeleteFail = core.NewDBError(
+		"STUDENT", "DELETE_STUDENT_FAIL", "delete student fail", core.ErrInternal)
+)
@@ -0,0 +1,112 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+// ReqMergeStudents 將 SourceIDs 的預約併入 TargetID 後刪除來源學員，僅限同一家長
+type ReqMergeStudents struct {
+	TargetID  string
+	SourceIDs []string
+}
+
+type MergeStudentsUseCase core.WriteUseCase[ReqMergeStudents, *entity.Student]
+
+type mergeStudentsUseCaseRepo interface {
+	repository.StudentRepository
+	repository.AppointmentRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewMergeStudentsUseCase(repo mergeStudentsUseCaseRepo) MergeStudentsUseCase {
+	return &mergeStudentsUseCase{repo: repo}
+}
+
+type mergeStudentsUseCase struct {
+	repo mergeStudentsUseCaseRepo
+}
+
+func (uc *mergeStudentsUseCase) Name() string {
+	return "MergeStudents"
+}
+
+func (uc *mergeStudentsUseCase) Execute(
+	ctx context.Context, req ReqMergeStudents,
+) (*entity.Student, core.UseCaseError) {
+	if req.TargetID == "" || len(req.SourceIDs) == 0 || slices.Contains(req.SourceIDs, req.TargetID) {
+		return nil, ErrMergeStudentsInvalidInput.Wrap(entity.ErrStudentMergeInvalid)
+	}
+	target, err := uc.findStudent(ctx, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*entity.Student, 0, len(req.SourceIDs))
+	for _, id := range req.SourceIDs {
+		source, err := uc.findStudent(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !source.BelongsTo(target.Parent().UserID()) {
+			return nil, ErrMergeStudentsInvalidInput.Wrap(entity.ErrStudentMergeInvalid)
+		}
+		sources = append(sources, source)
+	}
+
+	sourceIDs := make([]string, 0, len(sources))
+	for _, s := range sources {
+		sourceIDs = append(sourceIDs, s.ID())
+	}
+	// 排程快取由訂閱者依 StudentsMerged 清理
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if reassignErr := uc.repo.ReassignStudent(ctx, sourceIDs, target); reassignErr != nil {
+			if errors.Is(reassignErr, repository.ErrConflict) {
+				return ErrMergeStudentsConflict.Wrap(entity.ErrStudentMergeConflict)
+			}
+			return ErrMergeStudentsReassignFail.Wrap(reassignErr)
+		}
+		if delErr := uc.repo.DeleteStudents(ctx, sources); delErr != nil {
+			return ErrStudentDeleteFail.Wrap(delErr)
+		}
+		return core.AddEvents(ctx, uc.repo, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicStudentsMerged, domain.StudentsMerged{
+			TargetID:   target.ID(),
+			UserID:     target.Parent().UserID(),
+			SourceIDs:  sourceIDs,
+			OccurredAt: time.Now(),
+		}))
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	return target, nil
+}
+
+func (uc *mergeStudentsUseCase) findStudent(ctx context.Context, id string) (*entity.Student, core.UseCaseError) {
+	s, findErr := uc.repo.FindStudentByID(ctx, id)
+	if findErr != nil {
+		if errors.Is(findErr, repository.ErrNotFound) {
+			return nil, ErrStudentNotFound
+		}
+		return nil, ErrStudentFindFail.Wrap(findErr)
+	}
+	return s, nil
+}
+
+var (
+	ErrMergeStudentsInvalidInput = core.NewUseCaseError(
+		"MERGE_STUDENTS", "INVALID_INPUT", "需指定同一家長底下的目標與來源學員", core.ErrInvalidInput)
+	ErrMergeStudentsConflict = core.NewUseCaseError(
+		"MERGE_STUDENTS", "CONFLICT", "學員在同一場次皆有預約，請先取消其中一筆", core.ErrConflict)
+	ErrMergeStudentsReassignFail = core.NewDBError(
+		"MERGE_STUDENTS", "REASSIGN_FAIL", "reassign appointments fail", core.ErrInternal)
+)
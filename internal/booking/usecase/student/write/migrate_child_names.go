@@ -0,0 +1,128 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+// ReqMigrateChildNames DryRun 時只統計不寫入
+type ReqMigrateChildNames struct {
+	DryRun bool
+}
+
+// RespMigrateChildNames Failed 為無法對應的預約 (姓名不合法或同場次重複)，需人工處理
+type RespMigrateChildNames struct {
+	CreatedStudents []*entity.Student
+	FailedApptIDs   []string
+	Linked          int
+}
+
+type MigrateChildNamesUseCase core.WriteUseCase[ReqMigrateChildNames, *RespMigrateChildNames]
+
+type migrateChildNamesUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.StudentRepository
+	repository.AppointmentRepository
+	repository.EventOutbox
+}
+
+func NewMigrateChildNamesUseCase(repo migrateChildNamesUseCaseRepo) MigrateChildNamesUseCase {
+	return &migrateChildNamesUseCase{repo: repo}
+}
+
+type migrateChildNamesUseCase struct {
+	repo migrateChildNamesUseCaseRepo
+}
+
+func (uc *migrateChildNamesUseCase) Name() string {
+	return "MigrateChildNames"
+}
+
+func (uc *migrateChildNamesUseCase) Execute(
+	ctx context.Context, req ReqMigrateChildNames,
+) (*RespMigrateChildNames, core.UseCaseError) {
+	resp := &RespMigrateChildNames{}
+	studentsByUser := map[string][]*entity.Student{}
+	// 已處理 (DryRun) 或失敗的預約排除在下一批查詢之外，避免重複取得同一批
+	excludes := []string{}
+	for {
+		appts, findErr := uc.repo.FindApptsByFilter(ctx, repository.NewFilterApptWithoutStudent(excludes...))
+		if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+			return nil, ErrMigrateChildNamesFindFail.Wrap(findErr)
+		}
+		if len(appts) == 0 {
+			break
+		}
+		for _, appt := range appts {
+			userID := appt.User().UserID()
+			students, ok := studentsByUser[userID]
+			if !ok {
+				var findStudentErr repository.RepoError
+				students, findStudentErr = uc.repo.FindStudentsByUserID(ctx, userID)
+				if findStudentErr != nil && !errors.Is(findStudentErr, repository.ErrNotFound) {
+					return nil, ErrStudentFindFail.Wrap(findStudentErr)
+				}
+				studentsByUser[userID] = students
+			}
+			student := entity.FindStudentByName(students, appt.ChildName())
+			if student == nil {
+				newStudent, err := entity.NewStudent(
+					entity.WithStudentID(uc.repo.GenerateID()),
+					entity.WithStudentParent(appt.User()),
+					entity.WithStudentName(appt.ChildName()),
+				)
+				if err != nil {
+					resp.FailedApptIDs = append(resp.FailedApptIDs, appt.ID())
+					excludes = append(excludes, appt.ID())
+					continue
+				}
+				if !req.DryRun {
+					if saveErr := uc.repo.SaveStudent(ctx, newStudent); saveErr != nil {
+						return nil, ErrStudentSaveFail.Wrap(saveErr)
+					}
+				}
+				student = newStudent
+				studentsByUser[userID] = append(studentsByUser[userID], student)
+				resp.CreatedStudents = append(resp.CreatedStudents, student)
+			}
+			if err := appt.LinkStudent(student); err != nil {
+				resp.FailedApptIDs = append(resp.FailedApptIDs, appt.ID())
+				excludes = append(excludes, appt.ID())
+				continue
+			}
+			if req.DryRun {
+				excludes = append(excludes, appt.ID())
+				resp.Linked++
+				continue
+			}
+			if updateErr := uc.repo.UpdateAppt(ctx, appt); updateErr != nil {
+				resp.FailedApptIDs = append(resp.FailedApptIDs, appt.ID())
+				excludes = append(excludes, appt.ID())
+				continue
+			}
+			resp.Linked++
+		}
+	}
+	// 對應的預約分屬多位家長，排程快取由訂閱者依 ChildNamesMigrated 全部清理
+	if !req.DryRun && resp.Linked > 0 {
+		if ucErr := core.AddEvents(ctx, uc.repo, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicChildNamesMigrated, domain.ChildNamesMigrated{
+			Linked:          resp.Linked,
+			CreatedStudents: len(resp.CreatedStudents),
+			Failed:          len(resp.FailedApptIDs),
+			OccurredAt:      time.Now(),
+		})); ucErr != nil {
+			return nil, ucErr
+		}
+	}
+	return resp, nil
+}
+
+var ErrMigrateChildNamesFindFail = core.NewDBError(
+	"MIGRATE_CHILD_NAMES", "FIND_APPOINTMENT_FAIL", "find appointments without student fail", core.ErrInternal)
@@ -0,0 +1,101 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqUpdateStudent struct {
+	Birthdate *time.Time
+	UserID    string
+	StudentID string
+	Name      string
+	Nickname  string
+	Notes     string
+}
+
+type UpdateStudentUseCase core.WriteUseCase[ReqUpdateStudent, *entity.Student]
+
+type updateStudentUseCaseRepo interface {
+	repository.StudentRepository
+	repository.AppointmentRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewUpdateStudentUseCase(repo updateStudentUseCaseRepo) UpdateStudentUseCase {
+	return &updateStudentUseCase{repo: repo}
+}
+
+type updateStudentUseCase struct {
+	repo updateStudentUseCaseRepo
+}
+
+func (uc *updateStudentUseCase) Name() string {
+	return "UpdateStudent"
+}
+
+func (uc *updateStudentUseCase) Execute(
+	ctx context.Context, req ReqUpdateStudent,
+) (*entity.Student, core.UseCaseError) {
+	student, findErr := uc.repo.FindStudentByID(ctx, req.StudentID)
+	if findErr != nil {
+		if errors.Is(findErr, repository.ErrNotFound) {
+			return nil, ErrStudentNotFound
+		}
+		return nil, ErrStudentFindFail.Wrap(findErr)
+	}
+	if !student.BelongsTo(req.UserID) {
+		return nil, ErrStudentNotBelongToUser
+	}
+	oldName := student.Name()
+	if err := student.UpdateProfile(req.Name, req.Nickname, req.Birthdate, req.Notes); err != nil {
+		return nil, ErrStudentDomainFail.Wrap(err)
+	}
+	siblings, findErr := uc.repo.FindStudentsByUserID(ctx, req.UserID)
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		return nil, ErrStudentFindFail.Wrap(findErr)
+	}
+	if hasDuplicateName(siblings, student) {
+		return nil, ErrStudentDuplicateName
+	}
+	// 改名時同步既有預約上的姓名，讓報表與點名名單一致；排程快取由訂閱者清理
+	renamed := oldName != student.Name()
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if saveErr := uc.repo.SaveStudent(ctx, student); saveErr != nil {
+			return ErrStudentSaveFail.Wrap(saveErr)
+		}
+		if !renamed {
+			return nil
+		}
+		if reassignErr := uc.repo.ReassignStudent(ctx, []string{student.ID()}, student); reassignErr != nil {
+			return ErrStudentSyncApptFail.Wrap(reassignErr)
+		}
+		return core.AddEvents(ctx, uc.repo, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicStudentRenamed, domain.StudentRenamed{
+			StudentID:    student.ID(),
+			UserID:       req.UserID,
+			PreviousName: oldName,
+			Name:         student.Name(),
+			OccurredAt:   time.Now(),
+		}))
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	return student, nil
+}
+
+var (
+	ErrStudentNotBelongToUser = core.NewUseCaseError(
+		"STUDENT", "NOT_BELONG_TO_USER", "無權限修改此學員", core.ErrForbidden)
+	ErrStudentSyncApptFail = core.NewDBError(
+		"STUDENT", "SYNC_APPOINTMENT_FAIL", "同一場次已有相同姓名的預約，請先聯繫教練", core.ErrConflict)
+)
@@ -0,0 +1,71 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqQueryTeamMembers struct {
+	TeamID string
+}
+
+type QueryTeamMembersUseCase core.ReadUseCase[ReqQueryTeamMembers, []*entity.Student]
+
+type queryTeamMembersUseCaseRepo interface {
+	repository.TeamRepository
+	repository.StudentRepository
+}
+
+type queryTeamMembersUseCase struct {
+	repo queryTeamMembersUseCaseRepo
+}
+
+func NewQueryTeamMembersUseCase(repo queryTeamMembersUseCaseRepo) QueryTeamMembersUseCase {
+	return &queryTeamMembersUseCase{repo: repo}
+}
+
+func (uc *queryTeamMembersUseCase) Name() string {
+	return "QueryTeamMembers"
+}
+
+// Execute 查詢團隊成員學員，依家長與學員姓名排序
+func (uc *queryTeamMembersUseCase) Execute(
+	ctx context.Context, req ReqQueryTeamMembers,
+) ([]*entity.Student, core.UseCaseError) {
+	if req.TeamID == "" {
+		return nil, ErrQueryTeamMembersNotFound
+	}
+	team, err := uc.repo.FindTeamByID(ctx, req.TeamID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrQueryTeamMembersNotFound
+		}
+		return nil, ErrQueryTeamMembersFail.Wrap(err)
+	}
+	students, err := uc.repo.FindStudentsByIDs(ctx, team.MemberIDs())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*entity.Student{}, nil
+		}
+		return nil, ErrQueryTeamMembersFail.Wrap(err)
+	}
+	sort.SliceStable(students, func(i, j int) bool {
+		if students[i].Parent().UserName() != students[j].Parent().UserName() {
+			return students[i].Parent().UserName() < students[j].Parent().UserName()
+		}
+		return students[i].Name() < students[j].Name()
+	})
+	return students, nil
+}
+
+var (
+	ErrQueryTeamMembersNotFound = core.NewUseCaseError(
+		"QUERY_TEAM_MEMBERS", "NOT_FOUND", "找不到團隊", core.ErrNotFound)
+	ErrQueryTeamMembersFail = core.NewDBError(
+		"QUERY_TEAM_MEMBERS", "QUERY_FAIL", "query team members fail", core.ErrInternal)
+)
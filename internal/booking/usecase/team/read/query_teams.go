@@ -0,0 +1,60 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+// ReqQueryTeams CoachUserID 不為空時只查詢該教練負責的團隊
+type ReqQueryTeams struct {
+	CoachUserID string
+}
+
+type QueryTeamsUseCase core.ReadUseCase[ReqQueryTeams, []*entity.Team]
+
+type queryTeamsUseCase struct {
+	repo repository.TeamRepository
+}
+
+func NewQueryTeamsUseCase(repo repository.TeamRepository) QueryTeamsUseCase {
+	return &queryTeamsUseCase{repo: repo}
+}
+
+func (uc *queryTeamsUseCase) Name() string {
+	return "QueryTeams"
+}
+
+// Execute 依團隊名稱排序
+func (uc *queryTeamsUseCase) Execute(
+	ctx context.Context, req ReqQueryTeams,
+) ([]*entity.Team, core.UseCaseError) {
+	var (
+		teams []*entity.Team
+		err   repository.RepoError
+	)
+	if req.CoachUserID != "" {
+		teams, err = uc.repo.FindTeamsByCoach(ctx, req.CoachUserID)
+	} else {
+		teams, err = uc.repo.FindTeams(ctx)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*entity.Team{}, nil
+		}
+		return nil, ErrQueryTeamsFail.Wrap(err)
+	}
+	sort.SliceStable(teams, func(i, j int) bool {
+		return teams[i].Name() < teams[j].Name()
+	})
+	return teams, nil
+}
+
+var (
+	ErrQueryTeamsFail = core.NewDBError(
+		"QUERY_TEAMS", "QUERY_FAIL", "query teams fail", core.ErrInternal)
+)
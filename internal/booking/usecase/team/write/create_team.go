@@ -0,0 +1,80 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqCreateTeam struct {
+	HeadCoach     entity.User
+	Name          string
+	Assistants    []entity.User
+	LeaveApproval bool
+}
+
+type CreateTeamUseCase core.WriteUseCase[ReqCreateTeam, *entity.Team]
+
+type createTeamUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.TeamRepository
+}
+
+func NewCreateTeamUseCase(repo createTeamUseCaseRepo) CreateTeamUseCase {
+	return &createTeamUseCase{repo: repo}
+}
+
+type createTeamUseCase struct {
+	repo createTeamUseCaseRepo
+}
+
+func (uc *createTeamUseCase) Name() string {
+	return "CreateTeam"
+}
+
+func (uc *createTeamUseCase) Execute(
+	ctx context.Context, req ReqCreateTeam,
+) (*entity.Team, core.UseCaseError) {
+	team, err := entity.NewTeam(
+		entity.WithTeamID(uc.repo.GenerateID()),
+		entity.WithTeamName(req.Name),
+		entity.WithTeamHeadCoach(req.HeadCoach),
+		entity.WithTeamLeaveApproval(req.LeaveApproval),
+	)
+	if err != nil {
+		return nil, ErrTeamDomainFail.Wrap(err)
+	}
+	for _, a := range req.Assistants {
+		if err := team.AddAssistant(a); err != nil {
+			return nil, ErrTeamDomainFail.Wrap(err)
+		}
+	}
+	if saveErr := uc.repo.SaveTeam(ctx, team); saveErr != nil {
+		return nil, saveTeamError(saveErr)
+	}
+	return team, nil
+}
+
+// saveTeamError 團隊名稱有唯一索引，重複時回傳衝突
+func saveTeamError(err repository.RepoError) core.UseCaseError {
+	if errors.Is(err, repository.ErrConflict) {
+		return ErrTeamDuplicateName.Wrap(err)
+	}
+	return ErrTeamSaveFail.Wrap(err)
+}
+
+var (
+	ErrTeamDomainFail = core.NewDomainError(
+		"TEAM", "DOMAIN_ERROR", "團隊資料不正確，名稱需為 1-30 字且需指定總教練", core.ErrInvalidInput)
+	ErrTeamFindFail = core.NewDBError(
+		"TEAM", "FIND_TEAM_FAIL", "find team fail", core.ErrInternal)
+	ErrTeamNotFound = core.NewUseCaseError(
+		"TEAM", "NOT_FOUND", "找不到團隊", core.ErrNotFound)
+	ErrTeamDuplicateName = core.NewUseCaseError(
+		"TEAM", "DUPLICATE_NAME", "已有相同名稱的團隊", core.ErrConflict)
+	ErrTeamSaveFail = core.NewDBError(
+		"TEAM", "SAVE_TEAM_FAIL", "save team fail", core.ErrInternal)
+)
@@ -0,0 +1,65 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqDeleteTeam struct {
+	TeamID string
+}
+
+type DeleteTeamUseCase core.WriteUseCase[ReqDeleteTeam, *entity.Team]
+
+type deleteTeamUseCaseRepo interface {
+	repository.TeamRepository
+	repository.TrainRepository
+}
+
+func NewDeleteTeamUseCase(repo deleteTeamUseCaseRepo) DeleteTeamUseCase {
+	return &deleteTeamUseCase{repo: repo}
+}
+
+type deleteTeamUseCase struct {
+	repo deleteTeamUseCaseRepo
+}
+
+func (uc *deleteTeamUseCase) Name() string {
+	return "DeleteTeam"
+}
+
+// Execute 尚有綁定此團隊的未來場次時不可刪除，需先改為公開或刪除場次
+func (uc *deleteTeamUseCase) Execute(
+	ctx context.Context, req ReqDeleteTeam,
+) (*entity.Team, core.UseCaseError) {
+	team, ucErr := findTeam(ctx, uc.repo, req.TeamID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	trainDates, findErr := uc.repo.FindTrainDates(ctx,
+		repository.NewFilterTrainDateByTeamID(team.ID(), time.Now()))
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		return nil, ErrDeleteTeamFindTrainDateFail.Wrap(findErr)
+	}
+	if len(trainDates) > 0 {
+		return nil, ErrDeleteTeamHasTrainDates
+	}
+	if delErr := uc.repo.DeleteTeam(ctx, team); delErr != nil {
+		return nil, ErrDeleteTeamFail.Wrap(delErr)
+	}
+	return team, nil
+}
+
+var (
+	ErrDeleteTeamFindTrainDateFail = core.NewDBError(
+		"DELETE_TEAM", "FIND_TRAIN_DATE_FAIL", "find train dates fail", core.ErrInternal)
+	ErrDeleteTeamHasTrainDates = core.NewUseCaseError(
+		"DELETE_TEAM", "HAS_TRAIN_DATES", "尚有綁定此團隊的場次，請先改為公開或刪除", core.ErrConflict)
+	ErrDeleteTeamFail = core.NewDBError(
+		"DELETE_TEAM", "DELETE_FAIL", "delete team fail", core.ErrInternal)
+)
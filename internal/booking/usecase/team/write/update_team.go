@@ -0,0 +1,79 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+// ReqUpdateTeam 更新名稱與教練，Assistants 會取代原本的助理教練名單
+type ReqUpdateTeam struct {
+	HeadCoach     entity.User
+	TeamID        string
+	Name          string
+	Assistants    []entity.User
+	LeaveApproval bool
+}
+
+type UpdateTeamUseCase core.WriteUseCase[ReqUpdateTeam, *entity.Team]
+
+type updateTeamUseCaseRepo interface {
+	repository.TeamRepository
+}
+
+func NewUpdateTeamUseCase(repo updateTeamUseCaseRepo) UpdateTeamUseCase {
+	return &updateTeamUseCase{repo: repo}
+}
+
+type updateTeamUseCase struct {
+	repo updateTeamUseCaseRepo
+}
+
+func (uc *updateTeamUseCase) Name() string {
+	return "UpdateTeam"
+}
+
+func (uc *updateTeamUseCase) Execute(
+	ctx context.Context, req ReqUpdateTeam,
+) (*entity.Team, core.UseCaseError) {
+	team, ucErr := findTeam(ctx, uc.repo, req.TeamID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	if err := team.Rename(req.Name); err != nil {
+		return nil, ErrTeamDomainFail.Wrap(err)
+	}
+	if err := team.ChangeHeadCoach(req.HeadCoach); err != nil {
+		return nil, ErrTeamDomainFail.Wrap(err)
+	}
+	for _, a := range team.Assistants() {
+		team.RemoveAssistant(a.UserID())
+	}
+	for _, a := range req.Assistants {
+		if err := team.AddAssistant(a); err != nil {
+			return nil, ErrTeamDomainFail.Wrap(err)
+		}
+	}
+	team.SetLeaveApproval(req.LeaveApproval)
+	if saveErr := uc.repo.SaveTeam(ctx, team); saveErr != nil {
+		return nil, saveTeamError(saveErr)
+	}
+	return team, nil
+}
+
+func findTeam(ctx context.Context, repo repository.TeamRepository, teamID string) (*entity.Team, core.UseCaseError) {
+	if teamID == "" {
+		return nil, ErrTeamNotFound
+	}
+	team, err := repo.FindTeamByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, ErrTeamFindFail.Wrap(err)
+	}
+	return team, nil
+}
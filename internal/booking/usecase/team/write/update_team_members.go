@@ -0,0 +1,73 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqUpdateTeamMembers struct {
+	TeamID           string
+	AddStudentIDs    []string
+	RemoveStudentIDs []string
+}
+
+type UpdateTeamMembersUseCase core.WriteUseCase[ReqUpdateTeamMembers, *entity.Team]
+
+type updateTeamMembersUseCaseRepo interface {
+	repository.TeamRepository
+	repository.StudentRepository
+}
+
+func NewUpdateTeamMembersUseCase(repo updateTeamMembersUseCaseRepo) UpdateTeamMembersUseCase {
+	return &updateTeamMembersUseCase{repo: repo}
+}
+
+type updateTeamMembersUseCase struct {
+	repo updateTeamMembersUseCaseRepo
+}
+
+func (uc *updateTeamMembersUseCase) Name() string {
+	return "UpdateTeamMembers"
+}
+
+func (uc *updateTeamMembersUseCase) Execute(
+	ctx context.Context, req ReqUpdateTeamMembers,
+) (*entity.Team, core.UseCaseError) {
+	if len(req.AddStudentIDs) == 0 && len(req.RemoveStudentIDs) == 0 {
+		return nil, ErrUpdateTeamMembersInvalidInput
+	}
+	team, ucErr := findTeam(ctx, uc.repo, req.TeamID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	for _, id := range req.AddStudentIDs {
+		student, findErr := uc.repo.FindStudentByID(ctx, id)
+		if findErr != nil {
+			if errors.Is(findErr, repository.ErrNotFound) {
+				return nil, ErrUpdateTeamMembersStudentNotFound
+			}
+			return nil, ErrUpdateTeamMembersFindStudentFail.Wrap(findErr)
+		}
+		team.AddMembers(student)
+	}
+	for _, id := range req.RemoveStudentIDs {
+		team.RemoveMember(id)
+	}
+	if saveErr := uc.repo.SaveTeam(ctx, team); saveErr != nil {
+		return nil, saveTeamError(saveErr)
+	}
+	return team, nil
+}
+
+var (
+	ErrUpdateTeamMembersInvalidInput = core.NewUseCaseError(
+		"UPDATE_TEAM_MEMBERS", "INVALID_INPUT", "請選擇要加入或移除的學員", core.ErrInvalidInput)
+	ErrUpdateTeamMembersStudentNotFound = core.NewUseCaseError(
+		"UPDATE_TEAM_MEMBERS", "STUDENT_NOT_FOUND", "找不到學員", core.ErrNotFound)
+	ErrUpdateTeamMembersFindStudentFail = core.NewDBError(
+		"UPDATE_TEAM_MEMBERS", "FIND_STUDENT_FAIL", "find student fail", core.ErrInternal)
+)
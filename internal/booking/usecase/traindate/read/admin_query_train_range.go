@@ -11,6 +11,8 @@ import (
 type ReqAdminQueryTrainRange struct {
 	StartTime time.Time
 	EndTime   time.Time
+	// TeamID 只查詢某團隊的場次，空字串代表不篩選
+	TeamID string
 }
 
 type adminQueryTrainRangeUseCase struct {
@@ -30,6 +32,10 @@ func (uc *adminQueryTrainRangeUseCase) Name() string {
 func (uc *adminQueryTrainRangeUseCase) Execute(ctx context.Context, req ReqAdminQueryTrainRange) (
 	[]*entity.TrainDateHasApptState, core.UseCaseError,
 ) {
+	ctx, scopeErr := core.ScopeToTeam(ctx, req.TeamID)
+	if scopeErr != nil {
+		return nil, scopeErr
+	}
 	trainDates, err := uc.repo.QueryTrainDateHasAppointmentState(
 		ctx, repository.NewFilterTrainDataByTimeRange(req.StartTime, req.EndTime),
 	)
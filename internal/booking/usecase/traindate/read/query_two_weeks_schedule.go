@@ -128,7 +128,7 @@ func groupToWeeks(data []*entity.TrainDateHasUserApptState, start, end time.Time
 				Capacity:       td.Capacity,
 				BookedCount:    td.Capacity - td.AvailableCapacity,
 				IsFull:         td.AvailableCapacity <= 0,
-				Attendees:      transformAttendees(td.UserAppointments, td.EndDate),
+				Attendees:      transformAttendees(td),
 				EndDate:        td.EndDate,
 			})
 		}
@@ -150,17 +150,22 @@ func groupToWeeks(data []*entity.TrainDateHasUserApptState, start, end time.Time
 	return res
 }
 
-func transformAttendees(appts []entity.UserAppointment, courseEndDate time.Time) []*AttendeeVO {
+func transformAttendees(td *entity.TrainDateHasUserApptState) []*AttendeeVO {
 	res := make([]*AttendeeVO, 0)
 	now := time.Now()
-	for _, a := range appts {
+	for _, a := range td.UserAppointments {
 		status := "Booked"
 		if a.IsOnLeave {
 			status = "Leave"
 		} else if a.IsCheckedIn {
 			status = "CheckedIn"
-		} else if now.After(courseEndDate) {
+		} else if a.IsLeavePending() {
+			status = "LeavePending"
+		} else if now.After(td.EndDate) {
 			status = "Absent"
+		} else if td.IsBookedBeforeReschedule(a) {
+			// 場次改期，家長可免責取消
+			status = "Rescheduled"
 		}
 		res = append(res, &AttendeeVO{
 			Name: a.ChildName, Status: status, BookingID: a.ID, BookingTime: a.CreatedAt,
@@ -0,0 +1,75 @@
+package write
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+// ReqAssignTrainDateTeam TeamID 為空字串時改回公開場次，已預約的學員不受影響
+type ReqAssignTrainDateTeam struct {
+	TrainDateID string
+	TeamID      string
+}
+
+type AssignTrainDateTeamUseCase core.WriteUseCase[ReqAssignTrainDateTeam, *entity.TrainDate]
+
+type assignTrainDateTeamRepo interface {
+	repository.TrainRepository
+	repository.TeamRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewAssignTrainDateTeamUseCase(repo assignTrainDateTeamRepo) AssignTrainDateTeamUseCase {
+	return &assignTrainDateTeamUseCase{repo: repo}
+}
+
+type assignTrainDateTeamUseCase struct {
+	repo assignTrainDateTeamRepo
+}
+
+func (uc *assignTrainDateTeamUseCase) Name() string {
+	return "AssignTrainDateTeam"
+}
+
+func (uc *assignTrainDateTeamUseCase) Execute(
+	ctx context.Context, req ReqAssignTrainDateTeam,
+) (*entity.TrainDate, core.UseCaseError) {
+	if req.TrainDateID == "" {
+		return nil, ErrAssignTrainDateTeamInvalidInput
+	}
+	if ucErr := checkTeamExists(ctx, uc.repo, req.TeamID); ucErr != nil {
+		return nil, ucErr
+	}
+	trainDate, findErr := uc.repo.FindTrainDateByID(ctx, req.TrainDateID)
+	if findErr != nil {
+		return nil, ErrAssignTrainDateTeamFindTrainDateFail.Wrap(findErr)
+	}
+	trainDate.AssignTeam(req.TeamID)
+	// 快取由訂閱 TrainDateUpdated 的訂閱者清理
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if saveErr := uc.repo.UpdateManyTrainDates(ctx, []*entity.TrainDate{trainDate}); saveErr != nil {
+			return ErrAssignTrainDateTeamSaveFail.Wrap(saveErr)
+		}
+		return core.AddEvents(ctx, uc.repo, newTrainDateUpdatedEvent(
+			uc.repo.GenerateID(), trainDate, domain.TrainDateFieldTeam))
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	return trainDate, nil
+}
+
+var (
+	ErrAssignTrainDateTeamInvalidInput = core.NewUseCaseError(
+		"ASSIGN_TRAIN_DATE_TEAM", "INVALID_INPUT", "train date id is required", core.ErrInvalidInput)
+	ErrAssignTrainDateTeamFindTrainDateFail = core.NewDBError(
+		"ASSIGN_TRAIN_DATE_TEAM", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrAssignTrainDateTeamSaveFail = core.NewDBError(
+		"ASSIGN_TRAIN_DATE_TEAM", "SAVE_FAIL", "save train date fail", core.ErrInternal)
+)
@@ -6,21 +6,26 @@ import (
 	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/service"
 	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
 	"sort"
+	"time"
 )
 
 type batchCreateSlotUseCase struct {
-	repo     createTrainRepo
-	trainSvc service.TrainDateService // 依賴 Domain Service 介面
+	repo          createTrainRepo
+	trainSvc      service.TrainDateService // 依賴 Domain Service 介面
+	defaultPolicy entity.BookingPolicy     // 未指定預約規則時使用
 }
 
 func NewBatchCreateTrainDateUseCase(
 	repo createTrainRepo,
 	trainSvc service.TrainDateService,
+	defaultPolicy entity.BookingPolicy,
 ) core.WriteUseCase[[]ReqCreateTrainDate, []*entity.TrainDate] {
 	return &batchCreateSlotUseCase{
-		repo:     repo,
-		trainSvc: trainSvc,
+		repo:          repo,
+		trainSvc:      trainSvc,
+		defaultPolicy: defaultPolicy,
 	}
 }
 
@@ -37,6 +42,9 @@ func (uc *batchCreateSlotUseCase) Execute(
 			resultErr = ErrCreateTrainDateInvalidInput.Wrap(err)
 			return
 		}
+		if resultErr = checkTeamExists(ctx, uc.repo, r.TeamID); resultErr != nil {
+			return
+		}
 	}
 
 	if checkRequestsHasOverlap(req) {
@@ -67,7 +75,10 @@ func (uc *batchCreateSlotUseCase) Execute(
 				r.Location,
 				r.Capacity,
 				tr,
-			))
+			),
+			entity.WithTrainDateBookingPolicy(r.bookingPolicy(uc.defaultPolicy)),
+			entity.WithTrainDateTeamID(r.TeamID),
+		)
 		if err != nil {
 			resultErr = ErrCreateTrainDateNewDomainEntityFail.Wrap(err)
 			return
@@ -81,16 +92,22 @@ func (uc *batchCreateSlotUseCase) Execute(
 		resultErr = ErrCreateTrainDateCoachBusy.Wrap(err)
 		return
 	}
-	// 5. 批次存檔
-	err = uc.repo.SaveManyTrainDates(ctx, trainings)
-	if err != nil {
-		resultErr = ErrCreateTrainDateSaveToDBFail.Wrap(err)
+	// 5. 批次存檔，每個場次各一筆新增事件，快取由訂閱者清理
+	now := time.Now()
+	events := make([]event.Event, 0, len(trainings))
+	for _, t := range trainings {
+		events = append(events, newTrainDateCreatedEvent(uc.repo.GenerateID(), t, now))
+	}
+	resultErr = core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.SaveManyTrainDates(ctx, trainings); err != nil {
+			return ErrCreateTrainDateSaveToDBFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, events...)
+	})
+	if resultErr != nil {
 		return
 	}
 
-	// Invalidate train cache
-	_ = uc.repo.CleanTrainCache(ctx, "")
-
 	result = trainings
 	return
 }
@@ -0,0 +1,138 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+// ReqCancelTrainDate 教練停課 (例如雨天)，已預約的學員一併改為停課狀態
+type ReqCancelTrainDate struct {
+	TrainDateID string
+	OperatorID  string
+	Reason      string
+}
+
+type CancelTrainDateUseCase core.WriteUseCase[ReqCancelTrainDate, *entity.TrainDate]
+
+type cancelTrainDateUseCaseRepo interface {
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewCancelTrainDateUseCase(repo cancelTrainDateUseCaseRepo) CancelTrainDateUseCase {
+	return &cancelTrainDateUseCase{
+		repo: repo,
+	}
+}
+
+type cancelTrainDateUseCase struct {
+	repo cancelTrainDateUseCaseRepo
+}
+
+func (uc *cancelTrainDateUseCase) Name() string {
+	return "CancelTrainDate"
+}
+
+func (uc *cancelTrainDateUseCase) Execute(
+	ctx context.Context, req ReqCancelTrainDate,
+) (*entity.TrainDate, core.UseCaseError) {
+	trainDate, err := uc.repo.FindTrainDateByID(ctx, req.TrainDateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCancelTrainDateNotFound
+		}
+		return nil, ErrCancelTrainDateFindTrainDateFail.Wrap(err)
+	}
+	if cancelErr := trainDate.Cancel(req.Reason, req.OperatorID); cancelErr != nil {
+		return nil, ErrCancelTrainDateDomainFail.Wrap(cancelErr)
+	}
+
+	appts, err := uc.repo.FindApptsByFilter(ctx, repository.NewFilterApptByTrainID(trainDate.ID()))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCancelTrainDateFindApptFail.Wrap(err)
+	}
+	changed := make([]*entity.Appointment, 0, len(appts))
+	oldStatus := make(map[string]string, len(appts))
+	for _, appt := range appts {
+		// 誤按取消的紀錄已無名額，不需處理
+		if appt.Status() == entity.StatusCancelled || appt.IsCancelledByCoach() {
+			continue
+		}
+		oldStatus[appt.ID()] = appt.Status().String()
+		if cancelErr := appt.CancelByCoach(); cancelErr != nil {
+			return nil, ErrCancelTrainDateDomainFail.Wrap(cancelErr)
+		}
+		changed = append(changed, appt)
+	}
+
+	// 領域事件與停課狀態一併寫入，統計、課程包與快取由訂閱者處理
+	now := time.Now()
+	events := make([]event.Event, 0, len(changed)+1)
+	affected := make([]string, 0, len(changed))
+	seen := make(map[string]bool, len(changed))
+	for _, appt := range changed {
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+			BookingID:  appt.ID(),
+			UserID:     appt.User().UserID(),
+			TrainingID: appt.TrainingID(),
+			OldStatus:  oldStatus[appt.ID()],
+			NewStatus:  appt.Status().String(),
+			OccurredAt: now,
+		}))
+
+		if !seen[appt.User().UserID()] {
+			seen[appt.User().UserID()] = true
+			affected = append(affected, appt.User().UserID())
+		}
+	}
+	events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicTrainDateCancelled, domain.TrainDateCancelled{
+		TrainingID:      trainDate.ID(),
+		Reason:          trainDate.Cancellation().Reason(),
+		CancelledBy:     req.OperatorID,
+		AffectedUserIDs: affected,
+		OccurredAt:      now,
+	}))
+
+	// 先儲存場次狀態，避免停課期間仍有新預約
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if saveErr := uc.repo.UpdateManyTrainDates(ctx, []*entity.TrainDate{trainDate}); saveErr != nil {
+			return ErrCancelTrainDateSaveFail.Wrap(saveErr)
+		}
+		if len(changed) > 0 {
+			if saveErr := uc.repo.UpdateManyAppts(ctx, changed); saveErr != nil {
+				return ErrCancelTrainDateUpdateApptFail.Wrap(saveErr)
+			}
+		}
+		return core.AddEvents(ctx, uc.repo, events...)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+
+	return trainDate, nil
+}
+
+var (
+	ErrCancelTrainDateNotFound = core.NewUseCaseError(
+		"CANCEL_TRAIN_DATE", "NOT_FOUND", "找不到場次", core.ErrNotFound)
+	ErrCancelTrainDateFindTrainDateFail = core.NewDBError(
+		"CANCEL_TRAIN_DATE", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrCancelTrainDateDomainFail = core.NewDomainError(
+		"CANCEL_TRAIN_DATE", "DOMAIN_ERROR", "cancel train date fail", core.ErrInvalidInput)
+	ErrCancelTrainDateFindApptFail = core.NewDBError(
+		"CANCEL_TRAIN_DATE", "FIND_APPOINTMENT_FAIL", "find appointments fail", core.ErrInternal)
+	ErrCancelTrainDateSaveFail = core.NewDBError(
+		"CANCEL_TRAIN_DATE", "SAVE_FAIL", "save train date fail", core.ErrInternal)
+	ErrCancelTrainDateUpdateApptFail = core.NewDBError(
+		"CANCEL_TRAIN_DATE", "UPDATE_APPOINTMENT_FAIL", "update appointments fail", core.ErrInternal)
+)
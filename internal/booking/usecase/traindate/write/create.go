@@ -3,10 +3,12 @@ package write
 import (
 	"context"
 	"errors"
+	"seanAIgent/internal/booking/domain"
 	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/domain/service"
 	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
 	"time"
 )
 
@@ -19,26 +21,36 @@ var (
 		"CREATE_TRAIN_DATE", "DOMAIN_ERROR", "new domain entity failed", core.ErrInvalidInput)
 	ErrCreateTrainDateSaveToDBFail = core.NewDBError(
 		"CREATE_TRAIN_DATE", "DB_ERROR", "save to db failed", core.ErrInternal)
+	ErrCreateTrainDateTeamNotFound = core.NewUseCaseError(
+		"CREATE_TRAIN_DATE", "TEAM_NOT_FOUND", "找不到指定的團隊", core.ErrNotFound)
+	ErrCreateTrainDateFindTeamFail = core.NewDBError(
+		"CREATE_TRAIN_DATE", "FIND_TEAM_FAIL", "find team fail", core.ErrInternal)
 )
 
 type createTrainRepo interface {
 	repository.IdentityGenerator
 	repository.TrainRepository
+	repository.TeamRepository
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
 type createSlotUseCase struct {
-	repo     createTrainRepo
-	trainSvc service.TrainDateService // 依賴 Domain Service 介面
+	repo          createTrainRepo
+	trainSvc      service.TrainDateService // 依賴 Domain Service 介面
+	defaultPolicy entity.BookingPolicy     // 未指定預約規則時使用
 }
 
 // NewCreateSlotUseCase 負責組裝並回傳泛型介面
 func NewCreateTrainDateUseCase(
 	repo createTrainRepo,
 	trainSvc service.TrainDateService,
+	defaultPolicy entity.BookingPolicy,
 ) core.WriteUseCase[ReqCreateTrainDate, *entity.TrainDate] {
 	return &createSlotUseCase{
-		repo:     repo,
-		trainSvc: trainSvc,
+		repo:          repo,
+		trainSvc:      trainSvc,
+		defaultPolicy: defaultPolicy,
 	}
 }
 
@@ -53,6 +65,9 @@ func (uc *createSlotUseCase) Execute(
 		returnErr = ErrCreateTrainDateInvalidInput.Wrap(err)
 		return
 	}
+	if returnErr = checkTeamExists(ctx, uc.repo, req.TeamID); returnErr != nil {
+		return
+	}
 	// 1. 建立 Domain Entity (內部會驗證基本規則，如時間先後)
 	period, err := entity.NewTimeRange(req.StartTime, req.EndTime)
 	if err != nil {
@@ -68,7 +83,10 @@ func (uc *createSlotUseCase) Execute(
 			req.Location,
 			req.Capacity,
 			period,
-		))
+		),
+		entity.WithTrainDateBookingPolicy(req.bookingPolicy(uc.defaultPolicy)),
+		entity.WithTrainDateTeamID(req.TeamID),
+	)
 	if err != nil {
 		returnErr = ErrCreateTrainDateNewDomainEntityFail.Wrap(err)
 		return
@@ -80,16 +98,18 @@ func (uc *createSlotUseCase) Execute(
 		returnErr = ErrCreateTrainDateCoachBusy.Wrap(err)
 	}
 
-	// 3. 存檔
-	err = uc.repo.SaveTrainDate(ctx, training)
-	if err != nil {
-		returnErr = ErrCreateTrainDateSaveToDBFail.Wrap(err)
+	// 3. 存檔，新增事件一併寫入，快取由訂閱者清理
+	txErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.SaveTrainDate(ctx, training); err != nil {
+			return ErrCreateTrainDateSaveToDBFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, newTrainDateCreatedEvent(uc.repo.GenerateID(), training, time.Now()))
+	})
+	if txErr != nil {
+		returnErr = txErr
 		return
 	}
 
-	// Invalidate train cache
-	_ = uc.repo.CleanTrainCache(ctx, "")
-
 	result = training
 	return
 }
@@ -101,6 +121,44 @@ type ReqCreateTrainDate struct {
 	CoachID   string
 	Location  string
 	Capacity  int
+	// BookingPolicy 場次預約規則，nil 代表使用系統預設
+	BookingPolicy *entity.BookingPolicy
+	// TeamID 綁定團隊後只開放成員預約，空字串代表公開場次
+	TeamID string
+}
+
+func newTrainDateCreatedEvent(id string, t *entity.TrainDate, now time.Time) event.Event {
+	return event.NewTypedEvent(id, domain.TopicTrainDateCreated, domain.TrainDateCreated{
+		TrainingID: t.ID(),
+		CoachID:    t.UserID(),
+		Location:   t.Location(),
+		Capacity:   t.MaxCapacity(),
+		StartTime:  t.Period().Start(),
+		EndTime:    t.Period().End(),
+		TeamID:     t.TeamID(),
+		OccurredAt: now,
+	})
+}
+
+// checkTeamExists 場次綁定的團隊需存在
+func checkTeamExists(ctx context.Context, repo repository.TeamRepository, teamID string) core.UseCaseError {
+	if teamID == "" {
+		return nil
+	}
+	if _, err := repo.FindTeamByID(ctx, teamID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrCreateTrainDateTeamNotFound
+		}
+		return ErrCreateTrainDateFindTeamFail.Wrap(err)
+	}
+	return nil
+}
+
+func (r *ReqCreateTrainDate) bookingPolicy(defaultPolicy entity.BookingPolicy) entity.BookingPolicy {
+	if r.BookingPolicy != nil && !r.BookingPolicy.IsZero() {
+		return *r.BookingPolicy
+	}
+	return defaultPolicy
 }
 
 func (r *ReqCreateTrainDate) Validate() error {
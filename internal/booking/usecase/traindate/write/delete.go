@@ -2,9 +2,14 @@ package write
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
 	"seanAIgent/internal/booking/domain/entity"
 	"seanAIgent/internal/booking/domain/repository"
 	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
 )
 
 type ReqDeleteTrainDate struct {
@@ -14,6 +19,10 @@ type ReqDeleteTrainDate struct {
 
 type deleteTrainDateUseCaseRepo interface {
 	repository.TrainRepository
+	repository.TrainingSeriesRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
 }
 
 func NewDeleteTrainDateUseCase(repo deleteTrainDateUseCaseRepo) core.WriteUseCase[
@@ -44,22 +53,60 @@ func (uc *deleteTrainDateUseCase) Execute(
 		returnErr = ErrDeleteTrainDateDeleteFail.Wrap(err)
 		return
 	}
-	err = uc.repo.DeleteTrainingDate(ctx, trainDate)
-	if err != nil {
-		returnErr = ErrDeleteTrainDateDeleteFail.Wrap(err)
+	// 系列產生的場次需記為例外日期，避免排程再次產生
+	if trainDate.SeriesID() != "" {
+		err = excludeFromSeries(ctx, uc.repo, trainDate.SeriesID(), trainDate.Period().Start())
+		if err != nil {
+			returnErr = ErrDeleteTrainDateUpdateSeriesFail.Wrap(err)
+			return
+		}
+	}
+	// 快取由訂閱 TrainDateDeleted 的訂閱者清理
+	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicTrainDateDeleted, domain.TrainDateDeleted{
+		TrainingID: trainDate.ID(),
+		CoachID:    trainDate.UserID(),
+		Location:   trainDate.Location(),
+		StartTime:  trainDate.Period().Start(),
+		EndTime:    trainDate.Period().End(),
+		DeletedBy:  req.UserID,
+		OccurredAt: time.Now(),
+	})
+	returnErr = core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.DeleteTrainingDate(ctx, trainDate); err != nil {
+			return ErrDeleteTrainDateDeleteFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if returnErr != nil {
 		return
 	}
 
-	// Invalidate train cache
-	_ = uc.repo.CleanTrainCache(ctx, "")
-
 	result = trainDate
 	return
 }
 
+// excludeFromSeries 將系列在 start 當天的場次設為例外日期，系列已刪除時略過
+func excludeFromSeries(
+	ctx context.Context, repo repository.TrainingSeriesRepository, seriesID string, start time.Time,
+) error {
+	series, err := repo.FindTrainingSeriesByID(ctx, seriesID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := series.AddException(series.DateOf(start)); err != nil {
+		return err
+	}
+	return repo.SaveTrainingSeries(ctx, series)
+}
+
 var (
 	ErrDeleteTrainDateFindTrainDateFail = core.NewDBError(
 		"DELETE_TRAIN_DATE", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
 	ErrDeleteTrainDateDeleteFail = core.NewDomainError(
 		"DELETE_TRAIN_DATE", "DELETE_TRAIN_DATE_FAIL", "delete train date fail", core.ErrInternal)
+	ErrDeleteTrainDateUpdateSeriesFail = core.NewDBError(
+		"DELETE_TRAIN_DATE", "UPDATE_SERIES_FAIL", "update training series fail", core.ErrInternal)
 )
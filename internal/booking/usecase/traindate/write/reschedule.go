@@ -0,0 +1,188 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+	"seanAIgent/internal/util/timeutil"
+)
+
+// ReqRescheduleTrainDate 日期與時間以場次的時區解讀，Location 留空代表不改地點
+type ReqRescheduleTrainDate struct {
+	TrainDateID string
+	OperatorID  string
+	Location    string
+	Date        string // YYYY-MM-DD
+	StartTime   string // HH:MM
+	EndTime     string // HH:MM
+}
+
+func (r *ReqRescheduleTrainDate) Validate() error {
+	if r.TrainDateID == "" {
+		return errors.New("train date id is empty")
+	}
+	if r.Date == "" || r.StartTime == "" || r.EndTime == "" {
+		return errors.New("date and time are required")
+	}
+	return nil
+}
+
+type RescheduleTrainDateUseCase core.WriteUseCase[ReqRescheduleTrainDate, *entity.TrainDate]
+
+type rescheduleTrainDateUseCaseRepo interface {
+	repository.TrainRepository
+	repository.TrainingSeriesRepository
+	repository.AppointmentRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewRescheduleTrainDateUseCase(
+	repo rescheduleTrainDateUseCaseRepo, trainSvc service.TrainDateService,
+) RescheduleTrainDateUseCase {
+	return &rescheduleTrainDateUseCase{
+		repo:     repo,
+		trainSvc: trainSvc,
+	}
+}
+
+type rescheduleTrainDateUseCase struct {
+	repo     rescheduleTrainDateUseCaseRepo
+	trainSvc service.TrainDateService
+}
+
+func (uc *rescheduleTrainDateUseCase) Name() string {
+	return "RescheduleTrainDate"
+}
+
+func (uc *rescheduleTrainDateUseCase) Execute(
+	ctx context.Context, req ReqRescheduleTrainDate,
+) (*entity.TrainDate, core.UseCaseError) {
+	if err := req.Validate(); err != nil {
+		return nil, ErrRescheduleTrainDateInvalidInput.Wrap(err)
+	}
+	trainDate, findErr := uc.repo.FindTrainDateByID(ctx, req.TrainDateID)
+	if findErr != nil {
+		if errors.Is(findErr, repository.ErrNotFound) {
+			return nil, ErrRescheduleTrainDateNotFound
+		}
+		return nil, ErrRescheduleTrainDateFindTrainDateFail.Wrap(findErr)
+	}
+	period, err := parsePeriod(req.Date, req.StartTime, req.EndTime, trainDate.Timezone())
+	if err != nil {
+		return nil, ErrRescheduleTrainDateInvalidInput.Wrap(err)
+	}
+	if err := uc.trainSvc.CheckOverlapExcept(ctx, trainDate.UserID(), period, trainDate.ID()); err != nil {
+		if errors.Is(err, service.ErrTrainerTimeOverlap) {
+			return nil, ErrRescheduleTrainDateCoachBusy.Wrap(err)
+		}
+		return nil, ErrRescheduleTrainDateFindTrainDateFail.Wrap(err)
+	}
+
+	previous := trainDate.Period()
+	previousLocation := trainDate.Location()
+	if domainErr := trainDate.Reschedule(req.Location, period, req.OperatorID); domainErr != nil {
+		return nil, ErrRescheduleTrainDateDomainFail.Wrap(domainErr)
+	}
+
+	appts, findErr := uc.repo.FindApptsByFilter(ctx, repository.NewFilterApptByTrainID(trainDate.ID()))
+	if findErr != nil && !errors.Is(findErr, repository.ErrNotFound) {
+		// 查詢失敗時仍改期，通知排程會依場次找到預約
+		appts = nil
+	}
+	affected := make([]string, 0, len(appts))
+	seen := make(map[string]bool, len(appts))
+	for _, appt := range appts {
+		if appt.Status() == entity.StatusCancelled || seen[appt.User().UserID()] {
+			continue
+		}
+		seen[appt.User().UserID()] = true
+		affected = append(affected, appt.User().UserID())
+	}
+
+	// 領域事件與場次一併寫入，快取由訂閱者清理
+	now := time.Now()
+	events := []event.Event{event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicTrainDateRescheduled, domain.TrainDateRescheduled{
+		TrainingID:       trainDate.ID(),
+		PreviousStart:    previous.Start(),
+		PreviousEnd:      previous.End(),
+		PreviousLocation: previousLocation,
+		NewStart:         period.Start(),
+		NewEnd:           period.End(),
+		NewLocation:      trainDate.Location(),
+		RescheduledBy:    req.OperatorID,
+		AffectedUserIDs:  affected,
+		OccurredAt:       now,
+	})}
+
+	// 跨月改期時重新計算兩個月份的出席統計
+	if previous.Start().Year() != period.Start().Year() || previous.Start().Month() != period.Start().Month() {
+		for _, userID := range affected {
+			for _, t := range []time.Time{previous.Start(), period.Start()} {
+				events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicUserStatsRefreshRequested, domain.UserStatsRefreshRequested{
+					UserID:     userID,
+					Year:       t.Year(),
+					Month:      int(t.Month()),
+					Reason:     "TrainDateRescheduled",
+					OccurredAt: now,
+				}))
+			}
+		}
+	}
+
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		// 系列產生的場次改期後脫離系列，原日期設為例外避免排程再次產生
+		if trainDate.SeriesID() != "" {
+			if err := excludeFromSeries(ctx, uc.repo, trainDate.SeriesID(), previous.Start()); err != nil {
+				return ErrRescheduleTrainDateUpdateSeriesFail.Wrap(err)
+			}
+			trainDate.DetachFromSeries()
+		}
+		if err := uc.repo.UpdateManyTrainDates(ctx, []*entity.TrainDate{trainDate}); err != nil {
+			return ErrRescheduleTrainDateSaveFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, events...)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+
+	return trainDate, nil
+}
+
+func parsePeriod(date, startTime, endTime, timezone string) (entity.TimeRange, error) {
+	start, err := timeutil.ParseDateTime(date, startTime, timezone)
+	if err != nil {
+		return entity.TimeRange{}, err
+	}
+	end, err := timeutil.ParseDateTime(date, endTime, timezone)
+	if err != nil {
+		return entity.TimeRange{}, err
+	}
+	return entity.NewTimeRange(start, end)
+}
+
+var (
+	ErrRescheduleTrainDateInvalidInput = core.NewUseCaseError(
+		"RESCHEDULE_TRAIN_DATE", "INVALID_INPUT", "請填寫正確的日期與時間", core.ErrInvalidInput)
+	ErrRescheduleTrainDateNotFound = core.NewUseCaseError(
+		"RESCHEDULE_TRAIN_DATE", "NOT_FOUND", "找不到場次", core.ErrNotFound)
+	ErrRescheduleTrainDateFindTrainDateFail = core.NewDBError(
+		"RESCHEDULE_TRAIN_DATE", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrRescheduleTrainDateCoachBusy = core.NewUseCaseError(
+		"RESCHEDULE_TRAIN_DATE", "COACH_BUSY", "教練在此時段已有其他課程", core.ErrConflict)
+	ErrRescheduleTrainDateDomainFail = core.NewDomainError(
+		"RESCHEDULE_TRAIN_DATE", "DOMAIN_ERROR", "reschedule train date fail", core.ErrInvalidInput)
+	ErrRescheduleTrainDateUpdateSeriesFail = core.NewDBError(
+		"RESCHEDULE_TRAIN_DATE", "UPDATE_SERIES_FAIL", "update training series fail", core.ErrInternal)
+	ErrRescheduleTrainDateSaveFail = core.NewDBError(
+		"RESCHEDULE_TRAIN_DATE", "SAVE_FAIL", "save train date fail", core.ErrInternal)
+)
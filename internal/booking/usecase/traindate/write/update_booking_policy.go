@@ -0,0 +1,100 @@
+package write
+
+import (
+	"context"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+// ReqUpdateTrainDateBookingPolicy 各時間以分鐘為單位，與管理頁面與 MCP 工具的輸入一致
+type ReqUpdateTrainDateBookingPolicy struct {
+	TrainDateID            string
+	CancelWindowMinutes    int
+	LeaveCutoffMinutes     int
+	CheckInOpenMinutes     int
+	AttendanceAmendMinutes int
+}
+
+func (r ReqUpdateTrainDateBookingPolicy) policy() (entity.BookingPolicy, error) {
+	return entity.NewBookingPolicy(
+		time.Duration(r.CancelWindowMinutes)*time.Minute,
+		time.Duration(r.LeaveCutoffMinutes)*time.Minute,
+		time.Duration(r.CheckInOpenMinutes)*time.Minute,
+		time.Duration(r.AttendanceAmendMinutes)*time.Minute,
+	)
+}
+
+type UpdateTrainDateBookingPolicyUseCase core.WriteUseCase[ReqUpdateTrainDateBookingPolicy, *entity.TrainDate]
+
+type updateTrainDateBookingPolicyRepo interface {
+	repository.TrainRepository
+	repository.IdentityGenerator
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewUpdateTrainDateBookingPolicyUseCase(
+	repo updateTrainDateBookingPolicyRepo,
+) UpdateTrainDateBookingPolicyUseCase {
+	return &updateTrainDateBookingPolicyUseCase{repo: repo}
+}
+
+type updateTrainDateBookingPolicyUseCase struct {
+	repo updateTrainDateBookingPolicyRepo
+}
+
+func (uc *updateTrainDateBookingPolicyUseCase) Name() string {
+	return "UpdateTrainDateBookingPolicy"
+}
+
+func (uc *updateTrainDateBookingPolicyUseCase) Execute(
+	ctx context.Context, req ReqUpdateTrainDateBookingPolicy,
+) (*entity.TrainDate, core.UseCaseError) {
+	if req.TrainDateID == "" {
+		return nil, ErrUpdateBookingPolicyInvalidInput
+	}
+	policy, err := req.policy()
+	if err != nil {
+		return nil, ErrUpdateBookingPolicyInvalidInput.Wrap(err)
+	}
+	trainDate, findErr := uc.repo.FindTrainDateByID(ctx, req.TrainDateID)
+	if findErr != nil {
+		return nil, ErrUpdateBookingPolicyFindTrainDateFail.Wrap(findErr)
+	}
+	trainDate.UpdateBookingPolicy(policy)
+	// 快取由訂閱 TrainDateUpdated 的訂閱者清理
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if saveErr := uc.repo.UpdateManyTrainDates(ctx, []*entity.TrainDate{trainDate}); saveErr != nil {
+			return ErrUpdateBookingPolicySaveFail.Wrap(saveErr)
+		}
+		return core.AddEvents(ctx, uc.repo, newTrainDateUpdatedEvent(
+			uc.repo.GenerateID(), trainDate, domain.TrainDateFieldBookingPolicy))
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	return trainDate, nil
+}
+
+func newTrainDateUpdatedEvent(id string, t *entity.TrainDate, fields ...string) event.Event {
+	return event.NewTypedEvent(id, domain.TopicTrainDateUpdated, domain.TrainDateUpdated{
+		TrainingID: t.ID(),
+		Fields:     fields,
+		TeamID:     t.TeamID(),
+		OccurredAt: time.Now(),
+	})
+}
+
+var (
+	ErrUpdateBookingPolicyInvalidInput = core.NewUseCaseError(
+		"UPDATE_BOOKING_POLICY", "INVALID_INPUT", "預約規則設定不正確", core.ErrInvalidInput)
+	ErrUpdateBookingPolicyFindTrainDateFail = core.NewDBError(
+		"UPDATE_BOOKING_POLICY", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrUpdateBookingPolicySaveFail = core.NewDBError(
+		"UPDATE_BOOKING_POLICY", "SAVE_FAIL", "save train date fail", core.ErrInternal)
+)
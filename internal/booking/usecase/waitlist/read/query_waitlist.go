@@ -0,0 +1,53 @@
+package read
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqQueryWaitlist struct {
+	TrainDateID string
+}
+
+type QueryWaitlistUseCase core.ReadUseCase[ReqQueryWaitlist, *entity.Waitlist]
+
+type queryWaitlistUseCase struct {
+	repo repository.WaitlistRepository
+}
+
+func NewQueryWaitlistUseCase(repo repository.WaitlistRepository) QueryWaitlistUseCase {
+	return &queryWaitlistUseCase{repo: repo}
+}
+
+func (uc *queryWaitlistUseCase) Name() string {
+	return "QueryWaitlist"
+}
+
+// Execute 查詢場次的候補名單，尚無人候補時回傳空名單
+func (uc *queryWaitlistUseCase) Execute(
+	ctx context.Context, req ReqQueryWaitlist,
+) (*entity.Waitlist, core.UseCaseError) {
+	wl, err := uc.repo.FindWaitlistByTrainID(ctx, req.TrainDateID)
+	if err == nil {
+		return wl, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrQueryWaitlistFail.Wrap(err)
+	}
+	emptyWl, newErr := entity.NewWaitlist(entity.WithWaitlistTrainingID(req.TrainDateID))
+	if newErr != nil {
+		return nil, ErrQueryWaitlistInvalidInput.Wrap(newErr)
+	}
+	return emptyWl, nil
+}
+
+var (
+	ErrQueryWaitlistFail = core.NewDBError(
+		"QUERY_WAITLIST", "QUERY_FAIL", "query waitlist fail", core.ErrInternal)
+	ErrQueryWaitlistInvalidInput = core.NewUseCaseError(
+		"QUERY_WAITLIST", "INVALID_INPUT", "train date id is required", core.ErrInvalidInput)
+)
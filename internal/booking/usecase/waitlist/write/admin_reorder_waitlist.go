@@ -0,0 +1,74 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqAdminReorderWaitlist struct {
+	TrainDateID string
+	EntryIDs    []string
+}
+
+type AdminReorderWaitlistUseCase core.WriteUseCase[ReqAdminReorderWaitlist, *entity.Waitlist]
+
+type adminReorderWaitlistUseCaseRepo interface {
+	repository.WaitlistRepository
+}
+
+func NewAdminReorderWaitlistUseCase(repo adminReorderWaitlistUseCaseRepo) AdminReorderWaitlistUseCase {
+	return &adminReorderWaitlistUseCase{
+		repo: repo,
+	}
+}
+
+type adminReorderWaitlistUseCase struct {
+	repo adminReorderWaitlistUseCaseRepo
+}
+
+func (uc *adminReorderWaitlistUseCase) Name() string {
+	return "AdminReorderWaitlist"
+}
+
+func (uc *adminReorderWaitlistUseCase) Execute(
+	ctx context.Context, req ReqAdminReorderWaitlist,
+) (*entity.Waitlist, core.UseCaseError) {
+	wl, err := uc.repo.FindWaitlistByTrainID(ctx, req.TrainDateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAdminReorderWaitlistNotFound
+		}
+		return nil, ErrAdminReorderWaitlistFindFail.Wrap(err)
+	}
+
+	// 名單需與目前候補中的學員完全一致，避免覆蓋期間新加入或已遞補的學員
+	if err := wl.Reorder(req.EntryIDs); err != nil {
+		return nil, ErrAdminReorderWaitlistMismatch.Wrap(err)
+	}
+
+	err = uc.repo.SaveWaitlist(ctx, wl)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrAdminReorderWaitlistConflict.Wrap(err)
+		}
+		return nil, ErrAdminReorderWaitlistSaveFail.Wrap(err)
+	}
+	return wl, nil
+}
+
+var (
+	ErrAdminReorderWaitlistNotFound = core.NewDBError(
+		"ADMIN_REORDER_WAITLIST", "WAITLIST_NOT_FOUND", "waitlist not found", core.ErrNotFound)
+	ErrAdminReorderWaitlistFindFail = core.NewDBError(
+		"ADMIN_REORDER_WAITLIST", "FIND_WAITLIST_FAIL", "find waitlist fail", core.ErrInternal)
+	ErrAdminReorderWaitlistMismatch = core.NewDomainError(
+		"ADMIN_REORDER_WAITLIST", "MISMATCH", "候補名單已變動，請重新整理後再調整", core.ErrConflict)
+	ErrAdminReorderWaitlistConflict = core.NewDBError(
+		"ADMIN_REORDER_WAITLIST", "CONFLICT", "候補名單已被更新，請重新整理後再調整", core.ErrConflict)
+	ErrAdminReorderWaitlistSaveFail = core.NewDBError(
+		"ADMIN_REORDER_WAITLIST", "SAVE_WAITLIST_FAIL", "save waitlist fail", core.ErrInternal)
+)
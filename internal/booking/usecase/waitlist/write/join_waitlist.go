@@ -0,0 +1,182 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqJoinWaitlist struct {
+	TrainDateID string
+	User        entity.User
+	ChildNames  []string
+}
+
+type JoinWaitlistUseCase core.WriteUseCase[ReqJoinWaitlist, []*entity.WaitlistEntry]
+
+type joinWaitlistUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.TrainRepository
+	repository.WaitlistRepository
+	repository.StudentRepository
+	repository.TeamRepository
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewJoinWaitlistUseCase(repo joinWaitlistUseCaseRepo) JoinWaitlistUseCase {
+	return &joinWaitlistUseCase{
+		repo: repo,
+	}
+}
+
+type joinWaitlistUseCase struct {
+	repo joinWaitlistUseCaseRepo
+}
+
+func (uc *joinWaitlistUseCase) Name() string {
+	return "JoinWaitlist"
+}
+
+func (uc *joinWaitlistUseCase) Execute(
+	ctx context.Context, req ReqJoinWaitlist,
+) ([]*entity.WaitlistEntry, core.UseCaseError) {
+	if len(req.ChildNames) == 0 {
+		return nil, ErrJoinWaitlistNoChild
+	}
+	trainDate, err := uc.repo.FindTrainDateByID(ctx, req.TrainDateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJoinWaitlistTrainDateNotFound
+		}
+		return nil, ErrJoinWaitlistFindTrainDateFail.Wrap(err)
+	}
+	if trainDate.IsCancelled() {
+		return nil, ErrJoinWaitlistTrainingCancelled
+	}
+	if time.Now().After(trainDate.Period().Start()) {
+		return nil, ErrJoinWaitlistTrainingStarted
+	}
+	// 尚有名額時應直接預約
+	if !trainDate.IsFull() {
+		return nil, ErrJoinWaitlistHasAvailableSpot
+	}
+	if ucErr := uc.checkTeamMembers(ctx, trainDate, req); ucErr != nil {
+		return nil, ucErr
+	}
+
+	wl, err := uc.repo.FindWaitlistByTrainID(ctx, req.TrainDateID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJoinWaitlistFindWaitlistFail.Wrap(err)
+		}
+		var newErr error
+		wl, newErr = entity.NewWaitlist(entity.WithWaitlistTrainingID(req.TrainDateID))
+		if newErr != nil {
+			return nil, ErrJoinWaitlistDomainFail.Wrap(newErr)
+		}
+	}
+
+	entries := make([]*entity.WaitlistEntry, 0, len(req.ChildNames))
+	for _, childName := range req.ChildNames {
+		entry, err := wl.Join(uc.repo.GenerateID(), req.User, childName)
+		if err != nil {
+			if errors.Is(err, entity.ErrWaitlistAlreadyJoined) {
+				return nil, ErrJoinWaitlistAlreadyJoined
+			}
+			return nil, ErrJoinWaitlistDomainFail.Wrap(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	// 領域事件隨名單一併寫入，若加入期間剛好有名額釋出，由訂閱者立即遞補
+	entryIDs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		entryIDs = append(entryIDs, e.ID())
+	}
+	evt := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicWaitlistJoined, domain.WaitlistJoined{
+		TrainingID: req.TrainDateID,
+		UserID:     req.User.UserID(),
+		EntryIDs:   entryIDs,
+		OccurredAt: time.Now(),
+	})
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		if err := uc.repo.SaveWaitlist(ctx, wl); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				return ErrJoinWaitlistConflict.Wrap(err)
+			}
+			return ErrJoinWaitlistSaveFail.Wrap(err)
+		}
+		return core.AddEvents(ctx, uc.repo, evt)
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+
+	return entries, nil
+}
+
+// checkTeamMembers 團隊場次只有成員可以候補，候補以姓名登記，需對應到家長已建立的學員
+func (uc *joinWaitlistUseCase) checkTeamMembers(
+	ctx context.Context, trainDate *entity.TrainDate, req ReqJoinWaitlist,
+) core.UseCaseError {
+	if trainDate.TeamID() == "" {
+		return nil
+	}
+	team, err := uc.repo.FindTeamByID(ctx, trainDate.TeamID())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrJoinWaitlistTeamMemberOnly.Wrap(err)
+		}
+		return ErrJoinWaitlistFindTeamFail.Wrap(err)
+	}
+	owned, err := uc.repo.FindStudentsByUserID(ctx, req.User.UserID())
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return ErrJoinWaitlistFindTeamFail.Wrap(err)
+	}
+	for _, name := range req.ChildNames {
+		student := entity.FindStudentByName(owned, name)
+		if student == nil {
+			return ErrJoinWaitlistTeamMemberOnly
+		}
+		if err := team.CheckMembers(student); err != nil {
+			return ErrJoinWaitlistTeamMemberOnly.Wrap(err)
+		}
+	}
+	return nil
+}
+
+var (
+	ErrJoinWaitlistNoChild = core.NewUseCaseError(
+		"JOIN_WAITLIST", "NO_CHILD", "請選擇候補的學員", core.ErrInvalidInput)
+	ErrJoinWaitlistTrainDateNotFound = core.NewDBError(
+		"JOIN_WAITLIST", "TRAIN_DATE_NOT_FOUND", "train date not found", core.ErrNotFound)
+	ErrJoinWaitlistFindTrainDateFail = core.NewDBError(
+		"JOIN_WAITLIST", "FIND_TRAIN_DATE_FAIL", "find train date fail", core.ErrInternal)
+	ErrJoinWaitlistTrainingStarted = core.NewUseCaseError(
+		"JOIN_WAITLIST", "TRAINING_STARTED", "課程已開始，無法候補", core.ErrConflict)
+	ErrJoinWaitlistHasAvailableSpot = core.NewUseCaseError(
+		"JOIN_WAITLIST", "HAS_AVAILABLE_SPOT", "課程尚有名額，請直接預約", core.ErrConflict)
+	ErrJoinWaitlistAlreadyJoined = core.NewUseCaseError(
+		"JOIN_WAITLIST", "ALREADY_JOINED", "學員已在候補名單中", core.ErrConflict)
+	ErrJoinWaitlistFindWaitlistFail = core.NewDBError(
+		"JOIN_WAITLIST", "FIND_WAITLIST_FAIL", "find waitlist fail", core.ErrInternal)
+	ErrJoinWaitlistDomainFail = core.NewDomainError(
+		"JOIN_WAITLIST", "DOMAIN_ERROR", "join waitlist fail", core.ErrInvalidInput)
+	ErrJoinWaitlistConflict = core.NewDBError(
+		"JOIN_WAITLIST", "CONFLICT", "候補名單已被更新，請重新操作", core.ErrConflict)
+	ErrJoinWaitlistSaveFail = core.NewDBError(
+		"JOIN_WAITLIST", "SAVE_WAITLIST_FAIL", "save waitlist fail", core.ErrInternal)
+	ErrJoinWaitlistFindTeamFail = core.NewDBError(
+		"JOIN_WAITLIST", "FIND_TEAM_FAIL", "find team fail", core.ErrInternal)
+	ErrJoinWaitlistTeamMemberOnly = core.NewUseCaseError(
+		"JOIN_WAITLIST", "TEAM_MEMBER_ONLY", "此場次僅開放團隊成員候補", core.ErrForbidden)
+	ErrJoinWaitlistTrainingCancelled = core.NewUseCaseError(
+		"JOIN_WAITLIST", "TRAINING_CANCELLED", "此場次已停課", core.ErrConflict)
+)
@@ -0,0 +1,83 @@
+package write
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqLeaveWaitlist struct {
+	TrainDateID string
+	EntryID     string
+	UserID      string
+}
+
+type LeaveWaitlistUseCase core.WriteUseCase[ReqLeaveWaitlist, *entity.Waitlist]
+
+type leaveWaitlistUseCaseRepo interface {
+	repository.WaitlistRepository
+}
+
+func NewLeaveWaitlistUseCase(repo leaveWaitlistUseCaseRepo) LeaveWaitlistUseCase {
+	return &leaveWaitlistUseCase{
+		repo: repo,
+	}
+}
+
+type leaveWaitlistUseCase struct {
+	repo leaveWaitlistUseCaseRepo
+}
+
+func (uc *leaveWaitlistUseCase) Name() string {
+	return "LeaveWaitlist"
+}
+
+func (uc *leaveWaitlistUseCase) Execute(
+	ctx context.Context, req ReqLeaveWaitlist,
+) (*entity.Waitlist, core.UseCaseError) {
+	wl, err := uc.repo.FindWaitlistByTrainID(ctx, req.TrainDateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrLeaveWaitlistEntryNotFound
+		}
+		return nil, ErrLeaveWaitlistFindWaitlistFail.Wrap(err)
+	}
+
+	if err := wl.Leave(req.EntryID, req.UserID); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrWaitlistEntryNotFound):
+			return nil, ErrLeaveWaitlistEntryNotFound
+		case errors.Is(err, entity.ErrWaitlistEntryNotBelongToUser):
+			return nil, ErrLeaveWaitlistPermissionDenied
+		default:
+			return nil, ErrLeaveWaitlistDomainFail.Wrap(err)
+		}
+	}
+
+	err = uc.repo.SaveWaitlist(ctx, wl)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrLeaveWaitlistConflict.Wrap(err)
+		}
+		return nil, ErrLeaveWaitlistSaveFail.Wrap(err)
+	}
+	return wl, nil
+}
+
+var (
+	ErrLeaveWaitlistEntryNotFound = core.NewDBError(
+		"LEAVE_WAITLIST", "ENTRY_NOT_FOUND", "waitlist entry not found", core.ErrNotFound)
+	ErrLeaveWaitlistFindWaitlistFail = core.NewDBError(
+		"LEAVE_WAITLIST", "FIND_WAITLIST_FAIL", "find waitlist fail", core.ErrInternal)
+	ErrLeaveWaitlistPermissionDenied = core.NewUseCaseError(
+		"LEAVE_WAITLIST", "PERMISSION_DENIED", "permission denied", core.ErrPermissionDenied)
+	ErrLeaveWaitlistDomainFail = core.NewDomainError(
+		"LEAVE_WAITLIST", "DOMAIN_ERROR", "已遞補的候補無法退出，請改為取消預約", core.ErrConflict)
+	ErrLeaveWaitlistConflict = core.NewDBError(
+		"LEAVE_WAITLIST", "CONFLICT", "候補名單已被更新，請重新操作", core.ErrConflict)
+	ErrLeaveWaitlistSaveFail = core.NewDBError(
+		"LEAVE_WAITLIST", "SAVE_WAITLIST_FAIL", "save waitlist fail", core.ErrInternal)
+)
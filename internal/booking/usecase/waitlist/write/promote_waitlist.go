@@ -0,0 +1,206 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqPromoteWaitlist struct {
+	TrainDateID string
+}
+
+type PromoteWaitlistUseCase core.WriteUseCase[ReqPromoteWaitlist, []*entity.Appointment]
+
+type promoteWaitlistUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.WaitlistRepository
+	repository.StudentRepository
+	repository.UnitOfWork
+	repository.EventOutbox
+}
+
+func NewPromoteWaitlistUseCase(repo promoteWaitlistUseCaseRepo) PromoteWaitlistUseCase {
+	return &promoteWaitlistUseCase{
+		repo: repo,
+	}
+}
+
+type promoteWaitlistUseCase struct {
+	repo promoteWaitlistUseCaseRepo
+}
+
+func (uc *promoteWaitlistUseCase) Name() string {
+	return "PromoteWaitlist"
+}
+
+// Execute 依候補順序將學員遞補為正式預約，直到名額用完或名單清空
+func (uc *promoteWaitlistUseCase) Execute(
+	ctx context.Context, req ReqPromoteWaitlist,
+) ([]*entity.Appointment, core.UseCaseError) {
+	wl, err := uc.repo.FindWaitlistByTrainID(ctx, req.TrainDateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, ErrPromoteWaitlistFindWaitlistFail.Wrap(err)
+	}
+	if len(wl.Waiting()) == 0 {
+		return nil, nil
+	}
+
+	trainDate, err := uc.repo.FindTrainDateByID(ctx, req.TrainDateID)
+	if err != nil {
+		return nil, ErrPromoteWaitlistTrainDateNotFound.Wrap(err)
+	}
+	// 課程開始後或停課不再遞補
+	if trainDate.IsCancelled() || time.Now().After(trainDate.Period().Start()) {
+		return nil, nil
+	}
+
+	var appointments []*entity.Appointment
+	ucErr := core.WithinTx(ctx, uc.repo, func(ctx context.Context) core.UseCaseError {
+		var promoteErr core.UseCaseError
+		appointments, promoteErr = uc.promote(ctx, wl, req.TrainDateID)
+		return promoteErr
+	})
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	if len(appointments) == 0 {
+		return nil, nil
+	}
+	return appointments, nil
+}
+
+// promote 逐一扣除名額並建立預約，與名單的寫入在同一個交易內
+func (uc *promoteWaitlistUseCase) promote(
+	ctx context.Context, wl *entity.Waitlist, trainDateID string,
+) ([]*entity.Appointment, core.UseCaseError) {
+	// 1. 逐一扣除名額並在記憶體中標記遞補
+	appointments := make([]*entity.Appointment, 0)
+	entryIDs := make([]string, 0)
+	for {
+		entry, ok := wl.Next()
+		if !ok {
+			break
+		}
+		if err := uc.repo.DeductCapacity(ctx, trainDateID, 1); err != nil {
+			// 名額已滿時沒有符合條件的場次，其他錯誤需回報讓訂閱者重試
+			if errors.Is(err, repository.ErrNotFound) {
+				break
+			}
+			return nil, ErrPromoteWaitlistDeductCapacityFail.Wrap(err)
+		}
+		studentID, ucErr := uc.findStudentID(ctx, entry.User().UserID(), entry.ChildName())
+		if ucErr != nil {
+			return nil, ucErr
+		}
+		appt, err := entity.NewAppointment(
+			entity.WithCreateAppt(
+				uc.repo.GenerateID(), trainDateID, entry.User(), entry.ChildName(),
+			),
+			entity.WithApptStudentID(studentID),
+		)
+		if err == nil {
+			err = wl.Promote(entry.ID(), appt.ID())
+		}
+		if err != nil {
+			return nil, ErrPromoteWaitlistDomainFail.Wrap(err)
+		}
+		appointments = append(appointments, appt)
+		entryIDs = append(entryIDs, entry.ID())
+	}
+	if len(appointments) == 0 {
+		return nil, nil
+	}
+
+	// 2. 先以樂觀鎖寫回名單，確保同一位學員不會被重複遞補
+	if err := uc.repo.SaveWaitlist(ctx, wl); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrPromoteWaitlistConflict.Wrap(err)
+		}
+		return nil, ErrPromoteWaitlistSaveWaitlistFail.Wrap(err)
+	}
+	// 不支援交易時由補償將學員放回候補名單
+	repository.Compensate(ctx, func(ctx context.Context) error {
+		uc.revertPromotion(ctx, trainDateID, entryIDs)
+		return nil
+	})
+
+	// 3. 建立預約並寫入領域事件
+	if err := uc.repo.SaveManyAppointments(ctx, appointments); err != nil {
+		return nil, ErrPromoteWaitlistSaveApptFail.Wrap(err)
+	}
+	events := make([]event.Event, 0, len(appointments))
+	for _, appt := range appointments {
+		events = append(events, event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicAppointmentStatusChanged, domain.AppointmentStatusChanged{
+			BookingID:  appt.ID(),
+			UserID:     appt.User().UserID(),
+			TrainingID: appt.TrainingID(),
+			OldStatus:  entity.WaitlistEntryStatusWaiting.String(),
+			NewStatus:  appt.Status().String(),
+			OccurredAt: time.Now(),
+		}))
+	}
+	if ucErr := core.AddEvents(ctx, uc.repo, events...); ucErr != nil {
+		return nil, ucErr
+	}
+	return appointments, nil
+}
+
+// findStudentID 候補僅記錄姓名，遞補時對應到家長底下的同名學員，找不到時留待遷移工具處理
+func (uc *promoteWaitlistUseCase) findStudentID(
+	ctx context.Context, userID, childName string,
+) (string, core.UseCaseError) {
+	students, err := uc.repo.FindStudentsByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", nil
+		}
+		return "", ErrPromoteWaitlistFindStudentFail.Wrap(err)
+	}
+	if s := entity.FindStudentByName(students, childName); s != nil {
+		return s.ID(), nil
+	}
+	return "", nil
+}
+
+// revertPromotion 建立預約失敗時將學員放回候補名單
+func (uc *promoteWaitlistUseCase) revertPromotion(ctx context.Context, trainDateID string, entryIDs []string) {
+	wl, err := uc.repo.FindWaitlistByTrainID(ctx, trainDateID)
+	if err != nil {
+		return
+	}
+	for _, id := range entryIDs {
+		_ = wl.RevertPromotion(id)
+	}
+	_ = uc.repo.SaveWaitlist(ctx, wl)
+}
+
+var (
+	ErrPromoteWaitlistFindWaitlistFail = core.NewDBError(
+		"PROMOTE_WAITLIST", "FIND_WAITLIST_FAIL", "find waitlist fail", core.ErrInternal)
+	ErrPromoteWaitlistTrainDateNotFound = core.NewDBError(
+		"PROMOTE_WAITLIST", "TRAIN_DATE_NOT_FOUND", "train date not found", core.ErrNotFound)
+	ErrPromoteWaitlistDeductCapacityFail = core.NewDBError(
+		"PROMOTE_WAITLIST", "DEDUCT_CAPACITY_FAIL", "deduct capacity fail", core.ErrInternal)
+	ErrPromoteWaitlistFindStudentFail = core.NewDBError(
+		"PROMOTE_WAITLIST", "FIND_STUDENT_FAIL", "find student fail", core.ErrInternal)
+	ErrPromoteWaitlistDomainFail = core.NewDomainError(
+		"PROMOTE_WAITLIST", "DOMAIN_ERROR", "promote waitlist entry fail", core.ErrInternal)
+	ErrPromoteWaitlistConflict = core.NewDBError(
+		"PROMOTE_WAITLIST", "CONFLICT", "waitlist updated by others", core.ErrConflict)
+	ErrPromoteWaitlistSaveWaitlistFail = core.NewDBError(
+		"PROMOTE_WAITLIST", "SAVE_WAITLIST_FAIL", "save waitlist fail", core.ErrInternal)
+	ErrPromoteWaitlistSaveApptFail = core.NewDBError(
+		"PROMOTE_WAITLIST", "SAVE_APPOINTMENT_FAIL", "save appointment fail", core.ErrInternal)
+)
@@ -0,0 +1,137 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memPromoteRepo 以記憶體保存一個場次的候補名單，available 為剩餘名額；
+// 未用到的方法由內嵌的 interface 提供
+type memPromoteRepo struct {
+	repository.TrainRepository
+	repository.AppointmentRepository
+	repository.WaitlistRepository
+	repository.StudentRepository
+	training   *entity.TrainDate
+	waitlist   *entity.Waitlist
+	appts      []*entity.Appointment
+	events     []event.Event
+	available  int
+	nextID     int
+	deductErr  repository.RepoError
+	studentErr repository.RepoError
+}
+
+func newMemPromoteRepo(t *testing.T, available int, childNames ...string) *memPromoteRepo {
+	t.Helper()
+	start := time.Now().Add(72 * time.Hour)
+	period, err := entity.NewTimeRange(start, start.Add(time.Hour))
+	require.NoError(t, err)
+	td, err := entity.NewTrainDate(entity.WithBasicTrainDate("train1", "coach1", "Gym A", 10, period))
+	require.NoError(t, err)
+	wl, err := entity.NewWaitlist(entity.WithWaitlistTrainingID(td.ID()))
+	require.NoError(t, err)
+	user, err := entity.NewUser("user1", "Parent")
+	require.NoError(t, err)
+	repo := &memPromoteRepo{training: td, waitlist: wl, available: available}
+	for _, name := range childNames {
+		_, err := wl.Join(repo.GenerateID(), user, name)
+		require.NoError(t, err)
+	}
+	return repo
+}
+
+func (r *memPromoteRepo) GenerateID() string {
+	r.nextID++
+	return fmt.Sprintf("id-%03d", r.nextID)
+}
+
+func (r *memPromoteRepo) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *memPromoteRepo) AddEvents(ctx context.Context, events ...event.Event) repository.RepoError {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *memPromoteRepo) FindTrainDateByID(ctx context.Context, id string) (*entity.TrainDate, repository.RepoError) {
+	return r.training, nil
+}
+
+// DeductCapacity 與 mongo 實作相同，名額不足時沒有符合條件的場次
+func (r *memPromoteRepo) DeductCapacity(ctx context.Context, trainingID string, count int) repository.RepoError {
+	if r.deductErr != nil {
+		return r.deductErr
+	}
+	if r.available < count {
+		return repository.NewRepoNotFoundError("train", "memory", "deduct_capacity", nil)
+	}
+	r.available -= count
+	return nil
+}
+
+func (r *memPromoteRepo) FindWaitlistByTrainID(ctx context.Context, trainingID string) (*entity.Waitlist, repository.RepoError) {
+	return r.waitlist, nil
+}
+
+func (r *memPromoteRepo) SaveWaitlist(ctx context.Context, waitlist *entity.Waitlist) repository.RepoError {
+	return nil
+}
+
+func (r *memPromoteRepo) SaveManyAppointments(ctx context.Context, appts []*entity.Appointment) repository.RepoError {
+	r.appts = append(r.appts, appts...)
+	return nil
+}
+
+func (r *memPromoteRepo) FindStudentsByUserID(ctx context.Context, userID string) ([]*entity.Student, repository.RepoError) {
+	if r.studentErr != nil {
+		return nil, r.studentErr
+	}
+	return nil, repository.NewRepoNotFoundError("student", "memory", "find_students_by_user_id", nil)
+}
+
+func TestPromoteWaitlist_StopsWhenFull(t *testing.T) {
+	repo := newMemPromoteRepo(t, 1, "ChildA", "ChildB")
+
+	appts, err := NewPromoteWaitlistUseCase(repo).Execute(t.Context(), ReqPromoteWaitlist{TrainDateID: "train1"})
+	require.Nil(t, err)
+	require.Len(t, appts, 1)
+	assert.Equal(t, "ChildA", appts[0].ChildName())
+	assert.Len(t, repo.waitlist.Waiting(), 1)
+	assert.Len(t, repo.events, 1)
+}
+
+func TestPromoteWaitlist_ReturnsRepoErrors(t *testing.T) {
+	internalErr := repository.NewRepoInternalError("train", "memory", "op", errors.New("connection reset"))
+
+	t.Run("DeductCapacity", func(t *testing.T) {
+		repo := newMemPromoteRepo(t, 1, "ChildA")
+		repo.deductErr = internalErr
+
+		_, err := NewPromoteWaitlistUseCase(repo).Execute(t.Context(), ReqPromoteWaitlist{TrainDateID: "train1"})
+		require.NotNil(t, err)
+		assert.Equal(t, ErrPromoteWaitlistDeductCapacityFail.Code(), err.Code())
+		assert.Empty(t, repo.appts)
+	})
+
+	t.Run("FindStudent", func(t *testing.T) {
+		repo := newMemPromoteRepo(t, 1, "ChildA")
+		repo.studentErr = internalErr
+
+		_, err := NewPromoteWaitlistUseCase(repo).Execute(t.Context(), ReqPromoteWaitlist{TrainDateID: "train1"})
+		require.NotNil(t, err)
+		assert.Equal(t, ErrPromoteWaitlistFindStudentFail.Code(), err.Code())
+		assert.Empty(t, repo.appts)
+	})
+}
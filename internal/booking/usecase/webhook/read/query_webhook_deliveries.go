@@ -0,0 +1,59 @@
+package read
+
+import (
+	"context"
+	"errors"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+const maxWebhookDeliveriesLimit = 100
+
+// ReqQueryWebhookDeliveries 由新到舊，Limit 未指定或超過上限時取 100 筆
+type ReqQueryWebhookDeliveries struct {
+	EndpointID string
+	Limit      int
+}
+
+type QueryWebhookDeliveriesUseCase core.ReadUseCase[ReqQueryWebhookDeliveries, []*entity.WebhookDelivery]
+
+type queryWebhookDeliveriesUseCase struct {
+	repo repository.WebhookRepository
+}
+
+func NewQueryWebhookDeliveriesUseCase(repo repository.WebhookRepository) QueryWebhookDeliveriesUseCase {
+	return &queryWebhookDeliveriesUseCase{repo: repo}
+}
+
+func (uc *queryWebhookDeliveriesUseCase) Name() string {
+	return "QueryWebhookDeliveries"
+}
+
+func (uc *queryWebhookDeliveriesUseCase) Execute(
+	ctx context.Context, req ReqQueryWebhookDeliveries,
+) ([]*entity.WebhookDelivery, core.UseCaseError) {
+	if req.EndpointID == "" {
+		return nil, ErrQueryWebhookDeliveriesInvalidInput
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxWebhookDeliveriesLimit {
+		limit = maxWebhookDeliveriesLimit
+	}
+	deliveries, err := uc.repo.FindWebhookDeliveries(ctx, req.EndpointID, "", limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*entity.WebhookDelivery{}, nil
+		}
+		return nil, ErrQueryWebhookDeliveriesFail.Wrap(err)
+	}
+	return deliveries, nil
+}
+
+var (
+	ErrQueryWebhookDeliveriesInvalidInput = core.NewUseCaseError(
+		"QUERY_WEBHOOK_DELIVERIES", "INVALID_INPUT", "請指定端點", core.ErrInvalidInput)
+	ErrQueryWebhookDeliveriesFail = core.NewDBError(
+		"QUERY_WEBHOOK_DELIVERIES", "QUERY_FAIL", "query webhook deliveries fail", core.ErrInternal)
+)
@@ -0,0 +1,49 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqQueryWebhookEndpoints struct{}
+
+type QueryWebhookEndpointsUseCase core.ReadUseCase[ReqQueryWebhookEndpoints, []*entity.WebhookEndpoint]
+
+type queryWebhookEndpointsUseCase struct {
+	repo repository.WebhookRepository
+}
+
+func NewQueryWebhookEndpointsUseCase(repo repository.WebhookRepository) QueryWebhookEndpointsUseCase {
+	return &queryWebhookEndpointsUseCase{repo: repo}
+}
+
+func (uc *queryWebhookEndpointsUseCase) Name() string {
+	return "QueryWebhookEndpoints"
+}
+
+// Execute 依建立時間排序
+func (uc *queryWebhookEndpointsUseCase) Execute(
+	ctx context.Context, _ ReqQueryWebhookEndpoints,
+) ([]*entity.WebhookEndpoint, core.UseCaseError) {
+	endpoints, err := uc.repo.FindWebhookEndpoints(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*entity.WebhookEndpoint{}, nil
+		}
+		return nil, ErrQueryWebhookEndpointsFail.Wrap(err)
+	}
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt().Before(endpoints[j].CreatedAt())
+	})
+	return endpoints, nil
+}
+
+var (
+	ErrQueryWebhookEndpointsFail = core.NewDBError(
+		"QUERY_WEBHOOK_ENDPOINTS", "QUERY_FAIL", "query webhook endpoints fail", core.ErrInternal)
+)
@@ -0,0 +1,101 @@
+package write
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqCreateWebhookEndpoint struct {
+	Name       string
+	URL        string
+	OperatorID string
+	Topics     []string
+}
+
+type CreateWebhookEndpointUseCase core.WriteUseCase[ReqCreateWebhookEndpoint, *entity.WebhookEndpoint]
+
+type createWebhookEndpointUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.WebhookRepository
+}
+
+func NewCreateWebhookEndpointUseCase(repo createWebhookEndpointUseCaseRepo) CreateWebhookEndpointUseCase {
+	return &createWebhookEndpointUseCase{repo: repo}
+}
+
+type createWebhookEndpointUseCase struct {
+	repo createWebhookEndpointUseCaseRepo
+}
+
+func (uc *createWebhookEndpointUseCase) Name() string {
+	return "CreateWebhookEndpoint"
+}
+
+// Execute 新端點預設啟用，secret 由系統產生，只在建立與更換時顯示給管理員
+func (uc *createWebhookEndpointUseCase) Execute(
+	ctx context.Context, req ReqCreateWebhookEndpoint,
+) (*entity.WebhookEndpoint, core.UseCaseError) {
+	if ucErr := checkTopics(req.Topics); ucErr != nil {
+		return nil, ucErr
+	}
+	endpoint, err := entity.NewWebhookEndpoint(
+		entity.WithWebhookEndpointID(uc.repo.GenerateID()),
+		entity.WithWebhookEndpointName(req.Name),
+		entity.WithWebhookEndpointURL(req.URL),
+		entity.WithWebhookEndpointTopics(req.Topics...),
+		entity.WithWebhookEndpointCreatedBy(req.OperatorID),
+	)
+	if err != nil {
+		return nil, ErrWebhookEndpointDomainFail.Wrap(err)
+	}
+	if saveErr := uc.repo.SaveWebhookEndpoint(ctx, endpoint); saveErr != nil {
+		return nil, ErrWebhookEndpointSaveFail.Wrap(saveErr)
+	}
+	return endpoint, nil
+}
+
+// checkTopics 只能訂閱可對外轉送的主題
+func checkTopics(topics []string) core.UseCaseError {
+	allowed := domain.WebhookTopics()
+	for _, t := range topics {
+		if !slices.Contains(allowed, t) {
+			return ErrWebhookEndpointUnknownTopic
+		}
+	}
+	return nil
+}
+
+func findEndpoint(
+	ctx context.Context, repo repository.WebhookRepository, id string,
+) (*entity.WebhookEndpoint, core.UseCaseError) {
+	if id == "" {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	endpoint, err := repo.FindWebhookEndpointByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookEndpointNotFound
+		}
+		return nil, ErrWebhookEndpointFindFail.Wrap(err)
+	}
+	return endpoint, nil
+}
+
+var (
+	ErrWebhookEndpointDomainFail = core.NewDomainError(
+		"WEBHOOK", "DOMAIN_ERROR", "端點資料不正確，名稱需為 1-30 字、網址需為 http(s) 且至少訂閱一個主題", core.ErrInvalidInput)
+	ErrWebhookEndpointUnknownTopic = core.NewUseCaseError(
+		"WEBHOOK", "UNKNOWN_TOPIC", "包含不支援轉送的主題", core.ErrInvalidInput)
+	ErrWebhookEndpointNotFound = core.NewUseCaseError(
+		"WEBHOOK", "NOT_FOUND", "找不到 webhook 端點", core.ErrNotFound)
+	ErrWebhookEndpointFindFail = core.NewDBError(
+		"WEBHOOK", "FIND_ENDPOINT_FAIL", "find webhook endpoint fail", core.ErrInternal)
+	ErrWebhookEndpointSaveFail = core.NewDBError(
+		"WEBHOOK", "SAVE_ENDPOINT_FAIL", "save webhook endpoint fail", core.ErrInternal)
+)
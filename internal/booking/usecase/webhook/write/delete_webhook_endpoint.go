@@ -0,0 +1,46 @@
+package write
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+type ReqDeleteWebhookEndpoint struct {
+	ID string
+}
+
+type DeleteWebhookEndpointUseCase core.WriteUseCase[ReqDeleteWebhookEndpoint, *entity.WebhookEndpoint]
+
+func NewDeleteWebhookEndpointUseCase(repo repository.WebhookRepository) DeleteWebhookEndpointUseCase {
+	return &deleteWebhookEndpointUseCase{repo: repo}
+}
+
+type deleteWebhookEndpointUseCase struct {
+	repo repository.WebhookRepository
+}
+
+func (uc *deleteWebhookEndpointUseCase) Name() string {
+	return "DeleteWebhookEndpoint"
+}
+
+// Execute 送出紀錄保留至 TTL 到期，不一併刪除
+func (uc *deleteWebhookEndpointUseCase) Execute(
+	ctx context.Context, req ReqDeleteWebhookEndpoint,
+) (*entity.WebhookEndpoint, core.UseCaseError) {
+	endpoint, ucErr := findEndpoint(ctx, uc.repo, req.ID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	if delErr := uc.repo.DeleteWebhookEndpoint(ctx, endpoint); delErr != nil {
+		return nil, ErrDeleteWebhookEndpointFail.Wrap(delErr)
+	}
+	return endpoint, nil
+}
+
+var (
+	ErrDeleteWebhookEndpointFail = core.NewDBError(
+		"DELETE_WEBHOOK_ENDPOINT", "DELETE_FAIL", "delete webhook endpoint fail", core.ErrInternal)
+)
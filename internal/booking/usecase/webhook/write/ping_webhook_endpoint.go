@@ -0,0 +1,66 @@
+package write
+
+import (
+	"context"
+	"time"
+
+	"seanAIgent/internal/booking/domain"
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/domain/service"
+	"seanAIgent/internal/booking/usecase/core"
+	"seanAIgent/internal/event"
+)
+
+type ReqPingWebhookEndpoint struct {
+	ID         string
+	OperatorID string
+}
+
+type PingWebhookEndpointUseCase core.WriteUseCase[ReqPingWebhookEndpoint, *entity.WebhookDelivery]
+
+type pingWebhookEndpointUseCaseRepo interface {
+	repository.IdentityGenerator
+	repository.WebhookRepository
+}
+
+func NewPingWebhookEndpointUseCase(
+	repo pingWebhookEndpointUseCaseRepo, sender service.WebhookSender,
+) PingWebhookEndpointUseCase {
+	return &pingWebhookEndpointUseCase{repo: repo, sender: sender}
+}
+
+type pingWebhookEndpointUseCase struct {
+	repo   pingWebhookEndpointUseCaseRepo
+	sender service.WebhookSender
+}
+
+func (uc *pingWebhookEndpointUseCase) Name() string {
+	return "PingWebhookEndpoint"
+}
+
+// Execute 直接送出一次測試事件，不經過事件匯流排也不重試；停用中的端點同樣可以測試。
+// 對方回應失敗不視為錯誤，結果記錄在回傳的送出紀錄中
+func (uc *pingWebhookEndpointUseCase) Execute(
+	ctx context.Context, req ReqPingWebhookEndpoint,
+) (*entity.WebhookDelivery, core.UseCaseError) {
+	endpoint, ucErr := findEndpoint(ctx, uc.repo, req.ID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	e := event.NewTypedEvent(uc.repo.GenerateID(), domain.TopicWebhookPing, domain.WebhookPing{
+		EndpointID:  endpoint.ID(),
+		RequestedBy: req.OperatorID,
+		OccurredAt:  time.Now(),
+	})
+	delivery := uc.sender.Send(ctx, endpoint, e, 1)
+	if err := uc.repo.AddWebhookDelivery(ctx, delivery); err != nil {
+		return nil, ErrPingWebhookEndpointSaveFail.Wrap(err)
+	}
+	return delivery, nil
+}
+
+var (
+	ErrPingWebhookEndpointSaveFail = core.NewDBError(
+		"PING_WEBHOOK_ENDPOINT", "SAVE_DELIVERY_FAIL", "save webhook delivery fail", core.ErrInternal)
+)
@@ -0,0 +1,55 @@
+package write
+
+import (
+	"context"
+
+	"seanAIgent/internal/booking/domain/entity"
+	"seanAIgent/internal/booking/domain/repository"
+	"seanAIgent/internal/booking/usecase/core"
+)
+
+// ReqUpdateWebhookEndpoint RotateSecret 為 true 時同時更換金鑰
+type ReqUpdateWebhookEndpoint struct {
+	ID           string
+	Name         string
+	URL          string
+	Topics       []string
+	Enabled      bool
+	RotateSecret bool
+}
+
+type UpdateWebhookEndpointUseCase core.WriteUseCase[ReqUpdateWebhookEndpoint, *entity.WebhookEndpoint]
+
+func NewUpdateWebhookEndpointUseCase(repo repository.WebhookRepository) UpdateWebhookEndpointUseCase {
+	return &updateWebhookEndpointUseCase{repo: repo}
+}
+
+type updateWebhookEndpointUseCase struct {
+	repo repository.WebhookRepository
+}
+
+func (uc *updateWebhookEndpointUseCase) Name() string {
+	return "UpdateWebhookEndpoint"
+}
+
+func (uc *updateWebhookEndpointUseCase) Execute(
+	ctx context.Context, req ReqUpdateWebhookEndpoint,
+) (*entity.WebhookEndpoint, core.UseCaseError) {
+	if ucErr := checkTopics(req.Topics); ucErr != nil {
+		return nil, ucErr
+	}
+	endpoint, ucErr := findEndpoint(ctx, uc.repo, req.ID)
+	if ucErr != nil {
+		return nil, ucErr
+	}
+	if err := endpoint.Update(req.Name, req.URL, req.Topics, req.Enabled); err != nil {
+		return nil, ErrWebhookEndpointDomainFail.Wrap(err)
+	}
+	if req.RotateSecret {
+		endpoint.RotateSecret()
+	}
+	if saveErr := uc.repo.SaveWebhookEndpoint(ctx, endpoint); saveErr != nil {
+		return nil, ErrWebhookEndpointSaveFail.Wrap(saveErr)
+	}
+	return endpoint, nil
+}
@@ -0,0 +1,406 @@
+package event
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	archiveIndexCollection = "event_archives"
+	defaultArchivePrefix   = "event-archive"
+	archiveBatchSize       = 500
+	oneDay                 = 24 * time.Hour
+)
+
+var ErrArchiveChecksum = errors.New("archive checksum mismatch")
+
+// ArchiveStorage 封存檔的存放位置，vulpes/storage 的 Storage 即滿足此介面
+type ArchiveStorage interface {
+	Upload(ctx context.Context, key string, data []byte) (string, error)
+	Download(ctx context.Context, key string) ([]byte, error)
+}
+
+// ArchiveEntry 一個封存檔：同一天 (UTC)、同一主題的事件，依 ID 排列的 gzip JSONL
+type ArchiveEntry struct {
+	Key        string
+	Day        time.Time
+	Topic      string
+	Count      int
+	FirstID    string
+	LastID     string
+	Size       int
+	SHA256     string
+	ArchivedAt time.Time
+}
+
+// ArchiveFilter 依封存日期篩選，From、To 為 [From, To)，留空代表不限制
+type ArchiveFilter struct {
+	Topic string
+	From  time.Time
+	To    time.Time
+}
+
+func (f ArchiveFilter) match(e ArchiveEntry) bool {
+	return (f.Topic == "" || e.Topic == f.Topic) &&
+		(f.From.IsZero() || !e.Day.Before(f.From.UTC().Truncate(oneDay))) &&
+		(f.To.IsZero() || e.Day.Before(f.To))
+}
+
+// ArchiveIndex 封存檔清單，供依日期、主題查找封存檔
+type ArchiveIndex interface {
+	// Save 同一個 Key 重複寫入時覆蓋
+	Save(ctx context.Context, entry ArchiveEntry) error
+	// Find 依日期、主題排列
+	Find(ctx context.Context, filter ArchiveFilter) ([]ArchiveEntry, error)
+}
+
+// archivedEvent 封存檔中的一行；Payload 為 JSON 時原樣保留以便直接搜尋，
+// 自訂格式 (Marshaler) 改存於 Raw
+type archivedEvent struct {
+	ID         string            `json:"id"`
+	Topic      string            `json:"topic"`
+	Version    int               `json:"version"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Data       json.RawMessage   `json:"data,omitempty"`
+	Raw        []byte            `json:"raw,omitempty"`
+	Trace      map[string]string `json:"trace_context,omitempty"`
+}
+
+type ArchiverOption func(*Archiver)
+
+// WithArchivePrefix 封存檔 key 的前綴，空字串時使用預設的 event-archive
+func WithArchivePrefix(prefix string) ArchiverOption {
+	return func(a *Archiver) {
+		if prefix != "" {
+			a.prefix = prefix
+		}
+	}
+}
+
+// Archiver 在事件紀錄因 TTL 刪除前，將事件封存為每日、每個主題一個檔案，並可再匯入事件紀錄
+type Archiver struct {
+	log     EventLog
+	storage ArchiveStorage
+	index   ArchiveIndex
+	prefix  string
+	now     func() time.Time
+}
+
+func NewArchiver(log EventLog, storage ArchiveStorage, index ArchiveIndex, opts ...ArchiverOption) *Archiver {
+	a := &Archiver{log: log, storage: storage, index: index, prefix: defaultArchivePrefix, now: time.Now}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Archive 封存發生時間早於 before 所在日期 (UTC) 的事件，只處理完整的一天。
+// 由最後封存的日期開始逐日讀取並上傳，已封存的檔案略過，中斷後重新執行即可接續。
+func (a *Archiver) Archive(ctx context.Context, before time.Time) ([]ArchiveEntry, error) {
+	cutoff := before.UTC().Truncate(oneDay)
+	archived, err := a.index.Find(ctx, ArchiveFilter{})
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool, len(archived))
+	var from time.Time
+	for _, e := range archived {
+		done[e.Key] = true
+		from = maxTime(from, e.Day)
+	}
+	if from.IsZero() {
+		oldest, err := a.log.FindEvents(ctx, EventFilter{To: cutoff, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(oldest) == 0 {
+			return nil, nil
+		}
+		from = oldest[0].OccurredAt().UTC().Truncate(oneDay)
+	}
+
+	// 一次只讀取一天，首次執行時不需將保留期間內的事件全部載入記憶體
+	var result []ArchiveEntry
+	for day := from; day.Before(cutoff); day = day.Add(oneDay) {
+		entries, err := a.archiveDay(ctx, day, done)
+		result = append(result, entries...)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// archiveDay 依主題寫入一天的封存檔，清單只在上傳成功後更新
+func (a *Archiver) archiveDay(ctx context.Context, day time.Time, done map[string]bool) ([]ArchiveEntry, error) {
+	groups := make(map[string][]Event)
+	filter := EventFilter{From: day, To: day.Add(oneDay), Limit: archiveBatchSize}
+	for {
+		events, err := a.log.FindEvents(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			key := a.key(day, e.Topic())
+			if !done[key] {
+				groups[key] = append(groups[key], e)
+			}
+		}
+		if len(events) < archiveBatchSize {
+			break
+		}
+		filter.AfterID = events[len(events)-1].ID()
+	}
+
+	var result []ArchiveEntry
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		events := groups[key]
+		data, err := encodeArchive(events)
+		if err != nil {
+			return result, fmt.Errorf("encode %s fail: %w", key, err)
+		}
+		if _, err := a.storage.Upload(ctx, key, data); err != nil {
+			return result, fmt.Errorf("upload %s fail: %w", key, err)
+		}
+		sum := sha256.Sum256(data)
+		entry := ArchiveEntry{
+			Key:        key,
+			Day:        day,
+			Topic:      events[0].Topic(),
+			Count:      len(events),
+			FirstID:    events[0].ID(),
+			LastID:     events[len(events)-1].ID(),
+			Size:       len(data),
+			SHA256:     hex.EncodeToString(sum[:]),
+			ArchivedAt: a.now(),
+		}
+		if err := a.index.Save(ctx, entry); err != nil {
+			return result, err
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// Entries 查詢封存清單，依日期、主題排列
+func (a *Archiver) Entries(ctx context.Context, filter ArchiveFilter) ([]ArchiveEntry, error) {
+	return a.index.Find(ctx, filter)
+}
+
+// key 例如 event-archive/2026/03/01/booking.leave.reviewed.jsonl.gz
+func (a *Archiver) key(d time.Time, topic string) string {
+	return fmt.Sprintf("%s/%s/%s.jsonl.gz", a.prefix, d.Format("2006/01/02"), topic)
+}
+
+// Restore 將符合條件的封存檔匯入 target，回傳匯入的事件數；
+// 匯入 TTL 為 30 天的 event_logs 會立即被刪除，請改用 WithEventCollection 另開集合
+func (a *Archiver) Restore(ctx context.Context, target EventStore, filter ArchiveFilter) (int, error) {
+	entries, err := a.index.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	restored := 0
+	for _, entry := range entries {
+		data, err := a.storage.Download(ctx, entry.Key)
+		if err != nil {
+			return restored, fmt.Errorf("download %s fail: %w", entry.Key, err)
+		}
+		if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return restored, fmt.Errorf("%w: %s", ErrArchiveChecksum, entry.Key)
+		}
+		events, err := decodeArchive(data)
+		if err != nil {
+			return restored, fmt.Errorf("decode %s fail: %w", entry.Key, err)
+		}
+		for _, e := range events {
+			if err := target.Save(ctx, e); err != nil {
+				return restored, err
+			}
+			restored++
+		}
+	}
+	return restored, nil
+}
+
+func encodeArchive(events []Event) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	enc.SetEscapeHTML(false)
+	for _, e := range events {
+		line := archivedEvent{
+			ID:         e.ID(),
+			Topic:      e.Topic(),
+			Version:    e.Version(),
+			OccurredAt: e.OccurredAt().UTC(),
+		}
+		if json.Valid(e.Data()) {
+			line.Data = e.Data()
+		} else {
+			line.Raw = e.Data()
+		}
+		if t, ok := e.(tracedEvent); ok {
+			line.Trace = t.traceContext()
+		}
+		if err := enc.Encode(line); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeArchive(data []byte) ([]Event, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	dec := json.NewDecoder(zr)
+	var events []Event
+	for {
+		var line archivedEvent
+		if err := dec.Decode(&line); err != nil {
+			if errors.Is(err, io.EOF) {
+				return events, nil
+			}
+			return nil, err
+		}
+		e := &genericEvent{
+			id:         line.ID,
+			topic:      line.Topic,
+			occurredAt: line.OccurredAt,
+			data:       line.Raw,
+			version:    line.Version,
+			trace:      line.Trace,
+		}
+		if line.Data != nil {
+			e.data = line.Data
+		}
+		events = append(events, e)
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+type mongoArchiveIndex struct {
+	db *mongo.Database
+}
+
+func NewMongoArchiveIndex(db *mongo.Database) (ArchiveIndex, error) {
+	idx := &mongoArchiveIndex{db: db}
+	_, err := db.Collection(archiveIndexCollection).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "day", Value: 1}, {Key: "topic", Value: 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+type archiveEntryDoc struct {
+	Key        string    `bson:"_id"`
+	Day        time.Time `bson:"day"`
+	Topic      string    `bson:"topic"`
+	Count      int       `bson:"count"`
+	FirstID    string    `bson:"first_id"`
+	LastID     string    `bson:"last_id"`
+	Size       int       `bson:"size"`
+	SHA256     string    `bson:"sha256"`
+	ArchivedAt time.Time `bson:"archived_at"`
+}
+
+func (idx *mongoArchiveIndex) Save(ctx context.Context, entry ArchiveEntry) error {
+	doc := archiveEntryDoc(entry)
+	_, err := idx.db.Collection(archiveIndexCollection).ReplaceOne(
+		ctx, bson.M{"_id": doc.Key}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (idx *mongoArchiveIndex) Find(ctx context.Context, filter ArchiveFilter) ([]ArchiveEntry, error) {
+	query := bson.M{}
+	if filter.Topic != "" {
+		query["topic"] = filter.Topic
+	}
+	days := bson.M{}
+	if !filter.From.IsZero() {
+		days["$gte"] = filter.From.UTC().Truncate(oneDay)
+	}
+	if !filter.To.IsZero() {
+		days["$lt"] = filter.To
+	}
+	if len(days) > 0 {
+		query["day"] = days
+	}
+	cursor, err := idx.db.Collection(archiveIndexCollection).Find(ctx, query,
+		options.Find().SetSort(bson.D{{Key: "day", Value: 1}, {Key: "topic", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []archiveEntryDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	entries := make([]ArchiveEntry, 0, len(docs))
+	for _, doc := range docs {
+		entries = append(entries, ArchiveEntry(doc))
+	}
+	return entries, nil
+}
+
+// MemoryArchiveIndex 記憶體版的封存清單，供測試使用
+type MemoryArchiveIndex struct {
+	mu      sync.RWMutex
+	entries map[string]ArchiveEntry
+}
+
+func NewMemoryArchiveIndex() *MemoryArchiveIndex {
+	return &MemoryArchiveIndex{entries: make(map[string]ArchiveEntry)}
+}
+
+func (idx *MemoryArchiveIndex) Save(ctx context.Context, entry ArchiveEntry) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.entries[entry.Key] = entry
+	return nil
+}
+
+func (idx *MemoryArchiveIndex) Find(ctx context.Context, filter ArchiveFilter) ([]ArchiveEntry, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	var result []ArchiveEntry
+	for _, e := range idx.entries {
+		if filter.match(e) {
+			result = append(result, e)
+		}
+	}
+	slices.SortFunc(result, func(a, b ArchiveEntry) int {
+		return cmp.Or(a.Day.Compare(b.Day), cmp.Compare(a.Topic, b.Topic))
+	})
+	return result, nil
+}
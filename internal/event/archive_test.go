@@ -0,0 +1,115 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memArchiveStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (s *memArchiveStorage) Upload(ctx context.Context, key string, data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[key] = data
+	return key, nil
+}
+
+func (s *memArchiveStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.files[key], nil
+}
+
+func TestArchiver(t *testing.T) {
+	ctx := t.Context()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(WithMemoryTTL(0))
+	require.NoError(t, store.Save(ctx, NewStoredEvent("evt_01", "a", 1, base, []byte(`{"user_id":"u1"}`))))
+	require.NoError(t, store.Save(ctx, NewStoredEvent("evt_02", "b", 2, base.Add(time.Hour), []byte(`{"a":"<b>"}`))))
+	require.NoError(t, store.Save(ctx, NewStoredEvent("evt_03", "a", 1, base.Add(20*time.Hour), []byte("custom"))))
+	require.NoError(t, store.Save(ctx, &genericEvent{
+		id: "evt_04", topic: "a", occurredAt: base.Add(40 * time.Hour), data: []byte(`{}`),
+		trace: map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+	}))
+	storage := &memArchiveStorage{files: make(map[string][]byte)}
+	index := NewMemoryArchiveIndex()
+	archiver := NewArchiver(store, storage, index, WithArchivePrefix("test"))
+
+	// 只封存完整的一天：3/3 當天的 evt_04 未到期
+	entries, err := archiver.Archive(ctx, base.Add(40*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "test/2026/03/01/a.jsonl.gz", entries[0].Key)
+	assert.Equal(t, 1, entries[0].Count)
+	assert.Equal(t, "test/2026/03/01/b.jsonl.gz", entries[1].Key)
+	assert.Equal(t, "test/2026/03/02/a.jsonl.gz", entries[2].Key)
+	assert.Equal(t, "evt_03", entries[2].FirstID)
+	assert.Len(t, storage.files, 3)
+
+	t.Run("SkipsArchivedFiles", func(t *testing.T) {
+		entries, err := archiver.Archive(ctx, base.Add(40*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		entries, err = archiver.Archive(ctx, base.Add(72*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "test/2026/03/03/a.jsonl.gz", entries[0].Key)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		target := NewMemoryStore(WithMemoryTTL(0))
+		n, err := archiver.Restore(ctx, target, ArchiveFilter{Topic: "a", From: base, To: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []string{"evt_01", "evt_03"}, eventIDs(target.Events("")))
+
+		n, err = archiver.Restore(ctx, target, ArchiveFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+		restored := target.Events("")
+		require.Len(t, restored, 4)
+		assert.Equal(t, `{"a":"<b>"}`, string(restored[1].Data()))
+		assert.Equal(t, 2, restored[1].Version())
+		assert.Equal(t, "custom", string(restored[2].Data()))
+		assert.True(t, restored[3].OccurredAt().Equal(base.Add(40*time.Hour)))
+		assert.NotEmpty(t, restored[3].(tracedEvent).traceContext())
+	})
+
+	t.Run("ReadsOneDayAtATime", func(t *testing.T) {
+		log := &recordingEventLog{EventLog: store}
+		entries, err := NewArchiver(log, storage, NewMemoryArchiveIndex(), WithArchivePrefix("other")).
+			Archive(ctx, base.Add(72*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, entries, 4)
+		require.NotEmpty(t, log.filters)
+		for _, f := range log.filters[1:] {
+			assert.Equal(t, oneDay, f.To.Sub(f.From))
+		}
+	})
+
+	t.Run("ChecksumMismatch", func(t *testing.T) {
+		storage.files["test/2026/03/01/b.jsonl.gz"] = []byte("tampered")
+		_, err := archiver.Restore(ctx, NewMemoryStore(), ArchiveFilter{Topic: "b"})
+		assert.ErrorIs(t, err, ErrArchiveChecksum)
+	})
+}
+
+// recordingEventLog 記錄封存時的查詢條件
+type recordingEventLog struct {
+	EventLog
+	filters []EventFilter
+}
+
+func (l *recordingEventLog) FindEvents(ctx context.Context, filter EventFilter) ([]Event, error) {
+	l.filters = append(l.filters, filter)
+	return l.EventLog.FindEvents(ctx, filter)
+}
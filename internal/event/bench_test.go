@@ -6,7 +6,10 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // 一般 JSON Payload (觸發反射)
@@ -125,3 +128,104 @@ func BenchmarkTypedSubscriberHandle(b *testing.B) {
 		}
 	})
 }
+
+// nopStore 排除事件紀錄的成本，只比較分發方式
+type nopStore struct{}
+
+func (nopStore) Save(ctx context.Context, e Event) error { return nil }
+func (nopStore) FindUnprocessedEvents(ctx context.Context, subID, topic string) ([]Event, error) {
+	return nil, nil
+}
+func (nopStore) UpdateProgress(ctx context.Context, subID, evtID string) error { return nil }
+
+// subscribeLive 訂閱並等待追趕完成，nopStore 不保留事件，追趕期間發布的事件不會補處理
+func subscribeLive(bus *internalBus, s Subscriber) {
+	bus.Subscribe(s.Topic(), s)
+	pool := bus.pools[s.Topic()][len(bus.pools[s.Topic()])-1]
+	for {
+		pool.mu.Lock()
+		spilling := pool.spilling
+		pool.mu.Unlock()
+		if !spilling {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// ioSubscriber 模擬查詢 Mongo 的訂閱者，記錄最大同時處理數
+type ioSubscriber struct {
+	latency     time.Duration
+	inflight    atomic.Int64
+	maxInflight atomic.Int64
+	wg          sync.WaitGroup
+}
+
+func (s *ioSubscriber) ID() string    { return "io_sub" }
+func (s *ioSubscriber) Topic() string { return "bench.topic" }
+func (s *ioSubscriber) Handle(ctx context.Context, e Event) error {
+	defer s.wg.Done()
+	n := s.inflight.Add(1)
+	for {
+		m := s.maxInflight.Load()
+		if n <= m || s.maxInflight.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(s.latency)
+	s.inflight.Add(-1)
+	return nil
+}
+
+// BenchmarkFanOut 比較每個事件開一個 goroutine 與 worker pool，max_inflight 即同時打到資料庫的請求數
+func BenchmarkFanOut(b *testing.B) {
+	ctx := context.Background()
+	payload := AppointmentPayload{BookingID: "B001", Status: "ABSENT"}
+
+	b.Run("Unbounded_Goroutines", func(b *testing.B) {
+		bus := NewBus(nopStore{}).(*internalBus)
+		sub := &ioSubscriber{latency: 100 * time.Microsecond}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sub.wg.Add(1)
+			evt := NewTypedEvent(fmt.Sprintf("evt_%d", i), "bench.topic", payload)
+			go bus.handleEvent(ctx, sub, evt)
+		}
+		sub.wg.Wait()
+		b.ReportMetric(float64(sub.maxInflight.Load()), "max_inflight")
+	})
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("WorkerPool_%d", workers), func(b *testing.B) {
+			bus := NewBus(nopStore{}, WithDefaultWorkerPool(WorkerPoolConfig{Workers: workers, QueueSize: 64})).(*internalBus)
+			sub := &ioSubscriber{latency: 100 * time.Microsecond}
+			subscribeLive(bus, sub)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				sub.wg.Add(1)
+				_ = bus.Dispatch(ctx, NewTypedEvent(fmt.Sprintf("evt_%d", i), "bench.topic", payload))
+			}
+			sub.wg.Wait()
+			_ = bus.Close(ctx)
+			b.ReportMetric(float64(sub.maxInflight.Load()), "max_inflight")
+		})
+	}
+
+	b.Run("WorkerPool_KeyBy", func(b *testing.B) {
+		key := KeyBy(func(p AppointmentPayload) string { return p.BookingID })
+		bus := NewBus(nopStore{}, WithDefaultWorkerPool(WorkerPoolConfig{Workers: 4, QueueSize: 64, Key: key})).(*internalBus)
+		sub := &ioSubscriber{latency: 100 * time.Microsecond}
+		subscribeLive(bus, sub)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sub.wg.Add(1)
+			data, _ := json.Marshal(AppointmentPayload{BookingID: fmt.Sprintf("B%03d", i%100), Status: "ABSENT"})
+			// 經由 outbox 還原的事件需反序列化才能取得 key
+			evt := NewStoredEvent(fmt.Sprintf("evt_%d", i), "bench.topic", 1, time.Now(), data)
+			_ = bus.Dispatch(ctx, evt)
+		}
+		sub.wg.Wait()
+		_ = bus.Close(ctx)
+		b.ReportMetric(float64(sub.maxInflight.Load()), "max_inflight")
+	})
+}
@@ -2,78 +2,249 @@ package event
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrBusClosed Bus 關閉後不再分發事件
+var ErrBusClosed = errors.New("event bus closed")
+
 type internalBus struct {
 	store       EventStore
-	subscribers map[string][]Subscriber
+	deadLetters DeadLetterStore
+	retryPolicy RetryPolicy
+	workerPool  WorkerPoolConfig
+	sync        bool
+	telemetry   *busTelemetry
+	pools       map[string][]*workerPool
+	closed      bool
 	mu          sync.RWMutex
 }
 
-func NewBus(store EventStore) Bus {
-	return &internalBus{
+type BusOption func(*internalBus)
+
+// WithDefaultRetryPolicy 未自訂重試策略的訂閱者使用的策略
+func WithDefaultRetryPolicy(p RetryPolicy) BusOption {
+	return func(b *internalBus) {
+		b.retryPolicy = p
+	}
+}
+
+// WithDeadLetterStore 重試用盡的事件寫入 dead letter 後視為已處理；
+// 未設定時不更新進度，留待下次啟動追趕
+func WithDeadLetterStore(s DeadLetterStore) BusOption {
+	return func(b *internalBus) {
+		b.deadLetters = s
+	}
+}
+
+// WithDefaultWorkerPool 未自訂 worker pool 的訂閱者使用的設定
+func WithDefaultWorkerPool(cfg WorkerPoolConfig) BusOption {
+	return func(b *internalBus) {
+		b.workerPool = cfg
+	}
+}
+
+// WithSyncDispatch 在發布端直接處理事件，Publish 返回時訂閱者都已處理完；
+// 不經過 worker pool，供測試與單機展示使用
+func WithSyncDispatch() BusOption {
+	return func(b *internalBus) {
+		b.sync = true
+	}
+}
+
+func NewBus(store EventStore, opts ...BusOption) Bus {
+	b := &internalBus{
 		store:       store,
-		subscribers: make(map[string][]Subscriber),
+		retryPolicy: DefaultRetryPolicy,
+		workerPool:  DefaultWorkerPool,
+		pools:       make(map[string][]*workerPool),
+	}
+	for _, opt := range opts {
+		opt(b)
 	}
+	b.telemetry = newBusTelemetry(b)
+	return b
 }
 
+// Subscribe 每個訂閱者有各自的 worker pool，慢的訂閱者不會佔用其他訂閱者的 worker
 func (b *internalBus) Subscribe(topic string, s Subscriber) {
+	cfg := b.workerPool
+	if p, ok := lookup[WorkerPoolProvider](s); ok {
+		cfg = p.WorkerPool()
+	}
+
 	b.mu.Lock()
-	b.subscribers[topic] = append(b.subscribers[topic], s)
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	pool := newWorkerPool(b, s, cfg)
+	b.pools[topic] = append(b.pools[topic], pool)
 	b.mu.Unlock()
 
 	// 啟動追趕機制：找出該訂閱者漏掉的歷史事件
-	go b.catchUp(context.Background(), topic, s)
+	pool.start()
 }
 
-func (b *internalBus) catchUp(ctx context.Context, topic string, s Subscriber) {
-	unprocessed, err := b.store.FindUnprocessedEvents(ctx, s.ID(), topic)
-	if err != nil {
-		log.Printf("EventBus: catch-up error for %s: %v", s.ID(), err)
-		return
+func (b *internalBus) Publish(ctx context.Context, e Event) {
+	// 1. 持久化事件 (稽核與防丟)
+	if err := b.store.Save(ctx, e); err != nil {
+		log.Printf("EventBus: fail to save event %s: %v", e.ID(), err)
 	}
-
-	for _, e := range unprocessed {
-		b.handleEvent(ctx, s, e)
+	b.telemetry.recordPublished(ctx, e)
+	// 關閉後仍寫入事件紀錄，下次啟動時由追趕處理
+	if err := b.fanOut(ctx, e); err != nil {
+		log.Printf("EventBus: event %s saved but not dispatched: %v", e.ID(), err)
 	}
 }
 
-func (b *internalBus) Publish(ctx context.Context, e Event) {
-	// 1. 持久化事件 (稽核與防丟)
+// Dispatch 供 OutboxRelay 使用，事件紀錄寫入失敗時不分發，留在 outbox 等待重試
+// 關閉後回傳 ErrBusClosed，事件留在 outbox 待下次啟動
+func (b *internalBus) Dispatch(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	closed := b.closed
+	b.mu.RUnlock()
+	if closed {
+		return ErrBusClosed
+	}
 	if err := b.store.Save(ctx, e); err != nil {
-		log.Printf("EventBus: fail to save event %s: %v", e.ID(), err)
+		return err
 	}
+	b.telemetry.recordPublished(ctx, e)
+	return b.fanOut(ctx, e)
+}
 
+// fanOut 交給各訂閱者的 worker pool，處理時的 span 接續發布端的 trace
+func (b *internalBus) fanOut(ctx context.Context, e Event) error {
 	b.mu.RLock()
-	subs, ok := b.subscribers[e.Topic()]
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+	pools := b.pools[e.Topic()]
 	b.mu.RUnlock()
 
-	if !ok {
-		return
+	parent := publisherSpan(ctx, e)
+	for _, p := range pools {
+		p.submit(e, parent)
 	}
+	return nil
+}
 
-	for _, s := range subs {
-		// 重要：使用 context.Background() 確保異步處理不會因為 Web 請求結束而被取消
-		go b.handleEvent(context.Background(), s, e)
+func (b *internalBus) allPools() []*workerPool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var pools []*workerPool
+	for _, ps := range b.pools {
+		pools = append(pools, ps...)
 	}
+	return pools
 }
 
-func (b *internalBus) handleEvent(ctx context.Context, s Subscriber, e Event) {
-	defer func() {
-		if r := recover(); r != nil {
-			log.Printf("EventBus: subscriber %s panicked: %v", s.ID(), r)
+// Close 停止接受事件並等待佇列內的事件處理完；ctx 結束時中斷重試、放棄尚未處理的事件，
+// 進度只推進到已完成的事件，其餘留待下次啟動追趕
+func (b *internalBus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+	defer b.telemetry.close()
+	pools := b.allPools()
+
+	for _, p := range pools {
+		p.stop()
+	}
+	drained := make(chan struct{})
+	go func() {
+		for _, p := range pools {
+			p.workers.Wait()
 		}
+		close(drained)
 	}()
 
-	if err := s.Handle(ctx, e); err != nil {
-		log.Printf("EventBus: subscriber %s handle error: %v", s.ID(), err)
-		return
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		for _, p := range pools {
+			p.cancel()
+		}
+		return ctx.Err()
 	}
+}
 
-	// 處理成功後，更新進度
+// handleEvent 處理單一事件，成功或已寫入 dead letter 後更新進度；
+// 由事件紀錄追趕的事件以寫入時保存的 trace context 為父 span
+func (b *internalBus) handleEvent(ctx context.Context, s Subscriber, e Event) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = withParent(ctx, publisherSpan(ctx, e))
+	}
+	if !b.process(ctx, s, e) {
+		return
+	}
 	if err := b.store.UpdateProgress(ctx, s.ID(), e.ID()); err != nil {
 		log.Printf("EventBus: fail to update progress for %s: %v", s.ID(), err)
 	}
 }
+
+// process 依重試策略處理事件，成功或已寫入 dead letter 時回傳 true
+func (b *internalBus) process(ctx context.Context, s Subscriber, e Event) bool {
+	policy := b.retryPolicy
+	if p, ok := lookup[RetryPolicyProvider](s); ok {
+		policy = p.RetryPolicy()
+	}
+
+	ctx, span := b.telemetry.startHandle(ctx, s, e)
+	var err error
+	attempt := 0
+	defer func() { endHandle(span, attempt, err) }()
+	for attempt < policy.attempts() {
+		attempt++
+		start := time.Now()
+		err = Invoke(ctx, s, e)
+		b.telemetry.recordAttempt(ctx, s, e, time.Since(start), err)
+		if err == nil {
+			break
+		}
+		log.Printf("EventBus: subscriber %s handle error (attempt %d/%d): %v", s.ID(), attempt, policy.attempts(), err)
+		if IsPermanent(err) {
+			break
+		}
+		if attempt < policy.attempts() && !sleepCtx(ctx, policy.Backoff(attempt)) {
+			return false
+		}
+	}
+
+	if err != nil {
+		if b.deadLetters == nil {
+			return false
+		}
+		if dlErr := b.deadLetters.Add(ctx, NewDeadLetter(s.ID(), e, attempt, err)); dlErr != nil {
+			log.Printf("EventBus: fail to save dead letter for %s/%s: %v", s.ID(), e.ID(), dlErr)
+			return false
+		}
+		b.telemetry.recordDeadLetter(ctx, s, e)
+		log.Printf("EventBus: event %s moved to dead letter for %s after %d attempts", e.ID(), s.ID(), attempt)
+	}
+	return true
+}
+
+// Invoke 將訂閱者的 panic 轉為錯誤，與一般失敗一樣重試；
+// 重播等不經過 Bus 直接交給訂閱者處理時也應透過此函式呼叫
+func Invoke(ctx context.Context, s Subscriber, e Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("subscriber panicked: %v", r)
+		}
+	}()
+	return s.Handle(ctx, e)
+}
@@ -0,0 +1,209 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	deadLetterCollection   = "event_dead_letters"
+	defaultDeadLetterLimit = 100
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter 訂閱者重試用盡仍處理失敗的事件，ID 由訂閱者與事件 ID 組成
+type DeadLetter struct {
+	ID            string
+	SubscriberID  string
+	EventID       string
+	Topic         string
+	OccurredAt    time.Time
+	Data          []byte
+	Version       int
+	Error         string
+	Attempts      int
+	FirstFailedAt time.Time
+	LastFailedAt  time.Time
+}
+
+func NewDeadLetter(subscriberID string, e Event, attempts int, err error) DeadLetter {
+	now := time.Now()
+	dl := DeadLetter{
+		ID:            DeadLetterID(subscriberID, e.ID()),
+		SubscriberID:  subscriberID,
+		EventID:       e.ID(),
+		Topic:         e.Topic(),
+		OccurredAt:    e.OccurredAt(),
+		Data:          e.Data(),
+		Version:       e.Version(),
+		Attempts:      attempts,
+		FirstFailedAt: now,
+		LastFailedAt:  now,
+	}
+	if err != nil {
+		dl.Error = err.Error()
+	}
+	return dl
+}
+
+func DeadLetterID(subscriberID, eventID string) string {
+	return subscriberID + ":" + eventID
+}
+
+// Event 還原為原本的事件以便重送
+func (d DeadLetter) Event() Event {
+	return &genericEvent{
+		id:         d.EventID,
+		topic:      d.Topic,
+		occurredAt: d.OccurredAt,
+		data:       d.Data,
+		version:    d.Version,
+	}
+}
+
+// DeadLetterFilter 欄位留空代表不篩選，依最後失敗時間由新到舊排列
+type DeadLetterFilter struct {
+	SubscriberID string
+	Topic        string
+	Limit        int
+}
+
+type DeadLetterStore interface {
+	// Add 同一訂閱者的同一事件再次失敗時累加嘗試次數並更新錯誤
+	Add(ctx context.Context, dl DeadLetter) error
+	List(ctx context.Context, filter DeadLetterFilter) ([]DeadLetter, error)
+	// Get 找不到時回傳 ErrDeadLetterNotFound
+	Get(ctx context.Context, id string) (DeadLetter, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type mongoDeadLetterStore struct {
+	db *mongo.Database
+}
+
+func NewMongoDeadLetterStore(db *mongo.Database) (DeadLetterStore, error) {
+	s := &mongoDeadLetterStore{db: db}
+	if err := s.initIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *mongoDeadLetterStore) initIndexes(ctx context.Context) error {
+	_, err := s.db.Collection(deadLetterCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "subscriber_id", Value: 1}, {Key: "last_failed_at", Value: -1}}},
+		{Keys: bson.D{{Key: "last_failed_at", Value: -1}}},
+	})
+	return err
+}
+
+type deadLetterDoc struct {
+	ID            string    `bson:"_id"`
+	SubscriberID  string    `bson:"subscriber_id"`
+	EventID       string    `bson:"event_id"`
+	Topic         string    `bson:"topic"`
+	OccurredAt    time.Time `bson:"occurred_at"`
+	Data          []byte    `bson:"data"`
+	Version       int       `bson:"schema_version,omitempty"`
+	Error         string    `bson:"error"`
+	Attempts      int       `bson:"attempts"`
+	FirstFailedAt time.Time `bson:"first_failed_at"`
+	LastFailedAt  time.Time `bson:"last_failed_at"`
+}
+
+func (d *deadLetterDoc) toDeadLetter() DeadLetter {
+	return DeadLetter{
+		ID:            d.ID,
+		SubscriberID:  d.SubscriberID,
+		EventID:       d.EventID,
+		Topic:         d.Topic,
+		OccurredAt:    d.OccurredAt,
+		Data:          d.Data,
+		Version:       d.Version,
+		Error:         d.Error,
+		Attempts:      d.Attempts,
+		FirstFailedAt: d.FirstFailedAt,
+		LastFailedAt:  d.LastFailedAt,
+	}
+}
+
+func (s *mongoDeadLetterStore) Add(ctx context.Context, dl DeadLetter) error {
+	_, err := s.db.Collection(deadLetterCollection).UpdateOne(ctx,
+		bson.M{"_id": dl.ID},
+		bson.M{
+			"$set": bson.M{
+				"subscriber_id":  dl.SubscriberID,
+				"event_id":       dl.EventID,
+				"topic":          dl.Topic,
+				"occurred_at":    dl.OccurredAt,
+				"data":           dl.Data,
+				"schema_version": dl.Version,
+				"error":          dl.Error,
+				"last_failed_at": dl.LastFailedAt,
+			},
+			"$inc":         bson.M{"attempts": dl.Attempts},
+			"$setOnInsert": bson.M{"first_failed_at": dl.FirstFailedAt},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoDeadLetterStore) List(ctx context.Context, filter DeadLetterFilter) ([]DeadLetter, error) {
+	query := bson.M{}
+	if filter.SubscriberID != "" {
+		query["subscriber_id"] = filter.SubscriberID
+	}
+	if filter.Topic != "" {
+		query["topic"] = filter.Topic
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDeadLetterLimit
+	}
+	cursor, err := s.db.Collection(deadLetterCollection).Find(ctx, query,
+		options.Find().SetSort(bson.D{{Key: "last_failed_at", Value: -1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []deadLetterDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	result := make([]DeadLetter, 0, len(docs))
+	for i := range docs {
+		result = append(result, docs[i].toDeadLetter())
+	}
+	return result, nil
+}
+
+func (s *mongoDeadLetterStore) Get(ctx context.Context, id string) (DeadLetter, error) {
+	var doc deadLetterDoc
+	err := s.db.Collection(deadLetterCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	return doc.toDeadLetter(), nil
+}
+
+func (s *mongoDeadLetterStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.Collection(deadLetterCollection).DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
@@ -2,15 +2,20 @@ package event
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var ErrEventNotFound = errors.New("event not found")
+
 // Event 是所有系統事件的基礎介面
 type Event interface {
 	ID() string        // 唯一的事件 ID
 	Topic() string     // 事件主題
 	OccurredAt() time.Time
 	Data() []byte      // 優化: 使用原始位元組，避免 Store 與 Bus 進行反射處理
+	// Version Payload 的 schema 版本，由 1 起算，舊紀錄沒有版本時視為 1
+	Version() int
 }
 
 // Marshaler 定義了物件如何序列化為位元組
@@ -37,8 +42,46 @@ type EventStore interface {
 	UpdateProgress(ctx context.Context, subscriberID string, eventID string) error
 }
 
+// EventFilter 篩選事件紀錄，時間留空代表不限制，依事件 ID 由舊到新排列
+type EventFilter struct {
+	Topic string
+	// From、To 為發生時間的範圍 [From, To)
+	From time.Time
+	To   time.Time
+	// AfterID 分頁用，只回傳 ID 大於此值的事件
+	AfterID string
+	// BeforeID 由新到舊分頁用，只回傳 ID 小於此值的事件
+	BeforeID string
+	// Newest 改為依事件 ID 由新到舊排列，供後台瀏覽最近的事件
+	Newest bool
+	Limit  int
+}
+
+// SubscriberProgress 訂閱者最後處理的事件
+type SubscriberProgress struct {
+	SubscriberID string
+	LastEventID  string
+	UpdatedAt    time.Time
+}
+
+// EventLog 查詢事件紀錄並調整訂閱者進度，供重播等維運工具使用
+type EventLog interface {
+	FindEvents(ctx context.Context, filter EventFilter) ([]Event, error)
+	CountEvents(ctx context.Context, filter EventFilter) (int64, error)
+	// GetEvent 找不到時回傳 ErrEventNotFound
+	GetEvent(ctx context.Context, id string) (Event, error)
+	// FindProgress 所有記錄過進度的訂閱者，依 ID 排列
+	FindProgress(ctx context.Context) ([]SubscriberProgress, error)
+	// SetProgress 直接覆寫訂閱者進度，eventID 為空時清除進度，下次追趕由最舊的事件開始
+	SetProgress(ctx context.Context, subscriberID string, eventID string) error
+	// UpdateProgress 與 EventStore 相同，進度只往後推進
+	UpdateProgress(ctx context.Context, subscriberID string, eventID string) error
+}
+
 // Bus 負責分發事件
 type Bus interface {
 	Publish(ctx context.Context, e Event)
 	Subscribe(topic string, s Subscriber)
+	// Close 停止接受事件，等待處理中的事件直到 ctx 結束
+	Close(ctx context.Context) error
 }
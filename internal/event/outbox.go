@@ -0,0 +1,161 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	outboxCollection = "event_outbox"
+	// 已發送的事件保留 7 天供查核
+	outboxSentTTL = 7 * 24 * 60 * 60
+)
+
+// Outbox 與業務資料在同一個交易內寫入待發送的事件，交易提交後由 OutboxRelay 分發
+type Outbox interface {
+	Add(ctx context.Context, events ...Event) error
+	// FetchPending 依發生時間取出尚未發送的事件
+	FetchPending(ctx context.Context, limit int) ([]Event, error)
+	MarkSent(ctx context.Context, ids ...string) error
+	// Remove 刪除尚未發送的事件，供不支援交易的部署在寫入失敗時收回
+	Remove(ctx context.Context, ids ...string) error
+	Backlog(ctx context.Context) (OutboxBacklog, error)
+}
+
+// OutboxBacklog 待發送事件的積壓狀況，沒有積壓時 OldestAt 為零值
+type OutboxBacklog struct {
+	OldestAt time.Time
+	Pending  int64
+}
+
+// Age 最早一筆待發送事件已等待的時間
+func (b OutboxBacklog) Age(now time.Time) time.Duration {
+	if b.OldestAt.IsZero() {
+		return 0
+	}
+	return now.Sub(b.OldestAt)
+}
+
+type mongoOutbox struct {
+	db *mongo.Database
+}
+
+// NewMongoOutbox 寫入時沿用 context 內的 session，在交易內呼叫即與業務資料一起提交
+func NewMongoOutbox(db *mongo.Database) Outbox {
+	return &mongoOutbox{db: db}
+}
+
+func (s *mongoOutbox) initIndexes(ctx context.Context) error {
+	_, err := s.db.Collection(outboxCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sent_at", Value: 1}, {Key: "occurred_at", Value: 1}}},
+		// 尚未發送的事件 sent_at 為 null，不會被 TTL 刪除
+		{
+			Keys:    bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().SetName("sent_at_ttl").SetExpireAfterSeconds(outboxSentTTL),
+		},
+	})
+	return err
+}
+
+type outboxDoc struct {
+	ID         string     `bson:"_id"`
+	Topic      string     `bson:"topic"`
+	OccurredAt time.Time  `bson:"occurred_at"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	SentAt     *time.Time `bson:"sent_at"`
+	Data       []byte     `bson:"data"`
+	Version    int        `bson:"schema_version,omitempty"`
+	// Trace 寫入時請求的 W3C trace context，分發後處理的 span 接續此 trace
+	Trace map[string]string `bson:"trace_context,omitempty"`
+}
+
+func (s *mongoOutbox) Add(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now()
+	docs := make([]any, 0, len(events))
+	for _, e := range events {
+		docs = append(docs, outboxDoc{
+			ID:         e.ID(),
+			Topic:      e.Topic(),
+			OccurredAt: e.OccurredAt(),
+			CreatedAt:  now,
+			Data:       e.Data(),
+			Version:    e.Version(),
+			Trace:      injectTrace(ctx, e),
+		})
+	}
+	_, err := s.db.Collection(outboxCollection).InsertMany(ctx, docs)
+	return err
+}
+
+func (s *mongoOutbox) FetchPending(ctx context.Context, limit int) ([]Event, error) {
+	cursor, err := s.db.Collection(outboxCollection).Find(ctx,
+		bson.M{"sent_at": nil},
+		options.Find().
+			SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []Event
+	for cursor.Next(ctx) {
+		var doc outboxDoc
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		events = append(events, &genericEvent{
+			id:         doc.ID,
+			topic:      doc.Topic,
+			occurredAt: doc.OccurredAt,
+			data:       doc.Data,
+			version:    doc.Version,
+			trace:      doc.Trace,
+		})
+	}
+	return events, cursor.Err()
+}
+
+func (s *mongoOutbox) MarkSent(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.db.Collection(outboxCollection).UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{"sent_at": time.Now()}},
+	)
+	return err
+}
+
+func (s *mongoOutbox) Remove(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.db.Collection(outboxCollection).DeleteMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "sent_at": nil},
+	)
+	return err
+}
+
+func (s *mongoOutbox) Backlog(ctx context.Context) (OutboxBacklog, error) {
+	coll := s.db.Collection(outboxCollection)
+	pending, err := coll.CountDocuments(ctx, bson.M{"sent_at": nil})
+	if err != nil || pending == 0 {
+		return OutboxBacklog{}, err
+	}
+	var oldest outboxDoc
+	err = coll.FindOne(ctx, bson.M{"sent_at": nil},
+		options.FindOne().SetSort(bson.D{{Key: "occurred_at", Value: 1}})).Decode(&oldest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return OutboxBacklog{}, err
+	}
+	return OutboxBacklog{Pending: pending, OldestAt: oldest.OccurredAt}, nil
+}
@@ -0,0 +1,414 @@
+package event
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueueFullPolicy 訂閱者佇列已滿時的處理方式
+type QueueFullPolicy int
+
+const (
+	// BlockWhenFull 發布端等待佇列有空位；經由 OutboxRelay 分發時會放慢 relay，形成背壓
+	BlockWhenFull QueueFullPolicy = iota
+	// CatchUpWhenFull 不等待，之後的事件暫不排入佇列；事件已寫入事件紀錄，
+	// 待佇列清空後改由追趕依序補處理，適合可延遲且重複處理無副作用的訂閱者
+	CatchUpWhenFull
+)
+
+const catchUpRetryInterval = 5 * time.Second
+
+// WorkerPoolConfig 每個訂閱者各自的 worker 數與佇列長度
+type WorkerPoolConfig struct {
+	Workers int
+	// QueueSize 每個 worker 的佇列長度，至少為 1
+	QueueSize int
+	OnFull    QueueFullPolicy
+	// Key 相同 key 的事件交給同一個 worker 依序處理，未設定時輪流分配
+	Key func(Event) string
+}
+
+// DefaultWorkerPool 未自訂的訂閱者最多同時處理 4 個事件
+var DefaultWorkerPool = WorkerPoolConfig{
+	Workers:   4,
+	QueueSize: 64,
+	OnFull:    BlockWhenFull,
+}
+
+func (c WorkerPoolConfig) workers() int {
+	return max(c.Workers, 1)
+}
+
+// WorkerPoolProvider 訂閱者可自訂 worker pool，未實作時使用 Bus 的預設值
+type WorkerPoolProvider interface {
+	WorkerPool() WorkerPoolConfig
+}
+
+// WithWorkerPool 為訂閱者指定 worker pool，可與 WithRetryPolicy 疊加
+func WithWorkerPool(s Subscriber, cfg WorkerPoolConfig) Subscriber {
+	return &pooledSubscriber{Subscriber: s, cfg: cfg}
+}
+
+type pooledSubscriber struct {
+	Subscriber
+	cfg WorkerPoolConfig
+}
+
+func (s *pooledSubscriber) WorkerPool() WorkerPoolConfig {
+	return s.cfg
+}
+
+func (s *pooledSubscriber) Unwrap() Subscriber {
+	return s.Subscriber
+}
+
+// KeyBy 由 Payload 取出分配 worker 的 key，例如 userID，讓同一用戶的事件依序處理
+func KeyBy[T any](fn func(T) string) func(Event) string {
+	return func(e Event) string {
+		payload, err := DecodePayload[T](e)
+		if err != nil {
+			return ""
+		}
+		return fn(payload)
+	}
+}
+
+// lookup 沿著 With* 包裝尋找訂閱者實作的設定介面
+func lookup[T any](s Subscriber) (T, bool) {
+	for s != nil {
+		if v, ok := s.(T); ok {
+			return v, true
+		}
+		u, ok := s.(interface{ Unwrap() Subscriber })
+		if !ok {
+			break
+		}
+		s = u.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+// workerPool 單一訂閱者的 worker 與佇列，每個 worker 有自己的佇列以維持同一 key 的順序
+type workerPool struct {
+	bus    *internalBus
+	sub    Subscriber
+	cfg    WorkerPoolConfig
+	queues []chan job
+	next   atomic.Uint64
+	// pending 已排入佇列但尚未處理完成的事件數
+	pending sync.WaitGroup
+	workers sync.WaitGroup
+
+	// ctx 交給訂閱者處理事件，關閉逾時時取消以中斷重試
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopping chan struct{}
+	closeMu  sync.RWMutex
+	closed   bool
+
+	mu       sync.Mutex
+	spilling bool
+
+	// inflight 依排入順序記錄事件，前面的事件都處理完才推進進度，
+	// 關閉時未處理完的事件不會被跳過，留待下次啟動追趕
+	progressMu sync.Mutex
+	inflight   []*progressEntry
+	// behindSince 追趕中尚未排入佇列的最早事件發生時間，供計算延遲
+	behindSince time.Time
+	// catchUpBacklog 最近一次追趕查到的事件中尚未處理完的數量
+	catchUpBacklog atomic.Int64
+}
+
+type job struct {
+	evt     Event
+	entry   *progressEntry
+	parent  trace.SpanContext
+	catchUp bool
+}
+
+type progressEntry struct {
+	occurredAt time.Time
+	id         string
+	done       bool
+}
+
+func newWorkerPool(b *internalBus, s Subscriber, cfg WorkerPoolConfig) *workerPool {
+	p := &workerPool{
+		bus:      b,
+		sub:      s,
+		cfg:      cfg,
+		stopping: make(chan struct{}),
+	}
+	// 重要：不使用發布端的 context，確保異步處理不會因為 Web 請求結束而被取消
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	if b.sync {
+		return p
+	}
+	p.queues = make([]chan job, cfg.workers())
+	for i := range p.queues {
+		p.queues[i] = make(chan job, max(cfg.QueueSize, 1))
+		p.workers.Add(1)
+		go p.work(p.queues[i])
+	}
+	return p
+}
+
+func (p *workerPool) work(queue chan job) {
+	defer p.workers.Done()
+	for j := range queue {
+		// 關閉逾時後不再處理剩下的事件，進度停在這裡
+		if p.ctx.Err() == nil {
+			done := p.bus.process(withParent(p.ctx, j.parent), p.sub, j.evt)
+			if done {
+				p.complete(j.entry)
+			} else if p.ctx.Err() == nil {
+				// 重試用盡且未寫入 dead letter，進度停在前一筆，改由追趕重新處理；
+				// submit 等待佇列空位時持有 mu，另開 goroutine 以免互相等待
+				go p.spill(j.evt)
+			}
+		}
+		if j.catchUp {
+			p.catchUpBacklog.Add(-1)
+		}
+		p.pending.Done()
+	}
+}
+
+// complete 標記事件處理完成，將進度推進到最後一個前面都已完成的事件
+func (p *workerPool) complete(entry *progressEntry) {
+	p.progressMu.Lock()
+	entry.done = true
+	var last string
+	for len(p.inflight) > 0 && p.inflight[0].done {
+		last = p.inflight[0].id
+		p.inflight = p.inflight[1:]
+	}
+	p.progressMu.Unlock()
+
+	if last == "" {
+		return
+	}
+	// 關閉時 ctx 已取消，進度仍需寫入
+	if err := p.bus.store.UpdateProgress(context.Background(), p.sub.ID(), last); err != nil {
+		log.Printf("EventBus: fail to update progress for %s: %v", p.sub.ID(), err)
+	}
+}
+
+func (p *workerPool) track(e Event) *progressEntry {
+	entry := &progressEntry{id: e.ID(), occurredAt: e.OccurredAt()}
+	p.progressMu.Lock()
+	p.inflight = append(p.inflight, entry)
+	p.progressMu.Unlock()
+	return entry
+}
+
+func (p *workerPool) queueFor(e Event) chan job {
+	if len(p.queues) == 1 {
+		return p.queues[0]
+	}
+	if p.cfg.Key != nil {
+		if key := p.cfg.Key(e); key != "" {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(key))
+			return p.queues[h.Sum32()%uint32(len(p.queues))]
+		}
+	}
+	return p.queues[p.next.Add(1)%uint64(len(p.queues))]
+}
+
+// start 先追趕訂閱前漏掉的歷史事件，追趕完成前不接受即時分發，以免同一事件處理兩次
+func (p *workerPool) start() {
+	if p.bus.sync {
+		unprocessed, err := p.bus.store.FindUnprocessedEvents(p.ctx, p.sub.ID(), p.sub.Topic())
+		if err != nil {
+			log.Printf("EventBus: catch-up error for %s: %v", p.sub.ID(), err)
+			return
+		}
+		for _, e := range unprocessed {
+			p.bus.handleEvent(p.ctx, p.sub, e)
+		}
+		return
+	}
+	p.mu.Lock()
+	p.spilling = true
+	p.mu.Unlock()
+	go p.drainAndCatchUp()
+}
+
+// lag 最早一筆尚未處理完的事件已等待的時間；佇列內的事件都比尚未排入的事件早，
+// 佇列清空後才以 behindSince 計算
+func (p *workerPool) lag(now time.Time) time.Duration {
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+	oldest := p.behindSince
+	for _, entry := range p.inflight {
+		if !entry.done {
+			oldest = entry.occurredAt
+			break
+		}
+	}
+	if oldest.IsZero() {
+		return 0
+	}
+	return max(now.Sub(oldest), 0)
+}
+
+func (p *workerPool) setBehind(t time.Time) {
+	p.progressMu.Lock()
+	p.behindSince = t
+	p.progressMu.Unlock()
+}
+
+// enqueue 等待佇列有空位，開始關閉後回傳 false
+func (p *workerPool) enqueue(j job) bool {
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		return false
+	}
+	p.pending.Add(1)
+	j.entry = p.track(j.evt)
+	select {
+	case p.queueFor(j.evt) <- j:
+		return true
+	case <-p.stopping:
+		p.pending.Done()
+		return false
+	}
+}
+
+// tryEnqueue 佇列已滿時不等待
+func (p *workerPool) tryEnqueue(j job) bool {
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		return true
+	}
+	q := p.queueFor(j.evt)
+	if len(q) == cap(q) {
+		return false
+	}
+	p.pending.Add(1)
+	j.entry = p.track(j.evt)
+	q <- j
+	return true
+}
+
+// submit 分發即時事件，依 OnFull 決定佇列已滿時等待或改由追趕處理
+func (p *workerPool) submit(e Event, parent trace.SpanContext) {
+	if p.bus.sync {
+		p.bus.handleEvent(withParent(p.ctx, parent), p.sub, e)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.spilling {
+		return
+	}
+	j := job{evt: e, parent: parent}
+	if p.cfg.OnFull == BlockWhenFull {
+		p.enqueue(j)
+		return
+	}
+	if !p.tryEnqueue(j) {
+		p.spilling = true
+		p.setBehind(e.OccurredAt())
+		log.Printf("EventBus: queue of %s is full, falling back to catch-up from event %s", p.sub.ID(), e.ID())
+		go p.drainAndCatchUp()
+	}
+}
+
+// spill 停止即時分發並改由追趕處理，已在追趕中時由追趕的下一輪重新查詢
+func (p *workerPool) spill(e Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.spilling {
+		return
+	}
+	p.closeMu.RLock()
+	closed := p.closed
+	p.closeMu.RUnlock()
+	if closed {
+		return
+	}
+	p.spilling = true
+	p.setBehind(e.OccurredAt())
+	log.Printf("EventBus: %s failed to handle event %s, falling back to catch-up", p.sub.ID(), e.ID())
+	go p.drainAndCatchUp()
+}
+
+// drainAndCatchUp 等佇列清空後從事件紀錄補處理略過的事件，沒有遺漏時才恢復即時分發
+func (p *workerPool) drainAndCatchUp() {
+	ctx := p.ctx
+	var lastFirst string
+	for {
+		p.pending.Wait()
+		// 佇列已清空，失敗事件之後已處理的事件進度未推進，會由這一輪追趕重新排入
+		p.resetInflight()
+		unprocessed, err := p.bus.store.FindUnprocessedEvents(ctx, p.sub.ID(), p.sub.Topic())
+		if err != nil {
+			log.Printf("EventBus: catch-up error for %s: %v", p.sub.ID(), err)
+			if !sleepCtx(ctx, catchUpRetryInterval) {
+				return
+			}
+			continue
+		}
+		if len(unprocessed) == 0 {
+			if p.resume(ctx) {
+				log.Printf("EventBus: %s caught up, accepting live events", p.sub.ID())
+				return
+			}
+			continue
+		}
+		// 進度沒有前進代表事件仍處理失敗且未寫入 dead letter，稍後再試
+		if unprocessed[0].ID() == lastFirst && !sleepCtx(ctx, catchUpRetryInterval) {
+			return
+		}
+		lastFirst = unprocessed[0].ID()
+		p.catchUpBacklog.Store(int64(len(unprocessed)))
+		p.setBehind(unprocessed[0].OccurredAt())
+		for _, e := range unprocessed {
+			if !p.enqueue(job{evt: e, parent: publisherSpan(ctx, e), catchUp: true}) {
+				return
+			}
+		}
+	}
+}
+
+func (p *workerPool) resetInflight() {
+	p.progressMu.Lock()
+	p.inflight = nil
+	p.progressMu.Unlock()
+}
+
+// resume 持有鎖再確認一次，避免查詢後才寫入的事件被略過
+func (p *workerPool) resume(ctx context.Context) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	unprocessed, err := p.bus.store.FindUnprocessedEvents(ctx, p.sub.ID(), p.sub.Topic())
+	if err != nil || len(unprocessed) > 0 {
+		return false
+	}
+	p.spilling = false
+	p.catchUpBacklog.Store(0)
+	p.setBehind(time.Time{})
+	return true
+}
+
+// stop 不再接受新事件，worker 處理完佇列內的事件後結束
+func (p *workerPool) stop() {
+	close(p.stopping)
+	p.closeMu.Lock()
+	defer p.closeMu.Unlock()
+	p.closed = true
+	for _, q := range p.queues {
+		close(q)
+	}
+}
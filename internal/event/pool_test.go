@@ -0,0 +1,290 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncStore 可並行使用的事件紀錄，進度與 Mongo 實作一樣只往後推進
+type syncStore struct {
+	mu       sync.Mutex
+	events   []Event
+	progress map[string]string
+}
+
+func newSyncStore() *syncStore {
+	return &syncStore{progress: make(map[string]string)}
+}
+
+func (m *syncStore) Save(ctx context.Context, e Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, e)
+	return nil
+}
+
+func (m *syncStore) UpdateProgress(ctx context.Context, subID, evtID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progress[subID] = max(m.progress[subID], evtID)
+	return nil
+}
+
+func (m *syncStore) FindUnprocessedEvents(ctx context.Context, subID, topic string) ([]Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []Event
+	for _, e := range m.events {
+		if e.Topic() == topic && e.ID() > m.progress[subID] {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+type userPayload struct {
+	UserID string `json:"userId"`
+	Seq    int    `json:"seq"`
+}
+
+// recordingSubscriber 記錄每個 key 的處理順序與同時處理數
+type recordingSubscriber struct {
+	delay    time.Duration
+	mu       sync.Mutex
+	seen     map[string][]int
+	inflight map[string]int
+	overlap  bool
+	handled  atomic.Int32
+}
+
+func newRecordingSubscriber(delay time.Duration) *recordingSubscriber {
+	return &recordingSubscriber{delay: delay, seen: make(map[string][]int), inflight: make(map[string]int)}
+}
+
+func (s *recordingSubscriber) ID() string    { return "recorder" }
+func (s *recordingSubscriber) Topic() string { return "topic" }
+func (s *recordingSubscriber) Handle(ctx context.Context, e Event) error {
+	p, err := decodePayload[userPayload](e.Data())
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.inflight[p.UserID]++
+	if s.inflight[p.UserID] > 1 {
+		s.overlap = true
+	}
+	s.seen[p.UserID] = append(s.seen[p.UserID], p.Seq)
+	s.mu.Unlock()
+
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	s.inflight[p.UserID]--
+	s.mu.Unlock()
+	s.handled.Add(1)
+	return nil
+}
+
+func TestWorkerPool_OrdersByKey(t *testing.T) {
+	ctx := t.Context()
+	bus := NewBus(newSyncStore()).(*internalBus)
+	sub := newRecordingSubscriber(time.Millisecond)
+	bus.Subscribe("topic", WithWorkerPool(sub, WorkerPoolConfig{
+		Workers:   4,
+		QueueSize: 8,
+		Key:       KeyBy(func(p userPayload) string { return p.UserID }),
+	}))
+
+	const perUser = 20
+	users := []string{"u1", "u2", "u3", "u4", "u5"}
+	for seq := range perUser {
+		for _, u := range users {
+			id := fmt.Sprintf("evt_%s_%03d", u, seq)
+			require.NoError(t, bus.Dispatch(ctx, NewTypedEvent(id, "topic", userPayload{UserID: u, Seq: seq})))
+		}
+	}
+
+	require.Eventually(t, func() bool { return sub.handled.Load() == int32(perUser*len(users)) },
+		5*time.Second, 5*time.Millisecond)
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	assert.False(t, sub.overlap, "same key handled concurrently")
+	for _, u := range users {
+		assert.True(t, slices.IsSorted(sub.seen[u]), "user %s out of order: %v", u, sub.seen[u])
+		assert.Len(t, sub.seen[u], perUser)
+	}
+
+	// 由事件紀錄還原的事件同樣能取出 key
+	dl := NewDeadLetter("recorder", NewTypedEvent("evt_1", "topic", userPayload{UserID: "u9"}), 1, nil)
+	assert.Equal(t, "u9", KeyBy(func(p userPayload) string { return p.UserID })(dl.Event()))
+}
+
+type gatedSubscriber struct {
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	seen    []string
+}
+
+func (s *gatedSubscriber) ID() string    { return "gated" }
+func (s *gatedSubscriber) Topic() string { return "topic" }
+func (s *gatedSubscriber) Handle(ctx context.Context, e Event) error {
+	s.once.Do(func() {
+		close(s.started)
+		<-s.gate
+	})
+	s.mu.Lock()
+	s.seen = append(s.seen, e.ID())
+	s.mu.Unlock()
+	return nil
+}
+
+func TestWorkerPool_CatchUpWhenFull(t *testing.T) {
+	ctx := t.Context()
+	bus := NewBus(newSyncStore()).(*internalBus)
+	sub := &gatedSubscriber{started: make(chan struct{}), gate: make(chan struct{})}
+	bus.Subscribe("topic", WithWorkerPool(sub, WorkerPoolConfig{Workers: 1, QueueSize: 1, OnFull: CatchUpWhenFull}))
+	pool := bus.pools["topic"][0]
+	spilling := func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		return pool.spilling
+	}
+	// 訂閱後先完成追趕才接受即時分發
+	require.Eventually(t, func() bool { return !spilling() }, time.Second, time.Millisecond)
+
+	var ids []string
+	for i := range 10 {
+		ids = append(ids, fmt.Sprintf("evt_%02d", i))
+	}
+	require.NoError(t, bus.Dispatch(ctx, NewTypedEvent(ids[0], "topic", userPayload{})))
+	<-sub.started
+	// worker 卡住時，第二筆排入佇列，第三筆起改由追趕處理，發布端不會被阻塞
+	for _, id := range ids[1:] {
+		require.NoError(t, bus.Dispatch(ctx, NewTypedEvent(id, "topic", userPayload{})))
+	}
+	assert.True(t, spilling())
+
+	close(sub.gate)
+	require.Eventually(t, func() bool { return !spilling() }, 5*time.Second, time.Millisecond)
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	assert.Equal(t, ids, sub.seen)
+}
+
+// failOnceSubscriber 指定的事件第一次處理失敗
+type failOnceSubscriber struct {
+	failID string
+	mu     sync.Mutex
+	seen   []string
+	failed bool
+}
+
+func (s *failOnceSubscriber) ID() string    { return "fail_once" }
+func (s *failOnceSubscriber) Topic() string { return "topic" }
+func (s *failOnceSubscriber) Handle(ctx context.Context, e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.ID() == s.failID && !s.failed {
+		s.failed = true
+		return errors.New("downstream unavailable")
+	}
+	s.seen = append(s.seen, e.ID())
+	return nil
+}
+
+func TestWorkerPool_CatchesUpFailedEventWithoutDeadLetterStore(t *testing.T) {
+	ctx := t.Context()
+	store := newSyncStore()
+	bus := NewBus(store, WithDefaultRetryPolicy(RetryPolicy{MaxAttempts: 1})).(*internalBus)
+	sub := &failOnceSubscriber{failID: "evt_01"}
+	bus.Subscribe("topic", WithWorkerPool(sub, WorkerPoolConfig{Workers: 1, QueueSize: 8}))
+	pool := bus.pools["topic"][0]
+	spilling := func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		return pool.spilling
+	}
+	require.Eventually(t, func() bool { return !spilling() }, time.Second, time.Millisecond)
+
+	for i := range 4 {
+		require.NoError(t, bus.Dispatch(ctx, NewTypedEvent(fmt.Sprintf("evt_%02d", i), "topic", userPayload{})))
+	}
+
+	// 失敗的事件不推進進度，由追趕重新處理，之後的事件也一併重新處理
+	require.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return store.progress["fail_once"] == "evt_03"
+	}, 5*time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return !spilling() }, 5*time.Second, time.Millisecond)
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	assert.True(t, sub.failed)
+	assert.Equal(t, "evt_00", sub.seen[0])
+	assert.Equal(t, []string{"evt_01", "evt_02", "evt_03"}, sub.seen[len(sub.seen)-3:])
+}
+
+func TestBus_Close(t *testing.T) {
+	ctx := t.Context()
+	live := func(bus *internalBus) {
+		pool := bus.pools["topic"][0]
+		require.Eventually(t, func() bool {
+			pool.mu.Lock()
+			defer pool.mu.Unlock()
+			return !pool.spilling
+		}, time.Second, time.Millisecond)
+	}
+
+	t.Run("DrainsQueuedEvents", func(t *testing.T) {
+		store := newSyncStore()
+		bus := NewBus(store).(*internalBus)
+		sub := newRecordingSubscriber(5 * time.Millisecond)
+		bus.Subscribe("topic", WithWorkerPool(sub, WorkerPoolConfig{Workers: 2, QueueSize: 10}))
+		live(bus)
+		for i := range 10 {
+			require.NoError(t, bus.Dispatch(ctx, NewTypedEvent(fmt.Sprintf("evt_%02d", i), "topic", userPayload{UserID: "u1"})))
+		}
+
+		require.NoError(t, bus.Close(ctx))
+		assert.EqualValues(t, 10, sub.handled.Load())
+		assert.Equal(t, "evt_09", store.progress["recorder"])
+		assert.ErrorIs(t, bus.Dispatch(ctx, NewTypedEvent("evt_10", "topic", userPayload{})), ErrBusClosed)
+		assert.NoError(t, bus.Close(ctx))
+	})
+
+	t.Run("StopsProgressAtUnfinishedEvent", func(t *testing.T) {
+		store := newSyncStore()
+		bus := NewBus(store).(*internalBus)
+		sub := &gatedSubscriber{started: make(chan struct{}), gate: make(chan struct{})}
+		bus.Subscribe("topic", WithWorkerPool(sub, WorkerPoolConfig{Workers: 1, QueueSize: 4}))
+		live(bus)
+		for i := range 4 {
+			require.NoError(t, bus.Dispatch(ctx, NewTypedEvent(fmt.Sprintf("evt_%02d", i), "topic", userPayload{})))
+		}
+		<-sub.started
+
+		closeCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, bus.Close(closeCtx), context.DeadlineExceeded)
+
+		// 處理中的事件完成後，佇列內其餘事件不再處理，進度停在已完成的事件
+		close(sub.gate)
+		bus.pools["topic"][0].workers.Wait()
+		assert.Equal(t, []string{"evt_00"}, sub.seen)
+		assert.Equal(t, "evt_00", store.progress["gated"])
+		unprocessed, err := store.FindUnprocessedEvents(ctx, "gated", "topic")
+		require.NoError(t, err)
+		assert.Len(t, unprocessed, 3)
+	})
+}
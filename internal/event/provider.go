@@ -1,6 +1,7 @@
 package event
 
 import (
+	"context"
 	"sync"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -11,18 +12,43 @@ var (
 	bus     Bus
 )
 
-func ProvideEventBus(store EventStore) Bus {
+func ProvideEventBus(store EventStore, deadLetters DeadLetterStore) Bus {
 	busOnce.Do(func() {
-		bus = NewBus(store)
+		bus = NewBus(store, WithDeadLetterStore(deadLetters))
 	})
 	return bus
 }
 
+func ProvideDeadLetterStore(db *mongo.Database) (DeadLetterStore, error) {
+	return NewMongoDeadLetterStore(db)
+}
+
 func ProvideEventStore(db *mongo.Database) (EventStore, error) {
 	return NewMongoEventStore(db)
 }
 
+// ProvideEventLog 事件紀錄的查詢與進度調整，與 EventStore 為同一個實作
+func ProvideEventLog(store EventStore) EventLog {
+	return store.(EventLog)
+}
+
+func ProvideOutbox(db *mongo.Database) (Outbox, error) {
+	outbox := &mongoOutbox{db: db}
+	if err := outbox.initIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return outbox, nil
+}
+
+func ProvideOutboxRelay(outbox Outbox, bus Bus) *OutboxRelay {
+	return NewOutboxRelay(outbox, bus.(Dispatcher))
+}
+
 var EventSet = wire.NewSet(
 	ProvideEventBus,
 	ProvideEventStore,
+	ProvideEventLog,
+	ProvideDeadLetterStore,
+	ProvideOutbox,
+	ProvideOutboxRelay,
 )
@@ -0,0 +1,162 @@
+package event
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	defaultRelayInterval    = time.Second
+	defaultRelayBatchSize   = 100
+	defaultBacklogInterval  = 30 * time.Second
+	defaultBacklogWarnAfter = 5 * time.Minute
+)
+
+// Dispatcher 將事件寫入事件紀錄並分發給訂閱者，寫入失敗時回傳錯誤且不分發
+type Dispatcher interface {
+	Dispatch(ctx context.Context, e Event) error
+}
+
+type RelayOption func(*OutboxRelay)
+
+// WithRelayInterval 輪詢 outbox 的間隔
+func WithRelayInterval(d time.Duration) RelayOption {
+	return func(r *OutboxRelay) {
+		if d > 0 {
+			r.interval = d
+		}
+	}
+}
+
+// WithRelayBatchSize 每次輪詢最多分發的事件數
+func WithRelayBatchSize(n int) RelayOption {
+	return func(r *OutboxRelay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithBacklogWarnAfter 最早一筆待發送事件等待超過此時間時記錄警告
+func WithBacklogWarnAfter(d time.Duration) RelayOption {
+	return func(r *OutboxRelay) {
+		if d > 0 {
+			r.warnAfter = d
+		}
+	}
+}
+
+// OutboxRelay 依序將 outbox 內的事件交給 Bus 分發，成功後標記已發送；
+// 同一個 outbox 只應由一個 relay 處理，以維持事件順序
+type OutboxRelay struct {
+	outbox     Outbox
+	dispatcher Dispatcher
+	backlog    atomic.Pointer[OutboxBacklog]
+	interval   time.Duration
+	batchSize  int
+	warnAfter  time.Duration
+}
+
+func NewOutboxRelay(outbox Outbox, dispatcher Dispatcher, opts ...RelayOption) *OutboxRelay {
+	r := &OutboxRelay{
+		outbox:     outbox,
+		dispatcher: dispatcher,
+		interval:   defaultRelayInterval,
+		batchSize:  defaultRelayBatchSize,
+		warnAfter:  defaultBacklogWarnAfter,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.backlog.Store(&OutboxBacklog{})
+	return r
+}
+
+// Run 持續分發直到 ctx 結束
+func (r *OutboxRelay) Run(ctx context.Context) {
+	if err := r.registerMetrics(); err != nil {
+		log.Printf("OutboxRelay: register metrics fail: %v", err)
+	}
+	r.refreshBacklog(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	backlogTicker := time.NewTicker(defaultBacklogInterval)
+	defer backlogTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RelayOnce(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("OutboxRelay: relay fail: %v", err)
+			}
+		case <-backlogTicker.C:
+			r.refreshBacklog(ctx)
+		}
+	}
+}
+
+// RelayOnce 分發一批待發送事件，遇到失敗即停止以保留順序，回傳已發送的數量
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.outbox.FetchPending(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, e := range events {
+		if err := r.dispatcher.Dispatch(ctx, e); err != nil {
+			return sent, err
+		}
+		if err := r.outbox.MarkSent(ctx, e.ID()); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Backlog 最近一次統計的積壓狀況
+func (r *OutboxRelay) Backlog() OutboxBacklog {
+	return *r.backlog.Load()
+}
+
+func (r *OutboxRelay) refreshBacklog(ctx context.Context) {
+	backlog, err := r.outbox.Backlog(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("OutboxRelay: count backlog fail: %v", err)
+		}
+		return
+	}
+	r.backlog.Store(&backlog)
+	if age := backlog.Age(time.Now()); age > r.warnAfter {
+		log.Printf("OutboxRelay: %d events pending, oldest waiting %s", backlog.Pending, age.Truncate(time.Second))
+	}
+}
+
+func (r *OutboxRelay) registerMetrics() error {
+	meter := otel.Meter("seanAIgent/event")
+	pending, err := meter.Int64ObservableGauge("event.outbox.pending",
+		metric.WithDescription("outbox 內尚未發送的事件數"))
+	if err != nil {
+		return err
+	}
+	oldestAge, err := meter.Float64ObservableGauge("event.outbox.oldest_age",
+		metric.WithDescription("最早一筆待發送事件已等待的時間"), metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		backlog := r.Backlog()
+		o.ObserveInt64(pending, backlog.Pending)
+		o.ObserveFloat64(oldestAge, backlog.Age(time.Now()).Seconds())
+		return nil
+	}, pending, oldestAge)
+	return err
+}
@@ -0,0 +1,124 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memOutbox struct {
+	events []Event
+	sent   map[string]bool
+}
+
+func (m *memOutbox) Add(ctx context.Context, events ...Event) error {
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *memOutbox) FetchPending(ctx context.Context, limit int) ([]Event, error) {
+	var pending []Event
+	for _, e := range m.events {
+		if !m.sent[e.ID()] && len(pending) < limit {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memOutbox) MarkSent(ctx context.Context, ids ...string) error {
+	for _, id := range ids {
+		m.sent[id] = true
+	}
+	return nil
+}
+
+func (m *memOutbox) Remove(ctx context.Context, ids ...string) error {
+	removed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = !m.sent[id]
+	}
+	kept := m.events[:0]
+	for _, e := range m.events {
+		if !removed[e.ID()] {
+			kept = append(kept, e)
+		}
+	}
+	m.events = kept
+	return nil
+}
+
+func (m *memOutbox) Backlog(ctx context.Context) (OutboxBacklog, error) {
+	var b OutboxBacklog
+	for _, e := range m.events {
+		if m.sent[e.ID()] {
+			continue
+		}
+		if b.Pending == 0 {
+			b.OldestAt = e.OccurredAt()
+		}
+		b.Pending++
+	}
+	return b, nil
+}
+
+type failingDispatcher struct {
+	failOn     string
+	dispatched []string
+}
+
+func (d *failingDispatcher) Dispatch(ctx context.Context, e Event) error {
+	if e.ID() == d.failOn {
+		return errors.New("store unavailable")
+	}
+	d.dispatched = append(d.dispatched, e.ID())
+	return nil
+}
+
+func TestOutboxRelay_RelayOnce(t *testing.T) {
+	ctx := t.Context()
+	outbox := &memOutbox{sent: make(map[string]bool)}
+	require.NoError(t, outbox.Add(ctx,
+		NewTypedEvent("evt_1", "topic", AppointmentPayload{BookingID: "B001"}),
+		NewTypedEvent("evt_2", "topic", AppointmentPayload{BookingID: "B002"}),
+		NewTypedEvent("evt_3", "topic", AppointmentPayload{BookingID: "B003"}),
+	))
+
+	t.Run("StopsAtFirstFailureToKeepOrder", func(t *testing.T) {
+		dispatcher := &failingDispatcher{failOn: "evt_2"}
+		relay := NewOutboxRelay(outbox, dispatcher)
+
+		sent, err := relay.RelayOnce(ctx)
+		require.Error(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, []string{"evt_1"}, dispatcher.dispatched)
+
+		backlog, err := outbox.Backlog(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), backlog.Pending)
+	})
+
+	t.Run("RetriesPendingEvents", func(t *testing.T) {
+		dispatcher := &failingDispatcher{}
+		relay := NewOutboxRelay(outbox, dispatcher, WithRelayBatchSize(10))
+
+		sent, err := relay.RelayOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		assert.Equal(t, []string{"evt_2", "evt_3"}, dispatcher.dispatched)
+
+		sent, err = relay.RelayOnce(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+}
+
+func TestOutboxBacklog_Age(t *testing.T) {
+	now := time.Now()
+	assert.Zero(t, OutboxBacklog{}.Age(now))
+	assert.Equal(t, time.Minute, OutboxBacklog{Pending: 1, OldestAt: now.Add(-time.Minute)}.Age(now))
+}
@@ -0,0 +1,115 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy 訂閱者處理失敗時的重試策略，用盡次數後寫入 dead letter
+type RetryPolicy struct {
+	// MaxAttempts 包含第一次處理的總次數，小於 1 視為 1
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter 0~1，每次等待時間隨機縮短的比例，避免多個訂閱者同時重試
+	Jitter float64
+}
+
+// DefaultRetryPolicy 共處理 5 次，約 15 秒後轉入 dead letter
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Backoff 第 attempt 次失敗後到下一次處理前的等待時間
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= max(p.Multiplier, 1)
+		if p.MaxBackoff > 0 && d >= float64(p.MaxBackoff) {
+			d = float64(p.MaxBackoff)
+			break
+		}
+	}
+	if p.Jitter > 0 {
+		d -= d * min(p.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// RetryPolicyProvider 訂閱者可自訂重試策略，未實作時使用 Bus 的預設值
+type RetryPolicyProvider interface {
+	RetryPolicy() RetryPolicy
+}
+
+// Permanent 標記重試也不會成功的錯誤，Bus 不再重試，直接寫入 dead letter
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 錯誤鏈中是否有 Permanent 標記的錯誤
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// WithRetryPolicy 為訂閱者指定重試策略，可與 WithWorkerPool 疊加
+func WithRetryPolicy(s Subscriber, p RetryPolicy) Subscriber {
+	return &retryableSubscriber{Subscriber: s, policy: p}
+}
+
+type retryableSubscriber struct {
+	Subscriber
+	policy RetryPolicy
+}
+
+func (s *retryableSubscriber) RetryPolicy() RetryPolicy {
+	return s.policy
+}
+
+func (s *retryableSubscriber) Unwrap() Subscriber {
+	return s.Subscriber
+}
+
+// sleepCtx 等待 d，ctx 結束時提早返回 false
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
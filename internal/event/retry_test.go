@@ -0,0 +1,140 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memDeadLetterStore struct {
+	letters map[string]DeadLetter
+}
+
+func (m *memDeadLetterStore) Add(ctx context.Context, dl DeadLetter) error {
+	if old, ok := m.letters[dl.ID]; ok {
+		dl.Attempts += old.Attempts
+		dl.FirstFailedAt = old.FirstFailedAt
+	}
+	m.letters[dl.ID] = dl
+	return nil
+}
+
+func (m *memDeadLetterStore) List(ctx context.Context, filter DeadLetterFilter) ([]DeadLetter, error) {
+	var result []DeadLetter
+	for _, dl := range m.letters {
+		result = append(result, dl)
+	}
+	return result, nil
+}
+
+func (m *memDeadLetterStore) Get(ctx context.Context, id string) (DeadLetter, error) {
+	dl, ok := m.letters[id]
+	if !ok {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+	return dl, nil
+}
+
+func (m *memDeadLetterStore) Delete(ctx context.Context, id string) error {
+	delete(m.letters, id)
+	return nil
+}
+
+type flakySubscriber struct {
+	failures  int
+	calls     int
+	permanent bool
+}
+
+func (s *flakySubscriber) ID() string    { return "flaky" }
+func (s *flakySubscriber) Topic() string { return "topic" }
+func (s *flakySubscriber) Handle(ctx context.Context, e Event) error {
+	s.calls++
+	if s.calls <= s.failures {
+		if s.permanent {
+			return Permanent(errors.New("downstream unavailable"))
+		}
+		return errors.New("downstream unavailable")
+	}
+	return nil
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	assert.Equal(t, time.Second, p.Backoff(1))
+	assert.Equal(t, 2*time.Second, p.Backoff(2))
+	assert.Equal(t, 4*time.Second, p.Backoff(3))
+	assert.Equal(t, 5*time.Second, p.Backoff(4))
+
+	p.Jitter = 0.5
+	for range 20 {
+		d := p.Backoff(2)
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.LessOrEqual(t, d, 2*time.Second)
+	}
+	assert.Equal(t, 1, RetryPolicy{}.attempts())
+}
+
+func TestBus_HandleEventRetry(t *testing.T) {
+	ctx := t.Context()
+	fast := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Multiplier: 2}
+	evt := NewTypedEvent("evt_1", "topic", AppointmentPayload{BookingID: "B001"})
+
+	newBus := func() (*internalBus, *mockStore, *memDeadLetterStore) {
+		store := &mockStore{events: make(map[string][]Event), progress: make(map[string]string)}
+		dls := &memDeadLetterStore{letters: make(map[string]DeadLetter)}
+		return NewBus(store, WithDeadLetterStore(dls)).(*internalBus), store, dls
+	}
+
+	t.Run("SucceedsWithinAttempts", func(t *testing.T) {
+		bus, store, dls := newBus()
+		sub := &flakySubscriber{failures: 2}
+		bus.handleEvent(ctx, WithRetryPolicy(sub, fast), evt)
+
+		assert.Equal(t, 3, sub.calls)
+		assert.Empty(t, dls.letters)
+		assert.Equal(t, "evt_1", store.progress["flaky"])
+	})
+
+	t.Run("MovesToDeadLetterWhenExhausted", func(t *testing.T) {
+		bus, store, dls := newBus()
+		sub := &flakySubscriber{failures: 10}
+		bus.handleEvent(ctx, WithRetryPolicy(sub, fast), evt)
+
+		assert.Equal(t, 3, sub.calls)
+		dl, err := dls.Get(ctx, DeadLetterID("flaky", "evt_1"))
+		require.NoError(t, err)
+		assert.Equal(t, 3, dl.Attempts)
+		assert.Equal(t, "downstream unavailable", dl.Error)
+		assert.Equal(t, evt.Data(), dl.Event().Data())
+		// 已寫入 dead letter，追趕時不再重複處理
+		assert.Equal(t, "evt_1", store.progress["flaky"])
+	})
+
+	t.Run("MovesToDeadLetterWithoutRetryWhenPermanent", func(t *testing.T) {
+		bus, store, dls := newBus()
+		sub := &flakySubscriber{failures: 10, permanent: true}
+		bus.handleEvent(ctx, WithRetryPolicy(sub, fast), evt)
+
+		assert.Equal(t, 1, sub.calls)
+		dl, err := dls.Get(ctx, DeadLetterID("flaky", "evt_1"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, dl.Attempts)
+		assert.Equal(t, "downstream unavailable", dl.Error)
+		assert.Equal(t, "evt_1", store.progress["flaky"])
+	})
+
+	t.Run("KeepsProgressWithoutDeadLetterStore", func(t *testing.T) {
+		store := &mockStore{events: make(map[string][]Event), progress: make(map[string]string)}
+		bus := NewBus(store, WithDefaultRetryPolicy(fast)).(*internalBus)
+		sub := &flakySubscriber{failures: 10}
+		bus.handleEvent(ctx, sub, evt)
+
+		assert.Equal(t, 3, sub.calls)
+		assert.Empty(t, store.progress)
+	})
+}
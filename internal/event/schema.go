@@ -0,0 +1,80 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrUnknownSchemaVersion = errors.New("unknown schema version")
+
+// Upcaster 將某一版的 Payload 轉換為下一版
+type Upcaster func(data []byte) ([]byte, error)
+
+// UpcastJSON 以舊版與新版的結構描述轉換，適用於使用 JSON 序列化的 Payload
+func UpcastJSON[From, To any](fn func(From) To) Upcaster {
+	return func(data []byte) ([]byte, error) {
+		var old From
+		if err := json.Unmarshal(data, &old); err != nil {
+			return nil, err
+		}
+		return json.Marshal(fn(old))
+	}
+}
+
+// SchemaRegistry 記錄各主題的 Payload 版本，目前版本為已登記的 upcaster 數加 1
+type SchemaRegistry struct {
+	mu        sync.RWMutex
+	upcasters map[string][]Upcaster
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{upcasters: make(map[string][]Upcaster)}
+}
+
+// Schemas TypedEvent 與 TypedSubscriber 使用的版本登記
+var Schemas = NewSchemaRegistry()
+
+// RegisterUpcaster 登記到預設的 Schemas，通常在定義 Payload 的套件 init 中呼叫
+func RegisterUpcaster(topic string, fromVersion int, up Upcaster) {
+	Schemas.Register(topic, fromVersion, up)
+}
+
+// Register 登記 fromVersion 升到 fromVersion+1 的轉換，需由第 1 版起依序登記，否則 panic
+func (r *SchemaRegistry) Register(topic string, fromVersion int, up Upcaster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if current := len(r.upcasters[topic]) + 1; fromVersion != current {
+		panic(fmt.Sprintf("event: upcaster for %s must start from version %d, got %d", topic, current, fromVersion))
+	}
+	r.upcasters[topic] = append(r.upcasters[topic], up)
+}
+
+// CurrentVersion 新發布的事件使用的版本，未登記 upcaster 的主題為 1
+func (r *SchemaRegistry) CurrentVersion(topic string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.upcasters[topic]) + 1
+}
+
+// Upcast 依序套用 upcaster，將 version 版的 Payload 轉為目前版本；
+// 版本比目前新時（例如部署期間由新版程式寫入）回傳 ErrUnknownSchemaVersion
+func (r *SchemaRegistry) Upcast(topic string, version int, data []byte) ([]byte, error) {
+	r.mu.RLock()
+	upcasters := r.upcasters[topic]
+	r.mu.RUnlock()
+
+	version = max(version, 1)
+	if version > len(upcasters)+1 {
+		return nil, fmt.Errorf("%w: %s v%d, current v%d", ErrUnknownSchemaVersion, topic, version, len(upcasters)+1)
+	}
+	for _, up := range upcasters[version-1:] {
+		var err error
+		if data, err = up(data); err != nil {
+			return nil, fmt.Errorf("upcast %s from v%d fail: %w", topic, version, err)
+		}
+		version++
+	}
+	return data, nil
+}
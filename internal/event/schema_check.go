@@ -0,0 +1,78 @@
+package event
+
+import (
+	"context"
+	"fmt"
+)
+
+const verifyBatchSize = 500
+
+// SchemaCheck 主題目前的 Payload 型別，用來檢查事件紀錄是否仍能解碼
+type SchemaCheck struct {
+	Topic  string
+	decode func(Event) error
+}
+
+func CheckSchema[T any](topic string) SchemaCheck {
+	return SchemaCheck{Topic: topic, decode: func(e Event) error {
+		_, err := decodeEvent[T](e)
+		return err
+	}}
+}
+
+// SchemaReport Versions 為各主題、各版本的事件數
+type SchemaReport struct {
+	Versions map[string]map[int]int
+	Failures []SchemaFailure
+	// Unchecked 沒有對應 SchemaCheck 的主題與事件數
+	Unchecked map[string]int
+}
+
+type SchemaFailure struct {
+	EventID string
+	Topic   string
+	Version int
+	Err     error
+}
+
+func (f SchemaFailure) String() string {
+	return fmt.Sprintf("%s %s v%d: %v", f.EventID, f.Topic, f.Version, f.Err)
+}
+
+// VerifyStoredEvents 以目前的 Payload 型別逐筆解碼事件紀錄，
+// 供測試確認調整 Payload 或 upcaster 後，保留期間內各版本的事件都還能處理
+func VerifyStoredEvents(ctx context.Context, log EventLog, checks ...SchemaCheck) (*SchemaReport, error) {
+	decoders := make(map[string]func(Event) error, len(checks))
+	for _, c := range checks {
+		decoders[c.Topic] = c.decode
+	}
+	report := &SchemaReport{Versions: make(map[string]map[int]int), Unchecked: make(map[string]int)}
+
+	filter := EventFilter{Limit: verifyBatchSize}
+	for {
+		events, err := log.FindEvents(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			decode, ok := decoders[e.Topic()]
+			if !ok {
+				report.Unchecked[e.Topic()]++
+				continue
+			}
+			if report.Versions[e.Topic()] == nil {
+				report.Versions[e.Topic()] = make(map[int]int)
+			}
+			report.Versions[e.Topic()][e.Version()]++
+			if err := decode(e); err != nil {
+				report.Failures = append(report.Failures, SchemaFailure{
+					EventID: e.ID(), Topic: e.Topic(), Version: e.Version(), Err: err,
+				})
+			}
+		}
+		if len(events) < verifyBatchSize {
+			return report, nil
+		}
+		filter.AfterID = events[len(events)-1].ID()
+	}
+}
@@ -0,0 +1,128 @@
+package event
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 測試用的 Payload 演進：v1 只有全名，v2 拆成姓名，v3 的 status 改為大寫
+type memberV1 struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type memberV2 struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Status    string `json:"status"`
+}
+
+type memberV3 = memberV2
+
+const memberTopic = "member.updated"
+
+// useSchemas 以獨立的登記取代預設的 Schemas，測試結束後還原
+func useSchemas(t *testing.T) *SchemaRegistry {
+	prev := Schemas
+	Schemas = NewSchemaRegistry()
+	t.Cleanup(func() { Schemas = prev })
+	Schemas.Register(memberTopic, 1, UpcastJSON(func(v1 memberV1) memberV2 {
+		first, last, _ := strings.Cut(v1.Name, " ")
+		return memberV2{FirstName: first, LastName: last, Status: v1.Status}
+	}))
+	Schemas.Register(memberTopic, 2, UpcastJSON(func(v2 memberV2) memberV3 {
+		if v2.Status == "active" {
+			v2.Status = "ACTIVE"
+		}
+		return v2
+	}))
+	return Schemas
+}
+
+func TestSchemaRegistry(t *testing.T) {
+	r := useSchemas(t)
+	assert.Equal(t, 3, r.CurrentVersion(memberTopic))
+	assert.Equal(t, 1, r.CurrentVersion("other"))
+
+	data, err := r.Upcast(memberTopic, 1, []byte(`{"name":"Ada Lovelace","status":"active"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"first_name":"Ada","last_name":"Lovelace","status":"ACTIVE"}`, string(data))
+
+	// 沒有版本的舊紀錄視為第 1 版，目前版本不做轉換
+	data, err = r.Upcast(memberTopic, 0, []byte(`{"name":"Ada","status":"left"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"first_name":"Ada","last_name":"","status":"left"}`, string(data))
+	current := []byte(`{"first_name":"Ada"}`)
+	data, err = r.Upcast(memberTopic, 3, current)
+	require.NoError(t, err)
+	assert.Equal(t, current, data)
+
+	_, err = r.Upcast(memberTopic, 4, current)
+	assert.ErrorIs(t, err, ErrUnknownSchemaVersion)
+	_, err = r.Upcast(memberTopic, 1, []byte(`not json`))
+	assert.ErrorContains(t, err, "upcast member.updated from v1 fail")
+
+	assert.Panics(t, func() { r.Register(memberTopic, 2, nil) })
+	assert.Panics(t, func() { r.Register("other", 2, nil) })
+}
+
+func TestTypedSubscriber_Upcasts(t *testing.T) {
+	ctx := t.Context()
+	useSchemas(t)
+
+	live := NewTypedEvent("evt_3", memberTopic, memberV3{FirstName: "Grace", Status: "ACTIVE"})
+	assert.Equal(t, 3, live.Version())
+	assert.Equal(t, 1, NewTypedEvent("evt_x", "other", memberV1{}).Version())
+
+	var got []memberV3
+	sub := NewTypedSubscriber("member_projection", memberTopic, func(ctx context.Context, e Event, p memberV3) error {
+		got = append(got, p)
+		return nil
+	})
+	stored := NewStoredEvent("evt_1", memberTopic, 0, time.Now(), []byte(`{"name":"Ada Lovelace","status":"active"}`))
+	require.NoError(t, sub.Handle(ctx, stored))
+	require.NoError(t, sub.Handle(ctx, live))
+	assert.Equal(t, []memberV3{
+		{FirstName: "Ada", LastName: "Lovelace", Status: "ACTIVE"},
+		{FirstName: "Grace", Status: "ACTIVE"},
+	}, got)
+
+	// 死信保留原本的版本，重送時同樣會轉換
+	dl := NewDeadLetter("member_projection", stored, 1, nil)
+	assert.Equal(t, 1, dl.Event().Version())
+	p, err := DecodePayload[memberV3](dl.Event())
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", p.FirstName)
+
+	newer := NewStoredEvent("evt_4", memberTopic, 4, time.Now(), []byte(`{}`))
+	assert.ErrorIs(t, sub.Handle(ctx, newer), ErrUnknownSchemaVersion)
+}
+
+func TestVerifyStoredEvents(t *testing.T) {
+	ctx := t.Context()
+	useSchemas(t)
+	store := NewMemoryStore(WithMemoryTTL(0))
+	now := time.Now()
+	for _, e := range []Event{
+		NewStoredEvent("evt_01", memberTopic, 1, now, []byte(`{"name":"Ada Lovelace"}`)),
+		NewStoredEvent("evt_02", memberTopic, 2, now, []byte(`{"first_name":"Ada"}`)),
+		NewStoredEvent("evt_03", memberTopic, 2, now, []byte(`{"first_name":`)),
+		NewTypedEvent("evt_04", memberTopic, memberV3{FirstName: "Grace"}),
+		NewStoredEvent("evt_05", "audit.logged", 1, now, []byte(`{}`)),
+	} {
+		require.NoError(t, store.Save(ctx, e))
+	}
+
+	report, err := VerifyStoredEvents(ctx, store, CheckSchema[memberV3](memberTopic))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[int]int{memberTopic: {1: 1, 2: 2, 3: 1}}, report.Versions)
+	assert.Equal(t, map[string]int{"audit.logged": 1}, report.Unchecked)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, "evt_03", report.Failures[0].EventID)
+	assert.Equal(t, 2, report.Failures[0].Version)
+}
@@ -0,0 +1,200 @@
+package event
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultEventTTL 與 Mongo 事件紀錄的 TTL 索引相同
+const defaultEventTTL = 30 * 24 * time.Hour
+
+type MemoryStoreOption func(*MemoryStore)
+
+// WithMemoryTTL 事件保留時間，0 代表不過期
+func WithMemoryTTL(d time.Duration) MemoryStoreOption {
+	return func(s *MemoryStore) {
+		s.ttl = d
+	}
+}
+
+// WithMemoryClock 替換判斷過期用的時間來源，供測試使用
+func WithMemoryClock(now func() time.Time) MemoryStoreOption {
+	return func(s *MemoryStore) {
+		s.now = now
+	}
+}
+
+// MemoryStore 記憶體版的事件紀錄，行為與 Mongo 實作相同，供測試與單機展示使用
+type MemoryStore struct {
+	mu       sync.RWMutex
+	events   map[string]Event
+	progress map[string]SubscriberProgress
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+var (
+	_ EventStore = (*MemoryStore)(nil)
+	_ EventLog   = (*MemoryStore)(nil)
+)
+
+func NewMemoryStore(opts ...MemoryStoreOption) *MemoryStore {
+	s := &MemoryStore{
+		events:   make(map[string]Event),
+		progress: make(map[string]SubscriberProgress),
+		ttl:      defaultEventTTL,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Save 同一個 ID 重複寫入時覆蓋
+func (s *MemoryStore) Save(ctx context.Context, e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[e.ID()] = e
+	return nil
+}
+
+// UpdateProgress 與 Mongo 實作相同，進度只往後推進
+func (s *MemoryStore) UpdateProgress(ctx context.Context, subscriberID string, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.progress[subscriberID] = SubscriberProgress{
+		SubscriberID: subscriberID,
+		LastEventID:  max(s.progress[subscriberID].LastEventID, eventID),
+		UpdatedAt:    s.now(),
+	}
+	return nil
+}
+
+func (s *MemoryStore) SetProgress(ctx context.Context, subscriberID string, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if eventID == "" {
+		delete(s.progress, subscriberID)
+		return nil
+	}
+	s.progress[subscriberID] = SubscriberProgress{SubscriberID: subscriberID, LastEventID: eventID, UpdatedAt: s.now()}
+	return nil
+}
+
+// Progress 訂閱者最後處理的事件 ID，尚未處理過時回傳空字串
+func (s *MemoryStore) Progress(subscriberID string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.progress[subscriberID].LastEventID
+}
+
+func (s *MemoryStore) FindProgress(ctx context.Context) ([]SubscriberProgress, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]SubscriberProgress, 0, len(s.progress))
+	for _, p := range s.progress {
+		result = append(result, p)
+	}
+	slices.SortFunc(result, func(a, b SubscriberProgress) int { return cmp.Compare(a.SubscriberID, b.SubscriberID) })
+	return result, nil
+}
+
+// FindUnprocessedEvents 依發生時間排列，與 Mongo 實作相同
+func (s *MemoryStore) FindUnprocessedEvents(ctx context.Context, subscriberID string, topic string) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	last := s.progress[subscriberID].LastEventID
+	var result []Event
+	for _, e := range s.events {
+		if e.Topic() == topic && e.ID() > last {
+			result = append(result, e)
+		}
+	}
+	slices.SortFunc(result, func(a, b Event) int {
+		return cmp.Or(a.OccurredAt().Compare(b.OccurredAt()), cmp.Compare(a.ID(), b.ID()))
+	})
+	return result, nil
+}
+
+func (s *MemoryStore) FindEvents(ctx context.Context, filter EventFilter) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	result := s.filter(filter)
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) GetEvent(ctx context.Context, id string) (Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	e, ok := s.events[id]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+	return e, nil
+}
+
+func (s *MemoryStore) CountEvents(ctx context.Context, filter EventFilter) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	return int64(len(s.filter(filter))), nil
+}
+
+// Events 目前保留的事件，依 ID 由舊到新排列；topic 留空代表全部
+func (s *MemoryStore) Events(topic string) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	return s.filter(EventFilter{Topic: topic})
+}
+
+// filter 呼叫端需持有寫入鎖
+func (s *MemoryStore) filter(f EventFilter) []Event {
+	var result []Event
+	for _, e := range s.events {
+		if f.Topic != "" && e.Topic() != f.Topic {
+			continue
+		}
+		if !f.From.IsZero() && e.OccurredAt().Before(f.From) {
+			continue
+		}
+		if !f.To.IsZero() && !e.OccurredAt().Before(f.To) {
+			continue
+		}
+		if f.AfterID != "" && e.ID() <= f.AfterID {
+			continue
+		}
+		if f.BeforeID != "" && e.ID() >= f.BeforeID {
+			continue
+		}
+		result = append(result, e)
+	}
+	slices.SortFunc(result, func(a, b Event) int { return cmp.Compare(a.ID(), b.ID()) })
+	if f.Newest {
+		slices.Reverse(result)
+	}
+	return result
+}
+
+// expire 移除超過保留時間的事件，呼叫端需持有寫入鎖
+func (s *MemoryStore) expire() {
+	if s.ttl <= 0 {
+		return
+	}
+	cutoff := s.now().Add(-s.ttl)
+	for id, e := range s.events {
+		if e.OccurredAt().Before(cutoff) {
+			delete(s.events, id)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventAt 建立指定發生時間的事件
+func eventAt(id, topic string, at time.Time) Event {
+	return &genericEvent{id: id, topic: topic, occurredAt: at, data: []byte(`{}`)}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := t.Context()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("UnprocessedFollowsProgress", func(t *testing.T) {
+		store := NewMemoryStore(WithMemoryTTL(0))
+		require.NoError(t, store.Save(ctx, eventAt("evt_03", "a", base.Add(2*time.Minute))))
+		require.NoError(t, store.Save(ctx, eventAt("evt_01", "a", base)))
+		require.NoError(t, store.Save(ctx, eventAt("evt_02", "b", base.Add(time.Minute))))
+		require.NoError(t, store.Save(ctx, eventAt("evt_04", "a", base.Add(3*time.Minute))))
+
+		events, err := store.FindUnprocessedEvents(ctx, "sub", "a")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_01", "evt_03", "evt_04"}, eventIDs(events))
+
+		require.NoError(t, store.UpdateProgress(ctx, "sub", "evt_03"))
+		// 並行處理時較早的事件晚完成，進度不會倒退
+		require.NoError(t, store.UpdateProgress(ctx, "sub", "evt_01"))
+		assert.Equal(t, "evt_03", store.Progress("sub"))
+		events, err = store.FindUnprocessedEvents(ctx, "sub", "a")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_04"}, eventIDs(events))
+
+		require.NoError(t, store.SetProgress(ctx, "sub", "evt_01"))
+		assert.Equal(t, "evt_01", store.Progress("sub"))
+		require.NoError(t, store.SetProgress(ctx, "sub", ""))
+		events, err = store.FindUnprocessedEvents(ctx, "sub", "a")
+		require.NoError(t, err)
+		assert.Len(t, events, 3)
+	})
+
+	t.Run("FindEventsFiltersAndPages", func(t *testing.T) {
+		store := NewMemoryStore(WithMemoryTTL(0))
+		for i, id := range []string{"evt_01", "evt_02", "evt_03", "evt_04", "evt_05"} {
+			require.NoError(t, store.Save(ctx, eventAt(id, "a", base.Add(time.Duration(i)*time.Hour))))
+		}
+		filter := EventFilter{Topic: "a", From: base.Add(time.Hour), To: base.Add(4 * time.Hour), Limit: 2}
+		count, err := store.CountEvents(ctx, filter)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+
+		page, err := store.FindEvents(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_02", "evt_03"}, eventIDs(page))
+		filter.AfterID = "evt_03"
+		page, err = store.FindEvents(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_04"}, eventIDs(page))
+	})
+
+	t.Run("NewestFirstPages", func(t *testing.T) {
+		store := NewMemoryStore(WithMemoryTTL(0))
+		for i, id := range []string{"evt_01", "evt_02", "evt_03", "evt_04"} {
+			require.NoError(t, store.Save(ctx, eventAt(id, "a", base.Add(time.Duration(i)*time.Hour))))
+		}
+		filter := EventFilter{Newest: true, Limit: 2}
+		page, err := store.FindEvents(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_04", "evt_03"}, eventIDs(page))
+		filter.BeforeID = "evt_03"
+		page, err = store.FindEvents(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_02", "evt_01"}, eventIDs(page))
+
+		e, err := store.GetEvent(ctx, "evt_02")
+		require.NoError(t, err)
+		assert.Equal(t, "evt_02", e.ID())
+		_, err = store.GetEvent(ctx, "evt_09")
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+
+	t.Run("FindProgress", func(t *testing.T) {
+		store := NewMemoryStore(WithMemoryTTL(0), WithMemoryClock(func() time.Time { return base }))
+		require.NoError(t, store.UpdateProgress(ctx, "sub_b", "evt_02"))
+		require.NoError(t, store.SetProgress(ctx, "sub_a", "evt_01"))
+		require.NoError(t, store.SetProgress(ctx, "sub_c", "evt_01"))
+		require.NoError(t, store.SetProgress(ctx, "sub_c", ""))
+
+		progress, err := store.FindProgress(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []SubscriberProgress{
+			{SubscriberID: "sub_a", LastEventID: "evt_01", UpdatedAt: base},
+			{SubscriberID: "sub_b", LastEventID: "evt_02", UpdatedAt: base},
+		}, progress)
+	})
+
+	t.Run("ExpiresAfterTTL", func(t *testing.T) {
+		now := base
+		store := NewMemoryStore(WithMemoryTTL(24*time.Hour), WithMemoryClock(func() time.Time { return now }))
+		require.NoError(t, store.Save(ctx, eventAt("evt_01", "a", base.Add(-time.Hour))))
+		require.NoError(t, store.Save(ctx, eventAt("evt_02", "a", base)))
+		assert.Len(t, store.Events("a"), 2)
+
+		now = base.Add(24 * time.Hour)
+		assert.Equal(t, []string{"evt_02"}, eventIDs(store.Events("")))
+	})
+}
+
+func eventIDs(events []Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID())
+	}
+	return ids
+}
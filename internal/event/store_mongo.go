@@ -2,11 +2,13 @@ package event
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 const (
@@ -15,11 +17,32 @@ const (
 )
 
 type mongoEventStore struct {
-	db *mongo.Database
+	db         *mongo.Database
+	collection string
+	ttl        time.Duration
 }
 
-func NewMongoEventStore(db *mongo.Database) (EventStore, error) {
-	s := &mongoEventStore{db: db}
+type MongoEventStoreOption func(*mongoEventStore)
+
+// WithEventCollection 改用其他集合保存事件，例如由封存檔還原的事件；訂閱者進度仍共用 event_subscribers
+func WithEventCollection(name string) MongoEventStoreOption {
+	return func(s *mongoEventStore) {
+		s.collection = name
+	}
+}
+
+// WithEventTTL 事件保留時間，0 代表不建立 TTL 索引
+func WithEventTTL(d time.Duration) MongoEventStoreOption {
+	return func(s *mongoEventStore) {
+		s.ttl = d
+	}
+}
+
+func NewMongoEventStore(db *mongo.Database, opts ...MongoEventStoreOption) (EventStore, error) {
+	s := &mongoEventStore{db: db, collection: eventLogCollection, ttl: defaultEventTTL}
+	for _, opt := range opts {
+		opt(s)
+	}
 	if err := s.initIndexes(context.Background()); err != nil {
 		return nil, err
 	}
@@ -27,12 +50,15 @@ func NewMongoEventStore(db *mongo.Database) (EventStore, error) {
 }
 
 func (s *mongoEventStore) initIndexes(ctx context.Context) error {
-	// 建立 TTL 索引，30 天後自動刪除
+	if s.ttl <= 0 {
+		return nil
+	}
+	// 建立 TTL 索引，預設 30 天後自動刪除，需要保留的事件由 Archiver 先行封存
 	indexModel := mongo.IndexModel{
 		Keys:    bson.D{{Key: "occurred_at", Value: 1}},
-		Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60),
+		Options: options.Index().SetExpireAfterSeconds(int32(s.ttl / time.Second)),
 	}
-	_, err := s.db.Collection(eventLogCollection).Indexes().CreateOne(ctx, indexModel)
+	_, err := s.db.Collection(s.collection).Indexes().CreateOne(ctx, indexModel)
 	return err
 }
 
@@ -41,6 +67,10 @@ type eventDoc struct {
 	Topic      string    `bson:"topic"`
 	OccurredAt time.Time `bson:"occurred_at"`
 	Data       []byte    `bson:"data"` // 優化: 直接儲存二進制數據
+	// Version 加入版本前寫入的紀錄沒有此欄位
+	Version    int       `bson:"schema_version,omitempty"`
+	// Trace 發布端的 trace context，追趕或重播時處理的 span 仍接回原本的請求
+	Trace map[string]string `bson:"trace_context,omitempty"`
 }
 
 func (s *mongoEventStore) Save(ctx context.Context, e Event) error {
@@ -49,8 +79,10 @@ func (s *mongoEventStore) Save(ctx context.Context, e Event) error {
 		Topic:      e.Topic(),
 		OccurredAt: e.OccurredAt(),
 		Data:       e.Data(),
+		Version:    e.Version(),
+		Trace:      injectTrace(ctx, e),
 	}
-	_, err := s.db.Collection(eventLogCollection).UpdateOne(
+	_, err := s.db.Collection(s.collection).UpdateOne(
 		ctx,
 		bson.M{"_id": doc.ID},
 		bson.M{"$set": doc},
@@ -63,11 +95,10 @@ func (s *mongoEventStore) UpdateProgress(ctx context.Context, subscriberID strin
 	_, err := s.db.Collection(eventProgressCollection).UpdateOne(
 		ctx,
 		bson.M{"_id": subscriberID},
+		// worker 並行處理時完成順序不一定，進度只往後推進
 		bson.M{
-			"$set": bson.M{
-				"last_processed_event_id": eventID,
-				"updated_at":              time.Now(),
-			},
+			"$max": bson.M{"last_processed_event_id": eventID},
+			"$set": bson.M{"updated_at": time.Now()},
 		},
 		options.UpdateOne().SetUpsert(true),
 	)
@@ -87,7 +118,7 @@ func (s *mongoEventStore) FindUnprocessedEvents(ctx context.Context, subscriberI
 		return nil, err
 	}
 
-	cursor, err := s.db.Collection(eventLogCollection).Find(ctx, query, options.Find().SetSort(bson.D{{Key: "occurred_at", Value: 1}}))
+	cursor, err := s.db.Collection(s.collection).Find(ctx, query, options.Find().SetSort(bson.D{{Key: "occurred_at", Value: 1}}))
 	if err != nil {
 		return nil, err
 	}
@@ -107,19 +138,158 @@ func (s *mongoEventStore) FindUnprocessedEvents(ctx context.Context, subscriberI
 			topic:      doc.Topic,
 			occurredAt: doc.OccurredAt,
 			data:       doc.Data,
+			version:    doc.Version,
+			trace:      doc.Trace,
 		})
 	}
 	return events, nil
 }
 
+func (f EventFilter) query() bson.M {
+	query := bson.M{}
+	if f.Topic != "" {
+		query["topic"] = f.Topic
+	}
+	occurred := bson.M{}
+	if !f.From.IsZero() {
+		occurred["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		occurred["$lt"] = f.To
+	}
+	if len(occurred) > 0 {
+		query["occurred_at"] = occurred
+	}
+	id := bson.M{}
+	if f.AfterID != "" {
+		id["$gt"] = f.AfterID
+	}
+	if f.BeforeID != "" {
+		id["$lt"] = f.BeforeID
+	}
+	if len(id) > 0 {
+		query["_id"] = id
+	}
+	return query
+}
+
+func (s *mongoEventStore) FindEvents(ctx context.Context, filter EventFilter) ([]Event, error) {
+	order := 1
+	if filter.Newest {
+		order = -1
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: order}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := s.db.Collection(s.collection).Find(ctx, filter.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []eventDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(docs))
+	for _, doc := range docs {
+		events = append(events, doc.event())
+	}
+	return events, nil
+}
+
+func (s *mongoEventStore) GetEvent(ctx context.Context, id string) (Event, error) {
+	var doc eventDoc
+	err := s.db.Collection(s.collection).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+	return doc.event(), nil
+}
+
+func (s *mongoEventStore) CountEvents(ctx context.Context, filter EventFilter) (int64, error) {
+	return s.db.Collection(s.collection).CountDocuments(ctx, filter.query())
+}
+
+func (s *mongoEventStore) SetProgress(ctx context.Context, subscriberID string, eventID string) error {
+	if eventID == "" {
+		_, err := s.db.Collection(eventProgressCollection).DeleteOne(ctx, bson.M{"_id": subscriberID})
+		return err
+	}
+	_, err := s.db.Collection(eventProgressCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": subscriberID},
+		bson.M{
+			"$set": bson.M{
+				"last_processed_event_id": eventID,
+				"updated_at":              time.Now(),
+			},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoEventStore) FindProgress(ctx context.Context) ([]SubscriberProgress, error) {
+	cursor, err := s.db.Collection(eventProgressCollection).Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID          string    `bson:"_id"`
+		LastEventID string    `bson:"last_processed_event_id"`
+		UpdatedAt   time.Time `bson:"updated_at"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	result := make([]SubscriberProgress, 0, len(docs))
+	for _, doc := range docs {
+		result = append(result, SubscriberProgress{
+			SubscriberID: doc.ID,
+			LastEventID:  doc.LastEventID,
+			UpdatedAt:    doc.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (doc eventDoc) event() Event {
+	return &genericEvent{
+		id:         doc.ID,
+		topic:      doc.Topic,
+		occurredAt: doc.OccurredAt,
+		data:       doc.Data,
+		version:    doc.Version,
+		trace:      doc.Trace,
+	}
+}
+
 type genericEvent struct {
 	id         string
 	topic      string
 	occurredAt time.Time
 	data       []byte
+	version    int
+	trace      propagation.MapCarrier
+}
+
+// NewStoredEvent 還原已序列化的事件，例如由事件紀錄或匯出檔載入；version 為 0 時視為 1
+func NewStoredEvent(id, topic string, version int, occurredAt time.Time, data []byte) Event {
+	return &genericEvent{id: id, topic: topic, occurredAt: occurredAt, data: data, version: version}
 }
 
 func (e *genericEvent) ID() string          { return e.id }
 func (e *genericEvent) Topic() string       { return e.topic }
 func (e *genericEvent) OccurredAt() time.Time { return e.occurredAt }
 func (e *genericEvent) Data() []byte        { return e.data }
+func (e *genericEvent) Version() int        { return max(e.version, 1) }
+
+func (e *genericEvent) traceContext() propagation.MapCarrier { return e.trace }
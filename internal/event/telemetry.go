@@ -0,0 +1,155 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "seanAIgent/event"
+
+// tracedEvent 由 outbox 或事件紀錄還原的事件，帶有發布時的 trace context
+type tracedEvent interface {
+	traceContext() propagation.MapCarrier
+}
+
+// injectTrace 事件已帶有 trace context 時沿用，否則取自 ctx 目前的 span，沒有時回傳 nil
+func injectTrace(ctx context.Context, e Event) map[string]string {
+	if t, ok := e.(tracedEvent); ok && len(t.traceContext()) > 0 {
+		return t.traceContext()
+	}
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// publisherSpan 非同步處理的父 span：優先使用事件保存的 trace context，
+// 經由 OutboxRelay 分發時 ctx 屬於 relay 而非發布端的請求
+func publisherSpan(ctx context.Context, e Event) trace.SpanContext {
+	if t, ok := e.(tracedEvent); ok && len(t.traceContext()) > 0 {
+		return trace.SpanContextFromContext(
+			otel.GetTextMapPropagator().Extract(context.Background(), t.traceContext()))
+	}
+	return trace.SpanContextFromContext(ctx)
+}
+
+// withParent 將發布端的 span 接到 worker 的 context，取消與逾時仍由 worker 控制
+func withParent(ctx context.Context, parent trace.SpanContext) context.Context {
+	if !parent.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, parent)
+}
+
+// busTelemetry Bus 的 trace 與指標，MeterProvider 未設定時為 no-op
+type busTelemetry struct {
+	tracer      trace.Tracer
+	published   metric.Int64Counter
+	duration    metric.Float64Histogram
+	errors      metric.Int64Counter
+	deadLetters metric.Int64Counter
+	backlog     metric.Int64ObservableGauge
+	lag         metric.Float64ObservableGauge
+	reg         metric.Registration
+}
+
+func newBusTelemetry(b *internalBus) *busTelemetry {
+	meter := otel.Meter(instrumentationName)
+	t := &busTelemetry{tracer: otel.Tracer(instrumentationName)}
+	var errs [6]error
+	t.published, errs[0] = meter.Int64Counter("event.published",
+		metric.WithDescription("發布的事件數"))
+	t.duration, errs[1] = meter.Float64Histogram("event.handle.duration",
+		metric.WithDescription("訂閱者每次處理事件的時間，重試時每次各記一筆"), metric.WithUnit("s"))
+	t.errors, errs[2] = meter.Int64Counter("event.handle.errors",
+		metric.WithDescription("訂閱者處理失敗的次數，包含之後重試成功的失敗"))
+	t.deadLetters, errs[3] = meter.Int64Counter("event.dead_letters",
+		metric.WithDescription("重試用盡後寫入 dead letter 的事件數"))
+	t.backlog, errs[4] = meter.Int64ObservableGauge("event.catchup.backlog",
+		metric.WithDescription("訂閱者由事件紀錄追趕中尚未處理的事件數"))
+	t.lag, errs[5] = meter.Float64ObservableGauge("event.subscriber.lag",
+		metric.WithDescription("訂閱者最早一筆尚未處理完的事件已等待的時間，沒有待處理事件時為 0"),
+		metric.WithUnit("s"))
+	if err := errors.Join(errs[:]...); err != nil {
+		log.Printf("EventBus: register metrics fail: %v", err)
+		return t
+	}
+	reg, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		now := time.Now()
+		for _, p := range b.allPools() {
+			attrs := metric.WithAttributes(attribute.String("subscriber", p.sub.ID()))
+			o.ObserveInt64(t.backlog, p.catchUpBacklog.Load(), attrs)
+			o.ObserveFloat64(t.lag, p.lag(now).Seconds(), attrs)
+		}
+		return nil
+	}, t.backlog, t.lag)
+	if err != nil {
+		log.Printf("EventBus: register metrics fail: %v", err)
+		return t
+	}
+	t.reg = reg
+	return t
+}
+
+func (t *busTelemetry) recordPublished(ctx context.Context, e Event) {
+	t.published.Add(ctx, 1, metric.WithAttributes(attribute.String("topic", e.Topic())))
+}
+
+// startHandle 一個事件一個 span，涵蓋所有重試
+func (t *busTelemetry) startHandle(ctx context.Context, s Subscriber, e Event) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "EventBus.Handle "+s.ID(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("event.id", e.ID()),
+			attribute.String("event.topic", e.Topic()),
+			attribute.String("event.subscriber", s.ID()),
+		))
+}
+
+func (t *busTelemetry) recordAttempt(ctx context.Context, s Subscriber, e Event, elapsed time.Duration, err error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("subscriber", s.ID()),
+		attribute.String("topic", e.Topic()),
+		attribute.Bool("error", err != nil),
+	}
+	t.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+	if err != nil {
+		t.errors.Add(ctx, 1, metric.WithAttributes(attrs[:2]...))
+	}
+}
+
+func (t *busTelemetry) recordDeadLetter(ctx context.Context, s Subscriber, e Event) {
+	t.deadLetters.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("subscriber", s.ID()),
+		attribute.String("topic", e.Topic()),
+	))
+}
+
+// endHandle 記錄最後的結果，attempts 為實際處理次數
+func endHandle(span trace.Span, attempts int, err error) {
+	span.SetAttributes(attribute.Int("event.attempts", attempts))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *busTelemetry) close() {
+	if t.reg != nil {
+		if err := t.reg.Unregister(); err != nil {
+			log.Printf("EventBus: unregister metrics fail: %v", err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useTelemetry 暫時替換全域的 provider，需在 NewBus 之前呼叫
+func useTelemetry(t *testing.T) (*sdkmetric.ManualReader, *tracetest.SpanRecorder) {
+	t.Helper()
+	mp, tp, prop := otel.GetMeterProvider(), otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	reader := sdkmetric.NewManualReader()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetMeterProvider(mp)
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(prop)
+	})
+	return reader, recorder
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	result := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m.Data
+		}
+	}
+	return result
+}
+
+type spanSubscriber struct {
+	spans chan trace.SpanContext
+}
+
+func (s *spanSubscriber) ID() string    { return "span" }
+func (s *spanSubscriber) Topic() string { return "topic" }
+func (s *spanSubscriber) Handle(ctx context.Context, e Event) error {
+	s.spans <- trace.SpanContextFromContext(ctx)
+	return nil
+}
+
+func TestTelemetry_TracePropagation(t *testing.T) {
+	_, recorder := useTelemetry(t)
+	bus := NewBus(newSyncStore()).(*internalBus)
+	sub := &spanSubscriber{spans: make(chan trace.SpanContext, 1)}
+	subscribeLive(bus, sub)
+
+	ctx, parent := otel.Tracer("test").Start(t.Context(), "request")
+	parent.End()
+
+	t.Run("Publish", func(t *testing.T) {
+		bus.Publish(ctx, NewTypedEvent("evt_1", "topic", userPayload{UserID: "u1"}))
+		got := <-sub.spans
+		assert.Equal(t, parent.SpanContext().TraceID(), got.TraceID())
+	})
+
+	// 經由 relay 分發時 ctx 與請求無關，改由事件保存的 trace context 接續
+	t.Run("StoredTraceContext", func(t *testing.T) {
+		carrier := injectTrace(ctx, NewTypedEvent("evt_2", "topic", 0))
+		require.NotEmpty(t, carrier)
+		e := &genericEvent{id: "evt_2", topic: "topic", occurredAt: time.Now(), trace: carrier}
+		require.NoError(t, bus.Dispatch(context.Background(), e))
+		got := <-sub.spans
+		assert.Equal(t, parent.SpanContext().TraceID(), got.TraceID())
+	})
+
+	require.NoError(t, bus.Close(t.Context()))
+	var handled int
+	for _, s := range recorder.Ended() {
+		if s.Name() == "EventBus.Handle span" {
+			handled++
+			assert.Equal(t, parent.SpanContext().SpanID(), s.Parent().SpanID())
+		}
+	}
+	assert.Equal(t, 2, handled)
+}
+
+func TestTelemetry_Metrics(t *testing.T) {
+	reader, _ := useTelemetry(t)
+	ctx := t.Context()
+	dls := &memDeadLetterStore{letters: make(map[string]DeadLetter)}
+	bus := NewBus(newSyncStore(), WithSyncDispatch(), WithDeadLetterStore(dls)).(*internalBus)
+	fast := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	bus.Subscribe("topic", WithRetryPolicy(&flakySubscriber{failures: 3}, fast))
+
+	bus.Publish(ctx, NewTypedEvent("evt_1", "topic", userPayload{}))
+	bus.Publish(ctx, NewTypedEvent("evt_2", "topic", userPayload{}))
+
+	data := collect(t, reader)
+	published := data["event.published"].(metricdata.Sum[int64])
+	require.Len(t, published.DataPoints, 1)
+	assert.Equal(t, int64(2), published.DataPoints[0].Value)
+
+	// evt_1 兩次都失敗進入 dead letter，evt_2 第一次失敗、重試成功
+	errs := data["event.handle.errors"].(metricdata.Sum[int64])
+	require.Len(t, errs.DataPoints, 1)
+	assert.Equal(t, int64(3), errs.DataPoints[0].Value)
+	deadLetters := data["event.dead_letters"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(1), deadLetters.DataPoints[0].Value)
+
+	var attempts uint64
+	for _, dp := range data["event.handle.duration"].(metricdata.Histogram[float64]).DataPoints {
+		attempts += dp.Count
+	}
+	assert.Equal(t, uint64(4), attempts)
+}
+
+func TestTelemetry_Lag(t *testing.T) {
+	reader, _ := useTelemetry(t)
+	bus := NewBus(newSyncStore()).(*internalBus)
+	sub := &gatedSubscriber{started: make(chan struct{}), gate: make(chan struct{})}
+	subscribeLive(bus, sub)
+
+	lag := func() float64 {
+		gauge := collect(t, reader)["event.subscriber.lag"].(metricdata.Gauge[float64])
+		require.Len(t, gauge.DataPoints, 1)
+		return gauge.DataPoints[0].Value
+	}
+	assert.Zero(t, lag())
+
+	occurredAt := time.Now().Add(-time.Minute)
+	require.NoError(t, bus.Dispatch(t.Context(), NewStoredEvent("evt_1", "topic", 1, occurredAt, []byte(`{}`))))
+	<-sub.started
+	assert.GreaterOrEqual(t, lag(), time.Minute.Seconds())
+
+	close(sub.gate)
+	require.NoError(t, bus.Close(t.Context()))
+	assert.Zero(t, bus.pools["topic"][0].lag(time.Now()))
+}
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+// TestBus 同步分發並記錄發布的事件，Publish 返回時訂閱者都已處理完，
+// 測試可直接斷言發布的事件與訂閱者的結果
+type TestBus struct {
+	Store *MemoryStore
+
+	bus       *internalBus
+	mu        sync.Mutex
+	published []Event
+}
+
+var _ Bus = (*TestBus)(nil)
+
+// NewTestBus 使用 MemoryStore，預設只處理一次不重試；opts 可覆寫重試策略或加上 dead letter
+func NewTestBus(opts ...BusOption) *TestBus {
+	store := NewMemoryStore()
+	opts = append([]BusOption{WithDefaultRetryPolicy(RetryPolicy{MaxAttempts: 1})}, opts...)
+	opts = append(opts, WithSyncDispatch())
+	return &TestBus{
+		Store: store,
+		bus:   NewBus(store, opts...).(*internalBus),
+	}
+}
+
+func (b *TestBus) Publish(ctx context.Context, e Event) {
+	b.record(e)
+	b.bus.Publish(ctx, e)
+}
+
+func (b *TestBus) Dispatch(ctx context.Context, e Event) error {
+	b.record(e)
+	return b.bus.Dispatch(ctx, e)
+}
+
+func (b *TestBus) Subscribe(topic string, s Subscriber) {
+	b.bus.Subscribe(topic, s)
+}
+
+func (b *TestBus) Close(ctx context.Context) error {
+	return b.bus.Close(ctx)
+}
+
+// Published 已發布的事件，依發布順序排列；topic 留空代表全部
+func (b *TestBus) Published(topic string) []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var result []Event
+	for _, e := range b.published {
+		if topic == "" || e.Topic() == topic {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Reset 清除已記錄的發布事件，不影響事件紀錄與訂閱進度
+func (b *TestBus) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = nil
+}
+
+func (b *TestBus) record(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, e)
+}
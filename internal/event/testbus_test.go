@@ -0,0 +1,49 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestBus(t *testing.T) {
+	ctx := t.Context()
+	bus := NewTestBus()
+	var received []AppointmentPayload
+	handler := func(ctx context.Context, e Event, p AppointmentPayload) error {
+		received = append(received, p)
+		return nil
+	}
+
+	// 訂閱前已發布的事件在 Subscribe 時同步追趕
+	bus.Publish(ctx, NewTypedEvent("evt_01", "topic", AppointmentPayload{BookingID: "B001"}))
+	bus.Subscribe("topic", NewTypedSubscriber("stats", "topic", handler))
+	require.Len(t, received, 1)
+
+	bus.Publish(ctx, NewTypedEvent("evt_02", "topic", AppointmentPayload{BookingID: "B002"}))
+	bus.Publish(ctx, NewTypedEvent("evt_03", "other", AppointmentPayload{BookingID: "B003"}))
+	assert.Equal(t, []string{"B001", "B002"}, []string{received[0].BookingID, received[1].BookingID})
+	assert.Equal(t, "evt_02", bus.Store.Progress("stats"))
+
+	published := bus.Published("topic")
+	require.Len(t, published, 2)
+	p, err := DecodePayload[AppointmentPayload](published[1])
+	require.NoError(t, err)
+	assert.Equal(t, "B002", p.BookingID)
+	assert.Len(t, bus.Published(""), 3)
+
+	// 作為 OutboxRelay 的 Dispatcher，RelayOnce 返回時訂閱者已處理完
+	outbox := &memOutbox{sent: make(map[string]bool)}
+	require.NoError(t, outbox.Add(ctx, NewTypedEvent("evt_04", "topic", AppointmentPayload{BookingID: "B004"})))
+	sent, err := NewOutboxRelay(outbox, bus).RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Len(t, received, 3)
+
+	bus.Reset()
+	assert.Empty(t, bus.Published(""))
+	require.NoError(t, bus.Close(ctx))
+	assert.ErrorIs(t, bus.Dispatch(ctx, NewTypedEvent("evt_05", "topic", AppointmentPayload{})), ErrBusClosed)
+}
@@ -31,6 +31,33 @@ func (s *typedSubscriber[T]) ID() string    { return s.id }
 func (s *typedSubscriber[T]) Topic() string { return s.topic }
 
 func (s *typedSubscriber[T]) Handle(ctx context.Context, e Event) error {
+	payload, err := decodeEvent[T](e)
+	if err != nil {
+		return fmt.Errorf("TypedSubscriber[%s]: %w", s.id, err)
+	}
+	return s.handler(ctx, e, payload)
+}
+
+// DecodePayload 還原事件的 Payload，例如在測試中斷言發布的內容
+func DecodePayload[T any](e Event) (T, error) {
+	if te, ok := e.(*TypedEvent[T]); ok {
+		return te.payload, nil
+	}
+	return decodeEvent[T](e)
+}
+
+// decodeEvent 舊版的 Payload 先經 Schemas 轉為目前版本再解碼
+func decodeEvent[T any](e Event) (T, error) {
+	data, err := Schemas.Upcast(e.Topic(), e.Version(), e.Data())
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decodePayload[T](data)
+}
+
+// decodePayload 優先使用 Payload 自訂的 Unmarshaler，否則回退到 JSON
+func decodePayload[T any](data []byte) (T, error) {
 	var payload T
 
 	// 處理指標型別的初始化
@@ -42,20 +69,19 @@ func (s *typedSubscriber[T]) Handle(ctx context.Context, e Event) error {
 	// 取得實際用於 Unmarshal 的對象
 	target := any(&payload)
 	if u, ok := target.(Unmarshaler); ok {
-		if err := u.Unmarshal(e.Data()); err != nil {
-			return fmt.Errorf("TypedSubscriber[%s]: custom unmarshal fail: %w", s.id, err)
+		if err := u.Unmarshal(data); err != nil {
+			return payload, fmt.Errorf("custom unmarshal fail: %w", err)
 		}
 	} else if u, ok := any(payload).(Unmarshaler); ok {
-		if err := u.Unmarshal(e.Data()); err != nil {
-			return fmt.Errorf("TypedSubscriber[%s]: custom unmarshal fail: %w", s.id, err)
+		if err := u.Unmarshal(data); err != nil {
+			return payload, fmt.Errorf("custom unmarshal fail: %w", err)
 		}
 	} else {
-		if err := json.Unmarshal(e.Data(), &payload); err != nil {
-			return fmt.Errorf("TypedSubscriber[%s]: unmarshal fail: %w", s.id, err)
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return payload, fmt.Errorf("unmarshal fail: %w", err)
 		}
 	}
-
-	return s.handler(ctx, e, payload)
+	return payload, nil
 }
 
 // TypedEvent 是泛型事件包裝器，方便 Producer 發送
@@ -65,6 +91,7 @@ type TypedEvent[T any] struct {
 	occurredAt time.Time
 	payload    T
 	data       []byte
+	version    int
 	marshalOnce sync.Once
 }
 
@@ -74,12 +101,14 @@ func NewTypedEvent[T any](id, topic string, payload T) Event {
 		topic:      topic,
 		occurredAt: time.Now(),
 		payload:    payload,
+		version:    Schemas.CurrentVersion(topic),
 	}
 }
 
 func (e *TypedEvent[T]) ID() string          { return e.id }
 func (e *TypedEvent[T]) Topic() string       { return e.topic }
 func (e *TypedEvent[T]) OccurredAt() time.Time { return e.occurredAt }
+func (e *TypedEvent[T]) Version() int          { return e.version }
 func (e *TypedEvent[T]) Data() []byte {
 	e.marshalOnce.Do(func() {
 		// 優先檢查是否實作了 Marshaler 介面 (包含檢查指標)
@@ -36,6 +36,8 @@
 | **新增按鈕** | Button | **非同步操作**。使用 HTMX 將目前輸入的時段加入下方清單。 |
 | **已新增清單** | List | 動態顯示所有已加入的時段。 |
 | **刪除按鈕** | Button | **非同步操作**。位於每筆時段旁，點擊可即時刪除該筆資料。 |
+| **停課** | Form | **非同步操作**。填寫停課原因後停課，已預約的學員改為「停課」狀態並推播通知。 |
+| **改期** | Form | **非同步操作**。修改日期、時間或地點，保留既有預約並推播通知已預約的家長。 |
 
 > **備註**:
 > * 所有欄位與按鈕在權限驗證成功前，皆為 **禁用 (disabled)** 狀態。
@@ -58,6 +60,17 @@
     *   前端透過 HTMX 將該時段 ID POST 至 `/training-date/delete`。
     *   後端處理後，前端透過 `hx-swap="outerHTML"` 將該時段的整列元素從畫面移除。
 
+4.  **停課**:
+    *   已有預約的時段無法刪除，改以「停課」處理（例如雨天）。
+    *   使用者展開「停課」並填寫原因，確認後前端透過 HTMX 將時段 ID 與原因 POST 至 `/training-date/cancel`。
+    *   後端將時段標記為已停課，所有預約改為「停課」狀態（不計入請假或缺席，課程包堂數退還），並透過 LINE 通知家長與綁定的群組。
+    *   該列重新繪製，顯示「已停課：原因」標籤。
+5.  **改期**:
+    *   使用者展開「改期」並填寫新的日期、時間與地點（地點留空代表不變），確認後 POST 至 `/training-date/reschedule`。
+    *   後端檢查教練在新時段是否已有其他課程，通過後更新時段，既有預約保留不變。
+    *   改期前預約的家長會收到 LINE 通知，預約頁面顯示「改期」標籤，無法配合者可免費取消（不列入請假）。
+    *   該列重新繪製，顯示「已改期」標籤。
+
 ## 六、介面與體驗建議 (UI/UX)
 
 *   **動態刷新**: 所有操作均不刷新整個頁面，提供流暢的單頁應用體驗。